@@ -16,6 +16,7 @@ import (
 
 	log "github.com/golang/glog"
 	"github.com/youtube/vitess/go/exit"
+	"github.com/youtube/vitess/go/trace"
 	"github.com/youtube/vitess/go/vt/logutil"
 	"github.com/youtube/vitess/go/vt/servenv"
 	"github.com/youtube/vitess/go/vt/tabletmanager/tmclient"
@@ -73,6 +74,7 @@ func main() {
 	}
 
 	servenv.FireRunHooks()
+	defer trace.Flush()
 
 	topoServer := topo.GetServer()
 	defer topo.CloseServers()
@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 
+	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/vt/audit"
 	"github.com/youtube/vitess/go/vt/servenv"
 	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/vtctld"
@@ -22,6 +24,11 @@ func main() {
 	servenv.Init()
 	defer servenv.Close()
 
+	if err := audit.Init(); err != nil {
+		log.Fatalf("Fail to initialize the audit log: %v", err)
+	}
+	servenv.OnClose(audit.Close)
+
 	ts = topo.GetServer()
 	defer topo.CloseServers()
 
@@ -0,0 +1,9 @@
+// Copyright 2015, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	_ "github.com/youtube/vitess/go/vt/mysqlctl/gcsbackupstorage"
+)
@@ -21,6 +21,7 @@ var (
 	sleepTime     = flag.Duration("sleep_time", 3*time.Minute, "how long to sleep between janitor runs")
 	keyspace      = flag.String("keyspace", "", "keyspace to manage")
 	shard         = flag.String("shard", "", "shard to manage")
+	useElection   = flag.Bool("use_election", true, "if specified, will use a topology server-based master election to ensure only one vtjanitor runs the janitors of the shard in active mode at a time")
 	dryRunModules flagutil.StringListValue
 	activeModules flagutil.StringListValue
 )
@@ -62,8 +63,27 @@ func main() {
 		exit.Return(1)
 	}
 
-	scheduler.Enable(activeModules)
-	scheduler.EnableDryRun(dryRunModules)
+	if err := scheduler.Enable(activeModules); err != nil {
+		log.Errorf("cannot enable active modules: %v", err)
+		exit.Return(1)
+	}
+	if err := scheduler.EnableDryRun(dryRunModules); err != nil {
+		log.Errorf("cannot enable dry run modules: %v", err)
+		exit.Return(1)
+	}
+
+	if *useElection {
+		// We use servenv.ListeningURL which is only populated
+		// during Run, so we have to start this with OnRun.
+		servenv.OnRun(func() {
+			if err := scheduler.RunElection(servenv.ListeningURL.Host); err != nil {
+				log.Errorf("cannot start the master election: %v", err)
+				exit.Return(1)
+			}
+		})
+		servenv.OnTermSync(scheduler.StopElection)
+	}
+
 	go scheduler.Run()
 	servenv.RunDefault()
 }
@@ -7,9 +7,11 @@ package main
 
 import (
 	"flag"
+	"time"
 
 	log "github.com/golang/glog"
 	"github.com/youtube/vitess/go/exit"
+	"github.com/youtube/vitess/go/vt/audit"
 	"github.com/youtube/vitess/go/vt/dbconfigs"
 	"github.com/youtube/vitess/go/vt/mysqlctl"
 	"github.com/youtube/vitess/go/vt/servenv"
@@ -28,6 +30,8 @@ import (
 var (
 	enforceTableACLConfig = flag.Bool("enforce-tableacl-config", false, "if this flag is true, vttablet will fail to start if a valid tableacl config does not exist")
 	tableAclConfig        = flag.String("table-acl-config", "", "path to table access checker config file")
+	tableACLFromTopo      = flag.Bool("table-acl-from-topo", false, "if this flag is true, the table ACL of the keyspace is loaded from the topology, and reloaded when it changes there (see vtctl ApplyTableACL)")
+	tableACLRetryDelay    = flag.Duration("table-acl-topo-retry-delay", 30*time.Second, "delay before watching the table ACL in the topology again, after the watch failed")
 	tabletPath            = flag.String("tablet-path", "", "tablet alias")
 
 	agent *tabletmanager.ActionAgent
@@ -54,6 +58,12 @@ func main() {
 
 	servenv.Init()
 
+	if err := audit.Init(); err != nil {
+		log.Errorf("Fail to initialize the audit log: %v", err)
+		exit.Return(1)
+	}
+	servenv.OnClose(audit.Close)
+
 	if *tabletPath == "" {
 		log.Errorf("tabletPath required")
 		exit.Return(1)
@@ -75,8 +85,11 @@ func main() {
 		log.Warning(err)
 	}
 
+	// The topology is shared by the query service and the agent.
+	ts := topo.GetServer()
+
 	// creates and registers the query service
-	qsc := tabletserver.NewServer()
+	qsc := tabletserver.NewServer(ts)
 	servenv.OnRun(func() {
 		qsc.Register()
 		addStatusParts(qsc)
@@ -87,11 +100,11 @@ func main() {
 		qsc.StopService()
 	})
 
-	if *tableAclConfig != "" {
+	if *tableAclConfig != "" || *tableACLFromTopo {
 		// To override default simpleacl, other ACL plugins must set themselves to be default ACL factory
 		tableacl.Register("simpleacl", &simpleacl.Factory{})
 	} else if *enforceTableACLConfig {
-		log.Error("table acl config has to be specified with table-acl-config flag because enforce-tableacl-config is set.")
+		log.Error("table acl config has to be specified with table-acl-config or table-acl-from-topo flag because enforce-tableacl-config is set.")
 		exit.Return(1)
 	}
 	// tabletacl.Init loads ACL from file if *tableAclConfig is not empty
@@ -120,12 +133,27 @@ func main() {
 	if servenv.GRPCPort != nil {
 		gRPCPort = int32(*servenv.GRPCPort)
 	}
-	agent, err = tabletmanager.NewActionAgent(context.Background(), mysqld, qsc, tabletAlias, dbcfgs, mycnf, int32(*servenv.Port), gRPCPort)
+	agent, err = tabletmanager.NewActionAgent(context.Background(), ts, mysqld, qsc, tabletAlias, dbcfgs, mycnf, int32(*servenv.Port), gRPCPort)
 	if err != nil {
 		log.Error(err)
 		exit.Return(1)
 	}
 
+	if *tableACLFromTopo {
+		// The table ACL of the keyspace in the topology replaces
+		// the one of the config file.
+		watcher := tabletmanager.NewTableACLWatcher(agent.TopoServer, agent.Tablet().Keyspace, *tableACLRetryDelay)
+		if err := watcher.Load(context.Background()); err != nil {
+			log.Errorf("Fail to load the Table ACL from the topology: %v", err)
+			if *enforceTableACLConfig {
+				log.Error("Need a valid initial Table ACL when enforce-tableacl-config is set, exiting.")
+				exit.Return(1)
+			}
+		}
+		watcher.Start()
+		servenv.OnClose(watcher.Stop)
+	}
+
 	servenv.OnClose(func() {
 		// We will still use the topo server during lameduck period
 		// to update our state, so closing it in OnClose()
@@ -0,0 +1,163 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlconn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/youtube/vitess/go/sqldb"
+)
+
+// AuthServer is the interface that servers must implement to validate
+// users and passwords. It uses salt the way MySQL native auth does
+// it: the password is not sent in the clear, but the salt is used to
+// hash the password both on the client and server side, and the
+// result is sent and compared.
+type AuthServer interface {
+	// Salt returns the salt to use for a connection.
+	// It should be 20 bytes of data.
+	Salt() ([]byte, error)
+
+	// ValidateHash validates the data sent by the client matches
+	// what the server computes.  It also returns the user data.
+	ValidateHash(salt []byte, user string, authResponse []byte) (string, error)
+}
+
+// NewSalt returns a 20 character salt.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, 20)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	// Salt must be a legal UTF8 string.
+	for i := 0; i < len(salt); i++ {
+		salt[i] &= 0x7f
+		if salt[i] == '\x00' || salt[i] == '$' {
+			salt[i]++
+		}
+	}
+
+	return salt, nil
+}
+
+// scramblePassword computes the hash of the password using 4.1+ method.
+func scramblePassword(salt, password []byte) []byte {
+	if len(password) == 0 {
+		return nil
+	}
+
+	// stage1Hash = SHA1(password)
+	crypt := sha1.New()
+	crypt.Write(password)
+	stage1 := crypt.Sum(nil)
+
+	// scrambleHash = SHA1(salt + SHA1(stage1Hash))
+	// inner Hash
+	crypt.Reset()
+	crypt.Write(stage1)
+	hash := crypt.Sum(nil)
+	// outer Hash
+	crypt.Reset()
+	crypt.Write(salt)
+	crypt.Write(hash)
+	scramble := crypt.Sum(nil)
+
+	// token = scrambleHash XOR stage1Hash
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
+
+// AuthServerNone takes all comers.
+// It's meant to be used for testing and prototyping.
+// With this config, you can connect to a local vtgate using
+// the following command line: 'mysql -P port -h ::'.
+type AuthServerNone struct{}
+
+// Salt is part of the AuthServer interface.
+func (a *AuthServerNone) Salt() ([]byte, error) {
+	return NewSalt()
+}
+
+// ValidateHash is part of the AuthServer interface.
+// It accepts any user and password.
+func (a *AuthServerNone) ValidateHash(salt []byte, user string, authResponse []byte) (string, error) {
+	return "", nil
+}
+
+// AuthServerStaticEntry stores the values for a given user.
+type AuthServerStaticEntry struct {
+	Password string
+	UserData string
+}
+
+// AuthServerStatic implements AuthServer using a static configuration.
+type AuthServerStatic struct {
+	// Entries contains the users, passwords and user data.
+	Entries map[string]*AuthServerStaticEntry
+}
+
+// NewAuthServerStatic returns a new empty AuthServerStatic.
+func NewAuthServerStatic() *AuthServerStatic {
+	return &AuthServerStatic{
+		Entries: make(map[string]*AuthServerStaticEntry),
+	}
+}
+
+// NewAuthServerStaticFromFile reads the JSON configuration
+// in the provided file. The format is:
+//
+//	{
+//	  "username": {
+//	    "Password": "password",
+//	    "UserData": "data"
+//	  }
+//	}
+func NewAuthServerStaticFromFile(filename string) (*AuthServerStatic, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth server config file %v: %v", filename, err)
+	}
+	return NewAuthServerStaticFromString(string(data))
+}
+
+// NewAuthServerStaticFromString parses the JSON configuration
+// in the provided string. See NewAuthServerStaticFromFile for the format.
+func NewAuthServerStaticFromString(config string) (*AuthServerStatic, error) {
+	a := NewAuthServerStatic()
+	if err := json.Unmarshal([]byte(config), &a.Entries); err != nil {
+		return nil, fmt.Errorf("failed to parse auth server config: %v", err)
+	}
+	return a, nil
+}
+
+// Salt is part of the AuthServer interface.
+func (a *AuthServerStatic) Salt() ([]byte, error) {
+	return NewSalt()
+}
+
+// ValidateHash is part of the AuthServer interface.
+func (a *AuthServerStatic) ValidateHash(salt []byte, user string, authResponse []byte) (string, error) {
+	// Find the entry.
+	entry, ok := a.Entries[user]
+	if !ok {
+		return "", sqldb.NewSQLError(ERAccessDeniedError, SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+
+	// Validate the password.
+	computedAuthResponse := scramblePassword(salt, []byte(entry.Password))
+	if !bytes.Equal(authResponse, computedAuthResponse) {
+		return "", sqldb.NewSQLError(ERAccessDeniedError, SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+
+	return entry.UserData, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mysqlconn implements the server side of the MySQL
+// client/server protocol, so a Vitess process can accept connections
+// from stock MySQL clients and drivers.
+package mysqlconn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	// connBufferSize is how much we buffer for reading and
+	// writing. It is also how much we allocate for ephemeral buffers.
+	connBufferSize = 16 * 1024
+)
+
+// Conn is a connection between a client and a server, using the MySQL
+// binary protocol. It is built on top of an existing net.Conn, that
+// has already been established.
+//
+// Use Listener to accept incoming connections and get a Conn.
+// A Conn is only used by one goroutine at a time, so it is not
+// protected by a mutex.
+type Conn struct {
+	conn net.Conn
+
+	// ConnectionID is set:
+	// - at Connect() time for clients, with the value returned by
+	// the server.
+	// - at accept time for the server.
+	ConnectionID uint32
+
+	// Capabilities is the current set of features this connection
+	// is using.  It is the features that are both supported by
+	// the client and the server, and currently in use.
+	// It is set after the initial handshake.
+	Capabilities uint32
+
+	// CharacterSet is the character set used by the other side of the
+	// connection.
+	// It is set during the initial handshake.
+	CharacterSet uint8
+
+	// User is the name used by the client to connect.
+	// It is set during the initial handshake.
+	User string
+
+	// UserData is custom data returned by the AuthServer module.
+	// It is set during the initial handshake.
+	UserData string
+
+	// SchemaName is the default database name to use. It is set
+	// during handshake, and by ComInitDB packets.
+	SchemaName string
+
+	// ServerVersion is set during Connect with the server
+	// version.  It is not changed afterwards. It is unused for
+	// server-side connections.
+	ServerVersion string
+
+	// StatusFlags are the status flags we will base our returned
+	// flags on. It is only used by the server.
+	StatusFlags uint16
+
+	// ClientData is a place where an application can store any
+	// connection-related data. Mostly used on the server side, to
+	// avoid maps indexed by ConnectionID for instance.
+	ClientData interface{}
+
+	reader   *bufio.Reader
+	writer   *bufio.Writer
+	sequence uint8
+}
+
+// newConn is an internal method to create a Conn. Used by client and server
+// side for common creation code.
+func newConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn:   conn,
+		reader: bufio.NewReaderSize(conn, connBufferSize),
+		writer: bufio.NewWriterSize(conn, connBufferSize),
+	}
+}
+
+// readOnePacket reads a single packet from the underlying connection.
+// It returns the payload, and checks the sequence number.
+func (c *Conn) readOnePacket() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return nil, err
+	}
+	sequence := header[3]
+	if sequence != c.sequence {
+		return nil, fmt.Errorf("invalid sequence, expected %v got %v", c.sequence, sequence)
+	}
+	c.sequence++
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	if length == 0 {
+		return []byte{}, nil
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return nil, fmt.Errorf("io.ReadFull(packet body of length %v) failed: %v", length, err)
+	}
+	return data, nil
+}
+
+// readPacket reads a packet from the underlying connection.
+// It re-assembles packets that span more than one message.
+func (c *Conn) readPacket() ([]byte, error) {
+	data, err := c.readOnePacket()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < maxPacketSize {
+		return data, nil
+	}
+
+	// This packet is continued in the next ones.
+	for {
+		next, err := c.readOnePacket()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, next...)
+		if len(next) < maxPacketSize {
+			return data, nil
+		}
+	}
+}
+
+// writePacket writes a packet, possibly cutting it into multiple
+// chunks. Note this is not very efficient, as the client probably
+// has to build the []byte and that makes a memory copy.
+// It does not flush the connection.
+func (c *Conn) writePacket(data []byte) error {
+	index := 0
+	for {
+		length := len(data) - index
+		if length > maxPacketSize {
+			length = maxPacketSize
+		}
+
+		var header [4]byte
+		header[0] = byte(length)
+		header[1] = byte(length >> 8)
+		header[2] = byte(length >> 16)
+		header[3] = c.sequence
+		if _, err := c.writer.Write(header[:]); err != nil {
+			return fmt.Errorf("Write(header) failed: %v", err)
+		}
+		if length > 0 {
+			if _, err := c.writer.Write(data[index : index+length]); err != nil {
+				return fmt.Errorf("Write(packet) failed: %v", err)
+			}
+		}
+		c.sequence++
+
+		// If we sent the maximum length, we need to send another
+		// packet, even if it is empty.
+		if length < maxPacketSize {
+			return nil
+		}
+		index += length
+	}
+}
+
+// flush flushes the written data to the socket.
+func (c *Conn) flush() error {
+	return c.writer.Flush()
+}
+
+// writeOKPacket writes an OK packet.
+// It does not flush the connection.
+func (c *Conn) writeOKPacket(affectedRows, lastInsertID uint64, flags uint16, warnings uint16) error {
+	length := 1 + // OKPacket
+		lenEncIntSize(affectedRows) +
+		lenEncIntSize(lastInsertID) +
+		2 + // flags
+		2 // warnings
+	data := make([]byte, length)
+	pos := writeByte(data, 0, OKPacket)
+	pos = writeLenEncInt(data, pos, affectedRows)
+	pos = writeLenEncInt(data, pos, lastInsertID)
+	pos = writeUint16(data, pos, flags)
+	writeUint16(data, pos, warnings)
+	return c.writePacket(data)
+}
+
+// writeErrorPacket writes an error packet.
+// It does not flush the connection.
+func (c *Conn) writeErrorPacket(errorCode uint16, sqlState string, format string, args ...interface{}) error {
+	errorMessage := fmt.Sprintf(format, args...)
+	length := 1 + 2 + 1 + 5 + len(errorMessage)
+	data := make([]byte, length)
+	pos := writeByte(data, 0, ErrPacket)
+	pos = writeUint16(data, pos, errorCode)
+	pos = writeByte(data, pos, '#')
+	if sqlState == "" {
+		sqlState = SSUnknownSQLState
+	}
+	if len(sqlState) != 5 {
+		panic("sqlState has to be 5 characters long")
+	}
+	pos = writeEOFString(data, pos, sqlState)
+	writeEOFString(data, pos, errorMessage)
+	return c.writePacket(data)
+}
+
+// writeEOFPacket writes an EOF packet.
+// It does not flush the connection.
+func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
+	data := make([]byte, 5)
+	pos := writeByte(data, 0, EOFPacket)
+	pos = writeUint16(data, pos, warnings)
+	writeUint16(data, pos, flags)
+	return c.writePacket(data)
+}
+
+// Close closes the connection. It can be called from a different go
+// routine to interrupt the current connection.
+func (c *Conn) Close() {
+	c.conn.Close()
+}
+
+// RemoteAddr returns the underlying socket RemoteAddr().
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
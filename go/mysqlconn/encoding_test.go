@@ -0,0 +1,120 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlconn
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncLenInt(t *testing.T) {
+	tests := []struct {
+		value   uint64
+		encoded []byte
+	}{
+		{0x00, []byte{0x00}},
+		{0x0a, []byte{0x0a}},
+		{0xfa, []byte{0xfa}},
+		{0xfb, []byte{0xfc, 0xfb, 0x00}},
+		{0xfc, []byte{0xfc, 0xfc, 0x00}},
+		{0xfd, []byte{0xfc, 0xfd, 0x00}},
+		{0xfe, []byte{0xfc, 0xfe, 0x00}},
+		{0xff, []byte{0xfc, 0xff, 0x00}},
+		{0x0100, []byte{0xfc, 0x00, 0x01}},
+		{0x876a, []byte{0xfc, 0x6a, 0x87}},
+		{0xffff, []byte{0xfc, 0xff, 0xff}},
+		{0x010000, []byte{0xfd, 0x00, 0x00, 0x01}},
+		{0xabcdef, []byte{0xfd, 0xef, 0xcd, 0xab}},
+		{0xffffff, []byte{0xfd, 0xff, 0xff, 0xff}},
+		{0x01000000, []byte{0xfe, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+		{0xa0a1a2a3a4a5a6a7, []byte{0xfe, 0xa7, 0xa6, 0xa5, 0xa4, 0xa3, 0xa2, 0xa1, 0xa0}},
+	}
+	for _, test := range tests {
+		// Check lenEncIntSize first.
+		if got := lenEncIntSize(test.value); got != len(test.encoded) {
+			t.Errorf("lenEncIntSize(%x) returned %v but expected %v", test.value, got, len(test.encoded))
+		}
+
+		// Check successful encoding.
+		data := make([]byte, len(test.encoded))
+		pos := writeLenEncInt(data, 0, test.value)
+		if pos != len(test.encoded) {
+			t.Errorf("unexpected pos %v after writeLenEncInt(%x), expected %v", pos, test.value, len(test.encoded))
+		}
+		if !bytes.Equal(data, test.encoded) {
+			t.Errorf("unexpected encoded value for %x, got %v expected %v", test.value, data, test.encoded)
+		}
+
+		// Check successful decoding.
+		got, pos, ok := readLenEncInt(test.encoded, 0)
+		if !ok || got != test.value || pos != len(test.encoded) {
+			t.Errorf("readLenEncInt returned %x/%v/%v but expected %x/%v/%v", got, pos, ok, test.value, len(test.encoded), true)
+		}
+
+		// Check failed decoding.
+		_, _, ok = readLenEncInt(test.encoded[:len(test.encoded)-1], 0)
+		if ok {
+			t.Errorf("readLenEncInt returned ok=true for shorter value %x", test.value)
+		}
+	}
+}
+
+func TestEncStrings(t *testing.T) {
+	tests := []struct {
+		value       string
+		lenEncoded  []byte
+		nullEncoded []byte
+	}{
+		{
+			value:       "",
+			lenEncoded:  []byte{0x00},
+			nullEncoded: []byte{0x00},
+		},
+		{
+			value:       "a",
+			lenEncoded:  []byte{0x01, 'a'},
+			nullEncoded: []byte{'a', 0x00},
+		},
+		{
+			value:       "0123456789",
+			lenEncoded:  []byte{0x0a, '0', '1', '2', '3', '4', '5', '6', '7', '8', '9'},
+			nullEncoded: []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 0x00},
+		},
+	}
+	for _, test := range tests {
+		// len encoded strings.
+		if got := lenEncStringSize(test.value); got != len(test.lenEncoded) {
+			t.Errorf("lenEncStringSize(%v) returned %v but expected %v", test.value, got, len(test.lenEncoded))
+		}
+		data := make([]byte, len(test.lenEncoded))
+		pos := writeLenEncString(data, 0, test.value)
+		if pos != len(test.lenEncoded) || !bytes.Equal(data, test.lenEncoded) {
+			t.Errorf("writeLenEncString(%v) returned %v/%v but expected %v/%v", test.value, data, pos, test.lenEncoded, len(test.lenEncoded))
+		}
+		got, pos, ok := readLenEncString(test.lenEncoded, 0)
+		if !ok || got != test.value || pos != len(test.lenEncoded) {
+			t.Errorf("readLenEncString returned %v/%v/%v but expected %v/%v/%v", got, pos, ok, test.value, len(test.lenEncoded), true)
+		}
+		if len(test.lenEncoded) > 1 {
+			if _, _, ok := readLenEncString(test.lenEncoded[:len(test.lenEncoded)-1], 0); ok {
+				t.Errorf("readLenEncString returned ok=true for shorter value %v", test.value)
+			}
+		}
+
+		// null encoded strings.
+		data = make([]byte, len(test.nullEncoded))
+		pos = writeNullString(data, 0, test.value)
+		if pos != len(test.nullEncoded) || !bytes.Equal(data, test.nullEncoded) {
+			t.Errorf("writeNullString(%v) returned %v/%v but expected %v/%v", test.value, data, pos, test.nullEncoded, len(test.nullEncoded))
+		}
+		got, pos, ok = readNullString(test.nullEncoded, 0)
+		if !ok || got != test.value || pos != len(test.nullEncoded) {
+			t.Errorf("readNullString returned %v/%v/%v but expected %v/%v/%v", got, pos, ok, test.value, len(test.nullEncoded), true)
+		}
+		if _, _, ok := readNullString(test.nullEncoded[:len(test.nullEncoded)-1], 0); ok {
+			t.Errorf("readNullString returned ok=true for shorter value %v", test.value)
+		}
+	}
+}
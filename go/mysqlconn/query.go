@@ -0,0 +1,122 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlconn
+
+import (
+	"fmt"
+
+	"github.com/youtube/vitess/go/sqltypes"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// This file contains the methods related to writing result sets,
+// using the text protocol.
+
+// writeColumnDefinition writes a single column definition packet.
+func (c *Conn) writeColumnDefinition(field *querypb.Field) error {
+	typ, flags := sqltypes.TypeToMySQL(field.Type)
+	characterSet := uint16(CharacterSetBinary)
+	if sqltypes.IsText(field.Type) {
+		characterSet = CharacterSetUtf8
+	}
+
+	length := 4 + // lenEncStringSize("def")
+		lenEncStringSize("") + // schema
+		lenEncStringSize("") + // table
+		lenEncStringSize("") + // org_table
+		lenEncStringSize(field.Name) +
+		lenEncStringSize("") + // org_name
+		1 + // length of fixed length fields
+		2 + // character set
+		4 + // column length
+		1 + // type
+		2 + // flags
+		1 + // decimals
+		2 // filler
+
+	data := make([]byte, length)
+	pos := writeLenEncString(data, 0, "def")
+	pos = writeLenEncString(data, pos, "")
+	pos = writeLenEncString(data, pos, "")
+	pos = writeLenEncString(data, pos, "")
+	pos = writeLenEncString(data, pos, field.Name)
+	pos = writeLenEncString(data, pos, "")
+	pos = writeByte(data, pos, 0x0c)
+	pos = writeUint16(data, pos, characterSet)
+	// Column length is not tracked by Vitess, advertise the maximum.
+	pos = writeUint32(data, pos, 0xffffffff)
+	pos = writeByte(data, pos, byte(typ))
+	pos = writeUint16(data, pos, uint16(flags))
+	pos = writeByte(data, pos, 0)
+	pos += 2
+
+	if pos != len(data) {
+		return fmt.Errorf("internal error: packing of column definition used %v bytes instead of %v", pos, len(data))
+	}
+	return c.writePacket(data)
+}
+
+// writeFields writes the column count, the fields, and the EOF
+// packet that terminates them.
+func (c *Conn) writeFields(result *sqltypes.Result) error {
+	// Send the number of fields first.
+	count := uint64(len(result.Fields))
+	data := make([]byte, lenEncIntSize(count))
+	writeLenEncInt(data, 0, count)
+	if err := c.writePacket(data); err != nil {
+		return err
+	}
+
+	// Now send each Field.
+	for i, field := range result.Fields {
+		if err := c.writeColumnDefinition(field); err != nil {
+			return fmt.Errorf("writeColumnDefinition(%v) failed: %v", i, err)
+		}
+	}
+
+	// Now send an EOF packet.
+	return c.writeEOFPacket(c.StatusFlags, 0)
+}
+
+// writeRow writes a single row using the text protocol.
+func (c *Conn) writeRow(row []sqltypes.Value) error {
+	length := 0
+	for _, val := range row {
+		if val.IsNull() {
+			length++
+		} else {
+			l := len(val.Raw())
+			length += lenEncIntSize(uint64(l)) + l
+		}
+	}
+
+	data := make([]byte, length)
+	pos := 0
+	for _, val := range row {
+		if val.IsNull() {
+			pos = writeByte(data, pos, NullValue)
+		} else {
+			raw := val.Raw()
+			pos = writeLenEncInt(data, pos, uint64(len(raw)))
+			pos += copy(data[pos:], raw)
+		}
+	}
+
+	if pos != length {
+		return fmt.Errorf("internal error: packet row length %v doesn't match computed length %v", pos, length)
+	}
+	return c.writePacket(data)
+}
+
+// writeRows sends the rows of a Result.
+func (c *Conn) writeRows(result *sqltypes.Result) error {
+	for _, row := range result.Rows {
+		if err := c.writeRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,502 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlconn
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+
+	log "github.com/golang/glog"
+
+	"github.com/youtube/vitess/go/sqldb"
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/tb"
+)
+
+const (
+	// DefaultServerVersion is the default server version we're sending to the client.
+	// Can be changed.
+	DefaultServerVersion = "5.5.10-Vitess"
+)
+
+// A Handler is an interface used by Listener to send queries.
+// The implementation of this interface may store data in the ClientData
+// field of the Connection for its own purposes.
+//
+// For a given Connection, all these methods are serialized. It means
+// only one of these methods will be called concurrently for a given
+// Connection. So access to the Connection ClientData does not need to
+// be protected by a mutex.
+//
+// However, each connection is using one go routine, so multiple
+// Connection objects can call these concurrently, for different Connections.
+type Handler interface {
+	// NewConnection is called when a connection is created.
+	// It is not established yet. The handler can decide to
+	// set StatusFlags that will be returned by the handshake methods.
+	// In particular, ServerStatusAutocommit might be set.
+	NewConnection(c *Conn)
+
+	// ConnectionClosed is called when a connection is closed.
+	ConnectionClosed(c *Conn)
+
+	// ComQuery is called when a connection receives a query.
+	// The callback is called once per batch of results. The first
+	// batch carries the fields (if any), the next ones only rows.
+	// A result without fields is returned to the client as an OK
+	// packet, with its RowsAffected and InsertID.
+	ComQuery(c *Conn, query string, callback func(*sqltypes.Result) error) error
+}
+
+// Listener is the MySQL server protocol listener.
+type Listener struct {
+	// Construction parameters, set by NewListener.
+
+	// authServer is the AuthServer object to use for authentication.
+	authServer AuthServer
+
+	// handler is the data handler.
+	handler Handler
+
+	// This is the main listener socket.
+	listener net.Listener
+
+	// The following parameters are read by multiple connection go
+	// routines.  They are not protected by a mutex, so they
+	// should be set after NewListener, and not changed while
+	// Accept is running.
+
+	// ServerVersion is the version we will advertise.
+	ServerVersion string
+
+	// The following parameters are changed by the Accept routine.
+
+	// Incrementing ID for connection id.
+	connectionID uint32
+}
+
+// NewListener creates a new Listener.
+func NewListener(protocol, address string, authServer AuthServer, handler Handler) (*Listener, error) {
+	listener, err := net.Listen(protocol, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Listener{
+		authServer:    authServer,
+		handler:       handler,
+		listener:      listener,
+		ServerVersion: DefaultServerVersion,
+		connectionID:  1,
+	}, nil
+}
+
+// Addr returns the listener address.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Accept runs an accept loop until the listener is closed.
+func (l *Listener) Accept() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			// Close() was probably called.
+			return
+		}
+
+		connectionID := atomic.AddUint32(&l.connectionID, 1) - 1
+		go l.handle(conn, connectionID)
+	}
+}
+
+// handle is called in a go routine for each client connection.
+func (l *Listener) handle(conn net.Conn, connectionID uint32) {
+	c := newConn(conn)
+	c.ConnectionID = connectionID
+
+	// Catch panics, and close the connection in any case.
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("mysql_server caught panic:\n%v\n%s", x, tb.Stack(4))
+		}
+		conn.Close()
+	}()
+
+	// Tell the handler about the connection coming and going.
+	l.handler.NewConnection(c)
+	defer l.handler.ConnectionClosed(c)
+
+	// First build and send the server handshake packet.
+	salt, err := l.authServer.Salt()
+	if err != nil {
+		log.Errorf("Cannot get salt for connection %v: %v", c.ConnectionID, err)
+		return
+	}
+	if err := c.writeHandshakeV10(l.ServerVersion, salt); err != nil {
+		log.Errorf("Cannot send HandshakeV10 packet to %v: %v", c.RemoteAddr(), err)
+		return
+	}
+
+	// Wait for the client response.
+	response, err := c.readPacket()
+	if err != nil {
+		log.Errorf("Cannot read client handshake response from %v: %v", c.RemoteAddr(), err)
+		return
+	}
+	authMethod, authResponse, err := c.parseClientHandshakePacket(response)
+	if err != nil {
+		log.Errorf("Cannot parse client handshake response from %v: %v", c.RemoteAddr(), err)
+		return
+	}
+
+	// The client may have picked a different auth plugin (for
+	// instance newer MySQL clients default to
+	// caching_sha2_password). Ask it to switch to ours.
+	if authMethod != mysqlNativePassword {
+		if err := c.writeAuthSwitchRequest(mysqlNativePassword, salt); err != nil {
+			log.Errorf("Cannot send AuthSwitchRequest packet to %v: %v", c.RemoteAddr(), err)
+			return
+		}
+		authResponse, err = c.readPacket()
+		if err != nil {
+			log.Errorf("Cannot read AuthSwitchResponse from %v: %v", c.RemoteAddr(), err)
+			return
+		}
+	}
+
+	userData, err := l.authServer.ValidateHash(salt, c.User, authResponse)
+	if err != nil {
+		c.writeErrorPacketFromError(err)
+		c.flush()
+		return
+	}
+	c.UserData = userData
+
+	// Send an OK packet.
+	if err := c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
+		log.Errorf("Cannot write OK packet: %v", err)
+		return
+	}
+	if err := c.flush(); err != nil {
+		log.Errorf("Cannot flush OK packet: %v", err)
+		return
+	}
+
+	for {
+		c.sequence = 0
+		data, err := c.readPacket()
+		if err != nil {
+			// Don't log EOF errors. They cause too much spam.
+			return
+		}
+		if len(data) == 0 {
+			log.Errorf("Got empty packet from %v, closing connection", c.ConnectionID)
+			return
+		}
+
+		switch data[0] {
+		case ComQuit:
+			return
+		case ComInitDB:
+			c.SchemaName = string(data[1:])
+			if err := c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
+				log.Errorf("Error writing ComInitDB result to %v: %v", c.ConnectionID, err)
+				return
+			}
+		case ComQuery:
+			query := string(data[1:])
+			if err := l.execQuery(c, query); err != nil {
+				log.Errorf("Error writing query result to %v: %v", c.ConnectionID, err)
+				return
+			}
+		case ComPing:
+			if err := c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
+				log.Errorf("Error writing ComPing result to %v: %v", c.ConnectionID, err)
+				return
+			}
+		default:
+			log.Errorf("Got unhandled packet from %v, returning error: %v", c.ConnectionID, data)
+			if err := c.writeErrorPacket(ERUnknownComError, SSUnknownComError, "command handling not implemented yet: %v", data[0]); err != nil {
+				log.Errorf("Error writing error packet to %v: %v", c.ConnectionID, err)
+				return
+			}
+		}
+
+		if err := c.flush(); err != nil {
+			log.Errorf("Conn %v: Flush() failed: %v", c.ConnectionID, err)
+			return
+		}
+	}
+}
+
+// execQuery runs a ComQuery through the handler, and writes the
+// result, or the error, to the client. The returned error is only
+// set if the connection is no longer usable.
+func (l *Listener) execQuery(c *Conn, query string) error {
+	fieldSent := false
+	var okResult *sqltypes.Result
+	err := l.handler.ComQuery(c, query, func(qr *sqltypes.Result) error {
+		if fieldSent {
+			return c.writeRows(qr)
+		}
+		if len(qr.Fields) == 0 {
+			// This is a DML or a DDL, returned as an OK packet.
+			okResult = qr
+			return nil
+		}
+		fieldSent = true
+		if err := c.writeFields(qr); err != nil {
+			return err
+		}
+		return c.writeRows(qr)
+	})
+
+	switch {
+	case err != nil:
+		// The MySQL protocol allows an error packet
+		// to be sent in the middle of the rows.
+		return c.writeErrorPacketFromError(err)
+	case fieldSent:
+		return c.writeEOFPacket(c.StatusFlags, 0)
+	case okResult != nil:
+		return c.writeOKPacket(okResult.RowsAffected, okResult.InsertID, c.StatusFlags, 0)
+	}
+	return c.writeOKPacket(0, 0, c.StatusFlags, 0)
+}
+
+// writeHandshakeV10 writes the Initial Handshake Packet, server side.
+// It returns the salt data.
+func (c *Conn) writeHandshakeV10(serverVersion string, salt []byte) error {
+	capabilities := uint32(
+		CapabilityClientLongPassword |
+			CapabilityClientFoundRows |
+			CapabilityClientLongFlag |
+			CapabilityClientConnectWithDB |
+			CapabilityClientProtocol41 |
+			CapabilityClientTransactions |
+			CapabilityClientSecureConnection |
+			CapabilityClientPluginAuth |
+			CapabilityClientPluginAuthLenencClientData)
+
+	length :=
+		1 + // protocol version
+			len(serverVersion) + 1 + // null-terminated
+			4 + // connection ID
+			8 + // first part of salt data
+			1 + // filler byte
+			2 + // capability flags (lower 2 bytes)
+			1 + // character set
+			2 + // status flag
+			2 + // capability flags (upper 2 bytes)
+			1 + // length of auth plugin data
+			10 + // reserved (0)
+			13 + // auth-plugin-data
+			len(mysqlNativePassword) + 1 // auth-plugin-name
+
+	data := make([]byte, length)
+	pos := 0
+
+	// Protocol version.
+	pos = writeByte(data, pos, protocolVersion)
+
+	// Copy server version.
+	pos = writeNullString(data, pos, serverVersion)
+
+	// Add connectionID in.
+	pos = writeUint32(data, pos, c.ConnectionID)
+
+	pos += copy(data[pos:], salt[:8])
+
+	// One filler byte, always 0.
+	pos = writeByte(data, pos, 0)
+
+	// Lower part of the capability flags.
+	pos = writeUint16(data, pos, uint16(capabilities))
+
+	// Character set.
+	pos = writeByte(data, pos, CharacterSetUtf8)
+
+	// Status flag.
+	pos = writeUint16(data, pos, c.StatusFlags)
+
+	// Upper part of the capability flags.
+	pos = writeUint16(data, pos, uint16(capabilities>>16))
+
+	// Length of auth plugin data.
+	// Always 21 (8 + 13).
+	pos = writeByte(data, pos, 21)
+
+	// Reserved 10 bytes: all 0
+	pos += 10
+
+	// Second part of auth plugin data.
+	pos += copy(data[pos:], salt[8:])
+	data[pos] = 0
+	pos++
+
+	// Copy authPluginName. We always start with mysql_native_password.
+	pos = writeNullString(data, pos, mysqlNativePassword)
+
+	// Sanity check.
+	if pos != len(data) {
+		return fmt.Errorf("error building Handshake packet: got %v bytes expected %v", pos, len(data))
+	}
+
+	if err := c.writePacket(data); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// parseClientHandshakePacket parses the handshake sent by the client.
+// Returns the auth method and auth response, and sets the connection
+// fields (User, SchemaName, Capabilities, CharacterSet).
+func (c *Conn) parseClientHandshakePacket(data []byte) (string, []byte, error) {
+	pos := 0
+
+	// Client flags, 4 bytes.
+	clientFlags, pos, ok := readUint32(data, pos)
+	if !ok {
+		return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read client flags")
+	}
+	if clientFlags&CapabilityClientProtocol41 == 0 {
+		return "", nil, fmt.Errorf("parseClientHandshakePacket: only support protocol 4.1")
+	}
+	if clientFlags&CapabilityClientSSL > 0 {
+		return "", nil, fmt.Errorf("parseClientHandshakePacket: SSL is not supported")
+	}
+
+	// Remember a subset of the capabilities, so we can use them
+	// later in the protocol.
+	c.Capabilities = clientFlags & (CapabilityClientFoundRows)
+
+	// Max packet size. Don't do anything with this now.
+	_, pos, ok = readUint32(data, pos)
+	if !ok {
+		return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read maxPacketSize")
+	}
+
+	// Character set. Need to handle it.
+	characterSet, pos, ok := readByte(data, pos)
+	if !ok {
+		return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read characterSet")
+	}
+	c.CharacterSet = characterSet
+
+	// 23x reserved zero bytes.
+	pos += 23
+
+	// username
+	username, pos, ok := readNullString(data, pos)
+	if !ok {
+		return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read username")
+	}
+	c.User = username
+
+	// auth-response can have three forms.
+	var authResponse []byte
+	if clientFlags&CapabilityClientPluginAuthLenencClientData != 0 {
+		var l uint64
+		l, pos, ok = readLenEncInt(data, pos)
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read auth-response variable length")
+		}
+		authResponse, pos, ok = readBytes(data, pos, int(l))
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read auth-response")
+		}
+
+	} else if clientFlags&CapabilityClientSecureConnection != 0 {
+		var l byte
+		l, pos, ok = readByte(data, pos)
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read auth-response length")
+		}
+
+		authResponse, pos, ok = readBytes(data, pos, int(l))
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read auth-response")
+		}
+	} else {
+		a := ""
+		a, pos, ok = readNullString(data, pos)
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read auth-response")
+		}
+		authResponse = []byte(a)
+	}
+
+	// db name.
+	if clientFlags&CapabilityClientConnectWithDB != 0 {
+		dbname, p, ok := readNullString(data, pos)
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read dbname")
+		}
+		pos = p
+		c.SchemaName = dbname
+	}
+
+	// authMethod (with default)
+	authMethod := mysqlNativePassword
+	if clientFlags&CapabilityClientPluginAuth != 0 {
+		authMethod, pos, ok = readNullString(data, pos)
+		if !ok {
+			return "", nil, fmt.Errorf("parseClientHandshakePacket: can't read authMethod")
+		}
+	}
+
+	// Decode connection attributes send by the client
+	// (CLIENT_CONNECT_ATTRS) is not supported, as we don't
+	// advertise the capability.
+
+	// Make a copy of the auth response, as data will be reused.
+	return authMethod, append([]byte(nil), authResponse...), nil
+}
+
+// writeAuthSwitchRequest writes an auth switch request packet.
+func (c *Conn) writeAuthSwitchRequest(pluginName string, pluginData []byte) error {
+	length := 1 + // AuthSwitchRequestPacket
+		len(pluginName) + 1 + // 0-terminated pluginName
+		len(pluginData) + 1 // 0-terminated pluginData
+
+	data := make([]byte, length)
+	pos := writeByte(data, 0, AuthSwitchRequestPacket)
+	pos = writeNullString(data, pos, pluginName)
+	writeNullString(data, pos, string(pluginData))
+	if err := c.writePacket(data); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// errorRegexp extracts the MySQL error number and SQL state from
+// error messages that went through RPC boundaries and lost their type.
+var errorRegexp = regexp.MustCompile(`\(errno (\d+)\) \(sqlstate ([0-9A-Z]{5})\)`)
+
+// writeErrorPacketFromError writes an error packet, from a regular error.
+// If the error is a *sqldb.SQLError, or looks like one, the MySQL
+// error number and SQL state are preserved.
+func (c *Conn) writeErrorPacketFromError(err error) error {
+	if se, ok := err.(*sqldb.SQLError); ok {
+		return c.writeErrorPacket(uint16(se.Num), se.State, "%v", se.Message)
+	}
+
+	msg := err.Error()
+	if match := errorRegexp.FindStringSubmatch(msg); match != nil {
+		if num, perr := strconv.Atoi(match[1]); perr == nil {
+			return c.writeErrorPacket(uint16(num), match[2], "%v", msg)
+		}
+	}
+	return c.writeErrorPacket(ERUnknownError, SSUnknownSQLState, "unknown error: %v", msg)
+}
+
+// Close stops the listener, and hence all current connections.
+func (l *Listener) Close() {
+	l.listener.Close()
+}
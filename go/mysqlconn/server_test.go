@@ -0,0 +1,493 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlconn
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/youtube/vitess/go/sqldb"
+	"github.com/youtube/vitess/go/sqltypes"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+var selectRowsResult = &sqltypes.Result{
+	Fields: []*querypb.Field{
+		{
+			Name: "id",
+			Type: querypb.Type_INT32,
+		},
+		{
+			Name: "name",
+			Type: querypb.Type_VARCHAR,
+		},
+	},
+	Rows: [][]sqltypes.Value{
+		{
+			sqltypes.MakeTrusted(querypb.Type_INT32, []byte("10")),
+			sqltypes.MakeTrusted(querypb.Type_VARCHAR, []byte("nice name")),
+		},
+		{
+			sqltypes.MakeTrusted(querypb.Type_INT32, []byte("20")),
+			sqltypes.NULL,
+		},
+	},
+}
+
+type testHandler struct {
+	// mu protects lastConn, which is set by the server goroutines.
+	mu       sync.Mutex
+	lastConn *Conn
+}
+
+func (th *testHandler) NewConnection(c *Conn) {
+	th.setLastConn(c)
+}
+
+func (th *testHandler) setLastConn(c *Conn) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.lastConn = c
+}
+
+// LastConn returns the last connection the handler saw. Its fields
+// set during the handshake can be read once a query has been run on
+// it, since ComQuery also sets it.
+func (th *testHandler) LastConn() *Conn {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	return th.lastConn
+}
+
+func (th *testHandler) ConnectionClosed(c *Conn) {
+}
+
+func (th *testHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.Result) error) error {
+	th.setLastConn(c)
+	switch query {
+	case "error":
+		return sqldb.NewSQLError(ERUnknownComError, SSUnknownComError, "forced query handling error for: %v", query)
+	case "rpc error":
+		return fmt.Errorf("target: ks.0.master, vttablet: duplicate key (errno 1062) (sqlstate 23000) during query: insert")
+	case "insert":
+		return callback(&sqltypes.Result{
+			RowsAffected: 123,
+			InsertID:     123456789,
+		})
+	case "select rows":
+		return callback(selectRowsResult)
+	case "stream rows":
+		if err := callback(&sqltypes.Result{Fields: selectRowsResult.Fields}); err != nil {
+			return err
+		}
+		for _, row := range selectRowsResult.Rows {
+			if err := callback(&sqltypes.Result{Rows: [][]sqltypes.Value{row}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "schema echo":
+		return callback(&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{
+					Name: "schema_name",
+					Type: querypb.Type_VARCHAR,
+				},
+			},
+			Rows: [][]sqltypes.Value{
+				{
+					sqltypes.MakeTrusted(querypb.Type_VARCHAR, []byte(c.SchemaName)),
+				},
+			},
+		})
+	}
+	return callback(&sqltypes.Result{})
+}
+
+// testClient is a minimal client side implementation of the protocol,
+// used to exercise the server.
+type testClient struct {
+	*Conn
+}
+
+// connectForTest connects to the listener, and runs the handshake.
+// If authMethod is not mysql_native_password, the client will
+// advertise that plugin first, and expect an auth switch request.
+func connectForTest(addr net.Addr, user, password, dbname, authMethod string) (*testClient, error) {
+	conn, err := net.Dial(addr.Network(), addr.String())
+	if err != nil {
+		return nil, err
+	}
+	c := &testClient{Conn: newConn(conn)}
+
+	// Read and parse the server handshake.
+	data, err := c.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if data[0] != protocolVersion {
+		return nil, fmt.Errorf("bad protocol version %v", data[0])
+	}
+	pos := 1
+	c.ServerVersion, pos, _ = readNullString(data, pos)
+	c.ConnectionID, pos, _ = readUint32(data, pos)
+	salt1, pos, _ := readBytes(data, pos, 8)
+	// Filler, capabilities, character set, status flags, capabilities,
+	// auth data length, reserved bytes.
+	pos += 1 + 2 + 1 + 2 + 2 + 1 + 10
+	salt2, pos, _ := readBytes(data, pos, 12)
+	pos++
+	serverAuthMethod, _, ok := readNullString(data, pos)
+	if !ok || serverAuthMethod != mysqlNativePassword {
+		return nil, fmt.Errorf("bad server auth method %v", serverAuthMethod)
+	}
+	salt := append(append([]byte(nil), salt1...), salt2...)
+
+	// Send our response.
+	scrambled := scramblePassword(salt, []byte(password))
+	if authMethod != mysqlNativePassword {
+		scrambled = []byte("not a native password")
+	}
+	capabilities := uint32(CapabilityClientLongPassword |
+		CapabilityClientProtocol41 |
+		CapabilityClientSecureConnection |
+		CapabilityClientPluginAuth)
+	length := 4 + 4 + 1 + 23 + len(user) + 1 + 1 + len(scrambled) + len(authMethod) + 1
+	if dbname != "" {
+		capabilities |= CapabilityClientConnectWithDB
+		length += len(dbname) + 1
+	}
+	data = make([]byte, length)
+	pos = writeUint32(data, 0, capabilities)
+	pos = writeUint32(data, pos, maxPacketSize)
+	pos = writeByte(data, pos, CharacterSetUtf8)
+	pos += 23
+	pos = writeNullString(data, pos, user)
+	pos = writeByte(data, pos, byte(len(scrambled)))
+	pos += copy(data[pos:], scrambled)
+	if dbname != "" {
+		pos = writeNullString(data, pos, dbname)
+	}
+	writeNullString(data, pos, authMethod)
+	if err := c.writePacket(data); err != nil {
+		return nil, err
+	}
+	if err := c.flush(); err != nil {
+		return nil, err
+	}
+
+	data, err = c.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if data[0] == AuthSwitchRequestPacket {
+		pluginName, pos, _ := readNullString(data, 1)
+		if pluginName != mysqlNativePassword {
+			return nil, fmt.Errorf("unexpected auth switch to %v", pluginName)
+		}
+		salt, _, _ = readBytes(data, pos, 20)
+		if err := c.writePacket(scramblePassword(salt, []byte(password))); err != nil {
+			return nil, err
+		}
+		if err := c.flush(); err != nil {
+			return nil, err
+		}
+		data, err = c.readPacket()
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch data[0] {
+	case OKPacket:
+		return c, nil
+	case ErrPacket:
+		return nil, parseErrorPacket(data)
+	}
+	return nil, fmt.Errorf("unexpected handshake result packet: %v", data)
+}
+
+// writeCommand sends a command packet to the server.
+func (c *testClient) writeCommand(command byte, payload string) error {
+	c.sequence = 0
+	data := make([]byte, 1+len(payload))
+	data[0] = command
+	copy(data[1:], payload)
+	if err := c.writePacket(data); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// readOKPacket reads the response to a simple command.
+func (c *testClient) readOKPacket() error {
+	data, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	switch data[0] {
+	case OKPacket:
+		return nil
+	case ErrPacket:
+		return parseErrorPacket(data)
+	}
+	return fmt.Errorf("unexpected packet: %v", data)
+}
+
+// executeFetch sends a ComQuery and reads the full result.
+func (c *testClient) executeFetch(query string) (*sqltypes.Result, error) {
+	if err := c.writeCommand(ComQuery, query); err != nil {
+		return nil, err
+	}
+	data, err := c.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	switch data[0] {
+	case OKPacket:
+		affectedRows, pos, _ := readLenEncInt(data, 1)
+		lastInsertID, _, _ := readLenEncInt(data, pos)
+		return &sqltypes.Result{
+			RowsAffected: affectedRows,
+			InsertID:     lastInsertID,
+		}, nil
+	case ErrPacket:
+		return nil, parseErrorPacket(data)
+	}
+
+	count, _, _ := readLenEncInt(data, 0)
+	result := &sqltypes.Result{
+		Fields: make([]*querypb.Field, count),
+	}
+	for i := range result.Fields {
+		data, err := c.readPacket()
+		if err != nil {
+			return nil, err
+		}
+		// Skip catalog, schema, table, org_table.
+		pos := 0
+		for j := 0; j < 4; j++ {
+			_, pos, _ = readLenEncString(data, pos)
+		}
+		name, pos, _ := readLenEncString(data, pos)
+		// Skip org_name, fixed length, character set and column length.
+		_, pos, _ = readLenEncString(data, pos)
+		pos += 1 + 2 + 4
+		typ, pos, _ := readByte(data, pos)
+		flags, _, _ := readUint16(data, pos)
+		t, err := sqltypes.MySQLToType(int64(typ), int64(flags))
+		if err != nil {
+			return nil, err
+		}
+		result.Fields[i] = &querypb.Field{
+			Name: name,
+			Type: t,
+		}
+	}
+	if data, err := c.readPacket(); err != nil || !isEOFPacket(data) {
+		return nil, fmt.Errorf("expected EOF after fields, got %v %v", data, err)
+	}
+
+	for {
+		data, err := c.readPacket()
+		if err != nil {
+			return nil, err
+		}
+		if isEOFPacket(data) {
+			return result, nil
+		}
+		if data[0] == ErrPacket {
+			return nil, parseErrorPacket(data)
+		}
+		row := make([]sqltypes.Value, count)
+		pos := 0
+		for i := range row {
+			if data[pos] == NullValue {
+				pos++
+				continue
+			}
+			var s string
+			s, pos, _ = readLenEncString(data, pos)
+			row[i] = sqltypes.MakeTrusted(result.Fields[i].Type, []byte(s))
+		}
+		result.Rows = append(result.Rows, row)
+	}
+}
+
+// isEOFPacket returns true if the packet is an EOF packet. 0xfe is
+// also the first byte of large length encoded integers, so the
+// length needs to be checked too.
+func isEOFPacket(data []byte) bool {
+	return len(data) < 9 && data[0] == EOFPacket
+}
+
+// parseErrorPacket parses the error packet and returns a SQLError.
+func parseErrorPacket(data []byte) error {
+	code, pos, _ := readUint16(data, 1)
+	// Skip the '#' marker of the SQL state.
+	pos++
+	sqlState, pos, ok := readBytes(data, pos, 5)
+	if !ok {
+		return fmt.Errorf("invalid error packet: %v", data)
+	}
+	return sqldb.NewSQLError(int(code), string(sqlState), "%v", string(data[pos:]))
+}
+
+func newTestListener(t *testing.T, authServer AuthServer) (*Listener, *testHandler) {
+	th := &testHandler{}
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th)
+	if err != nil {
+		t.Fatalf("NewListener failed: %v", err)
+	}
+	go l.Accept()
+	return l, th
+}
+
+func TestServer(t *testing.T) {
+	authServer := NewAuthServerStatic()
+	authServer.Entries["user1"] = &AuthServerStaticEntry{
+		Password: "password1",
+		UserData: "userData1",
+	}
+	l, th := newTestListener(t, authServer)
+	defer l.Close()
+
+	c, err := connectForTest(l.Addr(), "user1", "password1", "ks1", mysqlNativePassword)
+	if err != nil {
+		t.Fatalf("connectForTest failed: %v", err)
+	}
+	defer c.Close()
+	if c.ServerVersion != DefaultServerVersion {
+		t.Errorf("ServerVersion: %v, want %v", c.ServerVersion, DefaultServerVersion)
+	}
+
+	// Regular select.
+	qr, err := c.executeFetch("select rows")
+	if err != nil {
+		t.Fatalf("select rows failed: %v", err)
+	}
+	if !reflect.DeepEqual(qr, selectRowsResult) {
+		t.Errorf("select rows:\n%#v, want\n%#v", qr, selectRowsResult)
+	}
+	if sc := th.LastConn(); sc.User != "user1" || sc.UserData != "userData1" || sc.SchemaName != "ks1" {
+		t.Errorf("server side conn: %v/%v/%v, want user1/userData1/ks1", sc.User, sc.UserData, sc.SchemaName)
+	}
+
+	// Streamed select.
+	qr, err = c.executeFetch("stream rows")
+	if err != nil {
+		t.Fatalf("stream rows failed: %v", err)
+	}
+	if !reflect.DeepEqual(qr, selectRowsResult) {
+		t.Errorf("stream rows:\n%#v, want\n%#v", qr, selectRowsResult)
+	}
+
+	// DML.
+	qr, err = c.executeFetch("insert")
+	if err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	if qr.RowsAffected != 123 || qr.InsertID != 123456789 {
+		t.Errorf("insert: %v, want 123/123456789", qr)
+	}
+
+	// Query errors don't close the connection.
+	_, err = c.executeFetch("error")
+	if se, ok := err.(*sqldb.SQLError); !ok || se.Num != ERUnknownComError || se.State != SSUnknownComError {
+		t.Errorf("error: %v, want ERUnknownComError", err)
+	}
+	_, err = c.executeFetch("rpc error")
+	if se, ok := err.(*sqldb.SQLError); !ok || se.Num != 1062 || se.State != "23000" {
+		t.Errorf("rpc error: %v, want errno 1062", err)
+	}
+
+	// ComInitDB, then make sure the handler sees it.
+	if err := c.writeCommand(ComInitDB, "ks2"); err != nil {
+		t.Fatalf("ComInitDB failed: %v", err)
+	}
+	if err := c.readOKPacket(); err != nil {
+		t.Fatalf("ComInitDB failed: %v", err)
+	}
+	qr, err = c.executeFetch("schema echo")
+	if err != nil || qr.Rows[0][0].String() != "ks2" {
+		t.Errorf("schema echo: %v %v, want ks2", qr, err)
+	}
+
+	// ComPing.
+	if err := c.writeCommand(ComPing, ""); err != nil {
+		t.Fatalf("ComPing failed: %v", err)
+	}
+	if err := c.readOKPacket(); err != nil {
+		t.Fatalf("ComPing failed: %v", err)
+	}
+
+	// Unknown command.
+	if err := c.writeCommand(0x1f, ""); err != nil {
+		t.Fatalf("writeCommand failed: %v", err)
+	}
+	err = c.readOKPacket()
+	if err == nil || !strings.Contains(err.Error(), "command handling not implemented yet") {
+		t.Errorf("unknown command: %v", err)
+	}
+}
+
+func TestServerAuthSwitch(t *testing.T) {
+	authServer := NewAuthServerStatic()
+	authServer.Entries["user1"] = &AuthServerStaticEntry{
+		Password: "password1",
+	}
+	l, _ := newTestListener(t, authServer)
+	defer l.Close()
+
+	c, err := connectForTest(l.Addr(), "user1", "password1", "", "caching_sha2_password")
+	if err != nil {
+		t.Fatalf("connectForTest failed: %v", err)
+	}
+	defer c.Close()
+	if _, err := c.executeFetch("select rows"); err != nil {
+		t.Errorf("select rows failed: %v", err)
+	}
+}
+
+func TestServerAuthFailure(t *testing.T) {
+	authServer := NewAuthServerStatic()
+	authServer.Entries["user1"] = &AuthServerStaticEntry{
+		Password: "password1",
+	}
+	l, _ := newTestListener(t, authServer)
+	defer l.Close()
+
+	for _, user := range []string{"user1", "user2"} {
+		_, err := connectForTest(l.Addr(), user, "bad password", "", mysqlNativePassword)
+		se, ok := err.(*sqldb.SQLError)
+		if !ok || se.Num != ERAccessDeniedError || se.State != SSAccessDeniedError {
+			t.Errorf("connectForTest(%v) returned %v, want access denied", user, err)
+		}
+	}
+}
+
+func TestAuthServerStaticFromString(t *testing.T) {
+	a, err := NewAuthServerStaticFromString(`{"user1": {"Password": "password1", "UserData": "data1"}}`)
+	if err != nil {
+		t.Fatalf("NewAuthServerStaticFromString failed: %v", err)
+	}
+	want := map[string]*AuthServerStaticEntry{
+		"user1": {
+			Password: "password1",
+			UserData: "data1",
+		},
+	}
+	if !reflect.DeepEqual(a.Entries, want) {
+		t.Errorf("Entries: %v, want %v", a.Entries, want)
+	}
+
+	if _, err := NewAuthServerStaticFromString("not json"); err == nil {
+		t.Errorf("NewAuthServerStaticFromString(not json) succeeded")
+	}
+}
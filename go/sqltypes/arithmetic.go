@@ -0,0 +1,208 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqltypes
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// This file contains functions that perform comparisons
+// and arithmetic on Values, as needed by VTGate to
+// merge results coming from multiple shards.
+
+// NullsafeCompare returns 0 if v1==v2, -1 if v1<v2, and 1 if v1>v2.
+// NULL is the lowest value. If either value is numeric, both values
+// are compared numerically. Otherwise, the raw bytes are compared.
+// An error is returned if a numeric value is compared against a
+// non-numeric one. An error is also returned for text values, as
+// well as enums and sets: MySQL compares them according to their
+// collation, which is not known here, so comparing their bytes
+// could produce a different order.
+func NullsafeCompare(v1, v2 Value) (int, error) {
+	switch {
+	case v1.IsNull() && v2.IsNull():
+		return 0, nil
+	case v1.IsNull():
+		return -1, nil
+	case v2.IsNull():
+		return 1, nil
+	}
+	n1, n2 := isNumber(v1.typ), isNumber(v2.typ)
+	switch {
+	case n1 && n2:
+		return compareNumeric(v1, v2)
+	case n1 || n2:
+		return 0, fmt.Errorf("types are not comparable: %v vs %v", v1.typ, v2.typ)
+	case IsCollated(v1.typ) || IsCollated(v2.typ):
+		return 0, fmt.Errorf("text values cannot be compared without their collation: %v vs %v", v1.typ, v2.typ)
+	}
+	return bytes.Compare(v1.val, v2.val), nil
+}
+
+// IsCollated returns true if the values of the type are compared
+// by MySQL according to a collation.
+func IsCollated(typ querypb.Type) bool {
+	return IsText(typ) || typ == Enum || typ == Set
+}
+
+// NullsafeAdd adds two Values in a null-safe manner. A null value
+// is treated as 0. If both values are null, then a null is returned.
+// Two signed or two unsigned integrals produce an Int64 or a Uint64.
+// If either value is a float, the result is a Float64. Otherwise,
+// the values are added as decimals, which produces a Decimal.
+// Non-numeric values return an error.
+func NullsafeAdd(v1, v2 Value) (Value, error) {
+	switch {
+	case v1.IsNull():
+		return v2, nil
+	case v2.IsNull():
+		return v1, nil
+	}
+	if !isNumber(v1.typ) || !isNumber(v2.typ) {
+		return NULL, fmt.Errorf("cannot add non-numeric values: %v, %v", v1.typ, v2.typ)
+	}
+	switch {
+	case IsSigned(v1.typ) && IsSigned(v2.typ):
+		i1, err := strconv.ParseInt(string(v1.val), 10, 64)
+		if err != nil {
+			return NULL, err
+		}
+		i2, err := strconv.ParseInt(string(v2.val), 10, 64)
+		if err != nil {
+			return NULL, err
+		}
+		result := i1 + i2
+		if (result > i1) != (i2 > 0) {
+			return NULL, fmt.Errorf("integer overflow: %d + %d", i1, i2)
+		}
+		return Value{typ: Int64, val: strconv.AppendInt(nil, result, 10)}, nil
+	case IsUnsigned(v1.typ) && IsUnsigned(v2.typ):
+		u1, err := strconv.ParseUint(string(v1.val), 10, 64)
+		if err != nil {
+			return NULL, err
+		}
+		u2, err := strconv.ParseUint(string(v2.val), 10, 64)
+		if err != nil {
+			return NULL, err
+		}
+		result := u1 + u2
+		if result < u1 {
+			return NULL, fmt.Errorf("unsigned integer overflow: %d + %d", u1, u2)
+		}
+		return Value{typ: Uint64, val: strconv.AppendUint(nil, result, 10)}, nil
+	case IsFloat(v1.typ) || IsFloat(v2.typ):
+		f1, err := strconv.ParseFloat(string(v1.val), 64)
+		if err != nil {
+			return NULL, err
+		}
+		f2, err := strconv.ParseFloat(string(v2.val), 64)
+		if err != nil {
+			return NULL, err
+		}
+		return Value{typ: Float64, val: strconv.AppendFloat(nil, f1+f2, 'g', -1, 64)}, nil
+	}
+	r1, ok := new(big.Rat).SetString(string(v1.val))
+	if !ok {
+		return NULL, fmt.Errorf("could not parse value: %s", v1.val)
+	}
+	r2, ok := new(big.Rat).SetString(string(v2.val))
+	if !ok {
+		return NULL, fmt.Errorf("could not parse value: %s", v2.val)
+	}
+	scale := decimalScale(v1.val)
+	if s2 := decimalScale(v2.val); s2 > scale {
+		scale = s2
+	}
+	return Value{typ: Decimal, val: []byte(r1.Add(r1, r2).FloatString(scale))}, nil
+}
+
+// decimalScale returns the number of digits after
+// the decimal point of a number.
+func decimalScale(val []byte) int {
+	if i := bytes.IndexByte(val, '.'); i != -1 {
+		return len(val) - i - 1
+	}
+	return 0
+}
+
+// isNumber returns true if the type is integral, float or decimal.
+func isNumber(typ querypb.Type) bool {
+	return IsIntegral(typ) || IsFloat(typ) || typ == Decimal
+}
+
+// compareNumeric compares two numeric values. Integral values
+// are compared without loss of precision. If either value
+// is not integral, both are converted to float64.
+func compareNumeric(v1, v2 Value) (int, error) {
+	switch {
+	case IsSigned(v1.typ) && IsSigned(v2.typ):
+		i1, err := strconv.ParseInt(string(v1.val), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		i2, err := strconv.ParseInt(string(v2.val), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compareInt64(i1, i2), nil
+	case IsIntegral(v1.typ) && IsIntegral(v2.typ):
+		// At least one of the values is unsigned.
+		if IsSigned(v1.typ) && bytes.HasPrefix(v1.val, []byte{'-'}) {
+			return -1, nil
+		}
+		if IsSigned(v2.typ) && bytes.HasPrefix(v2.val, []byte{'-'}) {
+			return 1, nil
+		}
+		u1, err := strconv.ParseUint(string(v1.val), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		u2, err := strconv.ParseUint(string(v2.val), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compareUint64(u1, u2), nil
+	}
+	f1, err := strconv.ParseFloat(string(v1.val), 64)
+	if err != nil {
+		return 0, err
+	}
+	f2, err := strconv.ParseFloat(string(v2.val), 64)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case f1 < f2:
+		return -1, nil
+	case f1 > f2:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func compareInt64(i1, i2 int64) int {
+	switch {
+	case i1 < i2:
+		return -1
+	case i1 > i2:
+		return 1
+	}
+	return 0
+}
+
+func compareUint64(u1, u2 uint64) int {
+	switch {
+	case u1 < u2:
+		return -1
+	case u1 > u2:
+		return 1
+	}
+	return 0
+}
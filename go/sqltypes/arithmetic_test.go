@@ -0,0 +1,170 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqltypes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNullsafeCompare(t *testing.T) {
+	testcases := []struct {
+		v1, v2 Value
+		out    int
+		err    string
+	}{{
+		v1:  NULL,
+		v2:  NULL,
+		out: 0,
+	}, {
+		v1:  NULL,
+		v2:  testVal(Int64, "1"),
+		out: -1,
+	}, {
+		v1:  testVal(Int64, "1"),
+		v2:  NULL,
+		out: 1,
+	}, {
+		v1:  testVal(Int64, "10"),
+		v2:  testVal(Int64, "9"),
+		out: 1,
+	}, {
+		v1:  testVal(Int32, "-1"),
+		v2:  testVal(Int64, "-1"),
+		out: 0,
+	}, {
+		v1:  testVal(Int64, "-1"),
+		v2:  testVal(Uint64, "18446744073709551615"),
+		out: -1,
+	}, {
+		v1:  testVal(Uint64, "18446744073709551615"),
+		v2:  testVal(Int64, "-1"),
+		out: 1,
+	}, {
+		v1:  testVal(Uint64, "2"),
+		v2:  testVal(Int64, "10"),
+		out: -1,
+	}, {
+		v1:  testVal(Float64, "1.5"),
+		v2:  testVal(Int64, "1"),
+		out: 1,
+	}, {
+		v1:  testVal(Decimal, "1.50"),
+		v2:  testVal(Float64, "1.5"),
+		out: 0,
+	}, {
+		v1:  testVal(VarBinary, "b"),
+		v2:  testVal(VarBinary, "abc"),
+		out: 1,
+	}, {
+		v1:  testVal(Datetime, "2016-01-02 00:00:00"),
+		v2:  testVal(Datetime, "2016-01-01 23:59:59"),
+		out: 1,
+	}, {
+		v1:  testVal(VarChar, "b"),
+		v2:  testVal(VarChar, "abc"),
+		err: "text values cannot be compared without their collation: VARCHAR vs VARCHAR",
+	}, {
+		v1:  testVal(VarBinary, "a"),
+		v2:  testVal(VarChar, "a"),
+		err: "text values cannot be compared without their collation: VARBINARY vs VARCHAR",
+	}, {
+		v1:  testVal(Enum, "a"),
+		v2:  testVal(Enum, "b"),
+		err: "text values cannot be compared without their collation: ENUM vs ENUM",
+	}, {
+		v1:  testVal(Int64, "1"),
+		v2:  testVal(VarChar, "1"),
+		err: "types are not comparable: INT64 vs VARCHAR",
+	}, {
+		v1:  testVal(Int64, "a"),
+		v2:  testVal(Int64, "1"),
+		err: `strconv.ParseInt: parsing "a": invalid syntax`,
+	}}
+	for _, tcase := range testcases {
+		got, err := NullsafeCompare(tcase.v1, tcase.v2)
+		if tcase.err != "" {
+			if err == nil || err.Error() != tcase.err {
+				t.Errorf("NullsafeCompare(%v, %v) error: %v, want %s", tcase.v1, tcase.v2, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NullsafeCompare(%v, %v) error: %v", tcase.v1, tcase.v2, err)
+			continue
+		}
+		if got != tcase.out {
+			t.Errorf("NullsafeCompare(%v, %v): %d, want %d", tcase.v1, tcase.v2, got, tcase.out)
+		}
+	}
+}
+
+func TestNullsafeAdd(t *testing.T) {
+	testcases := []struct {
+		v1, v2 Value
+		out    Value
+		err    string
+	}{{
+		v1:  NULL,
+		v2:  NULL,
+		out: NULL,
+	}, {
+		v1:  NULL,
+		v2:  testVal(Int64, "1"),
+		out: testVal(Int64, "1"),
+	}, {
+		v1:  testVal(Int64, "1"),
+		v2:  NULL,
+		out: testVal(Int64, "1"),
+	}, {
+		v1:  testVal(Int32, "1"),
+		v2:  testVal(Int64, "-3"),
+		out: testVal(Int64, "-2"),
+	}, {
+		v1:  testVal(Int64, "9223372036854775807"),
+		v2:  testVal(Int64, "1"),
+		err: "integer overflow: 9223372036854775807 + 1",
+	}, {
+		v1:  testVal(Uint64, "1"),
+		v2:  testVal(Uint32, "2"),
+		out: testVal(Uint64, "3"),
+	}, {
+		v1:  testVal(Uint64, "18446744073709551615"),
+		v2:  testVal(Uint64, "1"),
+		err: "unsigned integer overflow: 18446744073709551615 + 1",
+	}, {
+		v1:  testVal(Float64, "1.5"),
+		v2:  testVal(Int64, "1"),
+		out: testVal(Float64, "2.5"),
+	}, {
+		v1:  testVal(Decimal, "1.25"),
+		v2:  testVal(Decimal, "2.5"),
+		out: testVal(Decimal, "3.75"),
+	}, {
+		v1:  testVal(Decimal, "1"),
+		v2:  testVal(Uint64, "18446744073709551615"),
+		out: testVal(Decimal, "18446744073709551616"),
+	}, {
+		v1:  testVal(VarChar, "1"),
+		v2:  testVal(Int64, "1"),
+		err: "cannot add non-numeric values: VARCHAR, INT64",
+	}}
+	for _, tcase := range testcases {
+		got, err := NullsafeAdd(tcase.v1, tcase.v2)
+		if tcase.err != "" {
+			if err == nil || err.Error() != tcase.err {
+				t.Errorf("NullsafeAdd(%v, %v) error: %v, want %s", tcase.v1, tcase.v2, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NullsafeAdd(%v, %v) error: %v", tcase.v1, tcase.v2, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tcase.out) {
+			t.Errorf("NullsafeAdd(%v, %v): %v, want %v", tcase.v1, tcase.v2, makePretty(got), makePretty(tcase.out))
+		}
+	}
+}
@@ -8,6 +8,8 @@
 package trace
 
 import (
+	"sync/atomic"
+
 	"golang.org/x/net/context"
 )
 
@@ -35,18 +37,18 @@ type Span interface {
 // NewSpan creates a new Span with the currently installed tracing plugin.
 // If no tracing plugin is installed, it returns a fake Span that does nothing.
 func NewSpan(parent Span) Span {
-	return spanFactory.New(parent)
+	return getSpanFactory().New(parent)
 }
 
 // FromContext returns the Span from a Context if present. The bool return
 // value indicates whether a Span was present in the Context.
 func FromContext(ctx context.Context) (Span, bool) {
-	return spanFactory.FromContext(ctx)
+	return getSpanFactory().FromContext(ctx)
 }
 
 // NewContext returns a context based on parent with a new Span value.
 func NewContext(parent context.Context, span Span) context.Context {
-	return spanFactory.NewContext(parent, span)
+	return getSpanFactory().NewContext(parent, span)
 }
 
 // NewSpanFromContext returns a new Span whose parent is the Span from the given
@@ -67,6 +69,46 @@ func CopySpan(parentCtx, spanCtx context.Context) context.Context {
 	return parentCtx
 }
 
+// EncodeContext returns the trace context of the Span from the given Context,
+// encoded to be sent to another process, for instance in the metadata of an
+// RPC. It returns "" if there is no Span, or if the installed tracing plugin
+// cannot propagate traces.
+func EncodeContext(ctx context.Context) string {
+	codec, ok := getSpanFactory().(SpanContextCodec)
+	if !ok {
+		return ""
+	}
+	span, ok := FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return codec.Encode(span)
+}
+
+// DecodeContext returns a context based on parent, with the remote Span
+// encoded in value by EncodeContext. The new Spans created from this context
+// are part of the remote trace. If value cannot be decoded, parent is
+// returned.
+func DecodeContext(parent context.Context, value string) context.Context {
+	codec, ok := getSpanFactory().(SpanContextCodec)
+	if !ok || value == "" {
+		return parent
+	}
+	span, err := codec.Decode(value)
+	if err != nil {
+		return parent
+	}
+	return NewContext(parent, span)
+}
+
+// Flush sends the Spans buffered by the installed tracing plugin, if it
+// buffers any. It should be called before the process exits.
+func Flush() {
+	if flusher, ok := getSpanFactory().(Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // SpanFactory is an interface for creating spans or extracting them from Contexts.
 type SpanFactory interface {
 	New(parent Span) Span
@@ -74,15 +116,49 @@ type SpanFactory interface {
 	NewContext(parent context.Context, span Span) context.Context
 }
 
-var spanFactory SpanFactory = fakeSpanFactory{}
+// SpanContextCodec is implemented by the SpanFactory of the tracing plugins
+// which can propagate traces across processes.
+type SpanContextCodec interface {
+	// Encode returns the trace context of the span as a string.
+	Encode(span Span) string
+	// Decode returns a Span representing the remote span encoded in value.
+	// It is only meant to be the parent of new Spans.
+	Decode(value string) (Span, error)
+}
+
+// Flusher is implemented by the SpanFactory of the tracing plugins which
+// buffer the finished Spans before sending them.
+type Flusher interface {
+	Flush()
+}
+
+// spanFactory holds a spanFactoryHolder with the installed SpanFactory. It
+// is an atomic.Value because the plugins configured by flags can only
+// register their factory once the flags are parsed, while the servers may
+// already be creating Spans.
+var spanFactory atomic.Value
+
+// spanFactoryHolder gives the values stored in spanFactory the same
+// concrete type, as required by atomic.Value.
+type spanFactoryHolder struct {
+	SpanFactory
+}
+
+func init() {
+	RegisterSpanFactory(fakeSpanFactory{})
+}
+
+func getSpanFactory() SpanFactory {
+	return spanFactory.Load().(spanFactoryHolder).SpanFactory
+}
 
-// RegisterSpanFactory should be called by a plugin during init() to install a
-// factory that creates Spans for that plugin's tracing framework. Each call to
-// RegisterSpanFactory will overwrite any previous setting. If no factory is
-// registered, the default fake factory will produce Spans whose methods are all
-// no-ops.
+// RegisterSpanFactory should be called by a plugin to install a factory that
+// creates Spans for that plugin's tracing framework. It is safe to call it
+// while Spans are being created. Each call to RegisterSpanFactory will
+// overwrite any previous setting. If no factory is registered, the default
+// fake factory will produce Spans whose methods are all no-ops.
 func RegisterSpanFactory(sf SpanFactory) {
-	spanFactory = sf
+	spanFactory.Store(spanFactoryHolder{sf})
 }
 
 type fakeSpanFactory struct{}
@@ -5,6 +5,8 @@
 package trace
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -24,3 +26,77 @@ func TestFakeSpan(t *testing.T) {
 	NewContext(ctx, span)
 	CopySpan(ctx, ctx)
 }
+
+// fakeCodecSpanFactory propagates the labels of the spans.
+type fakeCodecSpanFactory struct{}
+
+type fakeCodecSpan struct {
+	fakeSpan
+	label string
+}
+
+type fakeCodecSpanKey struct{}
+
+func (fakeCodecSpanFactory) New(parent Span) Span { return &fakeCodecSpan{} }
+
+func (fakeCodecSpanFactory) FromContext(ctx context.Context) (Span, bool) {
+	span, ok := ctx.Value(fakeCodecSpanKey{}).(Span)
+	return span, ok
+}
+
+func (fakeCodecSpanFactory) NewContext(parent context.Context, span Span) context.Context {
+	return context.WithValue(parent, fakeCodecSpanKey{}, span)
+}
+
+func (fakeCodecSpanFactory) Encode(span Span) string { return span.(*fakeCodecSpan).label }
+
+func (fakeCodecSpanFactory) Decode(value string) (Span, error) {
+	if value == "bad" {
+		return nil, errors.New("bad span")
+	}
+	return &fakeCodecSpan{label: value}, nil
+}
+
+func TestEncodeDecodeContext(t *testing.T) {
+	defer RegisterSpanFactory(fakeSpanFactory{})
+	ctx := context.Background()
+
+	// The fake factory cannot propagate traces.
+	if got := EncodeContext(NewContext(ctx, NewSpan(nil))); got != "" {
+		t.Errorf("EncodeContext with fakeSpanFactory: %q, want empty", got)
+	}
+	if got := DecodeContext(ctx, "span"); got != ctx {
+		t.Errorf("DecodeContext with fakeSpanFactory returned a new context")
+	}
+
+	RegisterSpanFactory(fakeCodecSpanFactory{})
+	if got := EncodeContext(ctx); got != "" {
+		t.Errorf("EncodeContext without span: %q, want empty", got)
+	}
+	remoteCtx := DecodeContext(ctx, "span")
+	if got := EncodeContext(remoteCtx); got != "span" {
+		t.Errorf("EncodeContext(DecodeContext(span)): %q, want span", got)
+	}
+	if got := DecodeContext(ctx, "bad"); got != ctx {
+		t.Errorf("DecodeContext(bad) returned a new context")
+	}
+}
+
+func TestRegisterSpanFactoryConcurrently(t *testing.T) {
+	defer RegisterSpanFactory(fakeSpanFactory{})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			span := NewSpanFromContext(ctx)
+			span.StartLocal("test")
+			FromContext(NewContext(ctx, span))
+			span.Finish()
+		}
+	}()
+	RegisterSpanFactory(fakeCodecSpanFactory{})
+	wg.Wait()
+}
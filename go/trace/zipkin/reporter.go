@@ -0,0 +1,165 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zipkin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/stats"
+)
+
+var (
+	spansReported = stats.NewInt("ZipkinSpansReported")
+	spansDropped  = stats.NewInt("ZipkinSpansDropped")
+)
+
+// Model is a span in the Zipkin v2 JSON format.
+type Model struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint Endpoint          `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+// Endpoint is the Zipkin v2 JSON representation of the process which
+// recorded a span.
+type Endpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// Sender sends a batch of spans, encoded as a JSON array.
+type Sender func(batch []byte) error
+
+// NewHTTPSender returns a Sender posting the batches to a Zipkin
+// collector, for instance "http://localhost:9411/api/v2/spans".
+func NewHTTPSender(url string) Sender {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(batch []byte) error {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(batch))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("zipkin collector %v returned %v", url, resp.Status)
+		}
+		return nil
+	}
+}
+
+// NewWriterSender returns a Sender writing each batch to w, on its own
+// line.
+func NewWriterSender(w io.Writer) Sender {
+	return func(batch []byte) error {
+		_, err := w.Write(append(batch, '\n'))
+		return err
+	}
+}
+
+// NewFileSender returns a Sender appending the batches to a file.
+func NewFileSender(path string) (Sender, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterSender(f), nil
+}
+
+// Reporter buffers the finished spans, and sends them in batches every
+// flushInterval, or when maxPending spans are buffered. The spans
+// reported while the buffer is full are dropped.
+type Reporter struct {
+	sender        Sender
+	flushInterval time.Duration
+	maxPending    int
+
+	// sendMu serializes the sends.
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	pending []*Model
+	full    chan struct{}
+}
+
+// NewReporter returns a Reporter, and starts its flush loop.
+func NewReporter(sender Sender, flushInterval time.Duration, maxPending int) *Reporter {
+	r := &Reporter{
+		sender:        sender,
+		flushInterval: flushInterval,
+		maxPending:    maxPending,
+		full:          make(chan struct{}, 1),
+	}
+	go r.loop()
+	return r
+}
+
+// Report buffers a finished span.
+func (r *Reporter) Report(m *Model) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) >= 2*r.maxPending {
+		spansDropped.Add(1)
+		return
+	}
+	r.pending = append(r.pending, m)
+	if len(r.pending) == r.maxPending {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Reporter) loop() {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.full:
+		}
+		r.Flush()
+	}
+}
+
+// Flush sends the buffered spans.
+func (r *Reporter) Flush() {
+	r.sendMu.Lock()
+	defer r.sendMu.Unlock()
+
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		log.Errorf("Cannot encode %d Zipkin spans: %v", len(batch), err)
+		spansDropped.Add(int64(len(batch)))
+		return
+	}
+	if err := r.sender(data); err != nil {
+		log.Errorf("Cannot send %d Zipkin spans: %v", len(batch), err)
+		spansDropped.Add(int64(len(batch)))
+		return
+	}
+	spansReported.Add(int64(len(batch)))
+}
@@ -0,0 +1,208 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zipkin
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/youtube/vitess/go/trace"
+	"golang.org/x/net/context"
+)
+
+// SpanFactory is a trace.SpanFactory creating Zipkin spans. It also
+// implements trace.SpanContextCodec, with the B3 single header format
+// "<trace id>-<span id>-<sampled>", and trace.Flusher.
+type SpanFactory struct {
+	reporter    *Reporter
+	sampleRate  float64
+	serviceName string
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewSpanFactory returns a SpanFactory reporting its sampled spans to
+// reporter. A new trace is sampled with a probability of sampleRate,
+// and all its spans are sampled, including the ones in other processes.
+func NewSpanFactory(reporter *Reporter, sampleRate float64, serviceName string) *SpanFactory {
+	return &SpanFactory{
+		reporter:    reporter,
+		sampleRate:  sampleRate,
+		serviceName: serviceName,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// newID returns a random non-zero id, and whether it is sampled at
+// the sample rate.
+func (f *SpanFactory) newID() (uint64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := uint64(f.rand.Int63())
+	for id == 0 {
+		id = uint64(f.rand.Int63())
+	}
+	return id, f.rand.Float64() < f.sampleRate
+}
+
+// New is part of the trace.SpanFactory interface. A span without
+// parent starts a new trace.
+func (f *SpanFactory) New(parent trace.Span) trace.Span {
+	id, sampled := f.newID()
+	s := &span{
+		factory: f,
+		id:      id,
+		traceID: id,
+		sampled: sampled,
+	}
+	if p, ok := parent.(*span); ok {
+		s.traceID = p.traceID
+		s.parentID = p.id
+		s.sampled = p.sampled
+	}
+	return s
+}
+
+type spanKey struct{}
+
+// FromContext is part of the trace.SpanFactory interface.
+func (f *SpanFactory) FromContext(ctx context.Context) (trace.Span, bool) {
+	s, ok := ctx.Value(spanKey{}).(*span)
+	return s, ok
+}
+
+// NewContext is part of the trace.SpanFactory interface.
+func (f *SpanFactory) NewContext(parent context.Context, s trace.Span) context.Context {
+	return context.WithValue(parent, spanKey{}, s)
+}
+
+// Encode is part of the trace.SpanContextCodec interface.
+func (f *SpanFactory) Encode(s trace.Span) string {
+	zs, ok := s.(*span)
+	if !ok {
+		return ""
+	}
+	sampled := 0
+	if zs.sampled {
+		sampled = 1
+	}
+	return fmt.Sprintf("%016x-%016x-%d", zs.traceID, zs.id, sampled)
+}
+
+// Decode is part of the trace.SpanContextCodec interface.
+func (f *SpanFactory) Decode(value string) (trace.Span, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid B3 trace context: %v", value)
+	}
+	traceID, err := strconv.ParseUint(parts[0], 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace id in B3 trace context: %v", value)
+	}
+	id, err := strconv.ParseUint(parts[1], 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid span id in B3 trace context: %v", value)
+	}
+	return &span{
+		factory: f,
+		traceID: traceID,
+		id:      id,
+		sampled: parts[2] == "1",
+		remote:  true,
+	}, nil
+}
+
+// Flush is part of the trace.Flusher interface.
+func (f *SpanFactory) Flush() {
+	f.reporter.Flush()
+}
+
+// span is a trace.Span recording a Zipkin span.
+type span struct {
+	factory  *SpanFactory
+	traceID  uint64
+	id       uint64
+	parentID uint64
+	sampled  bool
+	// remote is set for the spans decoded from another process. They
+	// are only the parents of local spans, and are never reported.
+	remote bool
+
+	mu    sync.Mutex
+	name  string
+	kind  string
+	start time.Time
+	tags  map[string]string
+}
+
+func (s *span) startSpan(label, kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = label
+	s.kind = kind
+	s.start = time.Now()
+	s.tags = nil
+}
+
+// StartLocal is part of the trace.Span interface.
+func (s *span) StartLocal(label string) {
+	s.startSpan(label, "")
+}
+
+// StartClient is part of the trace.Span interface.
+func (s *span) StartClient(label string) {
+	s.startSpan(label, "CLIENT")
+}
+
+// StartServer is part of the trace.Span interface.
+func (s *span) StartServer(label string) {
+	s.startSpan(label, "SERVER")
+}
+
+// Annotate is part of the trace.Span interface. The values are
+// recorded as Zipkin tags.
+func (s *span) Annotate(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tags == nil {
+		s.tags = make(map[string]string)
+	}
+	s.tags[key] = fmt.Sprint(value)
+}
+
+// Finish is part of the trace.Span interface. Only the sampled spans
+// are reported.
+func (s *span) Finish() {
+	if s.remote || !s.sampled {
+		return
+	}
+	end := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := &Model{
+		TraceID:       fmt.Sprintf("%016x", s.traceID),
+		ID:            fmt.Sprintf("%016x", s.id),
+		Name:          s.name,
+		Kind:          s.kind,
+		Timestamp:     s.start.UnixNano() / int64(time.Microsecond),
+		Duration:      int64(end.Sub(s.start) / time.Microsecond),
+		LocalEndpoint: Endpoint{ServiceName: s.factory.serviceName},
+	}
+	if len(s.tags) > 0 {
+		m.Tags = make(map[string]string, len(s.tags))
+		for k, v := range s.tags {
+			m.Tags[k] = v
+		}
+	}
+	if s.parentID != 0 {
+		m.ParentID = fmt.Sprintf("%016x", s.parentID)
+	}
+	s.factory.reporter.Report(m)
+}
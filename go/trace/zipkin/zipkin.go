@@ -0,0 +1,65 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package zipkin is a tracing plugin exporting the spans of the trace
+// package in the Zipkin v2 JSON format, which is also accepted by
+// Jaeger. The spans are sent to a Zipkin collector with
+// -zipkin_collector, or appended to a local file with -zipkin_file,
+// one JSON array of spans per line.
+//
+// The trace context is propagated across the gRPC calls in the "b3"
+// metadata, so the spans of vtgate, vttablet and vtctl are part of the
+// same traces. Only a -zipkin_sample_rate fraction of the traces is
+// recorded.
+//
+// Link it into a binary with a plugin_zipkintrace.go file.
+package zipkin
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/trace"
+	"github.com/youtube/vitess/go/vt/servenv"
+)
+
+var (
+	collector     = flag.String("zipkin_collector", "", "URL of the Zipkin collector to send the trace spans to, e.g. http://localhost:9411/api/v2/spans")
+	file          = flag.String("zipkin_file", "", "file to append the trace spans to, as Zipkin JSON, when -zipkin_collector is not set")
+	sampleRate    = flag.Float64("zipkin_sample_rate", 0.001, "fraction of the traces to record, between 0 and 1")
+	serviceName   = flag.String("zipkin_service_name", "", "service name of the trace spans, defaults to the binary name")
+	flushInterval = flag.Duration("zipkin_flush_interval", time.Second, "maximum time the trace spans are buffered before they are sent")
+	maxPending    = flag.Int("zipkin_batch_size", 1000, "number of buffered trace spans which triggers a send")
+)
+
+func init() {
+	// The flags must be parsed. The servers may already be creating
+	// spans, which RegisterSpanFactory allows.
+	servenv.OnRun(func() {
+		var sender Sender
+		switch {
+		case *collector != "":
+			sender = NewHTTPSender(*collector)
+		case *file != "":
+			var err error
+			sender, err = NewFileSender(*file)
+			if err != nil {
+				log.Errorf("Cannot open the Zipkin trace file, tracing is disabled: %v", err)
+				return
+			}
+		default:
+			return
+		}
+		name := *serviceName
+		if name == "" {
+			name = filepath.Base(os.Args[0])
+		}
+		f := NewSpanFactory(NewReporter(sender, *flushInterval, *maxPending), *sampleRate, name)
+		trace.RegisterSpanFactory(f)
+		servenv.OnClose(f.Flush)
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zipkin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/trace"
+	"golang.org/x/net/context"
+)
+
+// recordingSender decodes the sent batches.
+type recordingSender struct {
+	mu    sync.Mutex
+	spans []*Model
+}
+
+func (rs *recordingSender) send(batch []byte) error {
+	var spans []*Model
+	if err := json.Unmarshal(batch, &spans); err != nil {
+		return err
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.spans = append(rs.spans, spans...)
+	return nil
+}
+
+func newTestFactory(sampleRate float64) (*SpanFactory, *recordingSender) {
+	rs := &recordingSender{}
+	return NewSpanFactory(NewReporter(rs.send, time.Hour, 100), sampleRate, "test"), rs
+}
+
+func TestSpans(t *testing.T) {
+	f, rs := newTestFactory(1)
+	root := f.New(nil)
+	root.StartServer("Execute")
+	child := f.New(root)
+	child.StartClient("mysql")
+	child.Annotate("table", "t1")
+	child.Finish()
+	root.Finish()
+	f.Flush()
+
+	if len(rs.spans) != 2 {
+		t.Fatalf("got %d spans, want 2: %v", len(rs.spans), rs.spans)
+	}
+	c, r := rs.spans[0], rs.spans[1]
+	if r.ParentID != "" || r.TraceID != r.ID || r.Name != "Execute" || r.Kind != "SERVER" {
+		t.Errorf("root span: %+v", r)
+	}
+	if c.TraceID != r.TraceID || c.ParentID != r.ID || c.Name != "mysql" || c.Kind != "CLIENT" {
+		t.Errorf("child span: %+v, root: %+v", c, r)
+	}
+	if c.Tags["table"] != "t1" || c.LocalEndpoint.ServiceName != "test" {
+		t.Errorf("child span: %+v", c)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	f, rs := newTestFactory(0)
+	root := f.New(nil)
+	root.StartLocal("root")
+	child := f.New(root)
+	child.StartLocal("child")
+	child.Finish()
+	root.Finish()
+	f.Flush()
+	if len(rs.spans) != 0 {
+		t.Errorf("got %d spans with a 0 sample rate: %v", len(rs.spans), rs.spans)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	f, rs := newTestFactory(1)
+	root := f.New(nil)
+	root.StartClient("vtgate")
+	value := f.Encode(root)
+
+	// In the other process.
+	remote, err := f.Decode(value)
+	if err != nil {
+		t.Fatalf("Decode(%v) failed: %v", value, err)
+	}
+	server := f.New(remote)
+	server.StartServer("vttablet")
+	server.Finish()
+	remote.Finish()
+	root.Finish()
+	f.Flush()
+
+	if len(rs.spans) != 2 {
+		t.Fatalf("got %d spans, want 2, the remote span must not be reported: %v", len(rs.spans), rs.spans)
+	}
+	s, r := rs.spans[0], rs.spans[1]
+	if s.TraceID != r.TraceID || s.ParentID != r.ID {
+		t.Errorf("server span: %+v, want child of %+v", s, r)
+	}
+
+	// A trace which is not sampled stays so.
+	unsampled, err := f.Decode(value[:len(value)-1] + "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = f.New(unsampled)
+	server.StartServer("vttablet")
+	server.Finish()
+	f.Flush()
+	if len(rs.spans) != 2 {
+		t.Errorf("the span of a trace which is not sampled was reported: %v", rs.spans[2:])
+	}
+
+	for _, bad := range []string{"", "1-2", "x-2-1", "1-y-1"} {
+		if _, err := f.Decode(bad); err == nil {
+			t.Errorf("Decode(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestTraceContext(t *testing.T) {
+	f, rs := newTestFactory(1)
+	trace.RegisterSpanFactory(f)
+
+	span := trace.NewSpanFromContext(context.Background())
+	span.StartClient("vtctl")
+	ctx := trace.NewContext(context.Background(), span)
+	remoteCtx := trace.DecodeContext(context.Background(), trace.EncodeContext(ctx))
+	server := trace.NewSpanFromContext(remoteCtx)
+	server.StartServer("vtctld")
+	server.Finish()
+	span.Finish()
+	trace.Flush()
+
+	if len(rs.spans) != 2 || rs.spans[0].ParentID != rs.spans[1].ID {
+		t.Errorf("spans: %+v, want the vtctld span child of the vtctl span", rs.spans)
+	}
+}
+
+func TestSenders(t *testing.T) {
+	var got []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	if err := NewHTTPSender(server.URL)([]byte("[]")); err != nil {
+		t.Errorf("HTTP sender failed: %v", err)
+	}
+	if string(got) != "[]" {
+		t.Errorf("collector received %q, want []", got)
+	}
+
+	buf := &bytes.Buffer{}
+	sender := NewWriterSender(buf)
+	sender([]byte("[1]"))
+	sender([]byte("[2]"))
+	if want := "[1]\n[2]\n"; buf.String() != want {
+		t.Errorf("writer sender wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestReporterFull(t *testing.T) {
+	rs := &recordingSender{}
+	r := NewReporter(rs.send, time.Hour, 2)
+	r.Report(&Model{ID: "1"})
+	r.Report(&Model{ID: "2"})
+	// Reaching the batch size triggers a send.
+	for i := 0; ; i++ {
+		rs.mu.Lock()
+		n := len(rs.spans)
+		rs.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("the full batch was not sent")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
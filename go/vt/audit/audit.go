@@ -0,0 +1,266 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package audit records the privileged operations run on a process,
+// like the queries run as the dba, the schema changes, the reparents
+// and the table ACL changes, with the identity of their caller and
+// their outcome. Unlike the streamlog of the queries, which is only
+// streamed to the subscribers of the debug pages, the audit log is
+// written to a sink, like a rotating JSON-lines file.
+package audit
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/vt/callerid"
+	"github.com/youtube/vitess/go/vt/callinfo"
+	"github.com/youtube/vitess/go/vt/servenv"
+	"golang.org/x/net/context"
+)
+
+// Event is an audited operation.
+type Event struct {
+	// Time is when the operation started.
+	Time time.Time
+	// Duration of the operation, in seconds.
+	Duration float64
+	// Process is the name of the process which ran the operation,
+	// e.g. vttablet or vtctld.
+	Process string
+	// Principal is the effective caller id of the operation, and
+	// Username its immediate caller id, or the user authenticated
+	// by the gRPC authentication plugin.
+	Principal  string
+	Username   string
+	RemoteAddr string
+	// Operation is e.g. ExecuteFetchAsDba, or Query for the queries
+	// of the query service, whose plan is PlanType.
+	Operation string
+	PlanType  string `json:",omitempty"`
+	// Target is what the operation ran on, e.g. a database, or the
+	// keyspace of a vtctl command.
+	Target    string
+	Statement string
+	// Outcome is OK or ERROR, with the error in Error.
+	Outcome string
+	Error   string `json:",omitempty"`
+}
+
+// Sink is where the audit events are written.
+type Sink interface {
+	// Write writes an event. The calls are serialized.
+	Write(ev *Event) error
+	// Close closes the sink. Write is not called after Close.
+	Close() error
+}
+
+// SinkFactory creates a Sink. It is called by Init, after the flags
+// are parsed.
+type SinkFactory func() (Sink, error)
+
+var (
+	sinkName          = flag.String("audit_log_sink", "", "the audit log sink to use, e.g. file. If not set, no audit log is recorded")
+	planTypes         = flag.String("audit_log_plan_types", "DDL,OTHER", "comma separated list of the plan types of the queries recorded in the audit log by vttablet, e.g. DDL,OTHER,PASS_DML")
+	principals        = flag.String("audit_log_principals", "", "comma separated list of the callers whose operations are recorded in the audit log, matching either their effective or their immediate caller id. If not set, the operations of all the callers are recorded")
+	excludePrincipals = flag.String("audit_log_exclude_principals", "", "comma separated list of the callers whose operations are not recorded in the audit log, e.g. automated jobs. Only their immediate caller id, or the user authenticated by the gRPC authentication plugin, is matched, as the effective caller id is not verified")
+
+	sinks = make(map[string]SinkFactory)
+
+	eventCount = stats.NewCounters("AuditLogEvents")
+	errorCount = stats.NewCounters("AuditLogErrors")
+)
+
+// logger is the configured audit log. It is nil if the audit log is
+// disabled.
+type logger struct {
+	process           string
+	planTypes         map[string]bool
+	principals        map[string]bool
+	excludePrincipals map[string]bool
+
+	// mu protects sink, which is set to nil by Close.
+	mu   sync.Mutex
+	sink Sink
+}
+
+var (
+	currentMu sync.Mutex
+	current   *logger
+)
+
+// RegisterSink registers an audit log sink, to be used with
+// -audit_log_sink=<name>. It should be called in an init function.
+func RegisterSink(name string, factory SinkFactory) {
+	if _, ok := sinks[name]; ok {
+		log.Fatalf("audit log sink %v already registered", name)
+	}
+	sinks[name] = factory
+}
+
+// Init creates the sink selected by -audit_log_sink, if any. It must
+// be called after the flags are parsed.
+func Init() error {
+	if *sinkName == "" {
+		return nil
+	}
+	factory, ok := sinks[*sinkName]
+	if !ok {
+		return fmt.Errorf("unknown audit log sink %v", *sinkName)
+	}
+	sink, err := factory()
+	if err != nil {
+		return fmt.Errorf("cannot create the audit log sink %v: %v", *sinkName, err)
+	}
+	setLogger(newLogger(filepath.Base(os.Args[0]), sink, *planTypes, *principals, *excludePrincipals))
+	log.Infof("Recording the audit log with the %v sink", *sinkName)
+	return nil
+}
+
+// Close closes the sink of the audit log. The operations which start
+// after Close are not recorded.
+func Close() {
+	l := setLogger(nil)
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.sink.Close(); err != nil {
+		log.Errorf("Cannot close the audit log sink: %v", err)
+	}
+	l.sink = nil
+}
+
+func newLogger(process string, sink Sink, planTypes, principals, excludePrincipals string) *logger {
+	return &logger{
+		process:           process,
+		planTypes:         parseList(planTypes),
+		principals:        parseList(principals),
+		excludePrincipals: parseList(excludePrincipals),
+		sink:              sink,
+	}
+}
+
+// setLogger replaces the current logger and returns the previous one.
+func setLogger(l *logger) *logger {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	previous := current
+	current = l
+	return previous
+}
+
+func getLogger() *logger {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	return current
+}
+
+func parseList(list string) map[string]bool {
+	values := make(map[string]bool)
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values[value] = true
+		}
+	}
+	return values
+}
+
+// Start returns the Event of an operation, whose caller is taken from
+// ctx. The operation must call Finish on it when it is done. Start
+// returns nil if the audit log is disabled, or if the operations of
+// the caller are not recorded. Finish can be called on nil.
+func Start(ctx context.Context, operation, target, statement string) *Event {
+	return getLogger().start(ctx, operation, "", target, statement)
+}
+
+// StartQuery is like Start, for a query of the query service. It
+// returns nil if the plan type of the query is not recorded.
+func StartQuery(ctx context.Context, planType, target, statement string) *Event {
+	l := getLogger()
+	if l == nil || !l.planTypes[planType] {
+		return nil
+	}
+	return l.start(ctx, "Query", planType, target, statement)
+}
+
+func (l *logger) start(ctx context.Context, operation, planType, target, statement string) *Event {
+	if l == nil {
+		return nil
+	}
+	ev := &Event{
+		Time:      time.Now(),
+		Process:   l.process,
+		Principal: callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(ctx)),
+		Username:  callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx)),
+		Operation: operation,
+		PlanType:  planType,
+		Target:    target,
+		Statement: statement,
+	}
+	if ev.Username == "" {
+		ev.Username, _ = servenv.AuthenticatedUser(ctx)
+	}
+	if ci, ok := callinfo.FromContext(ctx); ok {
+		ev.RemoteAddr = ci.RemoteAddr()
+	}
+	if !l.records(ev) {
+		return nil
+	}
+	return ev
+}
+
+// records returns true if the operations of the caller of ev are
+// recorded. The principal is set by the client, and is not verified:
+// the operations are recorded if either the principal or the username
+// is in the principals, and only the username, which is verified, can
+// exclude them.
+func (l *logger) records(ev *Event) bool {
+	if ev.Username != "" && l.excludePrincipals[ev.Username] {
+		return false
+	}
+	if len(l.principals) == 0 {
+		return true
+	}
+	return (ev.Principal != "" && l.principals[ev.Principal]) || (ev.Username != "" && l.principals[ev.Username])
+}
+
+// Finish records the outcome of the operation of ev in the audit log.
+func (ev *Event) Finish(err error) {
+	if ev == nil {
+		return
+	}
+	ev.Duration = time.Now().Sub(ev.Time).Seconds()
+	ev.Outcome = "OK"
+	if err != nil {
+		ev.Outcome = "ERROR"
+		ev.Error = err.Error()
+	}
+	getLogger().write(ev)
+}
+
+func (l *logger) write(ev *Event) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sink == nil {
+		return
+	}
+	if err := l.sink.Write(ev); err != nil {
+		errorCount.Add(ev.Operation, 1)
+		log.Errorf("Cannot write the audit log event %+v: %v", ev, err)
+		return
+	}
+	eventCount.Add(ev.Operation, 1)
+}
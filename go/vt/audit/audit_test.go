@@ -0,0 +1,102 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/youtube/vitess/go/vt/callerid"
+	"golang.org/x/net/context"
+)
+
+type memorySink struct {
+	events []*Event
+	closed bool
+}
+
+func (ms *memorySink) Write(ev *Event) error {
+	ms.events = append(ms.events, ev)
+	return nil
+}
+
+func (ms *memorySink) Close() error {
+	ms.closed = true
+	return nil
+}
+
+func callerContext(principal, username string) context.Context {
+	return callerid.NewContext(context.Background(),
+		callerid.NewEffectiveCallerID(principal, "", ""),
+		callerid.NewImmediateCallerID(username))
+}
+
+func TestAudit(t *testing.T) {
+	// Disabled: nothing is recorded, and Finish accepts nil.
+	Start(context.Background(), "ExecuteFetchAsDba", "vt_ks", "drop table t").Finish(nil)
+
+	sink := &memorySink{}
+	setLogger(newLogger("vttablet", sink, "DDL, OTHER", "", "robot"))
+	defer Close()
+
+	ctx := callerContext("alice", "vtgate")
+	Start(ctx, "ExecuteFetchAsDba", "vt_ks", "drop table t").Finish(nil)
+	StartQuery(ctx, "DDL", "vt_ks.t", "alter table t add c int").Finish(errors.New("duplicate column"))
+	StartQuery(ctx, "PASS_SELECT", "vt_ks.t", "select * from t").Finish(nil)
+	// The principal is not verified, so it cannot exclude the
+	// operation: only the username can.
+	Start(callerContext("robot", "vtgate"), "ApplySchema", "vt_ks", "create table u").Finish(nil)
+	Start(callerContext("", "robot"), "ApplySchema", "vt_ks", "create table v").Finish(nil)
+	Start(callerContext("alice", "robot"), "ApplySchema", "vt_ks", "create table w").Finish(nil)
+
+	if len(sink.events) != 3 {
+		t.Fatalf("got %d events, want 3: %+v", len(sink.events), sink.events)
+	}
+	ev := sink.events[0]
+	if ev.Process != "vttablet" || ev.Principal != "alice" || ev.Username != "vtgate" || ev.Operation != "ExecuteFetchAsDba" || ev.Target != "vt_ks" || ev.Statement != "drop table t" || ev.Outcome != "OK" || ev.Error != "" {
+		t.Errorf("got event %+v", ev)
+	}
+	ev = sink.events[1]
+	if ev.Operation != "Query" || ev.PlanType != "DDL" || ev.Outcome != "ERROR" || ev.Error != "duplicate column" {
+		t.Errorf("got event %+v", ev)
+	}
+	ev = sink.events[2]
+	if ev.Principal != "robot" || ev.Username != "vtgate" || ev.Statement != "create table u" {
+		t.Errorf("got event %+v", ev)
+	}
+
+	Close()
+	if !sink.closed {
+		t.Errorf("sink not closed")
+	}
+	Start(ctx, "ExecuteFetchAsDba", "vt_ks", "drop table t").Finish(nil)
+	if len(sink.events) != 3 {
+		t.Errorf("got %d events after Close, want 3", len(sink.events))
+	}
+}
+
+func TestAuditPrincipals(t *testing.T) {
+	sink := &memorySink{}
+	setLogger(newLogger("vtctld", sink, "", "alice,bob", ""))
+	defer Close()
+
+	for _, principal := range []string{"alice", "carol", "bob"} {
+		Start(callerContext(principal, ""), "PlannedReparentShard", "ks/0", "PlannedReparentShard ks/0").Finish(nil)
+	}
+	// Either identity can match.
+	Start(callerContext("dave", "alice"), "PlannedReparentShard", "ks/0", "PlannedReparentShard ks/0").Finish(nil)
+	Start(callerContext("dave", "erin"), "PlannedReparentShard", "ks/0", "PlannedReparentShard ks/0").Finish(nil)
+	// No plan type is recorded.
+	StartQuery(callerContext("alice", ""), "DDL", "vt_ks.t", "alter table t add c int").Finish(nil)
+
+	var got []string
+	for _, ev := range sink.events {
+		got = append(got, ev.Principal+"/"+ev.Username)
+	}
+	if want := []string{"alice/", "bob/", "dave/alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded the events of %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// This file contains the file sink, which writes the audit events to
+// a file, one JSON object per line. When the file reaches its maximum
+// size, it is rotated: <file> is renamed to <file>.1, <file>.1 to
+// <file>.2, and so on, up to the maximum number of backups.
+
+var (
+	fileName       = flag.String("audit_log_file", "", "file of the audit log, for the file sink")
+	fileMaxSize    = flag.Int64("audit_log_file_max_size", 100*1024*1024, "size in bytes of the audit log file above which it is rotated, for the file sink")
+	fileMaxBackups = flag.Int("audit_log_file_max_backups", 10, "number of rotated audit log files to keep, for the file sink")
+)
+
+func init() {
+	RegisterSink("file", func() (Sink, error) {
+		if *fileName == "" {
+			return nil, fmt.Errorf("the audit_log_file flag is required by the file sink")
+		}
+		return newFileSink(*fileName, *fileMaxSize, *fileMaxBackups)
+	})
+}
+
+type fileSink struct {
+	name       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+func newFileSink(name string, maxSize int64, maxBackups int) (*fileSink, error) {
+	fs := &fileSink{
+		name:       name,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := fs.open(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// open opens the file, appending to it if it exists.
+func (fs *fileSink) open() error {
+	file, err := os.OpenFile(fs.name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	fs.file = file
+	fs.size = fi.Size()
+	return nil
+}
+
+// Write is part of the Sink interface. The events are written without
+// buffering, so they are not lost if the process dies. If the rotation
+// fails, the event is still written, and the error returned.
+func (fs *fileSink) Write(ev *Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	var rotateErr error
+	if fs.file != nil && fs.size > 0 && fs.size+int64(len(data)) > fs.maxSize {
+		rotateErr = fs.rotate()
+	}
+	if fs.file == nil {
+		// The file could not be reopened by the last rotation.
+		if err := fs.open(); err != nil {
+			return err
+		}
+	}
+	n, err := fs.file.Write(data)
+	fs.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return rotateErr
+}
+
+// rotate closes the file, shifts the backups, and opens a new file.
+// Whatever fails, the file is reopened, so that the next events are
+// still written, and the rotation retried. fs.file is nil if it cannot
+// be reopened.
+func (fs *fileSink) rotate() error {
+	err := fs.file.Close()
+	if err == nil {
+		err = fs.shiftBackups()
+	}
+	fs.file = nil
+	if openErr := fs.open(); openErr != nil && err == nil {
+		err = openErr
+	}
+	return err
+}
+
+// shiftBackups renames the file and its backups, or removes the file
+// if there are no backups.
+func (fs *fileSink) shiftBackups() error {
+	if fs.maxBackups == 0 {
+		return os.Remove(fs.name)
+	}
+	for i := fs.maxBackups - 1; i > 0; i-- {
+		err := os.Rename(fs.backupName(i), fs.backupName(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(fs.name, fs.backupName(1))
+}
+
+func (fs *fileSink) backupName(i int) string {
+	return fmt.Sprintf("%v.%v", fs.name, i)
+}
+
+// Close is part of the Sink interface.
+func (fs *fileSink) Close() error {
+	if fs.file == nil {
+		return nil
+	}
+	return fs.file.Close()
+}
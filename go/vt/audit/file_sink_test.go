@@ -0,0 +1,117 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+// readEvents returns the statements of the events of an audit log file.
+func readEvents(t *testing.T, name string) []string {
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+	var statements []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		ev := &Event{}
+		if err := json.Unmarshal(scanner.Bytes(), ev); err != nil {
+			t.Fatalf("invalid event %q: %v", scanner.Text(), err)
+		}
+		statements = append(statements, ev.Statement)
+	}
+	return statements
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := path.Join(dir, "audit.log")
+
+	// Each event is about 150 bytes: two fit in a file.
+	fs, err := newFileSink(name, 400, 2)
+	if err != nil {
+		t.Fatalf("newFileSink failed: %v", err)
+	}
+	for _, statement := range []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7"} {
+		if err := fs.Write(&Event{Operation: "ExecuteFetchAsDba", Statement: statement}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want := map[string][]string{
+		name:        {"s7"},
+		name + ".1": {"s5", "s6"},
+		name + ".2": {"s3", "s4"},
+	}
+	for file, statements := range want {
+		got := readEvents(t, file)
+		if len(got) != len(statements) || (len(got) > 0 && got[0] != statements[0]) {
+			t.Errorf("%v: got %v, want %v", file, got, statements)
+		}
+	}
+	if _, err := os.Stat(name + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%v.3 exists: %v", name, err)
+	}
+
+	// Reopening appends to the file.
+	fs, err = newFileSink(name, 400, 2)
+	if err != nil {
+		t.Fatalf("newFileSink failed: %v", err)
+	}
+	if err := fs.Write(&Event{Operation: "ExecuteFetchAsDba", Statement: "s8"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	fs.Close()
+	if got := readEvents(t, name); len(got) != 2 || got[1] != "s8" {
+		t.Errorf("%v: got %v, want [s7 s8]", name, got)
+	}
+
+	// When the rotation fails, here because <file>.1 is a directory,
+	// the events are still written to the file.
+	fs, err = newFileSink(name, 400, 1)
+	if err != nil {
+		t.Fatalf("newFileSink failed: %v", err)
+	}
+	if err := os.Remove(name + ".1"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := os.MkdirAll(path.Join(name+".1", "dir"), 0700); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := fs.Write(&Event{Operation: "ExecuteFetchAsDba", Statement: "s9"}); err == nil {
+		t.Errorf("Write with a failed rotation: got no error")
+	}
+	if got := readEvents(t, name); len(got) != 3 || got[2] != "s9" {
+		t.Errorf("%v: got %v, want [s7 s8 s9]", name, got)
+	}
+	// The rotation is retried.
+	if err := os.RemoveAll(name + ".1"); err != nil {
+		t.Fatalf("RemoveAll failed: %v", err)
+	}
+	if err := fs.Write(&Event{Operation: "ExecuteFetchAsDba", Statement: "s10"}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	fs.Close()
+	if got := readEvents(t, name); len(got) != 1 || got[0] != "s10" {
+		t.Errorf("%v: got %v, want [s10]", name, got)
+	}
+	if got := readEvents(t, name+".1"); len(got) != 3 {
+		t.Errorf("%v.1: got %v, want [s7 s8 s9]", name, got)
+	}
+}
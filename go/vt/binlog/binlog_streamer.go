@@ -175,6 +175,10 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan replication.
 	var autocommit = true
 	var err error
 
+	// tableMaps contains the last TABLE_MAP_EVENT of each table id.
+	// The tables of the other databases are nil.
+	tableMaps := make(map[uint64]*tableMap)
+
 	// A begin can be triggered either by a BEGIN query, or by a GTID_EVENT.
 	begin := func() {
 		if statements != nil {
@@ -344,6 +348,52 @@ func (bls *Streamer) parseEvents(ctx context.Context, events <-chan replication.
 					}
 				}
 			}
+		case ev.IsTableMap(): // TABLE_MAP_EVENT
+			tm, err := ev.TableMap(format)
+			if err != nil {
+				return pos, fmt.Errorf("can't parse TABLE_MAP_EVENT: %v, event data: %#v", err, ev)
+			}
+			tableID := ev.TableID(format)
+			if tm.Database != "" && tm.Database != bls.dbname {
+				// Skip cross-db rows.
+				tableMaps[tableID] = nil
+				continue
+			}
+			ti, err := bls.getTableMap(ctx, tm, tableMaps[tableID])
+			if err != nil {
+				return pos, err
+			}
+			tableMaps[tableID] = ti
+		case ev.IsWriteRows(), ev.IsUpdateRows(), ev.IsDeleteRows(): // {WRITE,UPDATE,DELETE}_ROWS_EVENT
+			// The rows are converted to DML statements, so that
+			// binlog_format=ROW can be used.
+			tableID := ev.TableID(format)
+			ti, ok := tableMaps[tableID]
+			if !ok {
+				return pos, fmt.Errorf("got a ROWS_EVENT for table id %v without its TABLE_MAP_EVENT, event data: %#v", tableID, ev)
+			}
+			if ti == nil {
+				continue
+			}
+			rows, err := ev.Rows(format, ti.tm)
+			if err != nil {
+				return pos, fmt.Errorf("can't parse ROWS_EVENT: %v, event data: %#v", err, ev)
+			}
+			statements = append(statements, &binlogdatapb.BinlogTransaction_Statement{
+				Category: binlogdatapb.BinlogTransaction_Statement_BL_SET,
+				Sql:      []byte(fmt.Sprintf("SET TIMESTAMP=%d", ev.Timestamp())),
+			})
+			for _, sql := range ti.rowsStatements(ev, rows) {
+				statements = append(statements, &binlogdatapb.BinlogTransaction_Statement{
+					Category: binlogdatapb.BinlogTransaction_Statement_BL_DML,
+					Sql:      sql,
+				})
+			}
+			if autocommit {
+				if err = commit(ev.Timestamp()); err != nil {
+					return pos, err
+				}
+			}
 		case ev.IsPreviousGTIDs(): // PREVIOUS_GTIDS_EVENT
 			// MySQL 5.6 only: The Binlogs contain an
 			// event that gives us all the previously
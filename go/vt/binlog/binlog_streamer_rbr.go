@@ -0,0 +1,260 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package binlog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/mysqlctl"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// tableMap is the TABLE_MAP_EVENT of a table of the streamed
+// database, with the names and types of its columns, and the indexes
+// of its primary key columns, which the event doesn't contain.
+type tableMap struct {
+	tm        *replication.TableMap
+	fields    []*querypb.Field
+	pkColumns []int
+}
+
+// getTableMap returns the tableMap of tm. It reuses previous if it is
+// for the same table, with the same column types. Otherwise, it reads
+// the columns of the table from mysqld. Their number and types must
+// match the event: the rows of a table altered since then cannot be
+// streamed.
+func (bls *Streamer) getTableMap(ctx context.Context, tm *replication.TableMap, previous *tableMap) (*tableMap, error) {
+	if previous != nil && previous.tm.Name == tm.Name && bytes.Equal(previous.tm.Types, tm.Types) && reflect.DeepEqual(previous.tm.Metadata, tm.Metadata) {
+		previous.tm = tm
+		return previous, nil
+	}
+
+	qr, err := bls.mysqld.FetchSuperQuery(ctx, fmt.Sprintf("SELECT * FROM %v.%v WHERE 1 != 1", quoteID(bls.dbname), quoteID(tm.Name)))
+	if err != nil {
+		return nil, fmt.Errorf("can't get the columns of table %v: %v", tm.Name, err)
+	}
+	if len(qr.Fields) != len(tm.Types) {
+		return nil, fmt.Errorf("table %v has %v columns, but its TABLE_MAP_EVENT has %v", tm.Name, len(qr.Fields), len(tm.Types))
+	}
+	for i, field := range qr.Fields {
+		var metadata uint16
+		if i < len(tm.Metadata) {
+			metadata = tm.Metadata[i]
+		}
+		if !mysqlctl.ColumnTypeMatches(tm.Types[i], metadata, field.Type) {
+			return nil, fmt.Errorf("column %v of table %v has type %v, but its TABLE_MAP_EVENT has type %v", field.Name, tm.Name, field.Type, tm.Types[i])
+		}
+	}
+	ti := &tableMap{
+		tm:     tm,
+		fields: qr.Fields,
+	}
+
+	qr, err = bls.mysqld.FetchSuperQuery(ctx, fmt.Sprintf("SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = %v AND table_name = %v AND constraint_name = 'PRIMARY' ORDER BY ordinal_position", encodeString(bls.dbname), encodeString(tm.Name)))
+	if err != nil {
+		return nil, fmt.Errorf("can't get the primary key of table %v: %v", tm.Name, err)
+	}
+	for _, row := range qr.Rows {
+		name := row[0].String()
+		for i, field := range ti.fields {
+			if strings.EqualFold(field.Name, name) {
+				ti.pkColumns = append(ti.pkColumns, i)
+				break
+			}
+		}
+	}
+	return ti, nil
+}
+
+// rowsStatements returns the DML statements equivalent to the rows of
+// a {WRITE,UPDATE,DELETE}_ROWS_EVENT: one per row. Like the statements
+// executed by vttablet, they have a _stream comment with their primary
+// key values, if the row images contain them.
+func (ti *tableMap) rowsStatements(ev replication.BinlogEvent, rows replication.Rows) [][]byte {
+	statements := make([][]byte, 0, len(rows.Rows))
+	for _, row := range rows.Rows {
+		buf := &bytes.Buffer{}
+		var pkValues [][]sqltypes.Value
+		switch {
+		case ev.IsWriteRows():
+			data := ti.values(rows.DataColumns, row.Data)
+			fmt.Fprintf(buf, "INSERT INTO %v(", quoteID(ti.tm.Name))
+			ti.writeColumns(buf, data, ", ", func(i int) {
+				buf.WriteString(quoteID(ti.fields[i].Name))
+			})
+			buf.WriteString(") VALUES (")
+			ti.writeColumns(buf, data, ", ", func(i int) {
+				data[i].EncodeSQL(buf)
+			})
+			buf.WriteString(")")
+			pkValues = ti.appendPKValues(pkValues, data)
+		case ev.IsUpdateRows():
+			identify := ti.values(rows.IdentifyColumns, row.Identify)
+			data := ti.values(rows.DataColumns, row.Data)
+			fmt.Fprintf(buf, "UPDATE %v SET ", quoteID(ti.tm.Name))
+			ti.writeColumns(buf, data, ", ", func(i int) {
+				fmt.Fprintf(buf, "%v = ", quoteID(ti.fields[i].Name))
+				data[i].EncodeSQL(buf)
+			})
+			ti.writeWhere(buf, identify)
+			pkValues = ti.appendPKValues(pkValues, identify)
+			if newPK := ti.appendPKValues(nil, data); len(pkValues) == 1 && len(newPK) == 1 && !equalValues(pkValues[0], newPK[0]) {
+				pkValues = append(pkValues, newPK[0])
+			}
+		case ev.IsDeleteRows():
+			identify := ti.values(rows.IdentifyColumns, row.Identify)
+			fmt.Fprintf(buf, "DELETE FROM %v", quoteID(ti.tm.Name))
+			ti.writeWhere(buf, identify)
+			pkValues = ti.appendPKValues(pkValues, identify)
+		}
+		if len(pkValues) != 0 {
+			ti.writeStreamComment(buf, pkValues)
+		}
+		statements = append(statements, buf.Bytes())
+	}
+	return statements
+}
+
+// values returns the values of a row image, indexed by column. The
+// columns which are not in the image are nil.
+func (ti *tableMap) values(columns replication.Bitmap, image []sqltypes.Value) []*sqltypes.Value {
+	values := make([]*sqltypes.Value, len(ti.fields))
+	j := 0
+	for i := range ti.fields {
+		if !columns.Bit(i) {
+			continue
+		}
+		v := columnValue(ti.fields[i], image[j])
+		values[i] = &v
+		j++
+	}
+	return values
+}
+
+// columnValue converts a value decoded from a row image to the type of
+// its column. The images don't tell the signedness of the integers, or
+// whether a string is text or binary.
+func columnValue(field *querypb.Field, v sqltypes.Value) sqltypes.Value {
+	if v.IsNull() {
+		return v
+	}
+	switch {
+	case sqltypes.IsUnsigned(field.Type) && sqltypes.IsIntegral(v.Type()):
+		i, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return v
+		}
+		u := uint64(i)
+		switch field.Type {
+		case sqltypes.Uint8:
+			u &= 1<<8 - 1
+		case sqltypes.Uint16:
+			u &= 1<<16 - 1
+		case sqltypes.Uint24:
+			u &= 1<<24 - 1
+		case sqltypes.Uint32:
+			u &= 1<<32 - 1
+		}
+		return sqltypes.MakeTrusted(field.Type, strconv.AppendUint(nil, u, 10))
+	case sqltypes.IsText(field.Type) || sqltypes.IsBinary(field.Type):
+		return sqltypes.MakeTrusted(field.Type, v.Raw())
+	}
+	return v
+}
+
+// writeColumns calls write for each column in values, separated by sep.
+func (ti *tableMap) writeColumns(buf *bytes.Buffer, values []*sqltypes.Value, sep string, write func(i int)) {
+	first := true
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		if !first {
+			buf.WriteString(sep)
+		}
+		first = false
+		write(i)
+	}
+}
+
+// writeWhere writes the WHERE clause which identifies a row.
+func (ti *tableMap) writeWhere(buf *bytes.Buffer, identify []*sqltypes.Value) {
+	buf.WriteString(" WHERE ")
+	ti.writeColumns(buf, identify, " AND ", func(i int) {
+		buf.WriteString(quoteID(ti.fields[i].Name))
+		if identify[i].IsNull() {
+			buf.WriteString(" IS NULL")
+			return
+		}
+		buf.WriteString(" = ")
+		identify[i].EncodeSQL(buf)
+	})
+}
+
+// appendPKValues appends the primary key values of values to pkValues,
+// if they all are in the image.
+func (ti *tableMap) appendPKValues(pkValues [][]sqltypes.Value, values []*sqltypes.Value) [][]sqltypes.Value {
+	if len(ti.pkColumns) == 0 {
+		return pkValues
+	}
+	pk := make([]sqltypes.Value, 0, len(ti.pkColumns))
+	for _, i := range ti.pkColumns {
+		if values[i] == nil {
+			return pkValues
+		}
+		pk = append(pk, *values[i])
+	}
+	return append(pkValues, pk)
+}
+
+// writeStreamComment writes the _stream comment parsed by the
+// EventStreamer and the TablesFilterFunc, in the format of vttablet:
+// /* _stream table (pk1 pk2 ) (v1 v2 ) (...); */
+func (ti *tableMap) writeStreamComment(buf *bytes.Buffer, pkValues [][]sqltypes.Value) {
+	fmt.Fprintf(buf, " %s%s (", streamComment, ti.tm.Name)
+	for _, i := range ti.pkColumns {
+		buf.WriteString(ti.fields[i].Name)
+		buf.WriteString(" ")
+	}
+	buf.WriteString(")")
+	for _, pk := range pkValues {
+		buf.WriteString(" (")
+		for _, v := range pk {
+			v.EncodeASCII(buf)
+			buf.WriteString(" ")
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString("; */")
+}
+
+func equalValues(a, b []sqltypes.Value) bool {
+	for i := range a {
+		if !bytes.Equal(a[i].Raw(), b[i].Raw()) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteID quotes a MySQL identifier.
+func quoteID(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+func encodeString(s string) string {
+	buf := &bytes.Buffer{}
+	sqltypes.MakeString([]byte(s)).EncodeSQL(buf)
+	return buf.String()
+}
@@ -15,6 +15,7 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/youtube/vitess/go/sqltypes"
 	"github.com/youtube/vitess/go/vt/mysqlctl"
 	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
 
@@ -34,6 +35,10 @@ func (fakeEvent) IsRotate() bool                        { return false }
 func (fakeEvent) IsIntVar() bool                        { return false }
 func (fakeEvent) IsRand() bool                          { return false }
 func (fakeEvent) IsPreviousGTIDs() bool                 { return false }
+func (fakeEvent) IsTableMap() bool                      { return false }
+func (fakeEvent) IsWriteRows() bool                     { return false }
+func (fakeEvent) IsUpdateRows() bool                    { return false }
+func (fakeEvent) IsDeleteRows() bool                    { return false }
 func (fakeEvent) HasGTID(replication.BinlogFormat) bool { return true }
 func (fakeEvent) Timestamp() uint32                     { return 1407805592 }
 func (fakeEvent) Format() (replication.BinlogFormat, error) {
@@ -46,6 +51,13 @@ func (fakeEvent) PreviousGTIDs(replication.BinlogFormat) (replication.Position,
 	return replication.Position{}, errors.New("not a PreviousGTIDs")
 }
 func (fakeEvent) IsBeginGTID(replication.BinlogFormat) bool { return false }
+func (fakeEvent) TableID(replication.BinlogFormat) uint64   { return 0 }
+func (fakeEvent) TableMap(replication.BinlogFormat) (*replication.TableMap, error) {
+	return nil, errors.New("not a table map")
+}
+func (fakeEvent) Rows(replication.BinlogFormat, *replication.TableMap) (replication.Rows, error) {
+	return replication.Rows{}, errors.New("not a rows")
+}
 func (fakeEvent) Query(replication.BinlogFormat) (replication.Query, error) {
 	return replication.Query{}, errors.New("not a query")
 }
@@ -144,6 +156,39 @@ func (ev invalidIntVarEvent) StripChecksum(replication.BinlogFormat) (replicatio
 	return ev, nil, nil
 }
 
+type tableMapEvent struct {
+	fakeEvent
+	id uint64
+	tm *replication.TableMap
+}
+
+func (tableMapEvent) IsTableMap() bool                           { return true }
+func (ev tableMapEvent) TableID(replication.BinlogFormat) uint64 { return ev.id }
+func (ev tableMapEvent) TableMap(replication.BinlogFormat) (*replication.TableMap, error) {
+	return ev.tm, nil
+}
+func (ev tableMapEvent) StripChecksum(replication.BinlogFormat) (replication.BinlogEvent, []byte, error) {
+	return ev, nil, nil
+}
+
+type rowsEvent struct {
+	fakeEvent
+	write, update, delete bool
+	id                    uint64
+	rows                  replication.Rows
+}
+
+func (ev rowsEvent) IsWriteRows() bool                       { return ev.write }
+func (ev rowsEvent) IsUpdateRows() bool                      { return ev.update }
+func (ev rowsEvent) IsDeleteRows() bool                      { return ev.delete }
+func (ev rowsEvent) TableID(replication.BinlogFormat) uint64 { return ev.id }
+func (ev rowsEvent) Rows(replication.BinlogFormat, *replication.TableMap) (replication.Rows, error) {
+	return ev.rows, nil
+}
+func (ev rowsEvent) StripChecksum(replication.BinlogFormat) (replication.BinlogEvent, []byte, error) {
+	return ev, nil, nil
+}
+
 // sample MariaDB event data
 var (
 	mariadbRotateEvent         = mysqlctl.NewMariadbBinlogEvent([]byte{0x0, 0x0, 0x0, 0x0, 0x4, 0x88, 0xf3, 0x0, 0x0, 0x33, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x20, 0x0, 0x4, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x76, 0x74, 0x2d, 0x30, 0x30, 0x30, 0x30, 0x30, 0x36, 0x32, 0x33, 0x34, 0x34, 0x2d, 0x62, 0x69, 0x6e, 0x2e, 0x30, 0x30, 0x30, 0x30, 0x30, 0x31})
@@ -1046,6 +1091,138 @@ func TestStreamerParseEventsMariadbStandaloneGTID(t *testing.T) {
 	}
 }
 
+func TestStreamerParseEventsRBR(t *testing.T) {
+	columns := replication.NewBitmap([]byte{0x03}, 2)
+	input := []replication.BinlogEvent{
+		rotateEvent{},
+		formatEvent{},
+		queryEvent{query: replication.Query{
+			Database: "vt_test_keyspace",
+			SQL:      "BEGIN"}},
+		tableMapEvent{id: 1, tm: &replication.TableMap{
+			Database: "vt_test_keyspace",
+			Name:     "vt_a",
+			Types:    []byte{3, 15},
+		}},
+		rowsEvent{write: true, id: 1, rows: replication.Rows{
+			DataColumns: columns,
+			Rows: []replication.Row{{
+				Data: []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Int32, []byte("-1")), sqltypes.MakeTrusted(sqltypes.VarBinary, []byte("abc"))},
+			}},
+		}},
+		rowsEvent{update: true, id: 1, rows: replication.Rows{
+			IdentifyColumns: columns,
+			DataColumns:     columns,
+			Rows: []replication.Row{{
+				Identify: []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")), sqltypes.MakeTrusted(sqltypes.VarBinary, []byte("abc"))},
+				Data:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Int32, []byte("2")), sqltypes.MakeTrusted(sqltypes.VarBinary, []byte("abc"))},
+			}},
+		}},
+		rowsEvent{delete: true, id: 1, rows: replication.Rows{
+			IdentifyColumns: columns,
+			Rows: []replication.Row{{
+				Identify: []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Int32, []byte("2")), sqltypes.NULL},
+			}},
+		}},
+		tableMapEvent{id: 2, tm: &replication.TableMap{
+			Database: "other",
+			Name:     "vt_b",
+			Types:    []byte{3},
+		}},
+		rowsEvent{delete: true, id: 2},
+		xidEvent{},
+	}
+
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SELECT * FROM `vt_test_keyspace`.`vt_a` WHERE 1 != 1": {
+			Fields: []*querypb.Field{{Name: "id", Type: sqltypes.Uint32}, {Name: "name", Type: sqltypes.VarChar}},
+		},
+		"SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = 'vt_test_keyspace' AND table_name = 'vt_a' AND constraint_name = 'PRIMARY' ORDER BY ordinal_position": {
+			Rows: [][]sqltypes.Value{{sqltypes.MakeString([]byte("id"))}},
+		},
+	}
+
+	events := make(chan replication.BinlogEvent)
+	want := []*binlogdatapb.BinlogTransaction_Statement{
+		{Category: binlogdatapb.BinlogTransaction_Statement_BL_SET, Sql: []byte("SET TIMESTAMP=1407805592")},
+		{Category: binlogdatapb.BinlogTransaction_Statement_BL_DML, Sql: []byte("INSERT INTO `vt_a`(`id`, `name`) VALUES (4294967295, 'abc') /* _stream vt_a (id ) (4294967295 ); */")},
+		{Category: binlogdatapb.BinlogTransaction_Statement_BL_SET, Sql: []byte("SET TIMESTAMP=1407805592")},
+		{Category: binlogdatapb.BinlogTransaction_Statement_BL_DML, Sql: []byte("UPDATE `vt_a` SET `id` = 2, `name` = 'abc' WHERE `id` = 1 AND `name` = 'abc' /* _stream vt_a (id ) (1 ) (2 ); */")},
+		{Category: binlogdatapb.BinlogTransaction_Statement_BL_SET, Sql: []byte("SET TIMESTAMP=1407805592")},
+		{Category: binlogdatapb.BinlogTransaction_Statement_BL_DML, Sql: []byte("DELETE FROM `vt_a` WHERE `id` = 2 AND `name` IS NULL /* _stream vt_a (id ) (2 ); */")},
+	}
+	var got []binlogdatapb.BinlogTransaction
+	sendTransaction := func(trans *binlogdatapb.BinlogTransaction) error {
+		got = append(got, *trans)
+		return nil
+	}
+	bls := NewStreamer("vt_test_keyspace", mysqld, nil, replication.Position{}, 0, sendTransaction)
+
+	go sendTestEvents(events, input)
+	_, err := bls.parseEvents(context.Background(), events)
+	if err != ErrServerEOF {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0].Statements, want) {
+		t.Errorf("binlogConnStreamer.parseEvents(): got %v, want %v", got, want)
+	}
+}
+
+func TestStreamerParseEventsRBRAltered(t *testing.T) {
+	input := []replication.BinlogEvent{
+		rotateEvent{},
+		formatEvent{},
+		tableMapEvent{id: 1, tm: &replication.TableMap{
+			Database: "vt_test_keyspace",
+			Name:     "vt_a",
+			Types:    []byte{3, 15, 3},
+		}},
+	}
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SELECT * FROM `vt_test_keyspace`.`vt_a` WHERE 1 != 1": {
+			Fields: []*querypb.Field{{Name: "id", Type: sqltypes.Uint32}, {Name: "name", Type: sqltypes.VarChar}},
+		},
+	}
+	events := make(chan replication.BinlogEvent)
+	bls := NewStreamer("vt_test_keyspace", mysqld, nil, replication.Position{}, 0, func(*binlogdatapb.BinlogTransaction) error { return nil })
+
+	go sendTestEvents(events, input)
+	_, err := bls.parseEvents(context.Background(), events)
+	want := "table vt_a has 2 columns, but its TABLE_MAP_EVENT has 3"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v, want %v", err, want)
+	}
+}
+
+func TestStreamerParseEventsRBRAlteredType(t *testing.T) {
+	input := []replication.BinlogEvent{
+		rotateEvent{},
+		formatEvent{},
+		tableMapEvent{id: 1, tm: &replication.TableMap{
+			Database: "vt_test_keyspace",
+			Name:     "vt_a",
+			Types:    []byte{3, 3},
+		}},
+	}
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SELECT * FROM `vt_test_keyspace`.`vt_a` WHERE 1 != 1": {
+			Fields: []*querypb.Field{{Name: "id", Type: sqltypes.Uint32}, {Name: "name", Type: sqltypes.VarChar}},
+		},
+	}
+	events := make(chan replication.BinlogEvent)
+	bls := NewStreamer("vt_test_keyspace", mysqld, nil, replication.Position{}, 0, func(*binlogdatapb.BinlogTransaction) error { return nil })
+
+	go sendTestEvents(events, input)
+	_, err := bls.parseEvents(context.Background(), events)
+	want := "column name of table vt_a has type VARCHAR, but its TABLE_MAP_EVENT has type 3"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v, want %v", err, want)
+	}
+}
+
 func TestGetStatementCategory(t *testing.T) {
 	table := map[string]binlogdatapb.BinlogTransaction_Statement_Category{
 		"":  binlogdatapb.BinlogTransaction_Statement_BL_UNRECOGNIZED,
@@ -5,6 +5,7 @@
 package grpcbinlogplayer
 
 import (
+	"flag"
 	"time"
 
 	"golang.org/x/net/context"
@@ -13,12 +14,15 @@ import (
 
 	"github.com/youtube/vitess/go/netutil"
 	"github.com/youtube/vitess/go/vt/binlog/binlogplayer"
+	"github.com/youtube/vitess/go/vt/servenv/grpcutils"
 
 	binlogdatapb "github.com/youtube/vitess/go/vt/proto/binlogdata"
 	binlogservicepb "github.com/youtube/vitess/go/vt/proto/binlogservice"
 	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
 )
 
+var creds = flag.String("binlog_player_grpc_auth_static_client_creds", "", "JSON file of the username and password to send to a vttablet using the static gRPC authentication plugin")
+
 // client implements a Client over go rpc
 type client struct {
 	cc *grpc.ClientConn
@@ -27,8 +31,12 @@ type client struct {
 
 func (client *client) Dial(tablet *topodatapb.Tablet, connTimeout time.Duration) error {
 	addr := netutil.JoinHostPort(tablet.Hostname, tablet.PortMap["grpc"])
-	var err error
-	client.cc, err = grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(connTimeout))
+	authOpts, err := grpcutils.ClientAuthDialOptions(*creds)
+	if err != nil {
+		return err
+	}
+	opts := append([]grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(connTimeout)}, authOpts...)
+	client.cc, err = grpc.Dial(addr, opts...)
 	if err != nil {
 		return err
 	}
@@ -130,6 +130,15 @@ type TabletStats struct {
 	// Stats is the current health status, as received by the
 	// StreamHealth RPC (replication lag, ...).
 	Stats *querypb.RealtimeStats
+	// SchemaVersion identifies the schema of the tablet, as
+	// received by the StreamHealth RPC. It is 0 if the tablet
+	// doesn't publish its schema.
+	SchemaVersion int64
+	// TableSchemas contains the columns of the tables of the
+	// tablet. It is only set in the update that follows the first
+	// response of a stream, and the ones where SchemaVersion
+	// changed. It is not kept in the HealthCheck cache.
+	TableSchemas []*querypb.TableSchema
 	// LastError is the error we last saw when trying to get the
 	// tablet's healthcheck.
 	LastError error
@@ -460,7 +469,7 @@ func (hcc *healthCheckConn) processResponse(hc *HealthCheckImpl, stream tabletco
 }
 
 // update updates the stats of a healthCheckConn, and returns a copy
-// of its tabletStats.
+// of its tabletStats, with the table schemas of the response.
 func (hcc *healthCheckConn) update(shr *querypb.StreamHealthResponse, serving bool, healthErr error) TabletStats {
 	hcc.mu.Lock()
 	defer hcc.mu.Unlock()
@@ -469,8 +478,11 @@ func (hcc *healthCheckConn) update(shr *querypb.StreamHealthResponse, serving bo
 	hcc.tabletStats.Serving = serving
 	hcc.tabletStats.TabletExternallyReparentedTimestamp = shr.TabletExternallyReparentedTimestamp
 	hcc.tabletStats.Stats = shr.RealtimeStats
+	hcc.tabletStats.SchemaVersion = shr.SchemaVersion
 	hcc.tabletStats.LastError = healthErr
-	return hcc.tabletStats
+	ts := hcc.tabletStats
+	ts.TableSchemas = shr.TableSchemas
+	return ts
 }
 
 func (hc *HealthCheckImpl) checkHealthCheckTimeout() {
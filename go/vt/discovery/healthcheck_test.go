@@ -198,6 +198,55 @@ func TestHealthCheck(t *testing.T) {
 	hc.Close()
 }
 
+// TestHealthCheckSchema tests that the table schemas are sent to the
+// listener, but not kept in the cache.
+func TestHealthCheckSchema(t *testing.T) {
+	tablet := topo.NewTablet(0, "cell", "a")
+	tablet.PortMap["vt"] = 1
+	input := make(chan *querypb.StreamHealthResponse)
+	createFakeConn(tablet, input)
+	l := newListener()
+	hc := NewHealthCheck(1*time.Millisecond, 1*time.Millisecond, time.Hour).(*HealthCheckImpl)
+	hc.SetListener(l, true)
+	hc.AddTablet(tablet, "")
+	<-l.output
+
+	target := &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA}
+	tableSchemas := []*querypb.TableSchema{{
+		Name:    "t1",
+		Columns: []*querypb.Field{{Name: "id", Type: sqltypes.Int64}},
+	}}
+	input <- &querypb.StreamHealthResponse{
+		Target:        target,
+		Serving:       true,
+		RealtimeStats: &querypb.RealtimeStats{},
+		SchemaVersion: 5,
+		TableSchemas:  tableSchemas,
+	}
+	res := <-l.output
+	if res.SchemaVersion != 5 || !reflect.DeepEqual(res.TableSchemas, tableSchemas) {
+		t.Errorf(`<-l.output: %+v; want SchemaVersion 5 and TableSchemas %v`, res, tableSchemas)
+	}
+	tcsl := hc.CacheStatus()
+	if len(tcsl) != 1 || len(tcsl[0].TabletsStats) != 1 || tcsl[0].TabletsStats[0].SchemaVersion != 5 || tcsl[0].TabletsStats[0].TableSchemas != nil {
+		t.Errorf(`hc.CacheStatus() = %+v; want SchemaVersion 5 and no TableSchemas`, tcsl)
+	}
+
+	// The next responses don't have the table schemas.
+	input <- &querypb.StreamHealthResponse{
+		Target:        target,
+		Serving:       true,
+		RealtimeStats: &querypb.RealtimeStats{},
+		SchemaVersion: 5,
+	}
+	res = <-l.output
+	if res.SchemaVersion != 5 || res.TableSchemas != nil {
+		t.Errorf(`<-l.output: %+v; want SchemaVersion 5 and no TableSchemas`, res)
+	}
+
+	hc.Close()
+}
+
 // TestHealthCheckCloseWaitsForGoRoutines tests that Close() waits for all Go
 // routines to finish and the listener won't be called anymore.
 func TestHealthCheckCloseWaitsForGoRoutines(t *testing.T) {
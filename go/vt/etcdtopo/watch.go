@@ -24,7 +24,7 @@ func newWatchData(valueType dataType, node *etcd.Node) *topo.WatchData {
 
 // Watch is part of the topo.Backend interface
 func (s *Server) Watch(ctx context.Context, cellName, filePath string) (*topo.WatchData, <-chan *topo.WatchData, topo.CancelFunc) {
-	cell, err := s.getCell(cellName)
+	c, err := s.clientForCell(cellName)
 	if err != nil {
 		return &topo.WatchData{Err: fmt.Errorf("Watch cannot get cell: %v", err)}, nil, nil
 	}
@@ -34,7 +34,7 @@ func (s *Server) Watch(ctx context.Context, cellName, filePath string) (*topo.Wa
 	valueType, filePath = oldTypeAndFilePath(filePath)
 
 	// Get the initial version of the file
-	initial, err := cell.Get(filePath, false /* sort */, false /* recursive */)
+	initial, err := c.Get(filePath, false /* sort */, false /* recursive */)
 	if err != nil {
 		// generic error
 		return &topo.WatchData{Err: convertError(err)}, nil, nil
@@ -77,7 +77,7 @@ func (s *Server) Watch(ctx context.Context, cellName, filePath string) (*topo.Wa
 	watchError := make(chan error)
 	go func(stop chan bool) {
 		versionToWatch := initial.Node.ModifiedIndex + 1
-		_, err := cell.Client.Watch(filePath, versionToWatch, false /* recursive */, watchChannel, stop)
+		_, err := c.Watch(filePath, versionToWatch, false /* recursive */, watchChannel, stop)
 		// Watch will only return a non-nil error, otherwise
 		// it keeps on watching. Send the error down.
 		watchError <- err
@@ -0,0 +1,71 @@
+package janitor
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/mysqlctl"
+	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+)
+
+var (
+	backupPruningKeepCount = flag.Int("backup_pruning_keep_count", 0, "the backup_pruning janitor keeps at least this many full backups")
+	backupPruningKeepDays  = flag.Int("backup_pruning_keep_days", 0, "the backup_pruning janitor keeps the full backups taken less than this many days ago")
+)
+
+func init() {
+	Register("backup_pruning", &BackupPruning{})
+}
+
+// BackupPruning removes the expired backups of the shard from the
+// BackupStorage, with the same retention policy as the PruneBackups
+// vtctl command.
+type BackupPruning struct {
+	wr           *wrangler.Wrangler
+	bucket       string
+	keepCount    int
+	keepDuration time.Duration
+}
+
+// Configure is part of the Janitor interface.
+func (bp *BackupPruning) Configure(wr *wrangler.Wrangler, keyspace, shard string) error {
+	if *backupPruningKeepCount <= 0 && *backupPruningKeepDays <= 0 {
+		return errors.New("backup_pruning requires a positive -backup_pruning_keep_count or -backup_pruning_keep_days")
+	}
+	bp.wr = wr
+	bp.bucket = fmt.Sprintf("%v/%v", keyspace, shard)
+	bp.keepCount = *backupPruningKeepCount
+	bp.keepDuration = time.Duration(*backupPruningKeepDays) * 24 * time.Hour
+	return nil
+}
+
+// Run is part of the Janitor interface.
+func (bp *BackupPruning) Run(ctx context.Context, active bool) error {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+	bhs, err := bs.ListBackups(bp.bucket)
+	if err != nil {
+		return err
+	}
+	for _, bh := range mysqlctl.BackupsToPrune(bhs, bp.keepCount, bp.keepDuration, time.Now()) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !active {
+			bp.wr.Logger().Infof("would remove backup %v/%v", bp.bucket, bh.Name())
+			continue
+		}
+		bp.wr.Logger().Infof("removing backup %v/%v", bp.bucket, bh.Name())
+		if err := bs.RemoveBackup(bp.bucket, bh.Name()); err != nil {
+			return fmt.Errorf("cannot remove backup %v/%v: %v", bp.bucket, bh.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package janitor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/mysqlctl"
+	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
+	"github.com/youtube/vitess/go/vt/mysqlctl/filebackupstorage"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+)
+
+func TestBackupPruning(t *testing.T) {
+	root, err := ioutil.TempDir("", "backup_pruning_test")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	*filebackupstorage.FileBackupStorageRoot = root
+	*backupstorage.BackupStorageImplementation = "file"
+	defer func() { *backupstorage.BackupStorageImplementation = "" }()
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		t.Fatalf("GetBackupStorage failed: %v", err)
+	}
+	now := time.Now()
+	for i, name := range []string{"b1", "b2", "b3"} {
+		bh, err := bs.StartBackup("ks/0", name)
+		if err != nil {
+			t.Fatalf("StartBackup failed: %v", err)
+		}
+		wc, err := bh.AddFile("MANIFEST")
+		if err != nil {
+			t.Fatalf("AddFile failed: %v", err)
+		}
+		if err := json.NewEncoder(wc).Encode(&mysqlctl.BackupManifest{Time: now.Add(time.Duration(i-3) * time.Hour)}); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		if err := wc.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if err := bh.EndBackup(); err != nil {
+			t.Fatalf("EndBackup failed: %v", err)
+		}
+	}
+
+	bp := &BackupPruning{}
+	if err := bp.Configure(wrangler.New(logutil.NewMemoryLogger(), topo.Server{}, nil), "ks", "0"); err == nil {
+		t.Errorf("Configure without retention policy should have failed")
+	}
+	*backupPruningKeepCount = 2
+	defer func() { *backupPruningKeepCount = 0 }()
+	if err := bp.Configure(wrangler.New(logutil.NewMemoryLogger(), topo.Server{}, nil), "ks", "0"); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	checkBackups := func(want []string) {
+		bhs, err := bs.ListBackups("ks/0")
+		if err != nil {
+			t.Fatalf("ListBackups failed: %v", err)
+		}
+		var got []string
+		for _, bh := range bhs {
+			got = append(got, bh.Name())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("backups = %v, want %v", got, want)
+		}
+	}
+	ctx := context.Background()
+	if err := bp.Run(ctx, false); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	checkBackups([]string{"b1", "b2", "b3"})
+	if err := bp.Run(ctx, true); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	checkBackups([]string{"b2", "b3"})
+}
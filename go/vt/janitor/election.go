@@ -1,14 +1,122 @@
 package janitor
 
-// TODO(szopa): Implement master election using ZooKeeper.
+import (
+	"path"
+	"time"
 
-func (scheduler *Scheduler) IsMaster() bool {
-	return true
+	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/vt/topo"
+	"golang.org/x/net/context"
+)
+
+// Several vtjanitor processes can manage the same shard. They then
+// take part in a master election in the topology server, and only
+// the master runs the janitors in active mode. The others run them in
+// dry run mode, and one of them takes over when the master goes away.
+
+// electionRetryDelay is how long we wait before trying again when
+// WaitForMastership fails.
+const electionRetryDelay = 5 * time.Second
+
+// electionName returns the name of the master election for a shard.
+func electionName(keyspace, shard string) string {
+	return path.Join("vtjanitor", keyspace, shard)
+}
+
+// RunElection makes the scheduler a candidate in the master election
+// for its shard. id identifies this process, and should be unique,
+// like its hostname:port. The election runs in the background until
+// StopElection is called. If RunElection is not called, the scheduler
+// considers itself the master.
+func (scheduler *Scheduler) RunElection(id string) error {
+	mp, err := scheduler.ts.NewMasterParticipation(electionName(scheduler.Keyspace, scheduler.Shard), id)
+	if err != nil {
+		return err
+	}
+	scheduler.mu.Lock()
+	scheduler.mp = mp
+	scheduler.mu.Unlock()
+
+	go func() {
+		for {
+			ctx, err := mp.WaitForMastership()
+			switch err {
+			case nil:
+				log.Infof("became the master janitor for %v/%v", scheduler.Keyspace, scheduler.Shard)
+				scheduler.setMasterContext(ctx)
+				<-ctx.Done()
+				if !scheduler.setMasterContext(nil) {
+					// StopElection was called, and
+					// WaitForMastership can't be
+					// called any more.
+					return
+				}
+				log.Infof("lost the mastership for %v/%v", scheduler.Keyspace, scheduler.Shard)
+			case topo.ErrInterrupted:
+				return
+			default:
+				log.Errorf("Got error while waiting for master, will retry in %v: %v", electionRetryDelay, err)
+				time.Sleep(electionRetryDelay)
+			}
+		}
+	}()
+	return nil
 }
 
-func (scheduler *Scheduler) RunElection() {
+// StopElection leaves the master election, relinquishing the
+// mastership if we had it.
+func (scheduler *Scheduler) StopElection() {
+	scheduler.mu.Lock()
+	mp := scheduler.mp
+	scheduler.electionStopped = true
+	scheduler.mu.Unlock()
+	if mp != nil {
+		mp.Stop()
+	}
+}
+
+// setMasterContext records our current mastership. It returns false
+// if StopElection was called.
+func (scheduler *Scheduler) setMasterContext(ctx context.Context) bool {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	scheduler.masterCtx = ctx
+	return !scheduler.electionStopped
+}
+
+// masterContext returns the context the janitors should run with,
+// and whether we are the master. The context is canceled when we lose
+// the mastership.
+func (scheduler *Scheduler) masterContext() (context.Context, bool) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	if scheduler.mp == nil {
+		return context.Background(), true
+	}
+	if scheduler.masterCtx == nil || scheduler.masterCtx.Err() != nil {
+		return context.Background(), false
+	}
+	return scheduler.masterCtx, true
+}
+
+// IsMaster returns true if this scheduler can run the janitors in
+// active mode.
+func (scheduler *Scheduler) IsMaster() bool {
+	_, isMaster := scheduler.masterContext()
+	return isMaster
 }
 
+// CurrentMasterID returns the id of the current master janitor.
 func (scheduler *Scheduler) CurrentMasterID() string {
-	return "master election not implemented"
+	scheduler.mu.Lock()
+	mp := scheduler.mp
+	scheduler.mu.Unlock()
+	if mp == nil {
+		return "master election not enabled"
+	}
+	id, err := mp.GetCurrentMasterID()
+	if err != nil {
+		return "unknown: " + err.Error()
+	}
+	return id
 }
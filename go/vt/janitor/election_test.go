@@ -0,0 +1,49 @@
+package janitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/zktopo/zktestserver"
+)
+
+func waitForMaster(t *testing.T, scheduler *Scheduler, want bool) {
+	timeout := time.Now().Add(5 * time.Second)
+	for scheduler.IsMaster() != want {
+		if time.Now().After(timeout) {
+			t.Fatalf("IsMaster() never became %v", want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestElection(t *testing.T) {
+	ts := zktestserver.New(t, []string{"cell1"})
+	s1, _ := New("ks", "0", ts, nil, 0)
+	s2, _ := New("ks", "0", ts, nil, 0)
+
+	// Without an election, a scheduler is the master.
+	if !s1.IsMaster() {
+		t.Errorf("scheduler without election should be the master")
+	}
+
+	if err := s1.RunElection("id1"); err != nil {
+		t.Fatalf("RunElection failed: %v", err)
+	}
+	waitForMaster(t, s1, true)
+	if err := s2.RunElection("id2"); err != nil {
+		t.Fatalf("RunElection failed: %v", err)
+	}
+	if got := s2.CurrentMasterID(); got != "id1" {
+		t.Errorf("CurrentMasterID() = %v, want id1", got)
+	}
+	if s2.IsMaster() {
+		t.Errorf("second scheduler should not be the master")
+	}
+
+	// The second scheduler takes over when the first one stops.
+	s1.StopElection()
+	waitForMaster(t, s1, false)
+	waitForMaster(t, s2, true)
+	s2.StopElection()
+}
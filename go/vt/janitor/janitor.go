@@ -2,6 +2,7 @@ package janitor
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"sync"
@@ -10,8 +11,11 @@ import (
 	log "github.com/golang/glog"
 	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
 )
 
+var runTimeout = flag.Duration("janitor_run_timeout", 5*time.Minute, "timeout for one run of a janitor")
+
 var janitorRepository = make(map[string]Janitor)
 
 func AvailableModules() (modules []string) {
@@ -43,8 +47,9 @@ type Janitor interface {
 	// janitor can perform destructive changes to the
 	// topology. Otherwise, it is considered to be in dry run
 	// mode, and it should only log what it would have done if it
-	// were active.
-	Run(active bool) error
+	// were active. ctx is canceled if the scheduler loses its
+	// mastership while an active run is in progress.
+	Run(ctx context.Context, active bool) error
 
 	// Initialize initializes the Janitor.
 	Configure(wr *wrangler.Wrangler, keyspace, shard string) error
@@ -60,6 +65,16 @@ type Scheduler struct {
 	janitors  map[string]*JanitorInfo
 	ts        topo.Server
 	wrangler  *wrangler.Wrangler
+
+	// mu protects the master election fields, see election.go.
+	mu sync.Mutex
+	// mp is set if RunElection was called.
+	mp topo.MasterParticipation
+	// masterCtx is the context of our current mastership, or nil
+	// if we are not the master.
+	masterCtx context.Context
+	// electionStopped is set by StopElection.
+	electionStopped bool
 }
 
 func New(keyspace, shard string, ts topo.Server, wr *wrangler.Wrangler, sleepTime time.Duration) (*Scheduler, error) {
@@ -165,10 +180,13 @@ func (scheduler *Scheduler) runJanitor(name string) {
 	if !ok {
 		panic("janitor " + name + " not enabled")
 	}
-	active := janitor.Active && scheduler.IsMaster()
+	ctx, isMaster := scheduler.masterContext()
+	active := janitor.Active && isMaster
+	ctx, cancel := context.WithTimeout(ctx, *runTimeout)
+	defer cancel()
 	log.Infof("running janitor %v (active: %v)", name, active)
 	start := time.Now()
-	if err := janitor.Run(active); err != nil {
+	if err := janitor.Run(ctx, active); err != nil {
 		// TODO(szopa): Add some exponential
 		// backoff if an error occurs.
 		log.Errorf("janitor %v run: %v", name, err)
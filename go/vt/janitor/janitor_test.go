@@ -8,6 +8,7 @@ import (
 
 	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
 )
 
 //var testCells = []string{"oe", "wj"}
@@ -21,7 +22,7 @@ func (janitor *testJanitor) Configure(wr *wrangler.Wrangler, keyspace, shard str
 	return janitor.configure(wr, keyspace, shard)
 }
 
-func (janitor *testJanitor) Run(active bool) error {
+func (janitor *testJanitor) Run(ctx context.Context, active bool) error {
 	return janitor.run(active)
 }
 
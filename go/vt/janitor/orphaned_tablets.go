@@ -0,0 +1,139 @@
+package janitor
+
+import (
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+var (
+	orphanedTabletGracePeriod = flag.Duration("orphaned_tablet_grace_period", time.Hour, "how long a tablet must have been unreachable before the orphaned_tablets janitor deletes its record")
+	orphanedTabletPingTimeout = flag.Duration("orphaned_tablet_ping_timeout", 10*time.Second, "timeout for the pings sent by the orphaned_tablets janitor")
+)
+
+func init() {
+	Register("orphaned_tablets", &OrphanedTablets{})
+}
+
+// OrphanedTablets deletes the records of the tablets of the shard that
+// have been unreachable for longer than a grace period, like the ones
+// left behind by a tablet that was shut down and never restarted. The
+// master is never deleted.
+type OrphanedTablets struct {
+	wr              *wrangler.Wrangler
+	keyspace, shard string
+	gracePeriod     time.Duration
+
+	// mu protects unreachableSince.
+	mu sync.Mutex
+	// unreachableSince maps the aliases of the unreachable tablets
+	// to the time of the first failed ping.
+	unreachableSince map[string]time.Time
+}
+
+// Configure is part of the Janitor interface.
+func (ot *OrphanedTablets) Configure(wr *wrangler.Wrangler, keyspace, shard string) error {
+	ot.wr = wr
+	ot.keyspace = keyspace
+	ot.shard = shard
+	ot.gracePeriod = *orphanedTabletGracePeriod
+	ot.unreachableSince = make(map[string]time.Time)
+	return nil
+}
+
+// Run is part of the Janitor interface.
+func (ot *OrphanedTablets) Run(ctx context.Context, active bool) error {
+	tablets, err := ot.shardTablets(ctx)
+	if err != nil {
+		return err
+	}
+	si, err := ot.wr.TopoServer().GetShard(ctx, ot.keyspace, ot.shard)
+	if err != nil {
+		return err
+	}
+
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	now := time.Now()
+	seen := make(map[string]bool)
+	for _, ti := range tablets {
+		alias := topoproto.TabletAliasString(ti.Alias)
+		seen[alias] = true
+		if topoproto.TabletAliasEqual(si.MasterAlias, ti.Alias) {
+			continue
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, *orphanedTabletPingTimeout)
+		err := ot.wr.TabletManagerClient().Ping(pingCtx, ti.Tablet)
+		cancel()
+		if err == nil {
+			delete(ot.unreachableSince, alias)
+			continue
+		}
+		since, ok := ot.unreachableSince[alias]
+		if !ok {
+			since = now
+			ot.unreachableSince[alias] = now
+		}
+		if now.Sub(since) < ot.gracePeriod {
+			ot.wr.Logger().Infof("tablet %v is unreachable since %v: %v", alias, since, err)
+			continue
+		}
+
+		if !active {
+			ot.wr.Logger().Infof("would delete tablet %v, unreachable since %v", alias, since)
+			continue
+		}
+		ot.wr.Logger().Infof("deleting tablet %v, unreachable since %v", alias, since)
+		if err := ot.wr.DeleteTablet(ctx, ti.Alias, false /* allowMaster */); err != nil {
+			return fmt.Errorf("cannot delete tablet %v: %v", alias, err)
+		}
+		delete(ot.unreachableSince, alias)
+	}
+
+	// Forget about the tablets that are gone.
+	for alias := range ot.unreachableSince {
+		if !seen[alias] {
+			delete(ot.unreachableSince, alias)
+		}
+	}
+	return nil
+}
+
+// shardTablets returns the tablet records of the shard in all the
+// cells. Unlike GetTabletMapForShard, it doesn't rely on the
+// replication graph, so it also finds the tablets missing from it.
+func (ot *OrphanedTablets) shardTablets(ctx context.Context) ([]*topo.TabletInfo, error) {
+	ts := ot.wr.TopoServer()
+	cells, err := ts.GetKnownCells(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var aliases []*topodatapb.TabletAlias
+	for _, cell := range cells {
+		cellAliases, err := ts.GetTabletsByCell(ctx, cell)
+		if err != nil && err != topo.ErrNoNode {
+			return nil, fmt.Errorf("cannot list the tablets in cell %v: %v", cell, err)
+		}
+		aliases = append(aliases, cellAliases...)
+	}
+	tabletMap, err := ts.GetTabletMap(ctx, aliases)
+	if err != nil {
+		return nil, err
+	}
+	var result []*topo.TabletInfo
+	for _, ti := range tabletMap {
+		if ti.Keyspace == ot.keyspace && ti.Shard == ot.shard {
+			result = append(result, ti)
+		}
+	}
+	return result, nil
+}
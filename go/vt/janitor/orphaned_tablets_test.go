@@ -0,0 +1,149 @@
+package janitor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/tabletmanager/tmclient"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"github.com/youtube/vitess/go/vt/zktopo/zktestserver"
+	"golang.org/x/net/context"
+
+	replicationdatapb "github.com/youtube/vitess/go/vt/proto/replicationdata"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+// fakeTMClient answers the Ping and SlaveStatus calls of the
+// janitors, and records their SetMaster calls. The other methods are
+// not implemented.
+type fakeTMClient struct {
+	tmclient.TabletManagerClient
+
+	unreachable map[string]bool
+	slaveStatus map[string]*replicationdatapb.Status
+	setMaster   []string
+}
+
+func newFakeTMClient() *fakeTMClient {
+	return &fakeTMClient{
+		unreachable: make(map[string]bool),
+		slaveStatus: make(map[string]*replicationdatapb.Status),
+	}
+}
+
+func (client *fakeTMClient) Ping(ctx context.Context, tablet *topodatapb.Tablet) error {
+	if client.unreachable[topoproto.TabletAliasString(tablet.Alias)] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (client *fakeTMClient) SlaveStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	status, ok := client.slaveStatus[topoproto.TabletAliasString(tablet.Alias)]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return status, nil
+}
+
+func (client *fakeTMClient) SetMaster(ctx context.Context, tablet *topodatapb.Tablet, parent *topodatapb.TabletAlias, timeCreatedNS int64, forceStartSlave bool) error {
+	client.setMaster = append(client.setMaster, topoproto.TabletAliasString(tablet.Alias)+" -> "+topoproto.TabletAliasString(parent))
+	return nil
+}
+
+// newTestShard returns a wrangler on a topology with the ks/0 shard,
+// its master cell1-100 and the provided tablets.
+func newTestShard(t *testing.T, tmc tmclient.TabletManagerClient, tablets ...*topodatapb.Tablet) (*wrangler.Wrangler, *logutil.MemoryLogger) {
+	ctx := context.Background()
+	ts := zktestserver.New(t, []string{"cell1", "cell2"})
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	if err := ts.CreateShard(ctx, "ks", "0"); err != nil {
+		t.Fatalf("CreateShard failed: %v", err)
+	}
+	master := newTestTablet("cell1", 100, topodatapb.TabletType_MASTER)
+	for _, tablet := range append([]*topodatapb.Tablet{master}, tablets...) {
+		if err := ts.CreateTablet(ctx, tablet); err != nil {
+			t.Fatalf("CreateTablet failed: %v", err)
+		}
+	}
+	if _, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.MasterAlias = master.Alias
+		si.Cells = []string{"cell1", "cell2"}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardFields failed: %v", err)
+	}
+	logger := logutil.NewMemoryLogger()
+	return wrangler.New(logger, ts, tmc), logger
+}
+
+func newTestTablet(cell string, uid uint32, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+	return &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+		Hostname: fmt.Sprintf("host%v", uid),
+		PortMap:  map[string]int32{"mysql": 3306},
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     tabletType,
+	}
+}
+
+func TestOrphanedTablets(t *testing.T) {
+	ctx := context.Background()
+	tmc := newFakeTMClient()
+	wr, logger := newTestShard(t, tmc,
+		newTestTablet("cell1", 101, topodatapb.TabletType_REPLICA),
+		newTestTablet("cell2", 200, topodatapb.TabletType_RDONLY))
+	ts := wr.TopoServer()
+	tmc.unreachable["cell1-0000000100"] = true
+	tmc.unreachable["cell2-0000000200"] = true
+
+	ot := &OrphanedTablets{}
+	if err := ot.Configure(wr, "ks", "0"); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	// Within the grace period, nothing happens.
+	if err := ot.Run(ctx, true); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if _, err := ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell2", Uid: 200}); err != nil {
+		t.Errorf("tablet deleted within the grace period: %v", err)
+	}
+
+	// In dry run mode, we only log.
+	ot.gracePeriod = 0
+	if err := ot.Run(ctx, false); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if _, err := ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell2", Uid: 200}); err != nil {
+		t.Errorf("tablet deleted in dry run mode: %v", err)
+	}
+	if want := "would delete tablet cell2-0000000200"; !strings.Contains(logger.String(), want) {
+		t.Errorf("log doesn't contain %q: %v", want, logger.String())
+	}
+
+	// In active mode, the unreachable replica is deleted, but not
+	// the master.
+	if err := ot.Run(ctx, true); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if _, err := ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell2", Uid: 200}); err != topo.ErrNoNode {
+		t.Errorf("unreachable tablet not deleted: %v", err)
+	}
+	for _, uid := range []uint32{100, 101} {
+		if _, err := ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "cell1", Uid: uid}); err != nil {
+			t.Errorf("tablet %v deleted: %v", uid, err)
+		}
+	}
+	if len(ot.unreachableSince) != 0 {
+		t.Errorf("unreachableSince not cleaned up: %v", ot.unreachableSince)
+	}
+}
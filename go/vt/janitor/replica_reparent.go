@@ -0,0 +1,92 @@
+package janitor
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+var replicaReparentTimeout = flag.Duration("replica_reparent_timeout", 30*time.Second, "timeout for the SlaveStatus and SetMaster calls of the replica_reparent janitor")
+
+func init() {
+	Register("replica_reparent", &ReplicaReparent{})
+}
+
+// ReplicaReparent re-parents to the current master of the shard the
+// replica and rdonly tablets that replicate from another server, like
+// the ones that were unreachable during a reparent. The tablets of the
+// other types are left alone, as they may have stopped replicating on
+// purpose.
+type ReplicaReparent struct {
+	wr              *wrangler.Wrangler
+	keyspace, shard string
+}
+
+// Configure is part of the Janitor interface.
+func (rr *ReplicaReparent) Configure(wr *wrangler.Wrangler, keyspace, shard string) error {
+	rr.wr = wr
+	rr.keyspace = keyspace
+	rr.shard = shard
+	return nil
+}
+
+// Run is part of the Janitor interface.
+func (rr *ReplicaReparent) Run(ctx context.Context, active bool) error {
+	ts := rr.wr.TopoServer()
+	si, err := ts.GetShard(ctx, rr.keyspace, rr.shard)
+	if err != nil {
+		return err
+	}
+	if !si.HasMaster() {
+		rr.wr.Logger().Infof("shard %v/%v has no master, nothing to do", rr.keyspace, rr.shard)
+		return nil
+	}
+	master, err := ts.GetTablet(ctx, si.MasterAlias)
+	if err != nil {
+		return fmt.Errorf("cannot read the master %v: %v", topoproto.TabletAliasString(si.MasterAlias), err)
+	}
+
+	tabletMap, err := ts.GetTabletMapForShard(ctx, rr.keyspace, rr.shard)
+	if err != nil && err != topo.ErrPartialResult {
+		return err
+	}
+	for _, ti := range tabletMap {
+		if ti.Type != topodatapb.TabletType_REPLICA && ti.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		alias := topoproto.TabletAliasString(ti.Alias)
+
+		statusCtx, cancel := context.WithTimeout(ctx, *replicaReparentTimeout)
+		status, err := rr.wr.TabletManagerClient().SlaveStatus(statusCtx, ti.Tablet)
+		cancel()
+		if err != nil {
+			// Unreachable tablets are handled by the
+			// orphaned_tablets janitor.
+			rr.wr.Logger().Warningf("cannot get the replication status of tablet %v: %v", alias, err)
+			continue
+		}
+		if status.MasterHost == master.Hostname && status.MasterPort == master.PortMap["mysql"] {
+			continue
+		}
+
+		if !active {
+			rr.wr.Logger().Infof("would re-parent tablet %v, replicating from %v:%v, to master %v", alias, status.MasterHost, status.MasterPort, topoproto.TabletAliasString(master.Alias))
+			continue
+		}
+		rr.wr.Logger().Infof("re-parenting tablet %v, replicating from %v:%v, to master %v", alias, status.MasterHost, status.MasterPort, topoproto.TabletAliasString(master.Alias))
+		reparentCtx, cancel := context.WithTimeout(ctx, *replicaReparentTimeout)
+		err = rr.wr.ReparentTablet(reparentCtx, ti.Alias)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("cannot re-parent tablet %v: %v", alias, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package janitor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	replicationdatapb "github.com/youtube/vitess/go/vt/proto/replicationdata"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+func TestReplicaReparent(t *testing.T) {
+	ctx := context.Background()
+	tmc := newFakeTMClient()
+	wr, logger := newTestShard(t, tmc,
+		newTestTablet("cell1", 101, topodatapb.TabletType_REPLICA),
+		newTestTablet("cell1", 102, topodatapb.TabletType_RDONLY),
+		newTestTablet("cell1", 103, topodatapb.TabletType_BACKUP),
+		newTestTablet("cell1", 104, topodatapb.TabletType_REPLICA))
+	tmc.slaveStatus["cell1-0000000101"] = &replicationdatapb.Status{MasterHost: "host100", MasterPort: 3306}
+	tmc.slaveStatus["cell1-0000000102"] = &replicationdatapb.Status{MasterHost: "host104", MasterPort: 3306}
+	tmc.slaveStatus["cell1-0000000103"] = &replicationdatapb.Status{}
+	// cell1-0000000104 is unreachable.
+
+	rr := &ReplicaReparent{}
+	if err := rr.Configure(wr, "ks", "0"); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	if err := rr.Run(ctx, false); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(tmc.setMaster) != 0 {
+		t.Errorf("SetMaster called in dry run mode: %v", tmc.setMaster)
+	}
+	if want := "would re-parent tablet cell1-0000000102, replicating from host104:3306"; !strings.Contains(logger.String(), want) {
+		t.Errorf("log doesn't contain %q: %v", want, logger.String())
+	}
+
+	if err := rr.Run(ctx, true); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if want := []string{"cell1-0000000102 -> cell1-0000000100"}; !reflect.DeepEqual(tmc.setMaster, want) {
+		t.Errorf("SetMaster calls = %v, want %v", tmc.setMaster, want)
+	}
+}
@@ -0,0 +1,89 @@
+package janitor
+
+import (
+	"fmt"
+
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+func init() {
+	Register("shard_replication_fix", &ShardReplicationFix{})
+}
+
+// ShardReplicationFix removes the stale entries of the ShardReplication
+// objects of the shard in all the cells: the ones that point to a
+// tablet that doesn't exist, or that belongs to another shard or cell.
+// It does what the ShardReplicationFix vtctl command does.
+type ShardReplicationFix struct {
+	wr              *wrangler.Wrangler
+	keyspace, shard string
+}
+
+// Configure is part of the Janitor interface.
+func (srf *ShardReplicationFix) Configure(wr *wrangler.Wrangler, keyspace, shard string) error {
+	srf.wr = wr
+	srf.keyspace = keyspace
+	srf.shard = shard
+	return nil
+}
+
+// Run is part of the Janitor interface.
+func (srf *ShardReplicationFix) Run(ctx context.Context, active bool) error {
+	cells, err := srf.wr.TopoServer().GetKnownCells(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		stale, err := srf.staleEntries(ctx, cell)
+		if err != nil {
+			return fmt.Errorf("cannot check the replication graph in cell %v: %v", cell, err)
+		}
+		for _, alias := range stale {
+			if !active {
+				srf.wr.Logger().Infof("would remove tablet %v from the replication graph of %v/%v in cell %v", topoproto.TabletAliasString(alias), srf.keyspace, srf.shard, cell)
+				continue
+			}
+			// FixShardReplication removes the first stale entry
+			// it finds, so we call it once per stale entry.
+			if err := topo.FixShardReplication(ctx, srf.wr.TopoServer(), srf.wr.Logger(), cell, srf.keyspace, srf.shard); err != nil {
+				return fmt.Errorf("cannot fix the replication graph in cell %v: %v", cell, err)
+			}
+		}
+	}
+	return nil
+}
+
+// staleEntries returns the aliases of the stale entries of the
+// ShardReplication object in a cell.
+func (srf *ShardReplicationFix) staleEntries(ctx context.Context, cell string) ([]*topodatapb.TabletAlias, error) {
+	ts := srf.wr.TopoServer()
+	sri, err := ts.GetShardReplication(ctx, cell, srf.keyspace, srf.shard)
+	switch err {
+	case nil:
+	case topo.ErrNoNode:
+		return nil, nil
+	default:
+		return nil, err
+	}
+
+	var stale []*topodatapb.TabletAlias
+	for _, node := range sri.Nodes {
+		ti, err := ts.GetTablet(ctx, node.TabletAlias)
+		switch err {
+		case nil:
+			if ti.Keyspace != srf.keyspace || ti.Shard != srf.shard || ti.Alias.Cell != cell {
+				stale = append(stale, node.TabletAlias)
+			}
+		case topo.ErrNoNode:
+			stale = append(stale, node.TabletAlias)
+		default:
+			return nil, err
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,61 @@
+package janitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/youtube/vitess/go/vt/topo"
+	"golang.org/x/net/context"
+
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+func TestShardReplicationFix(t *testing.T) {
+	ctx := context.Background()
+	wr, logger := newTestShard(t, newFakeTMClient(),
+		newTestTablet("cell1", 101, topodatapb.TabletType_REPLICA))
+	ts := wr.TopoServer()
+
+	// Add an entry for a tablet that doesn't exist, and one for a
+	// tablet of another cell.
+	if err := ts.UpdateShardReplicationFields(ctx, "cell1", "ks", "0", func(sr *topodatapb.ShardReplication) error {
+		sr.Nodes = append(sr.Nodes,
+			&topodatapb.ShardReplication_Node{TabletAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 102}},
+			&topodatapb.ShardReplication_Node{TabletAlias: &topodatapb.TabletAlias{Cell: "cell2", Uid: 100}})
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardReplicationFields failed: %v", err)
+	}
+
+	srf := &ShardReplicationFix{}
+	if err := srf.Configure(wr, "ks", "0"); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	if err := srf.Run(ctx, false); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, want := range []string{"would remove tablet cell1-0000000102", "would remove tablet cell2-0000000100"} {
+		if !strings.Contains(logger.String(), want) {
+			t.Errorf("log doesn't contain %q: %v", want, logger.String())
+		}
+	}
+	checkNodes := func(want int) {
+		sri, err := ts.GetShardReplication(ctx, "cell1", "ks", "0")
+		if err != nil {
+			t.Fatalf("GetShardReplication failed: %v", err)
+		}
+		if len(sri.Nodes) != want {
+			t.Errorf("got %v nodes, want %v: %v", len(sri.Nodes), want, sri.Nodes)
+		}
+	}
+	checkNodes(4)
+
+	if err := srf.Run(ctx, true); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	checkNodes(2)
+	if _, err := ts.GetShardReplication(ctx, "cell2", "ks", "0"); err != topo.ErrNoNode {
+		t.Errorf("GetShardReplication(cell2) = %v, want ErrNoNode", err)
+	}
+}
@@ -16,11 +16,11 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	log "github.com/golang/glog"
 	"golang.org/x/net/context"
 
-	"github.com/youtube/vitess/go/cgzip"
 	"github.com/youtube/vitess/go/sync2"
 	"github.com/youtube/vitess/go/vt/concurrency"
 	"github.com/youtube/vitess/go/vt/logutil"
@@ -36,6 +36,9 @@ const (
 	backupInnodbLogGroupHomeDir = "InnoDBLog"
 	backupData                  = "Data"
 
+	// the base for binlog files in incremental backups
+	backupBinlog = "Binlog"
+
 	// the manifest file name
 	backupManifest = "MANIFEST"
 )
@@ -59,6 +62,8 @@ type FileEntry struct {
 	// - backupInnodbDataHomeDir for files that go into Mycnf.InnodbDataHomeDir
 	// - backupInnodbLogGroupHomeDir for files that go into Mycnf.InnodbLogGroupHomeDir
 	// - backupData for files that go into Mycnf.DataDir
+	// - backupBinlog for files that go into the directory of
+	//   Mycnf.BinLogPath
 	Base string
 
 	// Name is the file name, relative to Base
@@ -79,6 +84,8 @@ func (fe *FileEntry) open(cnf *Mycnf, readOnly bool) (*os.File, error) {
 		root = cnf.InnodbLogGroupHomeDir
 	case backupData:
 		root = cnf.DataDir
+	case backupBinlog:
+		root = path.Dir(cnf.BinLogPath)
 	default:
 		return nil, fmt.Errorf("unknown base: %v", fe.Base)
 	}
@@ -110,6 +117,33 @@ type BackupManifest struct {
 
 	// Position is the position at which the backup was taken
 	Position replication.Position
+
+	// Time is the time at which the backup was taken. It is not
+	// set for backups taken by older versions.
+	Time time.Time
+
+	// Incremental is true if the backup only contains the binlog
+	// files needed to go from FromPosition to Position, instead of
+	// a copy of the data files.
+	Incremental bool
+
+	// FromPosition is the Position of the backup an incremental
+	// backup was taken on top of.
+	FromPosition replication.Position
+
+	// Compression is the name of the BackupCompressor the files
+	// were compressed with. Backups taken by older versions don't
+	// have it, and were compressed with gzip.
+	Compression string
+
+	// EncryptionKeyID identifies the key EncryptedKey is encrypted
+	// with. It is empty if the backup is not encrypted.
+	EncryptionKeyID string
+
+	// EncryptedKey is the key the files are encrypted with, itself
+	// encrypted with the key from -backup_encryption_key_file.
+	// It is empty if the backup is not encrypted.
+	EncryptedKey []byte
 }
 
 // isDbDir returns true if the given directory contains a DB
@@ -185,7 +219,9 @@ func findFilesTobackup(cnf *Mycnf) ([]FileEntry, error) {
 // - uses the BackupStorage service to store a new backup
 // - shuts down Mysqld during the backup
 // - remember if we were replicating, restore the exact same state
-func Backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, dir, name string, backupConcurrency int, hookExtraEnv map[string]string) error {
+// If incremental is set, Mysqld keeps running, and only the binlogs
+// since the most recent backup are stored.
+func Backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, dir, name string, backupConcurrency int, hookExtraEnv map[string]string, incremental bool) error {
 
 	// start the backup with the BackupStorage
 	bs, err := backupstorage.GetBackupStorage()
@@ -193,12 +229,37 @@ func Backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, dir,
 		return err
 	}
 	defer bs.Close()
+
+	// an incremental backup is taken on top of the most recent one
+	var previous *BackupManifest
+	if incremental {
+		bhs, err := bs.ListBackups(dir)
+		if err != nil {
+			return fmt.Errorf("ListBackups failed: %v", err)
+		}
+		for i := len(bhs) - 1; i >= 0; i-- {
+			if previous, err = readManifest(bhs[i]); err == nil {
+				logger.Infof("taking incremental backup on top of backup %v", bhs[i].Name())
+				break
+			}
+			logger.Warningf("skipping possibly incomplete backup %v: %v", bhs[i].Name(), err)
+		}
+		if previous == nil {
+			return fmt.Errorf("no backup in %v to take an incremental backup on top of", dir)
+		}
+	}
+
 	bh, err := bs.StartBackup(dir, name)
 	if err != nil {
 		return fmt.Errorf("StartBackup failed: %v", err)
 	}
 
-	if err = backup(ctx, mysqld, logger, bh, backupConcurrency, hookExtraEnv); err != nil {
+	if incremental {
+		err = backupIncremental(ctx, mysqld, logger, bh, previous, backupConcurrency)
+	} else {
+		err = backup(ctx, mysqld, logger, bh, backupConcurrency, hookExtraEnv)
+	}
+	if err != nil {
 		if abortErr := bh.AbortBackup(); abortErr != nil {
 			logger.Errorf("failed to abort backup: %v", abortErr)
 		}
@@ -236,6 +297,7 @@ func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh b
 	}
 
 	// get the replication position
+	backupTime := time.Now()
 	if sourceIsMaster {
 		if !readOnly {
 			logger.Infof("turning master read-only before backup")
@@ -274,7 +336,12 @@ func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh b
 	logger.Infof("found %v files to backup", len(fes))
 
 	// backup everything
-	if err := backupFiles(mysqld, logger, bh, fes, replicationPosition, backupConcurrency); err != nil {
+	bm := &BackupManifest{
+		FileEntries: fes,
+		Position:    replicationPosition,
+		Time:        backupTime,
+	}
+	if err := backupFiles(mysqld, logger, bh, bm, backupConcurrency); err != nil {
 		return fmt.Errorf("can't backup files: %v", err)
 	}
 
@@ -316,7 +383,20 @@ func backup(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh b
 	return nil
 }
 
-func backupFiles(mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, fes []FileEntry, replicationPosition replication.Position, backupConcurrency int) (err error) {
+// backupFiles copies all the files of the manifest to the
+// BackupStorage, and then writes the MANIFEST.
+func backupFiles(mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, bm *BackupManifest, backupConcurrency int) (err error) {
+	bc, err := newBackupCodec(bm)
+	if err != nil {
+		return err
+	}
+	if bm.EncryptedKey != nil {
+		logger.Infof("compressing backup with %v, and encrypting it with key %v", bm.Compression, bm.EncryptionKeyID)
+	} else {
+		logger.Infof("compressing backup with %v", bm.Compression)
+	}
+
+	fes := bm.FileEntries
 	sema := sync2.NewSemaphore(backupConcurrency, 0)
 	rec := concurrency.AllErrorRecorder{}
 	wg := sync.WaitGroup{}
@@ -355,23 +435,23 @@ func backupFiles(mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.Bac
 			hasher := newHasher()
 			tee := io.MultiWriter(dst, hasher)
 
-			// create the gzip compression filter
-			gzip, err := cgzip.NewWriterLevel(tee, cgzip.Z_BEST_SPEED)
+			// create the compression and encryption filter
+			codec, err := bc.newWriter(tee)
 			if err != nil {
-				rec.RecordError(fmt.Errorf("cannot create gziper: %v", err))
+				rec.RecordError(fmt.Errorf("cannot create compressor: %v", err))
 				return
 			}
 
-			// copy from the source file to gzip to tee to output file and hasher
-			_, err = io.Copy(gzip, source)
+			// copy from the source file to codec to tee to output file and hasher
+			_, err = io.Copy(codec, source)
 			if err != nil {
 				rec.RecordError(fmt.Errorf("cannot copy data: %v", err))
 				return
 			}
 
-			// close gzip to flush it, after that the hash is good
-			if err = gzip.Close(); err != nil {
-				rec.RecordError(fmt.Errorf("cannot close gzip: %v", err))
+			// close codec to flush it, after that the hash is good
+			if err = codec.Close(); err != nil {
+				rec.RecordError(fmt.Errorf("cannot close compressor: %v", err))
 				return
 			}
 
@@ -398,10 +478,6 @@ func backupFiles(mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.Bac
 	}()
 
 	// JSON-encode and write the MANIFEST
-	bm := &BackupManifest{
-		FileEntries: fes,
-		Position:    replicationPosition,
-	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
 		return fmt.Errorf("cannot JSON encode %v: %v", backupManifest, err)
@@ -452,7 +528,13 @@ func checkNoDB(ctx context.Context, mysqld MysqlDaemon, dbName string) (bool, er
 
 // restoreFiles will copy all the files from the BackupStorage to the
 // right place
-func restoreFiles(cnf *Mycnf, bh backupstorage.BackupHandle, fes []FileEntry, restoreConcurrency int) error {
+func restoreFiles(cnf *Mycnf, bh backupstorage.BackupHandle, bm *BackupManifest, restoreConcurrency int) error {
+	bc, err := backupCodecFromManifest(bm)
+	if err != nil {
+		return err
+	}
+
+	fes := bm.FileEntries
 	sema := sync2.NewSemaphore(restoreConcurrency, 0)
 	rec := concurrency.AllErrorRecorder{}
 	wg := sync.WaitGroup{}
@@ -493,19 +575,19 @@ func restoreFiles(cnf *Mycnf, bh backupstorage.BackupHandle, fes []FileEntry, re
 			hasher := newHasher()
 
 			// create a Tee: we split the input into the hasher
-			// and into the gunziper
+			// and into the decrypter and uncompresser
 			tee := io.TeeReader(source, hasher)
 
-			// create the uncompresser
-			gz, err := cgzip.NewReader(tee)
+			// create the decrypter and uncompresser
+			codec, err := bc.newReader(tee)
 			if err != nil {
 				rec.RecordError(err)
 				return
 			}
-			defer func() { rec.RecordError(gz.Close()) }()
+			defer func() { rec.RecordError(codec.Close()) }()
 
 			// copy the data. Will also write to the hasher
-			if _, err = io.Copy(dst, gz); err != nil {
+			if _, err = io.Copy(dst, codec); err != nil {
 				rec.RecordError(err)
 				return
 			}
@@ -577,6 +659,9 @@ func removeExistingFiles(cnf *Mycnf) error {
 // Restore is the main entry point for backup restore.  If there is no
 // appropriate backup on the BackupStorage, Restore logs an error
 // and returns ErrNoBackup. Any other error is returned.
+// If target is not zero, the most recent full backup taken before the
+// target is restored, and the binlogs from the following incremental
+// backups are replayed up to the target.
 func Restore(
 	ctx context.Context,
 	mysqld MysqlDaemon,
@@ -586,7 +671,8 @@ func Restore(
 	localMetadata map[string]string,
 	logger logutil.Logger,
 	deleteBeforeRestore bool,
-	dbName string) (replication.Position, error) {
+	dbName string,
+	target RestoreTarget) (replication.Position, error) {
 
 	// find the right backup handle: most recent one, with a MANIFEST
 	logger.Infof("Restore: looking for a suitable backup to restore")
@@ -611,20 +697,21 @@ func Restore(
 	}
 
 	var bh backupstorage.BackupHandle
-	var bm BackupManifest
+	var bm *BackupManifest
 	var toRestore int
 	for toRestore = len(bhs) - 1; toRestore >= 0; toRestore-- {
 		bh = bhs[toRestore]
-		rc, err := bh.ReadFile(backupManifest)
+		bm, err = readManifest(bh)
 		if err != nil {
-			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v)", bh.Name(), dir, err)
+			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: %v", bh.Name(), dir, err)
 			continue
 		}
-
-		err = json.NewDecoder(rc).Decode(&bm)
-		rc.Close()
-		if err != nil {
-			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage (cannot JSON decode MANIFEST: %v)", bh.Name(), dir, err)
+		if bm.Incremental {
+			// Incremental backups are replayed on top of a full one.
+			continue
+		}
+		if !target.isAfter(bm) {
+			logger.Infof("Restore: skipping backup %v %v taken after %v", bh.Directory(), bh.Name(), target)
 			continue
 		}
 
@@ -632,6 +719,9 @@ func Restore(
 		break
 	}
 	if toRestore < 0 {
+		if !target.IsZero() {
+			return replication.Position{}, fmt.Errorf("no backup taken before %v", target)
+		}
 		// There is at least one attempted backup, but none could be read.
 		// This implies there is data we ought to have, so it's not safe to start
 		// up empty.
@@ -671,7 +761,7 @@ func Restore(
 	}
 
 	logger.Infof("Restore: copying all files")
-	if err := restoreFiles(mysqld.Cnf(), bh, bm.FileEntries, restoreConcurrency); err != nil {
+	if err := restoreFiles(mysqld.Cnf(), bh, bm, restoreConcurrency); err != nil {
 		return replication.Position{}, err
 	}
 
@@ -713,5 +803,23 @@ func Restore(
 		return replication.Position{}, err
 	}
 
+	if !target.IsZero() && !target.isReachedBy(bm) {
+		return restoreIncrementalBackups(ctx, mysqld, logger, bhs[toRestore+1:], bm.Position, target, restoreConcurrency)
+	}
 	return bm.Position, nil
 }
+
+// readManifest reads and decodes the MANIFEST of a backup.
+func readManifest(bh backupstorage.BackupHandle) (*BackupManifest, error) {
+	rc, err := bh.ReadFile(backupManifest)
+	if err != nil {
+		return nil, fmt.Errorf("can't read MANIFEST: %v", err)
+	}
+	defer rc.Close()
+
+	bm := &BackupManifest{}
+	if err := json.NewDecoder(rc).Decode(bm); err != nil {
+		return nil, fmt.Errorf("cannot JSON decode MANIFEST: %v", err)
+	}
+	return bm, nil
+}
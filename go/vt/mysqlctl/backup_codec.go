@@ -0,0 +1,212 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+	"github.com/youtube/vitess/go/cgzip"
+)
+
+// This file handles how the backup files are compressed and encrypted
+// before they are stored on the BackupStorage.
+
+var (
+	backupCompression       = flag.String("backup_compression", "gzip", "compression engine to use for new backups: gzip, snappy (faster, but compresses less), none, or another registered BackupCompressorMap entry")
+	backupEncryptionKeyFile = flag.String("backup_encryption_key_file", "", "if set, new backups are encrypted, with a key itself encrypted with the hex-encoded AES-256 key in this file. It is also needed to restore encrypted backups")
+)
+
+const (
+	// defaultBackupCompression is the compression engine of the
+	// backups that don't record one in their MANIFEST.
+	defaultBackupCompression = "gzip"
+)
+
+// BackupCompressor compresses and decompresses backup files.
+type BackupCompressor interface {
+	// NewWriter returns a WriteCloser that compresses what is
+	// written to it into w. Close must flush all the data to w,
+	// but not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+
+	// NewReader returns a ReadCloser that decompresses what is
+	// read from r. Close must not close r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// BackupCompressorMap contains the registered compression engines.
+// Other engines can be added by registering them in this map, in an
+// init function of a package linked into vttablet.
+var BackupCompressorMap = make(map[string]BackupCompressor)
+
+// gzipCompressor uses cgzip, tuned for speed.
+type gzipCompressor struct{}
+
+// NewWriter is part of the BackupCompressor interface.
+func (gzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return cgzip.NewWriterLevel(w, cgzip.Z_BEST_SPEED)
+}
+
+// NewReader is part of the BackupCompressor interface.
+func (gzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return cgzip.NewReader(r)
+}
+
+// snappyCompressor uses the snappy framing format. It is a lot
+// faster than gzip, at the cost of bigger backups.
+type snappyCompressor struct{}
+
+// NewWriter is part of the BackupCompressor interface.
+func (snappyCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+// NewReader is part of the BackupCompressor interface.
+func (snappyCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(snappy.NewReader(r)), nil
+}
+
+// noCompressor stores the files as they are, for data that
+// doesn't compress well.
+type noCompressor struct{}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// NewWriter is part of the BackupCompressor interface.
+func (noCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+// NewReader is part of the BackupCompressor interface.
+func (noCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(r), nil
+}
+
+func init() {
+	BackupCompressorMap["gzip"] = gzipCompressor{}
+	BackupCompressorMap["snappy"] = snappyCompressor{}
+	BackupCompressorMap["none"] = noCompressor{}
+}
+
+// backupCodec compresses, and optionally encrypts, the files of a
+// backup.
+type backupCodec struct {
+	compressor BackupCompressor
+
+	// key is the key the files are encrypted with, nil if the
+	// backup is not encrypted.
+	key []byte
+}
+
+// newBackupCodec returns the codec for a new backup, using the
+// command line flags, and records it in the manifest.
+func newBackupCodec(bm *BackupManifest) (*backupCodec, error) {
+	compressor, ok := BackupCompressorMap[*backupCompression]
+	if !ok {
+		return nil, fmt.Errorf("unknown backup compression engine: %v", *backupCompression)
+	}
+	bm.Compression = *backupCompression
+	bc := &backupCodec{
+		compressor: compressor,
+	}
+
+	if *backupEncryptionKeyFile != "" {
+		masterKey, keyID, err := readBackupEncryptionKey(*backupEncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		bc.key, bm.EncryptedKey, err = newDataKey(masterKey)
+		if err != nil {
+			return nil, err
+		}
+		bm.EncryptionKeyID = keyID
+	}
+	return bc, nil
+}
+
+// backupCodecFromManifest returns the codec to restore a backup.
+func backupCodecFromManifest(bm *BackupManifest) (*backupCodec, error) {
+	name := bm.Compression
+	if name == "" {
+		name = defaultBackupCompression
+	}
+	compressor, ok := BackupCompressorMap[name]
+	if !ok {
+		return nil, fmt.Errorf("backup was compressed with unknown engine: %v", name)
+	}
+	bc := &backupCodec{
+		compressor: compressor,
+	}
+
+	if bm.EncryptedKey != nil {
+		if *backupEncryptionKeyFile == "" {
+			return nil, fmt.Errorf("backup is encrypted with key %v, but -backup_encryption_key_file is not set", bm.EncryptionKeyID)
+		}
+		masterKey, keyID, err := readBackupEncryptionKey(*backupEncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if keyID != bm.EncryptionKeyID {
+			return nil, fmt.Errorf("backup is encrypted with key %v, but -backup_encryption_key_file has key %v", bm.EncryptionKeyID, keyID)
+		}
+		if bc.key, err = openDataKey(masterKey, bm.EncryptedKey); err != nil {
+			return nil, err
+		}
+	}
+	return bc, nil
+}
+
+// newWriter returns a WriteCloser that compresses, then encrypts,
+// into w. Close flushes all the data to w, but doesn't close it.
+func (bc *backupCodec) newWriter(w io.Writer) (io.WriteCloser, error) {
+	if bc.key == nil {
+		return bc.compressor.NewWriter(w)
+	}
+	ew, err := newEncryptWriter(w, bc.key)
+	if err != nil {
+		return nil, err
+	}
+	cw, err := bc.compressor.NewWriter(ew)
+	if err != nil {
+		return nil, err
+	}
+	return &chainWriteCloser{cw, ew}, nil
+}
+
+// newReader returns a ReadCloser that decrypts, then decompresses,
+// from r.
+func (bc *backupCodec) newReader(r io.Reader) (io.ReadCloser, error) {
+	if bc.key != nil {
+		var err error
+		if r, err = newDecryptReader(r, bc.key); err != nil {
+			return nil, err
+		}
+	}
+	return bc.compressor.NewReader(r)
+}
+
+// chainWriteCloser writes to the first WriteCloser, and closes them
+// all in order.
+type chainWriteCloser struct {
+	io.WriteCloser
+	next io.WriteCloser
+}
+
+func (c *chainWriteCloser) Close() error {
+	if err := c.WriteCloser.Close(); err != nil {
+		return err
+	}
+	return c.next.Close()
+}
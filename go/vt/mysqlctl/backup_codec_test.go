@@ -0,0 +1,224 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+// setBackupCodecFlags sets the codec flags, and returns a function
+// to restore them.
+func setBackupCodecFlags(compression, keyFile string) func() {
+	oldCompression, oldKeyFile := *backupCompression, *backupEncryptionKeyFile
+	*backupCompression, *backupEncryptionKeyFile = compression, keyFile
+	return func() {
+		*backupCompression, *backupEncryptionKeyFile = oldCompression, oldKeyFile
+	}
+}
+
+func writeKeyFile(t *testing.T, dir, name, key string) string {
+	file := path.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(key+"\n"), 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	return file
+}
+
+func encodeWithCodec(t *testing.T, bc *backupCodec, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w, err := bc.newWriter(buf)
+	if err != nil {
+		t.Fatalf("newWriter failed: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decodeWithCodec(bc *backupCodec, data []byte) ([]byte, error) {
+	r, err := bc.newReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func TestBackupCodecRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backupcodectest")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := writeKeyFile(t, dir, "key", strings.Repeat("0123456789abcdef", 4))
+
+	sizes := []int{0, 1, 1000, encryptChunkSize, encryptChunkSize + 1, 3*encryptChunkSize + 5}
+	for _, compression := range []string{"gzip", "snappy", "none"} {
+		for _, kf := range []string{"", keyFile} {
+			restore := setBackupCodecFlags(compression, kf)
+			for _, size := range sizes {
+				data := make([]byte, size)
+				rand.Read(data)
+
+				bm := &BackupManifest{}
+				bc, err := newBackupCodec(bm)
+				if err != nil {
+					t.Fatalf("newBackupCodec(%v, %q) failed: %v", compression, kf, err)
+				}
+				if bm.Compression != compression {
+					t.Errorf("bm.Compression = %v, want %v", bm.Compression, compression)
+				}
+				if got := bm.EncryptedKey != nil; got != (kf != "") {
+					t.Errorf("encrypted = %v with key file %q", got, kf)
+				}
+				encoded := encodeWithCodec(t, bc, data)
+
+				bc, err = backupCodecFromManifest(bm)
+				if err != nil {
+					t.Fatalf("backupCodecFromManifest(%v, %q) failed: %v", compression, kf, err)
+				}
+				got, err := decodeWithCodec(bc, encoded)
+				if err != nil {
+					t.Errorf("decode(%v, %q, %v bytes) failed: %v", compression, kf, size, err)
+					continue
+				}
+				if !bytes.Equal(got, data) {
+					t.Errorf("decode(%v, %q, %v bytes) returned different data", compression, kf, size)
+				}
+			}
+			restore()
+		}
+	}
+}
+
+func TestBackupCodecOldManifest(t *testing.T) {
+	defer setBackupCodecFlags("gzip", "")()
+
+	// Backups taken by older versions don't record their
+	// compression, and were compressed with gzip.
+	bc, err := newBackupCodec(&BackupManifest{})
+	if err != nil {
+		t.Fatalf("newBackupCodec failed: %v", err)
+	}
+	data := []byte("old backup contents")
+	encoded := encodeWithCodec(t, bc, data)
+
+	bc, err = backupCodecFromManifest(&BackupManifest{})
+	if err != nil {
+		t.Fatalf("backupCodecFromManifest failed: %v", err)
+	}
+	got, err := decodeWithCodec(bc, encoded)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("decode = %q, %v, want %q", got, err, data)
+	}
+}
+
+func TestBackupCodecErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backupcodectest")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := writeKeyFile(t, dir, "key", strings.Repeat("0123456789abcdef", 4))
+	otherKeyFile := writeKeyFile(t, dir, "other", strings.Repeat("fedcba9876543210", 4))
+	shortKeyFile := writeKeyFile(t, dir, "short", "0123456789abcdef")
+	badKeyFile := writeKeyFile(t, dir, "bad", "not hex")
+
+	defer setBackupCodecFlags("gzip", "")()
+	*backupCompression = "unknown"
+	if _, err := newBackupCodec(&BackupManifest{}); err == nil || !strings.Contains(err.Error(), "unknown backup compression engine") {
+		t.Errorf("newBackupCodec with unknown compression: %v", err)
+	}
+	if _, err := backupCodecFromManifest(&BackupManifest{Compression: "unknown"}); err == nil || !strings.Contains(err.Error(), "unknown engine") {
+		t.Errorf("backupCodecFromManifest with unknown compression: %v", err)
+	}
+	*backupCompression = "gzip"
+
+	for _, kf := range []string{path.Join(dir, "missing"), shortKeyFile, badKeyFile} {
+		*backupEncryptionKeyFile = kf
+		if _, err := newBackupCodec(&BackupManifest{}); err == nil {
+			t.Errorf("newBackupCodec with key file %v worked", kf)
+		}
+	}
+
+	*backupEncryptionKeyFile = keyFile
+	bm := &BackupManifest{}
+	if _, err := newBackupCodec(bm); err != nil {
+		t.Fatalf("newBackupCodec failed: %v", err)
+	}
+
+	*backupEncryptionKeyFile = ""
+	if _, err := backupCodecFromManifest(bm); err == nil || !strings.Contains(err.Error(), "-backup_encryption_key_file is not set") {
+		t.Errorf("backupCodecFromManifest without key file: %v", err)
+	}
+	*backupEncryptionKeyFile = otherKeyFile
+	if _, err := backupCodecFromManifest(bm); err == nil || !strings.Contains(err.Error(), "but -backup_encryption_key_file has key") {
+		t.Errorf("backupCodecFromManifest with other key file: %v", err)
+	}
+}
+
+func TestDecryptReaderTampering(t *testing.T) {
+	key := make([]byte, encryptKeySize)
+	bc := &backupCodec{
+		compressor: noCompressor{},
+		key:        key,
+	}
+	data := make([]byte, 2*encryptChunkSize+10)
+	rand.Read(data)
+	encoded := encodeWithCodec(t, bc, data)
+	fullChunk := encryptChunkSize + 16
+
+	testcases := []struct {
+		name    string
+		encoded []byte
+		err     string
+	}{{
+		name:    "truncated after a chunk",
+		encoded: encoded[:encryptNoncePrefixSize+fullChunk],
+		err:     "cannot decrypt chunk 0",
+	}, {
+		name:    "truncated last chunk",
+		encoded: encoded[:len(encoded)-1],
+		err:     "cannot decrypt chunk 2",
+	}, {
+		name:    "missing last chunk",
+		encoded: encoded[:encryptNoncePrefixSize+2*fullChunk],
+		err:     "cannot decrypt chunk 1",
+	}, {
+		name:    "header only",
+		encoded: encoded[:encryptNoncePrefixSize],
+		err:     "truncated after chunk 0",
+	}, {
+		name: "modified",
+		encoded: func() []byte {
+			modified := append([]byte(nil), encoded...)
+			modified[encryptNoncePrefixSize+fullChunk+5] ^= 1
+			return modified
+		}(),
+		err: "cannot decrypt chunk 1",
+	}}
+	for _, tcase := range testcases {
+		_, err := decodeWithCodec(bc, tcase.encoded)
+		if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("%v: %v, want %v", tcase.name, err, tcase.err)
+		}
+	}
+
+	// A different key can't decrypt it.
+	bc.key = bytes.Repeat([]byte{1}, encryptKeySize)
+	if _, err := decodeWithCodec(bc, encoded); err == nil {
+		t.Errorf("decrypting with a different key worked")
+	}
+}
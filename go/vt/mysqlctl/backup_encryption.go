@@ -0,0 +1,269 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// This file handles the encryption of backup files.
+//
+// It uses envelope encryption: each backup has its own random data
+// key, which encrypts its files. The data key is stored in the
+// MANIFEST, encrypted with the master key from the key file. So the
+// master key never encrypts a lot of data, and can be rotated
+// without re-encrypting the backups, by re-encrypting their data
+// keys.
+//
+// The files are encrypted with AES-256-GCM, in chunks of
+// encryptChunkSize bytes, so they can be streamed. A file starts
+// with a random nonce prefix. The nonce of a chunk is made of that
+// prefix, the index of the chunk, and a flag that is set for the last
+// chunk, so chunks can't be reordered, and the file can't be
+// truncated, without the decryption failing.
+
+const (
+	// encryptKeySize is the size of the AES-256 keys.
+	encryptKeySize = 32
+
+	// encryptChunkSize is the size of the plaintext of a chunk.
+	encryptChunkSize = 64 * 1024
+
+	// encryptNoncePrefixSize is the size of the random nonce
+	// prefix at the beginning of a file. The rest of the 12 bytes
+	// nonce is made of the chunk index (4 bytes) and the last
+	// chunk flag (1 byte).
+	encryptNoncePrefixSize = 7
+)
+
+// readBackupEncryptionKey reads the master key from a file. It
+// returns the key, and its ID to record in the MANIFEST.
+func readBackupEncryptionKey(file string) ([]byte, string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read backup encryption key: %v", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot decode backup encryption key from %v: %v", file, err)
+	}
+	if len(key) != encryptKeySize {
+		return nil, "", fmt.Errorf("backup encryption key in %v has %v bytes, expected %v", file, len(key), encryptKeySize)
+	}
+	// The ID is a truncated hash of the key, it only has to
+	// tell keys apart.
+	sum := sha256.Sum256(key)
+	return key, hex.EncodeToString(sum[:8]), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newDataKey returns a random data key, and its encrypted version
+// to store in the MANIFEST.
+func newDataKey(masterKey []byte) ([]byte, []byte, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := make([]byte, encryptKeySize)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, err
+	}
+	return key, aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// openDataKey decrypts a data key encrypted by newDataKey.
+func openDataKey(masterKey, encryptedKey []byte) ([]byte, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted backup key is too short")
+	}
+	nonce := encryptedKey[:aead.NonceSize()]
+	key, err := aead.Open(nil, nonce, encryptedKey[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt backup key: %v", err)
+	}
+	return key, nil
+}
+
+// chunkNonce sets the chunk index and the last chunk flag of a nonce.
+func chunkNonce(nonce []byte, index uint32, last bool) {
+	binary.BigEndian.PutUint32(nonce[encryptNoncePrefixSize:], index)
+	nonce[encryptNoncePrefixSize+4] = 0
+	if last {
+		nonce[encryptNoncePrefixSize+4] = 1
+	}
+}
+
+// encryptWriter encrypts what is written to it, chunk by chunk.
+type encryptWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	nonce []byte
+	index uint32
+
+	// buf has the plaintext of the current chunk. A full chunk
+	// is only written when more data comes, as it may be the last.
+	buf []byte
+	out []byte
+}
+
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce[:encryptNoncePrefixSize]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(nonce[:encryptNoncePrefixSize]); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:     w,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, 0, encryptChunkSize),
+		out:   make([]byte, 0, encryptChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (ew *encryptWriter) writeChunk(last bool) error {
+	if ew.index == 1<<32-1 {
+		return fmt.Errorf("file is too big to be encrypted")
+	}
+	chunkNonce(ew.nonce, ew.index, last)
+	ew.out = ew.aead.Seal(ew.out[:0], ew.nonce, ew.buf, nil)
+	if _, err := ew.w.Write(ew.out); err != nil {
+		return err
+	}
+	ew.index++
+	ew.buf = ew.buf[:0]
+	return nil
+}
+
+// Write is part of the io.Writer interface.
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if len(ew.buf) == encryptChunkSize {
+			if err := ew.writeChunk(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(ew.buf[len(ew.buf):encryptChunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close writes the last chunk. It doesn't close the underlying
+// writer.
+func (ew *encryptWriter) Close() error {
+	return ew.writeChunk(true)
+}
+
+// decryptReader decrypts what is read from it, chunk by chunk.
+type decryptReader struct {
+	r     *bufio.Reader
+	aead  cipher.AEAD
+	nonce []byte
+	index uint32
+	done  bool
+
+	// in is the current encrypted chunk, plain its plaintext,
+	// of which pos bytes were already read.
+	in    []byte
+	plain []byte
+	pos   int
+}
+
+func newDecryptReader(r io.Reader, key []byte) (*decryptReader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(r, nonce[:encryptNoncePrefixSize]); err != nil {
+		return nil, fmt.Errorf("cannot read encrypted file header: %v", err)
+	}
+	return &decryptReader{
+		r:     bufio.NewReader(r),
+		aead:  aead,
+		nonce: nonce,
+		in:    make([]byte, encryptChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (dr *decryptReader) readChunk() error {
+	n, err := io.ReadFull(dr.r, dr.in)
+	last := false
+	switch err {
+	case nil:
+		// A full chunk is the last one if nothing follows it.
+		if _, err := dr.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return fmt.Errorf("encrypted file is truncated after chunk %v", dr.index)
+	default:
+		return err
+	}
+
+	chunkNonce(dr.nonce, dr.index, last)
+	dr.plain, err = dr.aead.Open(dr.plain[:0], dr.nonce, dr.in[:n], nil)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt chunk %v of encrypted file: %v", dr.index, err)
+	}
+	dr.pos = 0
+	dr.index++
+	dr.done = last
+	return nil
+}
+
+// Read is part of the io.Reader interface.
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for dr.pos == len(dr.plain) {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.plain[dr.pos:])
+	dr.pos += n
+	return n, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+)
+
+// This file handles incremental backups, and point-in-time restores.
+//
+// An incremental backup doesn't copy the data files. It flushes the
+// binlogs, and stores all the closed binlog files written since the
+// previous backup, full or incremental. The binlogs have to be kept on
+// the server for longer than the interval between two incremental
+// backups.
+//
+// A point-in-time restore restores the most recent full backup taken
+// before the target, and then replays the binlogs of the incremental
+// backups taken after it, until the target is reached.
+
+// RestoreTarget is the point in time a restore replays binlogs up to.
+// At most one of its fields should be set. The zero value restores the
+// most recent full backup, without replaying any binlog.
+type RestoreTarget struct {
+	// Position is the replication position to restore up to,
+	// included.
+	Position replication.Position
+
+	// Time is the time to restore up to, excluded.
+	Time time.Time
+}
+
+// IsZero returns true if the RestoreTarget is not set.
+func (t RestoreTarget) IsZero() bool {
+	return t.Position.IsZero() && t.Time.IsZero()
+}
+
+// String returns a human readable version of the target.
+func (t RestoreTarget) String() string {
+	switch {
+	case !t.Position.IsZero():
+		return fmt.Sprintf("position %v", t.Position)
+	case !t.Time.IsZero():
+		return fmt.Sprintf("time %v", t.Time.UTC().Format(time.RFC3339))
+	}
+	return "latest backup"
+}
+
+// isAfter returns true if the backup was taken before the target, and
+// can be used as the starting point of the restore.
+func (t RestoreTarget) isAfter(bm *BackupManifest) bool {
+	if !t.Position.IsZero() && !t.Position.AtLeast(bm.Position) {
+		return false
+	}
+	if !t.Time.IsZero() && bm.Time.After(t.Time) {
+		return false
+	}
+	return true
+}
+
+// isReachedBy returns true if replaying the binlogs of the backup
+// is enough to reach the target.
+func (t RestoreTarget) isReachedBy(bm *BackupManifest) bool {
+	if !t.Position.IsZero() {
+		return bm.Position.AtLeast(t.Position)
+	}
+	return !bm.Time.Before(t.Time)
+}
+
+// backupIncremental stores the binlogs since the previous backup.
+func backupIncremental(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bh backupstorage.BackupHandle, previous *BackupManifest, backupConcurrency int) error {
+	// The position is read before the binlogs are flushed, so the
+	// closed binlog files contain at least all the transactions up
+	// to it.
+	backupTime := time.Now()
+	replicationPosition, err := mysqld.MasterPosition()
+	if err != nil {
+		return fmt.Errorf("can't get replication position: %v", err)
+	}
+	logger.Infof("using replication position: %v", replicationPosition)
+	if previous.Position.AtLeast(replicationPosition) {
+		return fmt.Errorf("no new transaction since the previous backup at %v", previous.Position)
+	}
+
+	if err := mysqld.ExecuteSuperQueryList(ctx, []string{"FLUSH BINARY LOGS"}); err != nil {
+		return fmt.Errorf("can't flush binary logs: %v", err)
+	}
+	qr, err := mysqld.FetchSuperQuery(ctx, "SHOW BINARY LOGS")
+	if err != nil {
+		return fmt.Errorf("can't list binary logs: %v", err)
+	}
+	if len(qr.Rows) == 0 {
+		return fmt.Errorf("no binary logs found, is binary logging enabled?")
+	}
+
+	// The last file is the one mysqld is writing to. The previous
+	// backup may come from another tablet, with other binlog file
+	// names, so the first file to store is found by GTID.
+	closed := qr.Rows[:len(qr.Rows)-1]
+	start, err := firstBinlogAfter(ctx, mysqld, closed, previous.Position)
+	if err != nil {
+		return err
+	}
+	var fes []FileEntry
+	for _, row := range closed[start:] {
+		fes = append(fes, FileEntry{
+			Base: backupBinlog,
+			Name: row[0].String(),
+		})
+	}
+	logger.Infof("found %v binlog files to backup", len(fes))
+
+	bm := &BackupManifest{
+		FileEntries:  fes,
+		Position:     replicationPosition,
+		Time:         backupTime,
+		Incremental:  true,
+		FromPosition: previous.Position,
+	}
+	if err := backupFiles(mysqld, logger, bh, bm, backupConcurrency); err != nil {
+		return fmt.Errorf("can't backup files: %v", err)
+	}
+	return nil
+}
+
+// firstBinlogAfter returns the index of the first binlog file that
+// contains transactions after pos. Each binlog file starts with the set
+// of the GTIDs of the files before it, so it's the last file whose
+// previous GTIDs are all in pos.
+func firstBinlogAfter(ctx context.Context, mysqld MysqlDaemon, binlogs [][]sqltypes.Value, pos replication.Position) (int, error) {
+	if pos.IsZero() {
+		return 0, nil
+	}
+	for i := len(binlogs) - 1; i >= 0; i-- {
+		name := binlogs[i][0].String()
+		previous, err := previousGTIDs(ctx, mysqld, name, pos.GTIDSet.Flavor())
+		if err != nil {
+			return 0, fmt.Errorf("can't get the previous GTIDs of binlog file %v: %v", name, err)
+		}
+		if previous.IsZero() || pos.AtLeast(previous) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("binlogs needed since the previous backup at %v are not on the server anymore", pos)
+}
+
+// previousGTIDs returns the set of the GTIDs written before a binlog
+// file, as stored in its Previous_gtids event with MySQL, or its
+// Gtid_list event with MariaDB. It's the second event of the file,
+// after the Format_desc event.
+func previousGTIDs(ctx context.Context, mysqld MysqlDaemon, name, flavor string) (replication.Position, error) {
+	qr, err := mysqld.FetchSuperQuery(ctx, fmt.Sprintf("SHOW BINLOG EVENTS IN '%v' LIMIT 2", name))
+	if err != nil {
+		return replication.Position{}, err
+	}
+	for _, row := range qr.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		switch row[2].String() {
+		case "Previous_gtids", "Gtid_list":
+			// MariaDB prints the list within brackets.
+			info := strings.Trim(strings.TrimSpace(row[5].String()), "[]")
+			if info == "" {
+				return replication.Position{}, nil
+			}
+			return replication.ParsePosition(flavor, info)
+		}
+	}
+	return replication.Position{}, fmt.Errorf("no Previous_gtids or Gtid_list event, is GTID mode enabled?")
+}
+
+// restoreIncrementalBackups replays the binlogs of the incremental
+// backups in bhs on top of a restored backup at pos, until the target
+// is reached. bhs is sorted by name, so the oldest backups come first.
+// It returns the position mysqld ends up at.
+func restoreIncrementalBackups(ctx context.Context, mysqld MysqlDaemon, logger logutil.Logger, bhs []backupstorage.BackupHandle, pos replication.Position, target RestoreTarget, restoreConcurrency int) (replication.Position, error) {
+	// The restored data files don't carry the replication position,
+	// which the replayed transactions are added to.
+	cmds, err := mysqld.SetSlavePositionCommands(pos)
+	if err != nil {
+		return pos, err
+	}
+	if err := mysqld.ExecuteSuperQueryList(ctx, cmds); err != nil {
+		return pos, fmt.Errorf("failed to set replication position: %v", err)
+	}
+
+	// The binlog files are copied to a temporary directory, and
+	// not to the directory mysqld writes its own binlogs to.
+	tmpDir, err := ioutil.TempDir(mysqld.Cnf().TmpDir, "restore_binlogs")
+	if err != nil {
+		return pos, fmt.Errorf("can't create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	cnf := *mysqld.Cnf()
+	cnf.BinLogPath = path.Join(tmpDir, "binlog")
+
+	for _, bh := range bhs {
+		bm, err := readManifest(bh)
+		if err != nil {
+			logger.Warningf("Restore: skipping possibly incomplete backup %v: %v", bh.Name(), err)
+			continue
+		}
+		if !bm.Incremental || pos.AtLeast(bm.Position) {
+			// Nothing new in there.
+			continue
+		}
+		if !pos.AtLeast(bm.FromPosition) {
+			return pos, fmt.Errorf("missing binlogs: incremental backup %v starts at %v, but restored data is at %v", bh.Name(), bm.FromPosition, pos)
+		}
+
+		logger.Infof("Restore: replaying incremental backup %v with %v binlog files", bh.Name(), len(bm.FileEntries))
+		if err := restoreFiles(&cnf, bh, bm, restoreConcurrency); err != nil {
+			return pos, err
+		}
+		for _, fe := range bm.FileEntries {
+			file := path.Join(tmpDir, fe.Name)
+			if err := mysqld.ApplyBinlogFile(ctx, file, pos, target.Position, target.Time); err != nil {
+				return pos, fmt.Errorf("can't apply binlog file %v from backup %v: %v", fe.Name, bh.Name(), err)
+			}
+			if err := os.Remove(file); err != nil {
+				return pos, err
+			}
+		}
+
+		if pos, err = mysqld.MasterPosition(); err != nil {
+			return pos, fmt.Errorf("can't get replication position: %v", err)
+		}
+		logger.Infof("Restore: replayed incremental backup %v, now at position %v", bh.Name(), pos)
+		if target.isReachedBy(bm) {
+			return pos, nil
+		}
+	}
+	return pos, fmt.Errorf("%v is after the last incremental backup, restored up to position %v", target, pos)
+}
@@ -0,0 +1,346 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
+	"github.com/youtube/vitess/go/vt/mysqlctl/filebackupstorage"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+)
+
+func testPosition(sequence uint64) replication.Position {
+	return replication.Position{
+		GTIDSet: replication.MariadbGTID{
+			Domain:   0,
+			Server:   1,
+			Sequence: sequence,
+		},
+	}
+}
+
+func TestRestoreTarget(t *testing.T) {
+	t0 := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+	bm := &BackupManifest{
+		Position: testPosition(10),
+		Time:     t0,
+	}
+
+	testcases := []struct {
+		target    RestoreTarget
+		str       string
+		isAfter   bool
+		isReached bool
+	}{{
+		target:    RestoreTarget{},
+		str:       "latest backup",
+		isAfter:   true,
+		isReached: true,
+	}, {
+		target:    RestoreTarget{Position: testPosition(5)},
+		str:       "position 0-1-5",
+		isAfter:   false,
+		isReached: true,
+	}, {
+		target:    RestoreTarget{Position: testPosition(10)},
+		str:       "position 0-1-10",
+		isAfter:   true,
+		isReached: true,
+	}, {
+		target:    RestoreTarget{Position: testPosition(15)},
+		str:       "position 0-1-15",
+		isAfter:   true,
+		isReached: false,
+	}, {
+		target:    RestoreTarget{Time: t0.Add(-time.Hour)},
+		str:       "time 2016-05-01T11:00:00Z",
+		isAfter:   false,
+		isReached: true,
+	}, {
+		target:    RestoreTarget{Time: t0.Add(time.Hour)},
+		str:       "time 2016-05-01T13:00:00Z",
+		isAfter:   true,
+		isReached: false,
+	}}
+	for _, tcase := range testcases {
+		if got := tcase.target.IsZero(); got != (tcase.str == "latest backup") {
+			t.Errorf("%v.IsZero(): %v", tcase.str, got)
+		}
+		if got := tcase.target.String(); got != tcase.str {
+			t.Errorf("String(): %v, want %v", got, tcase.str)
+		}
+		if got := tcase.target.isAfter(bm); got != tcase.isAfter {
+			t.Errorf("%v.isAfter(): %v, want %v", tcase.str, got, tcase.isAfter)
+		}
+		if tcase.target.IsZero() {
+			continue
+		}
+		if got := tcase.target.isReachedBy(bm); got != tcase.isReached {
+			t.Errorf("%v.isReachedBy(): %v, want %v", tcase.str, got, tcase.isReached)
+		}
+	}
+}
+
+func binaryLogsResult(names ...string) *sqltypes.Result {
+	qr := &sqltypes.Result{}
+	for _, name := range names {
+		qr.Rows = append(qr.Rows, []sqltypes.Value{
+			sqltypes.MakeString([]byte(name)),
+			sqltypes.MakeString([]byte("1024")),
+		})
+	}
+	return qr
+}
+
+// binlogEventsResult returns the first events of a binlog file
+// written after the transactions up to sequence.
+func binlogEventsResult(name string, sequence uint64) *sqltypes.Result {
+	row := func(pos, typ, info string) []sqltypes.Value {
+		return []sqltypes.Value{
+			sqltypes.MakeString([]byte(name)),
+			sqltypes.MakeString([]byte(pos)),
+			sqltypes.MakeString([]byte(typ)),
+			sqltypes.MakeString([]byte("1")),
+			sqltypes.MakeString([]byte("0")),
+			sqltypes.MakeString([]byte(info)),
+		}
+	}
+	return &sqltypes.Result{
+		Rows: [][]sqltypes.Value{
+			row("4", "Format_desc", "Server ver: 10.0.13-MariaDB-log, Binlog ver: 4"),
+			row("248", "Gtid_list", "["+testPosition(sequence).String()+"]"),
+		},
+	}
+}
+
+func TestIncrementalBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	logger := logutil.NewConsoleLogger()
+
+	root, err := ioutil.TempDir("", "backuptest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// Initialize BackupStorage
+	*filebackupstorage.FileBackupStorageRoot = path.Join(root, "fbs")
+	*backupstorage.BackupStorageImplementation = "file"
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		t.Fatalf("GetBackupStorage failed: %v", err)
+	}
+	defer bs.Close()
+	dir := "ks/0"
+
+	// Initialize the binlog directory
+	binlogDir := path.Join(root, "binlogs")
+	tmpDir := path.Join(root, "tmp")
+	for _, s := range []string{binlogDir, tmpDir} {
+		if err := os.MkdirAll(s, os.ModePerm); err != nil {
+			t.Fatalf("failed to create directory %v: %v", s, err)
+		}
+	}
+	// The other-bin files are the binlogs of another tablet of
+	// the shard.
+	for _, name := range []string{"vt-bin.000001", "vt-bin.000002", "vt-bin.000003", "other-bin.000011", "other-bin.000012", "other-bin.000013"} {
+		if err := ioutil.WriteFile(path.Join(binlogDir, name), []byte(name+" contents"), os.ModePerm); err != nil {
+			t.Fatalf("failed to write file %v: %v", name, err)
+		}
+	}
+	fmd := NewFakeMysqlDaemon(nil)
+	fmd.Mycnf = &Mycnf{
+		BinLogPath: path.Join(binlogDir, "vt-bin"),
+		TmpDir:     tmpDir,
+	}
+
+	// An incremental backup needs a previous backup.
+	if err := Backup(ctx, fmd, logger, dir, "0.incremental", 1, nil, true); err == nil || !strings.Contains(err.Error(), "no backup in ks/0") {
+		t.Errorf("Backup without previous backup: %v", err)
+	}
+
+	// Fake a full backup, without any file.
+	bh, err := bs.StartBackup(dir, "1.full")
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	if err := backupFiles(fmd, logger, bh, &BackupManifest{Position: testPosition(10)}, 1); err != nil {
+		t.Fatalf("backupFiles failed: %v", err)
+	}
+	if err := bh.EndBackup(); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+
+	// The first incremental backup stores the closed binlogs
+	// with transactions after the full backup.
+	fmd.CurrentMasterPosition = testPosition(20)
+	fmd.ExpectedExecuteSuperQueryList = []string{"FLUSH BINARY LOGS"}
+	fmd.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SHOW BINARY LOGS": binaryLogsResult("vt-bin.000001", "vt-bin.000002"),
+		"SHOW BINLOG EVENTS IN 'vt-bin.000001' LIMIT 2": binlogEventsResult("vt-bin.000001", 5),
+		"SHOW BINLOG EVENTS IN 'vt-bin.000002' LIMIT 2": binlogEventsResult("vt-bin.000002", 20),
+		"SHOW BINLOG EVENTS IN 'vt-bin.000003' LIMIT 2": binlogEventsResult("vt-bin.000003", 30),
+	}
+	if err := Backup(ctx, fmd, logger, dir, "2.incremental", 1, nil, true); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	// The second one starts at the binlog after the ones stored by
+	// the first one, even if the older binlogs are still there.
+	fmd.CurrentMasterPosition = testPosition(30)
+	fmd.ExpectedExecuteSuperQueryCurrent = 0
+	fmd.FetchSuperQueryMap["SHOW BINARY LOGS"] = binaryLogsResult("vt-bin.000001", "vt-bin.000002", "vt-bin.000003")
+	if err := Backup(ctx, fmd, logger, dir, "3.incremental", 1, nil, true); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	// The third one is taken on another tablet, with other binlog
+	// file names. other-bin.000012 has transactions both before and
+	// after the second backup.
+	fmd.CurrentMasterPosition = testPosition(40)
+	fmd.ExpectedExecuteSuperQueryCurrent = 0
+	fmd.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SHOW BINARY LOGS": binaryLogsResult("other-bin.000011", "other-bin.000012", "other-bin.000013", "other-bin.000014"),
+		"SHOW BINLOG EVENTS IN 'other-bin.000011' LIMIT 2": binlogEventsResult("other-bin.000011", 15),
+		"SHOW BINLOG EVENTS IN 'other-bin.000012' LIMIT 2": binlogEventsResult("other-bin.000012", 25),
+		"SHOW BINLOG EVENTS IN 'other-bin.000013' LIMIT 2": binlogEventsResult("other-bin.000013", 35),
+	}
+	if err := Backup(ctx, fmd, logger, dir, "4.incremental", 1, nil, true); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	// Without any new transaction, there is nothing to backup.
+	if err := Backup(ctx, fmd, logger, dir, "5.incremental", 1, nil, true); err == nil || !strings.Contains(err.Error(), "no new transaction") {
+		t.Errorf("Backup without new transaction: %v", err)
+	}
+
+	// If the binlogs since the previous backup were purged, the
+	// backup fails.
+	fmd.CurrentMasterPosition = testPosition(50)
+	fmd.ExpectedExecuteSuperQueryCurrent = 0
+	fmd.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		"SHOW BINARY LOGS": binaryLogsResult("other-bin.000015", "other-bin.000016"),
+		"SHOW BINLOG EVENTS IN 'other-bin.000015' LIMIT 2": binlogEventsResult("other-bin.000015", 45),
+	}
+	if err := Backup(ctx, fmd, logger, dir, "5.incremental", 1, nil, true); err == nil || !strings.Contains(err.Error(), "are not on the server anymore") {
+		t.Errorf("Backup with purged binlogs: %v", err)
+	}
+
+	bhs, err := bs.ListBackups(dir)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(bhs) != 4 {
+		t.Fatalf("ListBackups returned %v backups, want 4", len(bhs))
+	}
+	wantManifests := []*BackupManifest{{
+		Position:    testPosition(10),
+		Compression: "gzip",
+	}, {
+		FileEntries:  []FileEntry{{Base: backupBinlog, Name: "vt-bin.000001"}},
+		Position:     testPosition(20),
+		Incremental:  true,
+		FromPosition: testPosition(10),
+		Compression:  "gzip",
+	}, {
+		FileEntries:  []FileEntry{{Base: backupBinlog, Name: "vt-bin.000002"}},
+		Position:     testPosition(30),
+		Incremental:  true,
+		FromPosition: testPosition(20),
+		Compression:  "gzip",
+	}, {
+		FileEntries:  []FileEntry{{Base: backupBinlog, Name: "other-bin.000012"}, {Base: backupBinlog, Name: "other-bin.000013"}},
+		Position:     testPosition(40),
+		Incremental:  true,
+		FromPosition: testPosition(30),
+		Compression:  "gzip",
+	}}
+	for i, bh := range bhs {
+		bm, err := readManifest(bh)
+		if err != nil {
+			t.Fatalf("readManifest(%v) failed: %v", bh.Name(), err)
+		}
+		bm.Time = time.Time{}
+		for i := range bm.FileEntries {
+			bm.FileEntries[i].Hash = ""
+		}
+		if !reflect.DeepEqual(bm, wantManifests[i]) {
+			t.Errorf("manifest of %v: %#v, want %#v", bh.Name(), bm, wantManifests[i])
+		}
+	}
+
+	// Replay the incremental backups on top of the full one.
+	testcases := []struct {
+		from    replication.Position
+		target  replication.Position
+		applied []string
+		pos     replication.Position
+		err     string
+	}{{
+		from:    testPosition(10),
+		target:  testPosition(20),
+		applied: []string{"vt-bin.000001"},
+		pos:     testPosition(20),
+	}, {
+		from:    testPosition(10),
+		target:  testPosition(25),
+		applied: []string{"vt-bin.000001", "vt-bin.000002"},
+		pos:     testPosition(30),
+	}, {
+		from:    testPosition(10),
+		target:  testPosition(40),
+		applied: []string{"vt-bin.000001", "vt-bin.000002", "other-bin.000012", "other-bin.000013"},
+		pos:     testPosition(40),
+	}, {
+		from:    testPosition(10),
+		target:  testPosition(50),
+		applied: []string{"vt-bin.000001", "vt-bin.000002", "other-bin.000012", "other-bin.000013"},
+		err:     "position 0-1-50 is after the last incremental backup",
+	}, {
+		from: testPosition(5),
+		err:  "missing binlogs",
+	}}
+	for _, tcase := range testcases {
+		fmd.AppliedBinlogFiles = nil
+		fmd.ApplyBinlogFilePositions = map[string]replication.Position{
+			"vt-bin.000001":    testPosition(20),
+			"vt-bin.000002":    testPosition(30),
+			"other-bin.000012": testPosition(35),
+			"other-bin.000013": testPosition(40),
+		}
+		fmd.SetSlavePositionCommandsPos = tcase.from
+		fmd.SetSlavePositionCommandsResult = []string{"set position"}
+		fmd.ExpectedExecuteSuperQueryList = []string{"set position"}
+		fmd.ExpectedExecuteSuperQueryCurrent = 0
+
+		pos, err := restoreIncrementalBackups(ctx, fmd, logger, bhs[1:], tcase.from, RestoreTarget{Position: tcase.target}, 1)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("restoreIncrementalBackups(%v): %v, want %v", tcase.target, err, tcase.err)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("restoreIncrementalBackups(%v) failed: %v", tcase.target, err)
+			}
+			if !pos.Equal(tcase.pos) {
+				t.Errorf("restoreIncrementalBackups(%v): %v, want %v", tcase.target, pos, tcase.pos)
+			}
+		}
+		if !reflect.DeepEqual(fmd.AppliedBinlogFiles, tcase.applied) {
+			t.Errorf("restoreIncrementalBackups(%v) applied %v, want %v", tcase.target, fmd.AppliedBinlogFiles, tcase.applied)
+		}
+	}
+}
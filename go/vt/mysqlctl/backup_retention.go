@@ -0,0 +1,122 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/youtube/vitess/go/sync2"
+	"github.com/youtube/vitess/go/vt/concurrency"
+	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// This file handles the validation and the pruning of the backups
+// stored on the BackupStorage.
+
+const (
+	// BackupNameTimeFormat is the format of the time at the
+	// beginning of the backup names. BackupsToPrune relies on it
+	// for backups that don't have their time in their MANIFEST.
+	BackupNameTimeFormat = "2006-01-02.150405"
+)
+
+// ValidateBackup reads the MANIFEST of a backup, and checks the hash
+// of all its files. It returns the MANIFEST if the backup is valid.
+func ValidateBackup(bh backupstorage.BackupHandle, validateConcurrency int) (*BackupManifest, error) {
+	bm, err := readManifest(bh)
+	if err != nil {
+		return nil, err
+	}
+
+	sema := sync2.NewSemaphore(validateConcurrency, 0)
+	rec := concurrency.AllErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for i, fe := range bm.FileEntries {
+		wg.Add(1)
+		go func(i int, fe FileEntry) {
+			defer wg.Done()
+			sema.Acquire()
+			defer sema.Release()
+
+			name := fmt.Sprintf("%v", i)
+			rc, err := bh.ReadFile(name)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("cannot read file %v (%v): %v", name, fe.Name, err))
+				return
+			}
+			defer rc.Close()
+
+			hasher := newHasher()
+			if _, err := io.Copy(hasher, rc); err != nil {
+				rec.RecordError(fmt.Errorf("cannot read file %v (%v): %v", name, fe.Name, err))
+				return
+			}
+			if hash := hasher.HashString(); hash != fe.Hash {
+				rec.RecordError(fmt.Errorf("hash mismatch for file %v (%v), got %v expected %v", name, fe.Name, hash, fe.Hash))
+			}
+		}(i, fe)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+	return bm, nil
+}
+
+// backupTime returns the time a backup was taken. Backups taken by
+// older versions don't have it in their MANIFEST, it is then parsed
+// from their name.
+func backupTime(bh backupstorage.BackupHandle, bm *BackupManifest) (time.Time, bool) {
+	if !bm.Time.IsZero() {
+		return bm.Time, true
+	}
+	if len(bh.Name()) < len(BackupNameTimeFormat) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(BackupNameTimeFormat, bh.Name()[:len(BackupNameTimeFormat)])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// BackupsToPrune returns the backups to remove to enforce a retention
+// policy. bhs is sorted by name, as returned by ListBackups, so the
+// oldest backups come first.
+//
+// A full backup is kept if it is one of the keepCount most recent
+// ones, or if it was taken less than keepDuration before now. Zero
+// values disable these rules. All the backups older than the oldest
+// full backup that is kept are removed: incremental backups are
+// useless without the full backup they were taken on top of. If no
+// full backup is kept, nothing is removed.
+func BackupsToPrune(bhs []backupstorage.BackupHandle, keepCount int, keepDuration time.Duration, now time.Time) []backupstorage.BackupHandle {
+	oldestKept := -1
+	kept := 0
+	for i := len(bhs) - 1; i >= 0; i-- {
+		bm, err := readManifest(bhs[i])
+		if err != nil || bm.Incremental {
+			// Incomplete and incremental backups only go
+			// with the full backups around them.
+			continue
+		}
+		keep := kept < keepCount
+		if t, ok := backupTime(bhs[i], bm); keepDuration > 0 && ok && now.Sub(t) < keepDuration {
+			keep = true
+		}
+		if !keep {
+			break
+		}
+		kept++
+		oldestKept = i
+	}
+	if oldestKept == -1 {
+		return nil
+	}
+	return bhs[:oldestKept]
+}
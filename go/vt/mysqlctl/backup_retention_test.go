@@ -0,0 +1,217 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
+	"github.com/youtube/vitess/go/vt/mysqlctl/filebackupstorage"
+)
+
+// setupFileBackupStorage points the BackupStorage to a temporary
+// directory, and returns it with a function to clean it up.
+func setupFileBackupStorage(t *testing.T) (backupstorage.BackupStorage, func()) {
+	root, err := ioutil.TempDir("", "backuptest")
+	if err != nil {
+		t.Fatalf("os.TempDir failed: %v", err)
+	}
+	*filebackupstorage.FileBackupStorageRoot = root
+	*backupstorage.BackupStorageImplementation = "file"
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		t.Fatalf("GetBackupStorage failed: %v", err)
+	}
+	return bs, func() {
+		bs.Close()
+		os.RemoveAll(root)
+	}
+}
+
+// writeTestBackup stores a backup with the provided files. If bm is
+// nil, the MANIFEST is not written.
+func writeTestBackup(t *testing.T, bs backupstorage.BackupStorage, dir, name string, bm *BackupManifest, files ...string) {
+	bh, err := bs.StartBackup(dir, name)
+	if err != nil {
+		t.Fatalf("StartBackup failed: %v", err)
+	}
+	writeManifest := bm != nil
+	if !writeManifest {
+		bm = &BackupManifest{}
+	}
+	for i, contents := range files {
+		wc, err := bh.AddFile(fmt.Sprintf("%v", i))
+		if err != nil {
+			t.Fatalf("AddFile failed: %v", err)
+		}
+		if _, err := wc.Write([]byte(contents)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := wc.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		hasher := newHasher()
+		hasher.Write([]byte(contents))
+		bm.FileEntries = append(bm.FileEntries, FileEntry{
+			Base: backupData,
+			Name: fmt.Sprintf("file%v", i),
+			Hash: hasher.HashString(),
+		})
+	}
+	if writeManifest {
+		wc, err := bh.AddFile(backupManifest)
+		if err != nil {
+			t.Fatalf("AddFile failed: %v", err)
+		}
+		if err := json.NewEncoder(wc).Encode(bm); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		if err := wc.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+	if err := bh.EndBackup(); err != nil {
+		t.Fatalf("EndBackup failed: %v", err)
+	}
+}
+
+func findTestBackup(t *testing.T, bs backupstorage.BackupStorage, dir, name string) backupstorage.BackupHandle {
+	bhs, err := bs.ListBackups(dir)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	for _, bh := range bhs {
+		if bh.Name() == name {
+			return bh
+		}
+	}
+	t.Fatalf("no backup %v", name)
+	return nil
+}
+
+func TestValidateBackup(t *testing.T) {
+	bs, cleanup := setupFileBackupStorage(t)
+	defer cleanup()
+	dir := "ks/0"
+
+	writeTestBackup(t, bs, dir, "good", &BackupManifest{Position: testPosition(10)}, "contents 0", "contents 1", "contents 2")
+	writeTestBackup(t, bs, dir, "corrupted", &BackupManifest{Position: testPosition(10)}, "contents 0", "contents 1")
+	writeTestBackup(t, bs, dir, "incomplete", nil, "contents 0")
+
+	bm, err := ValidateBackup(findTestBackup(t, bs, dir, "good"), 2)
+	if err != nil {
+		t.Fatalf("ValidateBackup(good) failed: %v", err)
+	}
+	if len(bm.FileEntries) != 3 || !bm.Position.Equal(testPosition(10)) {
+		t.Errorf("ValidateBackup(good) returned %#v", bm)
+	}
+
+	// Corrupt the second file of the corrupted backup.
+	corrupted := path.Join(*filebackupstorage.FileBackupStorageRoot, dir, "corrupted", "1")
+	if err := ioutil.WriteFile(corrupted, []byte("corrupted"), os.ModePerm); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := ValidateBackup(findTestBackup(t, bs, dir, "corrupted"), 2); err == nil || !strings.Contains(err.Error(), "hash mismatch for file 1 (file1)") {
+		t.Errorf("ValidateBackup(corrupted): %v", err)
+	}
+	if err := os.Remove(corrupted); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := ValidateBackup(findTestBackup(t, bs, dir, "corrupted"), 2); err == nil || !strings.Contains(err.Error(), "cannot read file 1 (file1)") {
+		t.Errorf("ValidateBackup(missing file): %v", err)
+	}
+
+	if _, err := ValidateBackup(findTestBackup(t, bs, dir, "incomplete"), 2); err == nil || !strings.Contains(err.Error(), "can't read MANIFEST") {
+		t.Errorf("ValidateBackup(incomplete): %v", err)
+	}
+}
+
+func TestBackupsToPrune(t *testing.T) {
+	bs, cleanup := setupFileBackupStorage(t)
+	defer cleanup()
+	dir := "ks/0"
+
+	now := time.Date(2016, 5, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	backups := []struct {
+		name        string
+		time        time.Time
+		incremental bool
+		noManifest  bool
+	}{
+		// Taken by an older version, without time in the MANIFEST.
+		{name: now.Add(-6*day).Format(BackupNameTimeFormat) + ".cell-0000000100"},
+		{name: "b1", time: now.Add(-5 * day)},
+		{name: "b2", time: now.Add(-4*day - time.Hour), incremental: true},
+		{name: "b3", time: now.Add(-3 * day)},
+		{name: "b4", time: now.Add(-3*day + time.Hour), noManifest: true},
+		{name: "b5", time: now.Add(-2*day - time.Hour), incremental: true},
+		{name: "b6", time: now.Add(-1 * day)},
+		{name: "b7", time: now.Add(-time.Hour), incremental: true},
+	}
+	for _, b := range backups {
+		var bm *BackupManifest
+		if !b.noManifest {
+			bm = &BackupManifest{Time: b.time, Incremental: b.incremental}
+		}
+		writeTestBackup(t, bs, dir, b.name, bm)
+	}
+	bhs, err := bs.ListBackups(dir)
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+
+	testcases := []struct {
+		keepCount    int
+		keepDuration time.Duration
+		want         []string
+	}{{
+		keepCount: 1,
+		want:      []string{"2016-05-04.120000.cell-0000000100", "b1", "b2", "b3", "b4", "b5"},
+	}, {
+		keepCount: 2,
+		want:      []string{"2016-05-04.120000.cell-0000000100", "b1", "b2"},
+	}, {
+		keepCount: 10,
+		want:      nil,
+	}, {
+		keepDuration: 2 * day,
+		want:         []string{"2016-05-04.120000.cell-0000000100", "b1", "b2", "b3", "b4", "b5"},
+	}, {
+		keepDuration: 4 * day,
+		want:         []string{"2016-05-04.120000.cell-0000000100", "b1", "b2"},
+	}, {
+		keepDuration: 5*day + time.Hour,
+		want:         []string{"2016-05-04.120000.cell-0000000100"},
+	}, {
+		keepDuration: 7 * day,
+		want:         nil,
+	}, {
+		keepCount:    1,
+		keepDuration: 4 * day,
+		want:         []string{"2016-05-04.120000.cell-0000000100", "b1", "b2"},
+	}, {
+		// Nothing is removed if no full backup is kept.
+		keepDuration: time.Hour,
+		want:         nil,
+	}}
+	for _, tcase := range testcases {
+		var got []string
+		for _, bh := range BackupsToPrune(bhs, tcase.keepCount, tcase.keepDuration, now) {
+			got = append(got, bh.Name())
+		}
+		if !reflect.DeepEqual(got, tcase.want) {
+			t.Errorf("BackupsToPrune(%v, %v): %v, want %v", tcase.keepCount, tcase.keepDuration, got, tcase.want)
+		}
+	}
+}
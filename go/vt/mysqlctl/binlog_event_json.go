@@ -0,0 +1,314 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Value types of the binary JSON format of MySQL 5.7, as stored in
+// the binlogs.
+const (
+	jsonSmallObject = 0x00
+	jsonLargeObject = 0x01
+	jsonSmallArray  = 0x02
+	jsonLargeArray  = 0x03
+	jsonLiteral     = 0x04
+	jsonInt16       = 0x05
+	jsonUint16      = 0x06
+	jsonInt32       = 0x07
+	jsonUint32      = 0x08
+	jsonInt64       = 0x09
+	jsonUint64      = 0x0a
+	jsonDouble      = 0x0b
+	jsonString      = 0x0c
+	jsonOpaque      = 0x0f
+)
+
+// Literal values of the binary JSON format.
+const (
+	jsonNullLiteral  = 0x00
+	jsonTrueLiteral  = 0x01
+	jsonFalseLiteral = 0x02
+)
+
+// jsonValue converts a binary JSON value to its text representation,
+// as printed by MySQL. An empty value is the JSON null literal.
+func jsonValue(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	if err := printJSONValue(&buf, data[0], data[1:]); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// printJSONValue prints the value of type typ, which starts at the
+// beginning of data.
+func printJSONValue(buf *bytes.Buffer, typ byte, data []byte) error {
+	switch typ {
+	case jsonSmallObject:
+		return printJSONContainer(buf, data, false, true)
+	case jsonLargeObject:
+		return printJSONContainer(buf, data, true, true)
+	case jsonSmallArray:
+		return printJSONContainer(buf, data, false, false)
+	case jsonLargeArray:
+		return printJSONContainer(buf, data, true, false)
+	case jsonLiteral:
+		if len(data) < 1 {
+			return fmt.Errorf("literal overflows buffer")
+		}
+		return printJSONLiteral(buf, data[0])
+	case jsonInt16:
+		if len(data) < 2 {
+			return fmt.Errorf("int16 overflows buffer")
+		}
+		buf.WriteString(strconv.FormatInt(int64(int16(binary.LittleEndian.Uint16(data))), 10))
+	case jsonUint16:
+		if len(data) < 2 {
+			return fmt.Errorf("uint16 overflows buffer")
+		}
+		buf.WriteString(strconv.FormatUint(uint64(binary.LittleEndian.Uint16(data)), 10))
+	case jsonInt32:
+		if len(data) < 4 {
+			return fmt.Errorf("int32 overflows buffer")
+		}
+		buf.WriteString(strconv.FormatInt(int64(int32(binary.LittleEndian.Uint32(data))), 10))
+	case jsonUint32:
+		if len(data) < 4 {
+			return fmt.Errorf("uint32 overflows buffer")
+		}
+		buf.WriteString(strconv.FormatUint(uint64(binary.LittleEndian.Uint32(data)), 10))
+	case jsonInt64:
+		if len(data) < 8 {
+			return fmt.Errorf("int64 overflows buffer")
+		}
+		buf.WriteString(strconv.FormatInt(int64(binary.LittleEndian.Uint64(data)), 10))
+	case jsonUint64:
+		if len(data) < 8 {
+			return fmt.Errorf("uint64 overflows buffer")
+		}
+		buf.WriteString(strconv.FormatUint(binary.LittleEndian.Uint64(data), 10))
+	case jsonDouble:
+		if len(data) < 8 {
+			return fmt.Errorf("double overflows buffer")
+		}
+		buf.WriteString(strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(data)), 'g', -1, 64))
+	case jsonString:
+		l, pos, err := readJSONVarLen(data, 0)
+		if err != nil {
+			return err
+		}
+		if pos+l > len(data) {
+			return fmt.Errorf("string overflows buffer (%v > %v)", pos+l, len(data))
+		}
+		return printJSONString(buf, data[pos:pos+l])
+	case jsonOpaque:
+		return printJSONOpaque(buf, data)
+	default:
+		return fmt.Errorf("unknown value type %v", typ)
+	}
+	return nil
+}
+
+// printJSONContainer prints an object or an array. Its layout is:
+//
+//   element count (2 bytes if small, 4 if large)
+//   total size in bytes (2 or 4 bytes)
+//   key entries (objects only): key offset (2 or 4 bytes), key length (2 bytes)
+//   value entries: type (1 byte), value offset or inlined value (2 or 4 bytes)
+//   keys
+//   values
+//
+// The offsets are relative to the start of the container. The
+// literals and the 16 bits integers are inlined, as well as the 32
+// bits integers in large containers.
+func printJSONContainer(buf *bytes.Buffer, data []byte, large, object bool) error {
+	offsetSize := 2
+	if large {
+		offsetSize = 4
+	}
+	readOffset := func(pos int) int {
+		if large {
+			return int(binary.LittleEndian.Uint32(data[pos:]))
+		}
+		return int(binary.LittleEndian.Uint16(data[pos:]))
+	}
+	if len(data) < 2*offsetSize {
+		return fmt.Errorf("container header overflows buffer")
+	}
+	count := readOffset(0)
+	size := readOffset(offsetSize)
+	if size > len(data) {
+		return fmt.Errorf("container overflows buffer (%v > %v)", size, len(data))
+	}
+	data = data[:size]
+
+	keyEntrySize := offsetSize + 2
+	valueEntrySize := 1 + offsetSize
+	keyEntries := 2 * offsetSize
+	valueEntries := keyEntries
+	if object {
+		valueEntries += count * keyEntrySize
+	}
+	if valueEntries+count*valueEntrySize > len(data) {
+		return fmt.Errorf("container entries overflow buffer")
+	}
+
+	open, close := byte('['), byte(']')
+	if object {
+		open, close = '{', '}'
+	}
+	buf.WriteByte(open)
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if object {
+			entry := keyEntries + i*keyEntrySize
+			keyOffset := readOffset(entry)
+			keyLength := int(binary.LittleEndian.Uint16(data[entry+offsetSize:]))
+			if keyOffset+keyLength > len(data) {
+				return fmt.Errorf("key overflows buffer (%v > %v)", keyOffset+keyLength, len(data))
+			}
+			if err := printJSONString(buf, data[keyOffset:keyOffset+keyLength]); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+		}
+
+		entry := valueEntries + i*valueEntrySize
+		typ := data[entry]
+		switch {
+		case typ == jsonLiteral:
+			if err := printJSONLiteral(buf, data[entry+1]); err != nil {
+				return err
+			}
+			continue
+		case typ == jsonInt16, typ == jsonUint16, large && (typ == jsonInt32 || typ == jsonUint32):
+			if err := printJSONValue(buf, typ, data[entry+1:entry+valueEntrySize]); err != nil {
+				return err
+			}
+			continue
+		}
+		valueOffset := readOffset(entry + 1)
+		if valueOffset >= len(data) {
+			return fmt.Errorf("value overflows buffer (%v >= %v)", valueOffset, len(data))
+		}
+		if err := printJSONValue(buf, typ, data[valueOffset:]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(close)
+	return nil
+}
+
+func printJSONLiteral(buf *bytes.Buffer, literal byte) error {
+	switch literal {
+	case jsonNullLiteral:
+		buf.WriteString("null")
+	case jsonTrueLiteral:
+		buf.WriteString("true")
+	case jsonFalseLiteral:
+		buf.WriteString("false")
+	default:
+		return fmt.Errorf("unknown literal %v", literal)
+	}
+	return nil
+}
+
+func printJSONString(buf *bytes.Buffer, s []byte) error {
+	b, err := json.Marshal(string(s))
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// printJSONOpaque prints an opaque value: the MySQL column type of
+// the value (1 byte), and its data, whose length is a variable length
+// integer. Only the DECIMAL and temporal values are supported. The
+// temporal values are stored as 8 bytes packed values.
+func printJSONOpaque(buf *bytes.Buffer, data []byte) error {
+	if len(data) < 1 {
+		return fmt.Errorf("opaque value overflows buffer")
+	}
+	typ := data[0]
+	l, pos, err := readJSONVarLen(data, 1)
+	if err != nil {
+		return err
+	}
+	if pos+l > len(data) {
+		return fmt.Errorf("opaque value overflows buffer (%v > %v)", pos+l, len(data))
+	}
+	data = data[pos : pos+l]
+
+	switch typ {
+	case typeNewDecimal:
+		if len(data) < 2 {
+			return fmt.Errorf("decimal overflows buffer")
+		}
+		precision, scale := int(data[0]), int(data[1])
+		if 2+decimalLength(precision, scale) > len(data) {
+			return fmt.Errorf("decimal overflows buffer")
+		}
+		buf.Write(decimalValue(data[2:], precision, scale))
+		return nil
+	case typeDate, typeDateTime, typeTimestamp, typeTime:
+		if len(data) < 8 {
+			return fmt.Errorf("temporal value overflows buffer")
+		}
+		packed := int64(binary.LittleEndian.Uint64(data))
+		sign := ""
+		if packed < 0 {
+			sign = "-"
+			packed = -packed
+		}
+		micros := packed % (1 << 24)
+		v := packed >> 24
+		if typ == typeTime {
+			fmt.Fprintf(buf, "\"%v%02d:%02d:%02d.%06d\"", sign, v>>12, (v>>6)%(1<<6), v%(1<<6), micros)
+			return nil
+		}
+		ymd := v >> 17
+		ym := ymd >> 5
+		hms := v % (1 << 17)
+		if typ == typeDate {
+			fmt.Fprintf(buf, "\"%04d-%02d-%02d\"", ym/13, ym%13, ymd%(1<<5))
+			return nil
+		}
+		fmt.Fprintf(buf, "\"%04d-%02d-%02d %02d:%02d:%02d.%06d\"", ym/13, ym%13, ymd%(1<<5), hms>>12, (hms>>6)%(1<<6), hms%(1<<6), micros)
+		return nil
+	}
+	return fmt.Errorf("unsupported opaque value of type %v", typ)
+}
+
+// readJSONVarLen reads a variable length integer: 7 bits per byte,
+// least significant first, the high bit being set if more bytes
+// follow. It returns the value and the new position.
+func readJSONVarLen(data []byte, pos int) (int, int, error) {
+	l := 0
+	for shift := uint(0); shift < 35; shift += 7 {
+		if pos >= len(data) {
+			return 0, 0, fmt.Errorf("variable length overflows buffer")
+		}
+		b := data[pos]
+		pos++
+		l |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return l, pos, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid variable length")
+}
@@ -0,0 +1,851 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// Binlog event type codes for row based replication. MariaDB 10.0
+// uses the v1 rows events, MySQL 5.6 uses the v2 ones.
+const (
+	eTableMapEvent     = 19
+	eWriteRowsEventV1  = 23
+	eUpdateRowsEventV1 = 24
+	eDeleteRowsEventV1 = 25
+	eWriteRowsEventV2  = 30
+	eUpdateRowsEventV2 = 31
+	eDeleteRowsEventV2 = 32
+)
+
+// MySQL column types, as found in TABLE_MAP_EVENT.
+const (
+	typeDecimal    = 0
+	typeTiny       = 1
+	typeShort      = 2
+	typeLong       = 3
+	typeFloat      = 4
+	typeDouble     = 5
+	typeNull       = 6
+	typeTimestamp  = 7
+	typeLongLong   = 8
+	typeInt24      = 9
+	typeDate       = 10
+	typeTime       = 11
+	typeDateTime   = 12
+	typeYear       = 13
+	typeNewDate    = 14
+	typeVarchar    = 15
+	typeBit        = 16
+	typeTimestamp2 = 17
+	typeDateTime2  = 18
+	typeTime2      = 19
+	typeJSON       = 245
+	typeNewDecimal = 246
+	typeEnum       = 247
+	typeSet        = 248
+	typeTinyBlob   = 249
+	typeMediumBlob = 250
+	typeLongBlob   = 251
+	typeBlob       = 252
+	typeVarString  = 253
+	typeString     = 254
+	typeGeometry   = 255
+)
+
+// IsTableMap implements BinlogEvent.IsTableMap().
+func (ev binlogEvent) IsTableMap() bool {
+	return ev.Type() == eTableMapEvent
+}
+
+// IsWriteRows implements BinlogEvent.IsWriteRows().
+func (ev binlogEvent) IsWriteRows() bool {
+	return ev.Type() == eWriteRowsEventV1 || ev.Type() == eWriteRowsEventV2
+}
+
+// IsUpdateRows implements BinlogEvent.IsUpdateRows().
+func (ev binlogEvent) IsUpdateRows() bool {
+	return ev.Type() == eUpdateRowsEventV1 || ev.Type() == eUpdateRowsEventV2
+}
+
+// IsDeleteRows implements BinlogEvent.IsDeleteRows().
+func (ev binlogEvent) IsDeleteRows() bool {
+	return ev.Type() == eDeleteRowsEventV1 || ev.Type() == eDeleteRowsEventV2
+}
+
+// TableID implements BinlogEvent.TableID().
+//
+// Both MySQL 5.6 and MariaDB 10.0 use a 6 byte table id, which is the
+// first field of the post-header of TABLE_MAP and ROWS events.
+func (ev binlogEvent) TableID(f replication.BinlogFormat) uint64 {
+	data := ev.Bytes()[f.HeaderLength:]
+	return uint64(data[0]) |
+		uint64(data[1])<<8 |
+		uint64(data[2])<<16 |
+		uint64(data[3])<<24 |
+		uint64(data[4])<<32 |
+		uint64(data[5])<<40
+}
+
+// TableMap implements BinlogEvent.TableMap().
+//
+// Expected format (L = total length of event data):
+//   # bytes   field
+//   6         table id
+//   2         flags
+//   1         schema name length sl
+//   sl        schema name
+//   1         [00]
+//   1         table name length tl
+//   tl        table name
+//   1         [00]
+//   <var>     column count cc (var-len encoded)
+//   cc        column-def, one byte per column
+//   <var>     column-meta-def (var-len encoded string)
+//   n         NULL-bitmask, length: (cc + 7) / 8
+func (ev binlogEvent) TableMap(f replication.BinlogFormat) (*replication.TableMap, error) {
+	data := ev.Bytes()[f.HeaderLength:]
+
+	result := &replication.TableMap{}
+	pos := 6
+	if pos+2 > len(data) {
+		return nil, fmt.Errorf("TABLE_MAP_EVENT flags overflow buffer (%v > %v)", pos+2, len(data))
+	}
+	result.Flags = binary.LittleEndian.Uint16(data[pos : pos+2])
+	pos += 2
+
+	var err error
+	result.Database, pos, err = readTableMapName(data, pos)
+	if err != nil {
+		return nil, fmt.Errorf("can't read database name: %v", err)
+	}
+	result.Name, pos, err = readTableMapName(data, pos)
+	if err != nil {
+		return nil, fmt.Errorf("can't read table name: %v", err)
+	}
+
+	columnCount, pos, ok := readLenEncInt(data, pos)
+	if !ok {
+		return nil, fmt.Errorf("can't read column count in TABLE_MAP_EVENT")
+	}
+	if pos+int(columnCount) > len(data) {
+		return nil, fmt.Errorf("column types overflow buffer (%v > %v)", pos+int(columnCount), len(data))
+	}
+	result.Types = data[pos : pos+int(columnCount)]
+	pos += int(columnCount)
+
+	metaLen, pos, ok := readLenEncInt(data, pos)
+	if !ok {
+		return nil, fmt.Errorf("can't read metadata length in TABLE_MAP_EVENT")
+	}
+	metaEnd := pos + int(metaLen)
+	if metaEnd > len(data) {
+		return nil, fmt.Errorf("metadata overflows buffer (%v > %v)", metaEnd, len(data))
+	}
+	result.Metadata = make([]uint16, len(result.Types))
+	for i, typ := range result.Types {
+		result.Metadata[i], pos, err = metadataRead(data, pos, typ)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pos != metaEnd {
+		return nil, fmt.Errorf("unexpected metadata end: got %v was expecting %v (data=%v)", pos, metaEnd, data)
+	}
+
+	if pos+(int(columnCount)+7)/8 > len(data) {
+		return nil, fmt.Errorf("NULL bitmap overflows buffer (%v > %v)", pos+(int(columnCount)+7)/8, len(data))
+	}
+	result.CanBeNull = replication.NewBitmap(data[pos:], int(columnCount))
+
+	return result, nil
+}
+
+// readTableMapName reads a length-prefixed, NULL-terminated name.
+func readTableMapName(data []byte, pos int) (string, int, error) {
+	if pos >= len(data) {
+		return "", pos, fmt.Errorf("name length overflows buffer (%v >= %v)", pos, len(data))
+	}
+	l := int(data[pos])
+	pos++
+	if pos+l+1 > len(data) {
+		return "", pos, fmt.Errorf("name overflows buffer (%v > %v)", pos+l+1, len(data))
+	}
+	return string(data[pos : pos+l]), pos + l + 1, nil
+}
+
+// metadataRead reads a single value from the metadata string.
+func metadataRead(data []byte, pos int, typ byte) (uint16, int, error) {
+	switch typ {
+
+	case typeDecimal, typeTiny, typeShort, typeLong, typeNull, typeTimestamp, typeLongLong, typeInt24, typeDate, typeTime, typeDateTime, typeYear, typeNewDate:
+		// No data here.
+		return 0, pos, nil
+
+	case typeFloat, typeDouble, typeTimestamp2, typeDateTime2, typeTime2, typeJSON, typeTinyBlob, typeMediumBlob, typeLongBlob, typeBlob, typeGeometry:
+		// One byte.
+		if pos+1 > len(data) {
+			return 0, pos, fmt.Errorf("metadata for type %v overflows buffer (%v > %v)", typ, pos+1, len(data))
+		}
+		return uint16(data[pos]), pos + 1, nil
+
+	case typeNewDecimal, typeEnum, typeSet, typeString:
+		// Two bytes, Big Endian because of crazy encoding.
+		if pos+2 > len(data) {
+			return 0, pos, fmt.Errorf("metadata for type %v overflows buffer (%v > %v)", typ, pos+2, len(data))
+		}
+		return uint16(data[pos])<<8 + uint16(data[pos+1]), pos + 2, nil
+
+	case typeVarchar, typeBit, typeVarString:
+		// Two bytes, Little Endian
+		if pos+2 > len(data) {
+			return 0, pos, fmt.Errorf("metadata for type %v overflows buffer (%v > %v)", typ, pos+2, len(data))
+		}
+		return uint16(data[pos]) + uint16(data[pos+1])<<8, pos + 2, nil
+
+	default:
+		// Unknown types, we can't go on.
+		return 0, pos, fmt.Errorf("metadataRead: unsupported column type: %v", typ)
+	}
+}
+
+// Rows implements BinlogEvent.Rows().
+//
+// Expected format (L = total length of event data):
+//   # bytes   field
+//   6         table id
+//   2         flags
+//   -- if v2
+//   2         extra data length edl (including these 2 bytes)
+//   edl-2     extra data
+//   -- endif
+//   <var>     number of columns cc (var-len encoded)
+//   n         identify columns bitmap, length (cc + 7) / 8
+//             (UPDATE and DELETE only)
+//   n         data columns bitmap, length (cc + 7) / 8
+//             (WRITE and UPDATE only)
+//   <rows>    one or more rows, each one being:
+//             - NULL bitmap of the identify columns, and their values
+//               (UPDATE and DELETE only)
+//             - NULL bitmap of the data columns, and their values
+//               (WRITE and UPDATE only)
+func (ev binlogEvent) Rows(f replication.BinlogFormat, tm *replication.TableMap) (replication.Rows, error) {
+	typ := ev.Type()
+	data := ev.Bytes()[f.HeaderLength:]
+	hasIdentify := typ == eUpdateRowsEventV1 || typ == eUpdateRowsEventV2 ||
+		typ == eDeleteRowsEventV1 || typ == eDeleteRowsEventV2
+	hasData := typ == eWriteRowsEventV1 || typ == eWriteRowsEventV2 ||
+		typ == eUpdateRowsEventV1 || typ == eUpdateRowsEventV2
+
+	result := replication.Rows{}
+	pos := 6
+	if pos+2 > len(data) {
+		return result, fmt.Errorf("ROWS_EVENT flags overflow buffer (%v > %v)", pos+2, len(data))
+	}
+	result.Flags = binary.LittleEndian.Uint16(data[pos : pos+2])
+	pos += 2
+
+	// version=2 have extra data here.
+	if typ == eWriteRowsEventV2 || typ == eUpdateRowsEventV2 || typ == eDeleteRowsEventV2 {
+		if pos+2 > len(data) {
+			return result, fmt.Errorf("ROWS_EVENT extra data length overflows buffer (%v > %v)", pos+2, len(data))
+		}
+		// This extraDataLength contains the 2 bytes length.
+		extraDataLength := binary.LittleEndian.Uint16(data[pos : pos+2])
+		pos += int(extraDataLength)
+	}
+
+	columnCount, pos, ok := readLenEncInt(data, pos)
+	if !ok {
+		return result, fmt.Errorf("can't read column count in ROWS_EVENT")
+	}
+	if int(columnCount) != len(tm.Types) {
+		return result, fmt.Errorf("ROWS_EVENT has %v columns, but TABLE_MAP_EVENT for %v.%v has %v", columnCount, tm.Database, tm.Name, len(tm.Types))
+	}
+	bitmapLen := (int(columnCount) + 7) / 8
+
+	numIdentifyColumns := 0
+	numDataColumns := 0
+
+	if hasIdentify {
+		// Bitmap of the columns used for identify.
+		if pos+bitmapLen > len(data) {
+			return result, fmt.Errorf("identify columns bitmap overflows buffer (%v > %v)", pos+bitmapLen, len(data))
+		}
+		result.IdentifyColumns = replication.NewBitmap(data[pos:], int(columnCount))
+		pos += bitmapLen
+		numIdentifyColumns = result.IdentifyColumns.BitCount()
+	}
+
+	if hasData {
+		// Bitmap of columns that are present.
+		if pos+bitmapLen > len(data) {
+			return result, fmt.Errorf("data columns bitmap overflows buffer (%v > %v)", pos+bitmapLen, len(data))
+		}
+		result.DataColumns = replication.NewBitmap(data[pos:], int(columnCount))
+		pos += bitmapLen
+		numDataColumns = result.DataColumns.BitCount()
+	}
+
+	// One row at a time.
+	var err error
+	for pos < len(data) {
+		row := replication.Row{}
+
+		if hasIdentify {
+			row.Identify, pos, err = cellValues(data, pos, tm, result.IdentifyColumns, numIdentifyColumns)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		if hasData {
+			row.Data, pos, err = cellValues(data, pos, tm, result.DataColumns, numDataColumns)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// cellValues reads a NULL bitmap and the values of the columns set in
+// the provided bitmap, starting at pos. It returns the values and the
+// new position.
+func cellValues(data []byte, pos int, tm *replication.TableMap, columns replication.Bitmap, count int) ([]sqltypes.Value, int, error) {
+	nullBitmapLen := (count + 7) / 8
+	if pos+nullBitmapLen > len(data) {
+		return nil, pos, fmt.Errorf("NULL bitmap overflows buffer (%v > %v)", pos+nullBitmapLen, len(data))
+	}
+	nullBitmap := replication.NewBitmap(data[pos:], count)
+	pos += nullBitmapLen
+
+	values := make([]sqltypes.Value, 0, count)
+	valueIndex := 0
+	for c := 0; c < columns.Count(); c++ {
+		if !columns.Bit(c) {
+			continue
+		}
+		if nullBitmap.Bit(valueIndex) {
+			values = append(values, sqltypes.NULL)
+			valueIndex++
+			continue
+		}
+		valueIndex++
+
+		v, l, err := cellValue(data, pos, tm.Types[c], tm.Metadata[c])
+		if err != nil {
+			return nil, pos, fmt.Errorf("can't decode column %v of %v.%v: %v", c, tm.Database, tm.Name, err)
+		}
+		values = append(values, v)
+		pos += l
+	}
+	return values, pos, nil
+}
+
+// cellLength returns the number of bytes used by a value of the
+// provided type and metadata at position pos.
+func cellLength(data []byte, pos int, typ byte, metadata uint16) (int, error) {
+	switch typ {
+	case typeNull:
+		return 0, nil
+	case typeTiny, typeYear:
+		return 1, nil
+	case typeShort:
+		return 2, nil
+	case typeInt24, typeDate, typeTime:
+		return 3, nil
+	case typeLong, typeFloat, typeTimestamp:
+		return 4, nil
+	case typeLongLong, typeDouble, typeDateTime:
+		return 8, nil
+	case typeTimestamp2:
+		return 4 + (int(metadata)+1)/2, nil
+	case typeDateTime2:
+		return 5 + (int(metadata)+1)/2, nil
+	case typeTime2:
+		return 3 + (int(metadata)+1)/2, nil
+	case typeNewDecimal:
+		precision := int(metadata >> 8)
+		scale := int(metadata & 0xff)
+		return decimalLength(precision, scale), nil
+	case typeBit:
+		// bits in the high byte of metadata is the number of
+		// full bytes, the low byte is the number of extra bits.
+		nbits := int(metadata>>8)*8 + int(metadata&0xff)
+		return (nbits + 7) / 8, nil
+	case typeVarchar, typeVarString:
+		// Length is encoded in 1 or 2 bytes, depending on the
+		// maximum length.
+		if metadata < 256 {
+			if pos+1 > len(data) {
+				return 0, fmt.Errorf("string length overflows buffer (%v > %v)", pos+1, len(data))
+			}
+			return 1 + int(data[pos]), nil
+		}
+		if pos+2 > len(data) {
+			return 0, fmt.Errorf("string length overflows buffer (%v > %v)", pos+2, len(data))
+		}
+		return 2 + int(binary.LittleEndian.Uint16(data[pos:pos+2])), nil
+	case typeBlob, typeGeometry, typeJSON:
+		// Metadata is the number of bytes of the length.
+		return blobLength(data, pos, int(metadata))
+	case typeString:
+		realType, maxLength := stringType(metadata)
+		switch realType {
+		case typeEnum, typeSet:
+			return maxLength, nil
+		}
+		if maxLength < 256 {
+			if pos+1 > len(data) {
+				return 0, fmt.Errorf("string length overflows buffer (%v > %v)", pos+1, len(data))
+			}
+			return 1 + int(data[pos]), nil
+		}
+		if pos+2 > len(data) {
+			return 0, fmt.Errorf("string length overflows buffer (%v > %v)", pos+2, len(data))
+		}
+		return 2 + int(binary.LittleEndian.Uint16(data[pos:pos+2])), nil
+	default:
+		return 0, fmt.Errorf("unsupported column type: %v", typ)
+	}
+}
+
+// blobLength returns the length of a blob value, whose length is
+// encoded in lengthBytes little endian bytes.
+func blobLength(data []byte, pos int, lengthBytes int) (int, error) {
+	if lengthBytes < 1 || lengthBytes > 4 {
+		return 0, fmt.Errorf("unsupported blob length size: %v", lengthBytes)
+	}
+	if pos+lengthBytes > len(data) {
+		return 0, fmt.Errorf("blob length overflows buffer (%v > %v)", pos+lengthBytes, len(data))
+	}
+	l := 0
+	for i := lengthBytes - 1; i >= 0; i-- {
+		l = l<<8 | int(data[pos+i])
+	}
+	return lengthBytes + l, nil
+}
+
+// ColumnTypeMatches returns true if a column of the provided
+// TABLE_MAP_EVENT type and metadata can have the vitess type of a
+// field read from mysqld. The event doesn't tell the signedness of
+// the integers, or whether a string is text or binary.
+func ColumnTypeMatches(typ byte, metadata uint16, fieldType querypb.Type) bool {
+	switch typ {
+	case typeTiny:
+		return fieldType == sqltypes.Int8 || fieldType == sqltypes.Uint8
+	case typeShort:
+		return fieldType == sqltypes.Int16 || fieldType == sqltypes.Uint16
+	case typeInt24:
+		return fieldType == sqltypes.Int24 || fieldType == sqltypes.Uint24
+	case typeLong:
+		return fieldType == sqltypes.Int32 || fieldType == sqltypes.Uint32
+	case typeLongLong:
+		return fieldType == sqltypes.Int64 || fieldType == sqltypes.Uint64
+	case typeFloat:
+		return fieldType == sqltypes.Float32
+	case typeDouble:
+		return fieldType == sqltypes.Float64
+	case typeDecimal, typeNewDecimal:
+		return fieldType == sqltypes.Decimal
+	case typeNull:
+		return fieldType == sqltypes.Null
+	case typeTimestamp, typeTimestamp2:
+		return fieldType == sqltypes.Timestamp
+	case typeDate, typeNewDate:
+		return fieldType == sqltypes.Date
+	case typeTime, typeTime2:
+		return fieldType == sqltypes.Time
+	case typeDateTime, typeDateTime2:
+		return fieldType == sqltypes.Datetime
+	case typeYear:
+		return fieldType == sqltypes.Year
+	case typeBit:
+		return fieldType == sqltypes.Bit
+	case typeVarchar, typeVarString:
+		return fieldType == sqltypes.VarChar || fieldType == sqltypes.VarBinary
+	case typeTinyBlob, typeMediumBlob, typeLongBlob, typeBlob:
+		return fieldType == sqltypes.Text || fieldType == sqltypes.Blob
+	case typeEnum:
+		return fieldType == sqltypes.Enum
+	case typeSet:
+		return fieldType == sqltypes.Set
+	case typeString:
+		switch realType, _ := stringType(metadata); realType {
+		case typeEnum:
+			return fieldType == sqltypes.Enum
+		case typeSet:
+			return fieldType == sqltypes.Set
+		}
+		return fieldType == sqltypes.Char || fieldType == sqltypes.Binary
+	}
+	return false
+}
+
+// stringType decodes the metadata of a MYSQL_TYPE_STRING column. The
+// high byte is the real type (CHAR, ENUM or SET), the low byte the
+// length. For CHAR columns longer than 255 bytes, bits 4 and 5 of the
+// high byte are XOR'ed with the high bits of the length.
+// For ENUM and SET, the returned length is the size of the value.
+func stringType(metadata uint16) (byte, int) {
+	if metadata < 256 {
+		return typeString, int(metadata)
+	}
+	b0 := byte(metadata >> 8)
+	b1 := byte(metadata & 0xff)
+	if b0&0x30 != 0x30 {
+		return b0 | 0x30, int(uint16(b1) | uint16((b0&0x30)^0x30)<<4)
+	}
+	return b0, int(b1)
+}
+
+// cellValue returns the value of a cell of the provided type and
+// metadata at position pos, and the number of bytes it used.
+//
+// The TABLE_MAP_EVENT doesn't tell us about the signedness of integer
+// columns, or the character set of string columns. Integers are
+// returned as signed, and strings as binary types. ENUM and SET
+// values are returned as their numerical value. TIMESTAMP values are
+// returned in UTC. JSON values are returned as their text.
+func cellValue(data []byte, pos int, typ byte, metadata uint16) (sqltypes.Value, int, error) {
+	l, err := cellLength(data, pos, typ, metadata)
+	if err != nil {
+		return sqltypes.NULL, 0, err
+	}
+	if pos+l > len(data) {
+		return sqltypes.NULL, 0, fmt.Errorf("value of type %v overflows buffer (%v > %v)", typ, pos+l, len(data))
+	}
+	b := data[pos : pos+l]
+
+	switch typ {
+	case typeNull:
+		return sqltypes.NULL, 0, nil
+	case typeTiny:
+		return makeInt(querypb.Type_INT8, int64(int8(b[0]))), l, nil
+	case typeShort:
+		return makeInt(querypb.Type_INT16, int64(int16(binary.LittleEndian.Uint16(b)))), l, nil
+	case typeInt24:
+		v := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+		if v&0x800000 != 0 {
+			v |= 0xff000000
+		}
+		return makeInt(querypb.Type_INT24, int64(int32(v))), l, nil
+	case typeLong:
+		return makeInt(querypb.Type_INT32, int64(int32(binary.LittleEndian.Uint32(b)))), l, nil
+	case typeLongLong:
+		return makeInt(querypb.Type_INT64, int64(binary.LittleEndian.Uint64(b))), l, nil
+	case typeFloat:
+		f := math.Float32frombits(binary.LittleEndian.Uint32(b))
+		return sqltypes.MakeTrusted(querypb.Type_FLOAT32, strconv.AppendFloat(nil, float64(f), 'g', -1, 32)), l, nil
+	case typeDouble:
+		f := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		return sqltypes.MakeTrusted(querypb.Type_FLOAT64, strconv.AppendFloat(nil, f, 'g', -1, 64)), l, nil
+	case typeYear:
+		year := 0
+		if b[0] != 0 {
+			year = 1900 + int(b[0])
+		}
+		return sqltypes.MakeTrusted(querypb.Type_YEAR, []byte(fmt.Sprintf("%04d", year))), l, nil
+	case typeDate:
+		v := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+		day := v & 31
+		month := (v >> 5) & 15
+		year := v >> 9
+		return sqltypes.MakeTrusted(querypb.Type_DATE, []byte(fmt.Sprintf("%04d-%02d-%02d", year, month, day))), l, nil
+	case typeTime:
+		v := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+		if v&0x800000 != 0 {
+			v |= 0xff000000
+		}
+		t := int32(v)
+		sign := ""
+		if t < 0 {
+			sign = "-"
+			t = -t
+		}
+		return sqltypes.MakeTrusted(querypb.Type_TIME, []byte(fmt.Sprintf("%v%02d:%02d:%02d", sign, t/10000, (t/100)%100, t%100))), l, nil
+	case typeTime2:
+		return sqltypes.MakeTrusted(querypb.Type_TIME, time2Value(b, int(metadata))), l, nil
+	case typeTimestamp:
+		t := binary.LittleEndian.Uint32(b)
+		return sqltypes.MakeTrusted(querypb.Type_TIMESTAMP, timestampValue(t, 0, 0)), l, nil
+	case typeTimestamp2:
+		t := binary.BigEndian.Uint32(b[:4])
+		return sqltypes.MakeTrusted(querypb.Type_TIMESTAMP, timestampValue(t, fractionalPart(b[4:], int(metadata)), int(metadata))), l, nil
+	case typeDateTime:
+		v := binary.LittleEndian.Uint64(b)
+		d := v / 1000000
+		t := v % 1000000
+		return sqltypes.MakeTrusted(querypb.Type_DATETIME, []byte(fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d",
+			d/10000, (d/100)%100, d%100, t/10000, (t/100)%100, t%100))), l, nil
+	case typeDateTime2:
+		return sqltypes.MakeTrusted(querypb.Type_DATETIME, dateTime2Value(b, int(metadata))), l, nil
+	case typeNewDecimal:
+		precision := int(metadata >> 8)
+		scale := int(metadata & 0xff)
+		return sqltypes.MakeTrusted(querypb.Type_DECIMAL, decimalValue(b, precision, scale)), l, nil
+	case typeBit:
+		return sqltypes.MakeTrusted(querypb.Type_BIT, b), l, nil
+	case typeVarchar, typeVarString:
+		if metadata < 256 {
+			return sqltypes.MakeTrusted(querypb.Type_VARBINARY, b[1:]), l, nil
+		}
+		return sqltypes.MakeTrusted(querypb.Type_VARBINARY, b[2:]), l, nil
+	case typeBlob, typeGeometry:
+		return sqltypes.MakeTrusted(querypb.Type_BLOB, b[metadata:]), l, nil
+	case typeJSON:
+		v, err := jsonValue(b[metadata:])
+		if err != nil {
+			return sqltypes.NULL, 0, err
+		}
+		return sqltypes.MakeTrusted(querypb.Type_VARBINARY, v), l, nil
+	case typeString:
+		realType, maxLength := stringType(metadata)
+		switch realType {
+		case typeEnum, typeSet:
+			var v uint64
+			for i := len(b) - 1; i >= 0; i-- {
+				v = v<<8 | uint64(b[i])
+			}
+			return sqltypes.MakeTrusted(querypb.Type_UINT64, strconv.AppendUint(nil, v, 10)), l, nil
+		}
+		if maxLength < 256 {
+			return sqltypes.MakeTrusted(querypb.Type_BINARY, b[1:]), l, nil
+		}
+		return sqltypes.MakeTrusted(querypb.Type_BINARY, b[2:]), l, nil
+	}
+	return sqltypes.NULL, 0, fmt.Errorf("unsupported column type: %v", typ)
+}
+
+func makeInt(typ querypb.Type, v int64) sqltypes.Value {
+	return sqltypes.MakeTrusted(typ, strconv.AppendInt(nil, v, 10))
+}
+
+// fractionalPart returns the fractional seconds, in microseconds, of
+// a TIMESTAMP2 or DATETIME2 value. The fractional part is stored big
+// endian in (fsp+1)/2 bytes.
+func fractionalPart(b []byte, fsp int) int {
+	switch fsp {
+	case 1, 2:
+		return int(b[0]) * 10000
+	case 3, 4:
+		return int(binary.BigEndian.Uint16(b[:2])) * 100
+	case 5, 6:
+		return int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+	}
+	return 0
+}
+
+// appendFraction appends the fsp first digits of the microseconds.
+func appendFraction(buf []byte, micros, fsp int) []byte {
+	if fsp <= 0 {
+		return buf
+	}
+	return append(buf, fmt.Sprintf(".%06d", micros)[:fsp+1]...)
+}
+
+func timestampValue(t uint32, micros, fsp int) []byte {
+	if t == 0 && micros == 0 {
+		return appendFraction([]byte("0000-00-00 00:00:00"), 0, fsp)
+	}
+	return appendFraction([]byte(time.Unix(int64(t), 0).UTC().Format("2006-01-02 15:04:05")), micros, fsp)
+}
+
+// dateTime2Value decodes a DATETIME2 value. The first 5 bytes are a
+// big endian packed value, offset by 0x8000000000:
+//
+//   1 bit  sign (1 = non-negative)
+//   17 bits year*13+month
+//   5 bits day
+//   5 bits hour
+//   6 bits minute
+//   6 bits second
+func dateTime2Value(b []byte, fsp int) []byte {
+	v := uint64(b[0])<<32 | uint64(b[1])<<24 | uint64(b[2])<<16 | uint64(b[3])<<8 | uint64(b[4])
+	v -= 0x8000000000
+	ymd := v >> 17
+	ym := ymd >> 5
+	hms := v & (1<<17 - 1)
+
+	buf := []byte(fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d",
+		ym/13, ym%13, ymd&31, hms>>12, (hms>>6)&63, hms&63))
+	return appendFraction(buf, fractionalPart(b[5:], fsp), fsp)
+}
+
+// time2Value decodes a TIME2 value. The first 3 bytes are a big
+// endian packed value, offset by 0x800000:
+//
+//   1 bit  sign (1 = non-negative)
+//   1 bit  unused
+//   10 bits hour
+//   6 bits minute
+//   6 bits second
+//
+// Negative values with a fractional part are stored with the integer
+// part rounded up, and a negative fractional part.
+func time2Value(b []byte, fsp int) []byte {
+	intPart := int64(uint32(b[0])<<16|uint32(b[1])<<8|uint32(b[2])) - 0x800000
+	var frac int64
+	switch fsp {
+	case 1, 2:
+		frac = int64(b[3])
+		if intPart < 0 && frac != 0 {
+			intPart++
+			frac -= 0x100
+		}
+		frac *= 10000
+	case 3, 4:
+		frac = int64(binary.BigEndian.Uint16(b[3:5]))
+		if intPart < 0 && frac != 0 {
+			intPart++
+			frac -= 0x10000
+		}
+		frac *= 100
+	case 5, 6:
+		// The 6 bytes are a single packed value.
+		frac = int64(uint32(b[3])<<16 | uint32(b[4])<<8 | uint32(b[5]))
+	}
+	packed := intPart<<24 + frac
+
+	sign := ""
+	if packed < 0 {
+		sign = "-"
+		packed = -packed
+	}
+	hms := packed >> 24
+	micros := int(packed % (1 << 24))
+	buf := []byte(fmt.Sprintf("%v%02d:%02d:%02d", sign, (hms>>12)%(1<<10), (hms>>6)%(1<<6), hms%(1<<6)))
+	return appendFraction(buf, micros, fsp)
+}
+
+// dig2bytes is the number of bytes used to store a given number of
+// decimal digits, for the leftover digits of a DECIMAL.
+var dig2bytes = []int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
+
+// decimalLength returns the number of bytes used to store a DECIMAL
+// of the provided precision and scale. Each group of 9 digits is
+// stored in 4 bytes, and the leftover digits use dig2bytes.
+func decimalLength(precision, scale int) int {
+	intg := precision - scale
+	intg0 := intg / 9
+	frac0 := scale / 9
+	return intg0*4 + dig2bytes[intg-intg0*9] + frac0*4 + dig2bytes[scale-frac0*9]
+}
+
+// decimalValue decodes a binary DECIMAL. All the groups are stored big
+// endian. The sign is stored by inverting the first bit, and negative
+// numbers have all their bits inverted.
+func decimalValue(b []byte, precision, scale int) []byte {
+	d := make([]byte, len(b))
+	copy(d, b)
+	negative := d[0]&0x80 == 0
+	d[0] ^= 0x80
+	if negative {
+		for i := range d {
+			d[i] ^= 0xff
+		}
+	}
+
+	intg := precision - scale
+	intg0 := intg / 9
+	intg0x := intg - intg0*9
+	frac0 := scale / 9
+	frac0x := scale - frac0*9
+
+	readGroup := func(pos, size int) uint32 {
+		var v uint32
+		for i := 0; i < size; i++ {
+			v = v<<8 | uint32(d[pos+i])
+		}
+		return v
+	}
+
+	var intBuf bytes.Buffer
+	pos := 0
+	if intg0x > 0 {
+		size := dig2bytes[intg0x]
+		fmt.Fprintf(&intBuf, "%d", readGroup(pos, size))
+		pos += size
+	}
+	for i := 0; i < intg0; i++ {
+		fmt.Fprintf(&intBuf, "%09d", readGroup(pos, 4))
+		pos += 4
+	}
+	integer := bytes.TrimLeft(intBuf.Bytes(), "0")
+	if len(integer) == 0 {
+		integer = []byte{'0'}
+	}
+
+	var result bytes.Buffer
+	if negative {
+		result.WriteByte('-')
+	}
+	result.Write(integer)
+	if scale > 0 {
+		result.WriteByte('.')
+		for i := 0; i < frac0; i++ {
+			fmt.Fprintf(&result, "%09d", readGroup(pos, 4))
+			pos += 4
+		}
+		if frac0x > 0 {
+			size := dig2bytes[frac0x]
+			fmt.Fprintf(&result, "%0*d", frac0x, readGroup(pos, size))
+		}
+	}
+	return result.Bytes()
+}
+
+// readLenEncInt reads a length-encoded integer, as used in the MySQL
+// protocol.
+func readLenEncInt(data []byte, pos int) (uint64, int, bool) {
+	if pos >= len(data) {
+		return 0, 0, false
+	}
+	switch data[pos] {
+	case 0xfc:
+		// Encoded in the next 2 bytes.
+		if pos+2 >= len(data) {
+			return 0, 0, false
+		}
+		return uint64(data[pos+1]) |
+			uint64(data[pos+2])<<8, pos + 3, true
+	case 0xfd:
+		// Encoded in the next 3 bytes.
+		if pos+3 >= len(data) {
+			return 0, 0, false
+		}
+		return uint64(data[pos+1]) |
+			uint64(data[pos+2])<<8 |
+			uint64(data[pos+3])<<16, pos + 4, true
+	case 0xfe:
+		// Encoded in the next 8 bytes.
+		if pos+8 >= len(data) {
+			return 0, 0, false
+		}
+		return uint64(data[pos+1]) |
+			uint64(data[pos+2])<<8 |
+			uint64(data[pos+3])<<16 |
+			uint64(data[pos+4])<<24 |
+			uint64(data[pos+5])<<32 |
+			uint64(data[pos+6])<<40 |
+			uint64(data[pos+7])<<48 |
+			uint64(data[pos+8])<<56, pos + 9, true
+	}
+	return uint64(data[pos]), pos + 1, true
+}
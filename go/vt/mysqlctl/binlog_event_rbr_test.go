@@ -0,0 +1,434 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mysqlctl
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// rbrFormat is the format used by the RBR test events.
+var rbrFormat = replication.BinlogFormat{
+	FormatVersion: 4,
+	HeaderLength:  19,
+}
+
+// newRBREvent returns a binlogEvent of the given type, with a default
+// v4 header and the provided data.
+func newRBREvent(typ byte, data []byte) binlogEvent {
+	ev := make([]byte, 19+len(data))
+	binary.LittleEndian.PutUint32(ev[0:4], 1475077623)
+	ev[4] = typ
+	binary.LittleEndian.PutUint32(ev[5:9], 62344)
+	binary.LittleEndian.PutUint32(ev[9:13], uint32(len(ev)))
+	copy(ev[19:], data)
+	return binlogEvent(ev)
+}
+
+// Table map for vt_test_keyspace.vt_a with columns:
+// id int, name varchar(64), price decimal(10,2), created datetime,
+// data blob.
+var vtaTableMapData = []byte{
+	0x66, 0x00, 0x00, 0x00, 0x00, 0x00, // table id
+	0x01, 0x00, // flags
+	0x10, 'v', 't', '_', 't', 'e', 's', 't', '_', 'k', 'e', 'y', 's', 'p', 'a', 'c', 'e', 0x00,
+	0x04, 'v', 't', '_', 'a', 0x00,
+	0x05,                // column count
+	3, 15, 246, 18, 252, // column types
+	0x06,                               // metadata length
+	0x40, 0x00, 0x0a, 0x02, 0x00, 0x02, // metadata
+	0x1e, // NULL bitmap
+}
+
+func TestBinlogEventRBRTypes(t *testing.T) {
+	testcases := []struct {
+		typ                                         byte
+		tableMap, writeRows, updateRows, deleteRows bool
+	}{
+		{typ: eTableMapEvent, tableMap: true},
+		{typ: eWriteRowsEventV1, writeRows: true},
+		{typ: eWriteRowsEventV2, writeRows: true},
+		{typ: eUpdateRowsEventV1, updateRows: true},
+		{typ: eUpdateRowsEventV2, updateRows: true},
+		{typ: eDeleteRowsEventV1, deleteRows: true},
+		{typ: eDeleteRowsEventV2, deleteRows: true},
+		{typ: 2},
+	}
+	for _, tcase := range testcases {
+		ev := newRBREvent(tcase.typ, nil)
+		if got := ev.IsTableMap(); got != tcase.tableMap {
+			t.Errorf("type %v: IsTableMap() = %v, want %v", tcase.typ, got, tcase.tableMap)
+		}
+		if got := ev.IsWriteRows(); got != tcase.writeRows {
+			t.Errorf("type %v: IsWriteRows() = %v, want %v", tcase.typ, got, tcase.writeRows)
+		}
+		if got := ev.IsUpdateRows(); got != tcase.updateRows {
+			t.Errorf("type %v: IsUpdateRows() = %v, want %v", tcase.typ, got, tcase.updateRows)
+		}
+		if got := ev.IsDeleteRows(); got != tcase.deleteRows {
+			t.Errorf("type %v: IsDeleteRows() = %v, want %v", tcase.typ, got, tcase.deleteRows)
+		}
+	}
+}
+
+func TestBinlogEventTableMap(t *testing.T) {
+	ev := newRBREvent(eTableMapEvent, vtaTableMapData)
+	if !ev.IsValid() {
+		t.Fatalf("IsValid() = false")
+	}
+	if got, want := ev.TableID(rbrFormat), uint64(0x66); got != want {
+		t.Errorf("TableID() = %v, want %v", got, want)
+	}
+	got, err := ev.TableMap(rbrFormat)
+	if err != nil {
+		t.Fatalf("TableMap() error: %v", err)
+	}
+	want := &replication.TableMap{
+		Flags:     1,
+		Database:  "vt_test_keyspace",
+		Name:      "vt_a",
+		Types:     []byte{3, 15, 246, 18, 252},
+		CanBeNull: replication.NewBitmap([]byte{0x1e}, 5),
+		Metadata:  []uint16{0, 64, 0x0a02, 0, 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TableMap() = %#v, want %#v", got, want)
+	}
+	for i, canBeNull := range []bool{false, true, true, true, true} {
+		if got.CanBeNull.Bit(i) != canBeNull {
+			t.Errorf("CanBeNull.Bit(%v) = %v, want %v", i, got.CanBeNull.Bit(i), canBeNull)
+		}
+	}
+}
+
+func TestBinlogEventTableMapBadLength(t *testing.T) {
+	ev := newRBREvent(eTableMapEvent, vtaTableMapData[:len(vtaTableMapData)-4])
+	want := "metadata overflows buffer (45 > 42)"
+	if _, err := ev.TableMap(rbrFormat); err == nil || err.Error() != want {
+		t.Errorf("TableMap() error = %v, want %v", err, want)
+	}
+}
+
+func TestBinlogEventWriteRows(t *testing.T) {
+	tm, err := newRBREvent(eTableMapEvent, vtaTableMapData).TableMap(rbrFormat)
+	if err != nil {
+		t.Fatalf("TableMap() error: %v", err)
+	}
+	// MySQL 5.6 uses v2 events.
+	ev := newRBREvent(eWriteRowsEventV2, []byte{
+		0x66, 0x00, 0x00, 0x00, 0x00, 0x00, // table id
+		0x01, 0x00, // flags
+		0x02, 0x00, // extra data length
+		0x05, // column count
+		0x1f, // data columns
+		// First row.
+		0x00,
+		0x01, 0x00, 0x00, 0x00,
+		0x03, 'a', 'b', 'c',
+		0x80, 0x00, 0x04, 0xd2, 0x38,
+		0x99, 0x9a, 0x78, 0xfb, 0xc3,
+		0x02, 0x00, 'x', 'y',
+		// Second row, with NULLs.
+		0x1e,
+		0x02, 0x00, 0x00, 0x00,
+	})
+	if got, want := ev.TableID(rbrFormat), uint64(0x66); got != want {
+		t.Errorf("TableID() = %v, want %v", got, want)
+	}
+	got, err := ev.Rows(rbrFormat, tm)
+	if err != nil {
+		t.Fatalf("Rows() error: %v", err)
+	}
+	want := []replication.Row{
+		{
+			Data: []sqltypes.Value{
+				sqltypes.MakeTrusted(querypb.Type_INT32, []byte("1")),
+				sqltypes.MakeTrusted(querypb.Type_VARBINARY, []byte("abc")),
+				sqltypes.MakeTrusted(querypb.Type_DECIMAL, []byte("1234.56")),
+				sqltypes.MakeTrusted(querypb.Type_DATETIME, []byte("2016-09-28 15:47:03")),
+				sqltypes.MakeTrusted(querypb.Type_BLOB, []byte("xy")),
+			},
+		},
+		{
+			Data: []sqltypes.Value{
+				sqltypes.MakeTrusted(querypb.Type_INT32, []byte("2")),
+				sqltypes.NULL,
+				sqltypes.NULL,
+				sqltypes.NULL,
+				sqltypes.NULL,
+			},
+		},
+	}
+	if !reflect.DeepEqual(got.Rows, want) {
+		t.Errorf("Rows() = %v, want %v", got.Rows, want)
+	}
+	if got.DataColumns.BitCount() != 5 {
+		t.Errorf("DataColumns.BitCount() = %v, want 5", got.DataColumns.BitCount())
+	}
+}
+
+func TestBinlogEventUpdateRows(t *testing.T) {
+	// Table with columns: id int, name varchar(300).
+	tm := &replication.TableMap{
+		Database:  "vt_test_keyspace",
+		Name:      "vt_b",
+		Types:     []byte{3, 15},
+		CanBeNull: replication.NewBitmap([]byte{0x02}, 2),
+		Metadata:  []uint16{0, 300},
+	}
+	// MariaDB uses v1 events.
+	ev := newRBREvent(eUpdateRowsEventV1, []byte{
+		0x67, 0x00, 0x00, 0x00, 0x00, 0x00, // table id
+		0x01, 0x00, // flags
+		0x02, // column count
+		0x03, // identify columns
+		0x02, // data columns
+		0x00,
+		0x05, 0x00, 0x00, 0x00,
+		0x02, 0x00, 'h', 'i',
+		0x00,
+		0x03, 0x00, 'f', 'o', 'o',
+	})
+	got, err := ev.Rows(rbrFormat, tm)
+	if err != nil {
+		t.Fatalf("Rows() error: %v", err)
+	}
+	want := []replication.Row{{
+		Identify: []sqltypes.Value{
+			sqltypes.MakeTrusted(querypb.Type_INT32, []byte("5")),
+			sqltypes.MakeTrusted(querypb.Type_VARBINARY, []byte("hi")),
+		},
+		Data: []sqltypes.Value{
+			sqltypes.MakeTrusted(querypb.Type_VARBINARY, []byte("foo")),
+		},
+	}}
+	if !reflect.DeepEqual(got.Rows, want) {
+		t.Errorf("Rows() = %v, want %v", got.Rows, want)
+	}
+}
+
+func TestBinlogEventDeleteRows(t *testing.T) {
+	tm := &replication.TableMap{
+		Types:    []byte{3, 15},
+		Metadata: []uint16{0, 300},
+	}
+	ev := newRBREvent(eDeleteRowsEventV2, []byte{
+		0x67, 0x00, 0x00, 0x00, 0x00, 0x00, // table id
+		0x01, 0x00, // flags
+		0x02, 0x00, // extra data length
+		0x02, // column count
+		0x01, // identify columns
+		0x00,
+		0x07, 0x00, 0x00, 0x00,
+		0x00,
+		0x08, 0x00, 0x00, 0x00,
+	})
+	got, err := ev.Rows(rbrFormat, tm)
+	if err != nil {
+		t.Fatalf("Rows() error: %v", err)
+	}
+	want := []replication.Row{{
+		Identify: []sqltypes.Value{sqltypes.MakeTrusted(querypb.Type_INT32, []byte("7"))},
+	}, {
+		Identify: []sqltypes.Value{sqltypes.MakeTrusted(querypb.Type_INT32, []byte("8"))},
+	}}
+	if !reflect.DeepEqual(got.Rows, want) {
+		t.Errorf("Rows() = %v, want %v", got.Rows, want)
+	}
+}
+
+func TestBinlogEventRowsErrors(t *testing.T) {
+	tm := &replication.TableMap{
+		Database: "vt_test_keyspace",
+		Name:     "vt_b",
+		Types:    []byte{3, 15},
+		Metadata: []uint16{0, 300},
+	}
+	testcases := []struct {
+		data []byte
+		want string
+	}{{
+		data: []byte{0x67, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x03, 0x07},
+		want: "ROWS_EVENT has 3 columns, but TABLE_MAP_EVENT for vt_test_keyspace.vt_b has 2",
+	}, {
+		data: []byte{0x67, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x02, 0x03, 0x00, 0x05, 0x00, 0x00, 0x00, 0x03, 0x00, 'a'},
+		want: "can't decode column 1 of vt_test_keyspace.vt_b: value of type 15 overflows buffer (20 > 18)",
+	}}
+	for _, tcase := range testcases {
+		_, err := newRBREvent(eWriteRowsEventV1, tcase.data).Rows(rbrFormat, tm)
+		if err == nil || err.Error() != tcase.want {
+			t.Errorf("Rows() error = %v, want %v", err, tcase.want)
+		}
+	}
+}
+
+func TestCellValue(t *testing.T) {
+	testcases := []struct {
+		typ      byte
+		metadata uint16
+		data     []byte
+		want     sqltypes.Value
+	}{{
+		typ:  typeTiny,
+		data: []byte{0xff},
+		want: sqltypes.MakeTrusted(querypb.Type_INT8, []byte("-1")),
+	}, {
+		typ:  typeShort,
+		data: []byte{0x10, 0x27},
+		want: sqltypes.MakeTrusted(querypb.Type_INT16, []byte("10000")),
+	}, {
+		typ:  typeInt24,
+		data: []byte{0xfe, 0xff, 0xff},
+		want: sqltypes.MakeTrusted(querypb.Type_INT24, []byte("-2")),
+	}, {
+		typ:  typeLongLong,
+		data: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+		want: sqltypes.MakeTrusted(querypb.Type_INT64, []byte("9223372036854775807")),
+	}, {
+		typ:      typeFloat,
+		metadata: 4,
+		data:     []byte{0x00, 0x00, 0xc0, 0x3f},
+		want:     sqltypes.MakeTrusted(querypb.Type_FLOAT32, []byte("1.5")),
+	}, {
+		typ:      typeDouble,
+		metadata: 8,
+		data:     []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x40},
+		want:     sqltypes.MakeTrusted(querypb.Type_FLOAT64, []byte("2.25")),
+	}, {
+		typ:  typeYear,
+		data: []byte{0x74},
+		want: sqltypes.MakeTrusted(querypb.Type_YEAR, []byte("2016")),
+	}, {
+		typ:  typeDate,
+		data: []byte{0x3c, 0xc1, 0x0f},
+		want: sqltypes.MakeTrusted(querypb.Type_DATE, []byte("2016-09-28")),
+	}, {
+		typ:  typeTime,
+		data: []byte{0xc0, 0x1d, 0xfe},
+		want: sqltypes.MakeTrusted(querypb.Type_TIME, []byte("-12:34:56")),
+	}, {
+		typ:      typeTime2,
+		metadata: 2,
+		data:     []byte{0x7f, 0xff, 0xfe, 0xce},
+		want:     sqltypes.MakeTrusted(querypb.Type_TIME, []byte("-00:00:01.50")),
+	}, {
+		typ:  typeTimestamp,
+		data: []byte{0xf7, 0xe5, 0xeb, 0x57},
+		want: sqltypes.MakeTrusted(querypb.Type_TIMESTAMP, []byte("2016-09-28 15:47:03")),
+	}, {
+		typ:      typeTimestamp2,
+		metadata: 3,
+		data:     []byte{0x57, 0xeb, 0xe5, 0xf7, 0x04, 0xce},
+		want:     sqltypes.MakeTrusted(querypb.Type_TIMESTAMP, []byte("2016-09-28 15:47:03.123")),
+	}, {
+		typ:  typeDateTime,
+		data: []byte{0x4f, 0x04, 0xf6, 0x14, 0x56, 0x12, 0x00, 0x00},
+		want: sqltypes.MakeTrusted(querypb.Type_DATETIME, []byte("2016-09-28 15:47:03")),
+	}, {
+		typ:      typeNewDecimal,
+		metadata: 0x0a02,
+		data:     []byte{0x7f, 0xff, 0xfb, 0x2d, 0xc7},
+		want:     sqltypes.MakeTrusted(querypb.Type_DECIMAL, []byte("-1234.56")),
+	}, {
+		typ:      typeNewDecimal,
+		metadata: 0x140a,
+		data:     []byte{0x81, 0x0d, 0xfb, 0x38, 0xd2, 0x00, 0xbc, 0x61, 0x4e, 0x09},
+		want:     sqltypes.MakeTrusted(querypb.Type_DECIMAL, []byte("1234567890.0123456789")),
+	}, {
+		typ:      typeString,
+		metadata: 0xfe0a,
+		data:     []byte{0x02, 'a', 'b'},
+		want:     sqltypes.MakeTrusted(querypb.Type_BINARY, []byte("ab")),
+	}, {
+		typ:      typeString,
+		metadata: 0xf701,
+		data:     []byte{0x02},
+		want:     sqltypes.MakeTrusted(querypb.Type_UINT64, []byte("2")),
+	}, {
+		typ:      typeString,
+		metadata: 0xf802,
+		data:     []byte{0x05, 0x00},
+		want:     sqltypes.MakeTrusted(querypb.Type_UINT64, []byte("5")),
+	}, {
+		typ:      typeBit,
+		metadata: 0x0102,
+		data:     []byte{0x01, 0xff},
+		want:     sqltypes.MakeTrusted(querypb.Type_BIT, []byte{0x01, 0xff}),
+	}, {
+		// {"a": 1, "b": [true, "x"]}
+		typ:      typeJSON,
+		metadata: 4,
+		data: []byte{
+			0x21, 0x00, 0x00, 0x00, // length
+			0x00,                   // small object
+			0x02, 0x00, 0x20, 0x00, // count, size
+			0x12, 0x00, 0x01, 0x00, 0x13, 0x00, 0x01, 0x00, // keys
+			0x05, 0x01, 0x00, 0x02, 0x14, 0x00, // values
+			'a', 'b',
+			0x02, 0x00, 0x0c, 0x00, // small array: count, size
+			0x04, 0x01, 0x00, 0x0c, 0x0a, 0x00, // values
+			0x01, 'x',
+		},
+		want: sqltypes.MakeTrusted(querypb.Type_VARBINARY, []byte(`{"a": 1, "b": [true, "x"]}`)),
+	}, {
+		typ:      typeJSON,
+		metadata: 2,
+		data:     []byte{0x05, 0x00, 0x0c, 0x03, 'a', '"', 'c'},
+		want:     sqltypes.MakeTrusted(querypb.Type_VARBINARY, []byte(`"a\"c"`)),
+	}}
+	for _, tcase := range testcases {
+		got, l, err := cellValue(tcase.data, 0, tcase.typ, tcase.metadata)
+		if err != nil {
+			t.Errorf("cellValue(%v, %v) error: %v", tcase.typ, tcase.metadata, err)
+			continue
+		}
+		if l != len(tcase.data) {
+			t.Errorf("cellValue(%v, %v) length = %v, want %v", tcase.typ, tcase.metadata, l, len(tcase.data))
+		}
+		if !reflect.DeepEqual(got, tcase.want) {
+			t.Errorf("cellValue(%v, %v) = %v, want %v", tcase.typ, tcase.metadata, got, tcase.want)
+		}
+	}
+}
+
+func TestColumnTypeMatches(t *testing.T) {
+	testcases := []struct {
+		typ       byte
+		metadata  uint16
+		fieldType querypb.Type
+		want      bool
+	}{
+		{typ: typeLong, fieldType: sqltypes.Int32, want: true},
+		{typ: typeLong, fieldType: sqltypes.Uint32, want: true},
+		{typ: typeLong, fieldType: sqltypes.Int64, want: false},
+		{typ: typeDateTime2, fieldType: sqltypes.Datetime, want: true},
+		{typ: typeDateTime2, fieldType: sqltypes.Timestamp, want: false},
+		{typ: typeVarchar, metadata: 64, fieldType: sqltypes.VarChar, want: true},
+		{typ: typeVarchar, metadata: 64, fieldType: sqltypes.VarBinary, want: true},
+		{typ: typeVarchar, metadata: 64, fieldType: sqltypes.Text, want: false},
+		{typ: typeBlob, metadata: 2, fieldType: sqltypes.Text, want: true},
+		{typ: typeNewDecimal, metadata: 0x0a02, fieldType: sqltypes.Decimal, want: true},
+		// CHAR(10)
+		{typ: typeString, metadata: 0xfe0a, fieldType: sqltypes.Char, want: true},
+		{typ: typeString, metadata: 0xfe0a, fieldType: sqltypes.Enum, want: false},
+		// ENUM with less than 256 values
+		{typ: typeString, metadata: 0xf701, fieldType: sqltypes.Enum, want: true},
+		{typ: typeString, metadata: 0xf701, fieldType: sqltypes.Char, want: false},
+		// SET with 16 values
+		{typ: typeString, metadata: 0xf802, fieldType: sqltypes.Set, want: true},
+		{typ: typeJSON, metadata: 2, fieldType: sqltypes.Blob, want: false},
+	}
+	for _, tcase := range testcases {
+		if got := ColumnTypeMatches(tcase.typ, tcase.metadata, tcase.fieldType); got != tcase.want {
+			t.Errorf("ColumnTypeMatches(%v, %#x, %v) = %v, want %v", tcase.typ, tcase.metadata, tcase.fieldType, got, tcase.want)
+		}
+	}
+}
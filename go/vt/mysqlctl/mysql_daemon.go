@@ -6,8 +6,10 @@ package mysqlctl
 
 import (
 	"fmt"
+	"path"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/youtube/vitess/go/sqldb"
 	"github.com/youtube/vitess/go/sqltypes"
@@ -53,6 +55,10 @@ type MysqlDaemon interface {
 	SetMasterCommands(masterHost string, masterPort int) ([]string, error)
 	WaitForReparentJournal(ctx context.Context, timeCreatedNS int64) error
 
+	// ApplyBinlogFile replays the transactions of a binlog file
+	// that are not in fromPos, up to toPos or toTime if set.
+	ApplyBinlogFile(ctx context.Context, file string, fromPos, toPos replication.Position, toTime time.Time) error
+
 	// DemoteMaster waits for all current transactions to finish,
 	// and returns the current replication position. It will not
 	// change the read_only state of the server.
@@ -169,6 +175,16 @@ type FakeMysqlDaemon struct {
 	// PromoteSlaveResult is returned by PromoteSlave
 	PromoteSlaveResult replication.Position
 
+	// AppliedBinlogFiles is appended the base name of each
+	// file passed to ApplyBinlogFile.
+	AppliedBinlogFiles []string
+
+	// ApplyBinlogFilePositions maps the base name of a binlog
+	// file to the CurrentMasterPosition ApplyBinlogFile sets after
+	// applying it. If a file is not in there, ApplyBinlogFile
+	// returns an error.
+	ApplyBinlogFilePositions map[string]replication.Position
+
 	// SchemaFunc provides the return value for GetSchema.
 	// If not defined, the "Schema" field will be used instead, see below.
 	SchemaFunc func() (*tabletmanagerdatapb.SchemaDefinition, error)
@@ -254,6 +270,18 @@ func (fmd *FakeMysqlDaemon) RunMysqlUpgrade() error {
 	return nil
 }
 
+// ApplyBinlogFile is part of the MysqlDaemon interface
+func (fmd *FakeMysqlDaemon) ApplyBinlogFile(ctx context.Context, file string, fromPos, toPos replication.Position, toTime time.Time) error {
+	name := path.Base(file)
+	pos, ok := fmd.ApplyBinlogFilePositions[name]
+	if !ok {
+		return fmt.Errorf("unexpected binlog file %v", name)
+	}
+	fmd.AppliedBinlogFiles = append(fmd.AppliedBinlogFiles, name)
+	fmd.CurrentMasterPosition = pos
+	return nil
+}
+
 // ReinitConfig is part of the MysqlDaemon interface
 func (fmd *FakeMysqlDaemon) ReinitConfig(ctx context.Context) error {
 	return nil
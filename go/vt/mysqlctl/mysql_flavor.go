@@ -83,6 +83,11 @@ type MysqlFlavor interface {
 	// state after playback is done.  Whatever it does for a given
 	// flavor, it must be idempotent.
 	DisableBinlogPlayback(mysqld *Mysqld) error
+
+	// BinlogReplayArgs returns the mysqlbinlog arguments to only
+	// output the transactions that are not in fromPos, and, if
+	// toPos is not zero, that are in toPos.
+	BinlogReplayArgs(fromPos, toPos replication.Position) ([]string, error)
 }
 
 var mysqlFlavors = make(map[string]MysqlFlavor)
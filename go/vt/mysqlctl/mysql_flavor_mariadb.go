@@ -196,6 +196,12 @@ func (*mariaDB10) DisableBinlogPlayback(mysqld *Mysqld) error {
 	return nil
 }
 
+// BinlogReplayArgs implements MysqlFlavor.BinlogReplayArgs().
+// MariaDB's mysqlbinlog can't filter transactions by GTID.
+func (*mariaDB10) BinlogReplayArgs(fromPos, toPos replication.Position) ([]string, error) {
+	return nil, fmt.Errorf("replaying binlogs is not supported with MariaDB")
+}
+
 // mariadbBinlogEvent wraps a raw packet buffer and provides methods to examine
 // it by implementing replication.BinlogEvent. Some methods are pulled in from
 // binlogEvent.
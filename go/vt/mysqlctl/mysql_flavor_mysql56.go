@@ -173,6 +173,18 @@ func (*mysql56) DisableBinlogPlayback(mysqld *Mysqld) error {
 	return nil
 }
 
+// BinlogReplayArgs implements MysqlFlavor.BinlogReplayArgs().
+func (*mysql56) BinlogReplayArgs(fromPos, toPos replication.Position) ([]string, error) {
+	var args []string
+	if !fromPos.IsZero() {
+		args = append(args, "--exclude-gtids="+fromPos.GTIDSet.String())
+	}
+	if !toPos.IsZero() {
+		args = append(args, "--include-gtids="+toPos.GTIDSet.String())
+	}
+	return args, nil
+}
+
 // mysql56BinlogEvent wraps a raw packet buffer and provides methods to examine
 // it by implementing replication.BinlogEvent. Some methods are pulled in from
 // binlogEvent.
@@ -178,6 +178,32 @@ func TestMysql56SetSlavePositionCommands(t *testing.T) {
 	}
 }
 
+func TestMysql56BinlogReplayArgs(t *testing.T) {
+	fromPos, _ := (&mysql56{}).ParseReplicationPosition("00010203-0405-0607-0809-0a0b0c0d0e0f:1-2")
+	toPos, _ := (&mysql56{}).ParseReplicationPosition("00010203-0405-0607-0809-0a0b0c0d0e0f:1-5")
+	want := []string{
+		"--exclude-gtids=00010203-0405-0607-0809-0a0b0c0d0e0f:1-2",
+		"--include-gtids=00010203-0405-0607-0809-0a0b0c0d0e0f:1-5",
+	}
+
+	got, err := (&mysql56{}).BinlogReplayArgs(fromPos, toPos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("(&mysql56{}).BinlogReplayArgs(%v, %v) = %#v, want %#v", fromPos, toPos, got, want)
+	}
+
+	// Without a toPos, everything after fromPos is replayed.
+	got, err = (&mysql56{}).BinlogReplayArgs(fromPos, replication.Position{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("(&mysql56{}).BinlogReplayArgs(%v, zero) = %#v, want %#v", fromPos, got, want[:1])
+	}
+}
+
 func TestMysql56SetMasterCommands(t *testing.T) {
 	params := &sqldb.ConnParams{
 		Uname: "username",
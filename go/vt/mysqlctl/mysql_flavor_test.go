@@ -44,6 +44,9 @@ func (fakeMysqlFlavor) SetMasterCommands(params *sqldb.ConnParams, masterHost st
 }
 func (fakeMysqlFlavor) EnableBinlogPlayback(mysqld *Mysqld) error  { return nil }
 func (fakeMysqlFlavor) DisableBinlogPlayback(mysqld *Mysqld) error { return nil }
+func (fakeMysqlFlavor) BinlogReplayArgs(fromPos, toPos replication.Position) ([]string, error) {
+	return nil, nil
+}
 
 func TestMysqlFlavorEnvironmentVariable(t *testing.T) {
 	os.Setenv("MYSQL_FLAVOR", "fake flavor")
@@ -13,6 +13,7 @@ package mysqlctl
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
@@ -34,6 +35,7 @@ import (
 	vtenv "github.com/youtube/vitess/go/vt/env"
 	"github.com/youtube/vitess/go/vt/hook"
 	"github.com/youtube/vitess/go/vt/mysqlctl/mysqlctlclient"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
 	"golang.org/x/net/context"
 )
 
@@ -176,6 +178,86 @@ func (mysqld *Mysqld) RunMysqlUpgrade() error {
 	return err
 }
 
+// ApplyBinlogFile replays a binlog file on the running server, by
+// piping the output of mysqlbinlog into mysql. The transactions in
+// fromPos are skipped. If toPos is not zero, only the transactions in
+// toPos are replayed. If toTime is not zero, replay stops at the first
+// event that happened at or after toTime.
+func (mysqld *Mysqld) ApplyBinlogFile(ctx context.Context, file string, fromPos, toPos replication.Position, toTime time.Time) error {
+	flavor, err := mysqld.flavor()
+	if err != nil {
+		return fmt.Errorf("ApplyBinlogFile needs flavor: %v", err)
+	}
+	flavorArgs, err := flavor.BinlogReplayArgs(fromPos, toPos)
+	if err != nil {
+		return err
+	}
+
+	dir, err := vtenv.VtMysqlRoot()
+	if err != nil {
+		return err
+	}
+	mysqlbinlogName, err := binaryPath(dir, "mysqlbinlog")
+	if err != nil {
+		return err
+	}
+	mysqlName, err := binaryPath(dir, "mysql")
+	if err != nil {
+		return err
+	}
+	env := []string{os.ExpandEnv("LD_LIBRARY_PATH=$VT_MYSQL_ROOT/lib/mysql")}
+
+	// mysqlbinlog reads the file, and writes SQL to its stdout.
+	args := flavorArgs
+	if !toTime.IsZero() {
+		// --stop-datetime uses the local time zone.
+		args = append(args, "--stop-datetime="+toTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	args = append(args, file)
+	mysqlbinlogCmd := exec.Command(mysqlbinlogName, args...)
+	mysqlbinlogCmd.Env = env
+
+	// mysql reads the SQL from its stdin, and executes it.
+	args = []string{
+		// --defaults-file=* must be the first arg.
+		"--defaults-file=" + mysqld.config.path,
+		"--socket", mysqld.config.SocketFile,
+		"--user", mysqld.dba.Uname,
+	}
+	if mysqld.dba.Pass != "" {
+		// --password must be omitted entirely if empty, or else it will prompt.
+		args = append(args, "--password", mysqld.dba.Pass)
+	}
+	mysqlCmd := exec.Command(mysqlName, args...)
+	mysqlCmd.Env = env
+	mysqlCmd.Stdin, err = mysqlbinlogCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var mysqlbinlogErr, mysqlOut bytes.Buffer
+	mysqlbinlogCmd.Stderr = &mysqlbinlogErr
+	mysqlCmd.Stdout = &mysqlOut
+	mysqlCmd.Stderr = &mysqlOut
+
+	log.Infof("ApplyBinlogFile: %v | %v", mysqlbinlogCmd.Args, mysqlName)
+	if err := mysqlbinlogCmd.Start(); err != nil {
+		return fmt.Errorf("can't start mysqlbinlog: %v", err)
+	}
+	if err := mysqlCmd.Start(); err != nil {
+		mysqlbinlogCmd.Process.Kill()
+		mysqlbinlogCmd.Wait()
+		return fmt.Errorf("can't start mysql: %v", err)
+	}
+	binlogErr := mysqlbinlogCmd.Wait()
+	if err := mysqlCmd.Wait(); err != nil {
+		return fmt.Errorf("mysql failed: %v, output: %s", err, mysqlOut.Bytes())
+	}
+	if binlogErr != nil {
+		return fmt.Errorf("mysqlbinlog failed: %v, output: %s", binlogErr, mysqlbinlogErr.Bytes())
+	}
+	return nil
+}
+
 // Start will start the mysql daemon, either by running the 'mysqld_start'
 // hook, or by running mysqld_safe in the background.
 // If a mysqlctld address is provided in a flag, Start will run remotely.
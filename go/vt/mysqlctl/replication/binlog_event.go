@@ -9,6 +9,8 @@ package replication
 import (
 	"fmt"
 
+	"github.com/youtube/vitess/go/sqltypes"
+
 	binlogdatapb "github.com/youtube/vitess/go/vt/proto/binlogdata"
 )
 
@@ -57,6 +59,17 @@ type BinlogEvent interface {
 	// IsPreviousGTIDs returns true if this event is a PREVIOUS_GTIDS_EVENT.
 	IsPreviousGTIDs() bool
 
+	// RBR events.
+
+	// IsTableMap returns true if this is a TABLE_MAP_EVENT.
+	IsTableMap() bool
+	// IsWriteRows returns true if this is a WRITE_ROWS_EVENT (v1 or v2).
+	IsWriteRows() bool
+	// IsUpdateRows returns true if this is an UPDATE_ROWS_EVENT (v1 or v2).
+	IsUpdateRows() bool
+	// IsDeleteRows returns true if this is a DELETE_ROWS_EVENT (v1 or v2).
+	IsDeleteRows() bool
+
 	// Timestamp returns the timestamp from the event header.
 	Timestamp() uint32
 
@@ -84,6 +97,20 @@ type BinlogEvent interface {
 	// This is only valid if IsPreviousGTIDs() returns true.
 	PreviousGTIDs(BinlogFormat) (Position, error)
 
+	// TableID returns the table ID for a TableMap, UpdateRows,
+	// WriteRows or DeleteRows event.
+	TableID(BinlogFormat) uint64
+	// TableMap returns a TableMap struct representing data from a
+	// TABLE_MAP_EVENT.
+	// This is only valid if IsTableMap() returns true.
+	TableMap(BinlogFormat) (*TableMap, error)
+	// Rows returns a Rows struct representing data from a
+	// {WRITE,UPDATE,DELETE}_ROWS_EVENT. The TableMap must be the one
+	// that was sent for the event's TableID.
+	// This is only valid if IsWriteRows(), IsUpdateRows(), or
+	// IsDeleteRows() returns true.
+	Rows(BinlogFormat, *TableMap) (Rows, error)
+
 	// StripChecksum returns the checksum and a modified event with the checksum
 	// stripped off, if any. If there is no checksum, it returns the same event
 	// and a nil checksum.
@@ -121,3 +148,104 @@ func (q Query) String() string {
 	return fmt.Sprintf("{Database: %q, Charset: %v, SQL: %q}",
 		q.Database, q.Charset, q.SQL)
 }
+
+// TableMap contains data from a TABLE_MAP_EVENT.
+type TableMap struct {
+	// Flags is the table map flags field.
+	Flags uint16
+
+	// Database is the name of the database the table belongs to.
+	Database string
+
+	// Name is the name of the table.
+	Name string
+
+	// Types is an array of MySQL binlog types for the columns.
+	Types []byte
+
+	// CanBeNull's bit i is set if column i can be NULL.
+	CanBeNull Bitmap
+
+	// Metadata is an array of uint16, one per column.
+	// It contains a few extra information about each column,
+	// that is dependent on the type.
+	// - If the metadata is not present, this is zero.
+	// - If the metadata is one byte, only the lower 8 bits are used.
+	// - If the metadata is two bytes, all 16 bits are used.
+	Metadata []uint16
+}
+
+// Rows contains data from a {WRITE,UPDATE,DELETE}_ROWS_EVENT.
+type Rows struct {
+	// Flags is the rows event flags field.
+	Flags uint16
+
+	// IdentifyColumns describes which columns are included to
+	// identify the row. It is a bitmap indexed by the TableMap
+	// list of columns.
+	// Set for UPDATE and DELETE.
+	IdentifyColumns Bitmap
+
+	// DataColumns describes which columns are included. It is
+	// a bitmap indexed by the TableMap list of columns.
+	// Set for WRITE and UPDATE.
+	DataColumns Bitmap
+
+	// Rows is an array of Row in the event.
+	Rows []Row
+}
+
+// Row contains the values of a single row of a Rows event.
+// NULL columns are returned as sqltypes.NULL.
+type Row struct {
+	// Identify contains the before image of the row, one value
+	// per column set in Rows.IdentifyColumns.
+	// Set for UPDATE and DELETE.
+	Identify []sqltypes.Value
+
+	// Data contains the after image of the row, one value per
+	// column set in Rows.DataColumns.
+	// Set for WRITE and UPDATE.
+	Data []sqltypes.Value
+}
+
+// Bitmap is used by the different RBR events to represent a set of
+// columns.
+type Bitmap struct {
+	// data is the slice this is based on.
+	data []byte
+
+	// count is the number of bits declared in this bitmap.
+	count int
+}
+
+// NewBitmap returns a Bitmap of count bits, backed by the first
+// (count+7)/8 bytes of data. The least significant bit of the first
+// byte is the first bit, as in the MySQL wire format.
+func NewBitmap(data []byte, count int) Bitmap {
+	return Bitmap{
+		data:  data[:(count+7)/8],
+		count: count,
+	}
+}
+
+// Count returns the number of bits in this Bitmap.
+func (b Bitmap) Count() int {
+	return b.count
+}
+
+// Bit returns the value of a given bit in the Bitmap.
+func (b Bitmap) Bit(index int) bool {
+	return b.data[index/8]&(1<<uint(index%8)) > 0
+}
+
+// BitCount returns how many bits are set in the bitmap.
+func (b Bitmap) BitCount() int {
+	sum := 0
+	for i := 0; i < b.count; i++ {
+		if b.Bit(i) {
+			sum++
+		}
+	}
+	return sum
+}
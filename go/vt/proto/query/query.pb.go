@@ -62,6 +62,7 @@ It has these top-level messages:
 	UpdateStreamRequest
 	UpdateStreamResponse
 	TransactionMetadata
+	TableSchema
 */
 package query
 
@@ -1651,6 +1652,13 @@ type StreamHealthResponse struct {
 	TabletExternallyReparentedTimestamp int64 `protobuf:"varint,3,opt,name=tablet_externally_reparented_timestamp,json=tabletExternallyReparentedTimestamp" json:"tablet_externally_reparented_timestamp,omitempty"`
 	// realtime_stats contains information about the tablet status
 	RealtimeStats *RealtimeStats `protobuf:"bytes,4,opt,name=realtime_stats,json=realtimeStats" json:"realtime_stats,omitempty"`
+	// schema_version identifies the current schema of the tablet.
+	// It changes every time the tablet detects a schema change.
+	SchemaVersion int64 `protobuf:"varint,5,opt,name=schema_version,json=schemaVersion" json:"schema_version,omitempty"`
+	// table_schemas contains the tables of the tablet, with their columns.
+	// It is only sent in the first response of a stream, and in the
+	// responses where schema_version changed.
+	TableSchemas []*TableSchema `protobuf:"bytes,6,rep,name=table_schemas,json=tableSchemas" json:"table_schemas,omitempty"`
 }
 
 func (m *StreamHealthResponse) Reset()                    { *m = StreamHealthResponse{} }
@@ -1672,6 +1680,13 @@ func (m *StreamHealthResponse) GetRealtimeStats() *RealtimeStats {
 	return nil
 }
 
+func (m *StreamHealthResponse) GetTableSchemas() []*TableSchema {
+	if m != nil {
+		return m.TableSchemas
+	}
+	return nil
+}
+
 // UpdateStreamRequest is the payload for UpdateStream. At most one of
 // position and timestamp can be set. If neither is set, we will start
 // streaming from the current binlog position.
@@ -1751,6 +1766,25 @@ func (m *TransactionMetadata) GetParticipants() []*Target {
 	return nil
 }
 
+// TableSchema describes the columns of a table, as published by
+// StreamHealth.
+type TableSchema struct {
+	Name    string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Columns []*Field `protobuf:"bytes,2,rep,name=columns" json:"columns,omitempty"`
+}
+
+func (m *TableSchema) Reset()                    { *m = TableSchema{} }
+func (m *TableSchema) String() string            { return proto.CompactTextString(m) }
+func (*TableSchema) ProtoMessage()               {}
+func (*TableSchema) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *TableSchema) GetColumns() []*Field {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Target)(nil), "query.Target")
 	proto.RegisterType((*VTGateCallerID)(nil), "query.VTGateCallerID")
@@ -1806,6 +1840,7 @@ func init() {
 	proto.RegisterType((*UpdateStreamRequest)(nil), "query.UpdateStreamRequest")
 	proto.RegisterType((*UpdateStreamResponse)(nil), "query.UpdateStreamResponse")
 	proto.RegisterType((*TransactionMetadata)(nil), "query.TransactionMetadata")
+	proto.RegisterType((*TableSchema)(nil), "query.TableSchema")
 	proto.RegisterEnum("query.Flag", Flag_name, Flag_value)
 	proto.RegisterEnum("query.Type", Type_name, Type_value)
 	proto.RegisterEnum("query.TransactionState", TransactionState_name, TransactionState_value)
@@ -1816,154 +1851,158 @@ func init() {
 func init() { proto.RegisterFile("query.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2376 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xec, 0x5a, 0xdb, 0x6f, 0x1b, 0x59,
-	0x19, 0xef, 0xf8, 0x16, 0xfb, 0x73, 0x9c, 0x9c, 0x1c, 0xa7, 0x5b, 0x6f, 0xb6, 0xb0, 0x65, 0xf6,
-	0x56, 0xda, 0x12, 0xba, 0x6e, 0x08, 0xd5, 0xb2, 0x40, 0x1d, 0xc7, 0xe9, 0x5a, 0x75, 0x1c, 0xf7,
-	0x78, 0x1c, 0x28, 0x5a, 0x69, 0x74, 0x62, 0x9f, 0x26, 0xa3, 0xd8, 0x33, 0xd3, 0x99, 0xe3, 0xa4,
-	0x7e, 0x2b, 0xbb, 0xdc, 0xaf, 0x45, 0x5c, 0x96, 0x8b, 0xb4, 0x20, 0xf1, 0x27, 0xf0, 0x8c, 0x84,
-	0xf8, 0x03, 0x90, 0x78, 0x05, 0x89, 0x37, 0x84, 0x78, 0x82, 0x67, 0x1e, 0x10, 0x3a, 0x67, 0xce,
-	0x8c, 0xc7, 0x89, 0xbb, 0xed, 0x96, 0xa7, 0xa4, 0xfb, 0xe4, 0x73, 0xbe, 0xef, 0x9b, 0xf3, 0x9d,
-	0xdf, 0x77, 0x3b, 0x37, 0x43, 0xfe, 0xde, 0x90, 0x79, 0xa3, 0x65, 0xd7, 0x73, 0xb8, 0x83, 0xd3,
-	0xb2, 0xb3, 0x34, 0xc7, 0x1d, 0xd7, 0xe9, 0x51, 0x4e, 0x03, 0xf2, 0x52, 0xfe, 0x80, 0x7b, 0x6e,
-	0x37, 0xe8, 0xe8, 0xf7, 0x20, 0x63, 0x50, 0x6f, 0x97, 0x71, 0xbc, 0x04, 0xd9, 0x7d, 0x36, 0xf2,
-	0x5d, 0xda, 0x65, 0x25, 0xed, 0x82, 0x76, 0x31, 0x47, 0xa2, 0x3e, 0x5e, 0x84, 0xb4, 0xbf, 0x47,
-	0xbd, 0x5e, 0x29, 0x21, 0x19, 0x41, 0x07, 0x7f, 0x06, 0xf2, 0x9c, 0xee, 0xf4, 0x19, 0x37, 0xf9,
-	0xc8, 0x65, 0xa5, 0xe4, 0x05, 0xed, 0xe2, 0x5c, 0x79, 0x71, 0x39, 0x52, 0x67, 0x48, 0xa6, 0x31,
-	0x72, 0x19, 0x01, 0x1e, 0xb5, 0xf5, 0x2b, 0x30, 0xb7, 0x6d, 0xdc, 0xa4, 0x9c, 0x55, 0x69, 0xbf,
-	0xcf, 0xbc, 0xfa, 0xba, 0x50, 0x3d, 0xf4, 0x99, 0x67, 0xd3, 0x41, 0xa4, 0x3a, 0xec, 0xeb, 0x6f,
-	0x03, 0xd4, 0x0e, 0x98, 0xcd, 0x0d, 0x67, 0x9f, 0xd9, 0xf8, 0x3c, 0xe4, 0xb8, 0x35, 0x60, 0x3e,
-	0xa7, 0x03, 0x57, 0x8a, 0x26, 0xc9, 0x98, 0xf0, 0x88, 0x69, 0x2e, 0x41, 0xd6, 0x75, 0x7c, 0x8b,
-	0x5b, 0x8e, 0x2d, 0xe7, 0x98, 0x23, 0x51, 0x5f, 0xff, 0x02, 0xa4, 0xb7, 0x69, 0x7f, 0xc8, 0xf0,
-	0x8b, 0x90, 0x92, 0x20, 0x34, 0x09, 0x22, 0xbf, 0x1c, 0xd8, 0x51, 0xce, 0x5d, 0x32, 0xc4, 0xd8,
-	0x07, 0x42, 0x52, 0x8e, 0x3d, 0x4b, 0x82, 0x8e, 0xbe, 0x0f, 0xb3, 0x6b, 0x96, 0xdd, 0xdb, 0xa6,
-	0x9e, 0x25, 0x00, 0x3e, 0xe5, 0x30, 0xf8, 0x65, 0xc8, 0xc8, 0x86, 0x5f, 0x4a, 0x5e, 0x48, 0x5e,
-	0xcc, 0x97, 0x67, 0xd5, 0x87, 0x72, 0x6e, 0x44, 0xf1, 0xf4, 0x3f, 0x6a, 0x00, 0x6b, 0xce, 0xd0,
-	0xee, 0xdd, 0x16, 0x4c, 0x8c, 0x20, 0xe9, 0xdf, 0xeb, 0x2b, 0x83, 0x89, 0x26, 0xbe, 0x05, 0x73,
-	0x3b, 0x96, 0xdd, 0x33, 0x0f, 0xd4, 0x74, 0xfc, 0x52, 0x42, 0x0e, 0xf7, 0xb2, 0x1a, 0x6e, 0xfc,
-	0xf1, 0x72, 0x7c, 0xd6, 0x7e, 0xcd, 0xe6, 0xde, 0x88, 0x14, 0x76, 0xe2, 0xb4, 0xa5, 0x0e, 0xe0,
-	0xe3, 0x42, 0x42, 0xe9, 0x3e, 0x1b, 0x85, 0x4a, 0xf7, 0xd9, 0x08, 0x7f, 0x32, 0x8e, 0x28, 0x5f,
-	0x2e, 0x86, 0xba, 0x62, 0xdf, 0x2a, 0x98, 0x6f, 0x24, 0xae, 0x6b, 0xfa, 0xef, 0x34, 0x98, 0xab,
-	0xdd, 0x67, 0xdd, 0x21, 0x67, 0x5b, 0xae, 0xf0, 0x81, 0x8f, 0x97, 0xa1, 0xc8, 0xee, 0x77, 0xfb,
-	0xc3, 0x1e, 0x33, 0xef, 0x5a, 0xac, 0xdf, 0x33, 0x85, 0xe3, 0x7d, 0xa9, 0x23, 0x4b, 0x16, 0x14,
-	0x6b, 0x43, 0x70, 0x9a, 0x82, 0x21, 0xe4, 0x2d, 0x3b, 0x90, 0x67, 0x22, 0x34, 0x4c, 0x2e, 0x62,
-	0x43, 0xea, 0xcf, 0x92, 0x05, 0xc5, 0x8a, 0x05, 0x4d, 0x05, 0x8a, 0x5d, 0x67, 0xe0, 0x52, 0x6f,
-	0x52, 0x3e, 0x29, 0xe7, 0xbb, 0xa0, 0xe6, 0x3b, 0x96, 0x27, 0x0b, 0x4a, 0x7a, 0x4c, 0xd2, 0xdf,
-	0x84, 0xb4, 0x9c, 0x00, 0xc6, 0x90, 0x8a, 0x85, 0xa9, 0x6c, 0x47, 0x4e, 0x4f, 0x3c, 0xc2, 0xe9,
-	0xfa, 0x67, 0x21, 0x49, 0x9c, 0x43, 0x5c, 0x82, 0x99, 0x3e, 0xb3, 0x77, 0xf9, 0x9e, 0xc0, 0x96,
-	0xbc, 0x88, 0x49, 0xd8, 0xc5, 0xcf, 0x45, 0xfe, 0x0f, 0xc2, 0x22, 0xf4, 0xf8, 0xdb, 0x30, 0x4b,
-	0x98, 0x3f, 0xec, 0xf3, 0xda, 0x7d, 0xee, 0x51, 0x1f, 0x97, 0x21, 0x1f, 0x47, 0xa0, 0x3d, 0x0a,
-	0x01, 0xb0, 0x31, 0xfa, 0x12, 0xcc, 0xdc, 0xf5, 0x98, 0xbf, 0xc7, 0x3c, 0x65, 0xa1, 0xb0, 0x2b,
-	0xe2, 0x29, 0x2f, 0xa3, 0x21, 0xd0, 0x21, 0xa2, 0x50, 0xda, 0x3f, 0x98, 0xde, 0x38, 0x0a, 0x25,
-	0x72, 0xa2, 0x78, 0xf8, 0x25, 0x28, 0x78, 0xce, 0xa1, 0x6f, 0xd2, 0xbb, 0x77, 0x59, 0x97, 0xb3,
-	0x20, 0xd9, 0x52, 0x64, 0x56, 0x10, 0x2b, 0x8a, 0x86, 0x5f, 0x80, 0x9c, 0x65, 0xfb, 0xcc, 0xe3,
-	0xa6, 0xd5, 0x93, 0x86, 0x4e, 0x91, 0x6c, 0x40, 0xa8, 0xf7, 0xf0, 0xc7, 0x21, 0x25, 0x84, 0x4b,
-	0x29, 0xa9, 0x05, 0x94, 0x16, 0xe2, 0x1c, 0x12, 0x49, 0xc7, 0x97, 0x21, 0xc3, 0x24, 0xde, 0x52,
-	0x7a, 0x22, 0xa4, 0xe2, 0xa6, 0x20, 0x4a, 0x44, 0xff, 0x4d, 0x12, 0xf2, 0x6d, 0xee, 0x31, 0x3a,
-	0x90, 0xf8, 0xf1, 0x9b, 0x00, 0x3e, 0xa7, 0x9c, 0x0d, 0x98, 0xcd, 0x43, 0x20, 0xe7, 0xd5, 0x00,
-	0x31, 0xb9, 0xe5, 0x76, 0x28, 0x44, 0x62, 0xf2, 0x47, 0x0d, 0x9c, 0x78, 0x02, 0x03, 0x2f, 0xbd,
-	0x9f, 0x80, 0x5c, 0x34, 0x1a, 0xae, 0x40, 0xb6, 0x4b, 0x39, 0xdb, 0x75, 0xbc, 0x91, 0xaa, 0x02,
-	0xaf, 0x7c, 0x90, 0xf6, 0xe5, 0xaa, 0x12, 0x26, 0xd1, 0x67, 0xf8, 0x63, 0x10, 0x94, 0x4b, 0x99,
-	0x07, 0xaa, 0x96, 0xe5, 0x24, 0x45, 0xc4, 0x3f, 0x7e, 0x03, 0xb0, 0xeb, 0x59, 0x03, 0xea, 0x8d,
-	0xcc, 0x7d, 0x36, 0x32, 0x95, 0xcb, 0x92, 0x53, 0x5c, 0x86, 0x94, 0xdc, 0x2d, 0x36, 0xda, 0x08,
-	0x9c, 0x77, 0x7d, 0xf2, 0x5b, 0x15, 0x74, 0xc7, 0x1d, 0x11, 0xfb, 0x52, 0xd6, 0x20, 0x3f, 0xac,
-	0x36, 0x69, 0x19, 0x9f, 0xa2, 0xa9, 0xbf, 0x06, 0xd9, 0x70, 0xf2, 0x38, 0x07, 0xe9, 0x9a, 0xe7,
-	0x39, 0x1e, 0x3a, 0x83, 0x67, 0x20, 0xb9, 0xbe, 0xd9, 0x40, 0x9a, 0x6c, 0xac, 0x37, 0x50, 0x42,
-	0xff, 0x43, 0x22, 0x4a, 0x79, 0xc2, 0xee, 0x0d, 0x99, 0xcf, 0xf1, 0x17, 0xa1, 0xc8, 0x64, 0xac,
-	0x58, 0x07, 0xcc, 0xec, 0xca, 0x75, 0x40, 0x44, 0x4a, 0x10, 0xd0, 0xf3, 0xcb, 0xc1, 0x0a, 0x15,
-	0xae, 0x0f, 0x64, 0x21, 0x92, 0x55, 0xa4, 0x1e, 0xae, 0x41, 0xd1, 0x1a, 0x0c, 0x58, 0xcf, 0xa2,
-	0x3c, 0x3e, 0x40, 0xe0, 0xb0, 0xb3, 0x61, 0xf9, 0x9c, 0x58, 0x66, 0xc8, 0x42, 0xf4, 0x45, 0x34,
-	0xcc, 0x2b, 0x90, 0xe1, 0x72, 0xf9, 0x53, 0xd5, 0xa0, 0x10, 0x26, 0xaf, 0x24, 0x12, 0xc5, 0xc4,
-	0xaf, 0x41, 0xb0, 0x96, 0x96, 0x52, 0x13, 0x01, 0x31, 0xae, 0xa7, 0x24, 0xe0, 0xe3, 0x57, 0x60,
-	0x8e, 0x7b, 0xd4, 0xf6, 0x69, 0x57, 0x94, 0x36, 0x31, 0xa3, 0xb4, 0x5c, 0xa4, 0x0a, 0x31, 0x6a,
-	0xbd, 0x87, 0x3f, 0x0d, 0x33, 0x4e, 0x50, 0xfc, 0x4a, 0x99, 0x89, 0x19, 0x4f, 0x56, 0x46, 0x12,
-	0x4a, 0xe9, 0x9f, 0x87, 0xf9, 0xc8, 0x82, 0xbe, 0xeb, 0xd8, 0x3e, 0xc3, 0x97, 0x20, 0xe3, 0xc9,
-	0x84, 0x50, 0x56, 0xc3, 0x6a, 0x88, 0x58, 0x46, 0x13, 0x25, 0xa1, 0xff, 0x3b, 0x01, 0x45, 0xf5,
-	0xfd, 0x1a, 0xe5, 0xdd, 0xbd, 0x13, 0xea, 0x86, 0xcb, 0x30, 0x23, 0xe8, 0x56, 0x14, 0xb2, 0x53,
-	0x1c, 0x11, 0x4a, 0x08, 0x57, 0x50, 0xdf, 0x8c, 0xd9, 0x5d, 0xba, 0x22, 0x4b, 0x0a, 0xd4, 0x37,
-	0xc6, 0xc4, 0x29, 0x1e, 0xcb, 0x3c, 0xc6, 0x63, 0x33, 0x4f, 0xe4, 0xb1, 0x75, 0x58, 0x9c, 0xb4,
-	0xb8, 0x72, 0xdb, 0x15, 0x98, 0x09, 0x9c, 0x12, 0x16, 0xa7, 0x69, 0x7e, 0x0b, 0x45, 0xf4, 0x5f,
-	0x27, 0x60, 0x51, 0xd5, 0x8d, 0x67, 0x23, 0x81, 0x62, 0x76, 0x4e, 0x3f, 0x91, 0x9d, 0xab, 0x70,
-	0xf6, 0x88, 0x81, 0x9e, 0x22, 0x3f, 0x7e, 0xaf, 0xc1, 0xec, 0x1a, 0xdb, 0xb5, 0xec, 0x93, 0x69,
-	0x5e, 0x7d, 0x15, 0x0a, 0x6a, 0xfa, 0x0a, 0xfc, 0xf1, 0xa8, 0xd6, 0xa6, 0x44, 0xb5, 0xfe, 0x77,
-	0x0d, 0x0a, 0x55, 0x67, 0x30, 0xb0, 0xf8, 0x09, 0x8d, 0xab, 0xe3, 0x38, 0x53, 0xd3, 0x70, 0x22,
-	0x98, 0x0b, 0x61, 0x06, 0x06, 0xd2, 0xff, 0xa1, 0xc1, 0x3c, 0x71, 0xfa, 0xfd, 0x1d, 0xda, 0xdd,
-	0x3f, 0xdd, 0xd8, 0x31, 0xa0, 0x31, 0x50, 0x85, 0xfe, 0x3f, 0x1a, 0xcc, 0xb5, 0x3c, 0x26, 0x36,
-	0xb9, 0xa7, 0x1a, 0xbc, 0xd8, 0xae, 0xf7, 0xb8, 0x5a, 0x85, 0x73, 0x44, 0xb6, 0xf5, 0x05, 0x98,
-	0x8f, 0xb0, 0x2b, 0x7b, 0xfc, 0x45, 0x83, 0xb3, 0x41, 0x80, 0x28, 0x4e, 0xef, 0x84, 0x9a, 0x25,
-	0xc4, 0x9b, 0x8a, 0xe1, 0x2d, 0xc1, 0x73, 0x47, 0xb1, 0x29, 0xd8, 0xef, 0x26, 0xe0, 0x5c, 0x18,
-	0x1b, 0x27, 0x1c, 0xf8, 0xff, 0x11, 0x0f, 0x4b, 0x50, 0x3a, 0x6e, 0x04, 0x65, 0xa1, 0x87, 0x09,
-	0x28, 0x55, 0x3d, 0x46, 0x39, 0x8b, 0xed, 0x19, 0x4e, 0x4f, 0x6c, 0xe0, 0xd7, 0x61, 0xd6, 0xa5,
-	0x1e, 0xb7, 0xba, 0x96, 0x4b, 0xc5, 0x79, 0x29, 0x2d, 0xb7, 0x24, 0x47, 0x06, 0x98, 0x10, 0xd1,
-	0x5f, 0x80, 0xe7, 0xa7, 0x58, 0x44, 0xd9, 0xeb, 0xbf, 0x1a, 0xe0, 0x36, 0xa7, 0x1e, 0x7f, 0x06,
-	0x56, 0x95, 0xa9, 0xc1, 0x74, 0x16, 0x8a, 0x13, 0xf8, 0xe3, 0x76, 0x61, 0xfc, 0x99, 0x58, 0x71,
-	0x1e, 0x69, 0x97, 0x38, 0x7e, 0x65, 0x97, 0xbf, 0x69, 0xf0, 0x3c, 0x61, 0xbe, 0xd3, 0x3f, 0x38,
-	0x9d, 0x09, 0xa6, 0x9f, 0x87, 0xa5, 0x69, 0xf8, 0x14, 0xfc, 0xbf, 0x6a, 0xf0, 0x1c, 0x61, 0xb4,
-	0x77, 0x3a, 0xb1, 0xdf, 0x86, 0x73, 0xc7, 0xc0, 0xa9, 0xfd, 0xe9, 0x2a, 0x64, 0x07, 0x8c, 0xd3,
-	0x1e, 0xe5, 0x54, 0x41, 0x5a, 0x0a, 0xc7, 0x1d, 0x4b, 0x6f, 0x2a, 0x09, 0x12, 0xc9, 0xea, 0xef,
-	0x27, 0xa0, 0x28, 0x77, 0xba, 0x1f, 0x1d, 0x87, 0xa6, 0x1f, 0x87, 0x1e, 0x6a, 0xb0, 0x38, 0x69,
-	0xa0, 0xe8, 0x44, 0x90, 0x66, 0x9e, 0xe7, 0x78, 0x47, 0x6c, 0x42, 0x5a, 0x55, 0x79, 0x71, 0x43,
-	0x02, 0x6e, 0xec, 0xd4, 0x94, 0x78, 0xdc, 0xa9, 0x69, 0x4a, 0x39, 0x48, 0x4e, 0xdb, 0x80, 0xfe,
-	0x29, 0x01, 0xa5, 0xf8, 0x94, 0x3e, 0xba, 0x81, 0x98, 0xbc, 0x81, 0xf8, 0xd0, 0x97, 0x41, 0xef,
-	0x69, 0xf0, 0xfc, 0x14, 0x83, 0x7e, 0x38, 0x47, 0xc7, 0xee, 0x21, 0x12, 0x8f, 0xbd, 0x87, 0x78,
-	0x52, 0x57, 0xbf, 0x93, 0x82, 0x85, 0xb6, 0xdb, 0xb7, 0xb8, 0x1a, 0xe4, 0x74, 0x27, 0xe7, 0x27,
-	0x60, 0xd6, 0x17, 0x60, 0xcd, 0xae, 0xd3, 0x1f, 0x0e, 0x6c, 0xb9, 0x79, 0xca, 0x91, 0xbc, 0xa4,
-	0x55, 0x25, 0x09, 0xbf, 0x08, 0xf9, 0x50, 0x64, 0x68, 0x73, 0x75, 0xb5, 0x04, 0x4a, 0x62, 0x68,
-	0x73, 0xbc, 0x02, 0xe7, 0xec, 0xe1, 0xc0, 0x94, 0x37, 0xea, 0x2e, 0xf3, 0x4c, 0x39, 0xb2, 0x29,
-	0x36, 0x5c, 0xa5, 0xac, 0x14, 0x2e, 0xda, 0xc3, 0x01, 0x71, 0x0e, 0xfd, 0x16, 0xf3, 0xa4, 0xf2,
-	0x16, 0xf5, 0x38, 0xbe, 0x01, 0x39, 0xda, 0xdf, 0x75, 0x3c, 0x8b, 0xef, 0x0d, 0x4a, 0x39, 0x79,
-	0xcb, 0xac, 0x87, 0xb7, 0xcc, 0x47, 0xcd, 0xbf, 0x5c, 0x09, 0x25, 0xc9, 0xf8, 0x23, 0x7c, 0x19,
-	0xf0, 0xd0, 0x67, 0x66, 0x30, 0xb9, 0x40, 0xe9, 0x41, 0xb9, 0x04, 0x32, 0x3e, 0xe7, 0x87, 0x3e,
-	0x1b, 0x0f, 0xb3, 0x5d, 0xd6, 0xaf, 0x40, 0x2e, 0x1a, 0x04, 0x23, 0x98, 0xad, 0xdd, 0xee, 0x54,
-	0x1a, 0x66, 0xbb, 0xd5, 0xa8, 0x1b, 0x6d, 0x74, 0x06, 0x17, 0x20, 0xb7, 0xd1, 0x69, 0x34, 0xcc,
-	0x76, 0xb5, 0xd2, 0x44, 0x9a, 0x4e, 0x00, 0xe4, 0x87, 0x72, 0x88, 0xb1, 0x35, 0xb5, 0xc7, 0x58,
-	0xf3, 0x05, 0xc8, 0x79, 0xce, 0xa1, 0x32, 0x54, 0x42, 0x62, 0xcf, 0x7a, 0xce, 0xa1, 0x34, 0x93,
-	0x5e, 0x01, 0x1c, 0x07, 0xa6, 0x42, 0x3d, 0x96, 0x8d, 0xda, 0x44, 0x36, 0x8e, 0xf5, 0x47, 0xd9,
-	0x18, 0xec, 0xcc, 0x3c, 0x46, 0x07, 0x6f, 0x31, 0xda, 0xe7, 0x61, 0x01, 0xd2, 0x7f, 0x9b, 0x80,
-	0x02, 0x11, 0x14, 0x6b, 0xc0, 0xda, 0x9c, 0x72, 0x5f, 0xb8, 0x75, 0x4f, 0x8a, 0x98, 0xe3, 0x3c,
-	0xca, 0x91, 0x7c, 0x40, 0x93, 0x39, 0x84, 0xcb, 0x70, 0xd6, 0x67, 0x5d, 0xc7, 0xee, 0xf9, 0xe6,
-	0x0e, 0xdb, 0xb3, 0xec, 0x9e, 0x39, 0xa0, 0x3e, 0x57, 0x2f, 0x2c, 0x05, 0x52, 0x54, 0xcc, 0x35,
-	0xc9, 0xdb, 0x94, 0x2c, 0x7c, 0x15, 0x16, 0x77, 0x2c, 0xbb, 0xef, 0xec, 0x9a, 0x6e, 0x9f, 0x8e,
-	0x98, 0xe7, 0x2b, 0xa8, 0x22, 0x16, 0xd3, 0x04, 0x07, 0xbc, 0x56, 0xc0, 0x0a, 0x62, 0xe3, 0x2b,
-	0x70, 0x69, 0xaa, 0x16, 0xf3, 0xae, 0xd5, 0xe7, 0xcc, 0x63, 0x3d, 0xd3, 0x63, 0x6e, 0xdf, 0xea,
-	0x52, 0x59, 0x5b, 0x82, 0xad, 0xd8, 0xab, 0x53, 0x54, 0x6f, 0x28, 0x71, 0x32, 0x96, 0x16, 0xd6,
-	0xee, 0xba, 0x43, 0x73, 0xe8, 0xd3, 0x5d, 0x26, 0xcb, 0x92, 0x46, 0xb2, 0x5d, 0x77, 0xd8, 0x11,
-	0x7d, 0x8c, 0x20, 0x79, 0xcf, 0x0d, 0xaa, 0x91, 0x46, 0x44, 0x53, 0xff, 0xa7, 0x16, 0xde, 0x43,
-	0x86, 0xd6, 0x8b, 0xaa, 0x4d, 0x98, 0x53, 0xda, 0x07, 0xe5, 0x54, 0x09, 0x66, 0x7c, 0xe6, 0x1d,
-	0x58, 0xf6, 0x6e, 0xf8, 0x08, 0xa5, 0xba, 0xb8, 0x0d, 0xaf, 0xaa, 0x47, 0x64, 0x76, 0x9f, 0x33,
-	0xcf, 0xa6, 0xfd, 0xfe, 0xc8, 0x0c, 0x8e, 0x61, 0x36, 0x67, 0x3d, 0x73, 0xfc, 0xdc, 0x1b, 0x54,
-	0x9c, 0x97, 0x02, 0xe9, 0x5a, 0x24, 0x4c, 0x22, 0x59, 0x23, 0x7a, 0x08, 0xfe, 0x1c, 0xcc, 0x79,
-	0xca, 0xa7, 0xa6, 0x2f, 0x9c, 0xaa, 0x72, 0x79, 0x31, 0x7a, 0x49, 0x8a, 0x39, 0x9c, 0x14, 0xbc,
-	0x78, 0x57, 0xec, 0xd5, 0x8b, 0x1d, 0xb7, 0x47, 0x39, 0x0b, 0x10, 0x9f, 0xd0, 0x32, 0x16, 0x7f,
-	0xf6, 0x4e, 0x4d, 0x3e, 0x7b, 0x4f, 0x3e, 0xa3, 0xa7, 0x8f, 0x3c, 0xa3, 0xeb, 0x37, 0x60, 0x71,
-	0x12, 0xbf, 0xf2, 0xf5, 0x45, 0x48, 0xcb, 0x67, 0xaf, 0x23, 0x17, 0xaa, 0xb1, 0x77, 0x2d, 0x12,
-	0x08, 0xe8, 0x7f, 0xd6, 0xa0, 0x38, 0x65, 0x23, 0x17, 0xed, 0x12, 0xb5, 0xd8, 0x11, 0xf4, 0x53,
-	0x90, 0x96, 0x0f, 0x70, 0xea, 0xf9, 0xf4, 0xdc, 0xf1, 0x7d, 0xa0, 0x7c, 0x2c, 0x23, 0x81, 0x94,
-	0xc8, 0x4e, 0xe9, 0xd6, 0xae, 0x3c, 0x83, 0x86, 0xeb, 0x50, 0x5e, 0xd0, 0x82, 0x63, 0x69, 0x2f,
-	0x12, 0x19, 0x4a, 0x10, 0xe1, 0x21, 0x45, 0x8a, 0x04, 0xb8, 0x9e, 0xe6, 0xdc, 0x7b, 0x69, 0x1f,
-	0x52, 0x1b, 0x7d, 0xba, 0x8b, 0xb3, 0x90, 0x6a, 0x6e, 0x35, 0x6b, 0xe8, 0x0c, 0x9e, 0x07, 0xa8,
-	0xb7, 0xeb, 0x4d, 0xa3, 0x76, 0x93, 0x54, 0x1a, 0xe8, 0x41, 0x22, 0x20, 0x74, 0x9a, 0xed, 0xfa,
-	0xcd, 0x66, 0x6d, 0x1d, 0x3d, 0x48, 0xe1, 0x59, 0x98, 0xa9, 0xb7, 0x37, 0x1a, 0x5b, 0x15, 0x03,
-	0x3d, 0xc8, 0xe2, 0x02, 0x64, 0xeb, 0xed, 0xdb, 0x9d, 0x2d, 0x43, 0x30, 0x11, 0xce, 0x43, 0xa6,
-	0xde, 0x36, 0x6a, 0x5f, 0x36, 0xd0, 0x83, 0x0b, 0x01, 0x6f, 0xad, 0xde, 0xac, 0x90, 0x3b, 0xe8,
-	0xc1, 0x8d, 0x4b, 0xff, 0x4a, 0x40, 0xca, 0x18, 0xb9, 0x4c, 0xd4, 0xd6, 0xa6, 0xa8, 0xad, 0xc6,
-	0x9d, 0x96, 0x50, 0x99, 0x83, 0x54, 0xbd, 0x69, 0x5c, 0x47, 0x5f, 0x4d, 0x60, 0x80, 0x74, 0x47,
-	0xb6, 0xdf, 0xc9, 0x88, 0x76, 0xbd, 0x69, 0xbc, 0xbe, 0x8a, 0xde, 0x4d, 0x88, 0x61, 0x3b, 0x41,
-	0xe7, 0x6b, 0x21, 0xa3, 0xbc, 0x82, 0xbe, 0x1e, 0x31, 0xca, 0x2b, 0xe8, 0x1b, 0x21, 0xe3, 0x5a,
-	0x19, 0x7d, 0x33, 0x62, 0x5c, 0x2b, 0xa3, 0x6f, 0x85, 0x8c, 0xd5, 0x15, 0xf4, 0xed, 0x88, 0xb1,
-	0xba, 0x82, 0xbe, 0x93, 0x11, 0x58, 0x24, 0x92, 0x6b, 0x65, 0xf4, 0xdd, 0x6c, 0xd4, 0x5b, 0x5d,
-	0x41, 0xdf, 0xcb, 0xe2, 0x39, 0xc8, 0x19, 0xf5, 0xcd, 0x5a, 0xdb, 0xa8, 0x6c, 0xb6, 0xd0, 0xf7,
-	0x91, 0x98, 0xe6, 0x7a, 0xc5, 0xa8, 0xa1, 0x1f, 0xc8, 0xa6, 0x60, 0xa1, 0x1f, 0x22, 0x81, 0x51,
-	0x50, 0x65, 0xf7, 0xa1, 0xe4, 0xdc, 0xa9, 0x55, 0x08, 0xfa, 0x51, 0x06, 0xe7, 0x61, 0x66, 0xbd,
-	0x56, 0xad, 0x6f, 0x56, 0x1a, 0x08, 0xcb, 0x2f, 0x84, 0x55, 0x7e, 0x7c, 0x55, 0x34, 0xd7, 0x1a,
-	0x5b, 0x6b, 0xe8, 0x27, 0x2d, 0xa1, 0x70, 0xbb, 0x42, 0xaa, 0x6f, 0x55, 0x08, 0xfa, 0xe9, 0x55,
-	0xa1, 0x70, 0xbb, 0x42, 0x94, 0xbd, 0x7e, 0xd6, 0x12, 0x82, 0x92, 0xf5, 0xde, 0x55, 0x31, 0x69,
-	0x45, 0xff, 0x79, 0x0b, 0x67, 0x21, 0xb9, 0x56, 0x37, 0xd0, 0x2f, 0xa4, 0xb6, 0x5a, 0xb3, 0xb3,
-	0x89, 0x7e, 0x89, 0x04, 0xb1, 0x5d, 0x33, 0xd0, 0xaf, 0x04, 0x31, 0x6d, 0x74, 0x5a, 0x8d, 0x1a,
-	0x3a, 0x7f, 0x69, 0x03, 0xd0, 0xd1, 0x90, 0x13, 0xd3, 0xea, 0x34, 0x6f, 0x35, 0xb7, 0xbe, 0xd4,
-	0x44, 0x67, 0x44, 0xa7, 0x45, 0x6a, 0xad, 0x0a, 0xa9, 0x21, 0x0d, 0x03, 0x64, 0xaa, 0x5b, 0x9b,
-	0x9b, 0x75, 0x03, 0x25, 0xf0, 0x2c, 0x64, 0xc9, 0x56, 0xa3, 0xb1, 0x56, 0xa9, 0xde, 0x42, 0xc9,
-	0xb5, 0x25, 0x28, 0x75, 0x9d, 0xc1, 0xf2, 0xc8, 0x19, 0xf2, 0xe1, 0x0e, 0x5b, 0x3e, 0xb0, 0x38,
-	0xf3, 0xfd, 0xe0, 0xcf, 0x36, 0x3b, 0x19, 0xf9, 0x73, 0xed, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff,
-	0xe1, 0x6c, 0x20, 0x20, 0xa6, 0x23, 0x00, 0x00,
+	// 2434 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xdb, 0x6f, 0x1b, 0xc7,
+	0xd5, 0xf7, 0xf2, 0x26, 0xf2, 0x90, 0x94, 0x56, 0x43, 0x39, 0x66, 0x14, 0x7f, 0x5f, 0xdc, 0xcd,
+	0xcd, 0x75, 0x52, 0xd5, 0xa1, 0x55, 0x25, 0x48, 0xd3, 0x36, 0x14, 0x45, 0x39, 0x84, 0x29, 0x8a,
+	0x1e, 0x92, 0x6a, 0x5d, 0x04, 0x58, 0x8c, 0xc8, 0xb1, 0xb4, 0x10, 0xb9, 0x4b, 0xcf, 0x0e, 0x25,
+	0xf3, 0xcd, 0x4d, 0x7a, 0xbf, 0xba, 0xe8, 0x25, 0xbd, 0x00, 0x69, 0x81, 0xfe, 0x09, 0x7d, 0x2e,
+	0x50, 0xf4, 0xad, 0x2f, 0x05, 0xfa, 0xda, 0x02, 0x7d, 0x2b, 0xfa, 0xd8, 0x3e, 0xf7, 0xa1, 0x28,
+	0xe6, 0xb2, 0xcb, 0xa5, 0x44, 0xc7, 0x8e, 0xfb, 0x24, 0x39, 0x4f, 0x9c, 0x39, 0xe7, 0xec, 0xcc,
+	0xfc, 0x7e, 0xe7, 0xcc, 0x99, 0x1b, 0x21, 0x7b, 0x67, 0x44, 0xd9, 0x78, 0x65, 0xc8, 0x3c, 0xee,
+	0xa1, 0xa4, 0xac, 0x2c, 0xcf, 0x73, 0x6f, 0xe8, 0xf5, 0x08, 0x27, 0x4a, 0xbc, 0x9c, 0x3d, 0xe4,
+	0x6c, 0xd8, 0x55, 0x15, 0xeb, 0x0e, 0xa4, 0xda, 0x84, 0xed, 0x51, 0x8e, 0x96, 0x21, 0x7d, 0x40,
+	0xc7, 0xfe, 0x90, 0x74, 0x69, 0xd1, 0xb8, 0x64, 0x5c, 0xce, 0xe0, 0xb0, 0x8e, 0x96, 0x20, 0xe9,
+	0xef, 0x13, 0xd6, 0x2b, 0xc6, 0xa4, 0x42, 0x55, 0xd0, 0x67, 0x20, 0xcb, 0xc9, 0x6e, 0x9f, 0x72,
+	0x9b, 0x8f, 0x87, 0xb4, 0x18, 0xbf, 0x64, 0x5c, 0x9e, 0x2f, 0x2d, 0xad, 0x84, 0xdd, 0xb5, 0xa5,
+	0xb2, 0x3d, 0x1e, 0x52, 0x0c, 0x3c, 0x2c, 0x5b, 0xaf, 0xc0, 0xfc, 0x4e, 0xfb, 0x3a, 0xe1, 0xb4,
+	0x42, 0xfa, 0x7d, 0xca, 0x6a, 0x1b, 0xa2, 0xeb, 0x91, 0x4f, 0x99, 0x4b, 0x06, 0x61, 0xd7, 0x41,
+	0xdd, 0x7a, 0x07, 0xa0, 0x7a, 0x48, 0x5d, 0xde, 0xf6, 0x0e, 0xa8, 0x8b, 0x2e, 0x42, 0x86, 0x3b,
+	0x03, 0xea, 0x73, 0x32, 0x18, 0x4a, 0xd3, 0x38, 0x9e, 0x08, 0x1e, 0x30, 0xcc, 0x65, 0x48, 0x0f,
+	0x3d, 0xdf, 0xe1, 0x8e, 0xe7, 0xca, 0x31, 0x66, 0x70, 0x58, 0xb7, 0x3e, 0x0f, 0xc9, 0x1d, 0xd2,
+	0x1f, 0x51, 0xf4, 0x2c, 0x24, 0x24, 0x08, 0x43, 0x82, 0xc8, 0xae, 0x28, 0x1e, 0xe5, 0xd8, 0xa5,
+	0x42, 0xb4, 0x7d, 0x28, 0x2c, 0x65, 0xdb, 0x39, 0xac, 0x2a, 0xd6, 0x01, 0xe4, 0xd6, 0x1d, 0xb7,
+	0xb7, 0x43, 0x98, 0x23, 0x00, 0x3e, 0x66, 0x33, 0xe8, 0x79, 0x48, 0xc9, 0x82, 0x5f, 0x8c, 0x5f,
+	0x8a, 0x5f, 0xce, 0x96, 0x72, 0xfa, 0x43, 0x39, 0x36, 0xac, 0x75, 0xd6, 0x1f, 0x0c, 0x80, 0x75,
+	0x6f, 0xe4, 0xf6, 0x6e, 0x0a, 0x25, 0x32, 0x21, 0xee, 0xdf, 0xe9, 0x6b, 0xc2, 0x44, 0x11, 0xdd,
+	0x80, 0xf9, 0x5d, 0xc7, 0xed, 0xd9, 0x87, 0x7a, 0x38, 0x7e, 0x31, 0x26, 0x9b, 0x7b, 0x5e, 0x37,
+	0x37, 0xf9, 0x78, 0x25, 0x3a, 0x6a, 0xbf, 0xea, 0x72, 0x36, 0xc6, 0xf9, 0xdd, 0xa8, 0x6c, 0xb9,
+	0x03, 0xe8, 0xa4, 0x91, 0xe8, 0xf4, 0x80, 0x8e, 0x83, 0x4e, 0x0f, 0xe8, 0x18, 0x7d, 0x32, 0x8a,
+	0x28, 0x5b, 0x2a, 0x04, 0x7d, 0x45, 0xbe, 0xd5, 0x30, 0xdf, 0x88, 0xbd, 0x6e, 0x58, 0xbf, 0x35,
+	0x60, 0xbe, 0x7a, 0x97, 0x76, 0x47, 0x9c, 0x6e, 0x0f, 0x85, 0x0f, 0x7c, 0xb4, 0x02, 0x05, 0x7a,
+	0xb7, 0xdb, 0x1f, 0xf5, 0xa8, 0x7d, 0xdb, 0xa1, 0xfd, 0x9e, 0x2d, 0x1c, 0xef, 0xcb, 0x3e, 0xd2,
+	0x78, 0x51, 0xab, 0x36, 0x85, 0xa6, 0x21, 0x14, 0xc2, 0xde, 0x71, 0x95, 0x3d, 0x15, 0xa1, 0x61,
+	0x73, 0x11, 0x1b, 0xb2, 0xff, 0x34, 0x5e, 0xd4, 0xaa, 0x48, 0xd0, 0x94, 0xa1, 0xd0, 0xf5, 0x06,
+	0x43, 0xc2, 0xa6, 0xed, 0xe3, 0x72, 0xbc, 0x8b, 0x7a, 0xbc, 0x13, 0x7b, 0xbc, 0xa8, 0xad, 0x27,
+	0x22, 0xeb, 0x4d, 0x48, 0xca, 0x01, 0x20, 0x04, 0x89, 0x48, 0x98, 0xca, 0x72, 0xe8, 0xf4, 0xd8,
+	0x03, 0x9c, 0x6e, 0xbd, 0x06, 0x71, 0xec, 0x1d, 0xa1, 0x22, 0xcc, 0xf5, 0xa9, 0xbb, 0xc7, 0xf7,
+	0x05, 0xb6, 0xf8, 0x65, 0x84, 0x83, 0x2a, 0x7a, 0x2a, 0xf4, 0xbf, 0x0a, 0x8b, 0xc0, 0xe3, 0xef,
+	0x40, 0x0e, 0x53, 0x7f, 0xd4, 0xe7, 0xd5, 0xbb, 0x9c, 0x11, 0x1f, 0x95, 0x20, 0x1b, 0x45, 0x60,
+	0x3c, 0x08, 0x01, 0xd0, 0x09, 0xfa, 0x22, 0xcc, 0xdd, 0x66, 0xd4, 0xdf, 0xa7, 0x4c, 0x33, 0x14,
+	0x54, 0x45, 0x3c, 0x65, 0x65, 0x34, 0xa8, 0x3e, 0x44, 0x14, 0x4a, 0xfe, 0xd5, 0xf0, 0x26, 0x51,
+	0x28, 0x91, 0x63, 0xad, 0x43, 0xcf, 0x41, 0x9e, 0x79, 0x47, 0xbe, 0x4d, 0x6e, 0xdf, 0xa6, 0x5d,
+	0x4e, 0xd5, 0x64, 0x4b, 0xe0, 0x9c, 0x10, 0x96, 0xb5, 0x0c, 0x3d, 0x03, 0x19, 0xc7, 0xf5, 0x29,
+	0xe3, 0xb6, 0xd3, 0x93, 0x44, 0x27, 0x70, 0x5a, 0x09, 0x6a, 0x3d, 0xf4, 0xff, 0x90, 0x10, 0xc6,
+	0xc5, 0x84, 0xec, 0x05, 0x74, 0x2f, 0xd8, 0x3b, 0xc2, 0x52, 0x8e, 0x5e, 0x86, 0x14, 0x95, 0x78,
+	0x8b, 0xc9, 0xa9, 0x90, 0x8a, 0x52, 0x81, 0xb5, 0x89, 0xf5, 0xeb, 0x38, 0x64, 0x5b, 0x9c, 0x51,
+	0x32, 0x90, 0xf8, 0xd1, 0x9b, 0x00, 0x3e, 0x27, 0x9c, 0x0e, 0xa8, 0xcb, 0x03, 0x20, 0x17, 0x75,
+	0x03, 0x11, 0xbb, 0x95, 0x56, 0x60, 0x84, 0x23, 0xf6, 0xc7, 0x09, 0x8e, 0x3d, 0x02, 0xc1, 0xcb,
+	0x1f, 0xc4, 0x20, 0x13, 0xb6, 0x86, 0xca, 0x90, 0xee, 0x12, 0x4e, 0xf7, 0x3c, 0x36, 0xd6, 0x59,
+	0xe0, 0x85, 0x0f, 0xeb, 0x7d, 0xa5, 0xa2, 0x8d, 0x71, 0xf8, 0x19, 0xfa, 0x3f, 0x50, 0xe9, 0x52,
+	0xce, 0x03, 0x9d, 0xcb, 0x32, 0x52, 0x22, 0xe2, 0x1f, 0xbd, 0x01, 0x68, 0xc8, 0x9c, 0x01, 0x61,
+	0x63, 0xfb, 0x80, 0x8e, 0x6d, 0xed, 0xb2, 0xf8, 0x0c, 0x97, 0x99, 0xda, 0xee, 0x06, 0x1d, 0x6f,
+	0x2a, 0xe7, 0xbd, 0x3e, 0xfd, 0xad, 0x0e, 0xba, 0x93, 0x8e, 0x88, 0x7c, 0x29, 0x73, 0x90, 0x1f,
+	0x64, 0x9b, 0xa4, 0x8c, 0x4f, 0x51, 0xb4, 0x5e, 0x82, 0x74, 0x30, 0x78, 0x94, 0x81, 0x64, 0x95,
+	0x31, 0x8f, 0x99, 0xe7, 0xd0, 0x1c, 0xc4, 0x37, 0xb6, 0xea, 0xa6, 0x21, 0x0b, 0x1b, 0x75, 0x33,
+	0x66, 0xfd, 0x3e, 0x16, 0x4e, 0x79, 0x4c, 0xef, 0x8c, 0xa8, 0xcf, 0xd1, 0x17, 0xa0, 0x40, 0x65,
+	0xac, 0x38, 0x87, 0xd4, 0xee, 0xca, 0x75, 0x40, 0x44, 0x8a, 0x0a, 0xe8, 0x85, 0x15, 0xb5, 0x42,
+	0x05, 0xeb, 0x03, 0x5e, 0x0c, 0x6d, 0xb5, 0xa8, 0x87, 0xaa, 0x50, 0x70, 0x06, 0x03, 0xda, 0x73,
+	0x08, 0x8f, 0x36, 0xa0, 0x1c, 0x76, 0x3e, 0x48, 0x9f, 0x53, 0xcb, 0x0c, 0x5e, 0x0c, 0xbf, 0x08,
+	0x9b, 0x79, 0x01, 0x52, 0x5c, 0x2e, 0x7f, 0x3a, 0x1b, 0xe4, 0x83, 0xc9, 0x2b, 0x85, 0x58, 0x2b,
+	0xd1, 0x4b, 0xa0, 0xd6, 0xd2, 0x62, 0x62, 0x2a, 0x20, 0x26, 0xf9, 0x14, 0x2b, 0x3d, 0x7a, 0x01,
+	0xe6, 0x39, 0x23, 0xae, 0x4f, 0xba, 0x22, 0xb5, 0x89, 0x11, 0x25, 0xe5, 0x22, 0x95, 0x8f, 0x48,
+	0x6b, 0x3d, 0xf4, 0x69, 0x98, 0xf3, 0x54, 0xf2, 0x2b, 0xa6, 0xa6, 0x46, 0x3c, 0x9d, 0x19, 0x71,
+	0x60, 0x65, 0x7d, 0x0e, 0x16, 0x42, 0x06, 0xfd, 0xa1, 0xe7, 0xfa, 0x14, 0x5d, 0x81, 0x14, 0x93,
+	0x13, 0x42, 0xb3, 0x86, 0x74, 0x13, 0x91, 0x19, 0x8d, 0xb5, 0x85, 0xf5, 0xaf, 0x18, 0x14, 0xf4,
+	0xf7, 0xeb, 0x84, 0x77, 0xf7, 0x4f, 0xa9, 0x1b, 0x5e, 0x86, 0x39, 0x21, 0x77, 0xc2, 0x90, 0x9d,
+	0xe1, 0x88, 0xc0, 0x42, 0xb8, 0x82, 0xf8, 0x76, 0x84, 0x77, 0xe9, 0x8a, 0x34, 0xce, 0x13, 0xbf,
+	0x3d, 0x11, 0xce, 0xf0, 0x58, 0xea, 0x21, 0x1e, 0x9b, 0x7b, 0x24, 0x8f, 0x6d, 0xc0, 0xd2, 0x34,
+	0xe3, 0xda, 0x6d, 0xaf, 0xc0, 0x9c, 0x72, 0x4a, 0x90, 0x9c, 0x66, 0xf9, 0x2d, 0x30, 0xb1, 0x7e,
+	0x15, 0x83, 0x25, 0x9d, 0x37, 0x9e, 0x8c, 0x09, 0x14, 0xe1, 0x39, 0xf9, 0x48, 0x3c, 0x57, 0xe0,
+	0xfc, 0x31, 0x82, 0x1e, 0x63, 0x7e, 0xfc, 0xce, 0x80, 0xdc, 0x3a, 0xdd, 0x73, 0xdc, 0xd3, 0x49,
+	0xaf, 0xb5, 0x06, 0x79, 0x3d, 0x7c, 0x0d, 0xfe, 0x64, 0x54, 0x1b, 0x33, 0xa2, 0xda, 0xfa, 0xbb,
+	0x01, 0xf9, 0x8a, 0x37, 0x18, 0x38, 0xfc, 0x94, 0xc6, 0xd5, 0x49, 0x9c, 0x89, 0x59, 0x38, 0x4d,
+	0x98, 0x0f, 0x60, 0x2a, 0x82, 0xac, 0x7f, 0x18, 0xb0, 0x80, 0xbd, 0x7e, 0x7f, 0x97, 0x74, 0x0f,
+	0xce, 0x36, 0x76, 0x04, 0xe6, 0x04, 0xa8, 0x46, 0xff, 0x6f, 0x03, 0xe6, 0x9b, 0x8c, 0x0e, 0x09,
+	0xa3, 0x67, 0x1a, 0xbc, 0xd8, 0xae, 0xf7, 0xb8, 0x5e, 0x85, 0x33, 0x58, 0x96, 0xad, 0x45, 0x58,
+	0x08, 0xb1, 0x6b, 0x3e, 0xfe, 0x62, 0xc0, 0x79, 0x15, 0x20, 0x5a, 0xd3, 0x3b, 0xa5, 0xb4, 0x04,
+	0x78, 0x13, 0x11, 0xbc, 0x45, 0x78, 0xea, 0x38, 0x36, 0x0d, 0xfb, 0xbd, 0x18, 0x5c, 0x08, 0x62,
+	0xe3, 0x94, 0x03, 0xff, 0x1f, 0xe2, 0x61, 0x19, 0x8a, 0x27, 0x49, 0xd0, 0x0c, 0xdd, 0x8f, 0x41,
+	0xb1, 0xc2, 0x28, 0xe1, 0x34, 0xb2, 0x67, 0x38, 0x3b, 0xb1, 0x81, 0x5e, 0x85, 0xdc, 0x90, 0x30,
+	0xee, 0x74, 0x9d, 0x21, 0x11, 0xe7, 0xa5, 0xe4, 0xa5, 0xf8, 0xc9, 0x06, 0xa6, 0x4c, 0xac, 0x67,
+	0xe0, 0xe9, 0x19, 0x8c, 0x68, 0xbe, 0xfe, 0x63, 0x00, 0x6a, 0x71, 0xc2, 0xf8, 0x13, 0xb0, 0xaa,
+	0xcc, 0x0c, 0xa6, 0xf3, 0x50, 0x98, 0xc2, 0x1f, 0xe5, 0x85, 0xf2, 0x27, 0x62, 0xc5, 0x79, 0x20,
+	0x2f, 0x51, 0xfc, 0x9a, 0x97, 0xbf, 0x19, 0xf0, 0x34, 0xa6, 0xbe, 0xd7, 0x3f, 0x3c, 0x9b, 0x13,
+	0xcc, 0xba, 0x08, 0xcb, 0xb3, 0xf0, 0x69, 0xf8, 0x7f, 0x35, 0xe0, 0x29, 0x4c, 0x49, 0xef, 0x6c,
+	0x62, 0xbf, 0x09, 0x17, 0x4e, 0x80, 0xd3, 0xfb, 0xd3, 0x35, 0x48, 0x0f, 0x28, 0x27, 0x3d, 0xc2,
+	0x89, 0x86, 0xb4, 0x1c, 0xb4, 0x3b, 0xb1, 0xde, 0xd2, 0x16, 0x38, 0xb4, 0xb5, 0x3e, 0x88, 0x41,
+	0x41, 0xee, 0x74, 0x3f, 0x3e, 0x0e, 0xcd, 0x3e, 0x0e, 0xdd, 0x37, 0x60, 0x69, 0x9a, 0xa0, 0xf0,
+	0x44, 0x90, 0xa4, 0x8c, 0x79, 0xec, 0x18, 0x27, 0xb8, 0x59, 0x91, 0x17, 0x37, 0x58, 0x69, 0x23,
+	0xa7, 0xa6, 0xd8, 0xc3, 0x4e, 0x4d, 0x33, 0xd2, 0x41, 0x7c, 0xd6, 0x06, 0xf4, 0x4f, 0x31, 0x28,
+	0x46, 0x87, 0xf4, 0xf1, 0x0d, 0xc4, 0xf4, 0x0d, 0xc4, 0x47, 0xbe, 0x0c, 0x7a, 0xdf, 0x80, 0xa7,
+	0x67, 0x10, 0xfa, 0xd1, 0x1c, 0x1d, 0xb9, 0x87, 0x88, 0x3d, 0xf4, 0x1e, 0xe2, 0x51, 0x5d, 0xfd,
+	0x6e, 0x02, 0x16, 0x5b, 0xc3, 0xbe, 0xc3, 0x75, 0x23, 0x67, 0x7b, 0x72, 0x7e, 0x02, 0x72, 0xbe,
+	0x00, 0x6b, 0x77, 0xbd, 0xfe, 0x68, 0xe0, 0xca, 0xcd, 0x53, 0x06, 0x67, 0xa5, 0xac, 0x22, 0x45,
+	0xe8, 0x59, 0xc8, 0x06, 0x26, 0x23, 0x97, 0xeb, 0xab, 0x25, 0xd0, 0x16, 0x23, 0x97, 0xa3, 0x55,
+	0xb8, 0xe0, 0x8e, 0x06, 0xb6, 0xbc, 0x51, 0x1f, 0x52, 0x66, 0xcb, 0x96, 0x6d, 0xb1, 0xe1, 0x2a,
+	0xa6, 0xa5, 0x71, 0xc1, 0x1d, 0x0d, 0xb0, 0x77, 0xe4, 0x37, 0x29, 0x93, 0x9d, 0x37, 0x09, 0xe3,
+	0xe8, 0x2d, 0xc8, 0x90, 0xfe, 0x9e, 0xc7, 0x1c, 0xbe, 0x3f, 0x28, 0x66, 0xe4, 0x2d, 0xb3, 0x15,
+	0xdc, 0x32, 0x1f, 0xa7, 0x7f, 0xa5, 0x1c, 0x58, 0xe2, 0xc9, 0x47, 0xe8, 0x65, 0x40, 0x23, 0x9f,
+	0xda, 0x6a, 0x70, 0xaa, 0xd3, 0xc3, 0x52, 0x11, 0x64, 0x7c, 0x2e, 0x8c, 0x7c, 0x3a, 0x69, 0x66,
+	0xa7, 0x64, 0xbd, 0x02, 0x99, 0xb0, 0x11, 0x64, 0x42, 0xae, 0x7a, 0xb3, 0x53, 0xae, 0xdb, 0xad,
+	0x66, 0xbd, 0xd6, 0x6e, 0x99, 0xe7, 0x50, 0x1e, 0x32, 0x9b, 0x9d, 0x7a, 0xdd, 0x6e, 0x55, 0xca,
+	0x0d, 0xd3, 0xb0, 0x30, 0x80, 0xfc, 0x50, 0x36, 0x31, 0x61, 0xd3, 0x78, 0x08, 0x9b, 0xcf, 0x40,
+	0x86, 0x79, 0x47, 0x9a, 0xa8, 0x98, 0xc4, 0x9e, 0x66, 0xde, 0x91, 0xa4, 0xc9, 0x2a, 0x03, 0x8a,
+	0x02, 0xd3, 0xa1, 0x1e, 0x99, 0x8d, 0xc6, 0xd4, 0x6c, 0x9c, 0xf4, 0x1f, 0xce, 0x46, 0xb5, 0x33,
+	0x63, 0x94, 0x0c, 0xde, 0xa6, 0xa4, 0xcf, 0x83, 0x04, 0x64, 0xfd, 0x26, 0x06, 0x79, 0x2c, 0x24,
+	0xce, 0x80, 0x8a, 0x5b, 0x79, 0x5f, 0xb8, 0x75, 0x5f, 0x9a, 0xd8, 0x93, 0x79, 0x94, 0xc1, 0x59,
+	0x25, 0x93, 0x73, 0x08, 0x95, 0xe0, 0xbc, 0x4f, 0xbb, 0x9e, 0xdb, 0xf3, 0xed, 0x5d, 0xba, 0x2f,
+	0x9e, 0xdc, 0x06, 0xc4, 0xe7, 0xfa, 0x85, 0x25, 0x8f, 0x0b, 0x5a, 0xb9, 0x2e, 0x75, 0x5b, 0x52,
+	0x85, 0xae, 0xc2, 0xd2, 0xae, 0xe3, 0xf6, 0xbd, 0x3d, 0x7b, 0xd8, 0x27, 0x63, 0xca, 0x7c, 0x0d,
+	0x55, 0xc4, 0x62, 0x12, 0x23, 0xa5, 0x6b, 0x2a, 0x95, 0x8a, 0x8d, 0x2f, 0xc3, 0x95, 0x99, 0xbd,
+	0xd8, 0xb7, 0x9d, 0x3e, 0xa7, 0x8c, 0xf6, 0x6c, 0x46, 0x87, 0x7d, 0xa7, 0x4b, 0x64, 0x6e, 0x51,
+	0x5b, 0xb1, 0x17, 0x67, 0x74, 0xbd, 0xa9, 0xcd, 0xf1, 0xc4, 0x5a, 0xb0, 0xdd, 0x1d, 0x8e, 0xec,
+	0x91, 0x4f, 0xf6, 0xa8, 0x4c, 0x4b, 0x06, 0x4e, 0x77, 0x87, 0xa3, 0x8e, 0xa8, 0x8b, 0xbb, 0xfe,
+	0x3b, 0x43, 0x95, 0x8d, 0x0c, 0x2c, 0x8a, 0xd6, 0x1f, 0xc3, 0x7b, 0xc8, 0x80, 0xbd, 0x30, 0xdb,
+	0x04, 0x73, 0xca, 0xf8, 0xb0, 0x39, 0x55, 0x84, 0x39, 0x9f, 0xb2, 0x43, 0xc7, 0xdd, 0x0b, 0x1e,
+	0xa1, 0x74, 0x15, 0xb5, 0xe0, 0x45, 0xfd, 0x88, 0x4c, 0xef, 0x72, 0xca, 0x5c, 0xd2, 0xef, 0x8f,
+	0x6d, 0x75, 0x0c, 0x73, 0x39, 0xed, 0xd9, 0x93, 0xe7, 0x5e, 0x95, 0x71, 0x9e, 0x53, 0xd6, 0xd5,
+	0xd0, 0x18, 0x87, 0xb6, 0xed, 0xc0, 0x14, 0x7d, 0x16, 0xe6, 0x99, 0xf6, 0xa9, 0xed, 0x0b, 0xa7,
+	0xea, 0xb9, 0xbc, 0x14, 0xbe, 0x24, 0x45, 0x1c, 0x8e, 0xf3, 0x2c, 0x5a, 0x15, 0xb9, 0xce, 0xef,
+	0xee, 0xd3, 0x01, 0xb1, 0x0f, 0x29, 0xf3, 0x83, 0xb4, 0x1d, 0xc7, 0x79, 0x25, 0xdd, 0x51, 0x42,
+	0xf4, 0x1a, 0xe4, 0xd5, 0x2b, 0x8d, 0x12, 0x0b, 0xba, 0xa2, 0x69, 0x54, 0x3e, 0x7e, 0xb7, 0xa4,
+	0x0a, 0xe7, 0xf8, 0xa4, 0xe2, 0x8b, 0xb3, 0x40, 0xa1, 0x33, 0xec, 0x11, 0x4e, 0x15, 0xa3, 0xa7,
+	0x34, 0x4d, 0x46, 0x9f, 0xd5, 0x13, 0xd3, 0xcf, 0xea, 0xd3, 0xcf, 0xf4, 0xc9, 0x63, 0xcf, 0xf4,
+	0xd6, 0x5b, 0xb0, 0x34, 0x8d, 0x5f, 0xc7, 0xd2, 0x65, 0x48, 0xca, 0x67, 0xb5, 0x63, 0x17, 0xb6,
+	0x91, 0x77, 0x33, 0xac, 0x0c, 0xac, 0x3f, 0x1b, 0x50, 0x98, 0xb1, 0x51, 0x0c, 0x77, 0xa1, 0x46,
+	0xe4, 0x88, 0xfb, 0x29, 0x48, 0x8a, 0x10, 0x08, 0x9e, 0x67, 0x2f, 0x9c, 0xdc, 0x67, 0x0a, 0xb7,
+	0x53, 0xac, 0xac, 0xc4, 0xec, 0x97, 0x61, 0xd3, 0x95, 0x67, 0xdc, 0x60, 0x9d, 0xcb, 0x0a, 0x99,
+	0x3a, 0xf6, 0xf6, 0x42, 0x93, 0x91, 0x04, 0x11, 0x1c, 0x82, 0xa4, 0x89, 0xc2, 0xf5, 0x58, 0xe7,
+	0xea, 0x1a, 0x64, 0x23, 0x31, 0x33, 0xf3, 0xa1, 0xf9, 0x45, 0x98, 0x53, 0x4b, 0x4d, 0xb0, 0x66,
+	0x4f, 0x3f, 0xf7, 0x05, 0xca, 0x2b, 0x07, 0x90, 0xd8, 0xec, 0x93, 0x3d, 0x94, 0x86, 0x44, 0x63,
+	0xbb, 0x51, 0x35, 0xcf, 0xa1, 0x05, 0x80, 0x5a, 0xab, 0xd6, 0x68, 0x57, 0xaf, 0xe3, 0x72, 0xdd,
+	0xbc, 0x17, 0x53, 0x82, 0x4e, 0xa3, 0x55, 0xbb, 0xde, 0xa8, 0x6e, 0x98, 0xf7, 0x12, 0x28, 0x07,
+	0x73, 0xb5, 0xd6, 0x66, 0x7d, 0xbb, 0xdc, 0x36, 0xef, 0xa5, 0x51, 0x1e, 0xd2, 0xb5, 0xd6, 0xcd,
+	0xce, 0x76, 0x5b, 0x28, 0x4d, 0x94, 0x85, 0x54, 0xad, 0xd5, 0xae, 0x7e, 0xa9, 0x6d, 0xde, 0xbb,
+	0xa4, 0x74, 0xeb, 0xb5, 0x46, 0x19, 0xdf, 0x32, 0xef, 0xbd, 0x75, 0xe5, 0x9f, 0x31, 0x48, 0x88,
+	0xb7, 0x6e, 0xb1, 0x0c, 0x34, 0xc4, 0x32, 0xd0, 0xbe, 0xd5, 0x14, 0x5d, 0x66, 0x20, 0x51, 0x6b,
+	0xb4, 0x5f, 0x37, 0xbf, 0x12, 0x43, 0x00, 0xc9, 0x8e, 0x2c, 0xbf, 0x9b, 0x12, 0xe5, 0x5a, 0xa3,
+	0xfd, 0xea, 0x9a, 0xf9, 0x5e, 0x4c, 0x34, 0xdb, 0x51, 0x95, 0xaf, 0x06, 0x8a, 0xd2, 0xaa, 0xf9,
+	0xb5, 0x50, 0x51, 0x5a, 0x35, 0xbf, 0x1e, 0x28, 0xae, 0x95, 0xcc, 0x6f, 0x84, 0x8a, 0x6b, 0x25,
+	0xf3, 0x9b, 0x81, 0x62, 0x6d, 0xd5, 0xfc, 0x56, 0xa8, 0x58, 0x5b, 0x35, 0xbf, 0x9d, 0x12, 0x58,
+	0x24, 0x92, 0x6b, 0x25, 0xf3, 0x3b, 0xe9, 0xb0, 0xb6, 0xb6, 0x6a, 0x7e, 0x37, 0x8d, 0xe6, 0x21,
+	0xd3, 0xae, 0x6d, 0x55, 0x5b, 0xed, 0xf2, 0x56, 0xd3, 0xfc, 0x9e, 0x29, 0x86, 0xb9, 0x51, 0x6e,
+	0x57, 0xcd, 0xef, 0xcb, 0xa2, 0x50, 0x99, 0x3f, 0x30, 0x05, 0x46, 0x21, 0x95, 0xd5, 0xfb, 0x52,
+	0x73, 0xab, 0x5a, 0xc6, 0xe6, 0x0f, 0x53, 0x28, 0x0b, 0x73, 0x1b, 0xd5, 0x4a, 0x6d, 0xab, 0x5c,
+	0x37, 0x91, 0xfc, 0x42, 0xb0, 0xf2, 0xa3, 0xab, 0xa2, 0xb8, 0x5e, 0xdf, 0x5e, 0x37, 0x7f, 0xdc,
+	0x14, 0x1d, 0xee, 0x94, 0x71, 0xe5, 0xed, 0x32, 0x36, 0x7f, 0x72, 0x55, 0x74, 0xb8, 0x53, 0xc6,
+	0x9a, 0xaf, 0x9f, 0x36, 0x85, 0xa1, 0x54, 0xbd, 0x7f, 0x55, 0x0c, 0x5a, 0xcb, 0x7f, 0xd6, 0x44,
+	0x69, 0x88, 0xaf, 0xd7, 0xda, 0xe6, 0xcf, 0x65, 0x6f, 0xd5, 0x46, 0x67, 0xcb, 0xfc, 0x85, 0x29,
+	0x84, 0xad, 0x6a, 0xdb, 0xfc, 0xa5, 0x10, 0x26, 0xdb, 0x9d, 0x66, 0xbd, 0x6a, 0x5e, 0xbc, 0xb2,
+	0x09, 0xe6, 0xf1, 0xe8, 0x15, 0xc3, 0xea, 0x34, 0x6e, 0x34, 0xb6, 0xbf, 0xd8, 0x30, 0xcf, 0x89,
+	0x4a, 0x13, 0x57, 0x9b, 0x65, 0x5c, 0x35, 0x0d, 0x04, 0x90, 0xaa, 0x6c, 0x6f, 0x6d, 0xd5, 0xda,
+	0x66, 0x0c, 0xe5, 0x20, 0x8d, 0xb7, 0xeb, 0xf5, 0xf5, 0x72, 0xe5, 0x86, 0x19, 0x5f, 0x5f, 0x86,
+	0x62, 0xd7, 0x1b, 0xac, 0x8c, 0xbd, 0x11, 0x1f, 0xed, 0xd2, 0x95, 0x43, 0x87, 0x53, 0xdf, 0x57,
+	0xff, 0x0b, 0xda, 0x4d, 0xc9, 0x9f, 0x6b, 0xff, 0x1d, 0x00, 0xea, 0xc4, 0x02, 0xef, 0x51, 0x24,
+	0x00, 0x00,
 }
@@ -6,10 +6,14 @@
 Package tableacl is a generated protocol buffer package.
 
 It is generated from these files:
+
 	tableacl.proto
 
 It has these top-level messages:
+
 	TableGroupSpec
+	ColumnDenySpec
+	RowFilterSpec
 	Config
 */
 package tableacl
@@ -37,6 +41,10 @@ type TableGroupSpec struct {
 	Readers              []string `protobuf:"bytes,3,rep,name=readers" json:"readers,omitempty"`
 	Writers              []string `protobuf:"bytes,4,rep,name=writers" json:"writers,omitempty"`
 	Admins               []string `protobuf:"bytes,5,rep,name=admins" json:"admins,omitempty"`
+	// column_denies restrict the columns the readers can read.
+	ColumnDenies []*ColumnDenySpec `protobuf:"bytes,6,rep,name=column_denies,json=columnDenies" json:"column_denies,omitempty"`
+	// row_filters restrict the rows the readers can read.
+	RowFilters []*RowFilterSpec `protobuf:"bytes,7,rep,name=row_filters,json=rowFilters" json:"row_filters,omitempty"`
 }
 
 func (m *TableGroupSpec) Reset()                    { *m = TableGroupSpec{} }
@@ -44,6 +52,49 @@ func (m *TableGroupSpec) String() string            { return proto.CompactTextSt
 func (*TableGroupSpec) ProtoMessage()               {}
 func (*TableGroupSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+func (m *TableGroupSpec) GetColumnDenies() []*ColumnDenySpec {
+	if m != nil {
+		return m.ColumnDenies
+	}
+	return nil
+}
+
+func (m *TableGroupSpec) GetRowFilters() []*RowFilterSpec {
+	if m != nil {
+		return m.RowFilters
+	}
+	return nil
+}
+
+// ColumnDenySpec denies reading some columns of a group of tables
+// to a list of principals.
+type ColumnDenySpec struct {
+	Columns    []string `protobuf:"bytes,1,rep,name=columns" json:"columns,omitempty"`
+	Principals []string `protobuf:"bytes,2,rep,name=principals" json:"principals,omitempty"`
+	// if mask is set, the values of the columns are replaced with NULL
+	// in the results, instead of rejecting the queries which read them.
+	Mask bool `protobuf:"varint,3,opt,name=mask" json:"mask,omitempty"`
+}
+
+func (m *ColumnDenySpec) Reset()                    { *m = ColumnDenySpec{} }
+func (m *ColumnDenySpec) String() string            { return proto.CompactTextString(m) }
+func (*ColumnDenySpec) ProtoMessage()               {}
+func (*ColumnDenySpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+// RowFilterSpec restricts the rows of a group of tables a list of
+// principals can read.
+type RowFilterSpec struct {
+	Principals []string `protobuf:"bytes,1,rep,name=principals" json:"principals,omitempty"`
+	// predicate is a SQL boolean expression, added to the where clause
+	// of the selects.
+	Predicate string `protobuf:"bytes,2,opt,name=predicate" json:"predicate,omitempty"`
+}
+
+func (m *RowFilterSpec) Reset()                    { *m = RowFilterSpec{} }
+func (m *RowFilterSpec) String() string            { return proto.CompactTextString(m) }
+func (*RowFilterSpec) ProtoMessage()               {}
+func (*RowFilterSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
 type Config struct {
 	TableGroups []*TableGroupSpec `protobuf:"bytes,1,rep,name=table_groups,json=tableGroups" json:"table_groups,omitempty"`
 }
@@ -51,7 +102,7 @@ type Config struct {
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *Config) GetTableGroups() []*TableGroupSpec {
 	if m != nil {
@@ -62,24 +113,34 @@ func (m *Config) GetTableGroups() []*TableGroupSpec {
 
 func init() {
 	proto.RegisterType((*TableGroupSpec)(nil), "tableacl.TableGroupSpec")
+	proto.RegisterType((*ColumnDenySpec)(nil), "tableacl.ColumnDenySpec")
+	proto.RegisterType((*RowFilterSpec)(nil), "tableacl.RowFilterSpec")
 	proto.RegisterType((*Config)(nil), "tableacl.Config")
 }
 
 func init() { proto.RegisterFile("tableacl.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 207 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0x2b, 0x49, 0x4c, 0xca,
-	0x49, 0x4d, 0x4c, 0xce, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x80, 0xf1, 0x95, 0x96,
-	0x33, 0x72, 0xf1, 0x85, 0x80, 0x38, 0xee, 0x45, 0xf9, 0xa5, 0x05, 0xc1, 0x05, 0xa9, 0xc9, 0x42,
-	0x42, 0x5c, 0x2c, 0x79, 0x89, 0xb9, 0xa9, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x60, 0xb6,
-	0x90, 0x29, 0x97, 0x38, 0x58, 0x4b, 0x3c, 0x88, 0x57, 0x1c, 0x9f, 0x5f, 0x14, 0x5f, 0x50, 0x94,
-	0x9a, 0x96, 0x59, 0x91, 0x5a, 0x2c, 0xc1, 0xa4, 0xc0, 0xac, 0xc1, 0x19, 0x24, 0x02, 0x96, 0xf6,
-	0x03, 0xc9, 0xfa, 0x17, 0x05, 0x40, 0xe5, 0x84, 0x24, 0xb8, 0xd8, 0x8b, 0x52, 0x13, 0x53, 0x52,
-	0x8b, 0x8a, 0x25, 0x98, 0xc1, 0xca, 0x60, 0x5c, 0x90, 0x4c, 0x79, 0x51, 0x66, 0x09, 0x48, 0x86,
-	0x05, 0x22, 0x03, 0xe5, 0x0a, 0x89, 0x71, 0xb1, 0x25, 0xa6, 0xe4, 0x66, 0xe6, 0x15, 0x4b, 0xb0,
-	0x82, 0x25, 0xa0, 0x3c, 0x25, 0x57, 0x2e, 0x36, 0xe7, 0xfc, 0xbc, 0xb4, 0xcc, 0x74, 0x21, 0x6b,
-	0x2e, 0x1e, 0x88, 0x63, 0xd2, 0x41, 0x6e, 0x2e, 0x96, 0x60, 0x54, 0x60, 0xd6, 0xe0, 0x36, 0x92,
-	0xd0, 0x83, 0x7b, 0x12, 0xd5, 0x43, 0x41, 0xdc, 0x25, 0x70, 0x7e, 0x71, 0x12, 0x1b, 0x38, 0x04,
-	0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x64, 0x57, 0xee, 0xfe, 0x13, 0x01, 0x00, 0x00,
+	// 329 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x3b, 0x4f, 0xc3, 0x30,
+	0x10, 0xc7, 0x95, 0xa6, 0xa4, 0xed, 0xf5, 0x31, 0x58, 0x88, 0x7a, 0x40, 0x28, 0xca, 0x94, 0xa9,
+	0x03, 0x08, 0x09, 0x09, 0x31, 0x95, 0xc7, 0xc4, 0x43, 0x81, 0x99, 0xc8, 0x4d, 0xdc, 0xca, 0x22,
+	0xb1, 0x2d, 0x3b, 0x55, 0xe1, 0xdb, 0xf0, 0x51, 0x91, 0x2f, 0x49, 0x4b, 0x60, 0xf3, 0xcf, 0xbf,
+	0xdc, 0xdf, 0xf6, 0x5d, 0x60, 0x56, 0xb1, 0x55, 0xc1, 0x59, 0x56, 0x2c, 0xb4, 0x51, 0x95, 0x22,
+	0xc3, 0x96, 0xa3, 0xef, 0x1e, 0xcc, 0xde, 0x1c, 0x3c, 0x18, 0xb5, 0xd5, 0xaf, 0x9a, 0x67, 0x84,
+	0x40, 0x5f, 0xb2, 0x92, 0x53, 0x2f, 0xf4, 0xe2, 0x51, 0x82, 0x6b, 0x72, 0x09, 0x73, 0x2c, 0x49,
+	0x1d, 0xd9, 0x54, 0x99, 0x54, 0x1b, 0xbe, 0x16, 0x9f, 0xdc, 0xd2, 0x5e, 0xe8, 0xc7, 0xa3, 0xe4,
+	0x18, 0xf5, 0x93, 0xb3, 0xcf, 0xe6, 0xa5, 0x71, 0x84, 0xc2, 0xc0, 0x70, 0x96, 0x73, 0x63, 0xa9,
+	0x8f, 0x9f, 0xb5, 0xe8, 0xcc, 0xce, 0x88, 0xca, 0x99, 0x7e, 0x6d, 0x1a, 0x24, 0x27, 0x10, 0xb0,
+	0xbc, 0x14, 0xd2, 0xd2, 0x23, 0x14, 0x0d, 0x91, 0x1b, 0x98, 0x66, 0xaa, 0xd8, 0x96, 0x32, 0xcd,
+	0xb9, 0x14, 0xdc, 0xd2, 0x20, 0xf4, 0xe3, 0xf1, 0x39, 0x5d, 0xec, 0xdf, 0xb6, 0x44, 0x7d, 0xcb,
+	0xe5, 0x97, 0x7b, 0x47, 0x32, 0xc9, 0x5a, 0x16, 0xdc, 0x92, 0x2b, 0x18, 0x1b, 0xb5, 0x4b, 0xd7,
+	0xa2, 0xc0, 0x43, 0x07, 0x58, 0x3c, 0x3f, 0x14, 0x27, 0x6a, 0x77, 0x8f, 0x0e, 0x6b, 0xc1, 0xb4,
+	0x68, 0xa3, 0x77, 0x98, 0x75, 0x93, 0xdd, 0xe5, 0xeb, 0x6c, 0x4b, 0xbd, 0xfa, 0xf2, 0x0d, 0x92,
+	0x33, 0x00, 0x6d, 0x84, 0xcc, 0x84, 0x66, 0x45, 0xdb, 0x9a, 0x5f, 0x3b, 0xae, 0xb7, 0x25, 0xb3,
+	0x1f, 0xd4, 0x0f, 0xbd, 0x78, 0x98, 0xe0, 0x3a, 0x7a, 0x84, 0x69, 0xe7, 0xf0, 0x3f, 0x21, 0xde,
+	0xbf, 0x90, 0x53, 0x18, 0x69, 0xc3, 0x73, 0x91, 0xb1, 0x8a, 0xd3, 0x1e, 0x4e, 0xe9, 0xb0, 0x11,
+	0xdd, 0x41, 0xb0, 0x54, 0x72, 0x2d, 0x36, 0xe4, 0x1a, 0x26, 0xf5, 0xd0, 0x36, 0x6e, 0xb6, 0x75,
+	0x52, 0xa7, 0x61, 0xdd, 0xc1, 0x27, 0xe3, 0x6a, 0xcf, 0x76, 0x15, 0xe0, 0x9f, 0x72, 0xf1, 0x33,
+	0x00, 0x65, 0x85, 0xe7, 0x7e, 0x3b, 0x02, 0x00, 0x00,
 }
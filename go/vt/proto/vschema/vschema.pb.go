@@ -92,13 +92,20 @@ func (m *Vindex) GetParams() map[string]string {
 // Table is the table info for a Keyspace.
 type Table struct {
 	// If the table is a sequence, type must be
-	// "sequence". Otherwise, it should be empty.
+	// "sequence". If the table is a reference table,
+	// which has a copy of its data in every shard of
+	// the keyspace, type must be "reference".
+	// Otherwise, it should be empty.
 	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
 	// column_vindexes associates columns to vindexes.
 	ColumnVindexes []*ColumnVindex `protobuf:"bytes,2,rep,name=column_vindexes,json=columnVindexes" json:"column_vindexes,omitempty"`
 	// auto_increment is specified if a column needs
 	// to be associated with a sequence.
 	AutoIncrement *AutoIncrement `protobuf:"bytes,3,opt,name=auto_increment,json=autoIncrement" json:"auto_increment,omitempty"`
+	// source is the unsharded keyspace that contains
+	// the original of a reference table. Writes to the
+	// table are sent to that keyspace.
+	Source string `protobuf:"bytes,4,opt,name=source" json:"source,omitempty"`
 }
 
 func (m *Table) Reset()                    { *m = Table{} }
@@ -174,33 +181,33 @@ func init() {
 func init() { proto.RegisterFile("vschema.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 436 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x93, 0xd1, 0x6a, 0xd4, 0x40,
-	0x14, 0x86, 0x99, 0xc4, 0x4d, 0xb3, 0x27, 0x26, 0xd5, 0xa1, 0x96, 0x10, 0x11, 0x97, 0xa0, 0xb8,
-	0x57, 0xb9, 0xd8, 0x22, 0x68, 0x45, 0x51, 0x8a, 0x17, 0x45, 0x41, 0x49, 0xa5, 0xb7, 0x65, 0x9a,
-	0x3d, 0xd0, 0xd2, 0xcd, 0x24, 0x66, 0x92, 0x68, 0x5e, 0xc5, 0x1b, 0xc1, 0x37, 0xf0, 0x0d, 0xa5,
-	0x93, 0xc9, 0x74, 0xb2, 0x1b, 0xef, 0xe6, 0xe7, 0x9c, 0xff, 0x9f, 0x6f, 0xe6, 0xcc, 0x80, 0xdf,
-	0x8a, 0xec, 0x0a, 0x73, 0x96, 0x94, 0x55, 0x51, 0x17, 0x74, 0x4f, 0xc9, 0xf8, 0xaf, 0x05, 0xee,
-	0x27, 0xec, 0x44, 0xc9, 0x32, 0xa4, 0x21, 0xec, 0x89, 0x2b, 0x56, 0xad, 0x71, 0x1d, 0x92, 0x05,
-	0x59, 0xba, 0xe9, 0x20, 0xe9, 0x1b, 0x70, 0xdb, 0x6b, 0xbe, 0xc6, 0x9f, 0x28, 0x42, 0x6b, 0x61,
-	0x2f, 0xbd, 0xd5, 0xd3, 0x64, 0x48, 0x1c, 0xec, 0xc9, 0xb9, 0xea, 0xf8, 0xc8, 0xeb, 0xaa, 0x4b,
-	0xb5, 0x81, 0xbe, 0x04, 0xa7, 0x66, 0x97, 0x1b, 0x14, 0xa1, 0x2d, 0xad, 0x4f, 0x76, 0xad, 0xdf,
-	0x64, 0xbd, 0x37, 0xaa, 0xe6, 0xe8, 0x33, 0xf8, 0xa3, 0x44, 0xfa, 0x00, 0xec, 0x1b, 0xec, 0x24,
-	0xda, 0x3c, 0xbd, 0x5d, 0xd2, 0xe7, 0x30, 0x6b, 0xd9, 0xa6, 0xc1, 0xd0, 0x5a, 0x90, 0xa5, 0xb7,
-	0xda, 0xd7, 0xc1, 0xbd, 0x31, 0xed, 0xab, 0xc7, 0xd6, 0x2b, 0x12, 0x9d, 0x82, 0x67, 0x6c, 0x32,
-	0x91, 0xf5, 0x6c, 0x9c, 0x15, 0xe8, 0x2c, 0x69, 0x33, 0xa2, 0xe2, 0x3f, 0x04, 0x9c, 0x7e, 0x03,
-	0x4a, 0xe1, 0x5e, 0xdd, 0x95, 0xa8, 0x72, 0xe4, 0x9a, 0x1e, 0x81, 0x53, 0xb2, 0x8a, 0xe5, 0xc3,
-	0x4d, 0x3d, 0xde, 0xa2, 0x4a, 0xbe, 0xca, 0xaa, 0x3a, 0x6c, 0xdf, 0x4a, 0x0f, 0x60, 0x56, 0xfc,
-	0xe0, 0x58, 0x85, 0xb6, 0x4c, 0xea, 0x45, 0xf4, 0x1a, 0x3c, 0xa3, 0x79, 0x02, 0xfa, 0xc0, 0x84,
-	0x9e, 0x9b, 0x90, 0xbf, 0x08, 0xcc, 0x24, 0xf9, 0x24, 0xe3, 0x3b, 0xd8, 0xcf, 0x8a, 0x4d, 0x93,
-	0xf3, 0x8b, 0xad, 0xb1, 0x3e, 0xd2, 0xb0, 0x27, 0xb2, 0xae, 0x2e, 0x32, 0xc8, 0x0c, 0x85, 0x82,
-	0xbe, 0x85, 0x80, 0x35, 0x75, 0x71, 0x71, 0xcd, 0xb3, 0x0a, 0x73, 0xe4, 0xb5, 0xe4, 0xf6, 0x56,
-	0x87, 0xda, 0xfe, 0xa1, 0xa9, 0x8b, 0xd3, 0xa1, 0x9a, 0xfa, 0xcc, 0x94, 0xf1, 0x31, 0xdc, 0x37,
-	0xe3, 0xe9, 0x21, 0x38, 0xfd, 0x06, 0x0a, 0x52, 0xa9, 0x5b, 0x74, 0xce, 0xf2, 0xe1, 0x74, 0x72,
-	0x1d, 0x9f, 0x80, 0x3f, 0xca, 0xfe, 0xaf, 0x39, 0x02, 0x57, 0xe0, 0xf7, 0x06, 0x79, 0x36, 0x04,
-	0x68, 0x1d, 0xff, 0x26, 0x00, 0x67, 0x55, 0x7b, 0x7e, 0x26, 0x61, 0xe9, 0x7b, 0x98, 0xdf, 0xa8,
-	0xa7, 0x28, 0x42, 0x22, 0x2f, 0x22, 0xd6, 0x27, 0xb9, 0xeb, 0xd3, 0xef, 0x55, 0x0d, 0xef, 0xce,
-	0x14, 0x7d, 0x81, 0x60, 0x5c, 0x9c, 0x18, 0xd6, 0x8b, 0xf1, 0x0b, 0x7b, 0xb8, 0xf3, 0x0d, 0x8c,
-	0xf9, 0x5d, 0x3a, 0xf2, 0xa3, 0x1e, 0xfd, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xf1, 0x47, 0x7e, 0x27,
-	0xb9, 0x03, 0x00, 0x00,
+	// 444 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xd1, 0x6e, 0xd3, 0x30,
+	0x14, 0x95, 0xdb, 0x35, 0x6b, 0x6f, 0x68, 0x07, 0xd6, 0x98, 0xac, 0x20, 0x44, 0x15, 0x81, 0xe8,
+	0x53, 0x1e, 0x3a, 0x21, 0xc1, 0x10, 0x88, 0x69, 0xe2, 0x61, 0x02, 0x09, 0x94, 0xa1, 0xbd, 0x4e,
+	0x5e, 0x7a, 0xa5, 0x4d, 0x6b, 0x9c, 0x60, 0x27, 0x81, 0x7c, 0x0d, 0x12, 0x7f, 0x00, 0x5f, 0x38,
+	0xd5, 0x71, 0x3c, 0xa7, 0xcd, 0x9b, 0x8f, 0xee, 0x39, 0xe7, 0x9e, 0xeb, 0x6b, 0xc3, 0xb4, 0x52,
+	0xc9, 0x0d, 0xa6, 0x3c, 0xca, 0x65, 0x56, 0x64, 0x74, 0xdf, 0xc0, 0xf0, 0xdf, 0x00, 0xc6, 0x5f,
+	0xb0, 0x56, 0x39, 0x4f, 0x90, 0x32, 0xd8, 0x57, 0x37, 0x5c, 0xae, 0x70, 0xc5, 0xc8, 0x9c, 0x2c,
+	0xc6, 0x71, 0x0b, 0xe9, 0x7b, 0x18, 0x57, 0xb7, 0x62, 0x85, 0xbf, 0x51, 0xb1, 0xc1, 0x7c, 0xb8,
+	0xf0, 0x97, 0x2f, 0xa2, 0xd6, 0xb1, 0x95, 0x47, 0x97, 0x86, 0xf1, 0x59, 0x14, 0xb2, 0x8e, 0xad,
+	0x80, 0xbe, 0x01, 0xaf, 0xe0, 0xd7, 0x6b, 0x54, 0x6c, 0xa8, 0xa5, 0xcf, 0x77, 0xa5, 0x3f, 0x74,
+	0xbd, 0x11, 0x1a, 0x72, 0xf0, 0x15, 0xa6, 0x1d, 0x47, 0xfa, 0x18, 0x86, 0x77, 0x58, 0xeb, 0x68,
+	0x93, 0x78, 0x73, 0xa4, 0xaf, 0x60, 0x54, 0xf1, 0x75, 0x89, 0x6c, 0x30, 0x27, 0x0b, 0x7f, 0x79,
+	0x60, 0x8d, 0x1b, 0x61, 0xdc, 0x54, 0x4f, 0x06, 0x6f, 0x49, 0x70, 0x0e, 0xbe, 0xd3, 0xa4, 0xc7,
+	0xeb, 0x65, 0xd7, 0x6b, 0x66, 0xbd, 0xb4, 0xcc, 0xb1, 0x0a, 0xff, 0x12, 0xf0, 0x9a, 0x06, 0x94,
+	0xc2, 0x5e, 0x51, 0xe7, 0x68, 0x7c, 0xf4, 0x99, 0x1e, 0x83, 0x97, 0x73, 0xc9, 0xd3, 0xf6, 0xa6,
+	0x9e, 0x6d, 0xa5, 0x8a, 0xbe, 0xeb, 0xaa, 0x19, 0xb6, 0xa1, 0xd2, 0x43, 0x18, 0x65, 0xbf, 0x04,
+	0x4a, 0x36, 0xd4, 0x4e, 0x0d, 0x08, 0xde, 0x81, 0xef, 0x90, 0x7b, 0x42, 0x1f, 0xba, 0xa1, 0x27,
+	0x6e, 0xc8, 0xff, 0x04, 0x46, 0x3a, 0x79, 0x6f, 0xc6, 0x8f, 0x70, 0x90, 0x64, 0xeb, 0x32, 0x15,
+	0x57, 0x5b, 0x6b, 0x7d, 0x6a, 0xc3, 0x9e, 0xe9, 0xba, 0xb9, 0xc8, 0x59, 0xe2, 0x20, 0x54, 0xf4,
+	0x03, 0xcc, 0x78, 0x59, 0x64, 0x57, 0xb7, 0x22, 0x91, 0x98, 0xa2, 0x28, 0x74, 0x6e, 0x7f, 0x79,
+	0x64, 0xe5, 0xa7, 0x65, 0x91, 0x9d, 0xb7, 0xd5, 0x78, 0xca, 0x5d, 0x48, 0x8f, 0xc0, 0x53, 0x59,
+	0x29, 0x13, 0x64, 0x7b, 0x3a, 0x94, 0x41, 0xe1, 0x09, 0x3c, 0x72, 0xdb, 0x6e, 0x78, 0x4d, 0x63,
+	0x13, 0xde, 0xa0, 0xcd, 0x48, 0x82, 0xa7, 0xed, 0xd4, 0xfa, 0x1c, 0x9e, 0xc1, 0xf4, 0x74, 0xbb,
+	0x49, 0xaf, 0x38, 0x80, 0xb1, 0xc2, 0x9f, 0x25, 0x8a, 0xa4, 0x35, 0xb0, 0x38, 0xfc, 0x43, 0x00,
+	0x2e, 0x64, 0x75, 0x79, 0xa1, 0x87, 0xa0, 0x9f, 0x60, 0x72, 0x67, 0x9e, 0xa8, 0x62, 0x44, 0x5f,
+	0x50, 0x68, 0x27, 0x7c, 0xe0, 0xd9, 0x77, 0x6c, 0x96, 0xfa, 0x20, 0x0a, 0xbe, 0xc1, 0xac, 0x5b,
+	0xec, 0x59, 0xe2, 0xeb, 0xee, 0xcb, 0x7b, 0xb2, 0xf3, 0x3d, 0x9c, 0xbd, 0x5e, 0x7b, 0xfa, 0x03,
+	0x1f, 0xdf, 0x0f, 0x00, 0x3e, 0x08, 0x5d, 0x1f, 0xd1, 0x03, 0x00, 0x00,
 }
@@ -73,6 +73,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type Session struct {
 	InTransaction bool                    `protobuf:"varint,1,opt,name=in_transaction,json=inTransaction" json:"in_transaction,omitempty"`
 	ShardSessions []*Session_ShardSession `protobuf:"bytes,2,rep,name=shard_sessions,json=shardSessions" json:"shard_sessions,omitempty"`
+	// savepoints are the names of the active savepoints of the
+	// transaction, in the order they were set. They are set on the
+	// shards which join the transaction after them.
+	Savepoints []string `protobuf:"bytes,3,rep,name=savepoints" json:"savepoints,omitempty"`
 }
 
 func (m *Session) Reset()                    { *m = Session{} }
@@ -1564,97 +1568,98 @@ func init() {
 func init() { proto.RegisterFile("vtgate.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1471 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xd4, 0x59, 0x5b, 0x6f, 0x1b, 0x45,
-	0x14, 0xd6, 0xae, 0x6f, 0xf1, 0xf1, 0x25, 0xe9, 0xd4, 0x69, 0x8d, 0x29, 0x8d, 0xbb, 0x22, 0xaa,
-	0x0b, 0x91, 0xab, 0xa6, 0xdc, 0x84, 0x90, 0x80, 0x98, 0x08, 0x45, 0x85, 0x52, 0x26, 0x01, 0xf1,
-	0x00, 0x5a, 0x6d, 0xec, 0x51, 0xb2, 0xd8, 0x7b, 0xe9, 0xce, 0xac, 0x8b, 0x79, 0x40, 0xfc, 0x83,
-	0x3e, 0x21, 0x21, 0x84, 0x84, 0x90, 0x78, 0xe5, 0x15, 0x89, 0x37, 0x1e, 0x90, 0xf8, 0x01, 0x3c,
-	0xf0, 0xce, 0x1f, 0x40, 0xf0, 0x0b, 0xd0, 0xce, 0xcc, 0x5e, 0xbc, 0x89, 0x1d, 0xc7, 0x49, 0x2a,
-	0xf7, 0xc9, 0x9e, 0x99, 0x33, 0x33, 0xdf, 0x7c, 0xe7, 0x9b, 0x73, 0xe6, 0x68, 0xa1, 0x3c, 0x64,
-	0x07, 0x06, 0x23, 0x6d, 0xd7, 0x73, 0x98, 0x83, 0xf2, 0xa2, 0xd5, 0x28, 0x3d, 0xf4, 0x89, 0x37,
-	0x12, 0x9d, 0x8d, 0x2a, 0x73, 0x5c, 0xa7, 0x67, 0x30, 0x43, 0xb6, 0x4b, 0x43, 0xe6, 0xb9, 0x5d,
-	0xd1, 0xd0, 0xfe, 0x54, 0xa0, 0xb0, 0x4b, 0x28, 0x35, 0x1d, 0x1b, 0xad, 0x43, 0xd5, 0xb4, 0x75,
-	0xe6, 0x19, 0x36, 0x35, 0xba, 0xcc, 0x74, 0xec, 0xba, 0xd2, 0x54, 0x5a, 0x4b, 0xb8, 0x62, 0xda,
-	0x7b, 0x71, 0x27, 0xea, 0x40, 0x95, 0x1e, 0x1a, 0x5e, 0x4f, 0xa7, 0x62, 0x1e, 0xad, 0xab, 0xcd,
-	0x4c, 0xab, 0xb4, 0x79, 0xad, 0x2d, 0xb1, 0xc8, 0xf5, 0xda, 0xbb, 0x81, 0x95, 0x6c, 0xe0, 0x0a,
-	0x4d, 0xb4, 0x68, 0xe3, 0x53, 0x28, 0x27, 0x87, 0xd1, 0x3a, 0xe4, 0x99, 0xe1, 0x1d, 0x10, 0xc6,
-	0xf7, 0x2c, 0x6d, 0x56, 0xda, 0xe2, 0x08, 0x7b, 0xbc, 0x13, 0xcb, 0xc1, 0x00, 0x62, 0x02, 0x9f,
-	0x6e, 0xf6, 0xea, 0x6a, 0x53, 0x69, 0x65, 0x70, 0x25, 0xd1, 0xbb, 0xd3, 0xd3, 0x7e, 0x57, 0xa1,
-	0xba, 0xfd, 0x05, 0xe9, 0xfa, 0x8c, 0x60, 0xf2, 0xd0, 0x27, 0x94, 0xa1, 0x0d, 0x28, 0x76, 0x8d,
-	0xc1, 0x80, 0x78, 0xc1, 0x24, 0xb1, 0xc7, 0x72, 0x5b, 0x30, 0xd1, 0xe1, 0xfd, 0x3b, 0xef, 0xe0,
-	0x25, 0x61, 0xb1, 0xd3, 0x43, 0xb7, 0xa0, 0x20, 0x4f, 0xc7, 0x37, 0x10, 0xb6, 0xc9, 0xc3, 0xe1,
-	0x70, 0x1c, 0xdd, 0x84, 0x1c, 0x87, 0x5a, 0xcf, 0x70, 0xc3, 0x4b, 0x12, 0xf8, 0x96, 0xe3, 0xdb,
-	0xbd, 0x0f, 0x83, 0xbf, 0x58, 0x8c, 0xa3, 0x97, 0xa1, 0xc4, 0x8c, 0xfd, 0x01, 0x61, 0x3a, 0x1b,
-	0xb9, 0xa4, 0x9e, 0x6d, 0x2a, 0xad, 0xea, 0x66, 0xad, 0x1d, 0x79, 0x67, 0x8f, 0x0f, 0xee, 0x8d,
-	0x5c, 0x82, 0x81, 0x45, 0xff, 0xd1, 0x06, 0x20, 0xdb, 0x61, 0x7a, 0xca, 0x33, 0x39, 0xee, 0x99,
-	0x15, 0xdb, 0x61, 0x3b, 0x63, 0xce, 0x69, 0xc0, 0x52, 0x9f, 0x8c, 0xa8, 0x6b, 0x74, 0x49, 0x3d,
-	0xdf, 0x54, 0x5a, 0x45, 0x1c, 0xb5, 0xd1, 0x6d, 0x28, 0x38, 0x2e, 0xe3, 0x1e, 0x2b, 0x70, 0xac,
-	0xab, 0x12, 0xab, 0xa4, 0xea, 0x03, 0x31, 0x88, 0x43, 0x2b, 0xed, 0xb1, 0x02, 0xcb, 0x11, 0x8d,
-	0xd4, 0x75, 0x6c, 0x4a, 0xd0, 0x3a, 0xe4, 0x88, 0xe7, 0x39, 0x5e, 0x8a, 0x43, 0xfc, 0xa0, 0xb3,
-	0x1d, 0x74, 0x63, 0x31, 0x7a, 0x1a, 0x02, 0x5f, 0x80, 0xbc, 0x47, 0xa8, 0x3f, 0x60, 0x92, 0x41,
-	0x24, 0x51, 0x09, 0xf2, 0xf8, 0x08, 0x96, 0x16, 0xda, 0xdf, 0x2a, 0xd4, 0x24, 0x22, 0x2e, 0x1f,
-	0xba, 0x38, 0xee, 0x4d, 0x32, 0x9f, 0x4d, 0x31, 0x7f, 0x05, 0xf2, 0x5c, 0xfe, 0xb4, 0x9e, 0x6b,
-	0x66, 0x5a, 0x45, 0x2c, 0x5b, 0x69, 0x49, 0xe4, 0xcf, 0x24, 0x89, 0xc2, 0x04, 0x49, 0x24, 0xdc,
-	0xbe, 0x34, 0x93, 0xdb, 0xbf, 0x51, 0x60, 0x35, 0x45, 0xf2, 0x42, 0x38, 0xff, 0x3f, 0x15, 0x9e,
-	0x91, 0xb8, 0xee, 0x49, 0x66, 0x77, 0x9e, 0x16, 0x05, 0xdc, 0x80, 0x72, 0xf8, 0x5f, 0x37, 0xa5,
-	0x0e, 0xca, 0xb8, 0xd4, 0x8f, 0xcf, 0xb1, 0xa0, 0x62, 0xf8, 0x4e, 0x81, 0xc6, 0x71, 0xa4, 0x2f,
-	0x84, 0x22, 0xbe, 0xce, 0xc0, 0xd5, 0x18, 0x1c, 0x36, 0xec, 0x03, 0xf2, 0x94, 0xe8, 0xe1, 0x0e,
-	0x40, 0x9f, 0x8c, 0x74, 0x8f, 0x43, 0xe6, 0x6a, 0x08, 0x4e, 0x1a, 0xf9, 0x3a, 0x3c, 0x0d, 0x2e,
-	0xf6, 0xc3, 0x73, 0x2d, 0xa8, 0x3e, 0xbe, 0x55, 0xa0, 0x7e, 0xd4, 0x05, 0x0b, 0xa1, 0x8e, 0x5f,
-	0xb3, 0x91, 0x3a, 0xb6, 0x6d, 0x66, 0xb2, 0xd1, 0x53, 0x13, 0x2d, 0x36, 0x00, 0x11, 0x8e, 0x58,
-	0xef, 0x3a, 0x03, 0xdf, 0xb2, 0x75, 0xdb, 0xb0, 0x08, 0xcf, 0xf9, 0x45, 0xbc, 0x22, 0x46, 0x3a,
-	0x7c, 0xe0, 0xbe, 0x61, 0x11, 0xf4, 0x09, 0x5c, 0x96, 0xd6, 0x63, 0x21, 0x26, 0xcf, 0x45, 0xd5,
-	0x0a, 0x91, 0x4e, 0x60, 0xa2, 0x1d, 0x76, 0xe0, 0x4b, 0x62, 0x91, 0x7b, 0x93, 0x43, 0x52, 0xe1,
-	0x4c, 0x92, 0x5b, 0x3a, 0x59, 0x72, 0xc5, 0x59, 0x24, 0xd7, 0xd8, 0x87, 0xa5, 0x10, 0x34, 0x5a,
-	0x83, 0x2c, 0x87, 0xa6, 0x70, 0x68, 0xa5, 0xf0, 0xd5, 0x18, 0x20, 0xe2, 0x03, 0xa8, 0x06, 0xb9,
-	0xa1, 0x31, 0xf0, 0x09, 0x77, 0x5c, 0x19, 0x8b, 0x06, 0x5a, 0x83, 0x52, 0x82, 0x2b, 0xee, 0xab,
-	0x32, 0x86, 0x38, 0x1a, 0x27, 0x65, 0x9d, 0x60, 0x6c, 0x21, 0x64, 0x6d, 0xc3, 0x32, 0x57, 0x13,
-	0xcf, 0xcd, 0xdc, 0x20, 0x16, 0x9d, 0x72, 0x0a, 0xd1, 0xa9, 0x13, 0x1f, 0x29, 0x99, 0xe4, 0x23,
-	0x45, 0xfb, 0x25, 0x4e, 0xbb, 0x5b, 0x06, 0xeb, 0x1e, 0x3e, 0xa1, 0x87, 0xd7, 0x1d, 0x28, 0x04,
-	0x98, 0x4d, 0x22, 0xf0, 0x94, 0x36, 0xaf, 0x86, 0xa6, 0xa9, 0xd3, 0xe3, 0xd0, 0x6e, 0xde, 0x17,
-	0xf6, 0x3a, 0x54, 0x0d, 0x7a, 0xcc, 0xeb, 0xba, 0x62, 0xd0, 0x09, 0x3a, 0xcd, 0xcf, 0x14, 0x1a,
-	0xbf, 0x8f, 0x53, 0xe7, 0x18, 0x71, 0x17, 0xa6, 0xa2, 0x0d, 0x28, 0x08, 0x8d, 0x84, 0x94, 0x1d,
-	0x27, 0xa3, 0xd0, 0x44, 0xfb, 0x0a, 0x6a, 0x9c, 0xc9, 0xf8, 0xc2, 0x9f, 0xa3, 0x98, 0xd2, 0xef,
-	0x9d, 0xcc, 0x91, 0xf7, 0x8e, 0xf6, 0x9b, 0x0a, 0xd7, 0x93, 0xf4, 0x3c, 0xc9, 0x37, 0xdd, 0x2b,
-	0x69, 0x71, 0x5d, 0x1b, 0x13, 0x57, 0x8a, 0x92, 0x85, 0x55, 0xd8, 0x8f, 0x0a, 0xac, 0x4d, 0xa4,
-	0x70, 0x41, 0x64, 0xf6, 0xaf, 0x02, 0xb5, 0x5d, 0xe6, 0x11, 0xc3, 0x3a, 0x53, 0x45, 0x1e, 0xa9,
-	0x52, 0x3d, 0x5d, 0x99, 0x9d, 0x99, 0xd1, 0x45, 0xd3, 0xd2, 0x71, 0xc2, 0x2f, 0xb9, 0x99, 0xfc,
-	0xd2, 0x81, 0xd5, 0xd4, 0x91, 0xa5, 0x33, 0xe2, 0x38, 0xaf, 0x9c, 0x18, 0xe7, 0x1f, 0xab, 0xd0,
-	0x18, 0x5b, 0xe5, 0x2c, 0x81, 0x77, 0x66, 0xfa, 0x92, 0x3c, 0x64, 0x26, 0x66, 0x88, 0xec, 0xb4,
-	0x32, 0x36, 0x37, 0x23, 0xe5, 0xa7, 0x96, 0xfb, 0x0e, 0x3c, 0x7b, 0x2c, 0x21, 0x73, 0x90, 0xfb,
-	0x83, 0x0a, 0x6b, 0x63, 0x6b, 0x9d, 0x39, 0xfa, 0x9c, 0x0b, 0xc3, 0xe9, 0xb0, 0x99, 0x3d, 0xb1,
-	0x4c, 0xbc, 0x30, 0xb2, 0xef, 0x43, 0x73, 0x32, 0x41, 0x73, 0x30, 0xfe, 0xb3, 0x0a, 0xcf, 0xa5,
-	0x17, 0x3c, 0x4b, 0xc5, 0x76, 0x2e, 0x7c, 0x8f, 0x97, 0x61, 0xd9, 0x39, 0xca, 0xb0, 0x0b, 0xe3,
-	0xff, 0x3d, 0xb8, 0x3e, 0x89, 0xae, 0x39, 0xd8, 0x7f, 0x03, 0xca, 0x5b, 0xe4, 0xc0, 0xb4, 0xe7,
-	0xe2, 0x5a, 0x7b, 0x1d, 0x2a, 0x72, 0xb6, 0xdc, 0x3a, 0x91, 0x2d, 0x94, 0xe9, 0xd9, 0x42, 0x3b,
-	0x84, 0x4a, 0xc7, 0xb1, 0x2c, 0x93, 0x5d, 0x74, 0x52, 0xd7, 0x56, 0xa0, 0x1a, 0xee, 0x24, 0x60,
-	0x6a, 0x9f, 0xc3, 0x32, 0x76, 0x06, 0x83, 0x7d, 0xa3, 0xdb, 0xbf, 0xf0, 0xdd, 0x11, 0xac, 0xc4,
-	0x7b, 0xc9, 0xfd, 0xff, 0x51, 0xe1, 0xd2, 0xae, 0x3b, 0x30, 0x99, 0x74, 0xc9, 0x3c, 0x10, 0xa6,
-	0xbd, 0xb2, 0x66, 0x2e, 0x36, 0x6f, 0x40, 0x99, 0x06, 0x38, 0x64, 0x3d, 0x29, 0xe3, 0x77, 0x89,
-	0xf7, 0x89, 0x4a, 0x32, 0x28, 0x89, 0x42, 0x13, 0xdf, 0x66, 0x5c, 0xd7, 0x19, 0x0c, 0xd2, 0xc2,
-	0xb7, 0x19, 0x7a, 0x09, 0xae, 0xda, 0xbe, 0xa5, 0x7b, 0xce, 0x23, 0xaa, 0xbb, 0xc4, 0xd3, 0xf9,
-	0xca, 0xba, 0x6b, 0x78, 0x8c, 0x2b, 0x3a, 0x83, 0x2f, 0xdb, 0xbe, 0x85, 0x9d, 0x47, 0xf4, 0x01,
-	0xf1, 0xf8, 0xe6, 0x0f, 0x0c, 0x8f, 0xa1, 0xb7, 0xa0, 0x68, 0x0c, 0x0e, 0x1c, 0xcf, 0x64, 0x87,
-	0x96, 0x2c, 0x20, 0x35, 0x09, 0xf3, 0x08, 0x33, 0xed, 0xb7, 0x43, 0x4b, 0x1c, 0x4f, 0x42, 0x2f,
-	0x02, 0xf2, 0x29, 0xd1, 0x05, 0x38, 0xb1, 0xe9, 0x70, 0x53, 0x56, 0x93, 0xcb, 0x3e, 0x25, 0xf1,
-	0x32, 0x1f, 0x6f, 0x6a, 0x7f, 0x64, 0x00, 0x25, 0xd7, 0x95, 0x7a, 0x7d, 0x15, 0xf2, 0x7c, 0x3e,
-	0xad, 0x2b, 0xfc, 0x8e, 0xaf, 0x45, 0x6e, 0x3c, 0x62, 0xdb, 0x0e, 0x60, 0x63, 0x69, 0xde, 0xf8,
-	0x0c, 0xca, 0xe1, 0xc5, 0xe3, 0xc7, 0x49, 0x7a, 0x43, 0x99, 0x1a, 0x4c, 0xd4, 0x19, 0x82, 0x49,
-	0xe3, 0x4d, 0x28, 0xf2, 0x24, 0x76, 0xe2, 0xda, 0x71, 0xea, 0x55, 0x93, 0xa9, 0xb7, 0xf1, 0x97,
-	0x02, 0x59, 0x3e, 0x79, 0xe6, 0x57, 0xfb, 0xfb, 0x50, 0x8d, 0x50, 0x0a, 0xef, 0x09, 0x65, 0xdf,
-	0x9c, 0x42, 0x49, 0x92, 0x02, 0x5c, 0xee, 0x27, 0x09, 0xe9, 0x00, 0x88, 0xaf, 0x41, 0x7c, 0x29,
-	0xa1, 0xc3, 0xe7, 0xa7, 0x2c, 0x15, 0x1d, 0x17, 0x17, 0x69, 0x74, 0x72, 0x04, 0x59, 0x6a, 0x7e,
-	0x29, 0x1e, 0x5e, 0x19, 0xcc, 0xff, 0x6b, 0x77, 0x61, 0xf5, 0x5d, 0xc2, 0x76, 0xbd, 0x61, 0x98,
-	0x78, 0xc2, 0xeb, 0x33, 0x85, 0x26, 0x0d, 0xc3, 0x95, 0xf4, 0x24, 0xa9, 0x80, 0xd7, 0xa0, 0x4c,
-	0xbd, 0xa1, 0x3e, 0x36, 0x33, 0x08, 0xc2, 0x91, 0x7b, 0x92, 0x93, 0x4a, 0x34, 0x6e, 0x68, 0x3f,
-	0xa9, 0x70, 0xf9, 0x23, 0xb7, 0x67, 0x30, 0x22, 0xe2, 0xf1, 0xf9, 0x5f, 0xe3, 0x1a, 0xe4, 0x38,
-	0x17, 0x32, 0x3d, 0x89, 0x06, 0xba, 0x0d, 0xc5, 0xc8, 0x51, 0x9c, 0x99, 0xe3, 0xd5, 0xb4, 0x14,
-	0xba, 0x63, 0xde, 0xcc, 0x74, 0x0d, 0x8a, 0xcc, 0xb4, 0x08, 0x65, 0x86, 0xe5, 0xca, 0x9b, 0x1c,
-	0x77, 0x04, 0xba, 0x22, 0x43, 0x62, 0x33, 0xf9, 0xc9, 0x28, 0xd4, 0xd5, 0x76, 0xd0, 0xb7, 0xe7,
-	0xf4, 0x89, 0x8d, 0xc5, 0xb8, 0xd6, 0x87, 0xda, 0x38, 0x4b, 0x92, 0xf8, 0x56, 0xb8, 0xc0, 0x78,
-	0x92, 0x92, 0xb9, 0x2d, 0x18, 0x91, 0x2b, 0xa0, 0x5b, 0xb0, 0x12, 0x64, 0x2b, 0x8b, 0xe8, 0x31,
-	0x1e, 0xf1, 0x79, 0x6f, 0x59, 0xf4, 0xef, 0x85, 0xdd, 0x5b, 0x0d, 0xa8, 0x77, 0x1d, 0xab, 0x3d,
-	0x72, 0x7c, 0xe6, 0xef, 0x93, 0xf6, 0xd0, 0x64, 0x84, 0x52, 0xf1, 0x49, 0x73, 0x3f, 0xcf, 0x7f,
-	0xee, 0xfe, 0x1f, 0x00, 0x00, 0xff, 0xff, 0xc2, 0xa9, 0x37, 0x98, 0x1b, 0x1d, 0x00, 0x00,
+	// 1483 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x59, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xd6, 0xee, 0xfa, 0x27, 0x3e, 0xfe, 0x49, 0x3a, 0x75, 0x5a, 0x63, 0x4a, 0xe3, 0xae, 0x88,
+	0xea, 0x42, 0xe4, 0xaa, 0x29, 0x7f, 0x42, 0x48, 0x40, 0x4c, 0x84, 0xa2, 0x42, 0x29, 0x93, 0x80,
+	0xb8, 0x00, 0xad, 0x36, 0xf6, 0x28, 0x59, 0x6c, 0xef, 0x6e, 0x77, 0x66, 0x5d, 0xcc, 0x05, 0xe2,
+	0x0d, 0x7a, 0x85, 0x84, 0x10, 0x12, 0x42, 0xe2, 0x96, 0x5b, 0x24, 0xee, 0xb8, 0x40, 0xe2, 0x11,
+	0xb8, 0xe7, 0x05, 0x10, 0xf0, 0x02, 0x68, 0x67, 0x66, 0x7f, 0xbc, 0x89, 0x1d, 0xc7, 0x49, 0x2a,
+	0xf7, 0xca, 0x3b, 0x33, 0x67, 0x66, 0xbe, 0xf9, 0xce, 0x37, 0xe7, 0xcc, 0x91, 0xa1, 0x34, 0x64,
+	0x07, 0x26, 0x23, 0x2d, 0xd7, 0x73, 0x98, 0x83, 0x72, 0xa2, 0x55, 0x2f, 0x3e, 0xf4, 0x89, 0x37,
+	0x12, 0x9d, 0xf5, 0x0a, 0x73, 0x5c, 0xa7, 0x6b, 0x32, 0x53, 0xb6, 0x8b, 0x43, 0xe6, 0xb9, 0x1d,
+	0xd1, 0xd0, 0xff, 0x53, 0x20, 0xbf, 0x4b, 0x28, 0xb5, 0x1c, 0x1b, 0xad, 0x43, 0xc5, 0xb2, 0x0d,
+	0xe6, 0x99, 0x36, 0x35, 0x3b, 0xcc, 0x72, 0xec, 0x9a, 0xd2, 0x50, 0x9a, 0x4b, 0xb8, 0x6c, 0xd9,
+	0x7b, 0x71, 0x27, 0x6a, 0x43, 0x85, 0x1e, 0x9a, 0x5e, 0xd7, 0xa0, 0x62, 0x1e, 0xad, 0xa9, 0x0d,
+	0xad, 0x59, 0xdc, 0xbc, 0xd6, 0x92, 0x58, 0xe4, 0x7a, 0xad, 0xdd, 0xc0, 0x4a, 0x36, 0x70, 0x99,
+	0x26, 0x5a, 0x14, 0x5d, 0x07, 0xa0, 0xe6, 0x90, 0xb8, 0x8e, 0x65, 0x33, 0x5a, 0xd3, 0x1a, 0x5a,
+	0xb3, 0x80, 0x13, 0x3d, 0xf5, 0x4f, 0xa1, 0x94, 0x9c, 0x8e, 0xd6, 0x21, 0xc7, 0x4c, 0xef, 0x80,
+	0x30, 0x8e, 0xa9, 0xb8, 0x59, 0x6e, 0x89, 0x23, 0xee, 0xf1, 0x4e, 0x2c, 0x07, 0x83, 0x23, 0x24,
+	0xf0, 0x1b, 0x56, 0xb7, 0xa6, 0x36, 0x94, 0xa6, 0x86, 0xcb, 0x89, 0xde, 0x9d, 0xae, 0xfe, 0xbb,
+	0x0a, 0x95, 0xed, 0x2f, 0x48, 0xc7, 0x67, 0x04, 0x93, 0x87, 0x3e, 0xa1, 0x0c, 0x6d, 0x40, 0xa1,
+	0x63, 0xf6, 0xfb, 0xc4, 0x0b, 0x26, 0x89, 0x3d, 0x96, 0x5b, 0x82, 0xa9, 0x36, 0xef, 0xdf, 0x79,
+	0x07, 0x2f, 0x09, 0x8b, 0x9d, 0x2e, 0xba, 0x05, 0x79, 0x79, 0xfa, 0x9a, 0x1a, 0xd9, 0x26, 0x0f,
+	0x8f, 0xc3, 0x71, 0x74, 0x13, 0xb2, 0x1c, 0x6a, 0x4d, 0xe3, 0x86, 0x97, 0x24, 0xf0, 0x2d, 0xc7,
+	0xb7, 0xbb, 0x1f, 0x06, 0x9f, 0x58, 0x8c, 0xa3, 0x97, 0xa1, 0xc8, 0xcc, 0xfd, 0x3e, 0x61, 0x06,
+	0x1b, 0xb9, 0xa4, 0x96, 0x69, 0x28, 0xcd, 0xca, 0x66, 0xb5, 0x15, 0x79, 0x6f, 0x8f, 0x0f, 0xee,
+	0x8d, 0x5c, 0x82, 0x81, 0x45, 0xdf, 0x68, 0x03, 0x90, 0xed, 0x30, 0x23, 0xe5, 0xb9, 0x2c, 0xf7,
+	0xdc, 0x8a, 0xed, 0xb0, 0x9d, 0x31, 0xe7, 0xd5, 0x61, 0xa9, 0x47, 0x46, 0xd4, 0x35, 0x3b, 0xa4,
+	0x96, 0x6b, 0x28, 0xcd, 0x02, 0x8e, 0xda, 0xe8, 0x36, 0xe4, 0x1d, 0x97, 0x71, 0x8f, 0xe6, 0x39,
+	0xd6, 0x55, 0x89, 0x55, 0x52, 0xf5, 0x81, 0x18, 0xc4, 0xa1, 0x95, 0xfe, 0x58, 0x81, 0xe5, 0x88,
+	0x46, 0xea, 0x3a, 0x36, 0x25, 0x68, 0x1d, 0xb2, 0xc4, 0xf3, 0x1c, 0x2f, 0xc5, 0x21, 0x7e, 0xd0,
+	0xde, 0x0e, 0xba, 0xb1, 0x18, 0x3d, 0x0d, 0x81, 0x2f, 0x40, 0xce, 0x23, 0xd4, 0xef, 0x33, 0xc9,
+	0x20, 0x92, 0xa8, 0x04, 0x79, 0x7c, 0x04, 0x4b, 0x0b, 0xfd, 0x2f, 0x15, 0xaa, 0x12, 0x11, 0x97,
+	0x0f, 0x5d, 0x1c, 0xf7, 0x26, 0x99, 0xcf, 0xa4, 0x98, 0xbf, 0x02, 0x39, 0x7e, 0x3d, 0x68, 0x2d,
+	0xcb, 0x6f, 0x82, 0x6c, 0xa5, 0x25, 0x91, 0x3b, 0x93, 0x24, 0xf2, 0x13, 0x24, 0x91, 0x70, 0xfb,
+	0xd2, 0x4c, 0x6e, 0xff, 0x46, 0x81, 0xd5, 0x14, 0xc9, 0x0b, 0xe1, 0xfc, 0x7f, 0x55, 0x78, 0x46,
+	0xe2, 0xba, 0x27, 0x99, 0xdd, 0x79, 0x5a, 0x14, 0x70, 0x03, 0x4a, 0xe1, 0xb7, 0x61, 0x49, 0x1d,
+	0x94, 0x70, 0xb1, 0x17, 0x9f, 0x63, 0x41, 0xc5, 0xf0, 0x9d, 0x02, 0xf5, 0xe3, 0x48, 0x5f, 0x08,
+	0x45, 0x7c, 0xad, 0xc1, 0xd5, 0x18, 0x1c, 0x36, 0xed, 0x03, 0xf2, 0x94, 0xe8, 0xe1, 0x0e, 0x40,
+	0x8f, 0x8c, 0x0c, 0x8f, 0x43, 0xe6, 0x6a, 0x08, 0x4e, 0x1a, 0xf9, 0x3a, 0x3c, 0x0d, 0x2e, 0xf4,
+	0xe4, 0xd7, 0xa2, 0xea, 0xe3, 0x5b, 0x05, 0x6a, 0x47, 0x5d, 0xb0, 0x10, 0xea, 0xf8, 0x35, 0x13,
+	0xa9, 0x63, 0xdb, 0x66, 0x16, 0x1b, 0x3d, 0x35, 0xd1, 0x62, 0x03, 0x10, 0xe1, 0x88, 0x8d, 0x8e,
+	0xd3, 0xf7, 0x07, 0xb6, 0x61, 0x9b, 0x03, 0xc2, 0x73, 0x7e, 0x01, 0xaf, 0x88, 0x91, 0x36, 0x1f,
+	0xb8, 0x6f, 0x0e, 0x08, 0xfa, 0x04, 0x2e, 0x4b, 0xeb, 0xb1, 0x10, 0x93, 0xe3, 0xa2, 0x6a, 0x86,
+	0x48, 0x27, 0x30, 0xd1, 0x0a, 0x3b, 0xf0, 0x25, 0xb1, 0xc8, 0xbd, 0xc9, 0x21, 0x29, 0x7f, 0x26,
+	0xc9, 0x2d, 0x9d, 0x2c, 0xb9, 0xc2, 0x2c, 0x92, 0xab, 0xef, 0xc3, 0x52, 0x08, 0x1a, 0xad, 0x41,
+	0x86, 0x43, 0x53, 0x38, 0xb4, 0x62, 0xf8, 0x6a, 0x0c, 0x10, 0xf1, 0x01, 0x54, 0x85, 0xec, 0xd0,
+	0xec, 0xfb, 0x84, 0x3b, 0xae, 0x84, 0x45, 0x03, 0xad, 0x41, 0x31, 0xc1, 0x15, 0xf7, 0x55, 0x09,
+	0x43, 0x1c, 0x8d, 0x93, 0xb2, 0x4e, 0x30, 0xb6, 0x10, 0xb2, 0xb6, 0x61, 0x99, 0xab, 0x89, 0xe7,
+	0x66, 0x6e, 0x10, 0x8b, 0x4e, 0x39, 0x85, 0xe8, 0xd4, 0x89, 0x8f, 0x14, 0x2d, 0xf9, 0x48, 0xd1,
+	0x7f, 0x89, 0xd3, 0xee, 0x96, 0xc9, 0x3a, 0x87, 0x4f, 0xe8, 0xe1, 0x75, 0x07, 0xf2, 0x01, 0x66,
+	0x8b, 0x08, 0x3c, 0xc5, 0xcd, 0xab, 0xa1, 0x69, 0xea, 0xf4, 0x38, 0xb4, 0x9b, 0xf7, 0x85, 0xbd,
+	0x0e, 0x15, 0x93, 0x1e, 0xf3, 0xba, 0x2e, 0x9b, 0x74, 0x82, 0x4e, 0x73, 0x33, 0x85, 0xc6, 0xef,
+	0xe3, 0xd4, 0x39, 0x46, 0xdc, 0x85, 0xa9, 0x68, 0x03, 0xf2, 0x42, 0x23, 0x21, 0x65, 0xc7, 0xc9,
+	0x28, 0x34, 0xd1, 0xbf, 0x82, 0x2a, 0x67, 0x32, 0xbe, 0xf0, 0xe7, 0x28, 0xa6, 0xf4, 0x7b, 0x47,
+	0x3b, 0xf2, 0xde, 0xd1, 0x7f, 0x53, 0xe1, 0x7a, 0x92, 0x9e, 0x27, 0xf9, 0xa6, 0x7b, 0x25, 0x2d,
+	0xae, 0x6b, 0x63, 0xe2, 0x4a, 0x51, 0xb2, 0xb0, 0x0a, 0xfb, 0x51, 0x81, 0xb5, 0x89, 0x14, 0x2e,
+	0x88, 0xcc, 0xfe, 0x51, 0xa0, 0xba, 0xcb, 0x3c, 0x62, 0x0e, 0xce, 0x54, 0x91, 0x47, 0xaa, 0x54,
+	0x4f, 0x57, 0x66, 0x6b, 0x33, 0xba, 0x68, 0x5a, 0x3a, 0x4e, 0xf8, 0x25, 0x3b, 0x93, 0x5f, 0xda,
+	0xb0, 0x9a, 0x3a, 0xb2, 0x74, 0x46, 0x1c, 0xe7, 0x95, 0x13, 0xe3, 0xfc, 0x63, 0x15, 0xea, 0x63,
+	0xab, 0x9c, 0x25, 0xf0, 0xce, 0x4c, 0x5f, 0x92, 0x07, 0x6d, 0x62, 0x86, 0xc8, 0x4c, 0x2b, 0x63,
+	0xb3, 0x33, 0x52, 0x7e, 0x6a, 0xb9, 0xef, 0xc0, 0xb3, 0xc7, 0x12, 0x32, 0x07, 0xb9, 0x3f, 0xa8,
+	0xb0, 0x36, 0xb6, 0xd6, 0x99, 0xa3, 0xcf, 0xb9, 0x30, 0x9c, 0x0e, 0x9b, 0x99, 0x13, 0xcb, 0xc4,
+	0x0b, 0x23, 0xfb, 0x3e, 0x34, 0x26, 0x13, 0x34, 0x07, 0xe3, 0x3f, 0xab, 0xf0, 0x5c, 0x7a, 0xc1,
+	0xb3, 0x54, 0x6c, 0xe7, 0xc2, 0xf7, 0x78, 0x19, 0x96, 0x99, 0xa3, 0x0c, 0xbb, 0x30, 0xfe, 0xdf,
+	0x83, 0xeb, 0x93, 0xe8, 0x9a, 0x83, 0xfd, 0x37, 0xa0, 0xb4, 0x45, 0x0e, 0x2c, 0x7b, 0x2e, 0xae,
+	0xf5, 0xd7, 0xa1, 0x2c, 0x67, 0xcb, 0xad, 0x13, 0xd9, 0x42, 0x99, 0x9e, 0x2d, 0xf4, 0x43, 0x28,
+	0xb7, 0x9d, 0xc1, 0xc0, 0x62, 0x17, 0x9d, 0xd4, 0xf5, 0x15, 0xa8, 0x84, 0x3b, 0x09, 0x98, 0xfa,
+	0xe7, 0xb0, 0x8c, 0x9d, 0x7e, 0x7f, 0xdf, 0xec, 0xf4, 0x2e, 0x7c, 0x77, 0x04, 0x2b, 0xf1, 0x5e,
+	0x72, 0xff, 0xbf, 0x55, 0xb8, 0xb4, 0xeb, 0xf6, 0x2d, 0x26, 0x5d, 0x32, 0x0f, 0x84, 0x69, 0xaf,
+	0xac, 0x99, 0x8b, 0xcd, 0x1b, 0x50, 0xa2, 0x01, 0x0e, 0x59, 0x4f, 0xca, 0xf8, 0x5d, 0xe4, 0x7d,
+	0xa2, 0x92, 0x0c, 0x4a, 0xa2, 0xd0, 0xc4, 0xb7, 0x19, 0xd7, 0xb5, 0x86, 0x41, 0x5a, 0xf8, 0x36,
+	0x43, 0x2f, 0xc1, 0x55, 0xdb, 0x1f, 0x18, 0x9e, 0xf3, 0x88, 0x1a, 0x2e, 0xf1, 0x0c, 0xbe, 0xb2,
+	0xe1, 0x9a, 0x1e, 0xe3, 0x8a, 0xd6, 0xf0, 0x65, 0xdb, 0x1f, 0x60, 0xe7, 0x11, 0x7d, 0x40, 0x3c,
+	0xbe, 0xf9, 0x03, 0xd3, 0x63, 0xe8, 0x2d, 0x28, 0x98, 0xfd, 0x03, 0xc7, 0xb3, 0xd8, 0xe1, 0x40,
+	0x16, 0x90, 0xba, 0x84, 0x79, 0x84, 0x99, 0xd6, 0xdb, 0xa1, 0x25, 0x8e, 0x27, 0xa1, 0x17, 0x01,
+	0xf9, 0x94, 0x18, 0x02, 0x9c, 0xd8, 0x74, 0xb8, 0x29, 0xab, 0xc9, 0x65, 0x9f, 0x92, 0x78, 0x99,
+	0x8f, 0x37, 0xf5, 0x3f, 0x34, 0x40, 0xc9, 0x75, 0xa5, 0x5e, 0x5f, 0x85, 0x1c, 0x9f, 0x4f, 0x6b,
+	0x0a, 0xbf, 0xe3, 0x6b, 0x91, 0x1b, 0x8f, 0xd8, 0xb6, 0x02, 0xd8, 0x58, 0x9a, 0xd7, 0x3f, 0x83,
+	0x52, 0x78, 0xf1, 0xf8, 0x71, 0x92, 0xde, 0x50, 0xa6, 0x06, 0x13, 0x75, 0x86, 0x60, 0x52, 0x7f,
+	0x13, 0x0a, 0x3c, 0x89, 0x9d, 0xb8, 0x76, 0x9c, 0x7a, 0xd5, 0x64, 0xea, 0xad, 0xff, 0xa9, 0x40,
+	0x86, 0x4f, 0x9e, 0xf9, 0xd5, 0xfe, 0x3e, 0x54, 0x22, 0x94, 0xc2, 0x7b, 0x42, 0xd9, 0x37, 0xa7,
+	0x50, 0x92, 0xa4, 0x00, 0x97, 0x7a, 0x49, 0x42, 0xda, 0x00, 0xe2, 0xdf, 0x22, 0xbe, 0x94, 0xd0,
+	0xe1, 0xf3, 0x53, 0x96, 0x8a, 0x8e, 0x8b, 0x0b, 0x34, 0x3a, 0x39, 0x82, 0x0c, 0xb5, 0xbe, 0x14,
+	0x0f, 0x2f, 0x0d, 0xf3, 0x6f, 0xfd, 0x2e, 0xac, 0xbe, 0x4b, 0xd8, 0xae, 0x37, 0x0c, 0x13, 0x4f,
+	0x78, 0x7d, 0xa6, 0xd0, 0xa4, 0x63, 0xb8, 0x92, 0x9e, 0x24, 0x15, 0xf0, 0x1a, 0x94, 0xa8, 0x37,
+	0x34, 0xc6, 0x66, 0x06, 0x41, 0x38, 0x72, 0x4f, 0x72, 0x52, 0x91, 0xc6, 0x0d, 0xfd, 0x27, 0x15,
+	0x2e, 0x7f, 0xe4, 0x76, 0x4d, 0x46, 0x44, 0x3c, 0x3e, 0xff, 0x6b, 0x5c, 0x85, 0x2c, 0xe7, 0x42,
+	0xa6, 0x27, 0xd1, 0x40, 0xb7, 0xa1, 0x10, 0x39, 0x8a, 0x33, 0x73, 0xbc, 0x9a, 0x96, 0x42, 0x77,
+	0xcc, 0x9b, 0x99, 0xae, 0x41, 0x81, 0x59, 0x03, 0x42, 0x99, 0x39, 0x70, 0xe5, 0x4d, 0x8e, 0x3b,
+	0x02, 0x5d, 0x91, 0x21, 0xb1, 0x59, 0x2d, 0x3f, 0xa6, 0xab, 0xed, 0xa0, 0x6f, 0xcf, 0xe9, 0x11,
+	0x1b, 0x8b, 0x71, 0xbd, 0x07, 0xd5, 0x71, 0x96, 0x24, 0xf1, 0xcd, 0x70, 0x81, 0xf1, 0x24, 0x25,
+	0x73, 0x5b, 0x30, 0x22, 0x57, 0x40, 0xb7, 0x60, 0xc5, 0x23, 0xd4, 0x1f, 0x10, 0x23, 0xc6, 0x23,
+	0xfe, 0xde, 0x5b, 0x16, 0xfd, 0x7b, 0x61, 0xf7, 0x56, 0x1d, 0x6a, 0x1d, 0x67, 0xd0, 0x1a, 0x39,
+	0x3e, 0xf3, 0xf7, 0x49, 0x6b, 0x68, 0x31, 0x42, 0xa9, 0xf8, 0xcb, 0x73, 0x3f, 0xc7, 0x7f, 0xee,
+	0xfe, 0x3f, 0x00, 0xbe, 0xe3, 0x3c, 0x98, 0x3b, 0x1d, 0x00, 0x00,
 }
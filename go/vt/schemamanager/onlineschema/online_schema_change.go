@@ -0,0 +1,400 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package onlineschema implements a workflow that alters a table without
+locking it, and without the backup rotation of schemaswap.
+
+On each shard, in parallel, the workflow:
+  - creates a shadow table like the original one, and alters it.
+  - copies the rows to the shadow table in chunks, in primary key order.
+    The copy is throttled by a throttler.Throttler, which keeps the
+    replication lag of the REPLICA and RDONLY tablets of the shard
+    under a maximum, and can be reconfigured while the workflow is
+    running.
+  - at the same time, tails the changes to the original table from the
+    update stream of the master, and applies them to the shadow table by
+    copying the changed rows again.
+  - cuts over: the table is blacklisted on the master for the duration
+    of an atomic RENAME TABLE, after which the remaining changes are
+    applied. The original table is kept under a different name.
+
+The workflow checkpoints its progress, and resumes from the last copied
+chunk if vtctld restarts. The changes must be tracked by the update
+stream: the tables need a primary key, and all DMLs must have the
+_stream comments added by vttablet. The workflow must be the only one
+to alter the table while it's running.
+*/
+package onlineschema
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/vt/concurrency"
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/tabletmanager/tmclient"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/workflow"
+	"github.com/youtube/vitess/go/vt/wrangler"
+
+	workflowpb "github.com/youtube/vitess/go/vt/proto/workflow"
+)
+
+var (
+	queryTimeout = flag.Duration("online_schema_change_query_timeout", time.Minute,
+		"timeout for each SQL query sent to the masters by the online schema change workflows")
+	checkpointInterval = flag.Duration("online_schema_change_checkpoint_interval", 10*time.Second,
+		"how often the online schema change workflows checkpoint their progress")
+)
+
+const (
+	workflowFactoryName = "online_schema_change"
+
+	// uiUpdateInterval is how often the progress is refreshed in the UI.
+	uiUpdateInterval = time.Second
+
+	// defaultMaxReplicationLag is the default of -max_replication_lag.
+	defaultMaxReplicationLag = 10
+)
+
+// Phases of a shard, saved in ShardProgress.
+const (
+	// phaseInit is the initial phase: the shadow table may not exist.
+	phaseInit = ""
+	// phaseCopy is when the rows are copied and the changes applied.
+	phaseCopy = "copy"
+	// phaseCutover is set before the table is blacklisted. The shadow
+	// table may have been renamed already.
+	phaseCutover = "cutover"
+	// phaseDone means the shard has the new schema.
+	phaseDone = "done"
+)
+
+// ChangeData is the data structure serialized as JSON in Workflow.Data.
+type ChangeData struct {
+	Keyspace string
+	Table    string
+	// Alter is the part of the ALTER TABLE statement after the table
+	// name, e.g. "ADD COLUMN foo INT".
+	Alter string
+	// ChunkSize is the number of rows copied by each query.
+	ChunkSize int
+	// MaxRate is the initial maximum number of chunks copied per
+	// second, per shard.
+	MaxRate int64
+	// MaxReplicationLag is the replication lag, in seconds, that the
+	// throttler keeps the replicas of each shard under. If it's not
+	// set, defaultMaxReplicationLag is used.
+	MaxReplicationLag int64
+
+	// Shards contains the progress of each shard.
+	Shards map[string]*ShardProgress
+}
+
+// ShardProgress is the checkpointed progress of a shard.
+type ShardProgress struct {
+	Phase string
+	// Position is the replication position from which the changes
+	// still need to be applied.
+	Position string
+	// LastPK is the primary key of the last copied row, as an SQL
+	// tuple. It's empty until the first chunk is copied.
+	LastPK string
+	// CopyDone is set once all the rows were copied.
+	CopyDone bool
+	// CutoverPosition is the position of the master right after the
+	// RENAME. The changes are applied up to it.
+	CutoverPosition string
+	// RowsCopied, RowsEstimate and ChangesApplied are for display.
+	RowsCopied     uint64
+	RowsEstimate   uint64
+	ChangesApplied uint64
+}
+
+// Change implements the workflow.Workflow interface.
+type Change struct {
+	// mu protects data, and the UI nodes.
+	mu sync.Mutex
+	// data is the current state.
+	data *ChangeData
+
+	manager *workflow.Manager
+	wi      *topo.WorkflowInfo
+	wr      *wrangler.Wrangler
+	logger  *logutil.MemoryLogger
+
+	// rootNode has a child per shard, in shardNodes.
+	rootNode   *workflow.Node
+	shardNodes map[string]*workflow.Node
+	// lastUIUpdate and lastCheckpoint are when the nodes were last
+	// broadcast, and the data last saved.
+	lastUIUpdate   time.Time
+	lastCheckpoint time.Time
+}
+
+// Run is part of the workflow.Workflow interface.
+func (c *Change) Run(ctx context.Context, manager *workflow.Manager, wi *topo.WorkflowInfo) error {
+	c.manager = manager
+	c.wi = wi
+	c.wr = wrangler.New(c.logger, manager.TopoServer(), tmclient.NewTabletManagerClient())
+
+	shards, err := c.initShards(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.addNodes(shards); err != nil {
+		return err
+	}
+	defer manager.NodeManager().RemoveRootNode(c.rootNode)
+
+	var rec concurrency.AllErrorRecorder
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard string) {
+			defer wg.Done()
+			sc := &shardChange{
+				parent:   c,
+				shard:    shard,
+				progress: c.data.Shards[shard],
+			}
+			if err := sc.run(ctx); err != nil {
+				c.logger.Errorf("Shard %v: %v", shard, err)
+				rec.RecordError(fmt.Errorf("shard %v: %v", shard, err))
+			}
+		}(shard)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uiUpdateLocked()
+	c.rootNode.BroadcastChanges(true /* updateChildren */)
+	if rec.HasErrors() {
+		return rec.Error()
+	}
+	return nil
+}
+
+// addNodes creates the UI nodes, a child per shard, and adds them
+// to the NodeManager.
+func (c *Change) addNodes(shards []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rootNode = workflow.NewNode()
+	c.rootNode.AttachToWorkflow(c.wi, c)
+	c.rootNode.State = workflowpb.WorkflowState_Running
+	c.rootNode.Display = workflow.NodeDisplayDeterminate
+	c.rootNode.Message = fmt.Sprintf("Online schema change of table %v in keyspace %v: ALTER TABLE %v %v", c.data.Table, c.data.Keyspace, c.data.Table, c.data.Alter)
+	c.shardNodes = make(map[string]*workflow.Node)
+	for _, shard := range shards {
+		node := workflow.NewNode()
+		node.Name = "Shard " + shard
+		node.PathName = shard
+		node.State = workflowpb.WorkflowState_Running
+		node.Display = workflow.NodeDisplayDeterminate
+		c.rootNode.Children = append(c.rootNode.Children, node)
+		c.shardNodes[shard] = node
+	}
+	c.uiUpdateLocked()
+	return c.manager.NodeManager().AddRootNode(c.rootNode)
+}
+
+// initShards reads the shards of the keyspace, and adds them to the
+// data the first time the workflow runs. It returns the sorted list
+// of shards.
+func (c *Change) initShards(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data.Shards == nil {
+		shardNames, err := c.manager.TopoServer().GetShardNames(ctx, c.data.Keyspace)
+		if err != nil {
+			return nil, err
+		}
+		if len(shardNames) == 0 {
+			return nil, fmt.Errorf("keyspace %v has no shards", c.data.Keyspace)
+		}
+		c.data.Shards = make(map[string]*ShardProgress)
+		for _, shard := range shardNames {
+			c.data.Shards[shard] = &ShardProgress{}
+		}
+		if err := c.checkpointLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+	shards := make([]string, 0, len(c.data.Shards))
+	for shard := range c.data.Shards {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	return shards, nil
+}
+
+// Action is part of the workflow.Workflow interface. The copy can be
+// slowed down or paused with the throttler commands instead.
+func (c *Change) Action(ctx context.Context, path, name string) error {
+	return fmt.Errorf("cannot execute action '%s', '%s' on online schema change", path, name)
+}
+
+// updateShard is how the shards change their progress: f is called
+// with the lock held. The UI is refreshed, and the data checkpointed,
+// periodically or if force is set.
+func (c *Change) updateShard(ctx context.Context, shard string, force bool, f func(sp *ShardProgress)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f(c.data.Shards[shard])
+	now := time.Now()
+	if force || now.Sub(c.lastUIUpdate) > uiUpdateInterval {
+		c.uiUpdateLocked()
+		c.rootNode.BroadcastChanges(true /* updateChildren */)
+		c.lastUIUpdate = now
+	}
+	if force || now.Sub(c.lastCheckpoint) > *checkpointInterval {
+		return c.checkpointLocked(ctx)
+	}
+	return nil
+}
+
+// uiUpdateLocked updates the computed parts of the nodes, based on the
+// current state.
+func (c *Change) uiUpdateLocked() {
+	var copied, estimate uint64
+	done := 0
+	for shard, node := range c.shardNodes {
+		sp := c.data.Shards[shard]
+		copied += sp.RowsCopied
+		estimate += sp.RowsEstimate
+		node.Progress = shardPercent(sp)
+		node.ProgressMessage = fmt.Sprintf("%v/~%v rows copied, %v changes applied", sp.RowsCopied, sp.RowsEstimate, sp.ChangesApplied)
+		switch sp.Phase {
+		case phaseInit:
+			node.Message = "Creating the shadow table"
+		case phaseCopy:
+			if sp.CopyDone {
+				node.Message = "Applying the last changes before the cutover"
+			} else {
+				node.Message = "Copying the rows and applying the changes"
+			}
+		case phaseCutover:
+			node.Message = "Cutting over"
+		case phaseDone:
+			node.Message = fmt.Sprintf("Done, the original table was renamed to %v", oldTableName(c.data.Table))
+			node.State = workflowpb.WorkflowState_Done
+			done++
+		}
+	}
+	c.rootNode.ProgressMessage = fmt.Sprintf("%v/%v shards done, %v/~%v rows copied", done, len(c.shardNodes), copied, estimate)
+	if len(c.shardNodes) > 0 {
+		total := 0
+		for shard := range c.shardNodes {
+			total += shardPercent(c.data.Shards[shard])
+		}
+		c.rootNode.Progress = total / len(c.shardNodes)
+	}
+	c.rootNode.Log = c.logger.String()
+}
+
+// shardPercent returns the progress of a shard, 100 meaning done. The
+// copy is based on the estimated number of rows, which may be off.
+func shardPercent(sp *ShardProgress) int {
+	switch {
+	case sp.Phase == phaseDone:
+		return 100
+	case sp.CopyDone:
+		return 99
+	case sp.RowsEstimate == 0:
+		return 0
+	}
+	p := int(100 * sp.RowsCopied / sp.RowsEstimate)
+	if p > 98 {
+		p = 98
+	}
+	return p
+}
+
+// checkpointLocked saves a checkpoint in topo server.
+// Needs to be called with the lock.
+func (c *Change) checkpointLocked(ctx context.Context) error {
+	var err error
+	c.wi.Data, err = json.Marshal(c.data)
+	if err != nil {
+		return err
+	}
+	if err := c.manager.TopoServer().SaveWorkflow(ctx, c.wi); err != nil {
+		c.logger.Errorf("SaveWorkflow failed: %v", err)
+		return err
+	}
+	c.lastCheckpoint = time.Now()
+	return nil
+}
+
+// ChangeFactory is the factory to register the online schema change
+// workflows.
+type ChangeFactory struct{}
+
+// RegisterWorkflowFactory registers the online schema change as a valid
+// factory in the workflow framework.
+func RegisterWorkflowFactory() {
+	workflow.Register(workflowFactoryName, &ChangeFactory{})
+}
+
+// Init is part of the workflow.Factory interface.
+func (*ChangeFactory) Init(w *workflowpb.Workflow, args []string) error {
+	subFlags := flag.NewFlagSet(workflowFactoryName, flag.ContinueOnError)
+	keyspace := subFlags.String("keyspace", "", "Name of the keyspace of the table")
+	table := subFlags.String("table", "", "Name of the table to alter")
+	alter := subFlags.String("alter", "", "Alterations to apply to the table, e.g. 'ADD COLUMN foo INT'")
+	chunkSize := subFlags.Int("chunk_size", 1000, "Number of rows copied by each query")
+	maxRate := subFlags.Int64("max_rate", 10, "Maximum number of chunks copied per second, per shard. It can be changed while the workflow runs with the throttler commands")
+	maxReplicationLag := subFlags.Int64("max_replication_lag", defaultMaxReplicationLag, "Maximum replication lag of the replicas, in seconds. The copy is slowed down to keep the lag of the REPLICA and RDONLY tablets of each shard below it")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if *keyspace == "" || *table == "" || *alter == "" {
+		return fmt.Errorf("keyspace, table and alter must be provided for an online schema change")
+	}
+	if *chunkSize <= 0 {
+		return fmt.Errorf("chunk_size must be positive")
+	}
+	if *maxReplicationLag <= 0 {
+		return fmt.Errorf("max_replication_lag must be positive")
+	}
+
+	w.Name = fmt.Sprintf("Online schema change of %v.%v", *keyspace, *table)
+	data := &ChangeData{
+		Keyspace:          *keyspace,
+		Table:             *table,
+		Alter:             *alter,
+		ChunkSize:         *chunkSize,
+		MaxRate:           *maxRate,
+		MaxReplicationLag: *maxReplicationLag,
+	}
+	var err error
+	w.Data, err = json.Marshal(data)
+	return err
+}
+
+// Instantiate is part of the workflow.Factory interface.
+func (*ChangeFactory) Instantiate(w *workflowpb.Workflow) (workflow.Workflow, error) {
+	data := &ChangeData{}
+	if err := json.Unmarshal(w.Data, data); err != nil {
+		return nil, err
+	}
+	return &Change{
+		data:   data,
+		logger: logutil.NewMemoryLogger(),
+	}, nil
+}
+
+// Compile time interface check.
+var _ workflow.Factory = (*ChangeFactory)(nil)
+var _ workflow.Workflow = (*Change)(nil)
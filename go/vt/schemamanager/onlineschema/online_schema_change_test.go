@@ -0,0 +1,437 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package onlineschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/discovery"
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+	"github.com/youtube/vitess/go/vt/throttler"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/memorytopo"
+	"github.com/youtube/vitess/go/vt/workflow"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+	workflowpb "github.com/youtube/vitess/go/vt/proto/workflow"
+)
+
+// fakeExecutor checks the queries sent to the master, and returns the
+// expected results.
+type fakeExecutor struct {
+	t        *testing.T
+	expected []expectedQuery
+}
+
+type expectedQuery struct {
+	query  string
+	result *sqltypes.Result
+}
+
+func (fe *fakeExecutor) add(query string, result *sqltypes.Result) {
+	if result == nil {
+		result = &sqltypes.Result{}
+	}
+	fe.expected = append(fe.expected, expectedQuery{query, result})
+}
+
+func (fe *fakeExecutor) execute(ctx context.Context, query string, maxRows int) (*sqltypes.Result, error) {
+	if len(fe.expected) == 0 {
+		fe.t.Fatalf("unexpected query: %v", query)
+	}
+	eq := fe.expected[0]
+	fe.expected = fe.expected[1:]
+	if query != eq.query {
+		fe.t.Fatalf("got query:\n%v\nwant:\n%v", query, eq.query)
+	}
+	return eq.result, nil
+}
+
+func (fe *fakeExecutor) checkDone() {
+	if len(fe.expected) != 0 {
+		fe.t.Errorf("queries not executed: %v", fe.expected)
+	}
+}
+
+func int64Row(values ...int64) []sqltypes.Value {
+	row := make([]sqltypes.Value, len(values))
+	for i, v := range values {
+		row[i] = sqltypes.MakeTrusted(sqltypes.Int64, []byte(fmt.Sprintf("%v", v)))
+	}
+	return row
+}
+
+// newTestShardChange returns a shardChange on shard 0 of table t,
+// with the id and name columns. Its workflow is saved in a memory
+// topo, and its UI nodes are registered.
+func newTestShardChange(t *testing.T, chunkSize int) (*shardChange, *fakeExecutor) {
+	ctx := context.Background()
+	ts := topo.Server{Impl: memorytopo.NewMemoryTopo([]string{"cell1"})}
+	data := &ChangeData{
+		Keyspace:  "ks",
+		Table:     "t",
+		Alter:     "ADD COLUMN c INT",
+		ChunkSize: chunkSize,
+		MaxRate:   1000,
+		Shards: map[string]*ShardProgress{
+			"0": {Phase: phaseCopy},
+		},
+	}
+	w := &workflowpb.Workflow{
+		Uuid:        "uuid",
+		FactoryName: workflowFactoryName,
+	}
+	var err error
+	if w.Data, err = json.Marshal(data); err != nil {
+		t.Fatal(err)
+	}
+	wi, err := ts.CreateWorkflow(ctx, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Change{
+		data:    data,
+		manager: workflow.NewManager(ts),
+		wi:      wi,
+		logger:  logutil.NewMemoryLogger(),
+	}
+	if err := c.addNodes([]string{"0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	fe := &fakeExecutor{t: t}
+	sc := &shardChange{
+		parent:    c,
+		shard:     "0",
+		progress:  data.Shards["0"],
+		execute:   fe.execute,
+		events:    make(chan *querypb.StreamEvent, 10),
+		streamErr: make(chan error, 1),
+	}
+	if err := sc.setColumns("t", "_t_new", []string{"id", "name", "old"}, []string{"id"}, []string{"id", "name", "c"}, []string{"id"}); err != nil {
+		t.Fatal(err)
+	}
+	return sc, fe
+}
+
+func TestSetColumns(t *testing.T) {
+	sc, _ := newTestShardChange(t, 10)
+	if want := []string{"id", "name"}; !reflect.DeepEqual(sc.columns, want) {
+		t.Errorf("columns = %v, want %v", sc.columns, want)
+	}
+
+	for _, test := range []struct {
+		srcPK, dstPK []string
+		dstColumns   []string
+		want         string
+	}{{
+		dstColumns: []string{"id"},
+		want:       "table t has no primary key",
+	}, {
+		srcPK:      []string{"id"},
+		dstPK:      []string{"id", "name"},
+		dstColumns: []string{"id", "name"},
+		want:       "changing the primary key is not supported",
+	}, {
+		srcPK: []string{"id"},
+		want:  "table _t_new not found",
+	}} {
+		err := sc.setColumns("t", "_t_new", []string{"id", "name"}, test.srcPK, test.dstColumns, test.dstPK)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("setColumns(%v, %v, %v) = %v, want %v", test.srcPK, test.dstColumns, test.dstPK, err, test.want)
+		}
+	}
+}
+
+func TestCopyChunks(t *testing.T) {
+	sc, fe := newTestShardChange(t, 2)
+	ctx := context.Background()
+
+	fe.add("SELECT `id` FROM `t` ORDER BY `id` LIMIT 1 OFFSET 1", &sqltypes.Result{Rows: [][]sqltypes.Value{int64Row(2)}})
+	fe.add("INSERT IGNORE INTO `_t_new` (`id`, `name`) SELECT `id`, `name` FROM `t` WHERE (`id`) <= (2) ORDER BY `id` /* online schema change */", &sqltypes.Result{RowsAffected: 2})
+	fe.add("SELECT `id` FROM `t` WHERE (`id`) > (2) ORDER BY `id` LIMIT 1 OFFSET 1", nil)
+	fe.add("INSERT IGNORE INTO `_t_new` (`id`, `name`) SELECT `id`, `name` FROM `t` WHERE (`id`) > (2) ORDER BY `id` /* online schema change */", &sqltypes.Result{RowsAffected: 1})
+	if err := sc.copy(ctx); err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	fe.checkDone()
+
+	want := ShardProgress{
+		Phase:      phaseCopy,
+		LastPK:     "(2)",
+		CopyDone:   true,
+		RowsCopied: 3,
+	}
+	if *sc.progress != want {
+		t.Errorf("progress = %#v, want %#v", *sc.progress, want)
+	}
+
+	// The last chunk forces a checkpoint.
+	wi, err := sc.parent.manager.TopoServer().GetWorkflow(ctx, "uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &ChangeData{}
+	if err := json.Unmarshal(wi.Data, data); err != nil {
+		t.Fatal(err)
+	}
+	if got := *data.Shards["0"]; got != want {
+		t.Errorf("checkpointed progress = %#v, want %#v", got, want)
+	}
+}
+
+// dmlEvent returns an event changing the rows with the provided ids.
+func dmlEvent(table string, gtid string, ids ...int64) *querypb.StreamEvent {
+	stmt := &querypb.StreamEvent_Statement{
+		Category:         querypb.StreamEvent_Statement_DML,
+		TableName:        table,
+		PrimaryKeyFields: []*querypb.Field{{Name: "id", Type: sqltypes.Int64}},
+	}
+	for _, id := range ids {
+		stmt.PrimaryKeyValues = append(stmt.PrimaryKeyValues, sqltypes.RowsToProto3([][]sqltypes.Value{int64Row(id)})...)
+	}
+	return &querypb.StreamEvent{
+		Statements: []*querypb.StreamEvent_Statement{stmt},
+		EventToken: &querypb.EventToken{Position: gtid},
+	}
+}
+
+func TestApplyEvents(t *testing.T) {
+	sc, fe := newTestShardChange(t, 10)
+	ctx := context.Background()
+
+	// Nothing to do without events.
+	if err := sc.applyEvents(ctx, "t", "_t_new", false /* wait */, replication.Position{}); err != nil {
+		t.Fatalf("applyEvents failed: %v", err)
+	}
+
+	sc.events <- dmlEvent("t", "MariaDB/0-1-1", 1, 2)
+	sc.events <- dmlEvent("other", "MariaDB/0-1-2", 3)
+	ownQuery := dmlEvent("t", "MariaDB/0-1-3")
+	ownQuery.Statements[0] = &querypb.StreamEvent_Statement{
+		Category: querypb.StreamEvent_Statement_Error,
+		Sql:      []byte(copyChunkQuery("t", []string{"id", "name"}, []string{"id"}, "", "(2)")),
+	}
+	sc.events <- ownQuery
+	sc.events <- dmlEvent("t", "MariaDB/0-1-4", 2, 5)
+	fe.add("DELETE FROM `_t_new` WHERE (`id`) IN ((1), (2), (5)) /* online schema change */", nil)
+	fe.add("INSERT INTO `_t_new` (`id`, `name`) SELECT `id`, `name` FROM `t` WHERE (`id`) IN ((1), (2), (5)) /* online schema change */", nil)
+	if err := sc.applyEvents(ctx, "t", "_t_new", false /* wait */, replication.Position{}); err != nil {
+		t.Fatalf("applyEvents failed: %v", err)
+	}
+	fe.checkDone()
+	if sc.progress.Position != "MariaDB/0-1-4" || sc.progress.ChangesApplied != 3 {
+		t.Errorf("progress = %#v", *sc.progress)
+	}
+
+	// applyUntil waits for the position.
+	go func() {
+		sc.events <- dmlEvent("other", "MariaDB/0-1-5")
+		sc.events <- dmlEvent("t", "MariaDB/0-1-6", 7)
+	}()
+	fe.add("DELETE FROM `t` WHERE (`id`) IN ((7)) /* online schema change */", nil)
+	fe.add("INSERT INTO `t` (`id`, `name`) SELECT `id`, `name` FROM `_t_old` WHERE (`id`) IN ((7)) /* online schema change */", nil)
+	if err := sc.applyUntil(ctx, "_t_old", "t", "MariaDB/0-1-6"); err != nil {
+		t.Fatalf("applyUntil failed: %v", err)
+	}
+	fe.checkDone()
+	if want, _ := replication.DecodePosition("MariaDB/0-1-6"); !sc.position.Equal(want) {
+		t.Errorf("position = %v, want %v", sc.position, want)
+	}
+
+	// Changes we can't track fail the workflow.
+	untracked := dmlEvent("t", "MariaDB/0-1-7")
+	untracked.Statements[0] = &querypb.StreamEvent_Statement{
+		Category: querypb.StreamEvent_Statement_Error,
+		Sql:      []byte("update t set name = 'a'"),
+	}
+	sc.events <- untracked
+	want := "cannot track the changes of a statement without _stream comment"
+	if err := sc.applyEvents(ctx, "t", "_t_new", false /* wait */, replication.Position{}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("applyEvents = %v, want %v", err, want)
+	}
+}
+
+// ownEvent returns the event of a query sent by the workflow.
+func ownEvent(query, gtid string) *querypb.StreamEvent {
+	return &querypb.StreamEvent{
+		Statements: []*querypb.StreamEvent_Statement{{
+			Category: querypb.StreamEvent_Statement_Error,
+			Sql:      []byte(query),
+		}},
+		EventToken: &querypb.EventToken{Position: gtid},
+	}
+}
+
+func TestApplyUntilMultipleBatches(t *testing.T) {
+	// Two rows per batch.
+	sc, fe := newTestShardChange(t, 2)
+	ctx := context.Background()
+
+	// After the cutover, the workflow's own queries change the
+	// table. They are skipped, and the events after the target
+	// are left in the stream.
+	sc.events <- dmlEvent("t", "MariaDB/0-1-1", 1)
+	sc.events <- dmlEvent("t", "MariaDB/0-1-2", 2)
+	sc.events <- dmlEvent("t", "MariaDB/0-1-3", 3)
+	sc.events <- ownEvent(deleteRowsQuery("t", []string{"id"}, []string{"(1)", "(2)"}), "MariaDB/0-1-4")
+	sc.events <- dmlEvent("t", "MariaDB/0-1-5", 4)
+	for _, pks := range [][]string{{"(1)", "(2)"}, {"(3)"}} {
+		fe.add(deleteRowsQuery("t", sc.pkColumns, pks), nil)
+		fe.add(copyRowsQuery("_t_old", "t", sc.columns, sc.pkColumns, pks), nil)
+	}
+	if err := sc.applyUntil(ctx, "_t_old", "t", "MariaDB/0-1-3"); err != nil {
+		t.Fatalf("applyUntil failed: %v", err)
+	}
+	fe.checkDone()
+	if sc.progress.Position != "MariaDB/0-1-3" || sc.progress.ChangesApplied != 3 {
+		t.Errorf("progress = %#v", *sc.progress)
+	}
+	if got := len(sc.events); got != 2 {
+		t.Errorf("%v events left in the stream, want 2", got)
+	}
+
+	// The next batch goes on from there, skipping our own query.
+	fe.add(deleteRowsQuery("t", sc.pkColumns, []string{"(4)"}), nil)
+	fe.add(copyRowsQuery("_t_old", "t", sc.columns, sc.pkColumns, []string{"(4)"}), nil)
+	if err := sc.applyUntil(ctx, "_t_old", "t", "MariaDB/0-1-5"); err != nil {
+		t.Fatalf("applyUntil failed: %v", err)
+	}
+	fe.checkDone()
+	if sc.progress.Position != "MariaDB/0-1-5" || sc.progress.ChangesApplied != 4 {
+		t.Errorf("progress = %#v", *sc.progress)
+	}
+}
+
+func TestApplyUntilResume(t *testing.T) {
+	sc, fe := newTestShardChange(t, 10)
+	ctx := context.Background()
+
+	// The workflow stopped during the cutover, after the RENAME. It
+	// resumes from the last saved position, and the stream replays
+	// the queries of the previous run, which changed the table.
+	sc.progress.Phase = phaseCutover
+	sc.progress.Position = "MariaDB/0-1-2"
+	sc.position, _ = replication.DecodePosition(sc.progress.Position)
+	sc.events <- dmlEvent("t", "MariaDB/0-1-3", 1)
+	sc.events <- ddlEvent(renameQuery("t"), "MariaDB/0-1-4")
+	sc.events <- ownEvent(deleteRowsQuery("t", sc.pkColumns, []string{"(1)"}), "MariaDB/0-1-5")
+	sc.events <- ownEvent(copyRowsQuery("_t_old", "t", sc.columns, sc.pkColumns, []string{"(1)"}), "MariaDB/0-1-6")
+	fe.add(deleteRowsQuery("t", sc.pkColumns, []string{"(1)"}), nil)
+	fe.add(copyRowsQuery("_t_old", "t", sc.columns, sc.pkColumns, []string{"(1)"}), nil)
+	if err := sc.applyUntil(ctx, "_t_old", "t", "MariaDB/0-1-6"); err != nil {
+		t.Fatalf("applyUntil failed: %v", err)
+	}
+	fe.checkDone()
+	if sc.progress.Position != "MariaDB/0-1-6" || sc.progress.ChangesApplied != 1 {
+		t.Errorf("progress = %#v", *sc.progress)
+	}
+}
+
+// ddlEvent returns the event of a DDL.
+func ddlEvent(query, gtid string) *querypb.StreamEvent {
+	return &querypb.StreamEvent{
+		Statements: []*querypb.StreamEvent_Statement{{
+			Category: querypb.StreamEvent_Statement_DDL,
+			Sql:      []byte(query),
+		}},
+		EventToken: &querypb.EventToken{Position: gtid},
+	}
+}
+
+func TestQueries(t *testing.T) {
+	for _, test := range []struct {
+		got, want string
+	}{{
+		got:  createShadowQuery("t"),
+		want: "CREATE TABLE `_t_new` LIKE `t`",
+	}, {
+		got:  alterShadowQuery("t", "ADD COLUMN c INT"),
+		want: "ALTER TABLE `_t_new` ADD COLUMN c INT",
+	}, {
+		got:  showShadowQuery("my_t"),
+		want: "SHOW TABLES LIKE '\\_my\\_t\\_new'",
+	}, {
+		got:  chunkEndQuery("t", []string{"a", "b"}, "(1, 'x')", 100),
+		want: "SELECT `a`, `b` FROM `t` WHERE (`a`, `b`) > (1, 'x') ORDER BY `a`, `b` LIMIT 1 OFFSET 99",
+	}, {
+		got:  copyChunkQuery("t", []string{"a", "b", "c"}, []string{"a", "b"}, "(1, 'x')", "(3, 'y')"),
+		want: "INSERT IGNORE INTO `_t_new` (`a`, `b`, `c`) SELECT `a`, `b`, `c` FROM `t` WHERE (`a`, `b`) > (1, 'x') AND (`a`, `b`) <= (3, 'y') ORDER BY `a`, `b` /* online schema change */",
+	}, {
+		got:  deleteRowsQuery("t", []string{"id"}, []string{"(1)", "(2)"}),
+		want: "DELETE FROM `t` WHERE (`id`) IN ((1), (2)) /* online schema change */",
+	}, {
+		got:  copyRowsQuery("_t_old", "t", []string{"id", "name"}, []string{"id"}, []string{"(1)", "(2)"}),
+		want: "INSERT INTO `t` (`id`, `name`) SELECT `id`, `name` FROM `_t_old` WHERE (`id`) IN ((1), (2)) /* online schema change */",
+	}, {
+		got:  renameQuery("t"),
+		want: "RENAME TABLE `t` TO `_t_old`, `_t_new` TO `t`",
+	}} {
+		if test.got != test.want {
+			t.Errorf("got:\n%v\nwant:\n%v", test.got, test.want)
+		}
+	}
+}
+
+func TestFactoryInit(t *testing.T) {
+	f := &ChangeFactory{}
+	w := &workflowpb.Workflow{}
+	if err := f.Init(w, []string{"-keyspace", "ks", "-table", "t"}); err == nil {
+		t.Errorf("Init without -alter should have failed")
+	}
+	if err := f.Init(w, []string{"-keyspace", "ks", "-table", "t", "-alter", "ADD COLUMN c INT", "-chunk_size", "10"}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	wf, err := f.Instantiate(w)
+	if err != nil {
+		t.Fatalf("Instantiate failed: %v", err)
+	}
+	want := &ChangeData{
+		Keyspace:          "ks",
+		Table:             "t",
+		Alter:             "ADD COLUMN c INT",
+		ChunkSize:         10,
+		MaxRate:           10,
+		MaxReplicationLag: defaultMaxReplicationLag,
+	}
+	if got := wf.(*Change).data; !reflect.DeepEqual(got, want) {
+		t.Errorf("data = %#v, want %#v", got, want)
+	}
+}
+
+func TestReplicaWatcher(t *testing.T) {
+	ctx := context.Background()
+	ts := topo.Server{Impl: memorytopo.NewMemoryTopo([]string{"cell1", "cell2"})}
+	thr, err := throttler.NewThrottler("TestReplicaWatcher", "chunks", 1, 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw, err := newReplicaWatcher(ctx, ts, "ks", "0", thr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(rw.watchers); got != 2 {
+		t.Errorf("got %v topology watchers, want one per cell", got)
+	}
+	stats := &discovery.TabletStats{
+		Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}},
+		Target: &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: topodatapb.TabletType_REPLICA},
+		Up:     true,
+		Stats:  &querypb.RealtimeStats{SecondsBehindMaster: 1},
+	}
+	rw.StatsUpdate(stats)
+	rw.close()
+	// The updates received after close are ignored.
+	rw.StatsUpdate(stats)
+}
@@ -0,0 +1,139 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package onlineschema
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/youtube/vitess/go/sqltypes"
+)
+
+// This file contains the queries sent to the masters.
+
+// ownQueryComment ends the queries that change the rows of the tables.
+// They don't have a _stream comment, so the update stream doesn't tell
+// which rows they change: it's how applyEvents recognizes and skips
+// them, including the ones of a previous run of the workflow. Like the
+// _stream comment, it's at the end, so the binlog streamer still sees
+// a DML.
+const ownQueryComment = " /* online schema change */"
+
+// isOwnQuery returns true if the statement was sent by the workflow.
+func isOwnQuery(sql string) bool {
+	return strings.Contains(sql, ownQueryComment)
+}
+
+// shadowTableName returns the name of the table the new schema is
+// built in.
+func shadowTableName(table string) string {
+	return "_" + table + "_new"
+}
+
+// oldTableName returns the name the original table is renamed to
+// during the cutover.
+func oldTableName(table string) string {
+	return "_" + table + "_old"
+}
+
+// escape adds surrounding backticks (`) to an MySQL identifier.
+func escape(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+// columnList returns the escaped, comma-separated list of columns.
+func columnList(columns []string) string {
+	escaped := make([]string, len(columns))
+	for i, col := range columns {
+		escaped[i] = escape(col)
+	}
+	return strings.Join(escaped, ", ")
+}
+
+// encodeTuple returns the SQL representation of a row of values,
+// e.g. "(1, 'a')".
+func encodeTuple(row []sqltypes.Value) string {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('(')
+	for i, v := range row {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		v.EncodeSQL(buf)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+func createShadowQuery(table string) string {
+	return fmt.Sprintf("CREATE TABLE %v LIKE %v", escape(shadowTableName(table)), escape(table))
+}
+
+func dropShadowQuery(table string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS %v", escape(shadowTableName(table)))
+}
+
+func alterShadowQuery(table, alter string) string {
+	return fmt.Sprintf("ALTER TABLE %v %v", escape(shadowTableName(table)), alter)
+}
+
+func showShadowQuery(table string) string {
+	// '_' is a wildcard in LIKE patterns.
+	return fmt.Sprintf("SHOW TABLES LIKE '%v'", strings.Replace(shadowTableName(table), "_", "\\_", -1))
+}
+
+// chunkEndQuery returns the query to find the primary key of the last
+// row of the chunk that starts after lastPK. lastPK is an encoded
+// tuple, or "" for the first chunk.
+func chunkEndQuery(table string, pkColumns []string, lastPK string, chunkSize int) string {
+	pk := columnList(pkColumns)
+	where := ""
+	if lastPK != "" {
+		where = fmt.Sprintf(" WHERE (%v) > %v", pk, lastPK)
+	}
+	return fmt.Sprintf("SELECT %v FROM %v%v ORDER BY %v LIMIT 1 OFFSET %v", pk, escape(table), where, pk, chunkSize-1)
+}
+
+// copyChunkQuery returns the query to copy the rows after lastPK, up
+// to and including chunkEnd, to the shadow table. The rows that are
+// already there were copied by the change applier, which is always
+// more recent. chunkEnd is "" for the last chunk.
+func copyChunkQuery(table string, columns, pkColumns []string, lastPK, chunkEnd string) string {
+	pk := columnList(pkColumns)
+	var conditions []string
+	if lastPK != "" {
+		conditions = append(conditions, fmt.Sprintf("(%v) > %v", pk, lastPK))
+	}
+	if chunkEnd != "" {
+		conditions = append(conditions, fmt.Sprintf("(%v) <= %v", pk, chunkEnd))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	cols := columnList(columns)
+	return fmt.Sprintf("INSERT IGNORE INTO %v (%v) SELECT %v FROM %v%v ORDER BY %v%v",
+		escape(shadowTableName(table)), cols, cols, escape(table), where, pk, ownQueryComment)
+}
+
+// deleteRowsQuery and copyRowsQuery re-copy the rows with the provided
+// primary keys from src to dst, to apply the changes made to them.
+func deleteRowsQuery(dst string, pkColumns []string, pks []string) string {
+	return fmt.Sprintf("DELETE FROM %v WHERE (%v) IN (%v)%v", escape(dst), columnList(pkColumns), strings.Join(pks, ", "), ownQueryComment)
+}
+
+func copyRowsQuery(src, dst string, columns, pkColumns []string, pks []string) string {
+	cols := columnList(columns)
+	return fmt.Sprintf("INSERT INTO %v (%v) SELECT %v FROM %v WHERE (%v) IN (%v)%v",
+		escape(dst), cols, cols, escape(src), columnList(pkColumns), strings.Join(pks, ", "), ownQueryComment)
+}
+
+// renameQuery atomically swaps the shadow table in.
+func renameQuery(table string) string {
+	return fmt.Sprintf("RENAME TABLE %v TO %v, %v TO %v",
+		escape(table), escape(oldTableName(table)),
+		escape(shadowTableName(table)), escape(table))
+}
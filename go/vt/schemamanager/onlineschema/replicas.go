@@ -0,0 +1,86 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package onlineschema
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/vt/discovery"
+	"github.com/youtube/vitess/go/vt/throttler"
+	"github.com/youtube/vitess/go/vt/topo"
+
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// Parameters of the health check of the replicas, and of the
+	// topology watchers.
+	healthCheckConnTimeout  = 30 * time.Second
+	healthCheckRetryDelay   = 5 * time.Second
+	healthCheckTimeout      = time.Minute
+	topologyRefreshInterval = 30 * time.Second
+	topologyReadConcurrency = 32
+)
+
+// replicaWatcher feeds the replication lag of the REPLICA and RDONLY
+// tablets of a shard, in all the cells, to the throttler of the copy.
+type replicaWatcher struct {
+	healthCheck discovery.HealthCheck
+	watchers    []*discovery.TopologyWatcher
+
+	// mu protects throttler, which is set to nil by close, so that
+	// StatsUpdate doesn't record a lag after the throttler is closed.
+	mu        sync.Mutex
+	throttler *throttler.Throttler
+}
+
+// newReplicaWatcher starts watching the replicas of the shard. The
+// throttler is closed by close.
+func newReplicaWatcher(ctx context.Context, ts topo.Server, keyspace, shard string, t *throttler.Throttler) (*replicaWatcher, error) {
+	cells, err := ts.GetKnownCells(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rw := &replicaWatcher{
+		healthCheck: discovery.NewHealthCheck(healthCheckConnTimeout, healthCheckRetryDelay, healthCheckTimeout),
+		throttler:   t,
+	}
+	rw.healthCheck.SetListener(rw, false /* sendDownEvents */)
+	for _, cell := range cells {
+		rw.watchers = append(rw.watchers, discovery.NewShardReplicationWatcher(ts, rw.healthCheck, cell, keyspace, shard, topologyRefreshInterval, topologyReadConcurrency))
+	}
+	return rw, nil
+}
+
+// StatsUpdate is part of the discovery.HealthCheckStatsListener
+// interface.
+func (rw *replicaWatcher) StatsUpdate(ts *discovery.TabletStats) {
+	if ts.Target.TabletType != topodatapb.TabletType_REPLICA && ts.Target.TabletType != topodatapb.TabletType_RDONLY {
+		return
+	}
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.throttler != nil {
+		rw.throttler.RecordReplicationLag(time.Now(), ts)
+	}
+}
+
+// close stops watching the replicas, and closes the throttler.
+func (rw *replicaWatcher) close() {
+	for _, w := range rw.watchers {
+		w.Stop()
+	}
+	if err := rw.healthCheck.Close(); err != nil {
+		log.Warningf("Cannot close the health check of the replicas: %v", err)
+	}
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.throttler.Close()
+	rw.throttler = nil
+}
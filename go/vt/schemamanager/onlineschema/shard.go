@@ -0,0 +1,605 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package onlineschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/tabletserver/tabletconn"
+	"github.com/youtube/vitess/go/vt/throttler"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// streamRetryDelay is how long to wait before reconnecting to
+	// the update stream of the master after an error.
+	streamRetryDelay = 5 * time.Second
+	// connectTimeout is the timeout to connect to the master for the
+	// update stream.
+	connectTimeout = 30 * time.Second
+)
+
+// shardChange runs the online schema change on one shard.
+type shardChange struct {
+	parent *Change
+	shard  string
+	// progress must only be changed through parent.updateShard.
+	progress *ShardProgress
+
+	// master is the tablet the queries are sent to, and the changes
+	// streamed from.
+	master *topodatapb.Tablet
+	// execute runs a query on the master. It is replaced in tests.
+	execute func(ctx context.Context, query string, maxRows int) (*sqltypes.Result, error)
+
+	// columns are copied from the original table to the shadow
+	// table. They are identified by pkColumns.
+	columns   []string
+	pkColumns []string
+
+	// events are received from the update stream of the master. An
+	// error of the stream is sent to streamErr.
+	events    chan *querypb.StreamEvent
+	streamErr chan error
+	// position is the position of the last applied event.
+	position replication.Position
+}
+
+func (sc *shardChange) run(ctx context.Context) error {
+	if sc.progress.Phase == phaseDone {
+		// The blacklist may still be in place if we stopped right
+		// after the cutover.
+		return sc.setBlacklisted(ctx, false)
+	}
+	if err := sc.findMaster(ctx); err != nil {
+		return err
+	}
+	sc.execute = sc.executeOnMaster
+
+	table := sc.parent.data.Table
+	src, dst := table, shadowTableName(table)
+	switch sc.progress.Phase {
+	case phaseInit:
+		if err := sc.createShadow(ctx); err != nil {
+			return err
+		}
+	case phaseCutover:
+		qr, err := sc.execute(ctx, showShadowQuery(table), 1)
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) == 0 {
+			// We stopped after the RENAME, only the last
+			// changes are left.
+			src, dst = oldTableName(table), table
+		}
+	}
+	if err := sc.loadColumns(ctx, src, dst); err != nil {
+		return err
+	}
+
+	var err error
+	sc.position, err = replication.DecodePosition(sc.progress.Position)
+	if err != nil {
+		return err
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sc.startStream(streamCtx)
+
+	if dst == table {
+		return sc.finishCutover(ctx)
+	}
+	if err := sc.copy(ctx); err != nil {
+		return err
+	}
+	return sc.cutover(ctx)
+}
+
+func (sc *shardChange) findMaster(ctx context.Context) error {
+	ts := sc.parent.manager.TopoServer()
+	si, err := ts.GetShard(ctx, sc.parent.data.Keyspace, sc.shard)
+	if err != nil {
+		return err
+	}
+	if !si.HasMaster() {
+		return fmt.Errorf("shard %v/%v has no master", sc.parent.data.Keyspace, sc.shard)
+	}
+	ti, err := ts.GetTablet(ctx, si.MasterAlias)
+	if err != nil {
+		return err
+	}
+	sc.master = ti.Tablet
+	return nil
+}
+
+// executeOnMaster executes a query on the master as the dba user.
+// The query is written to the binlogs, so the replicas get the shadow
+// table too.
+func (sc *shardChange) executeOnMaster(ctx context.Context, query string, maxRows int) (*sqltypes.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, *queryTimeout)
+	defer cancel()
+	qr, err := sc.parent.wr.TabletManagerClient().ExecuteFetchAsDba(ctx, sc.master, true /* usePool */, []byte(query), maxRows, false /* disableBinlogs */, false /* reloadSchema */)
+	if err != nil {
+		return nil, fmt.Errorf("query %v failed: %v", query, err)
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
+
+// createShadow creates the shadow table, and records the position
+// from which the changes have to be applied.
+func (sc *shardChange) createShadow(ctx context.Context) error {
+	table := sc.parent.data.Table
+	for _, query := range []string{
+		dropShadowQuery(table),
+		createShadowQuery(table),
+		alterShadowQuery(table, sc.parent.data.Alter),
+	} {
+		if _, err := sc.execute(ctx, query, 0); err != nil {
+			return err
+		}
+	}
+	// The changes made before the shadow table was created will be
+	// copied with the rows.
+	position, err := sc.parent.wr.TabletManagerClient().MasterPosition(ctx, sc.master)
+	if err != nil {
+		return err
+	}
+	sc.parent.logger.Infof("Shard %v: created %v, copying the rows and applying the changes from %v", sc.shard, shadowTableName(table), position)
+	return sc.parent.updateShard(ctx, sc.shard, true /* force */, func(sp *ShardProgress) {
+		sp.Phase = phaseCopy
+		sp.Position = position
+	})
+}
+
+// loadColumns finds the columns to copy from src to dst: the ones they
+// have in common. The primary key must be the same.
+func (sc *shardChange) loadColumns(ctx context.Context, src, dst string) error {
+	sd, err := sc.parent.wr.TabletManagerClient().GetSchema(ctx, sc.master, []string{src, dst}, nil, false /* includeViews */)
+	if err != nil {
+		return err
+	}
+	var srcColumns, srcPK, dstColumns, dstPK []string
+	var rowCount uint64
+	for _, td := range sd.TableDefinitions {
+		switch td.Name {
+		case src:
+			srcColumns, srcPK, rowCount = td.Columns, td.PrimaryKeyColumns, td.RowCount
+		case dst:
+			dstColumns, dstPK = td.Columns, td.PrimaryKeyColumns
+		}
+	}
+	if err := sc.setColumns(src, dst, srcColumns, srcPK, dstColumns, dstPK); err != nil {
+		return err
+	}
+	return sc.parent.updateShard(ctx, sc.shard, false /* force */, func(sp *ShardProgress) {
+		if sp.RowsEstimate == 0 {
+			sp.RowsEstimate = rowCount
+		}
+	})
+}
+
+func (sc *shardChange) setColumns(src, dst string, srcColumns, srcPK, dstColumns, dstPK []string) error {
+	if srcColumns == nil {
+		return fmt.Errorf("table %v not found", src)
+	}
+	if dstColumns == nil {
+		return fmt.Errorf("table %v not found", dst)
+	}
+	if len(srcPK) == 0 {
+		return fmt.Errorf("table %v has no primary key, its changes cannot be tracked", src)
+	}
+	if !reflect.DeepEqual(srcPK, dstPK) {
+		return fmt.Errorf("changing the primary key is not supported: %v has %v, %v has %v", src, srcPK, dst, dstPK)
+	}
+	inDst := make(map[string]bool)
+	for _, col := range dstColumns {
+		inDst[strings.ToLower(col)] = true
+	}
+	sc.columns = nil
+	for _, col := range srcColumns {
+		if inDst[strings.ToLower(col)] {
+			sc.columns = append(sc.columns, col)
+		}
+	}
+	sc.pkColumns = srcPK
+	return nil
+}
+
+// startStream streams the changes from the master, from the current
+// position, into sc.events. It reconnects after errors, until ctx is
+// done.
+func (sc *shardChange) startStream(ctx context.Context) {
+	sc.events = make(chan *querypb.StreamEvent, 100)
+	sc.streamErr = make(chan error, 1)
+	target := &querypb.Target{
+		Keyspace:   sc.master.Keyspace,
+		Shard:      sc.master.Shard,
+		TabletType: topodatapb.TabletType_MASTER,
+	}
+	position := replication.EncodePosition(sc.position)
+	go func() {
+		for {
+			err := sc.stream(ctx, target, &position)
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			log.Warningf("Update stream of %v failed, will retry in %v: %v", sc.master.Alias, streamRetryDelay, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(streamRetryDelay):
+			}
+		}
+	}()
+}
+
+// stream reads the update stream until an error happens. position is
+// updated with each received event.
+func (sc *shardChange) stream(ctx context.Context, target *querypb.Target, position *string) error {
+	conn, err := tabletconn.GetDialer()(sc.master, connectTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	stream, err := conn.UpdateStream(ctx, target, *position, 0)
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if event.EventToken == nil {
+			// This would make us lose track of the position.
+			err := fmt.Errorf("event without EventToken from the update stream: %v", event)
+			select {
+			case sc.streamErr <- err:
+			default:
+			}
+			return err
+		}
+		select {
+		case sc.events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		*position = event.EventToken.Position
+	}
+}
+
+// copy copies the remaining rows to the shadow table, while applying
+// the changes. The copy is throttled to keep the replication lag of the
+// replicas below the maximum.
+func (sc *shardChange) copy(ctx context.Context) error {
+	data := sc.parent.data
+	maxReplicationLag := data.MaxReplicationLag
+	if maxReplicationLag == 0 {
+		// Workflows created before the field was added.
+		maxReplicationLag = defaultMaxReplicationLag
+	}
+	t, err := throttler.NewThrottler(fmt.Sprintf("OnlineSchemaChange/%v/%v", data.Keyspace, sc.shard), "chunks", 1 /* threadCount */, data.MaxRate, maxReplicationLag)
+	if err != nil {
+		return err
+	}
+	rw, err := newReplicaWatcher(ctx, sc.parent.manager.TopoServer(), data.Keyspace, sc.shard, t)
+	if err != nil {
+		t.Close()
+		return err
+	}
+	defer rw.close()
+
+	src, dst := data.Table, shadowTableName(data.Table)
+	for !sc.progress.CopyDone {
+		for {
+			backoff := t.Throttle(0 /* threadID */)
+			if backoff == throttler.NotThrottled {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+		if err := sc.applyEvents(ctx, src, dst, false /* wait */, replication.Position{}); err != nil {
+			return err
+		}
+		if err := sc.copyChunk(ctx); err != nil {
+			return err
+		}
+	}
+	sc.parent.logger.Infof("Shard %v: all the rows were copied", sc.shard)
+	return nil
+}
+
+// copyChunk copies the next chunk of rows.
+func (sc *shardChange) copyChunk(ctx context.Context) error {
+	data := sc.parent.data
+	lastPK := sc.progress.LastPK
+	qr, err := sc.execute(ctx, chunkEndQuery(data.Table, sc.pkColumns, lastPK, data.ChunkSize), 1)
+	if err != nil {
+		return err
+	}
+	chunkEnd := ""
+	if len(qr.Rows) > 0 {
+		chunkEnd = encodeTuple(qr.Rows[0])
+	}
+	qr, err = sc.execute(ctx, copyChunkQuery(data.Table, sc.columns, sc.pkColumns, lastPK, chunkEnd), 0)
+	if err != nil {
+		return err
+	}
+	return sc.parent.updateShard(ctx, sc.shard, chunkEnd == "" /* force */, func(sp *ShardProgress) {
+		sp.RowsCopied += qr.RowsAffected
+		if chunkEnd == "" {
+			sp.CopyDone = true
+		} else {
+			sp.LastPK = chunkEnd
+		}
+	})
+}
+
+// applyEvents applies the changes of the events received so far, by
+// copying the changed rows again from src to dst. If wait is set, it
+// waits for at least one event. If until is set, it stops at the first
+// event at or after it, and leaves the following ones for later.
+func (sc *shardChange) applyEvents(ctx context.Context, src, dst string, wait bool, until replication.Position) error {
+	var pks []string
+	seen := make(map[string]bool)
+	position := sc.position
+	received := 0
+	for len(pks) < sc.parent.data.ChunkSize && (until.IsZero() || !position.AtLeast(until)) {
+		var event *querypb.StreamEvent
+		if wait && received == 0 {
+			select {
+			case event = <-sc.events:
+			case err := <-sc.streamErr:
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else {
+			select {
+			case event = <-sc.events:
+			case err := <-sc.streamErr:
+				return err
+			default:
+			}
+		}
+		if event == nil {
+			break
+		}
+		received++
+
+		eventPKs, err := sc.changedPKs(event)
+		if err != nil {
+			return err
+		}
+		for _, pk := range eventPKs {
+			if !seen[pk] {
+				seen[pk] = true
+				pks = append(pks, pk)
+			}
+		}
+		position, err = replication.DecodePosition(event.EventToken.Position)
+		if err != nil {
+			return err
+		}
+	}
+	if received == 0 {
+		return nil
+	}
+
+	if len(pks) > 0 {
+		if _, err := sc.execute(ctx, deleteRowsQuery(dst, sc.pkColumns, pks), 0); err != nil {
+			return err
+		}
+		if _, err := sc.execute(ctx, copyRowsQuery(src, dst, sc.columns, sc.pkColumns, pks), 0); err != nil {
+			return err
+		}
+	}
+	sc.position = position
+	return sc.parent.updateShard(ctx, sc.shard, false /* force */, func(sp *ShardProgress) {
+		sp.Position = replication.EncodePosition(position)
+		sp.ChangesApplied += uint64(len(pks))
+	})
+}
+
+// changedPKs returns the primary keys of the rows of the table changed
+// by the event, as SQL tuples.
+func (sc *shardChange) changedPKs(event *querypb.StreamEvent) ([]string, error) {
+	table := sc.parent.data.Table
+	var pks []string
+	for _, stmt := range event.Statements {
+		switch stmt.Category {
+		case querypb.StreamEvent_Statement_DML:
+			if stmt.TableName != table {
+				continue
+			}
+			if len(stmt.PrimaryKeyFields) != len(sc.pkColumns) {
+				return nil, fmt.Errorf("unexpected primary key %v in the update stream for table %v, expected %v", stmt.PrimaryKeyFields, table, sc.pkColumns)
+			}
+			for _, row := range stmt.PrimaryKeyValues {
+				// The values were built by the EventStreamer
+				// from the _stream comment.
+				pks = append(pks, encodeTuple(sqltypes.MakeRowTrusted(stmt.PrimaryKeyFields, row)))
+			}
+		case querypb.StreamEvent_Statement_Error:
+			// Our own queries end up here. During the
+			// cutover, they change the table.
+			if isOwnQuery(string(stmt.Sql)) {
+				continue
+			}
+			if changesTable(string(stmt.Sql), table) {
+				return nil, fmt.Errorf("cannot track the changes of a statement without _stream comment: %s", stmt.Sql)
+			}
+		}
+	}
+	return pks, nil
+}
+
+// changesTable returns true if the DML may change the table.
+func changesTable(sql, table string) bool {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return strings.Contains(sql, table)
+	}
+	switch stmt := stmt.(type) {
+	case *sqlparser.Insert:
+		return sqlparser.GetTableName(stmt.Table) == table
+	case *sqlparser.Update:
+		return sqlparser.GetTableName(stmt.Table) == table
+	case *sqlparser.Delete:
+		return sqlparser.GetTableName(stmt.Table) == table
+	}
+	return false
+}
+
+// catchUp applies the changes up to the current position of the
+// master.
+func (sc *shardChange) catchUp(ctx context.Context, src, dst string) error {
+	position, err := sc.parent.wr.TabletManagerClient().MasterPosition(ctx, sc.master)
+	if err != nil {
+		return err
+	}
+	return sc.applyUntil(ctx, src, dst, position)
+}
+
+// applyUntil applies the changes up to the provided position.
+func (sc *shardChange) applyUntil(ctx context.Context, src, dst, position string) error {
+	target, err := replication.DecodePosition(position)
+	if err != nil {
+		return err
+	}
+	for !sc.position.AtLeast(target) {
+		if err := sc.applyEvents(ctx, src, dst, true /* wait */, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cutover swaps the shadow table in. The table is blacklisted on the
+// master during the RENAME, so the changes that were still in flight
+// can be applied before it's used again.
+func (sc *shardChange) cutover(ctx context.Context) error {
+	table := sc.parent.data.Table
+	// Catch up first, to keep the table blacklisted as short as
+	// possible.
+	if err := sc.catchUp(ctx, table, shadowTableName(table)); err != nil {
+		return err
+	}
+	if err := sc.parent.updateShard(ctx, sc.shard, true /* force */, func(sp *ShardProgress) {
+		sp.Phase = phaseCutover
+	}); err != nil {
+		return err
+	}
+
+	sc.parent.logger.Infof("Shard %v: blacklisting %v on master %v for the cutover", sc.shard, table, sc.master.Alias)
+	if err := sc.setBlacklisted(ctx, true); err != nil {
+		return err
+	}
+	// The RENAME waits for the transactions that use the table.
+	if _, err := sc.execute(ctx, renameQuery(table), 0); err != nil {
+		sc.unblacklist(ctx)
+		return err
+	}
+	position, err := sc.parent.wr.TabletManagerClient().MasterPosition(ctx, sc.master)
+	if err != nil {
+		sc.unblacklist(ctx)
+		return err
+	}
+	if err := sc.parent.updateShard(ctx, sc.shard, true /* force */, func(sp *ShardProgress) {
+		sp.CutoverPosition = position
+	}); err != nil {
+		sc.unblacklist(ctx)
+		return err
+	}
+	return sc.finishCutover(ctx)
+}
+
+// finishCutover applies the last changes, from the original table
+// (which was renamed) to the new one, and removes the blacklist.
+func (sc *shardChange) finishCutover(ctx context.Context) error {
+	position, err := sc.applyLastChanges(ctx)
+	sc.unblacklist(ctx)
+	if err != nil {
+		return err
+	}
+	sc.parent.logger.Infof("Shard %v: cutover done, the original table is now %v", sc.shard, oldTableName(sc.parent.data.Table))
+	sc.parent.wr.ReloadSchemaShard(ctx, sc.parent.data.Keyspace, sc.shard, position)
+	return nil
+}
+
+// applyLastChanges applies the changes up to the cutover position, and
+// returns it.
+func (sc *shardChange) applyLastChanges(ctx context.Context) (string, error) {
+	table := sc.parent.data.Table
+	position := sc.progress.CutoverPosition
+	if position == "" {
+		// We stopped before saving it. The table is still
+		// blacklisted, so the current position is as good.
+		var err error
+		position, err = sc.parent.wr.TabletManagerClient().MasterPosition(ctx, sc.master)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := sc.applyUntil(ctx, oldTableName(table), table, position); err != nil {
+		return "", err
+	}
+	if err := sc.parent.wr.TabletManagerClient().ReloadSchema(ctx, sc.master, ""); err != nil {
+		return "", err
+	}
+	return position, sc.parent.updateShard(ctx, sc.shard, true /* force */, func(sp *ShardProgress) {
+		sp.Phase = phaseDone
+	})
+}
+
+// unblacklist removes the blacklist, logging errors.
+func (sc *shardChange) unblacklist(ctx context.Context) {
+	if err := sc.setBlacklisted(ctx, false); err != nil {
+		sc.parent.logger.Errorf("Shard %v: cannot remove the blacklist of %v, use SetShardTabletControl: %v", sc.shard, sc.parent.data.Table, err)
+	}
+}
+
+// setBlacklisted blacklists the table on the master, or removes the
+// blacklist if it's the one we set.
+func (sc *shardChange) setBlacklisted(ctx context.Context, blacklisted bool) error {
+	data := sc.parent.data
+	tables := []string{data.Table}
+	si, err := sc.parent.manager.TopoServer().GetShard(ctx, data.Keyspace, sc.shard)
+	if err != nil {
+		return err
+	}
+	tc := si.GetTabletControl(topodatapb.TabletType_MASTER)
+	isBlacklisted := tc != nil && reflect.DeepEqual(tc.BlacklistedTables, tables)
+	if blacklisted == isBlacklisted {
+		return nil
+	}
+	if err := sc.parent.wr.SetShardTabletControl(ctx, data.Keyspace, sc.shard, topodatapb.TabletType_MASTER, nil /* cells */, !blacklisted /* remove */, false /* disableQueryService */, tables); err != nil {
+		return err
+	}
+	if sc.master == nil {
+		if err := sc.findMaster(ctx); err != nil {
+			return err
+		}
+	}
+	return sc.parent.wr.TabletManagerClient().RefreshState(ctx, sc.master)
+}
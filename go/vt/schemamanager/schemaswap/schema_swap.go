@@ -793,7 +793,7 @@ func (shardSwap *shardSchemaSwap) takeSeedBackup() error {
 		return fmt.Errorf("Cannot find the seed tablet on shard %v", shardSwap.shardName)
 	}
 
-	eventStream, err := shardSwap.parent.tabletClient.Backup(shardSwap.parent.ctx, seedTablet, *backupConcurrency)
+	eventStream, err := shardSwap.parent.tabletClient.Backup(shardSwap.parent.ctx, seedTablet, *backupConcurrency, false /* incremental */)
 	if err != nil {
 		return err
 	}
@@ -832,7 +832,7 @@ func (shardSwap *shardSchemaSwap) verifySwapApplied() error {
 // to become healthy and to catch up with replication.
 func (shardSwap *shardSchemaSwap) swapOnTablet(tablet *topodatapb.Tablet) error {
 	log.Infof("Restoring tablet %v from backup", tablet.Alias)
-	eventStream, err := shardSwap.parent.tabletClient.RestoreFromBackup(shardSwap.parent.ctx, tablet)
+	eventStream, err := shardSwap.parent.tabletClient.RestoreFromBackup(shardSwap.parent.ctx, tablet, "", time.Time{})
 	if err != nil {
 		return err
 	}
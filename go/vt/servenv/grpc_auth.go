@@ -0,0 +1,142 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"strings"
+
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// This file handles the authentication of the gRPC calls. When
+// -grpc_auth_mode is set, every call is authenticated by the
+// Authenticator of that plugin, and rejected if it fails. The
+// authenticated user is stored in the Context of the call, where the
+// services use it as the verified immediate caller id.
+
+// Authenticator authenticates the caller of a gRPC call.
+type Authenticator interface {
+	// Authenticate returns the name of the user making the call
+	// with the given Context, or an error if the call must be
+	// rejected.
+	Authenticate(ctx context.Context, fullMethod string) (string, error)
+}
+
+// AuthenticatorFactory creates an Authenticator. It is called after the
+// flags are parsed, when the gRPC server is created.
+type AuthenticatorFactory func() (Authenticator, error)
+
+var (
+	// GRPCAuth is the name of the authentication plugin to use for
+	// the gRPC calls. If not set, the calls are not authenticated.
+	GRPCAuth *string
+
+	// GRPCAuthProxyUsers are the authenticated users allowed to make
+	// calls on behalf of other users, like vtgate does when it calls
+	// vttablet.
+	GRPCAuthProxyUsers *string
+
+	authPlugins = make(map[string]AuthenticatorFactory)
+)
+
+// RegisterAuthPlugin registers an authentication plugin, to be used
+// with -grpc_auth_mode=<name>. It should be called in an init function.
+func RegisterAuthPlugin(name string, factory AuthenticatorFactory) {
+	if _, ok := authPlugins[name]; ok {
+		log.Fatalf("gRPC authentication plugin %v already registered", name)
+	}
+	authPlugins[name] = factory
+}
+
+// enableGRPCAuth installs the interceptors of the authentication plugin
+// selected by -grpc_auth_mode, if any.
+func enableGRPCAuth() {
+	if GRPCAuth == nil || *GRPCAuth == "" {
+		return
+	}
+	factory, ok := authPlugins[*GRPCAuth]
+	if !ok {
+		log.Fatalf("Unknown gRPC authentication plugin %v", *GRPCAuth)
+	}
+	authenticator, err := factory()
+	if err != nil {
+		log.Fatalf("Cannot create the gRPC authentication plugin %v: %v", *GRPCAuth, err)
+	}
+	log.Infof("Authenticating the gRPC calls with the %v plugin", *GRPCAuth)
+	unaryInterceptors = append([]grpc.UnaryServerInterceptor{authUnaryInterceptor(authenticator)}, unaryInterceptors...)
+	streamInterceptors = append([]grpc.StreamServerInterceptor{authStreamInterceptor(authenticator)}, streamInterceptors...)
+}
+
+type authenticatedUserKey struct{}
+
+// authenticate returns a Context based on ctx, with the user of the
+// call authenticated by authenticator.
+func authenticate(ctx context.Context, authenticator Authenticator, fullMethod string) (context.Context, error) {
+	user, err := authenticator.Authenticate(ctx, fullMethod)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+	}
+	return context.WithValue(ctx, authenticatedUserKey{}, user), nil
+}
+
+func authUnaryInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// AuthenticatedUser returns the user authenticated by the gRPC
+// authentication plugin for the call with the given Context. The bool
+// is false if the call was not authenticated.
+func AuthenticatedUser(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(authenticatedUserKey{}).(string)
+	return user, ok
+}
+
+// VerifiedCaller returns the verified name of the caller of a call,
+// which claims to be made on behalf of claimedUser. If the call was
+// not authenticated, claimedUser is trusted and returned. If it was, it
+// is the authenticated user, unless this user is one of the proxy users
+// in -grpc_auth_proxy_users, which are trusted to make calls on behalf
+// of the claimed users.
+func VerifiedCaller(ctx context.Context, claimedUser string) string {
+	user, ok := AuthenticatedUser(ctx)
+	if !ok {
+		return claimedUser
+	}
+	if claimedUser != "" && isProxyUser(user) {
+		return claimedUser
+	}
+	return user
+}
+
+func isProxyUser(user string) bool {
+	if GRPCAuthProxyUsers == nil || *GRPCAuthProxyUsers == "" {
+		return false
+	}
+	for _, proxy := range strings.Split(*GRPCAuthProxyUsers, ",") {
+		if strings.TrimSpace(proxy) == user {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+var mtlsAuthUsersFile = flag.String("grpc_auth_mtls_users_file", "", "JSON file mapping the common names of the client certificates to users for the mtls gRPC authentication plugin, as {\"<common name>\": \"<user>\"}. If not set, the common names are the users.")
+
+func init() {
+	RegisterAuthPlugin("mtls", func() (Authenticator, error) {
+		if GRPCCA == nil || *GRPCCA == "" {
+			return nil, errors.New("the mtls gRPC authentication plugin requires -grpc_ca, to verify the client certificates")
+		}
+		return newMTLSAuthenticatorFromFile(*mtlsAuthUsersFile)
+	})
+}
+
+// mtlsAuthenticator authenticates the users with the common name of
+// the verified certificate of the client.
+type mtlsAuthenticator struct {
+	// users maps the common names to the users. If nil, the common
+	// names are the users.
+	users map[string]string
+}
+
+func newMTLSAuthenticatorFromFile(file string) (*mtlsAuthenticator, error) {
+	if file == "" {
+		return &mtlsAuthenticator{}, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	ma := &mtlsAuthenticator{}
+	if err := json.Unmarshal(data, &ma.users); err != nil {
+		return nil, fmt.Errorf("cannot parse %v: %v", file, err)
+	}
+	return ma, nil
+}
+
+// clientCommonName returns the common name of the verified client
+// certificate of the call, or "".
+func clientCommonName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	if len(tlsInfo.State.VerifiedChains) < 1 || len(tlsInfo.State.VerifiedChains[0]) < 1 {
+		return ""
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+// Authenticate is part of the Authenticator interface.
+func (ma *mtlsAuthenticator) Authenticate(ctx context.Context, fullMethod string) (string, error) {
+	cn := clientCommonName(ctx)
+	if cn == "" {
+		return "", errors.New("no verified client certificate")
+	}
+	if ma.users == nil {
+		return cn, nil
+	}
+	user, ok := ma.users[cn]
+	if !ok {
+		return "", fmt.Errorf("unknown client certificate common name %v", cn)
+	}
+	return user, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/vt/servenv/grpcutils"
+)
+
+var staticAuthFile = flag.String("grpc_auth_static_file", "", "JSON file of the users accepted by the static gRPC authentication plugin, as {\"<user>\": {\"PasswordHash\": \"<bcrypt hash of the password>\"}}. Requires TLS")
+
+func init() {
+	RegisterAuthPlugin("static", func() (Authenticator, error) {
+		// The passwords are sent with every call.
+		if GRPCCert == nil || *GRPCCert == "" || GRPCKey == nil || *GRPCKey == "" {
+			return nil, errors.New("the static gRPC authentication plugin requires TLS, with -grpc_cert and -grpc_key")
+		}
+		return newStaticAuthenticatorFromFile(*staticAuthFile)
+	})
+}
+
+// StaticAuthUser is an entry of the file of the static authentication
+// plugin.
+type StaticAuthUser struct {
+	// PasswordHash is the bcrypt hash of the password, e.g. as
+	// generated by htpasswd -nbB.
+	PasswordHash string
+}
+
+// staticAuthenticator authenticates the users with the username and
+// the password they send in the metadata of the calls, against a
+// static list of users.
+type staticAuthenticator struct {
+	// users maps the usernames to the bcrypt hash of their
+	// password.
+	users map[string][]byte
+
+	// mu protects verified, which maps the usernames to the SHA-256
+	// of the last password which matched their bcrypt hash. bcrypt is
+	// too slow to be checked on every call: the calls with the same
+	// password only check it once.
+	mu       sync.Mutex
+	verified map[string][sha256.Size]byte
+}
+
+func newStaticAuthenticatorFromFile(file string) (*staticAuthenticator, error) {
+	if file == "" {
+		return nil, errors.New("the static gRPC authentication plugin requires -grpc_auth_static_file")
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var users map[string]*StaticAuthUser
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("cannot parse %v: %v", file, err)
+	}
+	return newStaticAuthenticator(users)
+}
+
+func newStaticAuthenticator(users map[string]*StaticAuthUser) (*staticAuthenticator, error) {
+	sa := &staticAuthenticator{
+		users:    make(map[string][]byte, len(users)),
+		verified: make(map[string][sha256.Size]byte),
+	}
+	for user, u := range users {
+		hash := []byte(u.PasswordHash)
+		if _, err := bcrypt.Cost(hash); err != nil {
+			return nil, fmt.Errorf("invalid bcrypt password hash for user %v: %v", user, err)
+		}
+		sa.users[user] = hash
+	}
+	return sa, nil
+}
+
+// Authenticate is part of the Authenticator interface.
+func (sa *staticAuthenticator) Authenticate(ctx context.Context, fullMethod string) (string, error) {
+	user := grpcMetadataValue(ctx, grpcutils.UsernameMetadataKey)
+	if user == "" {
+		return "", errors.New("no username in the call metadata")
+	}
+	hash, ok := sa.users[user]
+	if !ok {
+		return "", fmt.Errorf("invalid username or password for user %v", user)
+	}
+	password := []byte(grpcMetadataValue(ctx, grpcutils.PasswordMetadataKey))
+	digest := sha256.Sum256(password)
+	sa.mu.Lock()
+	verified, ok := sa.verified[user]
+	sa.mu.Unlock()
+	if ok && subtle.ConstantTimeCompare(digest[:], verified[:]) == 1 {
+		return user, nil
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, password); err != nil {
+		return "", fmt.Errorf("invalid username or password for user %v", user)
+	}
+	sa.mu.Lock()
+	sa.verified[user] = digest
+	sa.mu.Unlock()
+	return user, nil
+}
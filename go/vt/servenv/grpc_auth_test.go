@@ -0,0 +1,142 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestStaticAuthenticator(t *testing.T) {
+	secretHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa, err := newStaticAuthenticator(map[string]*StaticAuthUser{
+		"vtgate": {PasswordHash: string(secretHash)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testcases := []struct {
+		md   map[string]string
+		user string
+	}{{
+		md:   map[string]string{"username": "vtgate", "password": "secret"},
+		user: "vtgate",
+	}, {
+		md: map[string]string{"username": "vtgate", "password": "wrong"},
+	}, {
+		// The verified password is cached.
+		md:   map[string]string{"username": "vtgate", "password": "secret"},
+		user: "vtgate",
+	}, {
+		md: map[string]string{"username": "vtgate", "password": "wrong"},
+	}, {
+		md: map[string]string{"username": "other", "password": "secret"},
+	}, {
+		md: map[string]string{"password": "secret"},
+	}}
+	for _, tcase := range testcases {
+		user, err := sa.Authenticate(newMetadataContext(context.Background(), tcase.md), "/Service/Method")
+		if tcase.user == "" {
+			if err == nil {
+				t.Errorf("Authenticate(%v) succeeded", tcase.md)
+			}
+			continue
+		}
+		if err != nil || user != tcase.user {
+			t.Errorf("Authenticate(%v): (%v, %v), want %v", tcase.md, user, err, tcase.user)
+		}
+	}
+
+	// Unsalted hashes are not accepted.
+	if _, err := newStaticAuthenticator(map[string]*StaticAuthUser{"vtgate": {PasswordHash: "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"}}); err == nil {
+		t.Errorf("newStaticAuthenticator with an invalid hash succeeded")
+	}
+}
+
+func newPeerContext(cn string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}},
+			},
+		},
+	})
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	ma := &mtlsAuthenticator{}
+	if _, err := ma.Authenticate(context.Background(), "/Service/Method"); err == nil {
+		t.Errorf("Authenticate without a client certificate succeeded")
+	}
+	if user, err := ma.Authenticate(newPeerContext("vtgate-cn"), "/Service/Method"); err != nil || user != "vtgate-cn" {
+		t.Errorf("Authenticate: (%v, %v), want vtgate-cn", user, err)
+	}
+
+	ma = &mtlsAuthenticator{users: map[string]string{"vtgate-cn": "vtgate"}}
+	if user, err := ma.Authenticate(newPeerContext("vtgate-cn"), "/Service/Method"); err != nil || user != "vtgate" {
+		t.Errorf("Authenticate with users: (%v, %v), want vtgate", user, err)
+	}
+	if _, err := ma.Authenticate(newPeerContext("other-cn"), "/Service/Method"); err == nil {
+		t.Errorf("Authenticate with an unknown common name succeeded")
+	}
+}
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(ctx context.Context, fullMethod string) (string, error) {
+	if fullMethod == "/Service/Forbidden" {
+		return "", errors.New("forbidden")
+	}
+	return "user1", nil
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	interceptor := authUnaryInterceptor(fakeAuthenticator{})
+	var got string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = AuthenticatedUser(ctx)
+		return nil, nil
+	}
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/Service/Method"}, handler); err != nil || got != "user1" {
+		t.Errorf("authenticated call: (%v, %v), want user1", got, err)
+	}
+	got = ""
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/Service/Forbidden"}, handler); err == nil || got != "" {
+		t.Errorf("rejected call: (%v, %v), want an error", got, err)
+	}
+}
+
+func TestVerifiedCaller(t *testing.T) {
+	proxyUsers := "vtgate, vtworker"
+	GRPCAuthProxyUsers = &proxyUsers
+	defer func() { GRPCAuthProxyUsers = nil }()
+
+	if got := VerifiedCaller(context.Background(), "claimed"); got != "claimed" {
+		t.Errorf("VerifiedCaller without authentication: %v, want claimed", got)
+	}
+	ctx := context.WithValue(context.Background(), authenticatedUserKey{}, "vtgate")
+	if got := VerifiedCaller(ctx, "claimed"); got != "claimed" {
+		t.Errorf("VerifiedCaller for a proxy user: %v, want claimed", got)
+	}
+	if got := VerifiedCaller(ctx, ""); got != "vtgate" {
+		t.Errorf("VerifiedCaller for a proxy user without claim: %v, want vtgate", got)
+	}
+	ctx = context.WithValue(context.Background(), authenticatedUserKey{}, "app")
+	if got := VerifiedCaller(ctx, "claimed"); got != "app" {
+		t.Errorf("VerifiedCaller for a regular user: %v, want app", got)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/youtube/vitess/go/trace"
+	"github.com/youtube/vitess/go/vt/servenv/grpcutils"
+)
+
+// This file contains the interceptors of the gRPC server. gRPC only
+// accepts one interceptor of each kind, so they are chained, in the
+// order of the lists.
+
+var (
+	unaryInterceptors  = []grpc.UnaryServerInterceptor{traceUnaryInterceptor}
+	streamInterceptors = []grpc.StreamServerInterceptor{traceStreamInterceptor}
+)
+
+// chainUnaryInterceptors returns a unary interceptor calling the
+// interceptors in order.
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors returns a stream interceptor calling the
+// interceptors in order.
+func chainStreamInterceptors(interceptors []grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, next)
+			}
+		}
+		return chained(srv, stream)
+	}
+}
+
+// contextServerStream is a grpc.ServerStream with a different Context.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context is part of the grpc.ServerStream interface.
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// startServerSpan starts the server span of a call, child of the
+// remote span of the client if it sent its trace context.
+func startServerSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	ctx = trace.DecodeContext(ctx, grpcMetadataValue(ctx, grpcutils.TraceMetadataKey))
+	span := trace.NewSpanFromContext(ctx)
+	span.StartServer(method)
+	return trace.NewContext(ctx, span), span
+}
+
+func traceUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	defer span.Finish()
+	return handler(ctx, req)
+}
+
+func traceStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startServerSpan(stream.Context(), info.FullMethod)
+	defer span.Finish()
+	return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,34 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestChainUnaryInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name+" "+info.FullMethod)
+			return handler(ctx, req)
+		}
+	}
+	chained := chainUnaryInterceptors([]grpc.UnaryServerInterceptor{interceptor("first"), interceptor("second")})
+	resp, err := chained(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/Service/Method"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req.(string) + "-resp", nil
+	})
+	if err != nil || resp != "req-resp" {
+		t.Errorf("chained interceptors: (%v, %v), want (req-resp, nil)", resp, err)
+	}
+	if want := []string{"first /Service/Method", "second /Service/Method", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls: %v, want %v", calls, want)
+	}
+}
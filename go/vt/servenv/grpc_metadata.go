@@ -0,0 +1,24 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcMetadataValue returns the first value of key in the metadata of
+// the incoming gRPC call, or "".
+func grpcMetadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md[key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
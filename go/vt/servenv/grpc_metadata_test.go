@@ -0,0 +1,16 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package servenv
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// newMetadataContext returns a Context for an incoming gRPC call with
+// the given metadata.
+func newMetadataContext(ctx context.Context, md map[string]string) context.Context {
+	return metadata.NewContext(ctx, metadata.New(md))
+}
@@ -113,6 +113,10 @@ func createGRPCServer() {
 	if GRPCMaxMessageSize != nil {
 		opts = append(opts, grpc.MaxMsgSize(*GRPCMaxMessageSize))
 	}
+	enableGRPCAuth()
+	opts = append(opts,
+		grpc.UnaryInterceptor(chainUnaryInterceptors(unaryInterceptors)),
+		grpc.StreamInterceptor(chainStreamInterceptors(streamInterceptors)))
 
 	GRPCServer = grpc.NewServer(opts...)
 }
@@ -143,6 +147,8 @@ func RegisterGRPCFlags() {
 	// Note: We're using 4 MiB as default value because that's the default in the
 	// gRPC 1.0.0 Go server.
 	GRPCMaxMessageSize = flag.Int("grpc_max_message_size", 4*1024*1024, "Maximum allowed RPC message size. Larger messages will be rejected by gRPC with the error 'exceeding the max size'.")
+	GRPCAuth = flag.String("grpc_auth_mode", "", "the gRPC authentication plugin to use, static or mtls. If not set, the calls are not authenticated")
+	GRPCAuthProxyUsers = flag.String("grpc_auth_proxy_users", "", "comma separated list of the authenticated users allowed to make calls on behalf of other users, e.g. the user of vtgate on vttablet")
 }
 
 // GRPCCheckServiceMap returns if we should register a gRPC service
@@ -0,0 +1,62 @@
+package grpcutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const (
+	// UsernameMetadataKey is the gRPC metadata key of the username
+	// checked by the static authentication plugin.
+	UsernameMetadataKey = "username"
+
+	// PasswordMetadataKey is the gRPC metadata key of the password
+	// checked by the static authentication plugin.
+	PasswordMetadataKey = "password"
+)
+
+// StaticAuthClientCreds are the credentials sent by a client to a
+// server using the static authentication plugin. It implements
+// credentials.PerRPCCredentials.
+type StaticAuthClientCreds struct {
+	Username string
+	Password string
+}
+
+// GetRequestMetadata is part of the credentials.PerRPCCredentials interface.
+func (c *StaticAuthClientCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		UsernameMetadataKey: c.Username,
+		PasswordMetadataKey: c.Password,
+	}, nil
+}
+
+// RequireTransportSecurity is part of the credentials.PerRPCCredentials interface.
+// The password is sent with every call, so the connection must use TLS.
+func (c *StaticAuthClientCreds) RequireTransportSecurity() bool {
+	return true
+}
+
+// ClientAuthDialOptions returns the gRPC dial options sending the
+// credentials read from credsFile, a JSON file containing
+// {"Username": "<user>", "Password": "<password>"}. If credsFile is
+// not set, no credentials are sent. The credentials are only sent
+// over TLS.
+func ClientAuthDialOptions(credsFile string) ([]grpc.DialOption, error) {
+	if credsFile == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(credsFile)
+	if err != nil {
+		return nil, err
+	}
+	creds := &StaticAuthClientCreds{}
+	if err := json.Unmarshal(data, creds); err != nil {
+		return nil, fmt.Errorf("cannot parse %v: %v", credsFile, err)
+	}
+	return []grpc.DialOption{grpc.WithPerRPCCredentials(creds)}, nil
+}
@@ -0,0 +1,37 @@
+package grpcutils
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/youtube/vitess/go/trace"
+)
+
+// TraceMetadataKey is the gRPC metadata key used to propagate the trace
+// context from the clients to the servers.
+const TraceMetadataKey = "b3"
+
+// traceCredentials is a credentials.PerRPCCredentials which sends the
+// trace context of the call in its metadata.
+type traceCredentials struct{}
+
+// GetRequestMetadata is part of the credentials.PerRPCCredentials interface.
+func (traceCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	value := trace.EncodeContext(ctx)
+	if value == "" {
+		return nil, nil
+	}
+	return map[string]string{TraceMetadataKey: value}, nil
+}
+
+// RequireTransportSecurity is part of the credentials.PerRPCCredentials interface.
+func (traceCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// ClientTraceDialOption returns the gRPC dial option propagating the
+// trace context of the calls to the server, so the spans of both
+// processes are part of the same trace.
+func ClientTraceDialOption() grpc.DialOption {
+	return grpc.WithPerRPCCredentials(traceCredentials{})
+}
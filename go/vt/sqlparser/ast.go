@@ -26,9 +26,19 @@ import (
 
 // Parse parses the sql and returns a Statement, which
 // is the AST representation of the query.
+// If only the beginning of a CREATE TABLE, ALTER TABLE or
+// CREATE INDEX statement can be parsed, Parse returns a DDL
+// with Partial set, and no error: the callers which use the
+// TableSpec or the AlterSpecs of a DDL must check Partial.
 func Parse(sql string) (Statement, error) {
 	tokenizer := NewStringTokenizer(sql)
 	if yyParse(tokenizer) != 0 {
+		if tokenizer.partialDDL != nil {
+			// The beginning of a DDL was parsed, but not the
+			// rest of the statement: return what we have.
+			tokenizer.partialDDL.Partial = true
+			return tokenizer.partialDDL, nil
+		}
 		return nil, errors.New(tokenizer.LastError)
 	}
 	return tokenizer.ParseTree, nil
@@ -91,14 +101,15 @@ type Statement interface {
 	SQLNode
 }
 
-func (*Union) iStatement()  {}
-func (*Select) iStatement() {}
-func (*Insert) iStatement() {}
-func (*Update) iStatement() {}
-func (*Delete) iStatement() {}
-func (*Set) iStatement()    {}
-func (*DDL) iStatement()    {}
-func (*Other) iStatement()  {}
+func (*Union) iStatement()     {}
+func (*Select) iStatement()    {}
+func (*Insert) iStatement()    {}
+func (*Update) iStatement()    {}
+func (*Delete) iStatement()    {}
+func (*Set) iStatement()       {}
+func (*DDL) iStatement()       {}
+func (*Other) iStatement()     {}
+func (*Savepoint) iStatement() {}
 
 // SelectStatement any SELECT statement.
 type SelectStatement interface {
@@ -373,11 +384,18 @@ func (node *Set) WalkSubtree(visit Visit) error {
 // DDL represents a CREATE, ALTER, DROP or RENAME statement.
 // Table is set for AlterStr, DropStr, RenameStr.
 // NewName is set for AlterStr, CreateStr, RenameStr.
+// TableSpec is set for CreateStr, and AlterSpecs for AlterStr, only
+// if the rest of the statement could be parsed. Otherwise, Partial
+// is set, and the DDL doesn't describe the whole statement.
 type DDL struct {
-	Action   string
-	Table    TableIdent
-	NewName  TableIdent
-	IfExists bool
+	Action      string
+	Table       TableIdent
+	NewName     TableIdent
+	IfExists    bool
+	IfNotExists bool
+	TableSpec   *TableSpec
+	AlterSpecs  []*AlterSpec
+	Partial     bool
 }
 
 // DDL strings.
@@ -392,7 +410,14 @@ const (
 func (node *DDL) Format(buf *TrackedBuffer) {
 	switch node.Action {
 	case CreateStr:
-		buf.Myprintf("%s table %v", node.Action, node.NewName)
+		notExists := ""
+		if node.IfNotExists {
+			notExists = " if not exists"
+		}
+		buf.Myprintf("%s table%s %v", node.Action, notExists, node.NewName)
+		if node.TableSpec != nil {
+			buf.Myprintf(" %v", node.TableSpec)
+		}
 	case DropStr:
 		exists := ""
 		if node.IfExists {
@@ -403,6 +428,11 @@ func (node *DDL) Format(buf *TrackedBuffer) {
 		buf.Myprintf("%s table %v %v", node.Action, node.Table, node.NewName)
 	default:
 		buf.Myprintf("%s table %v", node.Action, node.Table)
+		prefix := " "
+		for _, spec := range node.AlterSpecs {
+			buf.Myprintf("%s%v", prefix, spec)
+			prefix = ", "
+		}
 	}
 }
 
@@ -411,10 +441,360 @@ func (node *DDL) WalkSubtree(visit Visit) error {
 	if node == nil {
 		return nil
 	}
-	return Walk(
+	if err := Walk(
 		visit,
 		node.Table,
 		node.NewName,
+		node.TableSpec,
+	); err != nil {
+		return err
+	}
+	for _, spec := range node.AlterSpecs {
+		if err := Walk(visit, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableSpec describes the columns, indexes and options of a table
+// in a CREATE TABLE statement.
+type TableSpec struct {
+	Columns []*ColumnDefinition
+	Indexes []*IndexDefinition
+	Options []*TableOption
+}
+
+// Format formats the node. The columns are listed before the
+// indexes, like in SHOW CREATE TABLE.
+func (node *TableSpec) Format(buf *TrackedBuffer) {
+	prefix := "("
+	for _, col := range node.Columns {
+		buf.Myprintf("%s%v", prefix, col)
+		prefix = ", "
+	}
+	for _, index := range node.Indexes {
+		buf.Myprintf("%s%v", prefix, index)
+		prefix = ", "
+	}
+	buf.Myprintf(")")
+	for _, option := range node.Options {
+		buf.Myprintf(" %v", option)
+	}
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *TableSpec) WalkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	for _, col := range node.Columns {
+		if err := Walk(visit, col); err != nil {
+			return err
+		}
+	}
+	for _, index := range node.Indexes {
+		if err := Walk(visit, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ColumnDefinition describes a column of a table.
+type ColumnDefinition struct {
+	Name ColIdent
+	Type *ColumnType
+}
+
+// Format formats the node.
+func (node *ColumnDefinition) Format(buf *TrackedBuffer) {
+	buf.Myprintf("%v %v", node.Name, node.Type)
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *ColumnDefinition) WalkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	return Walk(
+		visit,
+		node.Name,
+		node.Type,
+	)
+}
+
+// ColumnType describes the type of a column, and its attributes.
+type ColumnType struct {
+	// Type is the lowered name of the type, like "int" or "varchar".
+	Type string
+	// Length and Scale are nil if they were not specified.
+	Length     NumVal
+	Scale      NumVal
+	EnumValues []string
+	Unsigned   bool
+	Zerofill   bool
+	Charset    string
+	Collate    string
+	NotNull    bool
+	// Null is true if NULL was explicitly specified. It is not
+	// the default for TIMESTAMP columns.
+	Null bool
+	// Default and OnUpdate are nil if they were not specified.
+	Default       ValExpr
+	OnUpdate      ValExpr
+	Autoincrement bool
+	// KeyOpt is PrimaryKeyStr or UniqueKeyStr if the column is
+	// declared as a key.
+	KeyOpt  string
+	Comment StrVal
+}
+
+// Format formats the node.
+func (node *ColumnType) Format(buf *TrackedBuffer) {
+	buf.Myprintf("%s", node.Type)
+	switch {
+	case node.Length != nil && node.Scale != nil:
+		buf.Myprintf("(%v, %v)", node.Length, node.Scale)
+	case node.Length != nil:
+		buf.Myprintf("(%v)", node.Length)
+	case node.EnumValues != nil:
+		prefix := "("
+		for _, value := range node.EnumValues {
+			buf.Myprintf("%s%v", prefix, StrVal(value))
+			prefix = ", "
+		}
+		buf.Myprintf(")")
+	}
+	if node.Unsigned {
+		buf.Myprintf(" unsigned")
+	}
+	if node.Zerofill {
+		buf.Myprintf(" zerofill")
+	}
+	if node.Charset != "" {
+		buf.Myprintf(" character set %s", node.Charset)
+	}
+	if node.Collate != "" {
+		buf.Myprintf(" collate %s", node.Collate)
+	}
+	if node.NotNull {
+		buf.Myprintf(" not null")
+	}
+	if node.Null {
+		buf.Myprintf(" null")
+	}
+	if node.Default != nil {
+		buf.Myprintf(" default %v", node.Default)
+	}
+	if node.OnUpdate != nil {
+		buf.Myprintf(" on update %v", node.OnUpdate)
+	}
+	if node.Autoincrement {
+		buf.Myprintf(" auto_increment")
+	}
+	if node.KeyOpt != "" {
+		buf.Myprintf(" %s", node.KeyOpt)
+	}
+	if node.Comment != nil {
+		buf.Myprintf(" comment %v", node.Comment)
+	}
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *ColumnType) WalkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	return Walk(
+		visit,
+		node.Default,
+		node.OnUpdate,
+	)
+}
+
+// IndexDefinition describes an index of a table.
+type IndexDefinition struct {
+	// Type is one of the key strings below.
+	Type string
+	// Name is empty for primary keys and unnamed indexes.
+	Name    ColIdent
+	Columns []*IndexColumn
+	// Using is the lowered index type, like "btree", or empty.
+	Using   string
+	Comment StrVal
+}
+
+// Key strings. They are also used as ColumnType.KeyOpt.
+const (
+	PrimaryKeyStr  = "primary key"
+	UniqueKeyStr   = "unique key"
+	KeyStr         = "key"
+	FulltextKeyStr = "fulltext key"
+	SpatialKeyStr  = "spatial key"
+)
+
+// Format formats the node.
+func (node *IndexDefinition) Format(buf *TrackedBuffer) {
+	buf.Myprintf("%s", node.Type)
+	if node.Name.Original() != "" {
+		buf.Myprintf(" %v", node.Name)
+	}
+	prefix := " ("
+	for _, col := range node.Columns {
+		buf.Myprintf("%s%v", prefix, col)
+		prefix = ", "
+	}
+	buf.Myprintf(")")
+	if node.Using != "" {
+		buf.Myprintf(" using %s", node.Using)
+	}
+	if node.Comment != nil {
+		buf.Myprintf(" comment %v", node.Comment)
+	}
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *IndexDefinition) WalkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	if err := Walk(visit, node.Name); err != nil {
+		return err
+	}
+	for _, col := range node.Columns {
+		if err := Walk(visit, col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexColumn is a column of an index. Length is set for
+// prefix indexes.
+type IndexColumn struct {
+	Column ColIdent
+	Length NumVal
+}
+
+// Format formats the node.
+func (node *IndexColumn) Format(buf *TrackedBuffer) {
+	buf.Myprintf("%v", node.Column)
+	if node.Length != nil {
+		buf.Myprintf("(%v)", node.Length)
+	}
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *IndexColumn) WalkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	return Walk(visit, node.Column)
+}
+
+// TableOption is an option of a CREATE TABLE or ALTER TABLE
+// statement, like ENGINE=InnoDB or COMMENT='users'.
+type TableOption struct {
+	// Name is the lowered name of the option, like "engine"
+	// or "default charset".
+	Name  string
+	Value string
+	// Quoted is true if Value was a string literal.
+	Quoted bool
+}
+
+// Format formats the node.
+func (node *TableOption) Format(buf *TrackedBuffer) {
+	if node.Quoted {
+		buf.Myprintf("%s=%v", node.Name, StrVal(node.Value))
+		return
+	}
+	buf.Myprintf("%s=%s", node.Name, node.Value)
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *TableOption) WalkSubtree(visit Visit) error {
+	return nil
+}
+
+// AlterSpec is one of the operations of an ALTER TABLE statement.
+type AlterSpec struct {
+	Action string
+	// Column is set for AddColumnStr, ChangeColumnStr and
+	// ModifyColumnStr.
+	Column *ColumnDefinition
+	// Name is the dropped column or index, the column renamed by
+	// ChangeColumnStr, or the column altered by AlterColumnStr.
+	Name ColIdent
+	// First and After are the position of the added, changed or
+	// modified column.
+	First bool
+	After ColIdent
+	// Index is set for AddIndexStr.
+	Index *IndexDefinition
+	// Default is the new default of the column for AlterColumnStr.
+	// It is nil if the default is dropped.
+	Default ValExpr
+	// Option is set for TableOptionStr.
+	Option *TableOption
+}
+
+// AlterSpec actions.
+const (
+	AddColumnStr      = "add column"
+	AddIndexStr       = "add index"
+	DropColumnStr     = "drop column"
+	DropIndexStr      = "drop index"
+	DropPrimaryKeyStr = "drop primary key"
+	ChangeColumnStr   = "change column"
+	ModifyColumnStr   = "modify column"
+	AlterColumnStr    = "alter column"
+	TableOptionStr    = "table option"
+)
+
+// Format formats the node.
+func (node *AlterSpec) Format(buf *TrackedBuffer) {
+	switch node.Action {
+	case AddColumnStr, ModifyColumnStr:
+		buf.Myprintf("%s %v", node.Action, node.Column)
+	case ChangeColumnStr:
+		buf.Myprintf("%s %v %v", node.Action, node.Name, node.Column)
+	case AddIndexStr:
+		buf.Myprintf("add %v", node.Index)
+	case DropColumnStr, DropIndexStr:
+		buf.Myprintf("%s %v", node.Action, node.Name)
+	case DropPrimaryKeyStr:
+		buf.Myprintf("%s", node.Action)
+	case AlterColumnStr:
+		if node.Default == nil {
+			buf.Myprintf("%s %v drop default", node.Action, node.Name)
+		} else {
+			buf.Myprintf("%s %v set default %v", node.Action, node.Name, node.Default)
+		}
+	case TableOptionStr:
+		buf.Myprintf("%v", node.Option)
+	}
+	if node.First {
+		buf.Myprintf(" first")
+	}
+	if node.After.Original() != "" {
+		buf.Myprintf(" after %v", node.After)
+	}
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *AlterSpec) WalkSubtree(visit Visit) error {
+	if node == nil {
+		return nil
+	}
+	return Walk(
+		visit,
+		node.Column,
+		node.Name,
+		node.After,
+		node.Index,
+		node.Default,
 	)
 }
 
@@ -433,6 +813,35 @@ func (node *Other) WalkSubtree(visit Visit) error {
 	return nil
 }
 
+// Savepoint represents a SAVEPOINT, ROLLBACK TO SAVEPOINT or
+// RELEASE SAVEPOINT statement.
+type Savepoint struct {
+	Action string
+	Name   ColIdent
+}
+
+// Savepoint strings.
+const (
+	SavepointStr  = "savepoint"
+	RollbackToStr = "rollback to"
+	ReleaseStr    = "release"
+)
+
+// Format formats the node.
+func (node *Savepoint) Format(buf *TrackedBuffer) {
+	switch node.Action {
+	case SavepointStr:
+		buf.Myprintf("savepoint %v", node.Name)
+	default:
+		buf.Myprintf("%s savepoint %v", node.Action, node.Name)
+	}
+}
+
+// WalkSubtree walks the nodes of the subtree
+func (node *Savepoint) WalkSubtree(visit Visit) error {
+	return nil
+}
+
 // Comments represents a list of comments.
 type Comments [][]byte
 
@@ -456,7 +456,7 @@ func TestValid(t *testing.T) {
 		output: "alter table a",
 	}, {
 		input:  "alter table a drop foo",
-		output: "alter table a",
+		output: "alter table a drop column foo",
 	}, {
 		input:  "alter table a disable foo",
 		output: "alter table a",
@@ -489,8 +489,7 @@ func TestValid(t *testing.T) {
 	}, {
 		input: "create table `by`",
 	}, {
-		input:  "create table if not exists a",
-		output: "create table a",
+		input: "create table if not exists a",
 	}, {
 		input:  "create index a on b",
 		output: "alter table b",
@@ -520,10 +519,49 @@ func TestValid(t *testing.T) {
 		output: "drop table if exists a",
 	}, {
 		input:  "drop index b on a",
-		output: "alter table a",
+		output: "alter table a drop index b",
 	}, {
 		input:  "analyze table a",
 		output: "alter table a",
+	}, {
+		input: "create table a (id bigint(20) unsigned not null auto_increment, name varchar(64) character set utf8 collate utf8_bin default 'x' comment 'the name', primary key (id), unique key name_idx (name), key (name(10), id) using btree comment 'prefix')",
+	}, {
+		input:  "CREATE TABLE `a` (\n  `id` int(11) NOT NULL,\n  `price` decimal(10,2) DEFAULT NULL,\n  `state` enum('on','off') NOT NULL DEFAULT 'on',\n  `t` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n  `d` datetime(6) DEFAULT CURRENT_TIMESTAMP(6),\n  `c` text,\n  PRIMARY KEY (`id`),\n  KEY `c_idx` (`c`(20))\n) ENGINE=InnoDB AUTO_INCREMENT=12 DEFAULT CHARSET=utf8 COMMENT='prices'",
+		output: "create table a (id int(11) not null, price decimal(10, 2) default null, state enum('on', 'off') not null default 'on', t timestamp null default current_timestamp() on update current_timestamp(), d datetime(6) default current_timestamp(6), c text, primary key (id), key c_idx (c(20))) engine=InnoDB auto_increment=12 default charset=utf8 comment='prices'",
+	}, {
+		input:  "create table a (a int default -1 unique, c int primary key, fulltext index (d), spatial key e (e)) engine InnoDB, default character set = binary",
+		output: "create table a (a int default -1 unique key, c int primary key, fulltext key (d), spatial key e (e)) engine=InnoDB default character set=binary",
+	}, {
+		input: "create table a (comment int comment 'comment', first int, `key` int)",
+	}, {
+		input:  "create table a (a int, foreign key (a) references b (a))",
+		output: "create table a",
+	}, {
+		input:  "alter table a add column b int not null first, add c varchar(10) after a, add unique key b_idx (b), add index (c), add primary key (id)",
+		output: "alter table a add column b int not null first, add column c varchar(10) after a, add unique key b_idx (b), add key (c), add primary key (id)",
+	}, {
+		input:  "alter table a drop column b, drop index c, drop key d, drop primary key",
+		output: "alter table a drop column b, drop index c, drop index d, drop primary key",
+	}, {
+		input:  "alter table a change b c bigint unsigned after d, modify column e int null, modify f int first",
+		output: "alter table a change column b c bigint unsigned after d, modify column e int null, modify column f int first",
+	}, {
+		input:  "alter table a alter b set default 1, alter column c drop default",
+		output: "alter table a alter column b set default 1, alter column c drop default",
+	}, {
+		input:  "alter table a engine = InnoDB, comment='new', auto_increment=10, default charset=utf8mb4",
+		output: "alter table a engine=InnoDB, comment='new', auto_increment=10, default charset=utf8mb4",
+	}, {
+		input:  "create index a on b (c, d(10))",
+		output: "alter table b add key a (c, d(10))",
+	}, {
+		input:  "create unique index a using hash on b (c)",
+		output: "alter table b add unique key a (c) using hash",
+	}, {
+		input:  "create fulltext index a on b (c) using btree",
+		output: "alter table b add fulltext key a (c) using btree",
+	}, {
+		input: "select first, comment, modify from after",
 	}, {
 		input:  "show foobar",
 		output: "other",
@@ -533,6 +571,23 @@ func TestValid(t *testing.T) {
 	}, {
 		input:  "explain foobar",
 		output: "other",
+	}, {
+		input: "savepoint a",
+	}, {
+		input:  "SAVEPOINT `select`",
+		output: "savepoint `select`",
+	}, {
+		input: "rollback to savepoint a",
+	}, {
+		input:  "rollback to a",
+		output: "rollback to savepoint a",
+	}, {
+		input:  "rollback to savepoint savepoint",
+		output: "rollback to savepoint savepoint",
+	}, {
+		input: "release savepoint a",
+	}, {
+		input: "select savepoint, rollback from t",
 	}}
 	for _, tcase := range validSQL {
 		if tcase.output == "" {
@@ -557,6 +612,46 @@ func TestValid(t *testing.T) {
 	}
 }
 
+func TestPartialDDL(t *testing.T) {
+	testcases := []struct {
+		input   string
+		partial bool
+	}{{
+		input:   "alter table a add foo",
+		partial: true,
+	}, {
+		input:   "create table a (id int) partition by hash(id)",
+		partial: true,
+	}, {
+		input:   "create index b on a (c) garbage",
+		partial: true,
+	}, {
+		input: "alter table a drop foo",
+	}, {
+		input: "create table a (id int)",
+	}, {
+		input:   "create table a",
+		partial: true,
+	}, {
+		input: "drop table a",
+	}}
+	for _, tcase := range testcases {
+		tree, err := Parse(tcase.input)
+		if err != nil {
+			t.Errorf("input: %s, err: %v", tcase.input, err)
+			continue
+		}
+		ddl, ok := tree.(*DDL)
+		if !ok {
+			t.Errorf("input: %s, got %T, want *DDL", tcase.input, tree)
+			continue
+		}
+		if ddl.Partial != tcase.partial {
+			t.Errorf("input: %s, Partial: %v, want %v", tcase.input, ddl.Partial, tcase.partial)
+		}
+	}
+}
+
 func TestCaseSensitivity(t *testing.T) {
 	validSQL := []struct {
 		input  string
@@ -587,7 +682,7 @@ func TestCaseSensitivity(t *testing.T) {
 		output: "drop table if exists B",
 	}, {
 		input:  "drop index b on A",
-		output: "alter table A",
+		output: "alter table A drop index b",
 	}, {
 		input: "select a from B",
 	}, {
@@ -743,6 +838,9 @@ func TestErrors(t *testing.T) {
 	}, {
 		input:  "select next id from a",
 		output: "expecting value after next at position 23",
+	}, {
+		input:  "release a",
+		output: "syntax error at position 10 near 'a'",
 	}}
 	for _, tcase := range invalidSQL {
 		if tcase.output == "" {
@@ -4,6 +4,8 @@ package sqlparser
 import __yyfmt__ "fmt"
 
 //line sql.y:6
+import "strings"
+
 func setParseTree(yylex interface{}, stmt Statement) {
 	yylex.(*Tokenizer).ParseTree = stmt
 }
@@ -28,7 +30,13 @@ func forceEOF(yylex interface{}) {
 	yylex.(*Tokenizer).ForceEOF = true
 }
 
-//line sql.y:34
+// setDDL records the DDL built from the beginning of a statement.
+// Parse returns it if the rest of the statement can't be parsed.
+func setDDL(yylex interface{}, ddl *DDL) {
+	yylex.(*Tokenizer).partialDDL = ddl
+}
+
+//line sql.y:42
 type yySymType struct {
 	yys         int
 	empty       struct{}
@@ -66,6 +74,18 @@ type yySymType struct {
 	colIdent    ColIdent
 	colIdents   []ColIdent
 	tableIdent  TableIdent
+	strs        []string
+	ddl         *DDL
+	tableSpec   *TableSpec
+	columnDef   *ColumnDefinition
+	columnType  *ColumnType
+	indexDef    *IndexDefinition
+	indexCols   []*IndexColumn
+	indexCol    *IndexColumn
+	tableOpts   []*TableOption
+	tableOpt    *TableOption
+	alterSpecs  []*AlterSpec
+	alterSpec   *AlterSpec
 }
 
 const LEX_ERROR = 57346
@@ -155,7 +175,27 @@ const USING = 57429
 const SHOW = 57430
 const DESCRIBE = 57431
 const EXPLAIN = 57432
-const UNUSED = 57433
+const RELEASE = 57433
+const ADD = 57434
+const CHANGE = 57435
+const COLUMN = 57436
+const PRIMARY = 57437
+const FULLTEXT = 57438
+const SPATIAL = 57439
+const UNSIGNED = 57440
+const ZEROFILL = 57441
+const CHARACTER = 57442
+const COLLATE = 57443
+const CURRENT_TIMESTAMP = 57444
+const DATA_TYPE = 57445
+const AFTER = 57446
+const AUTO_INCREMENT = 57447
+const COMMENT_KEYWORD = 57448
+const FIRST = 57449
+const MODIFY = 57450
+const ROLLBACK = 57451
+const SAVEPOINT = 57452
+const UNUSED = 57453
 
 var yyToknames = [...]string{
 	"$end",
@@ -264,6 +304,26 @@ var yyToknames = [...]string{
 	"SHOW",
 	"DESCRIBE",
 	"EXPLAIN",
+	"RELEASE",
+	"ADD",
+	"CHANGE",
+	"COLUMN",
+	"PRIMARY",
+	"FULLTEXT",
+	"SPATIAL",
+	"UNSIGNED",
+	"ZEROFILL",
+	"CHARACTER",
+	"COLLATE",
+	"CURRENT_TIMESTAMP",
+	"DATA_TYPE",
+	"AFTER",
+	"AUTO_INCREMENT",
+	"COMMENT_KEYWORD",
+	"FIRST",
+	"MODIFY",
+	"ROLLBACK",
+	"SAVEPOINT",
 	"UNUSED",
 }
 var yyStatenames = [...]string{}
@@ -277,303 +337,432 @@ var yyExca = [...]int{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 106,
-	45, 221,
-	89, 221,
-	-2, 220,
+	-1, 208,
+	45, 320,
+	89, 320,
+	-2, 316,
+	-1, 209,
+	45, 321,
+	89, 321,
+	-2, 317,
 }
 
-const yyNprod = 225
+const yyNprod = 332
 const yyPrivate = 57344
 
 var yyTokenNames []string
 var yyStates []string
 
-const yyLast = 813
+const yyLast = 1273
 
 var yyAct = [...]int{
 
-	127, 192, 283, 233, 62, 112, 211, 223, 195, 324,
-	291, 400, 334, 222, 274, 245, 221, 136, 239, 194,
-	3, 145, 74, 225, 99, 67, 35, 100, 37, 40,
-	70, 41, 38, 41, 220, 64, 94, 383, 69, 382,
-	47, 71, 381, 68, 151, 46, 42, 50, 363, 128,
-	352, 354, 14, 306, 122, 80, 58, 149, 104, 43,
-	44, 45, 84, 176, 413, 122, 48, 49, 174, 182,
-	183, 177, 178, 179, 180, 181, 176, 98, 153, 63,
-	105, 275, 166, 322, 64, 85, 106, 64, 134, 139,
-	164, 122, 87, 275, 106, 123, 124, 125, 214, 89,
-	126, 81, 122, 160, 166, 60, 133, 129, 353, 162,
-	91, 196, 93, 165, 164, 197, 198, 199, 200, 365,
-	230, 65, 148, 150, 147, 302, 113, 114, 166, 142,
-	161, 205, 115, 122, 116, 409, 236, 152, 60, 156,
-	248, 106, 252, 215, 165, 164, 217, 117, 179, 180,
-	181, 176, 210, 60, 206, 250, 251, 249, 65, 166,
-	105, 90, 229, 231, 28, 105, 138, 191, 193, 244,
-	75, 202, 253, 254, 255, 86, 257, 258, 259, 260,
-	261, 262, 263, 264, 265, 266, 228, 218, 234, 309,
-	310, 311, 256, 325, 236, 86, 213, 267, 268, 270,
-	157, 271, 269, 105, 165, 164, 236, 226, 14, 64,
-	281, 141, 122, 279, 137, 231, 137, 282, 247, 166,
-	240, 242, 243, 237, 238, 241, 272, 278, 182, 183,
-	177, 178, 179, 180, 181, 176, 158, 105, 307, 288,
-	269, 236, 158, 236, 305, 289, 236, 122, 215, 289,
-	60, 86, 314, 315, 316, 312, 330, 236, 370, 367,
-	308, 208, 73, 377, 325, 313, 293, 296, 297, 298,
-	294, 318, 295, 299, 216, 97, 319, 226, 122, 349,
-	380, 297, 298, 327, 329, 331, 332, 335, 321, 328,
-	109, 177, 178, 179, 180, 181, 176, 347, 340, 247,
-	342, 345, 348, 339, 79, 341, 346, 358, 76, 357,
-	350, 39, 359, 379, 344, 163, 360, 343, 406, 362,
-	109, 109, 55, 369, 364, 83, 14, 336, 386, 201,
-	407, 368, 366, 203, 323, 54, 82, 143, 96, 226,
-	226, 226, 226, 57, 304, 209, 51, 52, 284, 109,
-	277, 376, 163, 175, 174, 182, 183, 177, 178, 179,
-	180, 181, 176, 384, 285, 212, 375, 385, 338, 227,
-	109, 388, 335, 387, 137, 109, 109, 109, 215, 132,
-	246, 390, 61, 389, 140, 392, 131, 412, 398, 14,
-	28, 30, 1, 399, 303, 300, 401, 401, 401, 64,
-	159, 144, 36, 404, 408, 219, 410, 411, 402, 403,
-	414, 146, 66, 109, 415, 130, 416, 59, 293, 296,
-	297, 298, 294, 280, 295, 299, 29, 72, 378, 207,
-	405, 77, 371, 333, 391, 374, 393, 394, 337, 227,
-	320, 204, 31, 32, 33, 34, 59, 109, 273, 118,
-	111, 88, 326, 110, 276, 92, 167, 107, 95, 351,
-	292, 246, 290, 103, 224, 102, 78, 59, 53, 135,
-	27, 56, 13, 14, 15, 16, 17, 372, 373, 154,
-	12, 11, 155, 10, 9, 8, 7, 109, 235, 6,
-	121, 5, 4, 2, 0, 18, 0, 0, 0, 0,
-	0, 227, 227, 227, 227, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 122, 59, 236, 106, 123, 124,
-	125, 0, 0, 126, 119, 120, 0, 0, 108, 0,
-	129, 175, 174, 182, 183, 177, 178, 179, 180, 181,
-	176, 0, 59, 103, 0, 0, 0, 232, 103, 113,
-	114, 101, 0, 0, 0, 115, 361, 116, 19, 20,
-	22, 21, 23, 0, 0, 0, 0, 0, 0, 0,
-	117, 24, 25, 26, 175, 174, 182, 183, 177, 178,
-	179, 180, 181, 176, 0, 0, 103, 109, 0, 109,
-	109, 0, 0, 395, 396, 397, 0, 0, 121, 232,
-	0, 286, 0, 0, 287, 0, 0, 0, 0, 0,
-	301, 0, 59, 0, 0, 0, 0, 0, 0, 0,
-	103, 0, 122, 0, 317, 106, 123, 124, 125, 0,
-	0, 126, 119, 120, 0, 0, 108, 0, 129, 0,
-	0, 14, 175, 174, 182, 183, 177, 178, 179, 180,
-	181, 176, 0, 0, 0, 0, 121, 113, 114, 101,
-	0, 0, 0, 115, 0, 116, 0, 0, 0, 0,
-	0, 0, 0, 121, 59, 59, 59, 59, 117, 0,
-	122, 0, 0, 106, 123, 124, 125, 355, 356, 126,
-	119, 120, 0, 0, 108, 0, 129, 122, 0, 0,
-	106, 123, 124, 125, 0, 0, 126, 119, 120, 0,
-	0, 108, 0, 129, 65, 113, 114, 0, 0, 0,
-	0, 115, 0, 116, 0, 122, 0, 0, 106, 123,
-	124, 125, 113, 114, 126, 0, 117, 0, 115, 0,
-	116, 129, 175, 174, 182, 183, 177, 178, 179, 180,
-	181, 176, 0, 117, 0, 0, 0, 0, 0, 0,
-	113, 114, 0, 0, 0, 0, 115, 0, 116, 0,
-	0, 0, 0, 0, 0, 0, 169, 172, 0, 0,
-	0, 117, 184, 185, 186, 187, 188, 189, 190, 173,
-	170, 171, 168, 175, 174, 182, 183, 177, 178, 179,
-	180, 181, 176, 175, 174, 182, 183, 177, 178, 179,
-	180, 181, 176,
+	209, 441, 100, 206, 391, 581, 324, 505, 381, 340,
+	380, 268, 265, 215, 462, 495, 432, 403, 379, 239,
+	201, 266, 355, 397, 360, 247, 350, 323, 3, 187,
+	105, 77, 202, 170, 101, 383, 276, 231, 104, 98,
+	86, 77, 159, 77, 278, 77, 72, 277, 356, 357,
+	361, 165, 51, 359, 188, 190, 191, 124, 269, 140,
+	118, 87, 528, 530, 64, 77, 66, 66, 98, 195,
+	77, 163, 98, 248, 95, 73, 74, 121, 85, 133,
+	358, 562, 45, 123, 44, 561, 77, 77, 46, 114,
+	560, 110, 77, 115, 15, 225, 57, 137, 47, 48,
+	98, 71, 539, 208, 67, 168, 68, 69, 70, 305,
+	477, 77, 595, 225, 153, 295, 77, 361, 98, 281,
+	529, 98, 348, 77, 139, 189, 77, 77, 433, 77,
+	77, 77, 433, 225, 493, 154, 97, 388, 98, 343,
+	98, 156, 164, 77, 293, 126, 77, 134, 135, 131,
+	354, 177, 98, 77, 98, 237, 77, 291, 295, 77,
+	113, 180, 150, 175, 76, 352, 510, 77, 185, 77,
+	63, 509, 178, 77, 77, 78, 79, 80, 81, 82,
+	83, 84, 271, 193, 282, 77, 274, 236, 230, 250,
+	241, 58, 114, 57, 456, 142, 98, 249, 447, 98,
+	365, 169, 270, 169, 169, 77, 272, 273, 78, 79,
+	80, 81, 82, 83, 84, 320, 322, 172, 173, 75,
+	251, 225, 280, 279, 308, 309, 310, 305, 406, 102,
+	551, 106, 550, 116, 98, 353, 78, 79, 80, 81,
+	82, 83, 84, 511, 342, 155, 285, 306, 307, 308,
+	309, 310, 305, 136, 368, 366, 77, 339, 141, 61,
+	62, 427, 331, 113, 59, 60, 294, 293, 546, 335,
+	77, 410, 345, 543, 144, 146, 124, 127, 337, 77,
+	149, 295, 373, 34, 408, 409, 407, 98, 294, 293,
+	507, 77, 119, 508, 541, 225, 394, 395, 396, 171,
+	225, 63, 321, 295, 174, 552, 294, 293, 480, 481,
+	482, 106, 286, 392, 181, 182, 376, 184, 106, 186,
+	386, 295, 58, 556, 384, 394, 414, 425, 426, 428,
+	77, 199, 114, 448, 200, 405, 591, 394, 77, 77,
+	437, 102, 371, 244, 102, 370, 579, 106, 287, 440,
+	77, 430, 264, 166, 76, 267, 42, 171, 398, 400,
+	401, 171, 171, 399, 436, 496, 56, 53, 50, 374,
+	114, 77, 444, 106, 198, 98, 446, 445, 427, 394,
+	523, 454, 52, 54, 98, 524, 98, 449, 287, 394,
+	61, 62, 455, 289, 559, 59, 60, 478, 55, 459,
+	458, 460, 394, 113, 476, 501, 394, 148, 109, 453,
+	452, 483, 451, 450, 479, 370, 369, 108, 111, 112,
+	158, 157, 496, 384, 155, 484, 78, 79, 80, 81,
+	82, 83, 84, 225, 240, 490, 97, 494, 122, 65,
+	473, 113, 502, 500, 351, 405, 109, 240, 492, 498,
+	207, 558, 520, 499, 124, 108, 111, 112, 372, 242,
+	521, 98, 98, 98, 98, 522, 519, 375, 97, 460,
+	516, 515, 518, 517, 98, 98, 349, 94, 77, 387,
+	389, 533, 155, 92, 535, 526, 192, 525, 536, 468,
+	469, 212, 125, 128, 129, 130, 91, 152, 384, 384,
+	384, 384, 77, 132, 15, 588, 378, 377, 78, 79,
+	80, 81, 82, 83, 84, 325, 565, 589, 429, 326,
+	327, 328, 329, 290, 151, 364, 102, 439, 435, 275,
+	363, 183, 389, 167, 542, 334, 197, 475, 351, 442,
+	78, 79, 80, 81, 82, 83, 84, 344, 555, 292,
+	443, 76, 341, 77, 568, 212, 212, 566, 571, 267,
+	573, 574, 572, 570, 330, 569, 88, 89, 332, 554,
+	76, 514, 580, 240, 99, 594, 77, 77, 77, 77,
+	338, 585, 583, 584, 212, 578, 590, 292, 592, 593,
+	207, 586, 77, 362, 235, 207, 77, 15, 77, 402,
+	35, 234, 411, 412, 413, 34, 415, 416, 417, 418,
+	419, 420, 421, 422, 423, 424, 36, 37, 38, 39,
+	40, 243, 512, 78, 79, 80, 81, 82, 83, 84,
+	385, 212, 1, 207, 474, 49, 212, 212, 212, 246,
+	245, 404, 78, 79, 80, 81, 82, 83, 84, 107,
+	76, 303, 311, 312, 306, 307, 308, 309, 310, 305,
+	96, 367, 162, 161, 160, 103, 534, 545, 41, 464,
+	467, 468, 469, 465, 212, 466, 470, 22, 304, 303,
+	311, 312, 306, 307, 308, 309, 310, 305, 20, 138,
+	544, 471, 288, 143, 43, 347, 207, 304, 303, 311,
+	312, 306, 307, 308, 309, 310, 305, 344, 120, 117,
+	233, 485, 486, 487, 438, 336, 587, 547, 504, 63,
+	553, 96, 78, 79, 80, 81, 82, 83, 84, 385,
+	489, 513, 491, 333, 431, 221, 214, 212, 346, 176,
+	58, 267, 179, 497, 213, 503, 506, 434, 296, 97,
+	210, 404, 527, 463, 461, 15, 382, 204, 147, 194,
+	90, 196, 33, 93, 582, 582, 582, 102, 14, 205,
+	13, 12, 11, 96, 10, 238, 9, 212, 8, 7,
+	596, 6, 5, 4, 597, 2, 598, 208, 0, 0,
+	0, 538, 0, 0, 225, 0, 540, 208, 226, 227,
+	228, 0, 0, 229, 385, 385, 385, 385, 61, 62,
+	232, 0, 0, 59, 60, 0, 0, 283, 0, 0,
+	284, 78, 79, 80, 81, 82, 83, 84, 0, 216,
+	217, 0, 0, 0, 0, 218, 0, 219, 0, 0,
+	563, 0, 0, 0, 564, 0, 0, 0, 567, 506,
+	220, 0, 0, 0, 393, 96, 224, 76, 344, 78,
+	79, 80, 81, 82, 83, 84, 0, 0, 0, 78,
+	79, 80, 81, 82, 83, 84, 0, 0, 0, 0,
+	225, 0, 394, 208, 226, 227, 228, 0, 0, 229,
+	222, 223, 0, 0, 211, 0, 232, 0, 212, 0,
+	212, 212, 0, 0, 575, 576, 577, 0, 96, 205,
+	0, 0, 0, 390, 205, 216, 217, 203, 0, 0,
+	0, 218, 0, 219, 0, 0, 0, 63, 0, 78,
+	79, 80, 81, 82, 83, 145, 220, 0, 0, 224,
+	311, 312, 306, 307, 308, 309, 310, 305, 58, 0,
+	0, 0, 205, 0, 0, 78, 79, 80, 81, 82,
+	83, 84, 537, 225, 0, 390, 208, 226, 227, 228,
+	0, 0, 229, 222, 223, 0, 0, 211, 0, 232,
+	304, 303, 311, 312, 306, 307, 308, 309, 310, 305,
+	0, 0, 56, 53, 0, 0, 457, 0, 216, 217,
+	203, 0, 0, 0, 218, 472, 219, 96, 52, 54,
+	488, 0, 15, 0, 0, 205, 61, 62, 0, 220,
+	0, 59, 60, 0, 55, 0, 0, 224, 304, 303,
+	311, 312, 306, 307, 308, 309, 310, 305, 78, 79,
+	80, 81, 82, 83, 84, 0, 0, 0, 0, 258,
+	0, 225, 0, 0, 208, 226, 227, 228, 0, 0,
+	229, 222, 223, 0, 0, 211, 259, 232, 0, 0,
+	0, 0, 0, 0, 0, 224, 256, 0, 0, 0,
+	0, 257, 96, 96, 96, 96, 216, 217, 0, 0,
+	0, 0, 218, 0, 219, 531, 532, 0, 0, 225,
+	0, 0, 208, 226, 227, 228, 0, 220, 229, 222,
+	223, 0, 0, 211, 0, 232, 0, 0, 0, 15,
+	16, 17, 18, 0, 262, 0, 78, 79, 80, 81,
+	82, 83, 84, 261, 216, 217, 252, 253, 254, 255,
+	218, 19, 219, 260, 263, 0, 0, 225, 0, 0,
+	208, 226, 227, 228, 0, 220, 229, 0, 0, 0,
+	0, 0, 0, 232, 304, 303, 311, 312, 306, 307,
+	308, 309, 310, 305, 78, 79, 80, 81, 82, 83,
+	84, 0, 216, 217, 0, 0, 0, 0, 218, 0,
+	219, 464, 467, 468, 469, 465, 0, 466, 470, 0,
+	0, 557, 0, 220, 21, 23, 25, 24, 26, 548,
+	549, 0, 0, 0, 0, 0, 0, 27, 28, 29,
+	32, 0, 78, 79, 80, 81, 82, 83, 84, 0,
+	0, 0, 0, 0, 298, 301, 0, 0, 31, 30,
+	313, 314, 315, 316, 317, 318, 319, 302, 299, 300,
+	297, 304, 303, 311, 312, 306, 307, 308, 309, 310,
+	305, 0, 0, 304, 303, 311, 312, 306, 307, 308,
+	309, 310, 305,
 }
 var yyPact = [...]int{
 
-	467, -1000, -1000, 385, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -70,
-	-69, -50, -37, -51, -1000, -1000, -1000, 383, 328, 303,
-	-1000, -67, 90, 372, 73, -76, -54, 73, -1000, -66,
-	73, -1000, 90, -79, 122, -79, 90, -1000, -1000, -1000,
-	-1000, -1000, -1000, 269, 73, -1000, 48, 312, 297, -27,
-	-1000, 90, 129, -1000, 27, -1000, 90, 40, 113, -1000,
-	90, -1000, -63, 90, 317, 231, 73, -1000, 577, -1000,
-	369, -1000, 90, 73, 90, 363, 73, 680, -1000, 316,
-	-82, -1000, 30, -1000, 90, -1000, -1000, 90, -1000, 190,
-	-1000, -1000, 110, 20, 87, 717, -1000, -1000, 652, 635,
-	-1000, -1000, -1000, 680, 680, 680, 680, 167, -1000, -1000,
-	-1000, 167, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 680,
-	90, -1000, -1000, 233, 205, -1000, 351, 652, -1000, 727,
-	9, 46, -1000, -1000, 230, 73, -1000, -65, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 57, 577, -1000,
-	-1000, 73, 38, 469, 652, 652, 166, 680, 88, 82,
-	680, 680, 680, 166, 680, 680, 680, 680, 680, 680,
-	680, 680, 680, 680, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 10, 717, 147, 159, 194, 717, -1000, -1000, -1000,
-	666, 577, -1000, 383, 31, 727, -1000, 320, 73, 73,
-	351, 332, 349, 87, 93, 727, 90, -1000, -1000, 90,
-	-1000, 203, 232, -1000, -1000, 105, 324, 202, -1000, -1000,
-	-1000, -1000, -36, -1000, 196, 577, -1000, 10, 32, -1000,
-	-1000, 135, -1000, -1000, 727, -1000, 46, -1000, -1000, 88,
-	680, 680, 680, 727, 727, 566, -1000, 150, -9, -1000,
-	66, 66, -22, -22, -22, 211, 211, -1000, -1000, 680,
-	-1000, -1000, 196, 19, -1000, 652, 220, 167, 385, 149,
-	210, -1000, 332, -1000, 680, 680, -1000, -1000, 356, 57,
-	57, 57, 57, -1000, 283, 280, -1000, 267, 263, 245,
-	8, -1000, 90, 90, -1000, 199, 73, -1000, 196, -1000,
-	-1000, -1000, 194, -1000, 727, 727, 498, 680, 727, -1000,
-	-42, -1000, 680, 56, -1000, 307, 213, -1000, -1000, -1000,
-	73, -1000, 277, 212, -1000, 455, -1000, 353, 336, 232,
-	219, 384, -1000, -1000, -1000, -1000, 279, -1000, 246, -1000,
-	-1000, -1000, -55, -58, -60, -1000, -1000, -1000, -1000, -1000,
-	-1000, 680, 727, -1000, 727, 680, 302, 167, -1000, 680,
-	680, -1000, -1000, -1000, 351, 652, 680, 652, 652, -1000,
-	-1000, 167, 167, 167, 727, 727, 380, -1000, 727, -1000,
-	332, 87, 156, 87, 87, 73, 73, 73, 73, 301,
-	89, -1000, 89, 89, 129, -1000, 379, -11, -1000, 73,
-	-1000, -1000, -1000, 73, -1000, 73, -1000,
+	1113, -1000, -1000, 600, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	311, -14, 274, -34, 8, 10, 5, -1000, -1000, -1000,
+	522, -21, -86, 591, 548, 464, -1000, -33, 701, 564,
+	522, -1000, 306, -4, 522, -41, -1000, -1000, -1000, 246,
+	-22, -1000, 344, 166, -53, -53, -53, 84, -1000, -1000,
+	-1000, 475, -1000, 31, 522, 1, -1000, 701, -42, 522,
+	-42, 701, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 809, 522, -1000, -1000, -1000,
+	372, 522, -1000, 109, 500, 469, 25, -1000, -1000, 701,
+	199, -1000, 76, 374, -1000, -80, 23, 308, 507, 63,
+	522, 63, 63, -1000, -1000, 522, -1000, 701, 92, 900,
+	701, -1000, 522, -80, -1000, 522, 522, 505, 522, 522,
+	522, 6, -1000, -1000, 458, -1000, -1000, 701, -30, 701,
+	515, 330, 522, -1000, -1000, 522, -1000, 918, -1000, 584,
+	-1000, 701, 522, 701, 562, 522, 1102, 143, 306, 171,
+	1022, -1000, 307, -1000, -1000, -1000, 522, -45, 522, -1000,
+	-45, -1000, 522, 522, -45, -1000, -1000, 508, -1000, -1000,
+	-76, -1000, -1000, -1000, 522, -76, 91, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 701, -1000, -1000, 701, -1000,
+	-1000, 302, -1000, -1000, 503, 68, 209, 1175, -1000, -1000,
+	-1000, 1054, 1006, -1000, -1000, -1000, 1102, 1102, 1102, 1102,
+	255, -1000, -1000, -1000, 255, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 1102, 701, -1000, -1000, 250, 436, -1000, 538,
+	1054, -1000, 1088, 50, 749, -1000, 692, -1000, 57, -1000,
+	-80, -1000, -1000, -1000, 448, 116, -1000, 96, -1, 585,
+	-1000, 504, 499, 151, 205, 369, -1000, 297, -1000, 522,
+	-45, -1000, -1000, -1000, 325, -1000, -1000, -1000, 522, -76,
+	-1000, 480, 479, -1000, -1000, -1000, 388, 918, -1000, -1000,
+	522, 55, 835, 1054, 1054, 304, 1102, 176, 211, 1102,
+	1102, 1102, 304, 1102, 1102, 1102, 1102, 1102, 1102, 1102,
+	1102, 1102, 1102, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	43, 1175, 249, 278, 332, 1175, -1000, -1000, -1000, 602,
+	918, -1000, 591, 66, 1088, -1000, 498, 522, 522, 538,
+	523, 535, 209, 739, 1088, -1000, 143, 6, -1000, 116,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 148, -1000,
+	-1000, 288, -68, -1000, -1000, -1000, 366, 363, -1000, -45,
+	522, 144, -1000, -1000, 701, -1000, -1000, -1, -1000, 423,
+	635, -1000, -1000, 420, 517, 88, -1000, -1000, -1000, -1000,
+	21, -1000, 342, 918, -1000, 43, 86, -1000, -1000, 254,
+	-1000, -1000, 1088, -1000, 749, -1000, -1000, 176, 1102, 1102,
+	1102, 1088, 1088, 952, -1000, 862, 574, -1000, 142, 142,
+	24, 24, 24, 167, 167, -1000, -1000, 1102, -1000, -1000,
+	342, 70, -1000, 1054, 321, 255, 600, 378, 359, -1000,
+	523, -1000, 1102, 1102, -1000, -1000, -1000, -1000, 243, -1000,
+	-1000, 121, -1000, 117, -1000, -1000, 196, -1000, -1000, 559,
+	388, 388, 388, 388, -1000, 432, 418, -1000, 426, 346,
+	453, 20, -1000, 701, 701, -1000, 355, 522, -1000, 342,
+	-1000, -1000, -1000, 332, -1000, 1088, 1088, 904, 1102, 1088,
+	-1000, 12, -1000, 1102, 231, -1000, 509, 227, -1000, -1000,
+	-1000, 522, -1000, 621, 222, -1000, 1187, -1000, 185, 183,
+	-1000, -1000, 260, 556, 533, 635, 279, 1157, -1000, -1000,
+	-1000, -1000, 417, -1000, 360, -1000, -1000, -1000, -7, -12,
+	-16, -1000, -1000, -1000, -1000, -1000, -1000, 1102, 1088, -1000,
+	1088, 1102, 490, 255, -1000, 1102, 1102, -1000, -1000, -1000,
+	-1000, -1000, 522, 538, 1054, 1102, 1054, 1054, -1000, -1000,
+	255, 255, 255, 1088, 1088, 577, -1000, 1088, -1000, 299,
+	523, 209, 215, 209, 209, 522, 522, 522, 522, -45,
+	488, 290, -1000, 290, 290, 199, -1000, -1000, 567, 37,
+	-1000, 522, -1000, -1000, -1000, 522, -1000, 522, -1000,
 }
 var yyPgo = [...]int{
 
-	0, 493, 19, 492, 491, 489, 486, 485, 484, 483,
-	481, 480, 472, 426, 471, 470, 468, 466, 24, 27,
-	465, 16, 13, 7, 464, 462, 10, 460, 23, 459,
-	11, 17, 58, 457, 456, 454, 453, 1, 18, 15,
-	8, 452, 5, 49, 450, 449, 448, 14, 441, 440,
-	438, 435, 6, 433, 12, 432, 2, 430, 429, 423,
-	9, 4, 79, 415, 311, 262, 412, 411, 405, 402,
-	401, 0, 400, 384, 395, 394, 40, 392, 391, 211,
-	3,
+	0, 785, 27, 783, 782, 781, 779, 778, 776, 774,
+	772, 771, 770, 768, 600, 763, 762, 760, 758, 20,
+	32, 757, 18, 10, 8, 756, 754, 14, 753, 35,
+	752, 5, 19, 3, 750, 748, 747, 744, 302, 23,
+	17, 6, 743, 13, 37, 736, 735, 734, 16, 733,
+	732, 731, 720, 9, 718, 7, 717, 1, 716, 715,
+	714, 15, 2, 34, 710, 439, 124, 709, 708, 438,
+	91, 105, 695, 694, 11, 188, 33, 692, 621, 691,
+	0, 688, 677, 668, 665, 38, 664, 663, 662, 26,
+	73, 661, 22, 24, 30, 649, 12, 21, 640, 639,
+	25, 29, 635, 52, 36, 634, 46, 632, 622, 616,
+	343, 4,
 }
 var yyR1 = [...]int{
 
-	0, 77, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 2, 2, 2, 3, 3, 4, 5,
-	6, 7, 7, 7, 8, 8, 8, 9, 10, 10,
-	10, 11, 12, 12, 12, 78, 13, 14, 14, 15,
-	15, 15, 16, 16, 17, 17, 18, 18, 19, 19,
-	19, 20, 20, 72, 72, 72, 21, 21, 22, 22,
-	23, 23, 23, 24, 24, 24, 24, 75, 75, 74,
-	74, 74, 25, 25, 25, 25, 26, 26, 26, 26,
-	27, 27, 28, 28, 29, 29, 29, 29, 30, 30,
-	31, 31, 32, 32, 32, 32, 32, 32, 33, 33,
-	33, 33, 33, 33, 33, 33, 33, 33, 33, 33,
-	33, 38, 38, 38, 38, 38, 38, 34, 34, 34,
-	34, 34, 34, 34, 39, 39, 39, 43, 40, 40,
-	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
-	37, 37, 37, 37, 37, 37, 37, 37, 37, 37,
-	37, 37, 45, 48, 48, 46, 46, 47, 49, 49,
-	44, 44, 44, 36, 36, 36, 36, 50, 50, 51,
-	51, 52, 52, 53, 53, 54, 55, 55, 55, 56,
-	56, 56, 57, 57, 57, 58, 58, 59, 59, 60,
-	60, 35, 35, 41, 41, 42, 42, 61, 61, 62,
-	63, 63, 65, 65, 66, 66, 64, 64, 67, 67,
-	67, 67, 67, 67, 68, 68, 69, 69, 70, 70,
-	71, 73, 79, 80, 76,
+	0, 107, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 2, 2, 2, 3, 3, 4,
+	5, 6, 7, 108, 7, 7, 8, 8, 8, 9,
+	10, 10, 10, 11, 81, 83, 84, 84, 84, 84,
+	85, 86, 86, 86, 86, 86, 86, 86, 86, 86,
+	86, 86, 86, 86, 86, 87, 87, 87, 87, 88,
+	88, 88, 91, 91, 89, 89, 89, 92, 92, 92,
+	92, 92, 93, 93, 93, 94, 94, 95, 95, 95,
+	95, 95, 96, 96, 97, 97, 98, 98, 99, 99,
+	99, 100, 90, 90, 90, 90, 90, 90, 90, 90,
+	101, 101, 101, 101, 82, 102, 102, 103, 103, 103,
+	103, 103, 103, 103, 103, 103, 103, 104, 104, 104,
+	12, 12, 12, 13, 13, 13, 13, 109, 14, 15,
+	15, 16, 16, 16, 17, 17, 18, 18, 19, 19,
+	20, 20, 20, 21, 21, 77, 77, 77, 22, 22,
+	23, 23, 24, 24, 24, 25, 25, 25, 25, 105,
+	105, 79, 79, 79, 26, 26, 26, 26, 27, 27,
+	27, 27, 28, 28, 29, 29, 30, 30, 30, 30,
+	31, 31, 32, 32, 33, 33, 33, 33, 33, 33,
+	34, 34, 34, 34, 34, 34, 34, 34, 34, 34,
+	34, 34, 34, 39, 39, 39, 39, 39, 39, 35,
+	35, 35, 35, 35, 35, 35, 40, 40, 40, 44,
+	41, 41, 38, 38, 38, 38, 38, 38, 38, 38,
+	38, 38, 38, 38, 38, 38, 38, 38, 38, 38,
+	38, 38, 38, 38, 46, 49, 49, 47, 47, 48,
+	50, 50, 45, 45, 45, 37, 37, 37, 37, 51,
+	51, 52, 52, 53, 53, 54, 54, 55, 56, 56,
+	56, 57, 57, 57, 58, 58, 58, 59, 59, 60,
+	60, 61, 61, 36, 36, 42, 42, 43, 43, 62,
+	62, 63, 64, 64, 66, 66, 67, 67, 65, 65,
+	68, 68, 73, 73, 73, 73, 74, 74, 69, 69,
+	70, 70, 71, 71, 72, 72, 75, 75, 76, 76,
+	78, 78, 80, 80, 80, 80, 80, 80, 80, 110,
+	111, 106,
 }
 var yyR2 = [...]int{
 
 	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 13, 6, 3, 8, 8, 8, 7,
-	3, 5, 8, 4, 6, 7, 4, 5, 4, 5,
-	5, 3, 2, 2, 2, 0, 2, 0, 2, 1,
-	2, 2, 0, 1, 0, 1, 1, 3, 1, 2,
-	3, 1, 1, 0, 1, 2, 1, 3, 1, 1,
-	3, 3, 3, 3, 5, 5, 3, 0, 1, 0,
-	1, 2, 1, 2, 2, 1, 2, 3, 2, 3,
-	2, 2, 1, 3, 0, 5, 5, 5, 1, 3,
-	0, 2, 1, 3, 3, 2, 3, 3, 1, 1,
-	3, 3, 4, 3, 4, 3, 4, 5, 6, 3,
-	2, 1, 2, 1, 2, 1, 2, 1, 1, 1,
-	1, 1, 1, 1, 3, 1, 1, 3, 1, 3,
-	1, 1, 1, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 2, 2, 2, 3, 3, 4, 5,
-	4, 1, 5, 0, 1, 1, 2, 4, 0, 2,
-	1, 3, 5, 1, 1, 1, 1, 0, 3, 0,
-	2, 0, 3, 1, 3, 2, 0, 1, 1, 0,
-	2, 4, 0, 2, 4, 0, 3, 1, 3, 0,
-	5, 2, 1, 1, 3, 3, 1, 1, 3, 3,
-	1, 1, 0, 2, 0, 3, 0, 1, 1, 1,
-	1, 1, 1, 1, 0, 1, 0, 1, 0, 2,
-	1, 1, 1, 1, 0,
+	1, 1, 1, 1, 13, 6, 3, 8, 8, 8,
+	7, 3, 2, 0, 12, 4, 2, 4, 4, 5,
+	4, 5, 5, 3, 4, 4, 1, 1, 3, 3,
+	2, 1, 2, 2, 4, 3, 2, 3, 3, 4,
+	2, 3, 2, 3, 3, 1, 4, 6, 4, 1,
+	1, 1, 1, 3, 1, 1, 1, 1, 1, 2,
+	1, 1, 1, 3, 4, 5, 3, 3, 4, 3,
+	3, 3, 1, 3, 1, 4, 0, 1, 1, 2,
+	3, 3, 1, 1, 1, 2, 1, 2, 3, 2,
+	1, 1, 1, 1, 4, 1, 3, 4, 2, 3,
+	3, 3, 5, 4, 6, 5, 3, 0, 1, 2,
+	2, 2, 2, 2, 3, 4, 3, 0, 2, 0,
+	2, 1, 2, 2, 0, 1, 0, 1, 1, 3,
+	1, 2, 3, 1, 1, 0, 1, 2, 1, 3,
+	1, 1, 3, 3, 3, 3, 5, 5, 3, 0,
+	1, 0, 1, 2, 1, 2, 2, 1, 2, 3,
+	2, 3, 2, 2, 1, 3, 0, 5, 5, 5,
+	1, 3, 0, 2, 1, 3, 3, 2, 3, 3,
+	1, 1, 3, 3, 4, 3, 4, 3, 4, 5,
+	6, 3, 2, 1, 2, 1, 2, 1, 2, 1,
+	1, 1, 1, 1, 1, 1, 3, 1, 1, 3,
+	1, 3, 1, 1, 1, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 2, 2, 2, 3, 3,
+	4, 5, 4, 1, 5, 0, 1, 1, 2, 4,
+	0, 2, 1, 3, 5, 1, 1, 1, 1, 0,
+	3, 0, 2, 0, 3, 1, 3, 2, 0, 1,
+	1, 0, 2, 4, 0, 2, 4, 0, 3, 1,
+	3, 0, 5, 2, 1, 1, 3, 3, 1, 1,
+	3, 3, 1, 1, 0, 2, 0, 3, 0, 1,
+	0, 1, 0, 1, 1, 1, 0, 2, 0, 1,
+	1, 1, 0, 1, 0, 1, 1, 1, 0, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 0,
 }
 var yyChk = [...]int{
 
-	-1000, -77, -1, -2, -3, -4, -5, -6, -7, -8,
-	-9, -10, -11, -12, 6, 7, 8, 9, 28, 91,
-	92, 94, 93, 95, 104, 105, 106, -15, 5, -13,
-	-78, -13, -13, -13, -13, 96, -69, 98, 102, -64,
-	98, 100, 96, 96, 97, 98, 96, -76, -76, -76,
-	-2, 18, 19, -16, 32, 19, -14, -64, -28, -73,
-	48, 10, -61, -62, -71, 48, -66, 101, 97, -71,
-	96, -71, -73, -65, 101, 48, -65, -73, -17, 35,
-	-71, 53, 24, 28, 89, -28, 46, 65, -73, 59,
-	48, -76, -73, -76, 99, -73, 21, 44, -71, -18,
-	-19, 82, -20, -73, -32, -37, 48, -33, 59, -79,
-	-36, -44, -42, 80, 81, 86, 88, 101, -45, 55,
-	56, 21, 45, 49, 50, 51, 54, -71, -43, 61,
-	-63, 17, 10, -28, -61, -73, -31, 11, -62, -37,
-	-73, -79, -76, 21, -70, 103, -67, 94, 92, 27,
-	93, 14, 107, 48, -73, -73, -76, 10, 46, -72,
-	-71, 20, 89, -79, 58, 57, 72, -34, 75, 59,
-	73, 74, 60, 72, 77, 76, 85, 80, 81, 82,
-	83, 84, 78, 79, 65, 66, 67, 68, 69, 70,
-	71, -32, -37, -32, -2, -40, -37, -37, -37, -37,
-	-37, -79, -43, -79, -48, -37, -28, -58, 28, -79,
-	-31, -52, 14, -32, 89, -37, 44, -71, -76, -68,
-	99, -21, -22, -23, -24, -28, -43, -79, -19, -71,
-	82, -71, -73, -80, -18, 19, 47, -32, -32, -38,
-	54, 59, 55, 56, -37, -39, -79, -43, 52, 75,
-	73, 74, 60, -37, -37, -37, -38, -37, -37, -37,
-	-37, -37, -37, -37, -37, -37, -37, -80, -80, 46,
-	-80, -71, -18, -46, -47, 62, -35, 30, -2, -61,
-	-59, -71, -52, -56, 16, 15, -73, -73, -31, 46,
-	-25, -26, -27, 34, 38, 40, 35, 36, 37, 41,
-	-74, -73, 20, -75, 20, -21, 89, -80, -18, 54,
-	55, 56, -40, -39, -37, -37, -37, 58, -37, -80,
-	-49, -47, 64, -32, -60, 44, -41, -42, -60, -80,
-	46, -56, -37, -53, -54, -37, -76, -50, 12, -22,
-	-23, -22, -23, 34, 34, 34, 39, 34, 39, 34,
-	-26, -29, 42, 100, 43, -73, -73, -80, -71, -80,
-	-80, 58, -37, 90, -37, 63, 25, 46, -71, 46,
-	46, -55, 22, 23, -51, 13, 15, 44, 44, 34,
-	34, 97, 97, 97, -37, -37, 26, -42, -37, -54,
-	-52, -32, -40, -32, -32, -79, -79, -79, 8, -56,
-	-30, -71, -30, -30, -61, -57, 17, 29, -80, 46,
-	-80, -80, 8, 75, -71, -71, -71,
+	-1000, -107, -1, -2, -3, -4, -5, -6, -7, -8,
+	-9, -10, -11, -12, -13, 6, 7, 8, 9, 28,
+	-81, 91, -82, 92, 94, 93, 95, 104, 105, 106,
+	126, 125, 107, -16, 5, -14, -109, -14, -14, -14,
+	-14, -83, 45, -73, 98, 96, 102, 112, 113, -102,
+	94, -103, 108, 93, 109, 124, 92, -90, 48, 121,
+	122, 116, 117, 27, 98, -65, 100, 96, 96, 97,
+	98, 96, -106, -106, -106, -75, 48, -80, 120, 121,
+	122, 123, 124, 125, 126, 99, 126, -2, 18, 19,
+	-17, 32, 19, -15, -65, -29, -78, 48, -80, 10,
+	-62, -63, -75, -84, -85, -94, -75, -95, 111, 102,
+	-70, 112, 113, 97, 26, 97, -75, -67, 101, 46,
+	-68, 99, -69, -94, 110, -69, -70, 111, -69, -69,
+	-69, 65, 28, 48, 116, 117, -75, 96, -78, -66,
+	101, -75, -66, -78, -75, 126, -75, -18, 35, -75,
+	53, 24, 28, 89, -29, 46, 65, 47, 46, 122,
+	-86, -87, -88, 48, 119, 28, 45, 26, -71, -70,
+	-76, -75, -71, -71, -75, -106, -78, 59, -103, -78,
+	-85, -75, -75, 26, -75, -85, -75, -101, 48, 119,
+	49, 50, 28, -106, -78, 99, -78, 21, 44, -75,
+	-75, -19, -20, 82, -21, -78, -33, -38, 48, -80,
+	-34, 59, -110, -37, -45, -43, 80, 81, 86, 88,
+	101, -46, 55, 56, 21, 45, 49, 50, 51, 54,
+	-75, -44, 61, -64, 17, 10, -29, -62, -78, -32,
+	11, -63, -38, -78, -110, -98, -99, -100, -90, -85,
+	-94, 49, 114, 115, 116, 117, 54, 59, 27, 44,
+	121, 111, 102, 122, 45, -96, -97, -75, -74, 103,
+	-76, -74, -76, -76, -74, 21, -104, 123, 120, -85,
+	-104, 28, 93, -78, -78, -106, 10, 46, -77, -75,
+	20, 89, -110, 58, 57, 72, -35, 75, 59, 73,
+	74, 60, 72, 77, 76, 85, 80, 81, 82, 83,
+	84, 78, 79, 65, 66, 67, 68, 69, 70, 71,
+	-33, -38, -33, -2, -41, -38, -38, -38, -38, -38,
+	-110, -44, -110, -49, -38, -29, -59, 28, -110, -32,
+	-53, 14, -33, 89, -38, -100, 46, -72, 65, 28,
+	-89, -75, 49, 119, 54, -92, 49, 50, 81, 54,
+	-93, 118, 8, 26, 26, 49, 50, -91, 49, 47,
+	46, 45, -75, -74, 44, -75, -104, 27, 27, -22,
+	-23, -24, -25, -29, -44, -110, -20, -75, 82, -75,
+	-78, -111, -19, 19, 47, -33, -33, -39, 54, 59,
+	55, 56, -38, -40, -110, -44, 52, 75, 73, 74,
+	60, -38, -38, -38, -39, -38, -38, -38, -38, -38,
+	-38, -38, -38, -38, -38, -111, -111, 46, -111, -75,
+	-19, -47, -48, 62, -36, 30, -2, -62, -60, -75,
+	-53, -57, 16, 15, -100, -101, -89, 50, 45, -93,
+	47, 46, 47, 46, -74, -97, 50, -78, -92, -32,
+	46, -26, -27, -28, 34, 38, 40, 35, 36, 37,
+	41, -79, -78, 20, -105, 20, -22, 89, -111, -19,
+	54, 55, 56, -41, -40, -38, -38, -38, 58, -38,
+	-111, -50, -48, 64, -33, -61, 44, -42, -43, -61,
+	-111, 46, -57, -38, -54, -55, -38, 47, 50, 50,
+	49, 47, -108, -51, 12, -23, -24, -23, -24, 34,
+	34, 34, 39, 34, 39, 34, -27, -30, 42, 100,
+	43, -78, -78, -111, -75, -111, -111, 58, -38, 90,
+	-38, 63, 25, 46, -75, 46, 46, -56, 22, 23,
+	47, 47, 45, -52, 13, 15, 44, 44, 34, 34,
+	97, 97, 97, -38, -38, 26, -43, -38, -55, -96,
+	-53, -33, -41, -33, -33, -110, -110, -110, 8, 47,
+	-57, -31, -75, -31, -31, -62, -74, -58, 17, 29,
+	-111, 46, -111, -111, 8, 75, -75, -75, -75,
 }
 var yyDef = [...]int{
 
 	0, -2, 1, 2, 3, 4, 5, 6, 7, 8,
-	9, 10, 11, 12, 35, 35, 35, 35, 35, 216,
-	206, 0, 0, 0, 224, 224, 224, 0, 39, 42,
-	37, 206, 0, 0, 0, 204, 0, 0, 217, 0,
-	0, 207, 0, 202, 0, 202, 0, 32, 33, 34,
-	15, 40, 41, 44, 0, 43, 36, 0, 0, 82,
-	221, 0, 20, 197, 0, 220, 0, 0, 0, 224,
-	0, 224, 0, 0, 0, 0, 0, 31, 0, 45,
-	0, 38, 0, 0, 0, 90, 0, 0, 224, 0,
-	218, 23, 0, 26, 0, 28, 203, 0, 224, 0,
-	46, 48, 53, 0, 51, 52, -2, 92, 0, 0,
-	130, 131, 132, 0, 0, 0, 0, 0, 151, 98,
-	99, 0, 222, 163, 164, 165, 166, 160, 196, 153,
-	0, 200, 201, 185, 90, 83, 171, 0, 198, 199,
-	0, 0, 21, 205, 0, 0, 224, 214, 208, 209,
-	210, 211, 212, 213, 27, 29, 30, 0, 0, 49,
-	54, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	9, 10, 11, 12, 13, 127, 127, 127, 127, 127,
+	0, 302, 0, 298, 0, 0, 0, 331, 331, 331,
+	0, 0, 0, 0, 131, 134, 129, 298, 0, 0,
+	0, 22, 0, 0, 0, 296, 303, 304, 305, 26,
+	300, 105, 308, 308, 308, 308, 308, 0, 92, 93,
+	94, 0, 96, 0, 0, 0, 299, 0, 294, 0,
+	294, 0, 120, 121, 122, 123, 316, 317, 322, 323,
+	324, 325, 326, 327, 328, 0, 0, 16, 132, 133,
+	136, 0, 135, 128, 0, 0, 174, 320, 321, 0,
+	21, 289, 0, 0, 36, 37, 0, 0, 0, 312,
+	318, 312, 312, 310, 311, 0, 331, 0, 0, 0,
+	0, 301, 0, 108, 309, 0, 0, 0, 0, 0,
+	0, 0, 95, 97, 0, 99, 331, 0, 0, 0,
+	0, 0, 0, 33, 124, 328, 126, 0, 137, 0,
+	130, 0, 0, 0, 182, 0, 0, 86, 0, 0,
+	40, 41, 55, 59, 60, 61, 0, 306, 318, 313,
+	306, 319, 318, 318, 306, 25, 34, 0, 106, 27,
+	117, 109, 110, 111, 0, 117, 0, 116, 100, 101,
+	102, 103, 98, 28, 104, 0, 30, 295, 0, 331,
+	125, 0, 138, 140, 145, 0, 143, 144, -2, -2,
+	184, 0, 0, 222, 223, 224, 0, 0, 0, 0,
+	0, 243, 190, 191, 0, 329, 255, 256, 257, 258,
+	252, 288, 245, 0, 292, 293, 277, 182, 175, 263,
+	0, 290, 291, 0, 0, 35, 87, 88, 314, 38,
+	39, 76, 42, 43, 0, 0, 46, 0, 0, 0,
+	50, 0, 52, 0, 0, 0, 82, 84, 77, 0,
+	306, 79, 80, 81, 0, 297, 107, 118, 0, 117,
+	113, 0, 0, 29, 31, 32, 0, 0, 141, 146,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 117, 118, 119, 120, 121, 122,
-	123, 95, 0, 0, 0, 0, 128, 143, 144, 145,
-	0, 0, 110, 0, 0, 154, 14, 0, 0, 0,
-	171, 179, 0, 91, 0, 128, 0, 219, 24, 0,
-	215, 90, 56, 58, 59, 69, 67, 0, 47, 55,
-	50, 161, 0, 147, 0, 0, 223, 93, 94, 97,
-	111, 0, 113, 115, 100, 101, 0, 125, 126, 0,
-	0, 0, 0, 103, 105, 0, 109, 133, 134, 135,
-	136, 137, 138, 139, 140, 141, 142, 96, 127, 0,
-	195, 146, 0, 158, 155, 0, 189, 0, 192, 189,
-	0, 187, 179, 19, 0, 0, 224, 25, 167, 0,
-	0, 0, 0, 72, 0, 0, 75, 0, 0, 0,
-	84, 70, 0, 0, 68, 0, 0, 148, 0, 112,
-	114, 116, 0, 102, 104, 106, 0, 0, 129, 150,
-	0, 156, 0, 0, 16, 0, 191, 193, 17, 186,
-	0, 18, 180, 172, 173, 176, 22, 169, 0, 57,
-	63, 0, 66, 73, 74, 76, 0, 78, 0, 80,
-	81, 60, 0, 0, 0, 71, 61, 62, 162, 149,
-	124, 0, 107, 152, 159, 0, 0, 0, 188, 0,
-	0, 175, 177, 178, 171, 0, 0, 0, 0, 77,
-	79, 0, 0, 0, 108, 157, 0, 194, 181, 174,
-	179, 170, 168, 64, 65, 0, 0, 0, 0, 182,
-	0, 88, 0, 0, 190, 13, 0, 0, 85, 0,
-	86, 87, 183, 0, 89, 0, 184,
+	0, 0, 0, 209, 210, 211, 212, 213, 214, 215,
+	187, 0, 0, 0, 0, 220, 235, 236, 237, 0,
+	0, 202, 0, 0, 246, 15, 0, 0, 0, 263,
+	271, 0, 183, 0, 220, 89, 0, 0, 315, 0,
+	45, 64, 65, 66, 47, 48, 67, 68, 0, 70,
+	71, 72, 0, 51, 53, 54, 0, 0, 62, 306,
+	0, 0, 307, 78, 0, 119, 112, 0, 115, 182,
+	148, 150, 151, 161, 159, 0, 139, 147, 142, 253,
+	0, 239, 0, 0, 330, 185, 186, 189, 203, 0,
+	205, 207, 192, 193, 0, 217, 218, 0, 0, 0,
+	0, 195, 197, 0, 201, 225, 226, 227, 228, 229,
+	230, 231, 232, 233, 234, 188, 219, 0, 287, 238,
+	0, 250, 247, 0, 281, 0, 284, 281, 0, 279,
+	271, 20, 0, 0, 90, 91, 44, 69, 0, 49,
+	56, 0, 58, 0, 75, 83, 0, 23, 114, 259,
+	0, 0, 0, 0, 164, 0, 0, 167, 0, 0,
+	0, 176, 162, 0, 0, 160, 0, 0, 240, 0,
+	204, 206, 208, 0, 194, 196, 198, 0, 0, 221,
+	242, 0, 248, 0, 0, 17, 0, 283, 285, 18,
+	278, 0, 19, 272, 264, 265, 268, 73, 0, 0,
+	63, 85, 0, 261, 0, 149, 155, 0, 158, 165,
+	166, 168, 0, 170, 0, 172, 173, 152, 0, 0,
+	0, 163, 153, 154, 254, 241, 216, 0, 199, 244,
+	251, 0, 0, 0, 280, 0, 0, 267, 269, 270,
+	74, 57, 0, 263, 0, 0, 0, 0, 169, 171,
+	0, 0, 0, 200, 249, 0, 286, 273, 266, 0,
+	271, 262, 260, 156, 157, 0, 0, 0, 0, 306,
+	274, 0, 180, 0, 0, 282, 24, 14, 0, 0,
+	177, 0, 178, 179, 275, 0, 181, 0, 276,
 }
 var yyTok1 = [...]int{
 
@@ -602,6 +791,8 @@ var yyTok2 = [...]int{
 	68, 69, 70, 71, 72, 73, 74, 75, 78, 79,
 	87, 88, 90, 91, 92, 93, 94, 95, 96, 97,
 	98, 99, 100, 101, 102, 103, 104, 105, 106, 107,
+	108, 109, 110, 111, 112, 113, 114, 115, 116, 117,
+	118, 119, 120, 121, 122, 123, 124, 125, 126, 127,
 }
 var yyTok3 = [...]int{
 	0,
@@ -946,25 +1137,25 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:171
+		//line sql.y:221
 		{
 			setParseTree(yylex, yyDollar[1].statement)
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:177
+		//line sql.y:227
 		{
 			yyVAL.statement = yyDollar[1].selStmt
 		}
-	case 13:
+	case 14:
 		yyDollar = yyS[yypt-13 : yypt+1]
-		//line sql.y:193
+		//line sql.y:244
 		{
 			yyVAL.selStmt = &Select{Comments: Comments(yyDollar[2].bytes2), Distinct: yyDollar[3].str, Hints: yyDollar[4].str, SelectExprs: yyDollar[5].selectExprs, From: yyDollar[7].tableExprs, Where: NewWhere(WhereStr, yyDollar[8].boolExpr), GroupBy: GroupBy(yyDollar[9].valExprs), Having: NewWhere(HavingStr, yyDollar[10].boolExpr), OrderBy: yyDollar[11].orderBy, Limit: yyDollar[12].limit, Lock: yyDollar[13].str}
 		}
-	case 14:
+	case 15:
 		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:197
+		//line sql.y:248
 		{
 			if yyDollar[4].colIdent.Lowered() != "value" {
 				yylex.Error("expecting value after next")
@@ -972,21 +1163,21 @@ yydefault:
 			}
 			yyVAL.selStmt = &Select{Comments: Comments(yyDollar[2].bytes2), SelectExprs: SelectExprs{Nextval{}}, From: TableExprs{&AliasedTableExpr{Expr: yyDollar[6].tableName}}}
 		}
-	case 15:
+	case 16:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:205
+		//line sql.y:256
 		{
 			yyVAL.selStmt = &Union{Type: yyDollar[2].str, Left: yyDollar[1].selStmt, Right: yyDollar[3].selStmt}
 		}
-	case 16:
+	case 17:
 		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:211
+		//line sql.y:262
 		{
 			yyVAL.statement = &Insert{Comments: Comments(yyDollar[2].bytes2), Ignore: yyDollar[3].str, Table: yyDollar[5].tableName, Columns: yyDollar[6].columns, Rows: yyDollar[7].insRows, OnDup: OnDup(yyDollar[8].updateExprs)}
 		}
-	case 17:
+	case 18:
 		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:215
+		//line sql.y:266
 		{
 			cols := make(Columns, 0, len(yyDollar[7].updateExprs))
 			vals := make(ValTuple, 0, len(yyDollar[7].updateExprs))
@@ -996,71 +1187,83 @@ yydefault:
 			}
 			yyVAL.statement = &Insert{Comments: Comments(yyDollar[2].bytes2), Ignore: yyDollar[3].str, Table: yyDollar[5].tableName, Columns: cols, Rows: Values{vals}, OnDup: OnDup(yyDollar[8].updateExprs)}
 		}
-	case 18:
+	case 19:
 		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:227
+		//line sql.y:278
 		{
 			yyVAL.statement = &Update{Comments: Comments(yyDollar[2].bytes2), Table: yyDollar[3].tableName, Exprs: yyDollar[5].updateExprs, Where: NewWhere(WhereStr, yyDollar[6].boolExpr), OrderBy: yyDollar[7].orderBy, Limit: yyDollar[8].limit}
 		}
-	case 19:
+	case 20:
 		yyDollar = yyS[yypt-7 : yypt+1]
-		//line sql.y:233
+		//line sql.y:284
 		{
 			yyVAL.statement = &Delete{Comments: Comments(yyDollar[2].bytes2), Table: yyDollar[4].tableName, Where: NewWhere(WhereStr, yyDollar[5].boolExpr), OrderBy: yyDollar[6].orderBy, Limit: yyDollar[7].limit}
 		}
-	case 20:
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:239
+		//line sql.y:290
 		{
 			yyVAL.statement = &Set{Comments: Comments(yyDollar[2].bytes2), Exprs: yyDollar[3].updateExprs}
 		}
-	case 21:
-		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:245
+	case 22:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:296
 		{
-			yyVAL.statement = &DDL{Action: CreateStr, NewName: yyDollar[4].tableIdent}
+			yyDollar[1].ddl.TableSpec = yyDollar[2].tableSpec
+			yyVAL.statement = yyDollar[1].ddl
 		}
-	case 22:
-		yyDollar = yyS[yypt-8 : yypt+1]
-		//line sql.y:249
+	case 23:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		//line sql.y:301
+		{
+			setDDL(yylex, &DDL{Action: AlterStr, Table: yyDollar[7].tableIdent, NewName: yyDollar[7].tableIdent})
+		}
+	case 24:
+		yyDollar = yyS[yypt-12 : yypt+1]
+		//line sql.y:305
 		{
 			// Change this to an alter statement
-			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[7].tableIdent, NewName: yyDollar[7].tableIdent}
+			index := &IndexDefinition{Type: yyDollar[2].str, Name: yyDollar[4].colIdent, Using: yyDollar[5].str, Columns: yyDollar[10].indexCols}
+			if yyDollar[12].str != "" {
+				index.Using = yyDollar[12].str
+			}
+			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[7].tableIdent, NewName: yyDollar[7].tableIdent, AlterSpecs: []*AlterSpec{{Action: AddIndexStr, Index: index}}}
 		}
-	case 23:
+	case 25:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:254
+		//line sql.y:314
 		{
 			yyVAL.statement = &DDL{Action: CreateStr, NewName: TableIdent(yyDollar[3].colIdent.Lowered())}
 		}
-	case 24:
-		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:260
+	case 26:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:320
 		{
-			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[4].tableIdent, NewName: yyDollar[4].tableIdent}
+			yyDollar[1].ddl.AlterSpecs = yyDollar[2].alterSpecs
+			yyVAL.statement = yyDollar[1].ddl
 		}
-	case 25:
-		yyDollar = yyS[yypt-7 : yypt+1]
-		//line sql.y:264
+	case 27:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:325
 		{
 			// Change this to a rename statement
-			yyVAL.statement = &DDL{Action: RenameStr, Table: yyDollar[4].tableIdent, NewName: yyDollar[7].tableIdent}
+			yyVAL.statement = &DDL{Action: RenameStr, Table: yyDollar[1].ddl.Table, NewName: yyDollar[4].tableIdent}
 		}
-	case 26:
+	case 28:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:269
+		//line sql.y:330
 		{
 			yyVAL.statement = &DDL{Action: AlterStr, Table: TableIdent(yyDollar[3].colIdent.Lowered()), NewName: TableIdent(yyDollar[3].colIdent.Lowered())}
 		}
-	case 27:
+	case 29:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:275
+		//line sql.y:336
 		{
 			yyVAL.statement = &DDL{Action: RenameStr, Table: yyDollar[3].tableIdent, NewName: yyDollar[5].tableIdent}
 		}
-	case 28:
+	case 30:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:281
+		//line sql.y:342
 		{
 			var exists bool
 			if yyDollar[3].byt != 0 {
@@ -1068,16 +1271,16 @@ yydefault:
 			}
 			yyVAL.statement = &DDL{Action: DropStr, Table: yyDollar[4].tableIdent, IfExists: exists}
 		}
-	case 29:
+	case 31:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:289
+		//line sql.y:350
 		{
 			// Change this to an alter statement
-			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[5].tableIdent, NewName: yyDollar[5].tableIdent}
+			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[5].tableIdent, NewName: yyDollar[5].tableIdent, AlterSpecs: []*AlterSpec{{Action: DropIndexStr, Name: yyDollar[3].colIdent}}}
 		}
-	case 30:
+	case 32:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:294
+		//line sql.y:355
 		{
 			var exists bool
 			if yyDollar[3].byt != 0 {
@@ -1085,668 +1288,1232 @@ yydefault:
 			}
 			yyVAL.statement = &DDL{Action: DropStr, Table: TableIdent(yyDollar[4].colIdent.Lowered()), IfExists: exists}
 		}
-	case 31:
+	case 33:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:304
+		//line sql.y:365
 		{
 			yyVAL.statement = &DDL{Action: AlterStr, Table: yyDollar[3].tableIdent, NewName: yyDollar[3].tableIdent}
 		}
-	case 32:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:310
-		{
-			yyVAL.statement = &Other{}
-		}
-	case 33:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:314
-		{
-			yyVAL.statement = &Other{}
-		}
 	case 34:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:318
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:371
 		{
-			yyVAL.statement = &Other{}
+			yyVAL.ddl = &DDL{Action: CreateStr, NewName: yyDollar[4].tableIdent, IfNotExists: yyDollar[3].byt != 0}
+			setDDL(yylex, yyVAL.ddl)
 		}
 	case 35:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:323
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:378
 		{
-			setAllowComments(yylex, true)
+			yyVAL.tableSpec = yyDollar[2].tableSpec
+			yyVAL.tableSpec.Options = yyDollar[4].tableOpts
 		}
 	case 36:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:327
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:385
 		{
-			yyVAL.bytes2 = yyDollar[2].bytes2
-			setAllowComments(yylex, false)
+			yyVAL.tableSpec = &TableSpec{Columns: []*ColumnDefinition{yyDollar[1].columnDef}}
 		}
 	case 37:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:333
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:389
 		{
-			yyVAL.bytes2 = nil
+			yyVAL.tableSpec = &TableSpec{Indexes: []*IndexDefinition{yyDollar[1].indexDef}}
 		}
 	case 38:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:337
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:393
 		{
-			yyVAL.bytes2 = append(yyDollar[1].bytes2, yyDollar[2].bytes)
+			yyVAL.tableSpec.Columns = append(yyVAL.tableSpec.Columns, yyDollar[3].columnDef)
 		}
 	case 39:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:343
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:397
 		{
-			yyVAL.str = UnionStr
+			yyVAL.tableSpec.Indexes = append(yyVAL.tableSpec.Indexes, yyDollar[3].indexDef)
 		}
 	case 40:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:347
+		//line sql.y:403
 		{
-			yyVAL.str = UnionAllStr
+			yyVAL.columnDef = &ColumnDefinition{Name: yyDollar[1].colIdent, Type: yyDollar[2].columnType}
 		}
 	case 41:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:351
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:411
 		{
-			yyVAL.str = UnionDistinctStr
+			yyVAL.columnType = yyDollar[1].columnType
 		}
 	case 42:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:356
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:415
 		{
-			yyVAL.str = ""
+			yyVAL.columnType.Unsigned = true
 		}
 	case 43:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:360
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:419
 		{
-			yyVAL.str = DistinctStr
+			yyVAL.columnType.Zerofill = true
 		}
 	case 44:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:365
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:423
 		{
-			yyVAL.str = ""
+			yyVAL.columnType.Charset = yyDollar[4].str
 		}
 	case 45:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:369
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:427
 		{
-			yyVAL.str = StraightJoinHint
+			yyVAL.columnType.Collate = yyDollar[3].str
 		}
 	case 46:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:375
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:431
 		{
-			yyVAL.selectExprs = SelectExprs{yyDollar[1].selectExpr}
+			yyVAL.columnType.NotNull = false
+			yyVAL.columnType.Null = true
 		}
 	case 47:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:379
+		//line sql.y:436
 		{
-			yyVAL.selectExprs = append(yyVAL.selectExprs, yyDollar[3].selectExpr)
+			yyVAL.columnType.NotNull = true
+			yyVAL.columnType.Null = false
 		}
 	case 48:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:385
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:441
 		{
-			yyVAL.selectExpr = &StarExpr{}
+			yyVAL.columnType.Default = yyDollar[3].valExpr
 		}
 	case 49:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:389
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:445
 		{
-			yyVAL.selectExpr = &NonStarExpr{Expr: yyDollar[1].expr, As: yyDollar[2].colIdent}
+			yyVAL.columnType.OnUpdate = yyDollar[4].valExpr
 		}
 	case 50:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:393
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:449
 		{
-			yyVAL.selectExpr = &StarExpr{TableName: yyDollar[1].tableIdent}
+			yyVAL.columnType.Autoincrement = true
 		}
 	case 51:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:399
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:453
 		{
-			yyVAL.expr = yyDollar[1].boolExpr
+			yyVAL.columnType.KeyOpt = PrimaryKeyStr
 		}
 	case 52:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:403
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:457
 		{
-			yyVAL.expr = yyDollar[1].valExpr
+			yyVAL.columnType.KeyOpt = UniqueKeyStr
 		}
 	case 53:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:408
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:461
 		{
-			yyVAL.colIdent = ColIdent{}
+			yyVAL.columnType.KeyOpt = UniqueKeyStr
 		}
 	case 54:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:412
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:465
 		{
-			yyVAL.colIdent = yyDollar[1].colIdent
+			yyVAL.columnType.Comment = StrVal(yyDollar[3].bytes)
 		}
 	case 55:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:416
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:471
 		{
-			yyVAL.colIdent = yyDollar[2].colIdent
+			yyVAL.columnType = &ColumnType{Type: yyDollar[1].str}
 		}
 	case 56:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:422
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:475
 		{
-			yyVAL.tableExprs = TableExprs{yyDollar[1].tableExpr}
+			yyVAL.columnType = &ColumnType{Type: yyDollar[1].str, Length: NumVal(yyDollar[3].bytes)}
 		}
 	case 57:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:426
+		yyDollar = yyS[yypt-6 : yypt+1]
+		//line sql.y:479
 		{
-			yyVAL.tableExprs = append(yyVAL.tableExprs, yyDollar[3].tableExpr)
+			yyVAL.columnType = &ColumnType{Type: yyDollar[1].str, Length: NumVal(yyDollar[3].bytes), Scale: NumVal(yyDollar[5].bytes)}
+		}
+	case 58:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:483
+		{
+			yyVAL.columnType = &ColumnType{Type: yyDollar[1].str, EnumValues: yyDollar[3].strs}
+		}
+	case 59:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:491
+		{
+			yyVAL.str = strings.ToLower(string(yyDollar[1].bytes))
 		}
 	case 60:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:436
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:495
 		{
-			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].tableName, As: yyDollar[2].tableIdent, Hints: yyDollar[3].indexHints}
+			yyVAL.str = string(yyDollar[1].bytes)
 		}
 	case 61:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:440
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:499
 		{
-			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].subquery, As: yyDollar[3].tableIdent}
+			yyVAL.str = "set"
 		}
 	case 62:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:444
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:505
 		{
-			yyVAL.tableExpr = &ParenTableExpr{Exprs: yyDollar[2].tableExprs}
+			yyVAL.strs = []string{string(yyDollar[1].bytes)}
 		}
 	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:457
+		//line sql.y:509
 		{
-			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr}
+			yyVAL.strs = append(yyVAL.strs, string(yyDollar[3].bytes))
 		}
 	case 64:
-		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:461
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:515
 		{
-			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr, On: yyDollar[5].boolExpr}
+			yyVAL.str = yyDollar[1].colIdent.String()
 		}
 	case 65:
-		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:465
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:519
 		{
-			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr, On: yyDollar[5].boolExpr}
+			yyVAL.str = string(yyDollar[1].bytes)
 		}
 	case 66:
-		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:469
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:523
 		{
-			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr}
+			if string(yyDollar[1].bytes) != "binary" {
+				yylex.Error("expecting charset name")
+				return 1
+			}
+			yyVAL.str = string(yyDollar[1].bytes)
 		}
 	case 67:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:474
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:533
 		{
-			yyVAL.empty = struct{}{}
+			yyVAL.valExpr = StrVal(yyDollar[1].bytes)
 		}
 	case 68:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:476
+		//line sql.y:537
 		{
-			yyVAL.empty = struct{}{}
+			yyVAL.valExpr = NumVal(yyDollar[1].bytes)
 		}
 	case 69:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:479
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:541
 		{
-			yyVAL.tableIdent = ""
+			yyVAL.valExpr = NumVal(append([]byte("-"), yyDollar[2].bytes...))
 		}
 	case 70:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:483
+		//line sql.y:545
 		{
-			yyVAL.tableIdent = yyDollar[1].tableIdent
+			yyVAL.valExpr = &NullVal{}
 		}
 	case 71:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:487
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:549
 		{
-			yyVAL.tableIdent = yyDollar[2].tableIdent
+			yyVAL.valExpr = yyDollar[1].valExpr
 		}
 	case 72:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:493
+		//line sql.y:555
 		{
-			yyVAL.str = JoinStr
+			yyVAL.valExpr = &FuncExpr{Name: "current_timestamp"}
 		}
 	case 73:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:497
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:559
 		{
-			yyVAL.str = JoinStr
+			yyVAL.valExpr = &FuncExpr{Name: "current_timestamp"}
 		}
 	case 74:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:501
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:563
 		{
-			yyVAL.str = JoinStr
+			yyVAL.valExpr = &FuncExpr{Name: "current_timestamp", Exprs: SelectExprs{&NonStarExpr{Expr: NumVal(yyDollar[3].bytes)}}}
 		}
 	case 75:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:505
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:569
 		{
-			yyVAL.str = StraightJoinStr
+			yyVAL.indexDef = yyDollar[1].indexDef
+			yyVAL.indexDef.Columns = yyDollar[3].indexCols
+			if yyDollar[5].str != "" {
+				yyVAL.indexDef.Using = yyDollar[5].str
+			}
 		}
 	case 76:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:511
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:577
 		{
-			yyVAL.str = LeftJoinStr
+			yyVAL.indexDef.Comment = StrVal(yyDollar[3].bytes)
 		}
 	case 77:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:515
+		//line sql.y:583
 		{
-			yyVAL.str = LeftJoinStr
+			yyVAL.indexDef = &IndexDefinition{Type: PrimaryKeyStr, Using: yyDollar[3].str}
 		}
 	case 78:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:519
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:587
 		{
-			yyVAL.str = RightJoinStr
+			yyVAL.indexDef = &IndexDefinition{Type: UniqueKeyStr, Name: yyDollar[3].colIdent, Using: yyDollar[4].str}
 		}
 	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:523
+		//line sql.y:591
 		{
-			yyVAL.str = RightJoinStr
+			yyVAL.indexDef = &IndexDefinition{Type: KeyStr, Name: yyDollar[2].colIdent, Using: yyDollar[3].str}
 		}
 	case 80:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:529
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:595
 		{
-			yyVAL.str = NaturalJoinStr
+			yyVAL.indexDef = &IndexDefinition{Type: FulltextKeyStr, Name: yyDollar[3].colIdent}
 		}
 	case 81:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:533
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:599
 		{
-			if yyDollar[2].str == LeftJoinStr {
-				yyVAL.str = NaturalLeftJoinStr
-			} else {
-				yyVAL.str = NaturalRightJoinStr
-			}
+			yyVAL.indexDef = &IndexDefinition{Type: SpatialKeyStr, Name: yyDollar[3].colIdent}
 		}
 	case 82:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:543
+		//line sql.y:605
 		{
-			yyVAL.tableName = &TableName{Name: yyDollar[1].tableIdent}
+			yyVAL.indexCols = []*IndexColumn{yyDollar[1].indexCol}
 		}
 	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:547
+		//line sql.y:609
 		{
-			yyVAL.tableName = &TableName{Qualifier: yyDollar[1].tableIdent, Name: yyDollar[3].tableIdent}
+			yyVAL.indexCols = append(yyVAL.indexCols, yyDollar[3].indexCol)
 		}
 	case 84:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:552
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:615
 		{
-			yyVAL.indexHints = nil
+			yyVAL.indexCol = &IndexColumn{Column: yyDollar[1].colIdent}
 		}
 	case 85:
-		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:556
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:619
 		{
-			yyVAL.indexHints = &IndexHints{Type: UseStr, Indexes: yyDollar[4].colIdents}
+			yyVAL.indexCol = &IndexColumn{Column: yyDollar[1].colIdent, Length: NumVal(yyDollar[3].bytes)}
 		}
 	case 86:
-		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:560
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:624
 		{
-			yyVAL.indexHints = &IndexHints{Type: IgnoreStr, Indexes: yyDollar[4].colIdents}
+			yyVAL.tableOpts = nil
 		}
 	case 87:
-		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:564
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:628
 		{
-			yyVAL.indexHints = &IndexHints{Type: ForceStr, Indexes: yyDollar[4].colIdents}
+			yyVAL.tableOpts = yyDollar[1].tableOpts
 		}
 	case 88:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:570
+		//line sql.y:634
+		{
+			yyVAL.tableOpts = []*TableOption{yyDollar[1].tableOpt}
+		}
+	case 89:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:638
+		{
+			yyVAL.tableOpts = append(yyVAL.tableOpts, yyDollar[2].tableOpt)
+		}
+	case 90:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:642
+		{
+			yyVAL.tableOpts = append(yyVAL.tableOpts, yyDollar[3].tableOpt)
+		}
+	case 91:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:648
+		{
+			yyVAL.tableOpt = yyDollar[3].tableOpt
+			yyVAL.tableOpt.Name = yyDollar[1].str
+		}
+	case 92:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:655
+		{
+			yyVAL.str = strings.ToLower(string(yyDollar[1].bytes))
+		}
+	case 93:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:659
+		{
+			yyVAL.str = "auto_increment"
+		}
+	case 94:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:663
+		{
+			yyVAL.str = "comment"
+		}
+	case 95:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:667
+		{
+			yyVAL.str = "character set"
+		}
+	case 96:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:671
+		{
+			yyVAL.str = "collate"
+		}
+	case 97:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:675
+		{
+			yyVAL.str = "default " + strings.ToLower(string(yyDollar[2].bytes))
+		}
+	case 98:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:679
+		{
+			yyVAL.str = "default character set"
+		}
+	case 99:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:683
+		{
+			yyVAL.str = "default collate"
+		}
+	case 100:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:689
+		{
+			yyVAL.tableOpt = &TableOption{Value: string(yyDollar[1].bytes)}
+		}
+	case 101:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:693
+		{
+			yyVAL.tableOpt = &TableOption{Value: string(yyDollar[1].bytes)}
+		}
+	case 102:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:697
+		{
+			yyVAL.tableOpt = &TableOption{Value: string(yyDollar[1].bytes), Quoted: true}
+		}
+	case 103:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:701
+		{
+			yyVAL.tableOpt = &TableOption{Value: string(yyDollar[1].bytes)}
+		}
+	case 104:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:707
+		{
+			yyVAL.ddl = &DDL{Action: AlterStr, Table: yyDollar[4].tableIdent, NewName: yyDollar[4].tableIdent}
+			setDDL(yylex, yyVAL.ddl)
+		}
+	case 105:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:714
+		{
+			yyVAL.alterSpecs = []*AlterSpec{yyDollar[1].alterSpec}
+		}
+	case 106:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:718
+		{
+			yyVAL.alterSpecs = append(yyVAL.alterSpecs, yyDollar[3].alterSpec)
+		}
+	case 107:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:724
+		{
+			yyVAL.alterSpec = yyDollar[4].alterSpec
+			yyVAL.alterSpec.Action = AddColumnStr
+			yyVAL.alterSpec.Column = yyDollar[3].columnDef
+		}
+	case 108:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:730
+		{
+			yyVAL.alterSpec = &AlterSpec{Action: AddIndexStr, Index: yyDollar[2].indexDef}
+		}
+	case 109:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:734
+		{
+			yyVAL.alterSpec = &AlterSpec{Action: DropColumnStr, Name: yyDollar[3].colIdent}
+		}
+	case 110:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:738
+		{
+			yyVAL.alterSpec = &AlterSpec{Action: DropIndexStr, Name: yyDollar[3].colIdent}
+		}
+	case 111:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:742
+		{
+			yyVAL.alterSpec = &AlterSpec{Action: DropPrimaryKeyStr}
+		}
+	case 112:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:746
+		{
+			yyVAL.alterSpec = yyDollar[5].alterSpec
+			yyVAL.alterSpec.Action = ChangeColumnStr
+			yyVAL.alterSpec.Name = yyDollar[3].colIdent
+			yyVAL.alterSpec.Column = yyDollar[4].columnDef
+		}
+	case 113:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:753
+		{
+			yyVAL.alterSpec = yyDollar[4].alterSpec
+			yyVAL.alterSpec.Action = ModifyColumnStr
+			yyVAL.alterSpec.Column = yyDollar[3].columnDef
+		}
+	case 114:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		//line sql.y:759
+		{
+			yyVAL.alterSpec = &AlterSpec{Action: AlterColumnStr, Name: yyDollar[3].colIdent, Default: yyDollar[6].valExpr}
+		}
+	case 115:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:763
+		{
+			yyVAL.alterSpec = &AlterSpec{Action: AlterColumnStr, Name: yyDollar[3].colIdent}
+		}
+	case 116:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:767
+		{
+			// The '=' is required here, so that unsupported operations
+			// like DISABLE KEYS are not parsed as table options.
+			yyDollar[3].tableOpt.Name = yyDollar[1].str
+			yyVAL.alterSpec = &AlterSpec{Action: TableOptionStr, Option: yyDollar[3].tableOpt}
+		}
+	case 117:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:775
+		{
+			yyVAL.alterSpec = &AlterSpec{}
+		}
+	case 118:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:779
+		{
+			yyVAL.alterSpec = &AlterSpec{First: true}
+		}
+	case 119:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:783
+		{
+			yyVAL.alterSpec = &AlterSpec{After: yyDollar[2].colIdent}
+		}
+	case 120:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:789
+		{
+			yyVAL.statement = &Other{}
+		}
+	case 121:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:793
+		{
+			yyVAL.statement = &Other{}
+		}
+	case 122:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:797
+		{
+			yyVAL.statement = &Other{}
+		}
+	case 123:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:803
+		{
+			yyVAL.statement = &Savepoint{Action: SavepointStr, Name: yyDollar[2].colIdent}
+		}
+	case 124:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:807
+		{
+			yyVAL.statement = &Savepoint{Action: RollbackToStr, Name: yyDollar[3].colIdent}
+		}
+	case 125:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		//line sql.y:811
+		{
+			yyVAL.statement = &Savepoint{Action: RollbackToStr, Name: yyDollar[4].colIdent}
+		}
+	case 126:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:815
+		{
+			yyVAL.statement = &Savepoint{Action: ReleaseStr, Name: yyDollar[3].colIdent}
+		}
+	case 127:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:820
+		{
+			setAllowComments(yylex, true)
+		}
+	case 128:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:824
+		{
+			yyVAL.bytes2 = yyDollar[2].bytes2
+			setAllowComments(yylex, false)
+		}
+	case 129:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:830
+		{
+			yyVAL.bytes2 = nil
+		}
+	case 130:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:834
+		{
+			yyVAL.bytes2 = append(yyDollar[1].bytes2, yyDollar[2].bytes)
+		}
+	case 131:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:840
+		{
+			yyVAL.str = UnionStr
+		}
+	case 132:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:844
+		{
+			yyVAL.str = UnionAllStr
+		}
+	case 133:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:848
+		{
+			yyVAL.str = UnionDistinctStr
+		}
+	case 134:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:853
+		{
+			yyVAL.str = ""
+		}
+	case 135:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:857
+		{
+			yyVAL.str = DistinctStr
+		}
+	case 136:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:862
+		{
+			yyVAL.str = ""
+		}
+	case 137:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:866
+		{
+			yyVAL.str = StraightJoinHint
+		}
+	case 138:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:872
+		{
+			yyVAL.selectExprs = SelectExprs{yyDollar[1].selectExpr}
+		}
+	case 139:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:876
+		{
+			yyVAL.selectExprs = append(yyVAL.selectExprs, yyDollar[3].selectExpr)
+		}
+	case 140:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:882
+		{
+			yyVAL.selectExpr = &StarExpr{}
+		}
+	case 141:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:886
+		{
+			yyVAL.selectExpr = &NonStarExpr{Expr: yyDollar[1].expr, As: yyDollar[2].colIdent}
+		}
+	case 142:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:890
+		{
+			yyVAL.selectExpr = &StarExpr{TableName: yyDollar[1].tableIdent}
+		}
+	case 143:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:896
+		{
+			yyVAL.expr = yyDollar[1].boolExpr
+		}
+	case 144:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:900
+		{
+			yyVAL.expr = yyDollar[1].valExpr
+		}
+	case 145:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:905
+		{
+			yyVAL.colIdent = ColIdent{}
+		}
+	case 146:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:909
+		{
+			yyVAL.colIdent = yyDollar[1].colIdent
+		}
+	case 147:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:913
+		{
+			yyVAL.colIdent = yyDollar[2].colIdent
+		}
+	case 148:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:919
+		{
+			yyVAL.tableExprs = TableExprs{yyDollar[1].tableExpr}
+		}
+	case 149:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:923
+		{
+			yyVAL.tableExprs = append(yyVAL.tableExprs, yyDollar[3].tableExpr)
+		}
+	case 152:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:933
+		{
+			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].tableName, As: yyDollar[2].tableIdent, Hints: yyDollar[3].indexHints}
+		}
+	case 153:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:937
+		{
+			yyVAL.tableExpr = &AliasedTableExpr{Expr: yyDollar[1].subquery, As: yyDollar[3].tableIdent}
+		}
+	case 154:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:941
+		{
+			yyVAL.tableExpr = &ParenTableExpr{Exprs: yyDollar[2].tableExprs}
+		}
+	case 155:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:954
+		{
+			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr}
+		}
+	case 156:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:958
+		{
+			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr, On: yyDollar[5].boolExpr}
+		}
+	case 157:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:962
+		{
+			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr, On: yyDollar[5].boolExpr}
+		}
+	case 158:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:966
+		{
+			yyVAL.tableExpr = &JoinTableExpr{LeftExpr: yyDollar[1].tableExpr, Join: yyDollar[2].str, RightExpr: yyDollar[3].tableExpr}
+		}
+	case 159:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:971
+		{
+			yyVAL.empty = struct{}{}
+		}
+	case 160:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:973
+		{
+			yyVAL.empty = struct{}{}
+		}
+	case 161:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:976
+		{
+			yyVAL.tableIdent = ""
+		}
+	case 162:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:980
+		{
+			yyVAL.tableIdent = yyDollar[1].tableIdent
+		}
+	case 163:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:984
+		{
+			yyVAL.tableIdent = yyDollar[2].tableIdent
+		}
+	case 164:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:990
+		{
+			yyVAL.str = JoinStr
+		}
+	case 165:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:994
+		{
+			yyVAL.str = JoinStr
+		}
+	case 166:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:998
+		{
+			yyVAL.str = JoinStr
+		}
+	case 167:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1002
+		{
+			yyVAL.str = StraightJoinStr
+		}
+	case 168:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:1008
+		{
+			yyVAL.str = LeftJoinStr
+		}
+	case 169:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:1012
+		{
+			yyVAL.str = LeftJoinStr
+		}
+	case 170:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:1016
+		{
+			yyVAL.str = RightJoinStr
+		}
+	case 171:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:1020
+		{
+			yyVAL.str = RightJoinStr
+		}
+	case 172:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:1026
+		{
+			yyVAL.str = NaturalJoinStr
+		}
+	case 173:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:1030
+		{
+			if yyDollar[2].str == LeftJoinStr {
+				yyVAL.str = NaturalLeftJoinStr
+			} else {
+				yyVAL.str = NaturalRightJoinStr
+			}
+		}
+	case 174:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1040
+		{
+			yyVAL.tableName = &TableName{Name: yyDollar[1].tableIdent}
+		}
+	case 175:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		//line sql.y:1044
+		{
+			yyVAL.tableName = &TableName{Qualifier: yyDollar[1].tableIdent, Name: yyDollar[3].tableIdent}
+		}
+	case 176:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:1049
+		{
+			yyVAL.indexHints = nil
+		}
+	case 177:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:1053
+		{
+			yyVAL.indexHints = &IndexHints{Type: UseStr, Indexes: yyDollar[4].colIdents}
+		}
+	case 178:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:1057
+		{
+			yyVAL.indexHints = &IndexHints{Type: IgnoreStr, Indexes: yyDollar[4].colIdents}
+		}
+	case 179:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		//line sql.y:1061
+		{
+			yyVAL.indexHints = &IndexHints{Type: ForceStr, Indexes: yyDollar[4].colIdents}
+		}
+	case 180:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1067
 		{
 			yyVAL.colIdents = []ColIdent{yyDollar[1].colIdent}
 		}
-	case 89:
+	case 181:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:574
+		//line sql.y:1071
 		{
 			yyVAL.colIdents = append(yyDollar[1].colIdents, yyDollar[3].colIdent)
 		}
-	case 90:
+	case 182:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:579
+		//line sql.y:1076
 		{
 			yyVAL.boolExpr = nil
 		}
-	case 91:
+	case 183:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:583
+		//line sql.y:1080
 		{
 			yyVAL.boolExpr = yyDollar[2].boolExpr
 		}
-	case 93:
+	case 185:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:590
+		//line sql.y:1087
 		{
 			yyVAL.boolExpr = &AndExpr{Left: yyDollar[1].boolExpr, Right: yyDollar[3].boolExpr}
 		}
-	case 94:
+	case 186:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:594
+		//line sql.y:1091
 		{
 			yyVAL.boolExpr = &OrExpr{Left: yyDollar[1].boolExpr, Right: yyDollar[3].boolExpr}
 		}
-	case 95:
+	case 187:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:598
+		//line sql.y:1095
 		{
 			yyVAL.boolExpr = &NotExpr{Expr: yyDollar[2].boolExpr}
 		}
-	case 96:
+	case 188:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:602
+		//line sql.y:1099
 		{
 			yyVAL.boolExpr = &ParenBoolExpr{Expr: yyDollar[2].boolExpr}
 		}
-	case 97:
+	case 189:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:606
+		//line sql.y:1103
 		{
 			yyVAL.boolExpr = &IsExpr{Operator: yyDollar[3].str, Expr: yyDollar[1].boolExpr}
 		}
-	case 98:
+	case 190:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:612
+		//line sql.y:1109
 		{
 			yyVAL.boolExpr = BoolVal(true)
 		}
-	case 99:
+	case 191:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:616
+		//line sql.y:1113
 		{
 			yyVAL.boolExpr = BoolVal(false)
 		}
-	case 100:
+	case 192:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:620
+		//line sql.y:1117
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: yyDollar[2].str, Right: yyDollar[3].valExpr}
 		}
-	case 101:
+	case 193:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:624
+		//line sql.y:1121
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: InStr, Right: yyDollar[3].colTuple}
 		}
-	case 102:
+	case 194:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:628
+		//line sql.y:1125
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: NotInStr, Right: yyDollar[4].colTuple}
 		}
-	case 103:
+	case 195:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:632
+		//line sql.y:1129
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: LikeStr, Right: yyDollar[3].valExpr}
 		}
-	case 104:
+	case 196:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:636
+		//line sql.y:1133
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: NotLikeStr, Right: yyDollar[4].valExpr}
 		}
-	case 105:
+	case 197:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:640
+		//line sql.y:1137
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: RegexpStr, Right: yyDollar[3].valExpr}
 		}
-	case 106:
+	case 198:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:644
+		//line sql.y:1141
 		{
 			yyVAL.boolExpr = &ComparisonExpr{Left: yyDollar[1].valExpr, Operator: NotRegexpStr, Right: yyDollar[4].valExpr}
 		}
-	case 107:
+	case 199:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:648
+		//line sql.y:1145
 		{
 			yyVAL.boolExpr = &RangeCond{Left: yyDollar[1].valExpr, Operator: BetweenStr, From: yyDollar[3].valExpr, To: yyDollar[5].valExpr}
 		}
-	case 108:
+	case 200:
 		yyDollar = yyS[yypt-6 : yypt+1]
-		//line sql.y:652
+		//line sql.y:1149
 		{
 			yyVAL.boolExpr = &RangeCond{Left: yyDollar[1].valExpr, Operator: NotBetweenStr, From: yyDollar[4].valExpr, To: yyDollar[6].valExpr}
 		}
-	case 109:
+	case 201:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:656
+		//line sql.y:1153
 		{
 			yyVAL.boolExpr = &IsExpr{Operator: yyDollar[3].str, Expr: yyDollar[1].valExpr}
 		}
-	case 110:
+	case 202:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:660
+		//line sql.y:1157
 		{
 			yyVAL.boolExpr = &ExistsExpr{Subquery: yyDollar[2].subquery}
 		}
-	case 111:
+	case 203:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:666
+		//line sql.y:1163
 		{
 			yyVAL.str = IsNullStr
 		}
-	case 112:
+	case 204:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:670
+		//line sql.y:1167
 		{
 			yyVAL.str = IsNotNullStr
 		}
-	case 113:
+	case 205:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:674
+		//line sql.y:1171
 		{
 			yyVAL.str = IsTrueStr
 		}
-	case 114:
+	case 206:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:678
+		//line sql.y:1175
 		{
 			yyVAL.str = IsNotTrueStr
 		}
-	case 115:
+	case 207:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:682
+		//line sql.y:1179
 		{
 			yyVAL.str = IsFalseStr
 		}
-	case 116:
+	case 208:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:686
+		//line sql.y:1183
 		{
 			yyVAL.str = IsNotFalseStr
 		}
-	case 117:
+	case 209:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:692
+		//line sql.y:1189
 		{
 			yyVAL.str = EqualStr
 		}
-	case 118:
+	case 210:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:696
+		//line sql.y:1193
 		{
 			yyVAL.str = LessThanStr
 		}
-	case 119:
+	case 211:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:700
+		//line sql.y:1197
 		{
 			yyVAL.str = GreaterThanStr
 		}
-	case 120:
+	case 212:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:704
+		//line sql.y:1201
 		{
 			yyVAL.str = LessEqualStr
 		}
-	case 121:
+	case 213:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:708
+		//line sql.y:1205
 		{
 			yyVAL.str = GreaterEqualStr
 		}
-	case 122:
+	case 214:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:712
+		//line sql.y:1209
 		{
 			yyVAL.str = NotEqualStr
 		}
-	case 123:
+	case 215:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:716
+		//line sql.y:1213
 		{
 			yyVAL.str = NullSafeEqualStr
 		}
-	case 124:
+	case 216:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:722
+		//line sql.y:1219
 		{
 			yyVAL.colTuple = ValTuple(yyDollar[2].valExprs)
 		}
-	case 125:
+	case 217:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:726
+		//line sql.y:1223
 		{
 			yyVAL.colTuple = yyDollar[1].subquery
 		}
-	case 126:
+	case 218:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:730
+		//line sql.y:1227
 		{
 			yyVAL.colTuple = ListArg(yyDollar[1].bytes)
 		}
-	case 127:
+	case 219:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:736
+		//line sql.y:1233
 		{
 			yyVAL.subquery = &Subquery{yyDollar[2].selStmt}
 		}
-	case 128:
+	case 220:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:742
+		//line sql.y:1239
 		{
 			yyVAL.valExprs = ValExprs{yyDollar[1].valExpr}
 		}
-	case 129:
+	case 221:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:746
+		//line sql.y:1243
 		{
 			yyVAL.valExprs = append(yyDollar[1].valExprs, yyDollar[3].valExpr)
 		}
-	case 130:
+	case 222:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:752
+		//line sql.y:1249
 		{
 			yyVAL.valExpr = yyDollar[1].valExpr
 		}
-	case 131:
+	case 223:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:756
+		//line sql.y:1253
 		{
 			yyVAL.valExpr = yyDollar[1].colName
 		}
-	case 132:
+	case 224:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:760
+		//line sql.y:1257
 		{
 			yyVAL.valExpr = yyDollar[1].rowTuple
 		}
-	case 133:
+	case 225:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:764
+		//line sql.y:1261
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: BitAndStr, Right: yyDollar[3].valExpr}
 		}
-	case 134:
+	case 226:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:768
+		//line sql.y:1265
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: BitOrStr, Right: yyDollar[3].valExpr}
 		}
-	case 135:
+	case 227:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:772
+		//line sql.y:1269
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: BitXorStr, Right: yyDollar[3].valExpr}
 		}
-	case 136:
+	case 228:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:776
+		//line sql.y:1273
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: PlusStr, Right: yyDollar[3].valExpr}
 		}
-	case 137:
+	case 229:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:780
+		//line sql.y:1277
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: MinusStr, Right: yyDollar[3].valExpr}
 		}
-	case 138:
+	case 230:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:784
+		//line sql.y:1281
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: MultStr, Right: yyDollar[3].valExpr}
 		}
-	case 139:
+	case 231:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:788
+		//line sql.y:1285
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: DivStr, Right: yyDollar[3].valExpr}
 		}
-	case 140:
+	case 232:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:792
+		//line sql.y:1289
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: ModStr, Right: yyDollar[3].valExpr}
 		}
-	case 141:
+	case 233:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:796
+		//line sql.y:1293
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: ShiftLeftStr, Right: yyDollar[3].valExpr}
 		}
-	case 142:
+	case 234:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:800
+		//line sql.y:1297
 		{
 			yyVAL.valExpr = &BinaryExpr{Left: yyDollar[1].valExpr, Operator: ShiftRightStr, Right: yyDollar[3].valExpr}
 		}
-	case 143:
+	case 235:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:804
+		//line sql.y:1301
 		{
 			if num, ok := yyDollar[2].valExpr.(NumVal); ok {
 				yyVAL.valExpr = num
@@ -1754,9 +2521,9 @@ yydefault:
 				yyVAL.valExpr = &UnaryExpr{Operator: UPlusStr, Expr: yyDollar[2].valExpr}
 			}
 		}
-	case 144:
+	case 236:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:812
+		//line sql.y:1309
 		{
 			if num, ok := yyDollar[2].valExpr.(NumVal); ok {
 				// Handle double negative
@@ -1769,15 +2536,15 @@ yydefault:
 				yyVAL.valExpr = &UnaryExpr{Operator: UMinusStr, Expr: yyDollar[2].valExpr}
 			}
 		}
-	case 145:
+	case 237:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:825
+		//line sql.y:1322
 		{
 			yyVAL.valExpr = &UnaryExpr{Operator: TildaStr, Expr: yyDollar[2].valExpr}
 		}
-	case 146:
+	case 238:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:829
+		//line sql.y:1326
 		{
 			// This rule prevents the usage of INTERVAL
 			// as a function. If support is needed for that,
@@ -1785,231 +2552,231 @@ yydefault:
 			// will be non-trivial because of grammar conflicts.
 			yyVAL.valExpr = &IntervalExpr{Expr: yyDollar[2].valExpr, Unit: yyDollar[3].colIdent}
 		}
-	case 147:
+	case 239:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:837
+		//line sql.y:1334
 		{
 			yyVAL.valExpr = &FuncExpr{Name: string(yyDollar[1].tableIdent)}
 		}
-	case 148:
+	case 240:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:841
+		//line sql.y:1338
 		{
 			yyVAL.valExpr = &FuncExpr{Name: string(yyDollar[1].tableIdent), Exprs: yyDollar[3].selectExprs}
 		}
-	case 149:
+	case 241:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:845
+		//line sql.y:1342
 		{
 			yyVAL.valExpr = &FuncExpr{Name: string(yyDollar[1].tableIdent), Distinct: true, Exprs: yyDollar[4].selectExprs}
 		}
-	case 150:
+	case 242:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:849
+		//line sql.y:1346
 		{
 			yyVAL.valExpr = &FuncExpr{Name: "if", Exprs: yyDollar[3].selectExprs}
 		}
-	case 151:
+	case 243:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:853
+		//line sql.y:1350
 		{
 			yyVAL.valExpr = yyDollar[1].caseExpr
 		}
-	case 152:
+	case 244:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:859
+		//line sql.y:1356
 		{
 			yyVAL.caseExpr = &CaseExpr{Expr: yyDollar[2].valExpr, Whens: yyDollar[3].whens, Else: yyDollar[4].valExpr}
 		}
-	case 153:
+	case 245:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:864
+		//line sql.y:1361
 		{
 			yyVAL.valExpr = nil
 		}
-	case 154:
+	case 246:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:868
+		//line sql.y:1365
 		{
 			yyVAL.valExpr = yyDollar[1].valExpr
 		}
-	case 155:
+	case 247:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:874
+		//line sql.y:1371
 		{
 			yyVAL.whens = []*When{yyDollar[1].when}
 		}
-	case 156:
+	case 248:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:878
+		//line sql.y:1375
 		{
 			yyVAL.whens = append(yyDollar[1].whens, yyDollar[2].when)
 		}
-	case 157:
+	case 249:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:884
+		//line sql.y:1381
 		{
 			yyVAL.when = &When{Cond: yyDollar[2].boolExpr, Val: yyDollar[4].valExpr}
 		}
-	case 158:
+	case 250:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:889
+		//line sql.y:1386
 		{
 			yyVAL.valExpr = nil
 		}
-	case 159:
+	case 251:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:893
+		//line sql.y:1390
 		{
 			yyVAL.valExpr = yyDollar[2].valExpr
 		}
-	case 160:
+	case 252:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:899
+		//line sql.y:1396
 		{
 			yyVAL.colName = &ColName{Name: yyDollar[1].colIdent}
 		}
-	case 161:
+	case 253:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:903
+		//line sql.y:1400
 		{
 			yyVAL.colName = &ColName{Qualifier: &TableName{Name: yyDollar[1].tableIdent}, Name: yyDollar[3].colIdent}
 		}
-	case 162:
+	case 254:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:907
+		//line sql.y:1404
 		{
 			yyVAL.colName = &ColName{Qualifier: &TableName{Qualifier: yyDollar[1].tableIdent, Name: yyDollar[3].tableIdent}, Name: yyDollar[5].colIdent}
 		}
-	case 163:
+	case 255:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:913
+		//line sql.y:1410
 		{
 			yyVAL.valExpr = StrVal(yyDollar[1].bytes)
 		}
-	case 164:
+	case 256:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:917
+		//line sql.y:1414
 		{
 			yyVAL.valExpr = NumVal(yyDollar[1].bytes)
 		}
-	case 165:
+	case 257:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:921
+		//line sql.y:1418
 		{
 			yyVAL.valExpr = ValArg(yyDollar[1].bytes)
 		}
-	case 166:
+	case 258:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:925
+		//line sql.y:1422
 		{
 			yyVAL.valExpr = &NullVal{}
 		}
-	case 167:
+	case 259:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:930
+		//line sql.y:1427
 		{
 			yyVAL.valExprs = nil
 		}
-	case 168:
+	case 260:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:934
+		//line sql.y:1431
 		{
 			yyVAL.valExprs = yyDollar[3].valExprs
 		}
-	case 169:
+	case 261:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:939
+		//line sql.y:1436
 		{
 			yyVAL.boolExpr = nil
 		}
-	case 170:
+	case 262:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:943
+		//line sql.y:1440
 		{
 			yyVAL.boolExpr = yyDollar[2].boolExpr
 		}
-	case 171:
+	case 263:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:948
+		//line sql.y:1445
 		{
 			yyVAL.orderBy = nil
 		}
-	case 172:
+	case 264:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:952
+		//line sql.y:1449
 		{
 			yyVAL.orderBy = yyDollar[3].orderBy
 		}
-	case 173:
+	case 265:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:958
+		//line sql.y:1455
 		{
 			yyVAL.orderBy = OrderBy{yyDollar[1].order}
 		}
-	case 174:
+	case 266:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:962
+		//line sql.y:1459
 		{
 			yyVAL.orderBy = append(yyDollar[1].orderBy, yyDollar[3].order)
 		}
-	case 175:
+	case 267:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:968
+		//line sql.y:1465
 		{
 			yyVAL.order = &Order{Expr: yyDollar[1].valExpr, Direction: yyDollar[2].str}
 		}
-	case 176:
+	case 268:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:973
+		//line sql.y:1470
 		{
 			yyVAL.str = AscScr
 		}
-	case 177:
+	case 269:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:977
+		//line sql.y:1474
 		{
 			yyVAL.str = AscScr
 		}
-	case 178:
+	case 270:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:981
+		//line sql.y:1478
 		{
 			yyVAL.str = DescScr
 		}
-	case 179:
+	case 271:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:986
+		//line sql.y:1483
 		{
 			yyVAL.limit = nil
 		}
-	case 180:
+	case 272:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:990
+		//line sql.y:1487
 		{
 			yyVAL.limit = &Limit{Rowcount: yyDollar[2].valExpr}
 		}
-	case 181:
+	case 273:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:994
+		//line sql.y:1491
 		{
 			yyVAL.limit = &Limit{Offset: yyDollar[2].valExpr, Rowcount: yyDollar[4].valExpr}
 		}
-	case 182:
+	case 274:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:999
+		//line sql.y:1496
 		{
 			yyVAL.str = ""
 		}
-	case 183:
+	case 275:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1003
+		//line sql.y:1500
 		{
 			yyVAL.str = ForUpdateStr
 		}
-	case 184:
+	case 276:
 		yyDollar = yyS[yypt-4 : yypt+1]
-		//line sql.y:1007
+		//line sql.y:1504
 		{
 			if yyDollar[3].colIdent.Lowered() != "share" {
 				yylex.Error("expecting share")
@@ -2021,234 +2788,282 @@ yydefault:
 			}
 			yyVAL.str = ShareModeStr
 		}
-	case 185:
+	case 277:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1020
+		//line sql.y:1517
 		{
 			yyVAL.columns = nil
 		}
-	case 186:
+	case 278:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1024
+		//line sql.y:1521
 		{
 			yyVAL.columns = yyDollar[2].columns
 		}
-	case 187:
+	case 279:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1030
+		//line sql.y:1527
 		{
 			yyVAL.columns = Columns{yyDollar[1].colIdent}
 		}
-	case 188:
+	case 280:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1034
+		//line sql.y:1531
 		{
 			yyVAL.columns = append(yyVAL.columns, yyDollar[3].colIdent)
 		}
-	case 189:
+	case 281:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1039
+		//line sql.y:1536
 		{
 			yyVAL.updateExprs = nil
 		}
-	case 190:
+	case 282:
 		yyDollar = yyS[yypt-5 : yypt+1]
-		//line sql.y:1043
+		//line sql.y:1540
 		{
 			yyVAL.updateExprs = yyDollar[5].updateExprs
 		}
-	case 191:
+	case 283:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1049
+		//line sql.y:1546
 		{
 			yyVAL.insRows = yyDollar[2].values
 		}
-	case 192:
+	case 284:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1053
+		//line sql.y:1550
 		{
 			yyVAL.insRows = yyDollar[1].selStmt
 		}
-	case 193:
+	case 285:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1059
+		//line sql.y:1556
 		{
 			yyVAL.values = Values{yyDollar[1].rowTuple}
 		}
-	case 194:
+	case 286:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1063
+		//line sql.y:1560
 		{
 			yyVAL.values = append(yyDollar[1].values, yyDollar[3].rowTuple)
 		}
-	case 195:
+	case 287:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1069
+		//line sql.y:1566
 		{
 			yyVAL.rowTuple = ValTuple(yyDollar[2].valExprs)
 		}
-	case 196:
+	case 288:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1073
+		//line sql.y:1570
 		{
 			yyVAL.rowTuple = yyDollar[1].subquery
 		}
-	case 197:
+	case 289:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1079
+		//line sql.y:1576
 		{
 			yyVAL.updateExprs = UpdateExprs{yyDollar[1].updateExpr}
 		}
-	case 198:
+	case 290:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1083
+		//line sql.y:1580
 		{
 			yyVAL.updateExprs = append(yyDollar[1].updateExprs, yyDollar[3].updateExpr)
 		}
-	case 199:
+	case 291:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1089
+		//line sql.y:1586
 		{
 			yyVAL.updateExpr = &UpdateExpr{Name: yyDollar[1].colIdent, Expr: yyDollar[3].valExpr}
 		}
-	case 202:
+	case 294:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1098
+		//line sql.y:1595
 		{
 			yyVAL.byt = 0
 		}
-	case 203:
+	case 295:
 		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1100
+		//line sql.y:1597
 		{
 			yyVAL.byt = 1
 		}
-	case 204:
+	case 296:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1103
+		//line sql.y:1600
 		{
-			yyVAL.empty = struct{}{}
+			yyVAL.byt = 0
 		}
-	case 205:
+	case 297:
 		yyDollar = yyS[yypt-3 : yypt+1]
-		//line sql.y:1105
+		//line sql.y:1602
 		{
-			yyVAL.empty = struct{}{}
+			yyVAL.byt = 1
 		}
-	case 206:
+	case 298:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1108
+		//line sql.y:1605
 		{
 			yyVAL.str = ""
 		}
-	case 207:
+	case 299:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1110
+		//line sql.y:1607
 		{
 			yyVAL.str = IgnoreStr
 		}
-	case 208:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1114
+	case 300:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:1610
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 209:
+	case 301:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1116
+		//line sql.y:1612
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 210:
+	case 302:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:1615
+		{
+			yyVAL.str = KeyStr
+		}
+	case 303:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1118
+		//line sql.y:1617
 		{
-			yyVAL.empty = struct{}{}
+			yyVAL.str = UniqueKeyStr
 		}
-	case 211:
+	case 304:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1120
+		//line sql.y:1619
 		{
-			yyVAL.empty = struct{}{}
+			yyVAL.str = FulltextKeyStr
 		}
-	case 212:
+	case 305:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1122
+		//line sql.y:1621
+		{
+			yyVAL.str = SpatialKeyStr
+		}
+	case 306:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:1624
+		{
+			yyVAL.str = ""
+		}
+	case 307:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		//line sql.y:1626
+		{
+			yyVAL.str = yyDollar[2].colIdent.Lowered()
+		}
+	case 308:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:1629
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 213:
+	case 309:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1124
+		//line sql.y:1631
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 214:
-		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1127
+	case 310:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1635
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 215:
+	case 311:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1129
+		//line sql.y:1637
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 216:
+	case 312:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1132
+		//line sql.y:1640
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 217:
+	case 313:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1134
+		//line sql.y:1642
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 218:
+	case 314:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1137
+		//line sql.y:1645
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 219:
-		yyDollar = yyS[yypt-2 : yypt+1]
-		//line sql.y:1139
+	case 315:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1647
 		{
 			yyVAL.empty = struct{}{}
 		}
-	case 220:
+	case 316:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1143
+		//line sql.y:1651
 		{
 			yyVAL.colIdent = NewColIdent(string(yyDollar[1].bytes))
 		}
-	case 221:
+	case 317:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1149
+		//line sql.y:1655
+		{
+			yyVAL.colIdent = NewColIdent(string(yyDollar[1].bytes))
+		}
+	case 318:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		//line sql.y:1660
+		{
+			yyVAL.colIdent = ColIdent{}
+		}
+	case 319:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1664
+		{
+			yyVAL.colIdent = yyDollar[1].colIdent
+		}
+	case 320:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1670
 		{
 			yyVAL.tableIdent = TableIdent(yyDollar[1].bytes)
 		}
-	case 222:
+	case 321:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		//line sql.y:1674
+		{
+			yyVAL.tableIdent = TableIdent(yyDollar[1].bytes)
+		}
+	case 329:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1155
+		//line sql.y:1689
 		{
 			if incNesting(yylex) {
 				yylex.Error("max nesting level reached")
 				return 1
 			}
 		}
-	case 223:
+	case 330:
 		yyDollar = yyS[yypt-1 : yypt+1]
-		//line sql.y:1164
+		//line sql.y:1698
 		{
 			decNesting(yylex)
 		}
-	case 224:
+	case 331:
 		yyDollar = yyS[yypt-0 : yypt+1]
-		//line sql.y:1169
+		//line sql.y:1703
 		{
 			forceEOF(yylex)
 		}
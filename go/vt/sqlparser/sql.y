@@ -5,6 +5,8 @@
 %{
 package sqlparser
 
+import "strings"
+
 func setParseTree(yylex interface{}, stmt Statement) {
   yylex.(*Tokenizer).ParseTree = stmt
 }
@@ -29,6 +31,12 @@ func forceEOF(yylex interface{}) {
   yylex.(*Tokenizer).ForceEOF = true
 }
 
+// setDDL records the DDL built from the beginning of a statement.
+// Parse returns it if the rest of the statement can't be parsed.
+func setDDL(yylex interface{}, ddl *DDL) {
+  yylex.(*Tokenizer).partialDDL = ddl
+}
+
 %}
 
 %union {
@@ -67,6 +75,18 @@ func forceEOF(yylex interface{}) {
   colIdent    ColIdent
   colIdents   []ColIdent
   tableIdent  TableIdent
+  strs        []string
+  ddl         *DDL
+  tableSpec   *TableSpec
+  columnDef   *ColumnDefinition
+  columnType  *ColumnType
+  indexDef    *IndexDefinition
+  indexCols   []*IndexColumn
+  indexCol    *IndexColumn
+  tableOpts   []*TableOption
+  tableOpt    *TableOption
+  alterSpecs  []*AlterSpec
+  alterSpec   *AlterSpec
 }
 
 %token LEX_ERROR
@@ -106,6 +126,19 @@ func forceEOF(yylex interface{}) {
 %token <empty> TABLE INDEX VIEW TO IGNORE IF UNIQUE USING
 %token <empty> SHOW DESCRIBE EXPLAIN
 
+// Transaction Tokens
+%token <empty> RELEASE
+%token <empty> ADD CHANGE COLUMN PRIMARY FULLTEXT SPATIAL
+%token <empty> UNSIGNED ZEROFILL CHARACTER COLLATE CURRENT_TIMESTAMP
+
+// MySQL reserved words that are data type names, like int or varchar,
+// map to this token.
+%token <bytes> DATA_TYPE
+
+// Keywords that MySQL doesn't reserve. They can be used as identifiers,
+// see non_reserved_keyword.
+%token <bytes> AFTER AUTO_INCREMENT COMMENT_KEYWORD FIRST MODIFY ROLLBACK SAVEPOINT
+
 // MySQL reserved words that are unused by this grammar will map to this token.
 %token <empty> UNUSED
 
@@ -113,7 +146,7 @@ func forceEOF(yylex interface{}) {
 %type <selStmt> select_statement
 %type <statement> insert_statement update_statement delete_statement set_statement
 %type <statement> create_statement alter_statement rename_statement drop_statement
-%type <statement> analyze_statement other_statement
+%type <statement> analyze_statement other_statement savepoint_statement
 %type <bytes2> comment_opt comment_list
 %type <str> union_op
 %type <str> distinct_opt straight_join_opt
@@ -156,9 +189,26 @@ func forceEOF(yylex interface{}) {
 %type <empty> for_from
 %type <str> ignore_opt
 %type <byt> exists_opt
-%type <empty> not_exists_opt non_rename_operation to_opt constraint_opt using_opt
-%type <colIdent> sql_id as_ci_opt
+%type <byt> not_exists_opt
+%type <empty> to_opt column_opt index_or_key index_or_key_opt equal_opt
+%type <str> constraint_opt using_opt
+%type <colIdent> sql_id sql_id_opt as_ci_opt
 %type <tableIdent> table_id as_opt_id
+%type <bytes> non_reserved_keyword
+%type <ddl> create_table_prefix alter_table_prefix
+%type <tableSpec> table_spec table_column_list
+%type <columnDef> column_definition
+%type <columnType> column_type data_type
+%type <str> type_name charset_name table_option_name
+%type <strs> enum_value_list
+%type <valExpr> default_value current_timestamp
+%type <indexDef> index_definition index_info
+%type <indexCols> index_column_list
+%type <indexCol> index_column
+%type <tableOpts> table_option_list_opt table_option_list
+%type <tableOpt> table_option table_option_value
+%type <alterSpecs> alter_spec_list
+%type <alterSpec> alter_spec column_position_opt
 %type <empty> as_opt
 %type <empty> force_eof
 
@@ -187,6 +237,7 @@ command:
 | drop_statement
 | analyze_statement
 | other_statement
+| savepoint_statement
 
 select_statement:
   SELECT comment_opt distinct_opt straight_join_opt select_expression_list FROM table_references where_expression_opt group_by_opt having_opt order_by_opt limit_opt lock_opt
@@ -241,14 +292,23 @@ set_statement:
   }
 
 create_statement:
-  CREATE TABLE not_exists_opt table_id force_eof
+  create_table_prefix table_spec
+  {
+    $1.TableSpec = $2
+    $$ = $1
+  }
+| CREATE constraint_opt INDEX sql_id using_opt ON table_id
   {
-    $$ = &DDL{Action: CreateStr, NewName: $4}
+    setDDL(yylex, &DDL{Action: AlterStr, Table: $7, NewName: $7})
   }
-| CREATE constraint_opt INDEX ID using_opt ON table_id force_eof
+  '(' index_column_list ')' using_opt
   {
     // Change this to an alter statement
-    $$ = &DDL{Action: AlterStr, Table: $7, NewName: $7}
+    index := &IndexDefinition{Type: $2, Name: $4, Using: $5, Columns: $10}
+    if $12 != "" {
+      index.Using = $12
+    }
+    $$ = &DDL{Action: AlterStr, Table: $7, NewName: $7, AlterSpecs: []*AlterSpec{{Action: AddIndexStr, Index: index}}}
   }
 | CREATE VIEW sql_id force_eof
   {
@@ -256,14 +316,15 @@ create_statement:
   }
 
 alter_statement:
-  ALTER ignore_opt TABLE table_id non_rename_operation force_eof
+  alter_table_prefix alter_spec_list
   {
-    $$ = &DDL{Action: AlterStr, Table: $4, NewName: $4}
+    $1.AlterSpecs = $2
+    $$ = $1
   }
-| ALTER ignore_opt TABLE table_id RENAME to_opt table_id
+| alter_table_prefix RENAME to_opt table_id
   {
     // Change this to a rename statement
-    $$ = &DDL{Action: RenameStr, Table: $4, NewName: $7}
+    $$ = &DDL{Action: RenameStr, Table: $1.Table, NewName: $4}
   }
 | ALTER VIEW sql_id force_eof
   {
@@ -285,10 +346,10 @@ drop_statement:
     }
     $$ = &DDL{Action: DropStr, Table: $4, IfExists: exists}
   }
-| DROP INDEX ID ON table_id
+| DROP INDEX sql_id ON table_id
   {
     // Change this to an alter statement
-    $$ = &DDL{Action: AlterStr, Table: $5, NewName: $5}
+    $$ = &DDL{Action: AlterStr, Table: $5, NewName: $5, AlterSpecs: []*AlterSpec{{Action: DropIndexStr, Name: $3}}}
   }
 | DROP VIEW exists_opt sql_id force_eof
   {
@@ -305,6 +366,424 @@ analyze_statement:
     $$ = &DDL{Action: AlterStr, Table: $3, NewName: $3}
   }
 
+create_table_prefix:
+  CREATE TABLE not_exists_opt table_id
+  {
+    $$ = &DDL{Action: CreateStr, NewName: $4, IfNotExists: $3 != 0}
+    setDDL(yylex, $$)
+  }
+
+table_spec:
+  '(' table_column_list ')' table_option_list_opt
+  {
+    $$ = $2
+    $$.Options = $4
+  }
+
+table_column_list:
+  column_definition
+  {
+    $$ = &TableSpec{Columns: []*ColumnDefinition{$1}}
+  }
+| index_definition
+  {
+    $$ = &TableSpec{Indexes: []*IndexDefinition{$1}}
+  }
+| table_column_list ',' column_definition
+  {
+    $$.Columns = append($$.Columns, $3)
+  }
+| table_column_list ',' index_definition
+  {
+    $$.Indexes = append($$.Indexes, $3)
+  }
+
+column_definition:
+  sql_id column_type
+  {
+    $$ = &ColumnDefinition{Name: $1, Type: $2}
+  }
+
+// column_type is a data type followed by the column attributes,
+// which MySQL accepts in any order.
+column_type:
+  data_type
+  {
+    $$ = $1
+  }
+| column_type UNSIGNED
+  {
+    $$.Unsigned = true
+  }
+| column_type ZEROFILL
+  {
+    $$.Zerofill = true
+  }
+| column_type CHARACTER SET charset_name
+  {
+    $$.Charset = $4
+  }
+| column_type COLLATE charset_name
+  {
+    $$.Collate = $3
+  }
+| column_type NULL
+  {
+    $$.NotNull = false
+    $$.Null = true
+  }
+| column_type NOT NULL
+  {
+    $$.NotNull = true
+    $$.Null = false
+  }
+| column_type DEFAULT default_value
+  {
+    $$.Default = $3
+  }
+| column_type ON UPDATE current_timestamp
+  {
+    $$.OnUpdate = $4
+  }
+| column_type AUTO_INCREMENT
+  {
+    $$.Autoincrement = true
+  }
+| column_type PRIMARY KEY
+  {
+    $$.KeyOpt = PrimaryKeyStr
+  }
+| column_type UNIQUE
+  {
+    $$.KeyOpt = UniqueKeyStr
+  }
+| column_type UNIQUE KEY
+  {
+    $$.KeyOpt = UniqueKeyStr
+  }
+| column_type COMMENT_KEYWORD STRING
+  {
+    $$.Comment = StrVal($3)
+  }
+
+data_type:
+  type_name
+  {
+    $$ = &ColumnType{Type: $1}
+  }
+| type_name '(' NUMBER ')'
+  {
+    $$ = &ColumnType{Type: $1, Length: NumVal($3)}
+  }
+| type_name '(' NUMBER ',' NUMBER ')'
+  {
+    $$ = &ColumnType{Type: $1, Length: NumVal($3), Scale: NumVal($5)}
+  }
+| type_name '(' enum_value_list ')'
+  {
+    $$ = &ColumnType{Type: $1, EnumValues: $3}
+  }
+
+// type_name accepts any identifier, so the types that MySQL doesn't
+// reserve, like text or datetime, are covered.
+type_name:
+  ID
+  {
+    $$ = strings.ToLower(string($1))
+  }
+| DATA_TYPE
+  {
+    $$ = string($1)
+  }
+| SET
+  {
+    $$ = "set"
+  }
+
+enum_value_list:
+  STRING
+  {
+    $$ = []string{string($1)}
+  }
+| enum_value_list ',' STRING
+  {
+    $$ = append($$, string($3))
+  }
+
+charset_name:
+  sql_id
+  {
+    $$ = $1.String()
+  }
+| STRING
+  {
+    $$ = string($1)
+  }
+| DATA_TYPE
+  {
+    if string($1) != "binary" {
+      yylex.Error("expecting charset name")
+      return 1
+    }
+    $$ = string($1)
+  }
+
+default_value:
+  STRING
+  {
+    $$ = StrVal($1)
+  }
+| NUMBER
+  {
+    $$ = NumVal($1)
+  }
+| '-' NUMBER
+  {
+    $$ = NumVal(append([]byte("-"), $2...))
+  }
+| NULL
+  {
+    $$ = &NullVal{}
+  }
+| current_timestamp
+  {
+    $$ = $1
+  }
+
+current_timestamp:
+  CURRENT_TIMESTAMP
+  {
+    $$ = &FuncExpr{Name: "current_timestamp"}
+  }
+| CURRENT_TIMESTAMP '(' ')'
+  {
+    $$ = &FuncExpr{Name: "current_timestamp"}
+  }
+| CURRENT_TIMESTAMP '(' NUMBER ')'
+  {
+    $$ = &FuncExpr{Name: "current_timestamp", Exprs: SelectExprs{&NonStarExpr{Expr: NumVal($3)}}}
+  }
+
+index_definition:
+  index_info '(' index_column_list ')' using_opt
+  {
+    $$ = $1
+    $$.Columns = $3
+    if $5 != "" {
+      $$.Using = $5
+    }
+  }
+| index_definition COMMENT_KEYWORD STRING
+  {
+    $$.Comment = StrVal($3)
+  }
+
+index_info:
+  PRIMARY KEY using_opt
+  {
+    $$ = &IndexDefinition{Type: PrimaryKeyStr, Using: $3}
+  }
+| UNIQUE index_or_key_opt sql_id_opt using_opt
+  {
+    $$ = &IndexDefinition{Type: UniqueKeyStr, Name: $3, Using: $4}
+  }
+| index_or_key sql_id_opt using_opt
+  {
+    $$ = &IndexDefinition{Type: KeyStr, Name: $2, Using: $3}
+  }
+| FULLTEXT index_or_key_opt sql_id_opt
+  {
+    $$ = &IndexDefinition{Type: FulltextKeyStr, Name: $3}
+  }
+| SPATIAL index_or_key_opt sql_id_opt
+  {
+    $$ = &IndexDefinition{Type: SpatialKeyStr, Name: $3}
+  }
+
+index_column_list:
+  index_column
+  {
+    $$ = []*IndexColumn{$1}
+  }
+| index_column_list ',' index_column
+  {
+    $$ = append($$, $3)
+  }
+
+index_column:
+  sql_id
+  {
+    $$ = &IndexColumn{Column: $1}
+  }
+| sql_id '(' NUMBER ')'
+  {
+    $$ = &IndexColumn{Column: $1, Length: NumVal($3)}
+  }
+
+table_option_list_opt:
+  {
+    $$ = nil
+  }
+| table_option_list
+  {
+    $$ = $1
+  }
+
+table_option_list:
+  table_option
+  {
+    $$ = []*TableOption{$1}
+  }
+| table_option_list table_option
+  {
+    $$ = append($$, $2)
+  }
+| table_option_list ',' table_option
+  {
+    $$ = append($$, $3)
+  }
+
+table_option:
+  table_option_name equal_opt table_option_value
+  {
+    $$ = $3
+    $$.Name = $1
+  }
+
+table_option_name:
+  ID
+  {
+    $$ = strings.ToLower(string($1))
+  }
+| AUTO_INCREMENT
+  {
+    $$ = "auto_increment"
+  }
+| COMMENT_KEYWORD
+  {
+    $$ = "comment"
+  }
+| CHARACTER SET
+  {
+    $$ = "character set"
+  }
+| COLLATE
+  {
+    $$ = "collate"
+  }
+| DEFAULT ID
+  {
+    $$ = "default " + strings.ToLower(string($2))
+  }
+| DEFAULT CHARACTER SET
+  {
+    $$ = "default character set"
+  }
+| DEFAULT COLLATE
+  {
+    $$ = "default collate"
+  }
+
+table_option_value:
+  ID
+  {
+    $$ = &TableOption{Value: string($1)}
+  }
+| DATA_TYPE
+  {
+    $$ = &TableOption{Value: string($1)}
+  }
+| STRING
+  {
+    $$ = &TableOption{Value: string($1), Quoted: true}
+  }
+| NUMBER
+  {
+    $$ = &TableOption{Value: string($1)}
+  }
+
+alter_table_prefix:
+  ALTER ignore_opt TABLE table_id
+  {
+    $$ = &DDL{Action: AlterStr, Table: $4, NewName: $4}
+    setDDL(yylex, $$)
+  }
+
+alter_spec_list:
+  alter_spec
+  {
+    $$ = []*AlterSpec{$1}
+  }
+| alter_spec_list ',' alter_spec
+  {
+    $$ = append($$, $3)
+  }
+
+alter_spec:
+  ADD column_opt column_definition column_position_opt
+  {
+    $$ = $4
+    $$.Action = AddColumnStr
+    $$.Column = $3
+  }
+| ADD index_definition
+  {
+    $$ = &AlterSpec{Action: AddIndexStr, Index: $2}
+  }
+| DROP column_opt sql_id
+  {
+    $$ = &AlterSpec{Action: DropColumnStr, Name: $3}
+  }
+| DROP index_or_key sql_id
+  {
+    $$ = &AlterSpec{Action: DropIndexStr, Name: $3}
+  }
+| DROP PRIMARY KEY
+  {
+    $$ = &AlterSpec{Action: DropPrimaryKeyStr}
+  }
+| CHANGE column_opt sql_id column_definition column_position_opt
+  {
+    $$ = $5
+    $$.Action = ChangeColumnStr
+    $$.Name = $3
+    $$.Column = $4
+  }
+| MODIFY column_opt column_definition column_position_opt
+  {
+    $$ = $4
+    $$.Action = ModifyColumnStr
+    $$.Column = $3
+  }
+| ALTER column_opt sql_id SET DEFAULT default_value
+  {
+    $$ = &AlterSpec{Action: AlterColumnStr, Name: $3, Default: $6}
+  }
+| ALTER column_opt sql_id DROP DEFAULT
+  {
+    $$ = &AlterSpec{Action: AlterColumnStr, Name: $3}
+  }
+| table_option_name '=' table_option_value
+  {
+    // The '=' is required here, so that unsupported operations
+    // like DISABLE KEYS are not parsed as table options.
+    $3.Name = $1
+    $$ = &AlterSpec{Action: TableOptionStr, Option: $3}
+  }
+
+column_position_opt:
+  {
+    $$ = &AlterSpec{}
+  }
+| FIRST
+  {
+    $$ = &AlterSpec{First: true}
+  }
+| AFTER sql_id
+  {
+    $$ = &AlterSpec{After: $2}
+  }
+
 other_statement:
   SHOW force_eof
   {
@@ -319,6 +798,24 @@ other_statement:
     $$ = &Other{}
   }
 
+savepoint_statement:
+  SAVEPOINT sql_id
+  {
+    $$ = &Savepoint{Action: SavepointStr, Name: $2}
+  }
+| ROLLBACK TO sql_id
+  {
+    $$ = &Savepoint{Action: RollbackToStr, Name: $3}
+  }
+| ROLLBACK TO SAVEPOINT sql_id
+  {
+    $$ = &Savepoint{Action: RollbackToStr, Name: $4}
+  }
+| RELEASE SAVEPOINT sql_id
+  {
+    $$ = &Savepoint{Action: ReleaseStr, Name: $3}
+  }
+
 comment_opt:
   {
     setAllowComments(yylex, true)
@@ -1100,42 +1597,53 @@ exists_opt:
   { $$ = 1 }
 
 not_exists_opt:
-  { $$ = struct{}{} }
+  { $$ = 0 }
 | IF NOT EXISTS
-  { $$ = struct{}{} }
+  { $$ = 1 }
 
 ignore_opt:
   { $$ = "" }
 | IGNORE
   { $$ = IgnoreStr }
 
-non_rename_operation:
-  ALTER
-  { $$ = struct{}{} }
-| DEFAULT
-  { $$ = struct{}{} }
-| DROP
+to_opt:
   { $$ = struct{}{} }
-| ORDER
+| TO
   { $$ = struct{}{} }
-| UNUSED
+
+constraint_opt:
+  { $$ = KeyStr }
+| UNIQUE
+  { $$ = UniqueKeyStr }
+| FULLTEXT
+  { $$ = FulltextKeyStr }
+| SPATIAL
+  { $$ = SpatialKeyStr }
+
+using_opt:
+  { $$ = "" }
+| USING sql_id
+  { $$ = $2.Lowered() }
+
+column_opt:
   { $$ = struct{}{} }
-| ID
+| COLUMN
   { $$ = struct{}{} }
 
-to_opt:
+index_or_key:
+  INDEX
   { $$ = struct{}{} }
-| TO
+| KEY
   { $$ = struct{}{} }
 
-constraint_opt:
+index_or_key_opt:
   { $$ = struct{}{} }
-| UNIQUE
+| index_or_key
   { $$ = struct{}{} }
 
-using_opt:
+equal_opt:
   { $$ = struct{}{} }
-| USING sql_id
+| '='
   { $$ = struct{}{} }
 
 sql_id:
@@ -1143,12 +1651,38 @@ sql_id:
   {
     $$ = NewColIdent(string($1))
   }
+| non_reserved_keyword
+  {
+    $$ = NewColIdent(string($1))
+  }
+
+sql_id_opt:
+  {
+    $$ = ColIdent{}
+  }
+| sql_id
+  {
+    $$ = $1
+  }
 
 table_id:
   ID
   {
     $$ = TableIdent($1)
   }
+| non_reserved_keyword
+  {
+    $$ = TableIdent($1)
+  }
+
+non_reserved_keyword:
+  AFTER
+| AUTO_INCREMENT
+| COMMENT_KEYWORD
+| FIRST
+| MODIFY
+| ROLLBACK
+| SAVEPOINT
 
 openb:
   '('
@@ -26,6 +26,7 @@ type Tokenizer struct {
 	LastError     string
 	posVarIndex   int
 	ParseTree     Statement
+	partialDDL    *DDL
 	nesting       int
 }
 
@@ -37,7 +38,7 @@ func NewStringTokenizer(sql string) *Tokenizer {
 
 var keywords = map[string]int{
 	"accessible":          UNUSED,
-	"add":                 UNUSED,
+	"add":                 ADD,
 	"all":                 ALL,
 	"alter":               ALTER,
 	"analyze":             ANALYZE,
@@ -47,20 +48,20 @@ var keywords = map[string]int{
 	"asensitive":          UNUSED,
 	"before":              UNUSED,
 	"between":             BETWEEN,
-	"bigint":              UNUSED,
-	"binary":              UNUSED,
-	"blob":                UNUSED,
+	"bigint":              DATA_TYPE,
+	"binary":              DATA_TYPE,
+	"blob":                DATA_TYPE,
 	"both":                UNUSED,
 	"by":                  BY,
 	"call":                UNUSED,
 	"cascade":             UNUSED,
 	"case":                CASE,
-	"change":              UNUSED,
-	"char":                UNUSED,
-	"character":           UNUSED,
+	"change":              CHANGE,
+	"char":                DATA_TYPE,
+	"character":           CHARACTER,
 	"check":               UNUSED,
-	"collate":             UNUSED,
-	"column":              UNUSED,
+	"collate":             COLLATE,
+	"column":              COLUMN,
 	"condition":           UNUSED,
 	"constraint":          UNUSED,
 	"continue":            UNUSED,
@@ -69,7 +70,7 @@ var keywords = map[string]int{
 	"cross":               CROSS,
 	"current_date":        UNUSED,
 	"current_time":        UNUSED,
-	"current_timestamp":   UNUSED,
+	"current_timestamp":   CURRENT_TIMESTAMP,
 	"current_user":        UNUSED,
 	"cursor":              UNUSED,
 	"database":            UNUSED,
@@ -78,8 +79,8 @@ var keywords = map[string]int{
 	"day_microsecond":     UNUSED,
 	"day_minute":          UNUSED,
 	"day_second":          UNUSED,
-	"dec":                 UNUSED,
-	"decimal":             UNUSED,
+	"dec":                 DATA_TYPE,
+	"decimal":             DATA_TYPE,
 	"declare":             UNUSED,
 	"default":             DEFAULT,
 	"delayed":             UNUSED,
@@ -90,7 +91,7 @@ var keywords = map[string]int{
 	"distinct":            DISTINCT,
 	"distinctrow":         UNUSED,
 	"div":                 UNUSED,
-	"double":              UNUSED,
+	"double":              DATA_TYPE,
 	"drop":                DROP,
 	"duplicate":           DUPLICATE,
 	"each":                UNUSED,
@@ -104,14 +105,14 @@ var keywords = map[string]int{
 	"explain":             EXPLAIN,
 	"false":               FALSE,
 	"fetch":               UNUSED,
-	"float":               UNUSED,
-	"float4":              UNUSED,
-	"float8":              UNUSED,
+	"float":               DATA_TYPE,
+	"float4":              DATA_TYPE,
+	"float8":              DATA_TYPE,
 	"for":                 FOR,
 	"force":               FORCE,
 	"foreign":             UNUSED,
 	"from":                FROM,
-	"fulltext":            UNUSED,
+	"fulltext":            FULLTEXT,
 	"generated":           UNUSED,
 	"get":                 UNUSED,
 	"grant":               UNUSED,
@@ -130,13 +131,13 @@ var keywords = map[string]int{
 	"inner":               INNER,
 	"insensitive":         UNUSED,
 	"insert":              INSERT,
-	"int":                 UNUSED,
-	"int1":                UNUSED,
-	"int2":                UNUSED,
-	"int3":                UNUSED,
-	"int4":                UNUSED,
-	"int8":                UNUSED,
-	"integer":             UNUSED,
+	"int":                 DATA_TYPE,
+	"int1":                DATA_TYPE,
+	"int2":                DATA_TYPE,
+	"int3":                DATA_TYPE,
+	"int4":                DATA_TYPE,
+	"int8":                DATA_TYPE,
+	"integer":             DATA_TYPE,
 	"interval":            INTERVAL,
 	"into":                INTO,
 	"io_after_gtids":      UNUSED,
@@ -158,18 +159,18 @@ var keywords = map[string]int{
 	"localtime":           UNUSED,
 	"localtimestamp":      UNUSED,
 	"lock":                LOCK,
-	"long":                UNUSED,
-	"longblob":            UNUSED,
-	"longtext":            UNUSED,
+	"long":                DATA_TYPE,
+	"longblob":            DATA_TYPE,
+	"longtext":            DATA_TYPE,
 	"loop":                UNUSED,
 	"low_priority":        UNUSED,
 	"master_bind":         UNUSED,
 	"match":               UNUSED,
 	"maxvalue":            UNUSED,
-	"mediumblob":          UNUSED,
-	"mediumint":           UNUSED,
-	"mediumtext":          UNUSED,
-	"middleint":           UNUSED,
+	"mediumblob":          DATA_TYPE,
+	"mediumint":           DATA_TYPE,
+	"mediumtext":          DATA_TYPE,
+	"middleint":           DATA_TYPE,
 	"minute_microsecond":  UNUSED,
 	"minute_second":       UNUSED,
 	"mod":                 UNUSED,
@@ -179,7 +180,7 @@ var keywords = map[string]int{
 	"not":                 NOT,
 	"no_write_to_binlog":  UNUSED,
 	"null":                NULL,
-	"numeric":             UNUSED,
+	"numeric":             DATA_TYPE,
 	"on":                  ON,
 	"optimize":            UNUSED,
 	"optimizer_costs":     UNUSED,
@@ -192,16 +193,16 @@ var keywords = map[string]int{
 	"outfile":             UNUSED,
 	"partition":           UNUSED,
 	"precision":           UNUSED,
-	"primary":             UNUSED,
+	"primary":             PRIMARY,
 	"procedure":           UNUSED,
 	"range":               UNUSED,
 	"read":                UNUSED,
 	"reads":               UNUSED,
 	"read_write":          UNUSED,
-	"real":                UNUSED,
+	"real":                DATA_TYPE,
 	"references":          UNUSED,
 	"regexp":              REGEXP,
-	"release":             UNUSED,
+	"release":             RELEASE,
 	"rename":              RENAME,
 	"repeat":              UNUSED,
 	"replace":             UNUSED,
@@ -221,8 +222,8 @@ var keywords = map[string]int{
 	"set":                 SET,
 	"show":                SHOW,
 	"signal":              UNUSED,
-	"smallint":            UNUSED,
-	"spatial":             UNUSED,
+	"smallint":            DATA_TYPE,
+	"spatial":             SPATIAL,
 	"specific":            UNUSED,
 	"sql":                 UNUSED,
 	"sqlexception":        UNUSED,
@@ -238,9 +239,9 @@ var keywords = map[string]int{
 	"table":               TABLE,
 	"terminated":          UNUSED,
 	"then":                THEN,
-	"tinyblob":            UNUSED,
-	"tinyint":             UNUSED,
-	"tinytext":            UNUSED,
+	"tinyblob":            DATA_TYPE,
+	"tinyint":             DATA_TYPE,
+	"tinytext":            DATA_TYPE,
 	"to":                  TO,
 	"trailing":            UNUSED,
 	"trigger":             UNUSED,
@@ -249,7 +250,7 @@ var keywords = map[string]int{
 	"union":               UNION,
 	"unique":              UNIQUE,
 	"unlock":              UNUSED,
-	"unsigned":            UNUSED,
+	"unsigned":            UNSIGNED,
 	"update":              UPDATE,
 	"usage":               UNUSED,
 	"use":                 USE,
@@ -258,9 +259,9 @@ var keywords = map[string]int{
 	"utc_time":            UNUSED,
 	"utc_timestamp":       UNUSED,
 	"values":              VALUES,
-	"varbinary":           UNUSED,
-	"varchar":             UNUSED,
-	"varcharacter":        UNUSED,
+	"varbinary":           DATA_TYPE,
+	"varchar":             DATA_TYPE,
+	"varcharacter":        DATA_TYPE,
 	"varying":             UNUSED,
 	"virtual":             UNUSED,
 	"view":                VIEW,
@@ -271,7 +272,20 @@ var keywords = map[string]int{
 	"write":               UNUSED,
 	"xor":                 UNUSED,
 	"year_month":          UNUSED,
-	"zerofill":            UNUSED,
+	"zerofill":            ZEROFILL,
+}
+
+// nonReservedKeywords are the keywords used by the DDL grammar that
+// MySQL doesn't reserve. They can still be used as unquoted
+// identifiers, see the non_reserved_keyword rule.
+var nonReservedKeywords = map[string]int{
+	"after":          AFTER,
+	"auto_increment": AUTO_INCREMENT,
+	"comment":        COMMENT_KEYWORD,
+	"first":          FIRST,
+	"modify":         MODIFY,
+	"rollback":       ROLLBACK,
+	"savepoint":      SAVEPOINT,
 }
 
 // Lex returns the next token form the Tokenizer.
@@ -285,7 +299,8 @@ func (tkn *Tokenizer) Lex(lval *yySymType) int {
 		typ, val = tkn.Scan()
 	}
 	switch typ {
-	case ID, STRING, NUMBER, VALUE_ARG, LIST_ARG, COMMENT:
+	case ID, STRING, NUMBER, VALUE_ARG, LIST_ARG, COMMENT, DATA_TYPE,
+		AFTER, AUTO_INCREMENT, COMMENT_KEYWORD, FIRST, MODIFY, ROLLBACK, SAVEPOINT:
 		lval.bytes = val
 	}
 	tkn.lastToken = val
@@ -421,6 +436,9 @@ func (tkn *Tokenizer) scanIdentifier() (int, []byte) {
 	if keywordID, found := keywords[loweredStr]; found {
 		return keywordID, lowered
 	}
+	if keywordID, found := nonReservedKeywords[loweredStr]; found {
+		return keywordID, buffer.Bytes()
+	}
 	// dual must always be case-insensitive
 	if loweredStr == "dual" {
 		return ID, lowered
@@ -0,0 +1,166 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tableacl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/tableacl/acl"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+// Restrictions are the column denies and the row filters of a table
+// group. They restrict what the principals they list can read from
+// the tables of the group, on top of the READER role.
+type Restrictions struct {
+	columnDenies []columnDeny
+	rowFilters   []rowFilter
+}
+
+type columnDeny struct {
+	// columns are lower cased.
+	columns    []string
+	principals acl.ACL
+	mask       bool
+}
+
+type rowFilter struct {
+	principals acl.ACL
+	predicate  sqlparser.BoolExpr
+}
+
+// newRestrictions returns the Restrictions of a table group, or nil
+// if it has none.
+func newRestrictions(group *tableaclpb.TableGroupSpec) (*Restrictions, error) {
+	if len(group.ColumnDenies) == 0 && len(group.RowFilters) == 0 {
+		return nil, nil
+	}
+	r := &Restrictions{}
+	for _, spec := range group.ColumnDenies {
+		principals, err := newACL(spec.Principals)
+		if err != nil {
+			return nil, err
+		}
+		columns := make([]string, 0, len(spec.Columns))
+		for _, column := range spec.Columns {
+			columns = append(columns, strings.ToLower(column))
+		}
+		r.columnDenies = append(r.columnDenies, columnDeny{
+			columns:    columns,
+			principals: principals,
+			mask:       spec.Mask,
+		})
+	}
+	for _, spec := range group.RowFilters {
+		principals, err := newACL(spec.Principals)
+		if err != nil {
+			return nil, err
+		}
+		predicate, err := parsePredicate(spec.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		r.rowFilters = append(r.rowFilters, rowFilter{
+			principals: principals,
+			predicate:  predicate,
+		})
+	}
+	return r, nil
+}
+
+// validateRestrictions returns an error if the column denies or the
+// row filters of a table group are invalid.
+func validateRestrictions(group *tableaclpb.TableGroupSpec) error {
+	for _, spec := range group.ColumnDenies {
+		if len(spec.Columns) == 0 {
+			return fmt.Errorf("table group %q: column deny without columns", group.Name)
+		}
+	}
+	for _, spec := range group.RowFilters {
+		if _, err := parsePredicate(spec.Predicate); err != nil {
+			return fmt.Errorf("table group %q: %v", group.Name, err)
+		}
+	}
+	return nil
+}
+
+// parsePredicate parses the predicate of a row filter. It must be a
+// plain boolean expression, without bind variables.
+func parsePredicate(predicate string) (sqlparser.BoolExpr, error) {
+	if strings.TrimSpace(predicate) == "" {
+		return nil, fmt.Errorf("empty row filter predicate")
+	}
+	stmt, err := sqlparser.Parse("select 1 from t where " + predicate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid row filter predicate %q: %v", predicate, err)
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Where == nil || sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Lock != "" {
+		return nil, fmt.Errorf("invalid row filter predicate %q: not a boolean expression", predicate)
+	}
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node.(type) {
+		case sqlparser.ValArg, sqlparser.ListArg:
+			return false, fmt.Errorf("invalid row filter predicate %q: bind variables are not allowed", predicate)
+		}
+		return true, nil
+	}, sel.Where.Expr)
+	if err != nil {
+		return nil, err
+	}
+	return sel.Where.Expr, nil
+}
+
+// DeniedColumns returns the lower cased columns principal may not
+// read. The value tells if the column must be masked, rather than
+// rejected. A column both masked and rejected is rejected.
+func (r *Restrictions) DeniedColumns(principal string) map[string]bool {
+	if r == nil {
+		return nil
+	}
+	var denied map[string]bool
+	for _, cd := range r.columnDenies {
+		if !cd.principals.IsMember(principal) {
+			continue
+		}
+		if denied == nil {
+			denied = make(map[string]bool)
+		}
+		for _, column := range cd.columns {
+			if mask, ok := denied[column]; ok && !mask {
+				continue
+			}
+			denied[column] = cd.mask
+		}
+	}
+	return denied
+}
+
+// RowFilter returns the predicate restricting the rows principal can
+// read, or nil. If several row filters list principal, the returned
+// predicate is the conjunction of theirs.
+func (r *Restrictions) RowFilter(principal string) sqlparser.BoolExpr {
+	if r == nil {
+		return nil
+	}
+	var predicate sqlparser.BoolExpr
+	for _, rf := range r.rowFilters {
+		if !rf.principals.IsMember(principal) {
+			continue
+		}
+		if predicate == nil {
+			predicate = &sqlparser.ParenBoolExpr{Expr: rf.predicate}
+			continue
+		}
+		predicate = &sqlparser.AndExpr{
+			Left:  predicate,
+			Right: &sqlparser.ParenBoolExpr{Expr: rf.predicate},
+		}
+	}
+	return predicate
+}
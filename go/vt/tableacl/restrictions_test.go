@@ -0,0 +1,132 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tableacl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/tableacl/simpleacl"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+func TestRestrictions(t *testing.T) {
+	setUpTableACL(&simpleacl.Factory{})
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group01",
+			TableNamesOrPrefixes: []string{"users"},
+			Readers:              []string{"u1", "u2", "u3"},
+			ColumnDenies: []*tableaclpb.ColumnDenySpec{{
+				Columns:    []string{"SSN", "salary"},
+				Principals: []string{"u1", "u2"},
+				Mask:       true,
+			}, {
+				Columns:    []string{"salary"},
+				Principals: []string{"u2"},
+			}},
+			RowFilters: []*tableaclpb.RowFilterSpec{{
+				Principals: []string{"u1", "u2"},
+				Predicate:  "region = 'us'",
+			}, {
+				Principals: []string{"u2"},
+				Predicate:  "deleted = 0 or admin = 1",
+			}},
+		}, {
+			Name:                 "group02",
+			TableNamesOrPrefixes: []string{"orders"},
+			Readers:              []string{"u1"},
+		}},
+	}
+	if err := InitFromProto(config); err != nil {
+		t.Fatalf("InitFromProto failed: %v", err)
+	}
+
+	restrictions := Authorized("users", READER).Restrictions
+	if restrictions == nil {
+		t.Fatalf("users has no restrictions")
+	}
+	if got := Authorized("orders", READER).Restrictions; got != nil {
+		t.Errorf("orders restrictions: %v, want nil", got)
+	}
+
+	testcases := []struct {
+		principal string
+		columns   map[string]bool
+		rowFilter string
+	}{{
+		principal: "u1",
+		columns:   map[string]bool{"ssn": true, "salary": true},
+		rowFilter: " where (region = 'us')",
+	}, {
+		principal: "u2",
+		columns:   map[string]bool{"ssn": true, "salary": false},
+		rowFilter: " where (region = 'us') and (deleted = 0 or admin = 1)",
+	}, {
+		principal: "u3",
+	}}
+	for _, tcase := range testcases {
+		if got := restrictions.DeniedColumns(tcase.principal); !reflect.DeepEqual(got, tcase.columns) {
+			t.Errorf("DeniedColumns(%v): %v, want %v", tcase.principal, got, tcase.columns)
+		}
+		got := sqlparser.String(sqlparser.NewWhere(sqlparser.WhereStr, restrictions.RowFilter(tcase.principal)))
+		if got != tcase.rowFilter {
+			t.Errorf("RowFilter(%v): %q, want %q", tcase.principal, got, tcase.rowFilter)
+		}
+	}
+}
+
+func TestValidateRestrictions(t *testing.T) {
+	testcases := []struct {
+		group *tableaclpb.TableGroupSpec
+		err   string
+	}{{
+		group: &tableaclpb.TableGroupSpec{
+			ColumnDenies: []*tableaclpb.ColumnDenySpec{{Principals: []string{"u1"}}},
+		},
+		err: "column deny without columns",
+	}, {
+		group: &tableaclpb.TableGroupSpec{
+			RowFilters: []*tableaclpb.RowFilterSpec{{Principals: []string{"u1"}}},
+		},
+		err: "empty row filter predicate",
+	}, {
+		group: &tableaclpb.TableGroupSpec{
+			RowFilters: []*tableaclpb.RowFilterSpec{{Predicate: "a = "}},
+		},
+		err: "invalid row filter predicate",
+	}, {
+		group: &tableaclpb.TableGroupSpec{
+			RowFilters: []*tableaclpb.RowFilterSpec{{Predicate: "a = 1 order by b"}},
+		},
+		err: "not a boolean expression",
+	}, {
+		group: &tableaclpb.TableGroupSpec{
+			RowFilters: []*tableaclpb.RowFilterSpec{{Predicate: "a = :a"}},
+		},
+		err: "bind variables are not allowed",
+	}, {
+		group: &tableaclpb.TableGroupSpec{
+			ColumnDenies: []*tableaclpb.ColumnDenySpec{{Columns: []string{"ssn"}}},
+			RowFilters:   []*tableaclpb.RowFilterSpec{{Predicate: "a = 1 and b in (1, 2)"}},
+		},
+	}}
+	for _, tcase := range testcases {
+		config := &tableaclpb.Config{TableGroups: []*tableaclpb.TableGroupSpec{tcase.group}}
+		err := ValidateProto(config)
+		if tcase.err == "" {
+			if err != nil {
+				t.Errorf("ValidateProto(%v) failed: %v", tcase.group, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("ValidateProto(%v): %v, must contain %v", tcase.group, err, tcase.err)
+		}
+	}
+}
@@ -26,12 +26,16 @@ import (
 type ACLResult struct {
 	acl.ACL
 	GroupName string
+	// Restrictions are the column and row restrictions of the table
+	// group, nil if it has none.
+	Restrictions *Restrictions
 }
 
 type aclEntry struct {
 	tableNameOrPrefix string
 	groupName         string
 	acl               map[Role]acl.ACL
+	restrictions      *Restrictions
 }
 
 type aclEntries []aclEntry
@@ -133,6 +137,10 @@ func load(config *tableaclpb.Config) error {
 		if err != nil {
 			return err
 		}
+		restrictions, err := newRestrictions(group)
+		if err != nil {
+			return err
+		}
 		for _, tableNameOrPrefix := range group.TableNamesOrPrefixes {
 			entries = append(entries, aclEntry{
 				tableNameOrPrefix: tableNameOrPrefix,
@@ -142,6 +150,7 @@ func load(config *tableaclpb.Config) error {
 					WRITER: writers,
 					ADMIN:  admins,
 				},
+				restrictions: restrictions,
 			})
 		}
 	}
@@ -161,6 +170,9 @@ func load(config *tableaclpb.Config) error {
 func ValidateProto(config *tableaclpb.Config) (err error) {
 	t := patricia.NewTrie()
 	for _, group := range config.TableGroups {
+		if err := validateRestrictions(group); err != nil {
+			return err
+		}
 		for _, name := range group.TableNamesOrPrefixes {
 			var prefix patricia.Prefix
 			if strings.HasSuffix(name, "%") {
@@ -186,6 +198,91 @@ func ValidateProto(config *tableaclpb.Config) (err error) {
 	return nil
 }
 
+// DiffConfigs returns a human-readable description of the changes
+// between two configs, one line per change. The table groups are
+// matched by name.
+func DiffConfigs(oldConfig, newConfig *tableaclpb.Config) []string {
+	oldGroups := make(map[string]*tableaclpb.TableGroupSpec)
+	for _, group := range oldConfig.GetTableGroups() {
+		oldGroups[group.Name] = group
+	}
+	newGroups := make(map[string]*tableaclpb.TableGroupSpec)
+	for _, group := range newConfig.GetTableGroups() {
+		newGroups[group.Name] = group
+	}
+
+	var diffs []string
+	for _, group := range oldConfig.GetTableGroups() {
+		if _, ok := newGroups[group.Name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("removed table group %q", group.Name))
+		}
+	}
+	for _, group := range newConfig.GetTableGroups() {
+		oldGroup, ok := oldGroups[group.Name]
+		if !ok {
+			diff := fmt.Sprintf("added table group %q: tables %v, readers %v, writers %v, admins %v", group.Name, group.TableNamesOrPrefixes, group.Readers, group.Writers, group.Admins)
+			if len(group.ColumnDenies) > 0 {
+				diff += fmt.Sprintf(", column denies %v", formatColumnDenies(group.ColumnDenies))
+			}
+			if len(group.RowFilters) > 0 {
+				diff += fmt.Sprintf(", row filters %v", formatRowFilters(group.RowFilters))
+			}
+			diffs = append(diffs, diff)
+			continue
+		}
+		for _, field := range []struct {
+			name     string
+			old, new []string
+		}{
+			{"tables", oldGroup.TableNamesOrPrefixes, group.TableNamesOrPrefixes},
+			{"readers", oldGroup.Readers, group.Readers},
+			{"writers", oldGroup.Writers, group.Writers},
+			{"admins", oldGroup.Admins, group.Admins},
+			{"column denies", formatColumnDenies(oldGroup.ColumnDenies), formatColumnDenies(group.ColumnDenies)},
+			{"row filters", formatRowFilters(oldGroup.RowFilters), formatRowFilters(group.RowFilters)},
+		} {
+			if !stringSetsEqual(field.old, field.new) {
+				diffs = append(diffs, fmt.Sprintf("table group %q: %s %v -> %v", group.Name, field.name, field.old, field.new))
+			}
+		}
+	}
+	return diffs
+}
+
+func formatColumnDenies(specs []*tableaclpb.ColumnDenySpec) []string {
+	var result []string
+	for _, spec := range specs {
+		s := fmt.Sprintf("%v denied to %v", spec.Columns, spec.Principals)
+		if spec.Mask {
+			s += " (masked)"
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func formatRowFilters(specs []*tableaclpb.RowFilterSpec) []string {
+	var result []string
+	for _, spec := range specs {
+		result = append(result, fmt.Sprintf("%q for %v", spec.Predicate, spec.Principals))
+	}
+	return result
+}
+
+func stringSetsEqual(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+		delete(set, s)
+	}
+	return len(set) == 0
+}
+
 // Authorized returns the list of entities who have the specified role on a tablel.
 func Authorized(table string, role Role) *ACLResult {
 	currentACL.RLock()
@@ -199,8 +296,9 @@ func Authorized(table string, role Role) *ACLResult {
 			acl, ok := currentACL.entries[mid].acl[role]
 			if ok {
 				return &ACLResult{
-					ACL:       acl,
-					GroupName: currentACL.entries[mid].groupName,
+					ACL:          acl,
+					GroupName:    currentACL.entries[mid].groupName,
+					Restrictions: currentACL.entries[mid].restrictions,
 				}
 			}
 			break
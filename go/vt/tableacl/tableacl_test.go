@@ -252,3 +252,47 @@ func setUpTableACL(factory acl.Factory) {
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
+
+func TestDiffConfigs(t *testing.T) {
+	oldConfig := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group01",
+			TableNamesOrPrefixes: []string{"t1"},
+			Readers:              []string{"u1", "u2"},
+		}, {
+			Name:                 "group02",
+			TableNamesOrPrefixes: []string{"t2"},
+		}},
+	}
+	newConfig := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group01",
+			TableNamesOrPrefixes: []string{"t1"},
+			Readers:              []string{"u2", "u1"},
+			Writers:              []string{"u1"},
+			ColumnDenies: []*tableaclpb.ColumnDenySpec{{
+				Columns:    []string{"ssn"},
+				Principals: []string{"u2"},
+				Mask:       true,
+			}},
+		}, {
+			Name:                 "group03",
+			TableNamesOrPrefixes: []string{"t3"},
+		}},
+	}
+	want := []string{
+		`removed table group "group02"`,
+		`table group "group01": writers [] -> [u1]`,
+		`table group "group01": column denies [] -> [[ssn] denied to [u2] (masked)]`,
+		`added table group "group03": tables [t3], readers [], writers [], admins []`,
+	}
+	if got := DiffConfigs(oldConfig, newConfig); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffConfigs:\n%v\nwant:\n%v", got, want)
+	}
+	if got := DiffConfigs(newConfig, newConfig); len(got) != 0 {
+		t.Errorf("DiffConfigs(same config): %v, want none", got)
+	}
+	if got := DiffConfigs(nil, oldConfig); len(got) != 2 {
+		t.Errorf("DiffConfigs(nil, config): %v, want 2 added groups", got)
+	}
+}
@@ -176,9 +176,11 @@ type ActionAgent struct {
 // associated services.
 //
 // batchCtx is the context that the agent will use for any background tasks
-// it spawns.
+// it spawns. topoServer is the topology, which the agent shares with
+// queryServiceControl.
 func NewActionAgent(
 	batchCtx context.Context,
+	topoServer topo.Server,
 	mysqld mysqlctl.MysqlDaemon,
 	queryServiceControl tabletserver.Controller,
 	tabletAlias *topodatapb.TabletAlias,
@@ -186,8 +188,6 @@ func NewActionAgent(
 	mycnf *mysqlctl.Mycnf,
 	port, gRPCPort int32,
 ) (agent *ActionAgent, err error) {
-	topoServer := topo.GetServer()
-
 	orc, err := newOrcClient()
 	if err != nil {
 		return nil, err
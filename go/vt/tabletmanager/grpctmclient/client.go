@@ -35,6 +35,7 @@ var (
 	key         = flag.String("tablet_manager_grpc_key", "", "the key to use to connect")
 	ca          = flag.String("tablet_manager_grpc_ca", "", "the server ca to use to validate servers when connecting")
 	name        = flag.String("tablet_manager_grpc_server_name", "", "the server name to use to validate server certificate")
+	creds       = flag.String("tablet_manager_grpc_auth_static_client_creds", "", "JSON file of the username and password to send to a vttablet using the static gRPC authentication plugin")
 )
 
 func init() {
@@ -64,14 +65,27 @@ func NewClient() *Client {
 	return &Client{}
 }
 
+// dialOptions returns the gRPC dial options to connect to a tablet.
+func dialOptions() ([]grpc.DialOption, error) {
+	opt, err := grpcutils.ClientSecureDialOption(*cert, *key, *ca, *name)
+	if err != nil {
+		return nil, err
+	}
+	authOpts, err := grpcutils.ClientAuthDialOptions(*creds)
+	if err != nil {
+		return nil, err
+	}
+	return append([]grpc.DialOption{opt, grpcutils.ClientTraceDialOption()}, authOpts...), nil
+}
+
 // dial returns a client to use
 func (client *Client) dial(tablet *topodatapb.Tablet) (*grpc.ClientConn, tabletmanagerservicepb.TabletManagerClient, error) {
 	addr := netutil.JoinHostPort(tablet.Hostname, int32(tablet.PortMap["grpc"]))
-	opt, err := grpcutils.ClientSecureDialOption(*cert, *key, *ca, *name)
+	opts, err := dialOptions()
 	if err != nil {
 		return nil, nil, err
 	}
-	cc, err := grpc.Dial(addr, opt)
+	cc, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -80,7 +94,7 @@ func (client *Client) dial(tablet *topodatapb.Tablet) (*grpc.ClientConn, tabletm
 
 func (client *Client) dialPool(tablet *topodatapb.Tablet) (tabletmanagerservicepb.TabletManagerClient, error) {
 	addr := netutil.JoinHostPort(tablet.Hostname, int32(tablet.PortMap["grpc"]))
-	opt, err := grpcutils.ClientSecureDialOption(*cert, *key, *ca, *name)
+	opts, err := dialOptions()
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +110,7 @@ func (client *Client) dialPool(tablet *topodatapb.Tablet) (tabletmanagerservicep
 		client.mu.Unlock()
 
 		for i := 0; i < cap(c); i++ {
-			cc, err := grpc.Dial(addr, opt)
+			cc, err := grpc.Dial(addr, opts...)
 			if err != nil {
 				return nil, err
 			}
@@ -743,7 +757,7 @@ func (e *backupStreamAdapter) Recv() (*logutilpb.Event, error) {
 }
 
 // Backup is part of the tmclient.TabletManagerClient interface.
-func (client *Client) Backup(ctx context.Context, tablet *topodatapb.Tablet, concurrency int) (logutil.EventStream, error) {
+func (client *Client) Backup(ctx context.Context, tablet *topodatapb.Tablet, concurrency int, incremental bool) (logutil.EventStream, error) {
 	cc, c, err := client.dial(tablet)
 	if err != nil {
 		return nil, err
@@ -751,6 +765,7 @@ func (client *Client) Backup(ctx context.Context, tablet *topodatapb.Tablet, con
 
 	stream, err := c.Backup(ctx, &tabletmanagerdatapb.BackupRequest{
 		Concurrency: int64(concurrency),
+		Incremental: incremental,
 	})
 	if err != nil {
 		cc.Close()
@@ -777,13 +792,19 @@ func (e *restoreFromBackupStreamAdapter) Recv() (*logutilpb.Event, error) {
 }
 
 // RestoreFromBackup is part of the tmclient.TabletManagerClient interface.
-func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet) (logutil.EventStream, error) {
+func (client *Client) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, restoreToPos string, restoreToTime time.Time) (logutil.EventStream, error) {
 	cc, c, err := client.dial(tablet)
 	if err != nil {
 		return nil, err
 	}
 
-	stream, err := c.RestoreFromBackup(ctx, &tabletmanagerdatapb.RestoreFromBackupRequest{})
+	request := &tabletmanagerdatapb.RestoreFromBackupRequest{
+		RestoreToPosition: restoreToPos,
+	}
+	if !restoreToTime.IsZero() {
+		request.RestoreToTimestamp = restoreToTime.Unix()
+	}
+	stream, err := c.RestoreFromBackup(ctx, request)
 	if err != nil {
 		cc.Close()
 		return nil, err
@@ -427,7 +427,7 @@ func (s *server) Backup(request *tabletmanagerdatapb.BackupRequest, stream table
 		})
 	})
 
-	return s.agent.Backup(ctx, int(request.Concurrency), logger)
+	return s.agent.Backup(ctx, int(request.Concurrency), request.Incremental, logger)
 }
 
 func (s *server) RestoreFromBackup(request *tabletmanagerdatapb.RestoreFromBackupRequest, stream tabletmanagerservicepb.TabletManager_RestoreFromBackupServer) (err error) {
@@ -445,7 +445,11 @@ func (s *server) RestoreFromBackup(request *tabletmanagerdatapb.RestoreFromBacku
 		})
 	})
 
-	return s.agent.RestoreFromBackup(ctx, logger)
+	var restoreToTime time.Time
+	if request.RestoreToTimestamp != 0 {
+		restoreToTime = time.Unix(request.RestoreToTimestamp, 0)
+	}
+	return s.agent.RestoreFromBackup(ctx, request.RestoreToPosition, restoreToTime, logger)
 }
 
 // registration glue
@@ -33,10 +33,13 @@ var (
 func (agent *ActionAgent) RestoreData(ctx context.Context, logger logutil.Logger, deleteBeforeRestore bool) error {
 	agent.actionMutex.Lock()
 	defer agent.actionMutex.Unlock()
-	return agent.restoreDataLocked(ctx, logger, deleteBeforeRestore)
+	return agent.restoreDataLocked(ctx, logger, deleteBeforeRestore, mysqlctl.RestoreTarget{})
 }
 
-func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.Logger, deleteBeforeRestore bool) error {
+// restoreDataLocked restores the latest backup. If target is set, the
+// binlogs are replayed up to it, and the tablet is left DRAINED and not
+// replicating, so the restored data doesn't catch up with the master.
+func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.Logger, deleteBeforeRestore bool, target mysqlctl.RestoreTarget) error {
 	// change type to RESTORE (using UpdateTabletFields so it's
 	// always authorized)
 	var originalType topodatapb.TabletType
@@ -60,12 +63,22 @@ func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.
 	localMetadata := agent.getLocalMetadataValues(originalType)
 	tablet := agent.Tablet()
 	dir := fmt.Sprintf("%v/%v", tablet.Keyspace, tablet.Shard)
-	pos, err := mysqlctl.Restore(ctx, agent.MysqlDaemon, dir, *restoreConcurrency, agent.hookExtraEnv(), localMetadata, logger, deleteBeforeRestore, topoproto.TabletDbName(tablet))
+	pos, err := mysqlctl.Restore(ctx, agent.MysqlDaemon, dir, *restoreConcurrency, agent.hookExtraEnv(), localMetadata, logger, deleteBeforeRestore, topoproto.TabletDbName(tablet), target)
 	switch err {
 	case nil:
-		// Reconnect to master.
-		if err := agent.startReplication(ctx, pos, originalType); err != nil {
-			return err
+		if target.IsZero() {
+			// Reconnect to master.
+			if err := agent.startReplication(ctx, pos, originalType); err != nil {
+				return err
+			}
+		} else {
+			// Only set the position at which to resume from the
+			// master, if the tablet is later put back in service.
+			logger.Infof("Restored up to %v, at position %v, changing type to DRAINED", target, pos)
+			if err := agent.setSlavePosition(ctx, pos); err != nil {
+				return err
+			}
+			originalType = topodatapb.TabletType_DRAINED
 		}
 	case mysqlctl.ErrNoBackup:
 		// No-op, starting with empty database.
@@ -94,8 +107,8 @@ func (agent *ActionAgent) restoreDataLocked(ctx context.Context, logger logutil.
 	return nil
 }
 
-func (agent *ActionAgent) startReplication(ctx context.Context, pos replication.Position, tabletType topodatapb.TabletType) error {
-	// Set the position at which to resume from the master.
+// setSlavePosition sets the position at which to resume from the master.
+func (agent *ActionAgent) setSlavePosition(ctx context.Context, pos replication.Position) error {
 	cmds, err := agent.MysqlDaemon.SetSlavePositionCommands(pos)
 	if err != nil {
 		return err
@@ -103,6 +116,14 @@ func (agent *ActionAgent) startReplication(ctx context.Context, pos replication.
 	if err := agent.MysqlDaemon.ExecuteSuperQueryList(ctx, cmds); err != nil {
 		return fmt.Errorf("failed to set slave position: %v", err)
 	}
+	return nil
+}
+
+func (agent *ActionAgent) startReplication(ctx context.Context, pos replication.Position, tabletType topodatapb.TabletType) error {
+	// Set the position at which to resume from the master.
+	if err := agent.setSlavePosition(ctx, pos); err != nil {
+		return err
+	}
 
 	// Read the shard to find the current master, and its location.
 	tablet := agent.Tablet()
@@ -137,7 +158,7 @@ func (agent *ActionAgent) startReplication(ctx context.Context, pos replication.
 	}
 
 	// Set master and start slave.
-	cmds, err = agent.MysqlDaemon.SetMasterCommands(ti.Hostname, int(ti.PortMap["mysql"]))
+	cmds, err := agent.MysqlDaemon.SetMasterCommands(ti.Hostname, int(ti.PortMap["mysql"]))
 	if err != nil {
 		return fmt.Errorf("MysqlDaemon.SetMasterCommands failed: %v", err)
 	}
@@ -109,9 +109,9 @@ type RPCAgent interface {
 
 	// Backup / restore related methods
 
-	Backup(ctx context.Context, concurrency int, logger logutil.Logger) error
+	Backup(ctx context.Context, concurrency int, incremental bool, logger logutil.Logger) error
 
-	RestoreFromBackup(ctx context.Context, logger logutil.Logger) error
+	RestoreFromBackup(ctx context.Context, restoreToPos string, restoreToTime time.Time, logger logutil.Logger) error
 
 	// HandleRPCPanic is to be called in a defer statement in each
 	// RPC input point.
@@ -10,6 +10,7 @@ import (
 
 	"github.com/youtube/vitess/go/vt/logutil"
 	"github.com/youtube/vitess/go/vt/mysqlctl"
+	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
 	"github.com/youtube/vitess/go/vt/topo/topoproto"
 	"github.com/youtube/vitess/go/vt/topotools"
 	"golang.org/x/net/context"
@@ -18,7 +19,7 @@ import (
 )
 
 // Backup takes a db backup and sends it to the BackupStorage
-func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, logger logutil.Logger) error {
+func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, incremental bool, logger logutil.Logger) error {
 	if err := agent.lock(ctx); err != nil {
 		return err
 	}
@@ -47,8 +48,8 @@ func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, logger lo
 
 	// now we can run the backup
 	dir := fmt.Sprintf("%v/%v", tablet.Keyspace, tablet.Shard)
-	name := fmt.Sprintf("%v.%v", time.Now().UTC().Format("2006-01-02.150405"), topoproto.TabletAliasString(tablet.Alias))
-	returnErr := mysqlctl.Backup(ctx, agent.MysqlDaemon, l, dir, name, concurrency, agent.hookExtraEnv())
+	name := fmt.Sprintf("%v.%v", time.Now().UTC().Format(mysqlctl.BackupNameTimeFormat), topoproto.TabletAliasString(tablet.Alias))
+	returnErr := mysqlctl.Backup(ctx, agent.MysqlDaemon, l, dir, name, concurrency, agent.hookExtraEnv(), incremental)
 
 	// change our type back to the original value
 	_, err = topotools.ChangeType(ctx, agent.TopoServer, tablet.Alias, originalType)
@@ -73,7 +74,19 @@ func (agent *ActionAgent) Backup(ctx context.Context, concurrency int, logger lo
 }
 
 // RestoreFromBackup deletes all local data and restores anew from the latest backup.
-func (agent *ActionAgent) RestoreFromBackup(ctx context.Context, logger logutil.Logger) error {
+// If restoreToPos or restoreToTime is set, it restores the latest backup
+// taken before that point, and replays the binlogs up to it.
+func (agent *ActionAgent) RestoreFromBackup(ctx context.Context, restoreToPos string, restoreToTime time.Time, logger logutil.Logger) error {
+	var target mysqlctl.RestoreTarget
+	if restoreToPos != "" {
+		pos, err := replication.DecodePosition(restoreToPos)
+		if err != nil {
+			return fmt.Errorf("cannot decode restore position %v: %v", restoreToPos, err)
+		}
+		target.Position = pos
+	}
+	target.Time = restoreToTime
+
 	if err := agent.lock(ctx); err != nil {
 		return err
 	}
@@ -91,7 +104,7 @@ func (agent *ActionAgent) RestoreFromBackup(ctx context.Context, logger logutil.
 	l := logutil.NewTeeLogger(logutil.NewConsoleLogger(), logger)
 
 	// now we can run restore
-	err = agent.restoreDataLocked(ctx, l, true /* deleteBeforeRestore */)
+	err = agent.restoreDataLocked(ctx, l, true /* deleteBeforeRestore */, target)
 
 	// re-run health check to be sure to capture any replication delay
 	agent.runHealthCheckLocked()
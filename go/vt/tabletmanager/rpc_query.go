@@ -6,13 +6,18 @@ package tabletmanager
 
 import (
 	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/audit"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
 	"golang.org/x/net/context"
 
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
 )
 
 // ExecuteFetchAsDba will execute the given query, possibly disabling binlogs and reload schema.
-func (agent *ActionAgent) ExecuteFetchAsDba(ctx context.Context, query []byte, dbName string, maxrows int, disableBinlogs bool, reloadSchema bool) (*querypb.QueryResult, error) {
+func (agent *ActionAgent) ExecuteFetchAsDba(ctx context.Context, query []byte, dbName string, maxrows int, disableBinlogs bool, reloadSchema bool) (_ *querypb.QueryResult, err error) {
+	ev := audit.Start(ctx, "ExecuteFetchAsDba", agent.auditTarget(dbName), string(query))
+	defer func() { ev.Finish(err) }()
+
 	// get a connection
 	conn, err := agent.MysqlDaemon.GetDbaConnection()
 	if err != nil {
@@ -54,7 +59,10 @@ func (agent *ActionAgent) ExecuteFetchAsDba(ctx context.Context, query []byte, d
 }
 
 // ExecuteFetchAsAllPrivs will execute the given query, possibly reloading schema.
-func (agent *ActionAgent) ExecuteFetchAsAllPrivs(ctx context.Context, query []byte, dbName string, maxrows int, reloadSchema bool) (*querypb.QueryResult, error) {
+func (agent *ActionAgent) ExecuteFetchAsAllPrivs(ctx context.Context, query []byte, dbName string, maxrows int, reloadSchema bool) (_ *querypb.QueryResult, err error) {
+	ev := audit.Start(ctx, "ExecuteFetchAsAllPrivs", agent.auditTarget(dbName), string(query))
+	defer func() { ev.Finish(err) }()
+
 	// get a connection
 	conn, err := agent.MysqlDaemon.GetAllPrivsConnection()
 	if err != nil {
@@ -88,3 +96,12 @@ func (agent *ActionAgent) ExecuteFetchAsApp(ctx context.Context, query []byte, m
 	result, err := conn.ExecuteFetch(string(query), maxrows, true /*wantFields*/)
 	return sqltypes.ResultToProto3(result), err
 }
+
+// auditTarget returns the target of the audit events of the queries
+// run on dbName, or on the database of the tablet if dbName is empty.
+func (agent *ActionAgent) auditTarget(dbName string) string {
+	if dbName == "" {
+		dbName = topoproto.TabletDbName(agent.Tablet())
+	}
+	return topoproto.TabletAliasString(agent.TabletAlias) + "/" + dbName
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/vt/audit"
 	"github.com/youtube/vitess/go/vt/mysqlctl/replication"
 	"github.com/youtube/vitess/go/vt/mysqlctl/tmutils"
 	"github.com/youtube/vitess/go/vt/topo/topoproto"
@@ -60,7 +61,10 @@ func (agent *ActionAgent) PreflightSchema(ctx context.Context, changes []string)
 }
 
 // ApplySchema will apply a schema change
-func (agent *ActionAgent) ApplySchema(ctx context.Context, change *tmutils.SchemaChange) (*tabletmanagerdatapb.SchemaChangeResult, error) {
+func (agent *ActionAgent) ApplySchema(ctx context.Context, change *tmutils.SchemaChange) (_ *tabletmanagerdatapb.SchemaChangeResult, err error) {
+	ev := audit.Start(ctx, "ApplySchema", agent.auditTarget(""), change.SQL)
+	defer func() { ev.Finish(err) }()
+
 	if err := agent.lock(ctx); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,132 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tabletmanager
+
+import (
+	"strings"
+	"time"
+
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/vt/audit"
+	"github.com/youtube/vitess/go/vt/tableacl"
+	"github.com/youtube/vitess/go/vt/topo"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+// tableACLReloads counts the reloads of the table ACL from the
+// topology, by "Success" and "Failure".
+var tableACLReloads = stats.NewCounters("TableACLReloads")
+
+// TableACLWatcher loads the table ACL of a keyspace from the topology,
+// and reloads it every time it changes there. An invalid table ACL is
+// rejected, and the previous one is kept. If the table ACL is deleted
+// from the topology, the last one stays in effect.
+type TableACLWatcher struct {
+	ts         topo.Server
+	keyspace   string
+	retryDelay time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTableACLWatcher returns a TableACLWatcher for the keyspace. When
+// the watch fails, it is restarted after retryDelay.
+func NewTableACLWatcher(ts topo.Server, keyspace string, retryDelay time.Duration) *TableACLWatcher {
+	return &TableACLWatcher{
+		ts:         ts,
+		keyspace:   keyspace,
+		retryDelay: retryDelay,
+	}
+}
+
+// Load reads the current table ACL of the keyspace, and loads it.
+func (w *TableACLWatcher) Load(ctx context.Context) error {
+	config, err := w.ts.GetTableACL(ctx, w.keyspace)
+	if err != nil {
+		return err
+	}
+	return tableacl.InitFromProto(config)
+}
+
+// Start starts watching the table ACL.
+func (w *TableACLWatcher) Start() {
+	var ctx context.Context
+	ctx, w.cancel = context.WithCancel(context.Background())
+	w.done = make(chan struct{})
+	go w.run(ctx)
+}
+
+// Stop stops watching the table ACL, and waits for the watch to end.
+func (w *TableACLWatcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *TableACLWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	for {
+		current, changes, cancel := w.ts.WatchTableACL(ctx, w.keyspace)
+		if current.Err != nil {
+			if current.Err != topo.ErrNoNode {
+				log.Warningf("Cannot watch the table ACL of keyspace %v, retrying in %v: %v", w.keyspace, w.retryDelay, current.Err)
+			}
+		} else {
+			w.apply(current.Value)
+			w.follow(ctx, changes, cancel)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retryDelay):
+		}
+	}
+}
+
+// follow applies the changes of a watch, until it fails or ctx is done.
+func (w *TableACLWatcher) follow(ctx context.Context, changes <-chan *topo.WatchTableACLData, cancel topo.CancelFunc) {
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			for range changes {
+			}
+			return
+		case wd, ok := <-changes:
+			if !ok {
+				return
+			}
+			if wd.Err != nil {
+				// The channel is closed right after an error.
+				log.Warningf("Watch on the table ACL of keyspace %v failed, retrying in %v: %v", w.keyspace, w.retryDelay, wd.Err)
+				continue
+			}
+			w.apply(wd.Value)
+		}
+	}
+}
+
+func (w *TableACLWatcher) apply(config *tableaclpb.Config) {
+	// The reloads which change the table ACL are recorded in the
+	// audit log, with the changes as statement.
+	var ev *audit.Event
+	if changes := tableacl.DiffConfigs(tableacl.GetCurrentConfig(), config); len(changes) > 0 {
+		ev = audit.Start(context.Background(), "ReloadTableACL", w.keyspace, strings.Join(changes, "\n"))
+	}
+	err := tableacl.InitFromProto(config)
+	ev.Finish(err)
+	if err != nil {
+		tableACLReloads.Add("Failure", 1)
+		log.Errorf("Invalid table ACL for keyspace %v in the topology, keeping the previous one: %v", w.keyspace, err)
+		return
+	}
+	tableACLReloads.Add("Success", 1)
+	log.Infof("Loaded the table ACL of keyspace %v from the topology", w.keyspace)
+}
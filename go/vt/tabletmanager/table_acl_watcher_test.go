@@ -0,0 +1,88 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tabletmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/vt/tableacl"
+	"github.com/youtube/vitess/go/vt/tableacl/simpleacl"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/memorytopo"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+func newTableACL(readers ...string) *tableaclpb.Config {
+	return &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group1",
+			TableNamesOrPrefixes: []string{"t1"},
+			Readers:              readers,
+		}},
+	}
+}
+
+func waitForTableACL(t *testing.T, want *tableaclpb.Config) {
+	for i := 0; ; i++ {
+		if got := tableacl.GetCurrentConfig(); proto.Equal(got, want) {
+			return
+		}
+		if i == 1000 {
+			t.Fatalf("timed out waiting for table ACL %v, got %v", want, tableacl.GetCurrentConfig())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTableACLWatcher(t *testing.T) {
+	tableacl.Register("simpleacl", &simpleacl.Factory{})
+	tableacl.SetDefaultACL("simpleacl")
+	ctx := context.Background()
+	ts := topo.Server{Impl: memorytopo.NewMemoryTopo([]string{"global", "cell1"})}
+
+	w := NewTableACLWatcher(ts, "ks", 10*time.Millisecond)
+	if err := w.Load(ctx); err != topo.ErrNoNode {
+		t.Errorf("Load without table ACL: %v, want ErrNoNode", err)
+	}
+
+	if err := ts.SaveTableACL(ctx, "ks", newTableACL("user1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Load(ctx); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	waitForTableACL(t, newTableACL("user1"))
+
+	w.Start()
+	defer w.Stop()
+	if err := ts.SaveTableACL(ctx, "ks", newTableACL("user2")); err != nil {
+		t.Fatal(err)
+	}
+	waitForTableACL(t, newTableACL("user2"))
+
+	// An invalid table ACL is rejected.
+	failures := tableACLReloads.Counts()["Failure"]
+	invalid := newTableACL("user3")
+	invalid.TableGroups[0].TableNamesOrPrefixes = []string{"t%", "t1"}
+	contents, err := proto.Marshal(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Update(ctx, "global", "/keyspaces/ks/TableACL", contents, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; tableACLReloads.Counts()["Failure"] == failures; i++ {
+		if i == 1000 {
+			t.Fatalf("timed out waiting for the invalid table ACL to be rejected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	waitForTableACL(t, newTableACL("user2"))
+}
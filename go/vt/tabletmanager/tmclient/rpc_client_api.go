@@ -187,11 +187,14 @@ type TabletManagerClient interface {
 	// Backup / restore related methods
 	//
 
-	// Backup creates a database backup
-	Backup(ctx context.Context, tablet *topodatapb.Tablet, concurrency int) (logutil.EventStream, error)
-
-	// RestoreFromBackup deletes local data and restores database from backup
-	RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet) (logutil.EventStream, error)
+	// Backup creates a database backup. If incremental is set, only
+	// the binlogs since the previous backup are backed up.
+	Backup(ctx context.Context, tablet *topodatapb.Tablet, concurrency int, incremental bool) (logutil.EventStream, error)
+
+	// RestoreFromBackup deletes local data and restores database from backup.
+	// If restoreToPos or restoreToTime is set, the binlogs from the
+	// incremental backups are replayed up to that point.
+	RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, restoreToPos string, restoreToTime time.Time) (logutil.EventStream, error)
 
 	//
 	// Management methods
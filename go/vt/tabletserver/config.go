@@ -11,10 +11,12 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/youtube/vitess/go/flagutil"
 	"github.com/youtube/vitess/go/streamlog"
 	"github.com/youtube/vitess/go/vt/dbconfigs"
 	"github.com/youtube/vitess/go/vt/mysqlctl"
 	"github.com/youtube/vitess/go/vt/tabletserver/queryservice"
+	"github.com/youtube/vitess/go/vt/topo"
 
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
 	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
@@ -50,6 +52,17 @@ func init() {
 	flag.StringVar(&qsConfig.DebugURLPrefix, "debug-url-prefix", DefaultQsConfig.DebugURLPrefix, "debug url prefix, vttablet will report various system debug pages and this config controls the prefix of these debug urls")
 	flag.StringVar(&qsConfig.PoolNamePrefix, "pool-name-prefix", DefaultQsConfig.PoolNamePrefix, "pool name prefix, vttablet has several pools and each of them has a name. This config specifies the prefix of these pool names")
 	flag.BoolVar(&qsConfig.EnableAutoCommit, "enable-autocommit", DefaultQsConfig.EnableAutoCommit, "if the flag is on, a DML outsides a transaction will be auto committed.")
+	flag.BoolVar(&qsConfig.EnableHotRowProtection, "queryserver-config-enable-hot-row-protection", DefaultQsConfig.EnableHotRowProtection, "if the flag is on, the DMLs updating the same row (by primary key) are queued and executed one at a time, instead of all waiting for the row lock in MySQL")
+	flag.IntVar(&qsConfig.HotRowProtectionMaxQueueSize, "queryserver-config-hot-row-protection-max-queue-size", DefaultQsConfig.HotRowProtectionMaxQueueSize, "maximum number of transactions queued for the same row with hot row protection, including the one being executed. The transactions above this limit are rejected with a retryable error.")
+	flag.IntVar(&qsConfig.HotRowProtectionMaxGlobalQueueSize, "queryserver-config-hot-row-protection-max-global-queue-size", DefaultQsConfig.HotRowProtectionMaxGlobalQueueSize, "maximum number of transactions queued for all the rows with hot row protection. The transactions above this limit are rejected with a retryable error.")
+	flag.BoolVar(&qsConfig.PublishSchema, "queryserver-config-publish-schema", DefaultQsConfig.PublishSchema, "if the flag is on, the schema version and the columns of the tables are published in the health stream, so vtgate can track them.")
+	flag.BoolVar(&qsConfig.EnableTxThrottler, "enable-tx-throttler", DefaultQsConfig.EnableTxThrottler, "If true, the master throttles the transactions when its replicas lag behind, to keep their replication lag below a target.")
+	flag.StringVar(&qsConfig.TxThrottlerConfig, "tx-throttler-config", DefaultQsConfig.TxThrottlerConfig, "The configuration of the transaction throttler, as a text throttlerdata.Configuration protobuf. The fields which are not set keep their default values.")
+	flag.Var((*flagutil.StringMapValue)(&qsConfig.TxThrottlerKeyspaceConfigs), "tx-throttler-keyspace-configs", "A comma-separated list of keyspace:config pairs, to override -tx-throttler-config for the shards of some keyspaces. Each config is a text throttlerdata.Configuration protobuf, and the fields which are not set keep the values of -tx-throttler-config.")
+	flag.Var((*flagutil.StringListValue)(&qsConfig.TxThrottlerHealthCheckCells), "tx-throttler-healthcheck-cells", "A comma-separated list of the cells where the transaction throttler looks for the replicas of the shard.")
+	flag.Float64Var(&qsConfig.TxThrottlerMaxWait, "tx-throttler-max-wait", DefaultQsConfig.TxThrottlerMaxWait, "How long (in seconds) a throttled Begin waits for the transaction throttler to let it go, before it is rejected. 0 rejects the throttled transactions right away.")
+	flag.Float64Var(&qsConfig.TwoPCAbandonAge, "twopc-abandon-age", DefaultQsConfig.TwoPCAbandonAge, "The age (in seconds) after which a distributed transaction whose metadata is still on the master is considered abandoned by its coordinator. The master periodically looks for them and exports their count. 0 disables the watchdog.")
+	flag.BoolVar(&qsConfig.TwoPCAutoResolve, "twopc-auto-resolve", DefaultQsConfig.TwoPCAutoResolve, "If true, the master concludes the abandoned distributed transactions found by the watchdog, by calling the participants found in the topology. Requires -twopc-abandon-age.")
 }
 
 // Init must be called after flag.Parse, and before doing any other operations.
@@ -78,10 +91,24 @@ type Config struct {
 	EnablePublishStats   bool
 	EnableAutoCommit     bool
 	EnableTableAclDryRun bool
+	PublishSchema        bool
 	StatsPrefix          string
 	DebugURLPrefix       string
 	PoolNamePrefix       string
 	TableAclExemptACL    string
+
+	EnableHotRowProtection             bool
+	HotRowProtectionMaxQueueSize       int
+	HotRowProtectionMaxGlobalQueueSize int
+
+	EnableTxThrottler           bool
+	TxThrottlerConfig           string
+	TxThrottlerKeyspaceConfigs  map[string]string
+	TxThrottlerHealthCheckCells []string
+	TxThrottlerMaxWait          float64
+
+	TwoPCAbandonAge  float64
+	TwoPCAutoResolve bool
 }
 
 // DefaultQsConfig is the default value for the query service config.
@@ -110,10 +137,24 @@ var DefaultQsConfig = Config{
 	EnablePublishStats:   true,
 	EnableAutoCommit:     false,
 	EnableTableAclDryRun: false,
+	PublishSchema:        false,
 	StatsPrefix:          "",
 	DebugURLPrefix:       "/debug",
 	PoolNamePrefix:       "",
 	TableAclExemptACL:    "",
+
+	EnableHotRowProtection:             false,
+	HotRowProtectionMaxQueueSize:       20,
+	HotRowProtectionMaxGlobalQueueSize: 1000,
+
+	EnableTxThrottler:           false,
+	TxThrottlerConfig:           "target_replication_lag_sec: 2 max_replication_lag_sec: 10",
+	TxThrottlerKeyspaceConfigs:  map[string]string{},
+	TxThrottlerHealthCheckCells: []string{},
+	TxThrottlerMaxWait:          0,
+
+	TwoPCAbandonAge:  0,
+	TwoPCAutoResolve: false,
 }
 
 var qsConfig Config
@@ -133,8 +174,8 @@ func buildFmter(logger *streamlog.StreamLogger) func(url.Values, interface{}) st
 }
 
 // NewServer creates a new TabletServer based on the command line flags.
-func NewServer() *TabletServer {
-	return NewTabletServer(qsConfig)
+func NewServer(topoServer topo.Server) *TabletServer {
+	return NewTabletServer(qsConfig, topoServer)
 }
 
 // Controller defines the control interface for TabletServer.
@@ -56,7 +56,7 @@ func StartServer(connParams sqldb.ConnParams) error {
 		TabletType: topodatapb.TabletType_MASTER,
 	}
 
-	Server = tabletserver.NewTabletServer(BaseConfig)
+	Server = tabletserver.NewTabletServerWithNilTopoServer(BaseConfig)
 	Server.Register()
 	err := Server.StartService(Target, dbcfgs, mysqld)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/youtube/vitess/go/sqltypes"
 	"github.com/youtube/vitess/go/vt/callerid"
 	"github.com/youtube/vitess/go/vt/callinfo"
+	"github.com/youtube/vitess/go/vt/servenv"
 	"github.com/youtube/vitess/go/vt/tabletserver/queryservice"
 	"github.com/youtube/vitess/go/vt/tabletserver/querytypes"
 	"github.com/youtube/vitess/go/vt/vterrors"
@@ -25,12 +26,23 @@ type query struct {
 	server queryservice.QueryService
 }
 
+// verifiedCallerID returns the immediate caller id of a call. If the
+// call was authenticated, the immediate caller id sent by the client
+// is only trusted if the client is a proxy user, like vtgate.
+// Otherwise, the authenticated user is the immediate caller.
+func verifiedCallerID(ctx context.Context, im *querypb.VTGateCallerID) *querypb.VTGateCallerID {
+	if _, ok := servenv.AuthenticatedUser(ctx); !ok {
+		return im
+	}
+	return callerid.NewImmediateCallerID(servenv.VerifiedCaller(ctx, callerid.GetUsername(im)))
+}
+
 // Execute is part of the queryservice.QueryServer interface
 func (q *query) Execute(ctx context.Context, request *querypb.ExecuteRequest) (response *querypb.ExecuteResponse, err error) {
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	bv, err := querytypes.Proto3ToBindVariables(request.Query.BindVariables)
 	if err != nil {
@@ -50,7 +62,7 @@ func (q *query) ExecuteBatch(ctx context.Context, request *querypb.ExecuteBatchR
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	bql, err := querytypes.Proto3ToBoundQueryList(request.Queries)
 	if err != nil {
@@ -70,7 +82,7 @@ func (q *query) StreamExecute(request *querypb.StreamExecuteRequest, stream quer
 	defer q.server.HandlePanic(&err)
 	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(stream.Context(), request.ImmediateCallerId),
 	)
 	bv, err := querytypes.Proto3ToBindVariables(request.Query.BindVariables)
 	if err != nil {
@@ -91,7 +103,7 @@ func (q *query) Begin(ctx context.Context, request *querypb.BeginRequest) (respo
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	transactionID, err := q.server.Begin(ctx, request.Target)
 	if err != nil {
@@ -108,7 +120,7 @@ func (q *query) Commit(ctx context.Context, request *querypb.CommitRequest) (res
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.Commit(ctx, request.Target, request.TransactionId); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -121,7 +133,7 @@ func (q *query) Rollback(ctx context.Context, request *querypb.RollbackRequest)
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.Rollback(ctx, request.Target, request.TransactionId); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -135,7 +147,7 @@ func (q *query) Prepare(ctx context.Context, request *querypb.PrepareRequest) (r
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.Prepare(ctx, request.Target, request.TransactionId, request.Dtid); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -149,7 +161,7 @@ func (q *query) CommitPrepared(ctx context.Context, request *querypb.CommitPrepa
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.CommitPrepared(ctx, request.Target, request.Dtid); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -163,7 +175,7 @@ func (q *query) RollbackPrepared(ctx context.Context, request *querypb.RollbackP
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.RollbackPrepared(ctx, request.Target, request.Dtid, request.TransactionId); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -177,7 +189,7 @@ func (q *query) CreateTransaction(ctx context.Context, request *querypb.CreateTr
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.CreateTransaction(ctx, request.Target, request.Dtid, request.Participants); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -191,7 +203,7 @@ func (q *query) StartCommit(ctx context.Context, request *querypb.StartCommitReq
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.StartCommit(ctx, request.Target, request.TransactionId, request.Dtid); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -205,7 +217,7 @@ func (q *query) SetRollback(ctx context.Context, request *querypb.SetRollbackReq
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.SetRollback(ctx, request.Target, request.Dtid, request.TransactionId); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -219,7 +231,7 @@ func (q *query) ResolveTransaction(ctx context.Context, request *querypb.Resolve
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	if err := q.server.ResolveTransaction(ctx, request.Target, request.Dtid); err != nil {
 		return nil, vterrors.ToGRPCError(err)
@@ -233,7 +245,7 @@ func (q *query) ReadTransaction(ctx context.Context, request *querypb.ReadTransa
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	result, err := q.server.ReadTransaction(ctx, request.Target, request.Dtid)
 	if err != nil {
@@ -248,7 +260,7 @@ func (q *query) BeginExecute(ctx context.Context, request *querypb.BeginExecuteR
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	bv, err := querytypes.Proto3ToBindVariables(request.Query.BindVariables)
 	if err != nil {
@@ -277,7 +289,7 @@ func (q *query) BeginExecuteBatch(ctx context.Context, request *querypb.BeginExe
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 	bql, err := querytypes.Proto3ToBoundQueryList(request.Queries)
 	if err != nil {
@@ -306,7 +318,7 @@ func (q *query) SplitQuery(ctx context.Context, request *querypb.SplitQueryReque
 	defer q.server.HandlePanic(&err)
 	ctx = callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(ctx, request.ImmediateCallerId),
 	)
 
 	bq, err := querytypes.Proto3ToBoundQuery(request.Query)
@@ -362,7 +374,7 @@ func (q *query) UpdateStream(request *querypb.UpdateStreamRequest, stream querys
 	defer q.server.HandlePanic(&err)
 	ctx := callerid.NewContext(callinfo.GRPCCallInfo(stream.Context()),
 		request.EffectiveCallerId,
-		request.ImmediateCallerId,
+		verifiedCallerID(stream.Context(), request.ImmediateCallerId),
 	)
 	if err := q.server.UpdateStream(ctx, request.Target, request.Position, request.Timestamp, func(reply *querypb.StreamEvent) error {
 		return stream.Send(&querypb.UpdateStreamResponse{
@@ -32,6 +32,7 @@ var (
 	ca    = flag.String("tablet_grpc_ca", "", "the server ca to use to validate servers when connecting")
 	name  = flag.String("tablet_grpc_server_name", "", "the server name to use to validate server certificate")
 	combo = flag.Bool("tablet_grpc_combine_begin_execute", false, "combines Begin and Execute / ExecuteBatch calls in one when possible")
+	creds = flag.String("tablet_grpc_auth_static_client_creds", "", "JSON file of the username and password to send to a vttablet using the static gRPC authentication plugin")
 )
 
 func init() {
@@ -62,7 +63,12 @@ func DialTablet(tablet *topodatapb.Tablet, timeout time.Duration) (tabletconn.Ta
 	if err != nil {
 		return nil, err
 	}
-	opts := []grpc.DialOption{opt}
+	opts := []grpc.DialOption{opt, grpcutils.ClientTraceDialOption()}
+	authOpts, err := grpcutils.ClientAuthDialOptions(*creds)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, authOpts...)
 	if timeout > 0 {
 		opts = append(opts, grpc.WithBlock(), grpc.WithTimeout(timeout))
 	}
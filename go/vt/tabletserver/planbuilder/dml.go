@@ -138,6 +138,7 @@ func analyzeSelect(sel *sqlparser.Select, getTable TableGetter) (plan *ExecPlan,
 	if err != nil {
 		return nil, err
 	}
+	plan.Select = sel
 
 	// Check if it's a NEXT VALUE statement.
 	if _, ok := sel.SelectExprs[0].(sqlparser.Nextval); ok {
@@ -147,6 +148,7 @@ func analyzeSelect(sel *sqlparser.Select, getTable TableGetter) (plan *ExecPlan,
 		plan.PlanID = PlanNextval
 		plan.FieldQuery = nil
 		plan.FullQuery = nil
+		plan.Select = nil
 	}
 	return plan, nil
 }
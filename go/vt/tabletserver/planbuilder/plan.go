@@ -54,6 +54,9 @@ const (
 	PlanSelectStream
 	// PlanOther is for SHOW, DESCRIBE & EXPLAIN statements
 	PlanOther
+	// PlanSavepoint is for SAVEPOINT, ROLLBACK TO SAVEPOINT & RELEASE SAVEPOINT
+	// statements
+	PlanSavepoint
 	// NumPlans stores the total number of plans
 	NumPlans
 )
@@ -73,6 +76,7 @@ var planName = []string{
 	"DDL",
 	"SELECT_STREAM",
 	"OTHER",
+	"SAVEPOINT",
 }
 
 func (pt PlanType) String() string {
@@ -121,6 +125,7 @@ var tableAclRoles = map[PlanType]tableacl.Role{
 	PlanOther:          tableacl.ADMIN,
 	PlanUpsertPK:       tableacl.WRITER,
 	PlanNextval:        tableacl.WRITER,
+	PlanSavepoint:      tableacl.READER,
 }
 
 // ReasonType indicates why a query plan fails to build
@@ -190,6 +195,18 @@ type ExecPlan struct {
 
 	// For PlanInsertSubquery: pk columns in the subquery result.
 	SubqueryPKColumns []int `json:",omitempty"`
+
+	// For PlanSavepoint: the savepoint statement.
+	Savepoint *sqlparser.Savepoint `json:"-"`
+
+	// For selects on a single table: the select statement, used to
+	// enforce the column denies and the row filters of the table ACL.
+	Select *sqlparser.Select `json:"-"`
+
+	// ReadTables are the tables the statement reads, anywhere in the
+	// statement, with the number of times each is referred to. See
+	// ReadTables.
+	ReadTables map[string]int `json:"-"`
 }
 
 func (plan *ExecPlan) setTableInfo(tableName string, getTable TableGetter) (*schema.Table, error) {
@@ -210,6 +227,15 @@ func GetExecPlan(sql string, getTable TableGetter) (plan *ExecPlan, err error) {
 	if err != nil {
 		return nil, err
 	}
+	plan, err = analyzeStatement(statement, getTable)
+	if err != nil {
+		return nil, err
+	}
+	plan.ReadTables = ReadTables(statement)
+	return plan, nil
+}
+
+func analyzeStatement(statement sqlparser.Statement, getTable TableGetter) (plan *ExecPlan, err error) {
 	switch stmt := statement.(type) {
 	case *sqlparser.Union:
 		return &ExecPlan{
@@ -231,6 +257,12 @@ func GetExecPlan(sql string, getTable TableGetter) (plan *ExecPlan, err error) {
 		return analyzeDDL(stmt, getTable), nil
 	case *sqlparser.Other:
 		return &ExecPlan{PlanID: PlanOther}, nil
+	case *sqlparser.Savepoint:
+		return &ExecPlan{
+			PlanID:    PlanSavepoint,
+			FullQuery: GenerateFullQuery(stmt),
+			Savepoint: stmt,
+		}, nil
 	}
 	return nil, errors.New("invalid SQL")
 }
@@ -243,8 +275,9 @@ func GetStreamExecPlan(sql string, getTable TableGetter) (plan *ExecPlan, err er
 	}
 
 	plan = &ExecPlan{
-		PlanID:    PlanSelectStream,
-		FullQuery: GenerateFullQuery(statement),
+		PlanID:     PlanSelectStream,
+		FullQuery:  GenerateFullQuery(statement),
+		ReadTables: ReadTables(statement),
 	}
 
 	switch stmt := statement.(type) {
@@ -254,6 +287,7 @@ func GetStreamExecPlan(sql string, getTable TableGetter) (plan *ExecPlan, err er
 		}
 		if tableName := analyzeFrom(stmt.From); tableName != "" {
 			plan.setTableInfo(tableName, getTable)
+			plan.Select = stmt
 		}
 	case *sqlparser.Union:
 		// pass
@@ -0,0 +1,135 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package planbuilder
+
+import (
+	"strconv"
+
+	"github.com/youtube/vitess/go/vt/schema"
+	"github.com/youtube/vitess/go/vt/sqlparser"
+)
+
+// This file contains the analysis and the rewriting of the selects
+// needed to enforce the column denies and the row filters of the
+// table ACL.
+
+// ReadTables returns the names of the tables a statement reads, with
+// the number of times each is referred to: in the from clauses, the
+// joins, the subqueries and the unions, and the tables updated or
+// deleted from. The table an insert writes to is not read. The
+// qualifiers are ignored, so that a table cannot be read unchecked
+// through its database name.
+func ReadTables(stmt sqlparser.Statement) map[string]int {
+	var written *sqlparser.TableName
+	if ins, ok := stmt.(*sqlparser.Insert); ok {
+		written = ins.Table
+	}
+	tables := make(map[string]int)
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			// The qualifiers of the columns are not table reads.
+			return false, nil
+		case *sqlparser.TableName:
+			if node != nil && node != written && node.Name != "" {
+				tables[string(node.Name)]++
+			}
+			return false, nil
+		}
+		return true, nil
+	}, stmt)
+	return tables
+}
+
+// AnalyzeSelectColumns returns how a select on a single table reads the
+// columns of the table. results has one entry per result column: the
+// lower cased table column it returns unchanged, or "" for any other
+// expression. others are the lower cased table columns the select
+// reads otherwise: in expressions, or in its where, group by, having
+// and order by clauses, including through the aliases and the
+// positions of the result columns.
+func AnalyzeSelectColumns(sel *sqlparser.Select, table *schema.Table) (results []string, others map[string]bool) {
+	others = make(map[string]bool)
+	addColumns := func(nodes ...sqlparser.SQLNode) {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if col, ok := node.(*sqlparser.ColName); ok {
+				others[col.Name.Lowered()] = true
+			}
+			return true, nil
+		}, nodes...)
+	}
+
+	aliases := make(map[string]string)
+	for _, expr := range sel.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.StarExpr:
+			for _, col := range table.Columns {
+				results = append(results, col.Name.Lowered())
+			}
+		case *sqlparser.NonStarExpr:
+			name := ""
+			if col, ok := expr.Expr.(*sqlparser.ColName); ok {
+				name = col.Name.Lowered()
+			} else {
+				addColumns(expr.Expr)
+			}
+			if expr.As.Original() != "" {
+				aliases[expr.As.Lowered()] = name
+			}
+			results = append(results, name)
+		default:
+			results = append(results, "")
+		}
+	}
+
+	addColumns(sel.From, sel.Where, sel.GroupBy, sel.Having, sel.OrderBy)
+	// The group by, having and order by clauses can also refer to the
+	// result columns by alias or by position.
+	for alias, name := range aliases {
+		if others[alias] && name != "" {
+			others[name] = true
+		}
+	}
+	var positions []sqlparser.ValExpr
+	positions = append(positions, sel.GroupBy...)
+	for _, order := range sel.OrderBy {
+		positions = append(positions, order.Expr)
+	}
+	for _, expr := range positions {
+		num, ok := expr.(sqlparser.NumVal)
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(string(num))
+		if err != nil || i < 1 || i > len(results) {
+			continue
+		}
+		if results[i-1] != "" {
+			others[results[i-1]] = true
+		}
+	}
+	return results, others
+}
+
+// GenerateFilteredSelectQuery generates the full query of a select,
+// with filter ANDed to its where clause. The limit is added like in
+// GenerateSelectLimitQuery, unless the select is streamed.
+func GenerateFilteredSelectQuery(sel *sqlparser.Select, filter sqlparser.BoolExpr, streaming bool) *sqlparser.ParsedQuery {
+	// The select is shared by all the executions of the plan,
+	// so it must not be modified.
+	filtered := *sel
+	if sel.Where == nil {
+		filtered.Where = sqlparser.NewWhere(sqlparser.WhereStr, filter)
+	} else {
+		filtered.Where = sqlparser.NewWhere(sqlparser.WhereStr, &sqlparser.AndExpr{
+			Left:  &sqlparser.ParenBoolExpr{Expr: sel.Where.Expr},
+			Right: filter,
+		})
+	}
+	if streaming {
+		return GenerateFullQuery(&filtered)
+	}
+	return GenerateSelectLimitQuery(&filtered)
+}
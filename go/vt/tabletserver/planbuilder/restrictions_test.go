@@ -0,0 +1,133 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package planbuilder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/youtube/vitess/go/vt/sqlparser"
+)
+
+func TestReadTables(t *testing.T) {
+	testcases := []struct {
+		query  string
+		tables map[string]int
+	}{{
+		query:  "select ssn from users, dual",
+		tables: map[string]int{"users": 1, "dual": 1},
+	}, {
+		query:  "select users.ssn from users join b on users.id = b.id",
+		tables: map[string]int{"users": 1, "b": 1},
+	}, {
+		query:  "select * from (select ssn from users) as t",
+		tables: map[string]int{"users": 1},
+	}, {
+		query:  "select id from users where id in (select id from users where ssn = 1)",
+		tables: map[string]int{"users": 2},
+	}, {
+		query:  "select id from a where exists (select 1 from db.users)",
+		tables: map[string]int{"a": 1, "users": 1},
+	}, {
+		query:  "select id from a union select ssn from users",
+		tables: map[string]int{"a": 1, "users": 1},
+	}, {
+		query:  "insert into a(id) select ssn from users",
+		tables: map[string]int{"users": 1},
+	}, {
+		query:  "update a set name = (select ssn from users) where id = 1",
+		tables: map[string]int{"a": 1, "users": 1},
+	}, {
+		query:  "delete from users where ssn = 1",
+		tables: map[string]int{"users": 1},
+	}, {
+		query:  "select 1 from dual",
+		tables: map[string]int{"dual": 1},
+	}}
+	for _, tcase := range testcases {
+		stmt, err := sqlparser.Parse(tcase.query)
+		if err != nil {
+			t.Fatalf("Parse(%v) failed: %v", tcase.query, err)
+		}
+		if tables := ReadTables(stmt); !reflect.DeepEqual(tables, tcase.tables) {
+			t.Errorf("ReadTables(%v): %v, want %v", tcase.query, tables, tcase.tables)
+		}
+	}
+}
+
+func TestAnalyzeSelectColumns(t *testing.T) {
+	testSchema := loadSchema("schema_test.json")
+	testcases := []struct {
+		query   string
+		results []string
+		others  map[string]bool
+	}{{
+		query:   "select * from a",
+		results: []string{"eid", "id", "name", "foo", "camelcase"},
+		others:  map[string]bool{},
+	}, {
+		query:   "select eid, Name as n, id + 1, 1 from a where foo = 1",
+		results: []string{"eid", "name", "", ""},
+		others:  map[string]bool{"id": true, "foo": true},
+	}, {
+		query:   "select eid, name as n from a group by 1 order by n",
+		results: []string{"eid", "name"},
+		others:  map[string]bool{"n": true, "eid": true, "name": true},
+	}, {
+		query:   "select count(*) from a having max(foo) > 1",
+		results: []string{""},
+		others:  map[string]bool{"foo": true},
+	}}
+	for _, tcase := range testcases {
+		stmt, err := sqlparser.Parse(tcase.query)
+		if err != nil {
+			t.Fatalf("Parse(%v) failed: %v", tcase.query, err)
+		}
+		results, others := AnalyzeSelectColumns(stmt.(*sqlparser.Select), testSchema["a"])
+		if !reflect.DeepEqual(results, tcase.results) || !reflect.DeepEqual(others, tcase.others) {
+			t.Errorf("AnalyzeSelectColumns(%v): %v, %v, want %v, %v", tcase.query, results, others, tcase.results, tcase.others)
+		}
+	}
+}
+
+func TestGenerateFilteredSelectQuery(t *testing.T) {
+	filter := &sqlparser.ParenBoolExpr{
+		Expr: &sqlparser.ComparisonExpr{
+			Operator: sqlparser.EqualStr,
+			Left:     &sqlparser.ColName{Name: sqlparser.NewColIdent("region")},
+			Right:    sqlparser.StrVal("us"),
+		},
+	}
+	testcases := []struct {
+		query     string
+		streaming bool
+		want      string
+	}{{
+		query: "select * from a",
+		want:  "select * from a where (region = 'us') limit :#maxLimit",
+	}, {
+		query: "select * from a where id = 1 or id = 2 limit 10",
+		want:  "select * from a where (id = 1 or id = 2) and (region = 'us') limit 10",
+	}, {
+		query:     "select * from a where id = 1",
+		streaming: true,
+		want:      "select * from a where (id = 1) and (region = 'us')",
+	}}
+	for _, tcase := range testcases {
+		stmt, err := sqlparser.Parse(tcase.query)
+		if err != nil {
+			t.Fatalf("Parse(%v) failed: %v", tcase.query, err)
+		}
+		sel := stmt.(*sqlparser.Select)
+		got := GenerateFilteredSelectQuery(sel, filter, tcase.streaming).Query
+		if got != tcase.want {
+			t.Errorf("GenerateFilteredSelectQuery(%v): %v, want %v", tcase.query, got, tcase.want)
+		}
+		// The select must not be modified.
+		if after := sqlparser.String(sel); after != tcase.query {
+			t.Errorf("select modified: %v, want %v", after, tcase.query)
+		}
+	}
+}
@@ -53,6 +53,7 @@ type QueryEngine struct {
 	consolidator *sync2.Consolidator
 	streamQList  *QueryList
 	twoPC        *TwoPC
+	txSerializer *TxSerializer
 
 	// Vars
 	strictMode       sync2.AtomicInt64
@@ -66,6 +67,7 @@ type QueryEngine struct {
 	tableaclAllowed      *stats.MultiCounters
 	tableaclDenied       *stats.MultiCounters
 	tableaclPseudoDenied *stats.MultiCounters
+	tableaclRestricted   *stats.MultiCounters
 	strictTableAcl       bool
 	enableTableAclDryRun bool
 	exemptACL            acl.ACL
@@ -154,6 +156,14 @@ func NewQueryEngine(checker MySQLChecker, config Config) *QueryEngine {
 	}
 	qe.preparedPool = NewTxPreparedPool(prepCap)
 	qe.twoPC = NewTwoPC()
+	if config.EnableHotRowProtection {
+		qe.txSerializer = NewTxSerializer(
+			config.HotRowProtectionMaxQueueSize,
+			config.HotRowProtectionMaxGlobalQueueSize,
+			config.StatsPrefix,
+			config.EnablePublishStats,
+		)
+	}
 	qe.consolidator = sync2.NewConsolidator()
 	http.Handle(config.DebugURLPrefix+"/consolidations", qe.consolidator)
 	qe.streamQList = NewQueryList()
@@ -189,6 +199,7 @@ func NewQueryEngine(checker MySQLChecker, config Config) *QueryEngine {
 	var tableACLAllowedName string
 	var tableACLDeniedName string
 	var tableACLPseudoDeniedName string
+	var tableACLRestrictedName string
 	if config.EnablePublishStats {
 		stats.Publish(config.StatsPrefix+"MaxResultSize", stats.IntFunc(qe.maxResultSize.Get))
 		stats.Publish(config.StatsPrefix+"MaxDMLRows", stats.IntFunc(qe.maxDMLRows.Get))
@@ -197,11 +208,13 @@ func NewQueryEngine(checker MySQLChecker, config Config) *QueryEngine {
 		tableACLAllowedName = "TableACLAllowed"
 		tableACLDeniedName = "TableACLDenied"
 		tableACLPseudoDeniedName = "TableACLPseudoDenied"
+		tableACLRestrictedName = "TableACLRestricted"
 	}
 
 	qe.tableaclAllowed = stats.NewMultiCounters(tableACLAllowedName, []string{"TableName", "TableGroup", "PlanID", "Username"})
 	qe.tableaclDenied = stats.NewMultiCounters(tableACLDeniedName, []string{"TableName", "TableGroup", "PlanID", "Username"})
 	qe.tableaclPseudoDenied = stats.NewMultiCounters(tableACLPseudoDeniedName, []string{"TableName", "TableGroup", "PlanID", "Username"})
+	qe.tableaclRestricted = stats.NewMultiCounters(tableACLRestrictedName, []string{"TableName", "TableGroup", "PlanID", "Username"})
 
 	return qe
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/youtube/vitess/go/mysql"
 	"github.com/youtube/vitess/go/sqltypes"
 	"github.com/youtube/vitess/go/trace"
+	"github.com/youtube/vitess/go/vt/audit"
 	"github.com/youtube/vitess/go/vt/callerid"
 	"github.com/youtube/vitess/go/vt/callinfo"
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
@@ -33,6 +34,12 @@ type QueryExecutor struct {
 	ctx           context.Context
 	logStats      *LogStats
 	qe            *QueryEngine
+
+	// filteredQuery replaces the FullQuery of the plan for the selects
+	// whose rows are filtered by the table ACL.
+	filteredQuery *sqlparser.ParsedQuery
+	// maskedColumns are the result columns masked by the table ACL.
+	maskedColumns []int
 }
 
 var sequenceFields = []*querypb.Field{
@@ -56,6 +63,9 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 	qre.logStats.TransactionID = qre.transactionID
 	planName := qre.plan.PlanID.String()
 	qre.logStats.PlanType = planName
+	defer qre.startSpan("QueryExecutor.Execute").Finish()
+	ev := audit.StartQuery(qre.ctx, planName, qre.auditTarget(), qre.query)
+	defer func() { ev.Finish(err) }()
 	defer func(start time.Time) {
 		duration := time.Now().Sub(start)
 		qre.qe.queryServiceStats.QueryStats.Add(planName, duration)
@@ -89,6 +99,15 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 			return nil, err
 		}
 		defer conn.Recycle()
+		// With hot row protection, wait for the other transactions
+		// updating the same rows before sending the DML to MySQL.
+		txDone, err := qre.qe.waitForSameRowTransactions(qre.ctx, qre.plan, qre.bindVars, conn)
+		if err != nil {
+			return nil, err
+		}
+		if txDone != nil {
+			defer txDone()
+		}
 		switch qre.plan.PlanID {
 		case planbuilder.PlanPassDML:
 			if qre.qe.strictMode.Get() != 0 {
@@ -109,6 +128,8 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 			return qre.execUpsertPK(conn)
 		case planbuilder.PlanSet:
 			return qre.txFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, false, true)
+		case planbuilder.PlanSavepoint:
+			return qre.execSavepoint(conn)
 		default:
 			return qre.execDirect(conn)
 		}
@@ -116,7 +137,7 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 		switch qre.plan.PlanID {
 		case planbuilder.PlanPassSelect:
 			return qre.execSelect()
-		case planbuilder.PlanSelectLock:
+		case planbuilder.PlanSelectLock, planbuilder.PlanSavepoint:
 			return nil, NewTabletError(vtrpcpb.ErrorCode_BAD_INPUT, "Disallowed outside transaction")
 		case planbuilder.PlanSet:
 			return qre.execSet()
@@ -141,6 +162,7 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 func (qre *QueryExecutor) Stream(excludeFieldNames bool, sendReply func(*sqltypes.Result) error) error {
 	qre.logStats.OriginalSQL = qre.query
 	qre.logStats.PlanType = qre.plan.PlanID.String()
+	defer qre.startSpan("QueryExecutor.Stream").Finish()
 
 	defer func(start time.Time) {
 		qre.qe.queryServiceStats.QueryStats.Record(qre.plan.PlanID.String(), start)
@@ -161,10 +183,21 @@ func (qre *QueryExecutor) Stream(excludeFieldNames bool, sendReply func(*sqltype
 	qre.qe.streamQList.Add(qd)
 	defer qre.qe.streamQList.Remove(qd)
 
-	return qre.streamFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, excludeFieldNames, sendReply)
+	return qre.streamFetch(conn, qre.selectQuery(), qre.bindVars, nil, excludeFieldNames, func(result *sqltypes.Result) error {
+		return sendReply(qre.maskColumns(result))
+	})
 }
 
 func (qre *QueryExecutor) execDmlAutoCommit() (reply *sqltypes.Result, err error) {
+	// With hot row protection, wait for the other transactions
+	// updating the same rows before taking a TxPool connection.
+	txDone, err := qre.qe.waitForSameRowTransactions(qre.ctx, qre.plan, qre.bindVars, nil)
+	if err != nil {
+		return nil, err
+	}
+	if txDone != nil {
+		defer txDone()
+	}
 	return qre.execAsTransaction(func(conn *TxConnection) (reply *sqltypes.Result, err error) {
 		switch qre.plan.PlanID {
 		case planbuilder.PlanPassDML:
@@ -255,6 +288,10 @@ func (qre *QueryExecutor) checkPermissions() error {
 		return nil
 	}
 
+	if err := qre.checkRestrictedTables(callerID.Username); err != nil {
+		return err
+	}
+
 	// empty table name, do not need a table ACL check.
 	if qre.plan.TableName == "" {
 		return nil
@@ -285,9 +322,149 @@ func (qre *QueryExecutor) checkPermissions() error {
 		return nil
 	}
 	qre.qe.tableaclAllowed.Add(tableACLStatsKey, 1)
+	return qre.checkRestrictions(callerID.Username, tableACLStatsKey)
+}
+
+// checkRestrictedTables rejects the queries of username which read a
+// table whose column denies or row filters apply to username, other
+// than as the single table of a select: in joins, derived tables,
+// subqueries or unions, or in DMLs. Only the selects on a single table
+// are analyzed, and their restrictions enforced by checkRestrictions.
+// Like the roles, this is only enforced in strict table ACL mode.
+func (qre *QueryExecutor) checkRestrictedTables(username string) error {
+	for table, result := range qre.plan.RestrictedTables {
+		if result.Restrictions.DeniedColumns(username) == nil && result.Restrictions.RowFilter(username) == nil {
+			continue
+		}
+		tableACLStatsKey := []string{
+			table,
+			result.GroupName,
+			qre.plan.PlanID.String(),
+			username,
+		}
+		if qre.qe.enableTableAclDryRun {
+			qre.qe.tableaclPseudoDenied.Add(tableACLStatsKey, 1)
+			return nil
+		}
+		if qre.qe.strictTableAcl {
+			errStr := fmt.Sprintf("table acl error: %q can only read table %q as the single table of a select", username, table)
+			qre.qe.tableaclDenied.Add(tableACLStatsKey, 1)
+			qre.qe.accessCheckerLogger.Infof("%s", errStr)
+			return NewTabletError(vtrpcpb.ErrorCode_PERMISSION_DENIED, "%s", errStr)
+		}
+		return nil
+	}
 	return nil
 }
 
+// checkRestrictions enforces the column denies and the row filters of
+// the table ACL on the selects of username. The denied columns which
+// the select returns unchanged are masked, if their column deny allows
+// it. Any other read of a denied column is rejected. Like the roles,
+// the restrictions are only enforced in strict table ACL mode.
+func (qre *QueryExecutor) checkRestrictions(username string, tableACLStatsKey []string) error {
+	restrictions := qre.plan.Authorized.Restrictions
+	if restrictions == nil || qre.plan.Select == nil {
+		return nil
+	}
+
+	var masked []int
+	rejected := ""
+	if denied := restrictions.DeniedColumns(username); denied != nil {
+		for i, column := range qre.plan.ResultColumns {
+			if mask, ok := denied[column]; ok {
+				if !mask {
+					rejected = column
+					break
+				}
+				masked = append(masked, i)
+			}
+		}
+		for column := range qre.plan.OtherColumns {
+			if _, ok := denied[column]; ok && rejected == "" {
+				rejected = column
+			}
+		}
+	}
+	if rejected != "" {
+		if qre.qe.enableTableAclDryRun {
+			qre.qe.tableaclPseudoDenied.Add(tableACLStatsKey, 1)
+			return nil
+		}
+		if qre.qe.strictTableAcl {
+			errStr := fmt.Sprintf("table acl error: %q cannot read column %q of table %q", username, rejected, qre.plan.TableName)
+			qre.qe.tableaclDenied.Add(tableACLStatsKey, 1)
+			qre.qe.accessCheckerLogger.Infof("%s", errStr)
+			return NewTabletError(vtrpcpb.ErrorCode_PERMISSION_DENIED, "%s", errStr)
+		}
+		return nil
+	}
+
+	filter := restrictions.RowFilter(username)
+	if (masked == nil && filter == nil) || qre.qe.enableTableAclDryRun || !qre.qe.strictTableAcl {
+		return nil
+	}
+	qre.maskedColumns = masked
+	if filter != nil {
+		qre.filteredQuery = planbuilder.GenerateFilteredSelectQuery(qre.plan.Select, filter, qre.plan.PlanID == planbuilder.PlanSelectStream)
+	}
+	qre.qe.tableaclRestricted.Add(tableACLStatsKey, 1)
+	return nil
+}
+
+// selectQuery returns the query to run for a select: the FullQuery of
+// the plan, unless the table ACL filters its rows.
+func (qre *QueryExecutor) selectQuery() *sqlparser.ParsedQuery {
+	if qre.filteredQuery != nil {
+		return qre.filteredQuery
+	}
+	return qre.plan.FullQuery
+}
+
+// maskColumns returns a copy of the result of a select, with the values
+// of the columns masked by the table ACL replaced with NULL. The result
+// itself may be shared, and is not modified.
+func (qre *QueryExecutor) maskColumns(result *sqltypes.Result) *sqltypes.Result {
+	if len(qre.maskedColumns) == 0 || result == nil || len(result.Rows) == 0 {
+		return result
+	}
+	masked := *result
+	masked.Rows = make([][]sqltypes.Value, len(result.Rows))
+	for i, row := range result.Rows {
+		maskedRow := make([]sqltypes.Value, len(row))
+		copy(maskedRow, row)
+		for _, column := range qre.maskedColumns {
+			if column < len(maskedRow) {
+				maskedRow[column] = sqltypes.NULL
+			}
+		}
+		masked.Rows[i] = maskedRow
+	}
+	return &masked
+}
+
+// execSavepoint executes a savepoint statement, and keeps track of the
+// savepoints in the TxConnection. The statement itself is not recorded.
+func (qre *QueryExecutor) execSavepoint(conn *TxConnection) (*sqltypes.Result, error) {
+	qr, err := qre.execSQL(conn, qre.plan.FullQuery.Query, false)
+	if err != nil {
+		return nil, err
+	}
+	name := qre.plan.Savepoint.Name.Original()
+	switch qre.plan.Savepoint.Action {
+	case sqlparser.SavepointStr:
+		conn.Savepoint(name)
+	case sqlparser.RollbackToStr:
+		err = conn.RollbackToSavepoint(name)
+	case sqlparser.ReleaseStr:
+		err = conn.ReleaseSavepoint(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
 func (qre *QueryExecutor) execDDL() (*sqltypes.Result, error) {
 	ddlPlan := planbuilder.DDLParse(qre.query)
 	if ddlPlan.Action == "" {
@@ -376,35 +553,43 @@ func (qre *QueryExecutor) execNextval() (*sqltypes.Result, error) {
 // execDirect is for reads inside transactions. Always send to MySQL.
 func (qre *QueryExecutor) execDirect(conn *TxConnection) (*sqltypes.Result, error) {
 	if qre.plan.Fields != nil {
-		result, err := qre.txFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, false, false)
+		result, err := qre.txFetch(conn, qre.selectQuery(), qre.bindVars, nil, false, false)
 		if err != nil {
 			return nil, err
 		}
 		result.Fields = qre.plan.Fields
-		return result, nil
+		return qre.maskColumns(result), nil
 	}
-	return qre.txFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, true, false)
+	result, err := qre.txFetch(conn, qre.selectQuery(), qre.bindVars, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return qre.maskColumns(result), nil
 }
 
 // execSelect sends a query to mysql only if another identical query is not running. Otherwise, it waits and
 // reuses the result. If the plan is missng field info, it sends the query to mysql requesting full info.
 func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
 	if qre.plan.Fields != nil {
-		result, err := qre.qFetch(qre.logStats, qre.plan.FullQuery, qre.bindVars)
+		result, err := qre.qFetch(qre.logStats, qre.selectQuery(), qre.bindVars)
 		if err != nil {
 			return nil, err
 		}
 		// result is read-only. So, let's copy it before modifying.
 		newResult := *result
 		newResult.Fields = qre.plan.Fields
-		return &newResult, nil
+		return qre.maskColumns(&newResult), nil
 	}
 	conn, err := qre.getConn(qre.qe.connPool)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Recycle()
-	return qre.dbConnFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, true)
+	result, err := qre.dbConnFetch(conn, qre.selectQuery(), qre.bindVars, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return qre.maskColumns(result), nil
 }
 
 func (qre *QueryExecutor) execInsertPK(conn *TxConnection) (*sqltypes.Result, error) {
@@ -540,6 +725,28 @@ func (qre *QueryExecutor) execSet() (*sqltypes.Result, error) {
 	return qre.dbConnFetch(conn, qre.plan.FullQuery, qre.bindVars, nil, false)
 }
 
+// startSpan starts the span of the query, and makes it the parent of
+// the spans of its connection and MySQL calls.
+func (qre *QueryExecutor) startSpan(label string) trace.Span {
+	span := trace.NewSpanFromContext(qre.ctx)
+	span.StartLocal(label)
+	span.Annotate("plan", qre.plan.PlanID.String())
+	if qre.plan.TableName != "" {
+		span.Annotate("table", qre.plan.TableName)
+	}
+	qre.ctx = trace.NewContext(qre.ctx, span)
+	return span
+}
+
+// auditTarget returns the target of the audit event of the query: the
+// database, and the table of the plan if any.
+func (qre *QueryExecutor) auditTarget() string {
+	if qre.plan.TableName == "" {
+		return qre.qe.dbconfigs.App.DbName
+	}
+	return qre.qe.dbconfigs.App.DbName + "." + qre.plan.TableName
+}
+
 func (qre *QueryExecutor) getConn(pool *ConnPool) (*DBConn, error) {
 	span := trace.NewSpanFromContext(qre.ctx)
 	span.StartLocal("QueryExecutor.getConn")
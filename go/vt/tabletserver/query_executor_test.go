@@ -641,6 +641,62 @@ func TestQueryExecutorPlanSet(t *testing.T) {
 	tsv.StopService()
 }
 
+func TestQueryExecutorPlanSavepoint(t *testing.T) {
+	db := setUpQueryExecutorTest()
+	for _, query := range []string{
+		"set a = 1",
+		"set b = 2",
+		"set c = 3",
+		"savepoint s1",
+		"savepoint s2",
+		"rollback to savepoint s1",
+		"release savepoint s1",
+	} {
+		db.AddQuery(query, &sqltypes.Result{})
+	}
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, enableStrict, db)
+	defer tsv.StopService()
+
+	qre := newTestQueryExecutor(ctx, tsv, "savepoint s1", 0)
+	checkPlanID(t, planbuilder.PlanSavepoint, qre.plan.PlanID)
+	if _, err := qre.Execute(); err == nil || !strings.Contains(err.Error(), "Disallowed outside transaction") {
+		t.Errorf("qre.Execute() outside transaction: %v, want Disallowed outside transaction", err)
+	}
+
+	txid := newTransaction(tsv)
+	for _, query := range []string{
+		"set a = 1",
+		"savepoint s1",
+		"set b = 2",
+		"savepoint s2",
+		"set c = 3",
+		"rollback to s1",
+	} {
+		qre = newTestQueryExecutor(ctx, tsv, query, txid)
+		if _, err := qre.Execute(); err != nil {
+			t.Fatalf("qre.Execute(%v) = %v, want nil", query, err)
+		}
+	}
+	// The queries rolled back must not be part of the redo log.
+	wantqueries := []string{"set a = 1"}
+	gotqueries := fetchRecordedQueries(qre)
+	if !reflect.DeepEqual(gotqueries, wantqueries) {
+		t.Errorf("queries: %v, want %v", gotqueries, wantqueries)
+	}
+
+	// s2 was removed by the rollback.
+	qre = newTestQueryExecutor(ctx, tsv, "release savepoint s2", txid)
+	if _, err := qre.Execute(); err == nil {
+		t.Error("qre.Execute(release savepoint s2) = nil, want error")
+	}
+	qre = newTestQueryExecutor(ctx, tsv, "release savepoint s1", txid)
+	if _, err := qre.Execute(); err != nil {
+		t.Fatalf("qre.Execute(release savepoint s1) = %v, want nil", err)
+	}
+	testCommitHelper(t, tsv, qre)
+}
+
 func TestQueryExecutorPlanOther(t *testing.T) {
 	db := setUpQueryExecutorTest()
 	query := "show test_table"
@@ -941,6 +997,158 @@ func TestQueryExecutorTableAclDryRun(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorTableAclRestrictions(t *testing.T) {
+	aclName := fmt.Sprintf("simpleacl-test-%d", rand.Int63())
+	tableacl.Register(aclName, &simpleacl.Factory{})
+	tableacl.SetDefaultACL(aclName)
+	db := setUpQueryExecutorTest()
+	fields := []*querypb.Field{
+		{Name: "pk", Type: sqltypes.Int32},
+		{Name: "addr", Type: sqltypes.Int32},
+	}
+	db.AddQuery("select pk, addr from test_table where 1 != 1", &sqltypes.Result{Fields: fields})
+	db.AddQuery("select pk, addr from test_table where (pk = 1) and (pk > 10) limit 1000", &sqltypes.Result{
+		Fields:       fields,
+		RowsAffected: 1,
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("11")),
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("2")),
+		}},
+	})
+	db.AddQuery("select pk, addr from test_table where pk = 1 limit 1000", &sqltypes.Result{
+		Fields:       fields,
+		RowsAffected: 1,
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("2")),
+		}},
+	})
+	db.AddQuery("select name from test_table where 1 != 1", &sqltypes.Result{Fields: getTestTableFields()[1:2]})
+	db.AddQuery("select pk from test_table where 1 != 1", &sqltypes.Result{Fields: fields[:1]})
+	// The fields of the other selects.
+	db.AddQueryPattern(".* where 1 != 1.*", &sqltypes.Result{Fields: fields[:1]})
+	db.AddQuery("select pk from test_table where pk in (select pk from test_table where addr = 2) limit 1000", &sqltypes.Result{
+		Fields:       fields[:1],
+		RowsAffected: 1,
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+		}},
+	})
+
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group01",
+			TableNamesOrPrefixes: []string{"test_table"},
+			Readers:              []string{"u1", "u2"},
+			ColumnDenies: []*tableaclpb.ColumnDenySpec{{
+				Columns:    []string{"addr"},
+				Principals: []string{"u2"},
+				Mask:       true,
+			}, {
+				Columns:    []string{"name"},
+				Principals: []string{"u2"},
+			}},
+			RowFilters: []*tableaclpb.RowFilterSpec{{
+				Principals: []string{"u2"},
+				Predicate:  "pk > 10",
+			}},
+		}},
+	}
+	if err := tableacl.InitFromProto(config); err != nil {
+		t.Fatalf("unable to load tableacl config, error: %v", err)
+	}
+
+	testcases := []struct {
+		username string
+		query    string
+		want     []sqltypes.Value
+		err      string
+	}{{
+		// u1 has no restrictions.
+		username: "u1",
+		query:    "select pk, addr from test_table where pk = 1 limit 1000",
+		want: []sqltypes.Value{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("2")),
+		},
+	}, {
+		// addr is masked, and the rows are filtered.
+		username: "u2",
+		query:    "select pk, addr from test_table where pk = 1 limit 1000",
+		want: []sqltypes.Value{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("11")),
+			sqltypes.NULL,
+		},
+	}, {
+		username: "u2",
+		query:    "select name from test_table limit 1000",
+		err:      `cannot read column \"name\"`,
+	}, {
+		// A masked column cannot be read in a where clause.
+		username: "u2",
+		query:    "select pk from test_table where addr = 2 limit 1000",
+		err:      `cannot read column \"addr\"`,
+	}, {
+		// The restricted tables can only be read as the single
+		// table of a select.
+		username: "u2",
+		query:    "select addr from test_table, dual limit 1000",
+		err:      `can only read table \"test_table\" as the single table of a select`,
+	}, {
+		username: "u2",
+		query:    "select t.addr from (select addr from test_table) as t limit 1000",
+		err:      `can only read table \"test_table\" as the single table of a select`,
+	}, {
+		username: "u2",
+		query:    "select pk from test_table where pk in (select pk from test_table where addr = 2) limit 1000",
+		err:      `can only read table \"test_table\" as the single table of a select`,
+	}, {
+		username: "u2",
+		query:    "select pk from test_table union select addr from test_table",
+		err:      `can only read table \"test_table\" as the single table of a select`,
+	}, {
+		username: "u2",
+		query:    "select 1 from dual where exists (select 1 from test_table where addr = 2)",
+		err:      `can only read table \"test_table\" as the single table of a select`,
+	}, {
+		// u1 is not restricted.
+		username: "u1",
+		query:    "select pk from test_table where pk in (select pk from test_table where addr = 2) limit 1000",
+		want: []sqltypes.Value{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+		},
+	}}
+	tsv := newTestTabletServer(context.Background(), enableStrict|enableStrictTableAcl, db)
+	defer tsv.StopService()
+	for _, tcase := range testcases {
+		ctx := callerid.NewContext(context.Background(), nil, &querypb.VTGateCallerID{Username: tcase.username})
+		qre := newTestQueryExecutor(ctx, tsv, tcase.query, 0)
+		got, err := qre.Execute()
+		if tcase.err != "" {
+			tabletError, ok := err.(*TabletError)
+			if !ok || tabletError.ErrorCode != vtrpcpb.ErrorCode_PERMISSION_DENIED || !strings.Contains(err.Error(), tcase.err) {
+				t.Errorf("%v by %v: %v, want PERMISSION_DENIED containing %v", tcase.query, tcase.username, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v by %v failed: %v", tcase.query, tcase.username, err)
+			continue
+		}
+		if len(got.Rows) != 1 || !reflect.DeepEqual(got.Rows[0], tcase.want) {
+			t.Errorf("%v by %v: %v, want %v", tcase.query, tcase.username, got.Rows, tcase.want)
+		}
+	}
+
+	// The rows of the result cached by the consolidator, or
+	// returned to the other callers, must not be masked.
+	ctx := callerid.NewContext(context.Background(), nil, &querypb.VTGateCallerID{Username: "u1"})
+	got, err := newTestQueryExecutor(ctx, tsv, "select pk, addr from test_table where pk = 1 limit 1000", 0).Execute()
+	if err != nil || got.Rows[0][1].IsNull() {
+		t.Errorf("unrestricted select after a masked one: %v, %v", got, err)
+	}
+}
+
 func TestQueryExecutorBlacklistQRFail(t *testing.T) {
 	db := setUpQueryExecutorTest()
 	query := "select * from test_table where name = 1 limit 1000"
@@ -1067,6 +1275,7 @@ const (
 	enableStrict               = 1 << iota
 	enableStrictTableAcl
 	smallTxPool
+	enableHotRowProtection
 )
 
 // newTestQueryExecutor uses a package level variable testTabletServer defined in tabletserver_test.go
@@ -1095,7 +1304,10 @@ func newTestTabletServer(ctx context.Context, flags executorFlags, db *fakesqldb
 	} else {
 		config.StrictTableAcl = false
 	}
-	tsv := NewTabletServer(config)
+	if flags&enableHotRowProtection > 0 {
+		config.EnableHotRowProtection = true
+	}
+	tsv := NewTabletServerWithNilTopoServer(config)
 	testUtils := newTestUtils()
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -50,6 +51,18 @@ type ExecPlan struct {
 	Rules      *QueryRules
 	Authorized *tableacl.ACLResult
 
+	// For the selects on a table with column denies in the table ACL:
+	// the table columns returned as result columns, and the other
+	// table columns read, see planbuilder.AnalyzeSelectColumns.
+	ResultColumns []string
+	OtherColumns  map[string]bool
+
+	// RestrictedTables are the tables with restrictions in the table
+	// ACL which the query reads other than as the single table of a
+	// select. The restrictions cannot be enforced there, see
+	// checkRestrictedTables.
+	RestrictedTables map[string]*tableacl.ACLResult
+
 	mu         sync.Mutex
 	QueryCount int64
 	Time       time.Duration
@@ -63,6 +76,30 @@ func (*ExecPlan) Size() int {
 	return 1
 }
 
+// setAuthorized looks up the table ACL of the plan, and analyzes the
+// columns it reads if the table has restrictions. It also looks up the
+// restrictions of all the other tables the query reads.
+func (ep *ExecPlan) setAuthorized() {
+	ep.Authorized = tableacl.Authorized(ep.TableName, ep.PlanID.MinRole())
+	analyzed := ep.Select != nil && ep.TableInfo != nil
+	if ep.Authorized.Restrictions != nil && analyzed {
+		ep.ResultColumns, ep.OtherColumns = planbuilder.AnalyzeSelectColumns(ep.Select, ep.TableInfo.Table)
+	}
+	for table, count := range ep.ReadTables {
+		if analyzed && table == ep.TableName && count == 1 {
+			continue
+		}
+		result := tableacl.Authorized(table, tableacl.READER)
+		if result.Restrictions == nil {
+			continue
+		}
+		if ep.RestrictedTables == nil {
+			ep.RestrictedTables = make(map[string]*tableacl.ACLResult)
+		}
+		ep.RestrictedTables[table] = result
+	}
+}
+
 // AddStats updates the stats for the current ExecPlan.
 func (ep *ExecPlan) AddStats(queryCount int64, duration, mysqlTime time.Duration, rowCount, errorCount int64) {
 	ep.mu.Lock()
@@ -93,6 +130,11 @@ type SchemaInfo struct {
 	tables     map[string]*TableInfo
 	lastChange int64
 	reloadTime time.Duration
+	// version identifies the current schema. It changes every
+	// time a table is loaded, changed or dropped.
+	version int64
+	// notifier is called after the schema changed, see SetNotifier.
+	notifier func()
 
 	// actionMutex serializes all calls to state-altering methods:
 	// Open, Close, Reload, DropTable, CreateOrUpdateTable.
@@ -217,7 +259,9 @@ func (si *SchemaInfo) Open(dbaParams *sqldb.ConnParams, strictMode bool) {
 		defer si.mu.Unlock()
 		si.tables = tables
 		si.lastChange = curTime
+		si.schemaChangedLocked()
 	}()
+	si.notify()
 	// Clear is not really needed. Doing it for good measure.
 	si.queries.Clear()
 	si.ticks.Start(func() {
@@ -265,6 +309,14 @@ func (si *SchemaInfo) Reload(ctx context.Context) error {
 		return nil
 	}
 
+	// Notify once, after mu is released, if tables were reloaded.
+	version := si.Version()
+	defer func() {
+		if si.Version() != version {
+			si.notify()
+		}
+	}()
+
 	// Get time first because it needs a connection from the pool.
 	curTime := si.mysqlTime(ctx)
 
@@ -332,7 +384,12 @@ func (si *SchemaInfo) ClearQueryPlanCache() {
 func (si *SchemaInfo) CreateOrUpdateTable(ctx context.Context, tableName string) error {
 	si.actionMutex.Lock()
 	defer si.actionMutex.Unlock()
-	return si.createOrUpdateTableLocked(ctx, tableName)
+	version := si.Version()
+	err := si.createOrUpdateTableLocked(ctx, tableName)
+	if si.Version() != version {
+		si.notify()
+	}
+	return err
 }
 
 // createOrUpdateTableLocked must only be called while holding actionMutex.
@@ -375,6 +432,7 @@ func (si *SchemaInfo) createOrUpdateTableLocked(ctx context.Context, tableName s
 		log.Infof("Updating table %s", tableName)
 	}
 	si.tables[tableName] = tableInfo
+	si.schemaChangedLocked()
 
 	switch tableInfo.Type {
 	case schema.NoType:
@@ -390,12 +448,83 @@ func (si *SchemaInfo) DropTable(tableName string) {
 	si.actionMutex.Lock()
 	defer si.actionMutex.Unlock()
 
+	func() {
+		si.mu.Lock()
+		defer si.mu.Unlock()
+
+		delete(si.tables, tableName)
+		si.schemaChangedLocked()
+		si.queries.Clear()
+		log.Infof("Table %s forgotten", tableName)
+	}()
+	si.notify()
+}
+
+// schemaChangedLocked assigns a new version to the schema. It must be
+// called with mu held.
+func (si *SchemaInfo) schemaChangedLocked() {
+	// Versions are timestamps, so they also change across restarts.
+	version := time.Now().UnixNano()
+	if version <= si.version {
+		version = si.version + 1
+	}
+	si.version = version
+}
+
+// SetNotifier sets the function to call after every schema change.
+// It is called without holding mu, so it can call Version and
+// TableSchemas.
+func (si *SchemaInfo) SetNotifier(notifier func()) {
 	si.mu.Lock()
 	defer si.mu.Unlock()
+	si.notifier = notifier
+}
 
-	delete(si.tables, tableName)
-	si.queries.Clear()
-	log.Infof("Table %s forgotten", tableName)
+func (si *SchemaInfo) notify() {
+	si.mu.Lock()
+	notifier := si.notifier
+	si.mu.Unlock()
+	if notifier != nil {
+		notifier()
+	}
+}
+
+// Version returns the current version of the schema.
+func (si *SchemaInfo) Version() int64 {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	return si.version
+}
+
+// TableSchemas returns the columns of all the tables, sorted by table
+// name, as published by StreamHealth.
+func (si *SchemaInfo) TableSchemas() []*querypb.TableSchema {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	names := make([]string, 0, len(si.tables))
+	for name := range si.tables {
+		if name == "dual" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]*querypb.TableSchema, 0, len(names))
+	for _, name := range names {
+		table := si.tables[name]
+		ts := &querypb.TableSchema{
+			Name:    name,
+			Columns: make([]*querypb.Field, 0, len(table.Columns)),
+		}
+		for _, col := range table.Columns {
+			ts.Columns = append(ts.Columns, &querypb.Field{
+				Name: col.Name.Original(),
+				Type: col.Type,
+			})
+		}
+		result = append(result, ts)
+	}
+	return result
 }
 
 // GetPlan returns the ExecPlan that for the query. Plans are cached in a cache.LRUCache.
@@ -436,7 +565,7 @@ func (si *SchemaInfo) GetPlan(ctx context.Context, logStats *LogStats, sql strin
 	}
 	plan := &ExecPlan{ExecPlan: splan, TableInfo: tableInfo}
 	plan.Rules = si.queryRuleSources.filterByPlan(sql, plan.PlanID, plan.TableName)
-	plan.Authorized = tableacl.Authorized(plan.TableName, plan.PlanID.MinRole())
+	plan.setAuthorized()
 	if plan.PlanID.IsSelect() {
 		if plan.FieldQuery == nil {
 			log.Warningf("Cannot cache field info: %s", sql)
@@ -478,7 +607,7 @@ func (si *SchemaInfo) GetStreamPlan(sql string) *ExecPlan {
 	}
 	plan := &ExecPlan{ExecPlan: splan, TableInfo: tableInfo}
 	plan.Rules = si.queryRuleSources.filterByPlan(sql, plan.PlanID, plan.TableName)
-	plan.Authorized = tableacl.Authorized(plan.TableName, plan.PlanID.MinRole())
+	plan.setAuthorized()
 	return plan
 }
 
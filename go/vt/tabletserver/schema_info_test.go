@@ -10,6 +10,7 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
@@ -296,6 +297,48 @@ func TestSchemaInfoDropTable(t *testing.T) {
 	schemaInfo.Close()
 }
 
+func TestSchemaInfoVersion(t *testing.T) {
+	db := fakesqldb.Register()
+	for query, result := range getSchemaInfoTestSupportedQueries() {
+		db.AddQuery(query, result)
+	}
+	schemaInfo := newTestSchemaInfo(10, 1*time.Second, 1*time.Second, false)
+	notified := 0
+	schemaInfo.SetNotifier(func() {
+		notified++
+	})
+	dbaParams := sqldb.ConnParams{Engine: db.Name}
+	schemaInfo.Open(&dbaParams, false)
+	defer schemaInfo.Close()
+
+	version := schemaInfo.Version()
+	if version == 0 || notified != 1 {
+		t.Errorf("after Open: version = %v, notified = %v, want non-zero version and 1 notification", version, notified)
+	}
+	want := []*querypb.TableSchema{{
+		Name:    "test_table_01",
+		Columns: []*querypb.Field{{Name: "pk", Type: sqltypes.Int32}},
+	}, {
+		Name:    "test_table_02",
+		Columns: []*querypb.Field{{Name: "pk", Type: sqltypes.Int32}},
+	}, {
+		Name:    "test_table_03",
+		Columns: []*querypb.Field{{Name: "pk", Type: sqltypes.Int32}},
+	}}
+	if got := schemaInfo.TableSchemas(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TableSchemas() = %v, want %v", got, want)
+	}
+
+	schemaInfo.DropTable("test_table_02")
+	if got := schemaInfo.Version(); got <= version || notified != 2 {
+		t.Errorf("after DropTable: version = %v, notified = %v, want > %v and 2 notifications", got, notified, version)
+	}
+	want = append(want[:1], want[2])
+	if got := schemaInfo.TableSchemas(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TableSchemas() = %v, want %v", got, want)
+	}
+}
+
 func TestSchemaInfoGetPlanPanicDuetoEmptyQuery(t *testing.T) {
 	db := fakesqldb.Register()
 	for query, result := range getSchemaInfoTestSupportedQueries() {
@@ -34,6 +34,9 @@ import (
 	"github.com/youtube/vitess/go/vt/tabletserver/queryservice"
 	"github.com/youtube/vitess/go/vt/tabletserver/querytypes"
 	"github.com/youtube/vitess/go/vt/tabletserver/splitquery"
+	"github.com/youtube/vitess/go/vt/tabletserver/txresolver"
+	"github.com/youtube/vitess/go/vt/tabletserver/txthrottler"
+	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/utils"
 	"golang.org/x/net/context"
 
@@ -112,6 +115,8 @@ type TabletServer struct {
 	// the context of a startRequest-endRequest.
 	qe               *QueryEngine
 	updateStreamList *binlog.StreamList
+	txThrottler      *txthrottler.TxThrottler
+	txWatchdog       *TxWatchdog
 
 	// checkMySQLThrottler is used to throttle the number of
 	// requests sent to CheckMySQL.
@@ -120,7 +125,7 @@ type TabletServer struct {
 	// streamHealthMutex protects all the following fields
 	streamHealthMutex        sync.Mutex
 	streamHealthIndex        int
-	streamHealthMap          map[int]chan<- *querypb.StreamHealthResponse
+	streamHealthMap          map[int]*streamHealthSubscriber
 	lastStreamHealthResponse *querypb.StreamHealthResponse
 
 	// history records changes in state for display on the status page.
@@ -135,6 +140,14 @@ type TabletServer struct {
 	eventToken      *querypb.EventToken
 }
 
+// streamHealthSubscriber is a StreamHealth stream.
+type streamHealthSubscriber struct {
+	c chan<- *querypb.StreamHealthResponse
+	// schemaVersion is the last schema version sent to the stream
+	// along with the table schemas.
+	schemaVersion int64
+}
+
 // RegisterFunction is a callback type to be called when we
 // Register() a TabletServer
 type RegisterFunction func(Controller)
@@ -150,19 +163,56 @@ type MySQLChecker interface {
 	CheckMySQL()
 }
 
+// NewTabletServerWithNilTopoServer is typically used in tests that
+// don't need a topoServer member.
+func NewTabletServerWithNilTopoServer(config Config) *TabletServer {
+	return NewTabletServer(config, topo.Server{})
+}
+
 // NewTabletServer creates an instance of TabletServer. Only one instance
-// of TabletServer can be created per process.
-func NewTabletServer(config Config) *TabletServer {
+// of TabletServer can be created per process. topoServer is the topology
+// of the tablet, used by the transaction throttler and the resolver of
+// the abandoned distributed transactions.
+func NewTabletServer(config Config, topoServer topo.Server) *TabletServer {
 	tsv := &TabletServer{
 		config:              config,
 		QueryTimeout:        sync2.NewAtomicDuration(time.Duration(config.QueryTimeout * 1e9)),
 		BeginTimeout:        sync2.NewAtomicDuration(time.Duration(config.TxPoolTimeout * 1e9)),
 		checkMySQLThrottler: sync2.NewSemaphore(1, 0),
-		streamHealthMap:     make(map[int]chan<- *querypb.StreamHealthResponse),
+		streamHealthMap:     make(map[int]*streamHealthSubscriber),
 		history:             history.New(10),
 	}
 	tsv.qe = NewQueryEngine(tsv, config)
+	if config.PublishSchema {
+		tsv.qe.schemaInfo.SetNotifier(tsv.broadcastSchemaChange)
+	}
 	tsv.updateStreamList = &binlog.StreamList{}
+	txThrottler, err := txthrottler.NewTxThrottler(
+		config.EnableTxThrottler,
+		config.TxThrottlerConfig,
+		config.TxThrottlerKeyspaceConfigs,
+		config.TxThrottlerHealthCheckCells,
+		time.Duration(config.TxThrottlerMaxWait*1e9),
+		topoServer,
+		config.StatsPrefix,
+		config.EnablePublishStats,
+	)
+	if err != nil {
+		log.Fatalf("Cannot create the transaction throttler: %v", err)
+	}
+	tsv.txThrottler = txThrottler
+	var resolver txresolver.QueryService
+	if config.TwoPCAutoResolve {
+		resolver = txresolver.NewTopoQueryService(topoServer, twoPCResolverConnectTimeout)
+	}
+	tsv.txWatchdog = NewTxWatchdog(
+		tsv.qe,
+		time.Duration(config.TwoPCAbandonAge*1e9),
+		time.Duration(config.QueryTimeout*1e9),
+		resolver,
+		config.StatsPrefix,
+		config.EnablePublishStats,
+	)
 	if config.EnablePublishStats {
 		stats.Publish(config.StatsPrefix+"TabletState", stats.IntFunc(func() int64 {
 			tsv.mu.Lock()
@@ -403,6 +453,8 @@ func (tsv *TabletServer) serveNewType() (err error) {
 	defer func() {
 		if x := recover(); x != nil {
 			log.Errorf("Could not start tabletserver: %v", x)
+			tsv.txWatchdog.Close()
+			tsv.txThrottler.Close()
 			tsv.qe.Close()
 			tsv.updateStreamList.Stop()
 			tsv.stopReplicationStreamer()
@@ -411,17 +463,23 @@ func (tsv *TabletServer) serveNewType() (err error) {
 		}
 	}()
 	if tsv.target.TabletType == topodatapb.TabletType_MASTER {
+		if err := tsv.txThrottler.Open(tsv.target.Keyspace, tsv.target.Shard); err != nil {
+			log.Errorf("Could not open the transaction throttler: %v", err)
+		}
 		err = tsv.qe.PrepareFromRedo()
 		if err != nil {
 			// TODO(sougou): raise alarms.
 			log.Errorf("Could not prepare transactions: %v", err)
 		}
+		tsv.txWatchdog.Open()
 	} else {
 		// Wait for in-flight transactional requests to complete
 		// before rolling back everything. In this state new
 		// transactional requests are not allowed. So, we can
 		// be sure that the tx pool won't change after the wait.
 		tsv.txRequests.Wait()
+		tsv.txWatchdog.Close()
+		tsv.txThrottler.Close()
 		tsv.qe.RollbackTransactions()
 		tsv.startReplicationStreamer()
 	}
@@ -458,6 +516,8 @@ func (tsv *TabletServer) StopService() {
 		tsv.transition(StateNotConnected)
 	}()
 	log.Infof("Shutting down query service")
+	tsv.txWatchdog.Close()
+	tsv.txThrottler.Close()
 	tsv.qe.Close()
 }
 
@@ -644,6 +704,9 @@ func (tsv *TabletServer) Begin(ctx context.Context, target *querypb.Target) (tra
 		target, true, false,
 		func(ctx context.Context, logStats *LogStats) error {
 			defer tsv.qe.queryServiceStats.QueryStats.Record("BEGIN", time.Now())
+			if tsv.txThrottler.Throttle(ctx) {
+				return NewTabletError(vtrpcpb.ErrorCode_RESOURCE_EXHAUSTED, "Transaction throttled")
+			}
 			transactionID, err = tsv.qe.txPool.Begin(ctx)
 			logStats.TransactionID = transactionID
 			return err
@@ -1397,9 +1460,15 @@ func (tsv *TabletServer) StreamHealthRegister(c chan<- *querypb.StreamHealthResp
 
 	id := tsv.streamHealthIndex
 	tsv.streamHealthIndex++
-	tsv.streamHealthMap[id] = c
-	if tsv.lastStreamHealthResponse != nil {
-		c <- tsv.lastStreamHealthResponse
+	sub := &streamHealthSubscriber{c: c}
+	tsv.streamHealthMap[id] = sub
+	if shr := tsv.lastStreamHealthResponse; shr != nil {
+		// The first response of a stream has the table schemas.
+		if shr.SchemaVersion != 0 {
+			shr = tsv.withTableSchemas(shr)
+			sub.schemaVersion = shr.SchemaVersion
+		}
+		c <- shr
 	}
 	return id, nil
 }
@@ -1474,17 +1543,59 @@ func (tsv *TabletServer) BroadcastHealth(terTimestamp int64, stats *querypb.Real
 		TabletExternallyReparentedTimestamp: terTimestamp,
 		RealtimeStats:                       stats,
 	}
+	if tsv.config.PublishSchema {
+		shr.SchemaVersion = tsv.qe.schemaInfo.Version()
+	}
 
 	tsv.streamHealthMutex.Lock()
 	defer tsv.streamHealthMutex.Unlock()
-	for _, c := range tsv.streamHealthMap {
+	tsv.broadcastHealthLocked(shr)
+	tsv.lastStreamHealthResponse = shr
+}
+
+// broadcastSchemaChange is the SchemaInfo notifier. It sends the new
+// schema to all the health streams right away, instead of waiting
+// for the next BroadcastHealth.
+func (tsv *TabletServer) broadcastSchemaChange() {
+	tsv.streamHealthMutex.Lock()
+	defer tsv.streamHealthMutex.Unlock()
+	if tsv.lastStreamHealthResponse == nil {
+		// The first BroadcastHealth will send the schema.
+		return
+	}
+	shr := *tsv.lastStreamHealthResponse
+	shr.SchemaVersion = tsv.qe.schemaInfo.Version()
+	tsv.broadcastHealthLocked(&shr)
+	tsv.lastStreamHealthResponse = &shr
+}
+
+// broadcastHealthLocked sends shr to all the health streams. The
+// streams that haven't received its schema version yet get the table
+// schemas too. It must be called with streamHealthMutex held.
+func (tsv *TabletServer) broadcastHealthLocked(shr *querypb.StreamHealthResponse) {
+	var withSchemas *querypb.StreamHealthResponse
+	for _, sub := range tsv.streamHealthMap {
+		msg := shr
+		if sub.schemaVersion != shr.SchemaVersion {
+			if withSchemas == nil {
+				withSchemas = tsv.withTableSchemas(shr)
+			}
+			msg = withSchemas
+		}
 		// do not block on any write
 		select {
-		case c <- shr:
+		case sub.c <- msg:
+			sub.schemaVersion = shr.SchemaVersion
 		default:
 		}
 	}
-	tsv.lastStreamHealthResponse = shr
+}
+
+// withTableSchemas returns a copy of shr with the table schemas.
+func (tsv *TabletServer) withTableSchemas(shr *querypb.StreamHealthResponse) *querypb.StreamHealthResponse {
+	result := *shr
+	result.TableSchemas = tsv.qe.schemaInfo.TableSchemas()
+	return &result
 }
 
 // startRequest validates the current state and target and registers
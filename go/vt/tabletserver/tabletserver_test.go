@@ -45,7 +45,7 @@ func TestTabletServerGetState(t *testing.T) {
 	setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	for i, state := range states {
 		tsv.setState(state)
 		if stateName := tsv.GetState(); stateName != names[i] {
@@ -63,7 +63,7 @@ func TestTabletServerAllowQueriesFailBadConn(t *testing.T) {
 	db.EnableConnFail()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	checkTabletServerState(t, tsv, StateNotConnected)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
@@ -78,7 +78,7 @@ func TestTabletServerAllowQueries(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	checkTabletServerState(t, tsv, StateNotConnected)
 	dbconfigs := testUtils.newDBConfigs(db)
 	tsv.setState(StateServing)
@@ -101,7 +101,7 @@ func TestTabletServerInitDBConfig(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	tsv.setState(StateServing)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	dbconfigs := testUtils.newDBConfigs(db)
@@ -121,7 +121,7 @@ func TestDecideAction(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	dbconfigs := testUtils.newDBConfigs(db)
 	err := tsv.InitDBConfig(target, dbconfigs, nil)
@@ -227,7 +227,7 @@ func TestSetServingType(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.InitDBConfig(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -327,7 +327,7 @@ func TestTabletServerSingleSchemaFailure(t *testing.T) {
 
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	originalSchemaErrorCount := tsv.qe.queryServiceStats.InternalErrors.Counts()["Schema"]
@@ -377,7 +377,7 @@ func TestTabletServerAllSchemaFailure(t *testing.T) {
 
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -390,7 +390,7 @@ func TestTabletServerCheckMysql(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -418,7 +418,7 @@ func TestTabletServerCheckMysqlFailInvalidConn(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -438,7 +438,7 @@ func TestTabletServerCheckMysqlInUnintialized(t *testing.T) {
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
 	config.EnablePublishStats = true
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	// TabletServer start request fail because we are in StateNotConnected;
 	// however, isMySQLReachable should return true. Here, we always assume
 	// MySQL is healthy unless we've verified it is not.
@@ -466,7 +466,7 @@ func TestTabletServerReconnect(t *testing.T) {
 	db.AddQuery("select addr from test_table where 1 != 1", &sqltypes.Result{})
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -510,7 +510,7 @@ func TestTabletServerTarget(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target1 := querypb.Target{
 		Keyspace:   "test_keyspace",
@@ -597,6 +597,66 @@ func TestTabletServerTarget(t *testing.T) {
 	}
 }
 
+func TestTabletServerStreamHealthSchema(t *testing.T) {
+	db := setUpTabletServerTest()
+	testUtils := newTestUtils()
+	config := testUtils.newQueryServiceConfig()
+	config.PublishSchema = true
+	tsv := NewTabletServerWithNilTopoServer(config)
+	dbconfigs := testUtils.newDBConfigs(db)
+	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
+	if err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs)); err != nil {
+		t.Fatalf("StartService failed: %v", err)
+	}
+	defer tsv.StopService()
+
+	c1 := make(chan *querypb.StreamHealthResponse, 10)
+	if _, err := tsv.StreamHealthRegister(c1); err != nil {
+		t.Fatalf("StreamHealthRegister failed: %v", err)
+	}
+	wantSchemas := []*querypb.TableSchema{{
+		Name:    "test_table",
+		Columns: getTestTableFields(),
+	}}
+	checkHealth := func(c chan *querypb.StreamHealthResponse, wantSchemas []*querypb.TableSchema) *querypb.StreamHealthResponse {
+		select {
+		case shr := <-c:
+			if shr.SchemaVersion != tsv.qe.schemaInfo.Version() {
+				t.Errorf("SchemaVersion = %v, want %v", shr.SchemaVersion, tsv.qe.schemaInfo.Version())
+			}
+			if !reflect.DeepEqual(shr.TableSchemas, wantSchemas) {
+				t.Errorf("TableSchemas = %v, want %v", shr.TableSchemas, wantSchemas)
+			}
+			return shr
+		default:
+			t.Fatalf("no health response")
+		}
+		return nil
+	}
+
+	// The first response of the stream has the table schemas, not
+	// the next ones.
+	tsv.BroadcastHealth(0, nil)
+	checkHealth(c1, wantSchemas)
+	tsv.BroadcastHealth(0, nil)
+	checkHealth(c1, nil)
+
+	// A new stream gets the table schemas right away.
+	c2 := make(chan *querypb.StreamHealthResponse, 10)
+	if _, err := tsv.StreamHealthRegister(c2); err != nil {
+		t.Fatalf("StreamHealthRegister failed: %v", err)
+	}
+	checkHealth(c2, wantSchemas)
+
+	// Schema changes are pushed to all the streams.
+	tsv.qe.schemaInfo.DropTable("test_table")
+	checkHealth(c1, []*querypb.TableSchema{})
+	checkHealth(c2, []*querypb.TableSchema{})
+	tsv.BroadcastHealth(0, nil)
+	checkHealth(c1, nil)
+	checkHealth(c2, nil)
+}
+
 func TestTabletServerStopWithPrepare(t *testing.T) {
 	// Reuse code from tx_executor_test.
 	_, tsv, _ := newTestTxExecutor()
@@ -903,7 +963,7 @@ func TestTabletServerBeginFail(t *testing.T) {
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
 	config.TransactionCap = 1
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -921,6 +981,74 @@ func TestTabletServerBeginFail(t *testing.T) {
 	}
 }
 
+func TestTabletServerHotRowProtection(t *testing.T) {
+	db := setUpQueryExecutorTest()
+	db.AddQuery("update test_table set name = 2 where pk in (1) /* _stream test_table (pk ) (1 ); */", &sqltypes.Result{})
+	db.AddQuery("update test_table set name = 2 where pk in (2) /* _stream test_table (pk ) (2 ); */", &sqltypes.Result{})
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, enableHotRowProtection, db)
+	defer tsv.StopService()
+	tsv.qe.txSerializer.maxQueueSize = 1
+
+	// Another transaction is updating the row.
+	txDone, err := tsv.qe.txSerializer.Wait(ctx, "test_table (1)", "test_table")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	query := "update test_table set name = 2 where pk = 1"
+	want := "hot row protection: too many queued transactions (1 >= 1) for the same row (table: test_table)"
+	_, transactionID, err := tsv.BeginExecute(ctx, &tsv.target, query, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("BeginExecute: %v, must contain %s", err, want)
+	}
+	if err := tsv.Rollback(ctx, &tsv.target, transactionID); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	transactionID, err = tsv.Begin(ctx, &tsv.target)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if _, err := tsv.Execute(ctx, &tsv.target, query, nil, transactionID, nil); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Execute in a transaction: %v, must contain %s", err, want)
+	}
+	if err := tsv.Rollback(ctx, &tsv.target, transactionID); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if _, err := tsv.Execute(ctx, &tsv.target, query, nil, 0, nil); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Execute: %v, must contain %s", err, want)
+	}
+	if got := tsv.qe.txSerializer.queueExceeded.Counts()["test_table"]; got != 3 {
+		t.Errorf("queueExceeded: %v, want 3", got)
+	}
+
+	// The other rows are not affected.
+	if _, err := tsv.Execute(ctx, &tsv.target, "update test_table set name = 2 where pk = 2", nil, 0, nil); err != nil {
+		t.Errorf("Execute on another row failed: %v", err)
+	}
+
+	txDone()
+	_, transactionID, err = tsv.BeginExecute(ctx, &tsv.target, query, nil, nil)
+	if err != nil {
+		t.Fatalf("BeginExecute failed: %v", err)
+	}
+	// The transaction holds the row lock now: its next DMLs on the
+	// row don't wait for the transactions queued after it.
+	txDone, err = tsv.qe.txSerializer.Wait(ctx, "test_table (1)", "test_table")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if _, err := tsv.Execute(ctx, &tsv.target, query, nil, transactionID, nil); err != nil {
+		t.Errorf("Execute of a locked row failed: %v", err)
+	}
+	txDone()
+	if err := tsv.Commit(ctx, &tsv.target, transactionID); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if got := tsv.qe.txSerializer.Queued(); got != 0 {
+		t.Errorf("Queued: %v, want 0", got)
+	}
+}
+
 func TestTabletServerCommitTransaction(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
@@ -934,7 +1062,7 @@ func TestTabletServerCommitTransaction(t *testing.T) {
 	}
 	db.AddQuery(executeSQL, executeSQLResult)
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -959,7 +1087,7 @@ func TestTabletServerCommiRollbacktFail(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -992,7 +1120,7 @@ func TestTabletServerRollback(t *testing.T) {
 	}
 	db.AddQuery(executeSQL, executeSQLResult)
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1089,7 +1217,7 @@ func TestTabletServerStreamExecute(t *testing.T) {
 	db.AddQuery(executeSQL, executeSQLResult)
 
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1115,7 +1243,7 @@ func TestTabletServerExecuteBatch(t *testing.T) {
 	db.AddQuery(sql, sqlResult)
 	db.AddQuery(expanedSQL, sqlResult)
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1139,7 +1267,7 @@ func TestTabletServerExecuteBatchFailEmptyQueryList(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1156,7 +1284,7 @@ func TestTabletServerExecuteBatchFailAsTransaction(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1180,7 +1308,7 @@ func TestTabletServerExecuteBatchBeginFail(t *testing.T) {
 	// make "begin" query fail
 	db.AddRejectedQuery("begin", errRejected)
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1205,7 +1333,7 @@ func TestTabletServerExecuteBatchCommitFail(t *testing.T) {
 	// make "commit" query fail
 	db.AddRejectedQuery("commit", errRejected)
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1243,7 +1371,7 @@ func TestTabletServerExecuteBatchSqlExecFailInTransaction(t *testing.T) {
 	db.AddRejectedQuery(expanedSQL, errRejected)
 
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1285,7 +1413,7 @@ func TestTabletServerExecuteBatchSqlSucceedInTransaction(t *testing.T) {
 
 	config := testUtils.newQueryServiceConfig()
 	config.EnableAutoCommit = true
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1308,7 +1436,7 @@ func TestTabletServerExecuteBatchCallCommitWithoutABegin(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1337,7 +1465,7 @@ func TestExecuteBatchNestedTransaction(t *testing.T) {
 	db.AddQuery(sql, sqlResult)
 	db.AddQuery(expanedSQL, sqlResult)
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1400,7 +1528,7 @@ func TestTabletServerSplitQuery(t *testing.T) {
 	})
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1432,7 +1560,7 @@ func TestTabletServerSplitQueryV2(t *testing.T) {
 	})
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_RDONLY}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1486,7 +1614,7 @@ func TestTabletServerSplitQueryInvalidQuery(t *testing.T) {
 	})
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1507,7 +1635,7 @@ func TestTabletServerSplitQueryV2InvalidQuery(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_RDONLY}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1564,7 +1692,7 @@ func TestTabletServerSplitQueryInvalidMinMax(t *testing.T) {
 	db.AddQuery(pkMinMaxQuery, pkMinMaxQueryResp)
 
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1585,7 +1713,7 @@ func TestTabletServerSplitQueryV2InvalidParams(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_RDONLY}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
@@ -1615,7 +1743,7 @@ func TestHandleExecUnknownError(t *testing.T) {
 	var err error
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	defer tsv.handleError("select * from test_table", nil, &err, logStats)
 	panic("unknown exec error")
 }
@@ -1632,7 +1760,7 @@ func TestHandleExecTabletError(t *testing.T) {
 	}()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	defer tsv.handleError("select * from test_table", nil, &err, logStats)
 	panic(NewTabletError(vtrpcpb.ErrorCode_INTERNAL_ERROR, "tablet error"))
 }
@@ -1649,7 +1777,7 @@ func TestTerseErrorsNonSQLError(t *testing.T) {
 	}()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	tsv.config.TerseErrors = true
 	defer tsv.handleError("select * from test_table", nil, &err, logStats)
 	panic(NewTabletError(vtrpcpb.ErrorCode_INTERNAL_ERROR, "tablet error"))
@@ -1667,7 +1795,7 @@ func TestTerseErrorsBindVars(t *testing.T) {
 	}()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	tsv.config.TerseErrors = true
 	defer tsv.handleError("select * from test_table", map[string]interface{}{"a": 1}, &err, logStats)
 	panic(&TabletError{
@@ -1690,7 +1818,7 @@ func TestTerseErrorsNoBindVars(t *testing.T) {
 	}()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	tsv.config.TerseErrors = true
 	defer tsv.handleError("select * from test_table", nil, &err, logStats)
 	panic(&TabletError{
@@ -1704,7 +1832,7 @@ func TestConfigChanges(t *testing.T) {
 	db := setUpTabletServerTest()
 	testUtils := newTestUtils()
 	config := testUtils.newQueryServiceConfig()
-	tsv := NewTabletServer(config)
+	tsv := NewTabletServerWithNilTopoServer(config)
 	dbconfigs := testUtils.newDBConfigs(db)
 	target := querypb.Target{TabletType: topodatapb.TabletType_MASTER}
 	err := tsv.StartService(target, dbconfigs, testUtils.newMysqld(&dbconfigs))
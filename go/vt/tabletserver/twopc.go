@@ -75,6 +75,7 @@ type TwoPC struct {
 	deleteParticipants *sqlparser.ParsedQuery
 	readTransaction    *sqlparser.ParsedQuery
 	readParticipants   *sqlparser.ParsedQuery
+	readAbandoned      *sqlparser.ParsedQuery
 }
 
 // NewTwoPC creates a TwoPC variable.
@@ -142,6 +143,9 @@ func (tpc *TwoPC) Open(sidecarDBName string, dbaparams *sqldb.ConnParams) {
 	tpc.readParticipants = buildParsedQuery(
 		"select keyspace, shard from `%s`.participant where dtid = %a",
 		sidecarDBName, ":dtid")
+	tpc.readAbandoned = buildParsedQuery(
+		"select dtid, time_created from `%s`.transaction where time_created < %a",
+		sidecarDBName, ":time_created")
 }
 
 func buildParsedQuery(in string, vars ...interface{}) *sqlparser.ParsedQuery {
@@ -327,6 +331,27 @@ func (tpc *TwoPC) ReadTransaction(ctx context.Context, conn *DBConn, dtid string
 	return result, nil
 }
 
+// ReadAbandoned returns the dtids of the transactions which were
+// created before abandonTime, with their creation time.
+func (tpc *TwoPC) ReadAbandoned(ctx context.Context, conn *DBConn, abandonTime time.Time) (map[string]time.Time, error) {
+	bindVars := map[string]interface{}{
+		"time_created": abandonTime.UnixNano(),
+	}
+	qr, err := tpc.read(ctx, conn, tpc.readAbandoned, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	txs := make(map[string]time.Time, len(qr.Rows))
+	for _, row := range qr.Rows {
+		t, err := row[1].ParseInt64()
+		if err != nil {
+			return nil, err
+		}
+		txs[row[0].String()] = time.Unix(0, t)
+	}
+	return txs, nil
+}
+
 func (tpc *TwoPC) exec(ctx context.Context, conn *TxConnection, pq *sqlparser.ParsedQuery, bindVars map[string]interface{}) (*sqltypes.Result, error) {
 	b, err := pq.GenerateQuery(bindVars)
 	if err != nil {
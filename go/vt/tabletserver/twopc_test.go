@@ -8,6 +8,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/youtube/vitess/go/sqltypes"
 )
@@ -98,3 +99,31 @@ func TestReadPrepared(t *testing.T) {
 		t.Errorf("ReadPrepared: %#v, want %#v", got, want)
 	}
 }
+
+func TestReadAbandoned(t *testing.T) {
+	_, tsv, db := newTestTxExecutor()
+	defer tsv.StopService()
+	tpc := tsv.qe.twoPC
+	ctx := context.Background()
+
+	conn, err := tsv.qe.connPool.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Recycle()
+
+	db.AddQuery("select dtid, time_created from `_vt`.transaction where time_created < 1000", &sqltypes.Result{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeString([]byte("dtid0")),
+			sqltypes.MakeString([]byte("1")),
+		}},
+	})
+	got, err := tpc.ReadAbandoned(ctx, conn, time.Unix(0, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Time{"dtid0": time.Unix(0, 1)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAbandoned: %#v, want %#v", got, want)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/youtube/vitess/go/streamlog"
 	"github.com/youtube/vitess/go/sync2"
 	"github.com/youtube/vitess/go/timer"
+	"github.com/youtube/vitess/go/trace"
 	"github.com/youtube/vitess/go/vt/callerid"
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
 	vtrpcpb "github.com/youtube/vitess/go/vt/proto/vtrpc"
@@ -139,6 +140,11 @@ func (axp *TxPool) WaitForEmpty() {
 // Begin begins a transaction, and returns the associated transaction id.
 // Subsequent statements can access the connection through the transaction id.
 func (axp *TxPool) Begin(ctx context.Context) (int64, error) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("TxPool.Begin")
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
 	poolCtx := ctx
 	if deadline, ok := ctx.Deadline(); ok {
 		var cancel func()
@@ -215,6 +221,11 @@ func (axp *TxPool) LocalBegin(ctx context.Context) (*TxConnection, error) {
 
 // LocalCommit is the commit function for LocalBegin.
 func (axp *TxPool) LocalCommit(ctx context.Context, conn *TxConnection) error {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("TxPool.LocalCommit")
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
 	defer conn.conclude(TxCommit)
 	axp.txStats.Add("Completed", time.Now().Sub(conn.StartTime))
 	if _, err := conn.Exec(ctx, "commit", 1, false); err != nil {
@@ -233,6 +244,11 @@ func (axp *TxPool) LocalConclude(ctx context.Context, conn *TxConnection) {
 }
 
 func (axp *TxPool) localRollback(ctx context.Context, conn *TxConnection) error {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartLocal("TxPool.Rollback")
+	defer span.Finish()
+	ctx = trace.NewContext(ctx, span)
+
 	defer conn.conclude(TxRollback)
 	axp.txStats.Add("Aborted", time.Now().Sub(conn.StartTime))
 	if _, err := conn.Exec(ctx, "rollback", 1, false); err != nil {
@@ -282,6 +298,22 @@ type TxConnection struct {
 	LogToFile         sync2.AtomicInt32
 	ImmediateCallerID *querypb.VTGateCallerID
 	EffectiveCallerID *vtrpcpb.CallerID
+
+	// savepoints are the active savepoints of the transaction,
+	// in the order they were set.
+	savepoints []txSavepoint
+
+	// serializedRows are the keys of the rows the transaction
+	// updated after waiting in the TxSerializer. MySQL holds their
+	// locks, so the next DMLs on them don't wait again.
+	serializedRows map[string]bool
+}
+
+// txSavepoint is a savepoint of a TxConnection, with the number
+// of queries recorded when it was set.
+type txSavepoint struct {
+	name    string
+	queries int
 }
 
 func newTxConnection(conn *DBConn, transactionID int64, pool *TxPool, immediate *querypb.VTGateCallerID, effective *vtrpcpb.CallerID) *TxConnection {
@@ -298,6 +330,10 @@ func newTxConnection(conn *DBConn, transactionID int64, pool *TxPool, immediate
 
 // Exec executes the statement for the current transaction.
 func (txc *TxConnection) Exec(ctx context.Context, query string, maxrows int, wantfields bool) (*sqltypes.Result, error) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartClient("TxConnection.Exec")
+	defer span.Finish()
+
 	r, err := txc.DBConn.ExecOnce(ctx, query, maxrows, wantfields)
 	if err != nil {
 		if IsConnErr(err) {
@@ -324,6 +360,50 @@ func (txc *TxConnection) RecordQuery(query string) {
 	txc.Queries = append(txc.Queries, query)
 }
 
+// Savepoint records a savepoint set in the transaction. Like in MySQL,
+// it replaces an existing savepoint with the same name.
+func (txc *TxConnection) Savepoint(name string) {
+	if i := txc.findSavepoint(name); i >= 0 {
+		txc.savepoints = append(txc.savepoints[:i], txc.savepoints[i+1:]...)
+	}
+	txc.savepoints = append(txc.savepoints, txSavepoint{name: name, queries: len(txc.Queries)})
+}
+
+// RollbackToSavepoint forgets the queries recorded after the savepoint,
+// so that they are not part of the redo log if the transaction is
+// prepared. The savepoints set after it are removed.
+func (txc *TxConnection) RollbackToSavepoint(name string) error {
+	i := txc.findSavepoint(name)
+	if i < 0 {
+		return NewTabletError(vtrpcpb.ErrorCode_BAD_INPUT, "savepoint %s does not exist", name)
+	}
+	txc.Queries = txc.Queries[:txc.savepoints[i].queries]
+	txc.savepoints = txc.savepoints[:i+1]
+	return nil
+}
+
+// ReleaseSavepoint removes the savepoint, and the savepoints set
+// after it. The queries recorded after it are kept.
+func (txc *TxConnection) ReleaseSavepoint(name string) error {
+	i := txc.findSavepoint(name)
+	if i < 0 {
+		return NewTabletError(vtrpcpb.ErrorCode_BAD_INPUT, "savepoint %s does not exist", name)
+	}
+	txc.savepoints = txc.savepoints[:i]
+	return nil
+}
+
+// findSavepoint returns the index of the savepoint, or -1. Savepoint
+// names are case-insensitive.
+func (txc *TxConnection) findSavepoint(name string) int {
+	for i, sp := range txc.savepoints {
+		if strings.EqualFold(sp.name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (txc *TxConnection) conclude(conclusion string) {
 	txc.pool.activePool.Unregister(txc.TransactionID)
 	txc.DBConn.Recycle()
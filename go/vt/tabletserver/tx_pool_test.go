@@ -7,6 +7,7 @@ package tabletserver
 import (
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -392,3 +393,37 @@ func newTxPool(enablePublishStats bool) *TxPool {
 		DummyChecker,
 	)
 }
+
+func TestTxConnectionSavepoints(t *testing.T) {
+	txc := &TxConnection{}
+	txc.RecordQuery("q1")
+	txc.Savepoint("a")
+	txc.RecordQuery("q2")
+	txc.Savepoint("b")
+	txc.RecordQuery("q3")
+	// Setting an existing savepoint again moves it.
+	txc.Savepoint("A")
+	txc.RecordQuery("q4")
+	if err := txc.RollbackToSavepoint("a"); err != nil {
+		t.Fatalf("RollbackToSavepoint(a) = %v, want nil", err)
+	}
+	if want := []string{"q1", "q2", "q3"}; !reflect.DeepEqual(txc.Queries, want) {
+		t.Errorf("queries: %v, want %v", txc.Queries, want)
+	}
+	if err := txc.RollbackToSavepoint("b"); err != nil {
+		t.Fatalf("RollbackToSavepoint(b) = %v, want nil", err)
+	}
+	if want := []string{"q1", "q2"}; !reflect.DeepEqual(txc.Queries, want) {
+		t.Errorf("queries: %v, want %v", txc.Queries, want)
+	}
+	// Rolling back to b removed a, which was set after it.
+	if err := txc.ReleaseSavepoint("a"); err == nil {
+		t.Error("ReleaseSavepoint(a) = nil, want error")
+	}
+	if err := txc.ReleaseSavepoint("b"); err != nil {
+		t.Errorf("ReleaseSavepoint(b) = %v, want nil", err)
+	}
+	if err := txc.RollbackToSavepoint("b"); err == nil {
+		t.Error("RollbackToSavepoint(b) after release = nil, want error")
+	}
+}
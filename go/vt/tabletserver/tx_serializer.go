@@ -0,0 +1,223 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tabletserver
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/vt/tabletserver/planbuilder"
+	"golang.org/x/net/context"
+
+	vtrpcpb "github.com/youtube/vitess/go/vt/proto/vtrpc"
+)
+
+// TxSerializer serializes the transactions which update the same
+// rows (hot row protection).
+//
+// When many transactions update the same row, they all wait for the
+// InnoDB row lock in MySQL, with a connection each, until the pool is
+// exhausted and the other transactions fail too. With the
+// TxSerializer, the DMLs which update the same rows are queued in
+// vttablet: the autocommit DMLs before they take a TxPool connection,
+// and the DMLs of transactions before they are sent to MySQL. Only one
+// of them at a time executes and then lets the next one go. The next
+// one then waits for the row lock in MySQL, and the others keep
+// waiting in the queue. The queues are bounded, and the DMLs which
+// don't fit are rejected with a retryable error.
+//
+// The rows are identified by the table name and the primary key values
+// of PlanDMLPK plans. The other DMLs are not serialized, nor the DMLs
+// of a transaction which already locked their rows.
+type TxSerializer struct {
+	maxQueueSize       int
+	maxGlobalQueueSize int
+
+	mu         sync.Mutex
+	queues     map[string]*txQueue
+	globalSize int
+
+	// waits counts the transactions which had to wait, per table.
+	waits *stats.Counters
+	// queueExceeded counts the transactions rejected because the
+	// queue for their row was full, per table.
+	queueExceeded *stats.Counters
+	// globalQueueExceeded counts the transactions rejected because
+	// the sum of all queues was full, per table.
+	globalQueueExceeded *stats.Counters
+}
+
+// txQueue is the queue of the transactions for one row. lock has a
+// capacity of one, and is held by the transaction being executed.
+type txQueue struct {
+	size int
+	lock chan struct{}
+}
+
+// NewTxSerializer returns a TxSerializer. maxQueueSize is the maximum
+// number of transactions queued for the same row, including the one
+// being executed. maxGlobalQueueSize is the maximum number of
+// transactions queued for all the rows.
+func NewTxSerializer(maxQueueSize, maxGlobalQueueSize int, statsPrefix string, enablePublishStats bool) *TxSerializer {
+	var waitsName, queueExceededName, globalQueueExceededName string
+	if enablePublishStats {
+		waitsName = statsPrefix + "HotRowProtectionWaits"
+		queueExceededName = statsPrefix + "HotRowProtectionQueueExceeded"
+		globalQueueExceededName = statsPrefix + "HotRowProtectionGlobalQueueExceeded"
+	}
+	txs := &TxSerializer{
+		maxQueueSize:        maxQueueSize,
+		maxGlobalQueueSize:  maxGlobalQueueSize,
+		queues:              make(map[string]*txQueue),
+		waits:               stats.NewCounters(waitsName),
+		queueExceeded:       stats.NewCounters(queueExceededName),
+		globalQueueExceeded: stats.NewCounters(globalQueueExceededName),
+	}
+	if enablePublishStats {
+		stats.Publish(statsPrefix+"HotRowProtectionQueued", stats.IntFunc(txs.Queued))
+		stats.Publish(statsPrefix+"HotRowProtectionQueues", stats.IntFunc(txs.QueueCount))
+	}
+	return txs
+}
+
+// Wait blocks until the transactions queued before this one for the
+// same key are done. The returned done function must be called when
+// the rows are locked in MySQL, or when the transaction gave up.
+// table is only used for the stats.
+func (txs *TxSerializer) Wait(ctx context.Context, key, table string) (done func(), err error) {
+	q, waiting, err := txs.enqueue(key, table)
+	if err != nil {
+		return nil, err
+	}
+	if waiting {
+		txs.waits.Add(table, 1)
+	}
+
+	select {
+	case q.lock <- struct{}{}:
+	case <-ctx.Done():
+		txs.dequeue(key, q)
+		return nil, NewTabletError(vtrpcpb.ErrorCode_DEADLINE_EXCEEDED, "context expired while waiting for the transactions updating the same row: %v", ctx.Err())
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-q.lock
+			txs.dequeue(key, q)
+		})
+	}, nil
+}
+
+// enqueue adds a transaction to the queue of key. It returns true if
+// other transactions are ahead of it.
+func (txs *TxSerializer) enqueue(key, table string) (*txQueue, bool, error) {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+
+	if txs.globalSize >= txs.maxGlobalQueueSize {
+		txs.globalQueueExceeded.Add(table, 1)
+		return nil, false, NewTabletError(vtrpcpb.ErrorCode_TRANSIENT_ERROR,
+			"hot row protection: too many queued transactions (%d >= %d)", txs.globalSize, txs.maxGlobalQueueSize)
+	}
+	q, ok := txs.queues[key]
+	if ok && q.size >= txs.maxQueueSize {
+		txs.queueExceeded.Add(table, 1)
+		return nil, false, NewTabletError(vtrpcpb.ErrorCode_TRANSIENT_ERROR,
+			"hot row protection: too many queued transactions (%d >= %d) for the same row (table: %v)", q.size, txs.maxQueueSize, table)
+	}
+	if !ok {
+		q = &txQueue{lock: make(chan struct{}, 1)}
+		txs.queues[key] = q
+	}
+	q.size++
+	txs.globalSize++
+	return q, q.size > 1, nil
+}
+
+// dequeue removes a transaction from the queue of key, and deletes
+// the queue when it's empty.
+func (txs *TxSerializer) dequeue(key string, q *txQueue) {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+
+	q.size--
+	txs.globalSize--
+	if q.size == 0 {
+		delete(txs.queues, key)
+	}
+}
+
+// Queued returns the number of transactions in all the queues.
+func (txs *TxSerializer) Queued() int64 {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	return int64(txs.globalSize)
+}
+
+// QueueCount returns the number of rows with queued transactions.
+func (txs *TxSerializer) QueueCount() int64 {
+	txs.mu.Lock()
+	defer txs.mu.Unlock()
+	return int64(len(txs.queues))
+}
+
+// txSerializerKey returns the key and the table of the rows updated
+// by a query. It returns false if the query is not a PlanDMLPK.
+func txSerializerKey(plan *ExecPlan, bindVars map[string]interface{}) (key, table string, ok bool, err error) {
+	if plan.PlanID != planbuilder.PlanDMLPK {
+		return "", "", false, nil
+	}
+	pkRows, err := buildValueList(plan.TableInfo, plan.PKValues, bindVars)
+	if err != nil {
+		return "", "", false, err
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString(plan.TableName)
+	for _, pkRow := range pkRows {
+		buf.WriteString(" (")
+		for i, v := range pkRow {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			v.EncodeSQL(buf)
+		}
+		buf.WriteString(")")
+	}
+	return buf.String(), plan.TableName, true, nil
+}
+
+// waitForSameRowTransactions waits for the transactions updating the
+// same rows as the query. conn is the connection of the transaction of
+// the query, or nil for an autocommit DML. It returns a nil done
+// function if hot row protection is disabled, or if the query doesn't
+// qualify.
+func (qe *QueryEngine) waitForSameRowTransactions(ctx context.Context, plan *ExecPlan, bindVars map[string]interface{}, conn *TxConnection) (done func(), err error) {
+	if qe.txSerializer == nil {
+		return nil, nil
+	}
+	key, table, ok, err := txSerializerKey(plan, bindVars)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if conn == nil {
+		return qe.txSerializer.Wait(ctx, key, table)
+	}
+	// Waiting for the rows this transaction already locked would
+	// deadlock with the transactions waiting for them in MySQL.
+	if conn.serializedRows[key] {
+		return nil, nil
+	}
+	done, err = qe.txSerializer.Wait(ctx, key, table)
+	if err != nil {
+		return nil, err
+	}
+	if conn.serializedRows == nil {
+		conn.serializedRows = make(map[string]bool)
+	}
+	conn.serializedRows[key] = true
+	return done, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tabletserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestTxSerializer(t *testing.T) {
+	txs := NewTxSerializer(2, 3, "", false)
+	ctx := context.Background()
+
+	done1, err := txs.Wait(ctx, "t1 (1)", "t1")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	// The second transaction waits for the first one.
+	done2 := make(chan func())
+	go func() {
+		done, err := txs.Wait(ctx, "t1 (1)", "t1")
+		if err != nil {
+			t.Errorf("Wait failed: %v", err)
+		}
+		done2 <- done
+	}()
+	for txs.Queued() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-done2:
+		t.Fatalf("second transaction did not wait for the first one")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// The queue for the row is full.
+	want := "too many queued transactions (2 >= 2) for the same row (table: t1)"
+	if _, err := txs.Wait(ctx, "t1 (1)", "t1"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Wait: %v, must contain %v", err, want)
+	}
+
+	// Other rows can still go, until the global queue is full.
+	done3, err := txs.Wait(ctx, "t1 (2)", "t1")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	want = "too many queued transactions (3 >= 3)"
+	if _, err := txs.Wait(ctx, "t1 (3)", "t1"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Wait: %v, must contain %v", err, want)
+	}
+
+	done1()
+	// Calling done twice is harmless.
+	done1()
+	(<-done2)()
+	done3()
+
+	if got := txs.Queued(); got != 0 {
+		t.Errorf("Queued: %v, want 0", got)
+	}
+	if got := txs.QueueCount(); got != 0 {
+		t.Errorf("QueueCount: %v, want 0", got)
+	}
+	if got := txs.waits.Counts()["t1"]; got != 1 {
+		t.Errorf("waits: %v, want 1", got)
+	}
+	if got := txs.queueExceeded.Counts()["t1"]; got != 1 {
+		t.Errorf("queueExceeded: %v, want 1", got)
+	}
+	if got := txs.globalQueueExceeded.Counts()["t1"]; got != 1 {
+		t.Errorf("globalQueueExceeded: %v, want 1", got)
+	}
+}
+
+func TestTxSerializerContextExpired(t *testing.T) {
+	txs := NewTxSerializer(2, 3, "", false)
+	done, err := txs.Wait(context.Background(), "t1 (1)", "t1")
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	want := "context expired while waiting for the transactions updating the same row"
+	if _, err := txs.Wait(ctx, "t1 (1)", "t1"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Wait: %v, must contain %v", err, want)
+	}
+	if got := txs.Queued(); got != 1 {
+		t.Errorf("Queued: %v, want 1", got)
+	}
+}
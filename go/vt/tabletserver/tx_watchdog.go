@@ -0,0 +1,120 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tabletserver
+
+import (
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/sync2"
+	"github.com/youtube/vitess/go/timer"
+	"github.com/youtube/vitess/go/vt/tabletserver/txresolver"
+	"golang.org/x/net/context"
+)
+
+// twoPCResolverConnectTimeout is the timeout to connect to the
+// participants of the transactions resolved by the TxWatchdog.
+const twoPCResolverConnectTimeout = 30 * time.Second
+
+// TxWatchdog looks for the distributed transactions which were
+// abandoned by their coordinator. It periodically scans the 2PC
+// metadata stored on the tablet, as the metadata manager of the
+// transactions, for the transactions older than abandonAge. If a resolver is set, the abandoned transactions are
+// concluded with txresolver.Resume. Otherwise, they are only exported
+// in the stats, and must be concluded with vtctl.
+//
+// The TxWatchdog only runs on a master, while the QueryEngine is open.
+type TxWatchdog struct {
+	qe         *QueryEngine
+	abandonAge time.Duration
+	timeout    time.Duration
+	resolver   txresolver.QueryService
+	ticks      *timer.Timer
+
+	// abandoned is the number of abandoned transactions found by the
+	// last scan.
+	abandoned sync2.AtomicInt64
+	// resolutions counts the outcomes of the resolutions, by
+	// "Resolved" and "Failed".
+	resolutions *stats.Counters
+}
+
+// NewTxWatchdog returns a TxWatchdog. An abandonAge of 0 disables the
+// watchdog. The scans run every abandonAge / 2. resolver may be nil,
+// and each resolution is bounded by timeout.
+func NewTxWatchdog(qe *QueryEngine, abandonAge, timeout time.Duration, resolver txresolver.QueryService, statsPrefix string, enablePublishStats bool) *TxWatchdog {
+	resolutionsName := ""
+	if enablePublishStats {
+		resolutionsName = statsPrefix + "TwoPCResolutions"
+	}
+	tw := &TxWatchdog{
+		qe:          qe,
+		abandonAge:  abandonAge,
+		timeout:     timeout,
+		resolver:    resolver,
+		ticks:       timer.NewTimer(abandonAge / 2),
+		resolutions: stats.NewCounters(resolutionsName),
+	}
+	if enablePublishStats {
+		stats.Publish(statsPrefix+"TwoPCAbandonedTransactions", stats.IntFunc(tw.abandoned.Get))
+	}
+	return tw
+}
+
+// Open starts the scans.
+func (tw *TxWatchdog) Open() {
+	if tw.abandonAge == 0 {
+		return
+	}
+	tw.ticks.Start(tw.check)
+}
+
+// Close stops the scans. The transactions being resolved may be left
+// in any state, and will be resolved again by the next master.
+func (tw *TxWatchdog) Close() {
+	tw.ticks.Stop()
+	tw.abandoned.Set(0)
+}
+
+// check scans the metadata for abandoned transactions, and resolves
+// them if a resolver is set.
+func (tw *TxWatchdog) check() {
+	ctx := context.Background()
+	conn, err := tw.qe.connPool.Get(ctx)
+	if err != nil {
+		log.Errorf("Error getting a connection to look for abandoned distributed transactions: %v", err)
+		return
+	}
+	txs, err := tw.qe.twoPC.ReadAbandoned(ctx, conn, time.Now().Add(-tw.abandonAge))
+	conn.Recycle()
+	if err != nil {
+		log.Errorf("Error reading the abandoned distributed transactions: %v", err)
+		return
+	}
+	tw.abandoned.Set(int64(len(txs)))
+	if len(txs) == 0 {
+		return
+	}
+	if tw.resolver == nil {
+		log.Warningf("Found %d abandoned distributed transactions, they must be concluded with vtctl ConcludeDistributedTransaction", len(txs))
+		return
+	}
+	for dtid, created := range txs {
+		log.Infof("Resolving the distributed transaction %v created at %v", dtid, created)
+		if err := tw.resolve(ctx, dtid); err != nil {
+			tw.resolutions.Add("Failed", 1)
+			log.Errorf("Error resolving the distributed transaction %v: %v", dtid, err)
+			continue
+		}
+		tw.resolutions.Add("Resolved", 1)
+	}
+}
+
+func (tw *TxWatchdog) resolve(ctx context.Context, dtid string) error {
+	ctx, cancel := context.WithTimeout(ctx, tw.timeout)
+	defer cancel()
+	return txresolver.Resume(ctx, tw.resolver, dtid)
+}
@@ -0,0 +1,94 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tabletserver
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"golang.org/x/net/context"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// fakeResolver resolves the transactions which are in the Commit state,
+// and have no participants.
+type fakeResolver struct {
+	failDtid string
+
+	mu       sync.Mutex
+	resolved []string
+}
+
+func (fr *fakeResolver) ReadTransaction(ctx context.Context, target *querypb.Target, dtid string) (*querypb.TransactionMetadata, error) {
+	return &querypb.TransactionMetadata{Dtid: dtid, State: querypb.TransactionState_COMMIT}, nil
+}
+
+func (fr *fakeResolver) SetRollback(ctx context.Context, target *querypb.Target, dtid string, transactionID int64) error {
+	return nil
+}
+
+func (fr *fakeResolver) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) error {
+	return nil
+}
+
+func (fr *fakeResolver) RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) error {
+	return nil
+}
+
+func (fr *fakeResolver) ResolveTransaction(ctx context.Context, target *querypb.Target, dtid string) error {
+	if dtid == fr.failDtid {
+		return errors.New("resolve failed")
+	}
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.resolved = append(fr.resolved, dtid)
+	return nil
+}
+
+func TestTxWatchdog(t *testing.T) {
+	_, tsv, db := newTestTxExecutor()
+	defer tsv.StopService()
+	db.AddQueryPattern("select dtid, time_created from `_vt`\\.transaction where time_created < .*", &sqltypes.Result{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeString([]byte("ks:0:0:1")),
+			sqltypes.MakeString([]byte("1")),
+		}, {
+			sqltypes.MakeString([]byte("ks:0:0:2")),
+			sqltypes.MakeString([]byte("2")),
+		}, {
+			sqltypes.MakeString([]byte("ks:0:0:3")),
+			sqltypes.MakeString([]byte("3")),
+		}},
+	})
+
+	// Without a resolver, the transactions are only counted.
+	tw := NewTxWatchdog(tsv.qe, time.Hour, time.Second, nil, "", false)
+	tw.check()
+	if got := tw.abandoned.Get(); got != 3 {
+		t.Errorf("abandoned: %v, want 3", got)
+	}
+
+	fr := &fakeResolver{failDtid: "ks:0:0:3"}
+	tw = NewTxWatchdog(tsv.qe, time.Hour, time.Second, fr, "", false)
+	tw.check()
+	sort.Strings(fr.resolved)
+	if want := []string{"ks:0:0:1", "ks:0:0:2"}; !reflect.DeepEqual(fr.resolved, want) {
+		t.Errorf("resolved: %v, want %v", fr.resolved, want)
+	}
+	if want := map[string]int64{"Resolved": 2, "Failed": 1}; !reflect.DeepEqual(tw.resolutions.Counts(), want) {
+		t.Errorf("resolutions: %v, want %v", tw.resolutions.Counts(), want)
+	}
+
+	tw.Close()
+	if got := tw.abandoned.Get(); got != 0 {
+		t.Errorf("abandoned after Close: %v, want 0", got)
+	}
+}
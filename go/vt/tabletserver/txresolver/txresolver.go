@@ -0,0 +1,205 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package txresolver concludes the distributed (2PC) transactions which
+// were left in doubt by their coordinator.
+//
+// The metadata of a distributed transaction is stored by its metadata
+// manager (MM), the master of the shard encoded in the dtid. Resume
+// reads it, and finishes the commit or the rollback of the transaction
+// on all the participants, the same way vtgate does when it recovers
+// from a failure in the middle of a commit.
+package txresolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/concurrency"
+	"github.com/youtube/vitess/go/vt/tabletserver/tabletconn"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"golang.org/x/net/context"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+// QueryService is the subset of the tablet query service used to
+// resolve distributed transactions. It is implemented by
+// tabletconn.TabletConn and by TopoQueryService.
+type QueryService interface {
+	ReadTransaction(ctx context.Context, target *querypb.Target, dtid string) (*querypb.TransactionMetadata, error)
+	SetRollback(ctx context.Context, target *querypb.Target, dtid string, transactionID int64) error
+	CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) error
+	RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) error
+	ResolveTransaction(ctx context.Context, target *querypb.Target, dtid string) error
+}
+
+// ParseDTID returns the target of the metadata manager of a dtid, and
+// the id of the transaction which created it on the metadata manager.
+// A dtid has the format <keyspace>:<shard>:0:<transaction id>.
+func ParseDTID(dtid string) (*querypb.Target, int64, error) {
+	splits := strings.Split(dtid, ":")
+	if len(splits) != 4 {
+		return nil, 0, fmt.Errorf("invalid parts in dtid: %s", dtid)
+	}
+	txid, err := strconv.ParseInt(splits[3], 10, 0)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid transaction id in dtid: %s", dtid)
+	}
+	return &querypb.Target{
+		Keyspace:   splits[0],
+		Shard:      splits[1],
+		TabletType: topodatapb.TabletType_MASTER,
+	}, txid, nil
+}
+
+// Resume concludes a distributed transaction. A transaction in the
+// Prepare state is rolled back, because its coordinator never decided
+// to commit it. A transaction in the Commit or Rollback state is
+// committed or rolled back on all the participants. The metadata is
+// then deleted from the metadata manager. Resuming a transaction which
+// is already resolved does nothing.
+func Resume(ctx context.Context, qs QueryService, dtid string) error {
+	mmTarget, txid, err := ParseDTID(dtid)
+	if err != nil {
+		return err
+	}
+	transaction, err := qs.ReadTransaction(ctx, mmTarget, dtid)
+	if err != nil {
+		return err
+	}
+	if transaction == nil || transaction.Dtid == "" {
+		// It was already resolved.
+		return nil
+	}
+
+	var action func(*querypb.Target) error
+	switch transaction.State {
+	case querypb.TransactionState_PREPARE:
+		if err := qs.SetRollback(ctx, mmTarget, dtid, txid); err != nil {
+			return err
+		}
+		fallthrough
+	case querypb.TransactionState_ROLLBACK:
+		action = func(t *querypb.Target) error {
+			return qs.RollbackPrepared(ctx, t, dtid, 0)
+		}
+	case querypb.TransactionState_COMMIT:
+		action = func(t *querypb.Target) error {
+			return qs.CommitPrepared(ctx, t, dtid)
+		}
+	default:
+		return fmt.Errorf("invalid state for dtid %v: %v", dtid, transaction.State)
+	}
+	if err := runTargets(transaction.Participants, action); err != nil {
+		return err
+	}
+	return qs.ResolveTransaction(ctx, mmTarget, dtid)
+}
+
+// runTargets runs action on all the targets in parallel.
+func runTargets(targets []*querypb.Target, action func(*querypb.Target) error) error {
+	allErrors := new(concurrency.AllErrorRecorder)
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func(t *querypb.Target) {
+			defer wg.Done()
+			allErrors.RecordError(action(t))
+		}(t)
+	}
+	wg.Wait()
+	return allErrors.Error()
+}
+
+// TopoQueryService is a QueryService which sends each call to the
+// current master of the target shard, found in the topology.
+type TopoQueryService struct {
+	ts             topo.Server
+	connectTimeout time.Duration
+}
+
+// NewTopoQueryService returns a TopoQueryService which finds the masters
+// in ts.
+func NewTopoQueryService(ts topo.Server, connectTimeout time.Duration) *TopoQueryService {
+	return &TopoQueryService{
+		ts:             ts,
+		connectTimeout: connectTimeout,
+	}
+}
+
+// dial returns a connection to the master of the target shard.
+func (tqs *TopoQueryService) dial(ctx context.Context, target *querypb.Target) (tabletconn.TabletConn, error) {
+	si, err := tqs.ts.GetShard(ctx, target.Keyspace, target.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasMaster() {
+		return nil, fmt.Errorf("no master in shard %v", topoproto.KeyspaceShardString(target.Keyspace, target.Shard))
+	}
+	ti, err := tqs.ts.GetTablet(ctx, si.MasterAlias)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := tabletconn.GetDialer()(ti.Tablet, tqs.connectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to master %v: %v", topoproto.TabletAliasString(si.MasterAlias), err)
+	}
+	return conn, nil
+}
+
+// ReadTransaction is part of the QueryService interface.
+func (tqs *TopoQueryService) ReadTransaction(ctx context.Context, target *querypb.Target, dtid string) (*querypb.TransactionMetadata, error) {
+	conn, err := tqs.dial(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+	return conn.ReadTransaction(ctx, target, dtid)
+}
+
+// SetRollback is part of the QueryService interface.
+func (tqs *TopoQueryService) SetRollback(ctx context.Context, target *querypb.Target, dtid string, transactionID int64) error {
+	conn, err := tqs.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	return conn.SetRollback(ctx, target, dtid, transactionID)
+}
+
+// CommitPrepared is part of the QueryService interface.
+func (tqs *TopoQueryService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) error {
+	conn, err := tqs.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	return conn.CommitPrepared(ctx, target, dtid)
+}
+
+// RollbackPrepared is part of the QueryService interface.
+func (tqs *TopoQueryService) RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) error {
+	conn, err := tqs.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	return conn.RollbackPrepared(ctx, target, dtid, originalID)
+}
+
+// ResolveTransaction is part of the QueryService interface.
+func (tqs *TopoQueryService) ResolveTransaction(ctx context.Context, target *querypb.Target, dtid string) error {
+	conn, err := tqs.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	return conn.ResolveTransaction(ctx, target, dtid)
+}
@@ -0,0 +1,161 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package txresolver
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+// fakeQueryService records the calls it receives, as
+// "<call> <keyspace>/<shard>".
+type fakeQueryService struct {
+	metadata *querypb.TransactionMetadata
+	err      error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeQueryService) record(call string, target *querypb.Target) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call+" "+target.Keyspace+"/"+target.Shard)
+}
+
+func (f *fakeQueryService) ReadTransaction(ctx context.Context, target *querypb.Target, dtid string) (*querypb.TransactionMetadata, error) {
+	f.record("ReadTransaction", target)
+	return f.metadata, nil
+}
+
+func (f *fakeQueryService) SetRollback(ctx context.Context, target *querypb.Target, dtid string, transactionID int64) error {
+	f.record("SetRollback", target)
+	return nil
+}
+
+func (f *fakeQueryService) CommitPrepared(ctx context.Context, target *querypb.Target, dtid string) error {
+	f.record("CommitPrepared", target)
+	return f.err
+}
+
+func (f *fakeQueryService) RollbackPrepared(ctx context.Context, target *querypb.Target, dtid string, originalID int64) error {
+	f.record("RollbackPrepared", target)
+	return f.err
+}
+
+func (f *fakeQueryService) ResolveTransaction(ctx context.Context, target *querypb.Target, dtid string) error {
+	f.record("ResolveTransaction", target)
+	return nil
+}
+
+func TestParseDTID(t *testing.T) {
+	target, txid, err := ParseDTID("ks:-80:0:1234")
+	if err != nil {
+		t.Fatalf("ParseDTID failed: %v", err)
+	}
+	want := &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_MASTER}
+	if !reflect.DeepEqual(target, want) || txid != 1234 {
+		t.Errorf("ParseDTID: (%v, %v), want (%v, 1234)", target, txid, want)
+	}
+
+	for _, dtid := range []string{"ks:-80:1234", "ks:-80:0:abc"} {
+		if _, _, err := ParseDTID(dtid); err == nil {
+			t.Errorf("ParseDTID(%v) succeeded", dtid)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	participants := []*querypb.Target{{Keyspace: "ks", Shard: "40-80"}, {Keyspace: "ks", Shard: "80-"}}
+	testcases := []struct {
+		state querypb.TransactionState
+		calls []string
+	}{{
+		state: querypb.TransactionState_PREPARE,
+		calls: []string{
+			"ReadTransaction ks/-40",
+			"SetRollback ks/-40",
+			"RollbackPrepared ks/40-80",
+			"RollbackPrepared ks/80-",
+			"ResolveTransaction ks/-40",
+		},
+	}, {
+		state: querypb.TransactionState_ROLLBACK,
+		calls: []string{
+			"ReadTransaction ks/-40",
+			"RollbackPrepared ks/40-80",
+			"RollbackPrepared ks/80-",
+			"ResolveTransaction ks/-40",
+		},
+	}, {
+		state: querypb.TransactionState_COMMIT,
+		calls: []string{
+			"ReadTransaction ks/-40",
+			"CommitPrepared ks/40-80",
+			"CommitPrepared ks/80-",
+			"ResolveTransaction ks/-40",
+		},
+	}}
+	for _, tcase := range testcases {
+		qs := &fakeQueryService{
+			metadata: &querypb.TransactionMetadata{
+				Dtid:         "ks:-40:0:1",
+				State:        tcase.state,
+				Participants: participants,
+			},
+		}
+		if err := Resume(context.Background(), qs, "ks:-40:0:1"); err != nil {
+			t.Errorf("Resume(%v) failed: %v", tcase.state, err)
+		}
+		// The participants are called in parallel, just before
+		// ResolveTransaction.
+		n := len(qs.calls)
+		sort.Strings(qs.calls[n-1-len(participants) : n-1])
+		if !reflect.DeepEqual(qs.calls, tcase.calls) {
+			t.Errorf("Resume(%v) calls: %v, want %v", tcase.state, qs.calls, tcase.calls)
+		}
+	}
+}
+
+func TestResumeResolved(t *testing.T) {
+	qs := &fakeQueryService{metadata: &querypb.TransactionMetadata{}}
+	if err := Resume(context.Background(), qs, "ks:-40:0:1"); err != nil {
+		t.Errorf("Resume failed: %v", err)
+	}
+	if want := []string{"ReadTransaction ks/-40"}; !reflect.DeepEqual(qs.calls, want) {
+		t.Errorf("Resume calls: %v, want %v", qs.calls, want)
+	}
+}
+
+func TestResumeParticipantError(t *testing.T) {
+	qs := &fakeQueryService{
+		metadata: &querypb.TransactionMetadata{
+			Dtid:         "ks:-40:0:1",
+			State:        querypb.TransactionState_COMMIT,
+			Participants: []*querypb.Target{{Keyspace: "ks", Shard: "40-"}},
+		},
+		err: errors.New("participant error"),
+	}
+	err := Resume(context.Background(), qs, "ks:-40:0:1")
+	if err == nil || !strings.Contains(err.Error(), "participant error") {
+		t.Errorf("Resume: %v, must contain participant error", err)
+	}
+	// The metadata must be kept, so that the transaction can be
+	// resumed again.
+	for _, call := range qs.calls {
+		if strings.HasPrefix(call, "ResolveTransaction") {
+			t.Errorf("transaction resolved after a participant error")
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package txthrottler throttles the transactions of a master tablet
+// when its replicas lag behind.
+//
+// The TxThrottler watches the replication lag of the REPLICA tablets
+// of the shard with a discovery.HealthCheck, and feeds it to a
+// throttler.Throttler with its MaxReplicationLagModule. The throttler
+// computes the maximum rate of transactions which keeps the lag below
+// the target, and Begin calls above this rate are delayed and then
+// rejected.
+//
+// The throttler is registered in the throttler.GlobalManager under the
+// name returned by ThrottlerName, so its configuration can be changed
+// at runtime through the throttler RPC service.
+//
+// The configuration can be overridden per keyspace: the keyspaces don't
+// have the same write load, nor the same tolerance to replication lag,
+// and a process like vtcombo serves the tablets of several keyspaces
+// with the same flags.
+package txthrottler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/vt/discovery"
+	"github.com/youtube/vitess/go/vt/throttler"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"golang.org/x/net/context"
+
+	throttlerdatapb "github.com/youtube/vitess/go/vt/proto/throttlerdata"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// Parameters of the health check and of the topology watchers.
+	healthCheckConnTimeout  = 30 * time.Second
+	healthCheckRetryDelay   = 2 * time.Millisecond
+	healthCheckTimeout      = 1 * time.Minute
+	topologyRefreshInterval = 1 * time.Minute
+	topologyReadConcurrency = 32
+)
+
+var (
+	// These vars are used by the tests to replace the health check
+	// and the topology watchers.
+	healthCheckFactory = func() discovery.HealthCheck {
+		return discovery.NewHealthCheck(healthCheckConnTimeout, healthCheckRetryDelay, healthCheckTimeout)
+	}
+	topologyWatcherFactory = func(ts topo.Server, hc discovery.HealthCheck, cell, keyspace, shard string) stopper {
+		return discovery.NewShardReplicationWatcher(ts, hc, cell, keyspace, shard, topologyRefreshInterval, topologyReadConcurrency)
+	}
+)
+
+// stopper is implemented by discovery.TopologyWatcher.
+type stopper interface {
+	Stop()
+}
+
+// ThrottlerName returns the name of the throttler of a shard in the
+// throttler.GlobalManager.
+func ThrottlerName(keyspace, shard string) string {
+	return "TransactionThrottler-" + topoproto.KeyspaceShardString(keyspace, shard)
+}
+
+// TxThrottler throttles the transactions of a tablet. It must be
+// opened with the keyspace and shard of the tablet before it
+// throttles anything. If it is disabled, Open and Close do nothing,
+// and Throttle always returns false.
+type TxThrottler struct {
+	enabled    bool
+	topoServer topo.Server
+	config     *throttlerdatapb.Configuration
+	// keyspaceConfigs overrides config for the shards of some
+	// keyspaces.
+	keyspaceConfigs  map[string]*throttlerdatapb.Configuration
+	healthCheckCells []string
+	maxWait          time.Duration
+
+	requests  *stats.Counters
+	throttled *stats.Counters
+
+	// mu protects state. state is nil when the TxThrottler is
+	// closed.
+	mu    sync.Mutex
+	state *txThrottlerState
+}
+
+// txThrottlerState holds the resources of an open TxThrottler.
+type txThrottlerState struct {
+	keyspace string
+	shard    string
+
+	// throttleMu serializes the calls to throttler.Throttle,
+	// as we use a single thread ID, and protects throttlerClosed.
+	throttleMu      sync.Mutex
+	throttler       *throttler.Throttler
+	throttlerClosed bool
+
+	// closeMu protects closed, so that StatsUpdate doesn't record
+	// a replication lag after the throttler is closed.
+	closeMu sync.Mutex
+	closed  bool
+
+	healthCheck      discovery.HealthCheck
+	topologyWatchers []stopper
+}
+
+// NewTxThrottler returns a TxThrottler. configText is a text
+// throttlerdata.Configuration, merged with the defaults of the
+// MaxReplicationLagModule. keyspaceConfigTexts maps a keyspace to a
+// text throttlerdata.Configuration merged on top of configText for its
+// shards. The replicas are looked up in the healthCheckCells cells of
+// topoServer. A throttled Begin waits up to maxWait for the rate to
+// allow it, before it is rejected.
+func NewTxThrottler(enabled bool, configText string, keyspaceConfigTexts map[string]string, healthCheckCells []string, maxWait time.Duration, topoServer topo.Server, statsPrefix string, enablePublishStats bool) (*TxThrottler, error) {
+	var requestsName, throttledName string
+	if enablePublishStats {
+		requestsName = statsPrefix + "TransactionThrottlerRequests"
+		throttledName = statsPrefix + "TransactionThrottlerThrottled"
+	}
+	txt := &TxThrottler{
+		enabled:          enabled,
+		topoServer:       topoServer,
+		healthCheckCells: healthCheckCells,
+		maxWait:          maxWait,
+		requests:         stats.NewCounters(requestsName),
+		throttled:        stats.NewCounters(throttledName),
+	}
+	if !enabled {
+		return txt, nil
+	}
+
+	if len(healthCheckCells) == 0 {
+		return nil, fmt.Errorf("the transaction throttler needs at least one cell to look for the replicas")
+	}
+	defaults := throttler.NewMaxReplicationLagModuleConfig(10).Configuration
+	config, err := mergeConfig(&defaults, configText)
+	if err != nil {
+		return nil, err
+	}
+	txt.config = config
+	txt.keyspaceConfigs = make(map[string]*throttlerdatapb.Configuration)
+	for keyspace, text := range keyspaceConfigTexts {
+		if txt.keyspaceConfigs[keyspace], err = mergeConfig(config, text); err != nil {
+			return nil, fmt.Errorf("keyspace %v: %v", keyspace, err)
+		}
+	}
+	return txt, nil
+}
+
+// mergeConfig returns a copy of base, with the fields set in the text
+// throttlerdata.Configuration overridden.
+func mergeConfig(base *throttlerdatapb.Configuration, configText string) (*throttlerdatapb.Configuration, error) {
+	config := proto.Clone(base).(*throttlerdatapb.Configuration)
+	override := &throttlerdatapb.Configuration{}
+	if err := proto.UnmarshalText(configText, override); err != nil {
+		return nil, fmt.Errorf("cannot parse the transaction throttler config %q: %v", configText, err)
+	}
+	proto.Merge(config, override)
+	if err := (throttler.MaxReplicationLagModuleConfig{Configuration: *config}).Verify(); err != nil {
+		return nil, fmt.Errorf("invalid transaction throttler config: %v", err)
+	}
+	return config, nil
+}
+
+// configFor returns the configuration of the shards of the keyspace.
+func (txt *TxThrottler) configFor(keyspace string) *throttlerdatapb.Configuration {
+	if config, ok := txt.keyspaceConfigs[keyspace]; ok {
+		return config
+	}
+	return txt.config
+}
+
+// Open starts the throttling for a shard. It creates the throttler and
+// starts watching the replicas. Opening an open TxThrottler for the
+// same shard does nothing.
+func (txt *TxThrottler) Open(keyspace, shard string) error {
+	if !txt.enabled {
+		return nil
+	}
+	txt.mu.Lock()
+	defer txt.mu.Unlock()
+	if txt.state != nil {
+		if txt.state.keyspace == keyspace && txt.state.shard == shard {
+			return nil
+		}
+		return fmt.Errorf("transaction throttler already opened for %v", topoproto.KeyspaceShardString(txt.state.keyspace, txt.state.shard))
+	}
+
+	config := txt.configFor(keyspace)
+	t, err := throttler.NewThrottler(ThrottlerName(keyspace, shard), "transactions", 1 /* threadCount */, throttler.MaxRateModuleDisabled, config.MaxReplicationLagSec)
+	if err != nil {
+		return err
+	}
+	if err := t.UpdateConfiguration(config, true /* copyZeroValues */); err != nil {
+		t.Close()
+		return err
+	}
+
+	state := &txThrottlerState{
+		keyspace:    keyspace,
+		shard:       shard,
+		throttler:   t,
+		healthCheck: healthCheckFactory(),
+	}
+	state.healthCheck.SetListener(state, false /* sendDownEvents */)
+	for _, cell := range txt.healthCheckCells {
+		state.topologyWatchers = append(state.topologyWatchers, topologyWatcherFactory(txt.topoServer, state.healthCheck, cell, keyspace, shard))
+	}
+	txt.state = state
+	log.Infof("transaction throttler opened for %v", topoproto.KeyspaceShardString(keyspace, shard))
+	return nil
+}
+
+// Close stops the throttling, and releases the resources of Open.
+func (txt *TxThrottler) Close() {
+	txt.mu.Lock()
+	state := txt.state
+	txt.state = nil
+	txt.mu.Unlock()
+	if state == nil {
+		return
+	}
+	state.close()
+	log.Infof("transaction throttler closed for %v", topoproto.KeyspaceShardString(state.keyspace, state.shard))
+}
+
+// Throttle returns true if a new transaction must be rejected. If the
+// throttler asks to back off, and the backoff ends before the
+// configured maxWait, Throttle waits and asks again. ctx can cut the
+// wait short.
+func (txt *TxThrottler) Throttle(ctx context.Context) bool {
+	txt.mu.Lock()
+	state := txt.state
+	txt.mu.Unlock()
+	if state == nil {
+		return false
+	}
+	txt.requests.Add(state.keyspace, 1)
+
+	deadline := time.Now().Add(txt.maxWait)
+	for {
+		backoff := state.throttle()
+		if backoff == throttler.NotThrottled {
+			return false
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			txt.throttled.Add(state.keyspace, 1)
+			return true
+		}
+	}
+	txt.throttled.Add(state.keyspace, 1)
+	return true
+}
+
+func (state *txThrottlerState) throttle() time.Duration {
+	state.throttleMu.Lock()
+	defer state.throttleMu.Unlock()
+	if state.throttlerClosed {
+		// Close was called while we were waiting.
+		return throttler.NotThrottled
+	}
+	return state.throttler.Throttle(0 /* threadID */)
+}
+
+func (state *txThrottlerState) close() {
+	for _, tw := range state.topologyWatchers {
+		tw.Stop()
+	}
+	state.healthCheck.Close()
+
+	state.closeMu.Lock()
+	state.closed = true
+	state.closeMu.Unlock()
+	state.throttleMu.Lock()
+	state.throttler.Close()
+	state.throttlerClosed = true
+	state.throttleMu.Unlock()
+}
+
+// StatsUpdate records the replication lag of the replicas. It is part
+// of the discovery.HealthCheckStatsListener interface.
+func (state *txThrottlerState) StatsUpdate(ts *discovery.TabletStats) {
+	// The rdonly tablets are often used for batch jobs, and are
+	// allowed to lag behind.
+	if ts.Target.TabletType != topodatapb.TabletType_REPLICA {
+		return
+	}
+	state.closeMu.Lock()
+	defer state.closeMu.Unlock()
+	if state.closed {
+		return
+	}
+	state.throttler.RecordReplicationLag(time.Now(), ts)
+}
@@ -0,0 +1,218 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package txthrottler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/vt/discovery"
+	"github.com/youtube/vitess/go/vt/throttler"
+	"github.com/youtube/vitess/go/vt/topo"
+	"golang.org/x/net/context"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+type fakeTopologyWatcher struct {
+	cell    string
+	stopped bool
+}
+
+func (tw *fakeTopologyWatcher) Stop() {
+	tw.stopped = true
+}
+
+// useFakes replaces the health check and the topology watchers, and
+// returns the created watchers.
+func useFakes() *[]*fakeTopologyWatcher {
+	var watchers []*fakeTopologyWatcher
+	healthCheckFactory = func() discovery.HealthCheck {
+		return discovery.NewFakeHealthCheck()
+	}
+	topologyWatcherFactory = func(ts topo.Server, hc discovery.HealthCheck, cell, keyspace, shard string) stopper {
+		tw := &fakeTopologyWatcher{cell: cell}
+		watchers = append(watchers, tw)
+		return tw
+	}
+	return &watchers
+}
+
+func restoreFactories(healthCheck func() discovery.HealthCheck, topologyWatcher func(topo.Server, discovery.HealthCheck, string, string, string) stopper) {
+	healthCheckFactory = healthCheck
+	topologyWatcherFactory = topologyWatcher
+}
+
+func TestDisabled(t *testing.T) {
+	txt, err := NewTxThrottler(false, "", nil, nil, 0, topo.Server{}, "", false)
+	if err != nil {
+		t.Fatalf("NewTxThrottler failed: %v", err)
+	}
+	if err := txt.Open("ks", "0"); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if txt.Throttle(context.Background()) {
+		t.Errorf("a disabled throttler throttled a transaction")
+	}
+	txt.Close()
+}
+
+func TestNewTxThrottlerErrors(t *testing.T) {
+	testcases := []struct {
+		config          string
+		keyspaceConfigs map[string]string
+		cells           []string
+		err             string
+	}{{
+		config: "",
+		err:    "needs at least one cell",
+	}, {
+		config: "not a config",
+		cells:  []string{"cell1"},
+		err:    "cannot parse the transaction throttler config",
+	}, {
+		config: "target_replication_lag_sec: 20 max_replication_lag_sec: 10",
+		cells:  []string{"cell1"},
+		err:    "invalid transaction throttler config",
+	}, {
+		// The keyspace config is merged on top of the global one.
+		config:          "max_replication_lag_sec: 30",
+		keyspaceConfigs: map[string]string{"ks": "target_replication_lag_sec: 40"},
+		cells:           []string{"cell1"},
+		err:             "keyspace ks: invalid transaction throttler config",
+	}}
+	for _, tcase := range testcases {
+		_, err := NewTxThrottler(true, tcase.config, tcase.keyspaceConfigs, tcase.cells, 0, topo.Server{}, "", false)
+		if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("NewTxThrottler(%q, %v, %v): %v, must contain %v", tcase.config, tcase.keyspaceConfigs, tcase.cells, err, tcase.err)
+		}
+	}
+}
+
+func TestTxThrottler(t *testing.T) {
+	defer restoreFactories(healthCheckFactory, topologyWatcherFactory)
+	watchers := useFakes()
+
+	txt, err := NewTxThrottler(true, "max_replication_lag_sec: 20", nil, []string{"cell1", "cell2"}, 50*time.Millisecond, topo.Server{}, "", false)
+	if err != nil {
+		t.Fatalf("NewTxThrottler failed: %v", err)
+	}
+	if got, want := txt.config.MaxReplicationLagSec, int64(20); got != want {
+		t.Errorf("MaxReplicationLagSec = %v, want %v", got, want)
+	}
+	if got, want := txt.config.TargetReplicationLagSec, int64(2); got != want {
+		t.Errorf("TargetReplicationLagSec = %v, want the default %v", got, want)
+	}
+
+	ctx := context.Background()
+	if txt.Throttle(ctx) {
+		t.Errorf("a closed throttler throttled a transaction")
+	}
+	if err := txt.Open("ks", "0"); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := txt.Open("ks", "0"); err != nil {
+		t.Errorf("Open for the same shard failed: %v", err)
+	}
+	if err := txt.Open("ks", "1"); err == nil {
+		t.Errorf("Open for another shard succeeded")
+	}
+	var cells []string
+	for _, tw := range *watchers {
+		cells = append(cells, tw.cell)
+	}
+	if want := []string{"cell1", "cell2"}; !reflect.DeepEqual(cells, want) {
+		t.Errorf("watched cells = %v, want %v", cells, want)
+	}
+	if got, want := throttler.GlobalManager.Throttlers(), []string{"TransactionThrottler-ks/0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("registered throttlers = %v, want %v", got, want)
+	}
+
+	// Only the replica lag is recorded. This must not panic.
+	for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY} {
+		txt.state.StatsUpdate(&discovery.TabletStats{
+			Target: &querypb.Target{Keyspace: "ks", Shard: "0", TabletType: tabletType},
+			Stats:  &querypb.RealtimeStats{SecondsBehindMaster: 1},
+		})
+	}
+
+	if txt.Throttle(ctx) {
+		t.Errorf("transaction throttled below the initial rate")
+	}
+
+	// Setting the rate to 0 through the manager stops all
+	// transactions. The rate is updated asynchronously.
+	throttler.GlobalManager.SetMaxRate(throttler.ZeroRateNoProgess)
+	deadline := time.Now().Add(10 * time.Second)
+	for !txt.Throttle(ctx) {
+		if time.Now().After(deadline) {
+			t.Fatalf("transactions not throttled after setting the rate to 0")
+		}
+	}
+	if got := txt.throttled.Counts()["ks"]; got != 1 {
+		t.Errorf("throttled count = %v, want 1", got)
+	}
+
+	txt.Close()
+	for _, tw := range *watchers {
+		if !tw.stopped {
+			t.Errorf("topology watcher for %v not stopped", tw.cell)
+		}
+	}
+	if got := throttler.GlobalManager.Throttlers(); len(got) != 0 {
+		t.Errorf("throttlers still registered after Close: %v", got)
+	}
+	if txt.Throttle(ctx) {
+		t.Errorf("a closed throttler throttled a transaction")
+	}
+}
+
+func TestTxThrottlerKeyspaceConfigs(t *testing.T) {
+	defer restoreFactories(healthCheckFactory, topologyWatcherFactory)
+	useFakes()
+
+	// Like in vtcombo, the tablets of both keyspaces are created
+	// with the same flags.
+	config := "target_replication_lag_sec: 2 max_replication_lag_sec: 10"
+	keyspaceConfigs := map[string]string{"ks2": "target_replication_lag_sec: 5 max_replication_lag_sec: 60"}
+	testcases := []struct {
+		keyspace          string
+		targetLag, maxLag int64
+	}{{
+		keyspace:  "ks1",
+		targetLag: 2,
+		maxLag:    10,
+	}, {
+		keyspace:  "ks2",
+		targetLag: 5,
+		maxLag:    60,
+	}}
+	var txts []*TxThrottler
+	for _, tcase := range testcases {
+		txt, err := NewTxThrottler(true, config, keyspaceConfigs, []string{"cell1"}, 0, topo.Server{}, "", false)
+		if err != nil {
+			t.Fatalf("NewTxThrottler failed: %v", err)
+		}
+		if err := txt.Open(tcase.keyspace, "0"); err != nil {
+			t.Fatalf("Open(%v) failed: %v", tcase.keyspace, err)
+		}
+		defer txt.Close()
+		txts = append(txts, txt)
+	}
+
+	for _, tcase := range testcases {
+		configs, err := throttler.GlobalManager.GetConfiguration(ThrottlerName(tcase.keyspace, "0"))
+		if err != nil {
+			t.Fatalf("GetConfiguration(%v) failed: %v", tcase.keyspace, err)
+		}
+		got := configs[ThrottlerName(tcase.keyspace, "0")]
+		if got.TargetReplicationLagSec != tcase.targetLag || got.MaxReplicationLagSec != tcase.maxLag {
+			t.Errorf("%v: target and max replication lag = %v, %v, want %v, %v", tcase.keyspace, got.TargetReplicationLagSec, got.MaxReplicationLagSec, tcase.targetLag, tcase.maxLag)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package topo
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/vt/tableacl"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+// This file contains the utility methods to manage the table ACL of
+// the keyspaces. They are stored in the global cell.
+
+func tableACLFileName(keyspace string) string {
+	return path.Join("/keyspaces", keyspace, "TableACL")
+}
+
+// GetTableACL returns the table ACL of a keyspace.
+// Returns ErrNoNode if the keyspace doesn't have a table ACL.
+func (ts Server) GetTableACL(ctx context.Context, keyspace string) (*tableaclpb.Config, error) {
+	contents, _, err := ts.Get(ctx, "global", tableACLFileName(keyspace))
+	if err != nil {
+		return nil, err
+	}
+	config := &tableaclpb.Config{}
+	if err := proto.Unmarshal(contents, config); err != nil {
+		return nil, fmt.Errorf("error unpacking TableACL object: %v", err)
+	}
+	return config, nil
+}
+
+// SaveTableACL first validates the table ACL, then saves it as the
+// table ACL of the keyspace.
+func (ts Server) SaveTableACL(ctx context.Context, keyspace string, config *tableaclpb.Config) error {
+	if err := tableacl.ValidateProto(config); err != nil {
+		return err
+	}
+	contents, err := proto.Marshal(config)
+	if err != nil {
+		return err
+	}
+	// The keyspace directory may not exist yet, and an Update
+	// doesn't create it in all the implementations.
+	filePath := tableACLFileName(keyspace)
+	_, err = ts.Update(ctx, "global", filePath, contents, nil)
+	if err == ErrNoNode {
+		_, err = ts.Create(ctx, "global", filePath, contents)
+	}
+	return err
+}
+
+// DeleteTableACL deletes the table ACL of a keyspace.
+func (ts Server) DeleteTableACL(ctx context.Context, keyspace string) error {
+	return ts.Delete(ctx, "global", tableACLFileName(keyspace), nil)
+}
+
+// WatchTableACLData is returned / streamed by WatchTableACL.
+// The WatchTableACL API guarantees exactly one of Value or Err will be set.
+type WatchTableACLData struct {
+	Value *tableaclpb.Config
+	Err   error
+}
+
+// WatchTableACL will set a watch on the table ACL of a keyspace.
+// It has the same contract as Backend.Watch, but it also unpacks the
+// contents into a tableaclpb.Config object.
+func (ts Server) WatchTableACL(ctx context.Context, keyspace string) (*WatchTableACLData, <-chan *WatchTableACLData, CancelFunc) {
+	filePath := tableACLFileName(keyspace)
+
+	current, wdChannel, cancel := ts.Watch(ctx, "global", filePath)
+	if current.Err != nil {
+		return &WatchTableACLData{Err: current.Err}, nil, nil
+	}
+	value := &tableaclpb.Config{}
+	if err := proto.Unmarshal(current.Contents, value); err != nil {
+		// Cancel the watch, drain channel.
+		cancel()
+		for range wdChannel {
+		}
+		return &WatchTableACLData{Err: fmt.Errorf("error unpacking initial TableACL object: %v", err)}, nil, nil
+	}
+
+	changes := make(chan *WatchTableACLData, 10)
+
+	// The background routine reads any event from the watch channel,
+	// translates it, and sends it to the caller.
+	// If cancel() is called, the underlying Watch() code will
+	// send an ErrInterrupted and then close the channel. We'll
+	// just propagate that back to our caller.
+	go func() {
+		defer close(changes)
+
+		for wd := range wdChannel {
+			if wd.Err != nil {
+				// Last error value, we're done.
+				// wdChannel will be closed right after
+				// this, no need to do anything.
+				changes <- &WatchTableACLData{Err: wd.Err}
+				return
+			}
+
+			value := &tableaclpb.Config{}
+			if err := proto.Unmarshal(wd.Contents, value); err != nil {
+				cancel()
+				for range wdChannel {
+				}
+				changes <- &WatchTableACLData{Err: fmt.Errorf("error unpacking TableACL object: %v", err)}
+				return
+			}
+
+			changes <- &WatchTableACLData{Value: value}
+		}
+	}()
+
+	return &WatchTableACLData{Value: value}, changes, cancel
+}
@@ -28,4 +28,9 @@ func TestMemoryTopo(t *testing.T) {
 	ts = factory()
 	checkFile(t, ts)
 	ts.Close()
+
+	t.Log("=== checkTableACL")
+	ts = factory()
+	checkTableACL(t, ts)
+	ts.Close()
 }
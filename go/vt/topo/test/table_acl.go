@@ -0,0 +1,98 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/youtube/vitess/go/vt/topo"
+	"golang.org/x/net/context"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+// checkTableACL runs the tests on the table ACL part of the API.
+func checkTableACL(t *testing.T, impl topo.Impl) {
+	ctx := context.Background()
+	ts := topo.Server{Impl: impl}
+
+	if _, err := ts.GetTableACL(ctx, "test_keyspace"); err != topo.ErrNoNode {
+		t.Errorf("GetTableACL(missing): %v, want ErrNoNode", err)
+	}
+
+	// An invalid table ACL is rejected.
+	invalid := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group1",
+			TableNamesOrPrefixes: []string{"t%", "t1"},
+		}},
+	}
+	if err := ts.SaveTableACL(ctx, "test_keyspace", invalid); err == nil {
+		t.Errorf("SaveTableACL(invalid) succeeded")
+	}
+
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "group1",
+			TableNamesOrPrefixes: []string{"t1"},
+			Readers:              []string{"user1"},
+		}},
+	}
+	if err := ts.SaveTableACL(ctx, "test_keyspace", config); err != nil {
+		t.Fatalf("SaveTableACL: %v", err)
+	}
+	got, err := ts.GetTableACL(ctx, "test_keyspace")
+	if err != nil || !proto.Equal(got, config) {
+		t.Errorf("GetTableACL: (%v, %v), want %v", got, err, config)
+	}
+
+	current, changes, cancel := ts.WatchTableACL(ctx, "test_keyspace")
+	if current.Err != nil || !proto.Equal(current.Value, config) {
+		t.Fatalf("WatchTableACL: (%v, %v), want %v", current.Value, current.Err, config)
+	}
+
+	config.TableGroups[0].Writers = []string{"user2"}
+	if err := ts.SaveTableACL(ctx, "test_keyspace", config); err != nil {
+		t.Fatalf("SaveTableACL: %v", err)
+	}
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case wd, ok := <-changes:
+			if !ok {
+				t.Fatalf("watch channel closed unexpectedly")
+			}
+			if wd.Err != nil {
+				t.Fatalf("watch error: %v", wd.Err)
+			}
+			if !proto.Equal(wd.Value, config) {
+				// The watch may return the previous value again.
+				continue
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for the new table ACL")
+		}
+		break
+	}
+
+	cancel()
+	for wd := range changes {
+		if wd.Err == nil {
+			continue
+		}
+		if wd.Err != topo.ErrInterrupted {
+			t.Errorf("final watch error: %v, want ErrInterrupted", wd.Err)
+		}
+	}
+
+	if err := ts.DeleteTableACL(ctx, "test_keyspace"); err != nil {
+		t.Errorf("DeleteTableACL: %v", err)
+	}
+	if _, err := ts.GetTableACL(ctx, "test_keyspace"); err != topo.ErrNoNode {
+		t.Errorf("GetTableACL(deleted): %v, want ErrNoNode", err)
+	}
+}
@@ -83,6 +83,11 @@ func TopoServerTestSuite(t *testing.T, factory func() topo.Impl) {
 	checkVSchema(t, ts)
 	ts.Close()
 
+	t.Log("=== checkTableACL")
+	ts = factory()
+	checkTableACL(t, ts)
+	ts.Close()
+
 	t.Log("=== checkElection")
 	ts = factory()
 	checkElection(t, ts)
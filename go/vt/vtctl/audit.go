@@ -0,0 +1,50 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vtctl
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/youtube/vitess/go/vt/audit"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+)
+
+// auditedCommands are the commands recorded in the audit log, when it
+// is enabled: the schema changes, the reparents, the table ACL
+// changes, and the commands running arbitrary queries or hooks on the
+// tablets.
+var auditedCommands = map[string]bool{
+	"ApplySchema":                    true,
+	"CopySchemaShard":                true,
+	"ApplyVSchema":                   true,
+	"ApplyTableACL":                  true,
+	"ExecuteFetchAsDba":              true,
+	"ExecuteHook":                    true,
+	"InitShardMaster":                true,
+	"PlannedReparentShard":           true,
+	"EmergencyReparentShard":         true,
+	"ReparentTablet":                 true,
+	"TabletExternallyReparented":     true,
+	"ConcludeDistributedTransaction": true,
+}
+
+// runCommand runs cmd with its flags and arguments, recording it in
+// the audit log if it is one of the auditedCommands. The target of the
+// event is the positional arguments of the command, e.g. its keyspace
+// or its shard, and its statement is the whole command line.
+func runCommand(ctx context.Context, wr *wrangler.Wrangler, cmd command, subFlags *flag.FlagSet, args []string) (err error) {
+	if auditedCommands[cmd.name] {
+		ev := audit.Start(ctx, cmd.name, "", strings.Join(args, " "))
+		defer func() {
+			if ev != nil && subFlags.Parsed() {
+				ev.Target = strings.Join(subFlags.Args(), " ")
+			}
+			ev.Finish(err)
+		}()
+	}
+	return cmd.method(ctx, wr, subFlags, args[1:])
+}
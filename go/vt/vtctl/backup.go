@@ -8,8 +8,10 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/mysqlctl"
 	"github.com/youtube/vitess/go/vt/mysqlctl/backupstorage"
 	"github.com/youtube/vitess/go/vt/topo/topoproto"
 	"github.com/youtube/vitess/go/vt/wrangler"
@@ -27,12 +29,22 @@ func init() {
 		commandRemoveBackup,
 		"<keyspace/shard> <backup name>",
 		"Removes a backup for the BackupStorage."})
+	addCommand("Shards", command{
+		"PruneBackups",
+		commandPruneBackups,
+		"[-keep_count=N] [-keep_days=N] [-dry_run] <keyspace/shard>",
+		"Removes the old backups of a shard. A full backup is kept if it is one of the keep_count most recent ones, or if it was taken less than keep_days ago. All the backups older than the oldest full backup that is kept are removed."})
+	addCommand("Shards", command{
+		"ValidateBackup",
+		commandValidateBackup,
+		"[-concurrency=4] <keyspace/shard> <backup name>",
+		"Reads the MANIFEST of a backup, and checks the hash of all its files."})
 
 	addCommand("Tablets", command{
 		"RestoreFromBackup",
 		commandRestoreFromBackup,
-		"<tablet alias>",
-		"Stops mysqld and restores the data from the latest backup."})
+		"[-restore_to_pos=<position>|-restore_to_timestamp=<RFC3339 time>] <tablet alias>",
+		"Stops mysqld and restores the data from the latest backup. With -restore_to_pos or -restore_to_timestamp, restores the latest backup taken before that point, and replays the binlogs from the incremental backups up to it. The tablet is then left in DRAINED type, without replication."})
 }
 
 func commandListBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -87,13 +99,108 @@ func commandRemoveBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 	return bs.RemoveBackup(bucket, name)
 }
 
+func commandPruneBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	keepCount := subFlags.Int("keep_count", 0, "Keeps at least this many full backups")
+	keepDays := subFlags.Int("keep_days", 0, "Keeps the full backups taken less than this many days ago")
+	dryRun := subFlags.Bool("dry_run", false, "Only lists the backups that would be removed")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("action PruneBackups requires <keyspace/shard>")
+	}
+	if *keepCount <= 0 && *keepDays <= 0 {
+		return fmt.Errorf("action PruneBackups requires a positive -keep_count or -keep_days")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	bucket := fmt.Sprintf("%v/%v", keyspace, shard)
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+	bhs, err := bs.ListBackups(bucket)
+	if err != nil {
+		return err
+	}
+	keepDuration := time.Duration(*keepDays) * 24 * time.Hour
+	for _, bh := range mysqlctl.BackupsToPrune(bhs, *keepCount, keepDuration, time.Now()) {
+		if *dryRun {
+			wr.Logger().Printf("Would remove backup %v\n", bh.Name())
+			continue
+		}
+		wr.Logger().Infof("Removing backup %v", bh.Name())
+		if err := bs.RemoveBackup(bucket, bh.Name()); err != nil {
+			return fmt.Errorf("cannot remove backup %v: %v", bh.Name(), err)
+		}
+	}
+	return nil
+}
+
+func commandValidateBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	concurrency := subFlags.Int("concurrency", 4, "Specifies the number of files to check simultaneously")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("action ValidateBackup requires <keyspace/shard> <backup name>")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	bucket := fmt.Sprintf("%v/%v", keyspace, shard)
+	name := subFlags.Arg(1)
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return err
+	}
+	defer bs.Close()
+	bhs, err := bs.ListBackups(bucket)
+	if err != nil {
+		return err
+	}
+	for _, bh := range bhs {
+		if bh.Name() != name {
+			continue
+		}
+		bm, err := mysqlctl.ValidateBackup(bh, *concurrency)
+		if err != nil {
+			return fmt.Errorf("backup %v is not valid: %v", name, err)
+		}
+		wr.Logger().Printf("Backup %v is valid: %v files, position %v\n", name, len(bm.FileEntries), bm.Position)
+		return nil
+	}
+	return fmt.Errorf("no backup %v in %v", name, bucket)
+}
+
 func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	restoreToPos := subFlags.String("restore_to_pos", "", "Restores up to this replication position, included")
+	restoreToTimestamp := subFlags.String("restore_to_timestamp", "", "Restores up to this time, in RFC3339 format, excluded")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("The RestoreFromBackup command requires the <tablet alias> argument.")
 	}
+	if *restoreToPos != "" && *restoreToTimestamp != "" {
+		return fmt.Errorf("-restore_to_pos and -restore_to_timestamp cannot be used together")
+	}
+	var restoreToTime time.Time
+	if *restoreToTimestamp != "" {
+		var err error
+		restoreToTime, err = time.Parse(time.RFC3339, *restoreToTimestamp)
+		if err != nil {
+			return fmt.Errorf("cannot parse -restore_to_timestamp %v: %v", *restoreToTimestamp, err)
+		}
+	}
 
 	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
 	if err != nil {
@@ -103,7 +210,7 @@ func commandRestoreFromBackup(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if err != nil {
 		return err
 	}
-	stream, err := wr.TabletManagerClient().RestoreFromBackup(ctx, tabletInfo.Tablet)
+	stream, err := wr.TabletManagerClient().RestoreFromBackup(ctx, tabletInfo.Tablet, *restoreToPos, restoreToTime)
 	if err != nil {
 		return err
 	}
@@ -6,9 +6,11 @@
 package grpcvtctlclient
 
 import (
+	"flag"
 	"time"
 
 	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/servenv/grpcutils"
 	"github.com/youtube/vitess/go/vt/vtctl/vtctlclient"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -18,6 +20,15 @@ import (
 	vtctlservicepb "github.com/youtube/vitess/go/vt/proto/vtctlservice"
 )
 
+var (
+	cert = flag.String("vtctld_grpc_cert", "", "the cert to use to connect")
+	key  = flag.String("vtctld_grpc_key", "", "the key to use to connect")
+	ca   = flag.String("vtctld_grpc_ca", "", "the server ca to use to validate servers when connecting")
+	name = flag.String("vtctld_grpc_server_name", "", "the server name to use to validate server certificate")
+
+	creds = flag.String("vtctld_grpc_auth_static_client_creds", "", "JSON file of the username and password to send to a vtctld using the static gRPC authentication plugin")
+)
+
 type gRPCVtctlClient struct {
 	cc *grpc.ClientConn
 	c  vtctlservicepb.VtctlClient
@@ -25,7 +36,16 @@ type gRPCVtctlClient struct {
 
 func gRPCVtctlClientFactory(addr string, dialTimeout time.Duration) (vtctlclient.VtctlClient, error) {
 	// create the RPC client
-	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(dialTimeout))
+	opt, err := grpcutils.ClientSecureDialOption(*cert, *key, *ca, *name)
+	if err != nil {
+		return nil, err
+	}
+	authOpts, err := grpcutils.ClientAuthDialOptions(*creds)
+	if err != nil {
+		return nil, err
+	}
+	opts := append([]grpc.DialOption{opt, grpc.WithBlock(), grpc.WithTimeout(dialTimeout)}, authOpts...)
+	cc, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, err
 	}
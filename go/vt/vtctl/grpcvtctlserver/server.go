@@ -11,6 +11,7 @@ package grpcvtctlserver
 import (
 	"google.golang.org/grpc"
 
+	"github.com/youtube/vitess/go/vt/callinfo"
 	"github.com/youtube/vitess/go/vt/logutil"
 	"github.com/youtube/vitess/go/vt/servenv"
 	"github.com/youtube/vitess/go/vt/tabletmanager/tmclient"
@@ -54,7 +55,7 @@ func (s *VtctlServer) ExecuteVtctlCommand(args *vtctldatapb.ExecuteVtctlCommandR
 	wr := wrangler.New(logger, s.ts, tmc)
 
 	// execute the command
-	return vtctl.RunCommand(stream.Context(), wr, args.Args)
+	return vtctl.RunCommand(callinfo.GRPCCallInfo(stream.Context()), wr, args.Args)
 }
 
 // StartServer registers the VtctlServer for RPCs
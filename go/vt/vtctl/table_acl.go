@@ -0,0 +1,106 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vtctl
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/youtube/vitess/go/vt/tableacl"
+	"github.com/youtube/vitess/go/vt/topo"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+
+	tableaclpb "github.com/youtube/vitess/go/vt/proto/tableacl"
+)
+
+// This file contains the commands to manage the table ACLs stored in
+// the topology, which are watched by the vttablets started with
+// -table-acl-from-topo.
+
+func init() {
+	addCommand("Schema, Version, Permissions", command{
+		"GetTableACL",
+		commandGetTableACL,
+		"<keyspace>",
+		"Displays the table ACL stored in the topology for the keyspace."})
+	addCommand("Schema, Version, Permissions", command{
+		"ApplyTableACL",
+		commandApplyTableACL,
+		"{-table_acl=<table acl> || -table_acl_file=<table acl file>} [-dry-run] <keyspace>",
+		"Validates the table ACL, displays the changes from the current one, and stores it in the topology for the keyspace. The vttablets watching it reload it. With -dry-run, only the changes are displayed."})
+}
+
+func commandGetTableACL(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace> argument is required for the GetTableACL command")
+	}
+	config, err := wr.TopoServer().GetTableACL(ctx, subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), config)
+}
+
+func commandApplyTableACL(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	tableACL := subFlags.String("table_acl", "", "Identifies the table ACL, in JSON")
+	tableACLFile := subFlags.String("table_acl_file", "", "Identifies the table ACL file, in JSON")
+	dryRun := subFlags.Bool("dry-run", false, "If set, only displays the changes, without storing the table ACL")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace> argument is required for the ApplyTableACL command")
+	}
+	if (*tableACL == "") == (*tableACLFile == "") {
+		return fmt.Errorf("either the table_acl or table_acl_file flag must be specified when calling the ApplyTableACL command")
+	}
+	data := []byte(*tableACL)
+	if *tableACLFile != "" {
+		var err error
+		data, err = ioutil.ReadFile(*tableACLFile)
+		if err != nil {
+			return err
+		}
+	}
+	config := &tableaclpb.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("cannot parse the table ACL: %v", err)
+	}
+	if err := tableacl.ValidateProto(config); err != nil {
+		return err
+	}
+
+	keyspace := subFlags.Arg(0)
+	current, err := wr.TopoServer().GetTableACL(ctx, keyspace)
+	switch err {
+	case nil:
+	case topo.ErrNoNode:
+		current = &tableaclpb.Config{}
+	default:
+		return err
+	}
+	changes := tableacl.DiffConfigs(current, config)
+	if len(changes) == 0 {
+		wr.Logger().Printf("No changes to the table ACL of keyspace %v\n", keyspace)
+	}
+	for _, change := range changes {
+		wr.Logger().Printf("%v\n", change)
+	}
+	if *dryRun {
+		return nil
+	}
+
+	if err := wr.TopoServer().SaveTableACL(ctx, keyspace, config); err != nil {
+		return err
+	}
+	wr.Logger().Printf("Uploaded table ACL object:\n")
+	return printJSON(wr.Logger(), config)
+}
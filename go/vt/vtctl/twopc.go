@@ -0,0 +1,111 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vtctl
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/tabletserver/txresolver"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"golang.org/x/net/context"
+)
+
+// This file contains the commands to inspect and conclude the
+// distributed (2PC) transactions left in doubt by their coordinator.
+
+func init() {
+	addCommand("Shards", command{
+		"ListDistributedTransactions",
+		commandListDistributedTransactions,
+		"[-sidecar_db_name <name>] [-older_than <duration>] [-json] <keyspace/shard>",
+		"Lists the distributed transactions whose metadata is stored on the master of the shard. They are either being committed, or were abandoned by their coordinator."})
+	addCommand("Shards", command{
+		"ReadDistributedTransaction",
+		commandReadDistributedTransaction,
+		"[-connect_timeout <connect timeout>] <dtid>",
+		"Displays the state and the participants of a distributed transaction."})
+	addCommand("Shards", command{
+		"ConcludeDistributedTransaction",
+		commandConcludeDistributedTransaction,
+		"[-connect_timeout <connect timeout>] <dtid>",
+		"Concludes a distributed transaction. If the coordinator had not decided to commit it, it is rolled back on all the participants. Otherwise, the commit or the rollback is finished on all the participants. Then its metadata is deleted."})
+}
+
+func commandListDistributedTransactions(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	sidecarDBName := subFlags.String("sidecar_db_name", "_vt", "The name of the database where vttablet stores the 2PC metadata")
+	olderThan := subFlags.Duration("older_than", 0, "Only lists the transactions created more than this duration ago")
+	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ListDistributedTransactions command")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	si, err := wr.TopoServer().GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	if !si.HasMaster() {
+		return fmt.Errorf("no master in shard %v", topoproto.KeyspaceShardString(keyspace, shard))
+	}
+
+	query := fmt.Sprintf("select dtid, state, time_created, time_updated from `%s`.transaction", *sidecarDBName)
+	if *olderThan > 0 {
+		query += fmt.Sprintf(" where time_created < %d", time.Now().Add(-*olderThan).UnixNano())
+	}
+	query += " order by time_created"
+	qrproto, err := wr.ExecuteFetchAsDba(ctx, si.MasterAlias, query, 10000, false /* disableBinlogs */, false /* reloadSchema */)
+	if err != nil {
+		return err
+	}
+	qr := sqltypes.Proto3ToResult(qrproto)
+	if *json {
+		return printJSON(wr.Logger(), qr)
+	}
+	printQueryResult(loggerWriter{wr.Logger()}, qr)
+	return nil
+}
+
+func commandReadDistributedTransaction(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	connectTimeout := subFlags.Duration("connect_timeout", 30*time.Second, "Connection timeout for vttablet client")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <dtid> argument is required for the ReadDistributedTransaction command")
+	}
+	dtid := subFlags.Arg(0)
+	target, _, err := txresolver.ParseDTID(dtid)
+	if err != nil {
+		return err
+	}
+	metadata, err := txresolver.NewTopoQueryService(wr.TopoServer(), *connectTimeout).ReadTransaction(ctx, target, dtid)
+	if err != nil {
+		return fmt.Errorf("ReadTransaction failed: %v", err)
+	}
+	if metadata == nil || metadata.Dtid == "" {
+		return fmt.Errorf("distributed transaction %v not found, it may have been concluded already", dtid)
+	}
+	return printJSON(wr.Logger(), metadata)
+}
+
+func commandConcludeDistributedTransaction(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	connectTimeout := subFlags.Duration("connect_timeout", 30*time.Second, "Connection timeout for vttablet client")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <dtid> argument is required for the ConcludeDistributedTransaction command")
+	}
+	return txresolver.Resume(ctx, txresolver.NewTopoQueryService(wr.TopoServer(), *connectTimeout), subFlags.Arg(0))
+}
@@ -93,6 +93,7 @@ import (
 
 	"github.com/youtube/vitess/go/flagutil"
 	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/trace"
 	hk "github.com/youtube/vitess/go/vt/hook"
 	"github.com/youtube/vitess/go/vt/key"
 	"github.com/youtube/vitess/go/vt/logutil"
@@ -187,8 +188,8 @@ var commands = []commandGroup{
 				"<tablet alias> <duration>",
 				"Blocks the action queue on the specified tablet for the specified amount of time. This is typically used for testing."},
 			{"Backup", commandBackup,
-				"[-concurrency=4] <tablet alias>",
-				"Stops mysqld and uses the BackupStorage service to store a new backup. This function also remembers if the tablet was replicating so that it can restore the same state after the backup completes."},
+				"[-concurrency=4] [-incremental] <tablet alias>",
+				"Stops mysqld and uses the BackupStorage service to store a new backup. This function also remembers if the tablet was replicating so that it can restore the same state after the backup completes. With -incremental, mysqld keeps running and only the binlogs since the previous backup are stored."},
 			{"ExecuteHook", commandExecuteHook,
 				"<tablet alias> <hook name> [<param1=value1> <param2=value2> ...]",
 				"Runs the specified hook on the given tablet. A hook is a script that resides in the $VTROOT/vthook directory. You can put any script into that directory and use this command to run that script.\n" +
@@ -355,7 +356,7 @@ var commands = []commandGroup{
 				"Displays the VTGate routing schema."},
 			{"ApplyVSchema", commandApplyVSchema,
 				"{-vschema=<vschema> || -vschema_file=<vschema file>} [-cells=c1,c2,...] [-skip_rebuild] <keyspace>",
-				"Applies the VTGate routing schema to the provided keyspace. Shows the result after application."},
+				"Applies the VTGate routing schema to the provided keyspace. Shows the result after application. The reference tables must already be copied to every shard of the keyspace."},
 			{"RebuildVSchemaGraph", commandRebuildVSchemaGraph,
 				"[-cells=c1,c2,...]",
 				"Rebuilds the cell-specific SrvVSchema from the global VSchema objects in the provided cells (or all cells if none provided)."},
@@ -991,6 +992,7 @@ func commandSleep(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.Fla
 
 func commandBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	concurrency := subFlags.Int("concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously")
+	incremental := subFlags.Bool("incremental", false, "Only backs up the binlogs since the previous backup, for point-in-time restores")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1006,7 +1008,7 @@ func commandBackup(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.Fl
 	if err != nil {
 		return err
 	}
-	stream, err := wr.TabletManagerClient().Backup(ctx, tabletInfo.Tablet, *concurrency)
+	stream, err := wr.TabletManagerClient().Backup(ctx, tabletInfo.Tablet, *concurrency, *incremental)
 	if err != nil {
 		return err
 	}
@@ -2090,6 +2092,9 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 		return err
 	}
 	keyspace := subFlags.Arg(0)
+	if err := wr.ValidateReferenceTables(ctx, keyspace, &vs); err != nil {
+		return err
+	}
 	if err := wr.TopoServer().SaveVSchema(ctx, keyspace, &vs); err != nil {
 		return err
 	}
@@ -2284,7 +2289,10 @@ func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error
 					wr.Logger().Printf("%s\n\n", cmd.help)
 					subFlags.PrintDefaults()
 				}
-				return cmd.method(ctx, wr, subFlags, args[1:])
+				span := trace.NewSpanFromContext(ctx)
+				span.StartLocal("vtctl." + cmd.name)
+				defer span.Finish()
+				return runCommand(trace.NewContext(ctx, span), wr, cmd, subFlags, args)
 			}
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	log "github.com/golang/glog"
 	"golang.org/x/net/context"
 
+	"github.com/youtube/vitess/go/vt/schemamanager/onlineschema"
 	"github.com/youtube/vitess/go/vt/schemamanager/schemaswap"
 	"github.com/youtube/vitess/go/vt/servenv"
 	"github.com/youtube/vitess/go/vt/topo"
@@ -28,6 +29,7 @@ func initWorkflowManager(ts topo.Server) {
 		topovalidator.Register()
 
 		schemaswap.RegisterWorkflowFactory()
+		onlineschema.RegisterWorkflowFactory()
 
 		// Create the WorkflowManager.
 		vtctl.WorkflowManager = workflow.NewManager(ts)
@@ -0,0 +1,237 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package consolidator contains the logic to consolidate the identical
+replica and rdonly reads that are executing at the same time in VTGate,
+and to cache their results for a short time.
+
+vttablet already consolidates the identical SELECTs it is executing,
+but each VTGate sends its own copy of a query to the tablet. When many
+app servers send the same scatter read, every shard receives it N
+times. With -enable_vtgate_consolidator, VTGate only sends one copy of
+each shard query at a time, and all the callers share its result. With
+-vtgate_result_cache_ttl, the results are also kept for a short time,
+and identical queries are answered from the cache.
+
+Queries are keyed on their normalized SQL, their bind variables, their
+target, and their effective and immediate caller IDs: a caller never
+gets a result read with the identity of another one, which the table
+ACLs of the tablet may have treated differently. Only the SELECTs sent
+to replica and rdonly tablets outside of a transaction are eligible. The queries that ask for an
+EventToken are not, as their results depend on when they are executed.
+*/
+package consolidator
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/sync2"
+	"github.com/youtube/vitess/go/vt/callerid"
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/tabletserver/querytypes"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+var (
+	enableConsolidator = flag.Bool("enable_vtgate_consolidator", false, "Consolidate the identical replica and rdonly reads executing at the same time, and send only one of them to the tablet.")
+	resultCacheTTL     = flag.Duration("vtgate_result_cache_ttl", 0, "If positive, the results of the replica and rdonly reads are cached for this long, and identical queries are answered from the cache.")
+	resultCacheSize    = flag.Int64("vtgate_result_cache_size", 10000, "The maximum number of rows in the result cache.")
+	resultCacheMaxRows = flag.Int("vtgate_result_cache_max_rows", 1000, "Results with more rows than this are not cached.")
+
+	consolidations    = stats.NewCounters("VtgateConsolidations")
+	resultCacheHits   = stats.NewCounters("VtgateResultCacheHits")
+	resultCacheMisses = stats.NewCounters("VtgateResultCacheMisses")
+)
+
+// Consolidator consolidates the identical queries, and caches their
+// results.
+type Consolidator struct {
+	consolidate bool
+	ttl         time.Duration
+	queries     *sync2.Consolidator
+	results     *resultCache
+}
+
+// newConsolidator returns a Consolidator. The results are cached if
+// ttl is positive.
+func newConsolidator(consolidate bool, ttl time.Duration, cacheSize int64, cacheMaxRows int) *Consolidator {
+	return &Consolidator{
+		consolidate: consolidate,
+		ttl:         ttl,
+		queries:     sync2.NewConsolidator(),
+		results:     newResultCache(cacheSize, cacheMaxRows),
+	}
+}
+
+var (
+	defaultConsolidator *Consolidator
+	defaultOnce         sync.Once
+)
+
+// getDefault returns the Consolidator used by vtgate. It is created
+// on first use, after the flags are parsed.
+func getDefault() *Consolidator {
+	defaultOnce.Do(func() {
+		defaultConsolidator = newConsolidator(*enableConsolidator, *resultCacheTTL, *resultCacheSize, *resultCacheMaxRows)
+		stats.Publish("VtgateResultCacheLength", stats.IntFunc(defaultConsolidator.results.cache.Length))
+		stats.Publish("VtgateResultCacheSize", stats.IntFunc(defaultConsolidator.results.cache.Size))
+		stats.Publish("VtgateResultCacheCapacity", stats.IntFunc(defaultConsolidator.results.cache.Capacity))
+	})
+	return defaultConsolidator
+}
+
+// Execute runs a query on one shard outside of a transaction. If the
+// query is eligible, its execution is shared with the identical
+// queries executing at the same time, and its result may come from the
+// result cache. execute sends the query to the tablet, with the
+// context it is passed. The returned result is owned by the caller.
+func Execute(ctx context.Context, target *querypb.Target, sql string, bindVars map[string]interface{}, options *querypb.ExecuteOptions, execute func(ctx context.Context) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	return getDefault().Execute(ctx, target, sql, bindVars, options, execute)
+}
+
+// Execute is the Consolidator version of the package-level Execute.
+func (c *Consolidator) Execute(ctx context.Context, target *querypb.Target, sql string, bindVars map[string]interface{}, options *querypb.ExecuteOptions, execute func(ctx context.Context) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if !c.consolidate && c.ttl <= 0 {
+		return execute(ctx)
+	}
+	if !eligible(target, options) {
+		return execute(ctx)
+	}
+	key, ok := queryKey(ctx, target, sql, bindVars)
+	if !ok {
+		return execute(ctx)
+	}
+
+	// The results are shared with the other callers, or kept in the
+	// cache: each caller gets its own copy, which it may modify.
+	if c.ttl > 0 {
+		if qr, ok := c.results.get(key); ok {
+			resultCacheHits.Add(target.Keyspace, 1)
+			return qr.Copy(), nil
+		}
+		resultCacheMisses.Add(target.Keyspace, 1)
+	}
+	if !c.consolidate {
+		qr, err := execute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.results.set(key, qr, c.ttl)
+		return qr.Copy(), nil
+	}
+
+	q, created := c.queries.Create(key)
+	if created {
+		func() {
+			// Broadcast must be called even if execute
+			// panics, or the waiters would wait forever.
+			defer q.Broadcast()
+			// The waiters must not fail because this caller
+			// went away.
+			sharedCtx, cancel := detach(ctx)
+			defer cancel()
+			q.Result, q.Err = execute(sharedCtx)
+			if q.Err == nil && c.ttl > 0 {
+				// Set before the Broadcast, so the queries
+				// that come after it hit the cache.
+				c.results.set(key, q.Result.(*sqltypes.Result), c.ttl)
+			}
+		}()
+	} else {
+		consolidations.Add(target.Keyspace, 1)
+		q.Wait()
+	}
+	if q.Err != nil {
+		return nil, q.Err
+	}
+	return q.Result.(*sqltypes.Result).Copy(), nil
+}
+
+// detachedContext has the values of its parent, e.g. the caller IDs,
+// but not its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// detach returns a context for a shared execution. It has the values
+// and the deadline of ctx, but it is not canceled with ctx.
+func detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detachedContext{ctx}, deadline)
+	}
+	return context.WithCancel(detachedContext{ctx})
+}
+
+// eligible returns true if the queries with this target and options
+// can be consolidated and cached.
+func eligible(target *querypb.Target, options *querypb.ExecuteOptions) bool {
+	if target.TabletType != topodatapb.TabletType_REPLICA && target.TabletType != topodatapb.TabletType_RDONLY {
+		return false
+	}
+	if options != nil && (options.IncludeEventToken || options.CompareEventToken != nil) {
+		return false
+	}
+	return true
+}
+
+// queryKey returns the key of a query, which includes the caller IDs
+// of ctx. It returns false if the query is not a simple SELECT, or if
+// it locks rows.
+func queryKey(ctx context.Context, target *querypb.Target, sql string, bindVars map[string]interface{}) (string, bool) {
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return "", false
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Lock != "" {
+		return "", false
+	}
+	bv, err := querytypes.BindVariablesToProto3(bindVars)
+	if err != nil {
+		return "", false
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%v/%v@%v: %v", target.Keyspace, target.Shard, topoproto.TabletTypeLString(target.TabletType), sqlparser.String(stmt))
+	names := make([]string, 0, len(bv))
+	for name := range bv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(buf, " :%v=%v", name, bv[name])
+	}
+	if ef := callerid.EffectiveCallerIDFromContext(ctx); ef != nil {
+		fmt.Fprintf(buf, " effective_caller_id={%v}", ef)
+	}
+	if im := callerid.ImmediateCallerIDFromContext(ctx); im != nil {
+		fmt.Fprintf(buf, " immediate_caller_id={%v}", im)
+	}
+	return buf.String(), true
+}
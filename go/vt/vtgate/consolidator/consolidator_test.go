@@ -0,0 +1,239 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package consolidator
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/sync2"
+	"github.com/youtube/vitess/go/vt/callerid"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+var replicaTarget = &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_REPLICA}
+
+// countingExecute returns an execute function that counts its calls,
+// and returns a result with one row.
+func countingExecute(count *sync2.AtomicInt64, release chan struct{}) func(context.Context) (*sqltypes.Result, error) {
+	return func(context.Context) (*sqltypes.Result, error) {
+		count.Add(1)
+		if release != nil {
+			<-release
+		}
+		return &sqltypes.Result{
+			Rows: [][]sqltypes.Value{{sqltypes.MakeString([]byte("a"))}},
+		}, nil
+	}
+}
+
+func TestQueryKey(t *testing.T) {
+	testcases := []struct {
+		target   *querypb.Target
+		sql      string
+		bindVars map[string]interface{}
+		key      string
+		ok       bool
+	}{{
+		target:   replicaTarget,
+		sql:      "select  a from t where id = :id and name = :name",
+		bindVars: map[string]interface{}{"name": "x", "id": 1},
+		key:      `ks/-80@replica: select a from t where id = :id and name = :name :id=type:INT64 value:"1"  :name=type:VARCHAR value:"x" `,
+		ok:       true,
+	}, {
+		target: replicaTarget,
+		sql:    "select a from t for update",
+	}, {
+		target: replicaTarget,
+		sql:    "update t set a = 1",
+	}, {
+		target: replicaTarget,
+		sql:    "not a query",
+	}}
+	for _, tcase := range testcases {
+		key, ok := queryKey(context.Background(), tcase.target, tcase.sql, tcase.bindVars)
+		if key != tcase.key || ok != tcase.ok {
+			t.Errorf("queryKey(%v) = (%q, %v), want (%q, %v)", tcase.sql, key, ok, tcase.key, tcase.ok)
+		}
+	}
+
+	// The key doesn't depend on the formatting of the query.
+	key1, _ := queryKey(context.Background(), replicaTarget, "SELECT a FROM t", nil)
+	key2, _ := queryKey(context.Background(), replicaTarget, "select a\n from t", nil)
+	if key1 != key2 {
+		t.Errorf("keys of identical queries differ: %q and %q", key1, key2)
+	}
+
+	// Different callers never share a key.
+	keys := make(map[string]bool)
+	for _, ctx := range []context.Context{
+		context.Background(),
+		callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("alice", "", ""), nil),
+		callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("bob", "", ""), nil),
+		callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("alice")),
+		callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("alice", "", ""), callerid.NewImmediateCallerID("bob")),
+	} {
+		key, _ := queryKey(ctx, replicaTarget, "select a from t", nil)
+		if keys[key] {
+			t.Errorf("key %q used by different callers", key)
+		}
+		keys[key] = true
+	}
+}
+
+func TestConsolidate(t *testing.T) {
+	c := newConsolidator(true, 0, 100, 10)
+	var count sync2.AtomicInt64
+	release := make(chan struct{})
+	execute := countingExecute(&count, release)
+
+	var wg sync.WaitGroup
+	results := make([]*sqltypes.Result, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			results[i], err = c.Execute(context.Background(), replicaTarget, "select a from t", nil, nil, execute)
+			if err != nil {
+				t.Errorf("Execute failed: %v", err)
+			}
+		}(i)
+	}
+	// Wait until the first query executes, and the others wait
+	// for it.
+	for count.Get() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := count.Get(); got != 1 {
+		t.Errorf("query executed %v times, want 1", got)
+	}
+	for _, qr := range results {
+		if !reflect.DeepEqual(qr, results[0]) {
+			t.Errorf("got different results: %v and %v", qr, results[0])
+		}
+	}
+	// Each caller gets its own copy of the result.
+	results[0].Rows[0][0] = sqltypes.MakeString([]byte("b"))
+	if got := results[1].Rows[0][0].String(); got != "a" {
+		t.Errorf("shared result was modified: %v", got)
+	}
+
+	// Errors are shared too, and are not cached.
+	want := errors.New("tablet error")
+	if _, err := c.Execute(context.Background(), replicaTarget, "select a from t", nil, nil, func(context.Context) (*sqltypes.Result, error) {
+		return nil, want
+	}); err != want {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+}
+
+func TestNotEligible(t *testing.T) {
+	c := newConsolidator(true, time.Hour, 100, 10)
+	var count sync2.AtomicInt64
+	execute := countingExecute(&count, nil)
+
+	masterTarget := &querypb.Target{Keyspace: "ks", Shard: "-80", TabletType: topodatapb.TabletType_MASTER}
+	for i := 0; i < 2; i++ {
+		c.Execute(context.Background(), masterTarget, "select a from t", nil, nil, execute)
+		c.Execute(context.Background(), replicaTarget, "select a from t", nil, &querypb.ExecuteOptions{IncludeEventToken: true}, execute)
+		c.Execute(context.Background(), replicaTarget, "select a from t for update", nil, nil, execute)
+	}
+	if got := count.Get(); got != 6 {
+		t.Errorf("queries executed %v times, want 6", got)
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	c := newConsolidator(false, time.Hour, 100, 1)
+	var count sync2.AtomicInt64
+	execute := countingExecute(&count, nil)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Execute(context.Background(), replicaTarget, "select a from t", nil, nil, execute); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if got := count.Get(); got != 1 {
+		t.Errorf("query executed %v times, want 1", got)
+	}
+	if entries := c.results.entries(); len(entries) != 1 || entries[0].Rows != 1 {
+		t.Errorf("unexpected cache entries: %v", entries)
+	}
+
+	// The cached result is not modified through a returned copy.
+	qr, _ := c.Execute(context.Background(), replicaTarget, "select a from t", nil, nil, execute)
+	qr.Rows[0][0] = sqltypes.MakeString([]byte("b"))
+	qr, _ = c.Execute(context.Background(), replicaTarget, "select a from t", nil, nil, execute)
+	if got := qr.Rows[0][0].String(); got != "a" {
+		t.Errorf("cached result was modified: %v", got)
+	}
+
+	// Different bind variables or targets are different queries.
+	c.Execute(context.Background(), replicaTarget, "select a from t", map[string]interface{}{"a": 1}, nil, execute)
+	c.Execute(context.Background(), &querypb.Target{Keyspace: "ks", Shard: "80-", TabletType: topodatapb.TabletType_REPLICA}, "select a from t", nil, nil, execute)
+	if got := count.Get(); got != 3 {
+		t.Errorf("queries executed %v times, want 3", got)
+	}
+
+	// Results with too many rows are not cached.
+	big := func(context.Context) (*sqltypes.Result, error) {
+		count.Add(1)
+		return &sqltypes.Result{Rows: make([][]sqltypes.Value, 2)}, nil
+	}
+	c.Execute(context.Background(), replicaTarget, "select b from t", nil, nil, big)
+	c.Execute(context.Background(), replicaTarget, "select b from t", nil, nil, big)
+	if got := count.Get(); got != 5 {
+		t.Errorf("queries executed %v times, want 5", got)
+	}
+}
+
+func TestResultCacheExpiration(t *testing.T) {
+	rc := newResultCache(100, 10)
+	qr := &sqltypes.Result{}
+	rc.set("expired", qr, -time.Second)
+	rc.set("valid", qr, time.Hour)
+	if _, ok := rc.get("expired"); ok {
+		t.Errorf("got an expired result")
+	}
+	if got, ok := rc.get("valid"); !ok || got != qr {
+		t.Errorf("get(valid) = (%v, %v), want (%v, true)", got, ok, qr)
+	}
+	if got := rc.cache.Length(); got != 1 {
+		t.Errorf("cache length = %v, want 1", got)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	ef := callerid.NewEffectiveCallerID("alice", "", "")
+	ctx := callerid.NewContext(context.Background(), ef, nil)
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	deadline, _ := ctx.Deadline()
+
+	detached, detachedCancel := detach(ctx)
+	defer detachedCancel()
+	cancel()
+	if err := detached.Err(); err != nil {
+		t.Errorf("detached context canceled with its parent: %v", err)
+	}
+	if got, ok := detached.Deadline(); !ok || !got.Equal(deadline) {
+		t.Errorf("Deadline() = (%v, %v), want (%v, true)", got, ok, deadline)
+	}
+	if got := callerid.EffectiveCallerIDFromContext(detached); got != ef {
+		t.Errorf("effective caller ID = %v, want %v", got, ef)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package consolidator
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	log "github.com/golang/glog"
+
+	"github.com/youtube/vitess/go/acl"
+	"github.com/youtube/vitess/go/vt/logz"
+)
+
+const resultCachezHeaderHTML = `
+	<thead>
+		<tr>
+			<th>Query</th>
+			<th>Rows</th>
+			<th>Expires In</th>
+		</tr>
+	</thead>
+`
+
+const resultCachezRowHTML = `
+		<tr class="low">
+			<td>{{.Key}}</td>
+			<td>{{.Rows}}</td>
+			<td>{{.ExpiresIn}}</td>
+		</tr>
+`
+
+var resultCachezRowTemplate = template.Must(template.New("resultcachez").Parse(resultCachezRowHTML))
+
+func init() {
+	// vttablet uses /debug/consolidations, and both can run in
+	// the same process.
+	http.HandleFunc("/debug/vtgate_consolidations", func(w http.ResponseWriter, r *http.Request) {
+		getDefault().queries.ServeHTTP(w, r)
+	})
+	http.HandleFunc("/debug/result_cache", func(w http.ResponseWriter, r *http.Request) {
+		resultCachezHandler(w, r, getDefault())
+	})
+}
+
+// resultCachezHandler lists the cached results. The hit stats and
+// the size of the cache are exported as variables.
+func resultCachezHandler(w http.ResponseWriter, r *http.Request, c *Consolidator) {
+	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+	logz.StartHTMLTable(w)
+	defer logz.EndHTMLTable(w)
+
+	w.Write([]byte(resultCachezHeaderHTML))
+	now := time.Now()
+	for _, e := range c.results.entries() {
+		row := struct {
+			Key       string
+			Rows      int
+			ExpiresIn time.Duration
+		}{e.Key, e.Rows, e.Expires.Sub(now)}
+		if err := resultCachezRowTemplate.Execute(w, row); err != nil {
+			log.Errorf("resultcachez: couldn't execute template: %v", err)
+		}
+	}
+}
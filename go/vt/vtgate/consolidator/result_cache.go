@@ -0,0 +1,92 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package consolidator
+
+import (
+	"time"
+
+	"github.com/youtube/vitess/go/cache"
+	"github.com/youtube/vitess/go/sqltypes"
+)
+
+// resultCache is an LRU cache of query results, with a TTL. Its size
+// is the number of rows of the cached results.
+type resultCache struct {
+	cache   *cache.LRUCache
+	maxRows int
+}
+
+// cachedResult is the value stored in the cache.
+type cachedResult struct {
+	result  *sqltypes.Result
+	expires time.Time
+}
+
+// Size is part of the cache.Value interface. Empty results count as
+// one row, so the number of entries is also bounded.
+func (cr *cachedResult) Size() int {
+	if len(cr.result.Rows) == 0 {
+		return 1
+	}
+	return len(cr.result.Rows)
+}
+
+func newResultCache(capacity int64, maxRows int) *resultCache {
+	return &resultCache{
+		cache:   cache.NewLRUCache(capacity),
+		maxRows: maxRows,
+	}
+}
+
+// get returns the cached result of a query, if it has not expired.
+func (rc *resultCache) get(key string) (*sqltypes.Result, bool) {
+	v, ok := rc.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	cr := v.(*cachedResult)
+	if time.Now().After(cr.expires) {
+		rc.cache.Delete(key)
+		return nil, false
+	}
+	return cr.result, true
+}
+
+// set caches the result of a query for ttl, unless it is too big.
+func (rc *resultCache) set(key string, result *sqltypes.Result, ttl time.Duration) {
+	if len(result.Rows) > rc.maxRows {
+		return
+	}
+	rc.cache.Set(key, &cachedResult{
+		result:  result,
+		expires: time.Now().Add(ttl),
+	})
+}
+
+// entry describes a cached result for the status page.
+type entry struct {
+	Key     string
+	Rows    int
+	Expires time.Time
+}
+
+// entries returns the non-expired cached results, most recently used
+// first.
+func (rc *resultCache) entries() []entry {
+	now := time.Now()
+	var result []entry
+	for _, item := range rc.cache.Items() {
+		cr := item.Value.(*cachedResult)
+		if now.After(cr.expires) {
+			continue
+		}
+		result = append(result, entry{
+			Key:     item.Key,
+			Rows:    len(cr.result.Rows),
+			Expires: cr.expires,
+		})
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"errors"
+
+	"github.com/youtube/vitess/go/sqltypes"
+)
+
+// Limit is a primitive that performs the LIMIT operation
+// for scatter queries. The underlying route fetches up to
+// Offset+Count rows from each shard. The rows are expected
+// to be already merge-sorted, if an order was specified.
+// Limit then skips the first Offset rows, and returns
+// up to Count rows.
+type Limit struct {
+	Count  int64
+	Offset int64 `json:",omitempty"`
+	// Input is the primitive that supplies the rows.
+	Input Primitive
+}
+
+// Execute performs a non-streaming exec.
+func (l *Limit) Execute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool) (*sqltypes.Result, error) {
+	result, err := l.Input.Execute(vcursor, joinvars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	rows := result.Rows
+	if int64(len(rows)) <= l.Offset {
+		rows = nil
+	} else {
+		rows = rows[l.Offset:]
+	}
+	if int64(len(rows)) > l.Count {
+		rows = rows[:l.Count]
+	}
+	result.Rows = rows
+	result.RowsAffected = uint64(len(rows))
+	return result, nil
+}
+
+// errLimitReached is returned to the input of a streaming Limit
+// once the limit is reached, to stop the stream.
+var errLimitReached = errors.New("limit reached")
+
+// StreamExecute performs a streaming exec. Once the limit
+// is reached, the input stream is stopped.
+func (l *Limit) StreamExecute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool, sendReply func(*sqltypes.Result) error) error {
+	offset, count := l.Offset, l.Count
+	err := l.Input.StreamExecute(vcursor, joinvars, wantfields, func(qr *sqltypes.Result) error {
+		if len(qr.Fields) != 0 {
+			if err := sendReply(&sqltypes.Result{Fields: qr.Fields}); err != nil {
+				return err
+			}
+		}
+		if count == 0 {
+			return errLimitReached
+		}
+		rows := qr.Rows
+		if offset > 0 {
+			if int64(len(rows)) <= offset {
+				offset -= int64(len(rows))
+				return nil
+			}
+			rows = rows[offset:]
+			offset = 0
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if int64(len(rows)) > count {
+			rows = rows[:count]
+		}
+		count -= int64(len(rows))
+		if err := sendReply(&sqltypes.Result{Rows: rows, RowsAffected: uint64(len(rows))}); err != nil {
+			return err
+		}
+		if count == 0 {
+			return errLimitReached
+		}
+		return nil
+	})
+	if err == errLimitReached {
+		return nil
+	}
+	return err
+}
+
+// GetFields fetches the field info.
+func (l *Limit) GetFields(vcursor VCursor, joinvars map[string]interface{}) (*sqltypes.Result, error) {
+	return l.Input.GetFields(vcursor, joinvars)
+}
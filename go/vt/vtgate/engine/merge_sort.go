@@ -0,0 +1,239 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/youtube/vitess/go/sqltypes"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// StreamFunc streams the results of a single source
+// through sendReply. It's used by MergeSort to read
+// the results of the individual shards of a scatter query.
+type StreamFunc func(sendReply func(*sqltypes.Result) error) error
+
+// errMergeSortAborted is returned to the sources of a MergeSort
+// if the merge terminated before all of them were consumed.
+var errMergeSortAborted = errors.New("merge sort aborted")
+
+// SortRows sorts the rows in place as specified by orderBy.
+// The sort is stable.
+func SortRows(rows [][]sqltypes.Value, orderBy []OrderbyParams) error {
+	sh := &sortHeap{
+		rows:    rows,
+		orderBy: orderBy,
+	}
+	sort.Stable(sh)
+	return sh.err
+}
+
+// MergeSort performs a k-way merge of the results coming from
+// streams. Each stream must return its rows already sorted as
+// specified by orderBy. The field info is sent only once, from
+// the first stream that supplies it. Rows that are already
+// available are batched together. A result is sent as soon as
+// the merge has to wait for a stream.
+func MergeSort(streams []StreamFunc, orderBy []OrderbyParams, sendReply func(*sqltypes.Result) error) error {
+	done := make(chan struct{})
+	errs := make([]error, len(streams))
+	sources := make([]*mergeSource, len(streams))
+	var wg sync.WaitGroup
+	for i, stream := range streams {
+		sources[i] = &mergeSource{results: make(chan *sqltypes.Result, 1)}
+		wg.Add(1)
+		go func(i int, stream StreamFunc) {
+			defer wg.Done()
+			defer close(sources[i].results)
+			errs[i] = stream(func(qr *sqltypes.Result) error {
+				select {
+				case sources[i].results <- qr:
+					return nil
+				case <-done:
+					return errMergeSortAborted
+				}
+			})
+		}(i, stream)
+	}
+
+	err := mergeSources(sources, orderBy, sendReply)
+	close(done)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSources merges the rows of the sources and sends them
+// through sendReply.
+func mergeSources(sources []*mergeSource, orderBy []OrderbyParams, sendReply func(*sqltypes.Result) error) error {
+	fieldSent := false
+	pending := &sqltypes.Result{}
+	flush := func() error {
+		if len(pending.Rows) == 0 {
+			return nil
+		}
+		qr := pending
+		pending = &sqltypes.Result{}
+		return sendReply(qr)
+	}
+	// next returns the next row of the source. If the source
+	// has no buffered rows, pending rows are flushed before
+	// waiting for its next result.
+	next := func(src *mergeSource) ([]sqltypes.Value, bool, error) {
+		for len(src.rows) == 0 {
+			if err := flush(); err != nil {
+				return nil, false, err
+			}
+			qr, ok := <-src.results
+			if !ok {
+				return nil, false, nil
+			}
+			if len(qr.Fields) != 0 && !fieldSent {
+				fieldSent = true
+				if err := sendReply(&sqltypes.Result{Fields: qr.Fields}); err != nil {
+					return nil, false, err
+				}
+			}
+			src.rows = qr.Rows
+		}
+		row := src.rows[0]
+		src.rows = src.rows[1:]
+		return row, true, nil
+	}
+
+	sh := &sortHeap{orderBy: orderBy}
+	for i, src := range sources {
+		row, ok, err := next(src)
+		if err != nil {
+			return err
+		}
+		if ok {
+			sh.rows = append(sh.rows, row)
+			sh.sources = append(sh.sources, i)
+		}
+	}
+	heap.Init(sh)
+	if sh.err != nil {
+		return sh.err
+	}
+	for sh.Len() > 0 {
+		src := sh.sources[0]
+		pending.Rows = append(pending.Rows, sh.rows[0])
+		pending.RowsAffected++
+		row, ok, err := next(sources[src])
+		if err != nil {
+			return err
+		}
+		if ok {
+			sh.rows[0] = row
+			heap.Fix(sh, 0)
+		} else {
+			heap.Pop(sh)
+		}
+		if sh.err != nil {
+			return sh.err
+		}
+	}
+	return flush()
+}
+
+// mergeSource holds the results of a stream that
+// are yet to be merged.
+type mergeSource struct {
+	results chan *sqltypes.Result
+	rows    [][]sqltypes.Value
+}
+
+// sortHeap is used for sorting and merging rows. It implements
+// both sort.Interface and heap.Interface. If sources is set,
+// it's kept in sync with rows. Since the interfaces can't return
+// errors, the first comparison error is stored in err.
+type sortHeap struct {
+	rows    [][]sqltypes.Value
+	sources []int
+	orderBy []OrderbyParams
+	err     error
+}
+
+// Len is part of sort.Interface.
+func (sh *sortHeap) Len() int {
+	return len(sh.rows)
+}
+
+// Less is part of sort.Interface.
+func (sh *sortHeap) Less(i, j int) bool {
+	for _, order := range sh.orderBy {
+		if sh.err != nil {
+			return true
+		}
+		cmp, err := compareOrder(sh.rows[i], sh.rows[j], order)
+		if err != nil {
+			sh.err = err
+			return true
+		}
+		if cmp == 0 {
+			continue
+		}
+		if order.Desc {
+			cmp = -cmp
+		}
+		return cmp < 0
+	}
+	return false
+}
+
+// compareOrder compares the order by column of two rows. The text
+// values are compared by their weight strings, which MySQL computes
+// according to their collation. The enums and sets are sorted by
+// MySQL according to their index in the column definition, which is
+// not known here.
+func compareOrder(row1, row2 []sqltypes.Value, order OrderbyParams) (int, error) {
+	v1, v2 := row1[order.Col], row2[order.Col]
+	for _, typ := range []querypb.Type{v1.Type(), v2.Type()} {
+		if typ == sqltypes.Enum || typ == sqltypes.Set {
+			return 0, fmt.Errorf("unsupported: merge-sorting %v values in scatter", typ)
+		}
+	}
+	if order.WeightStringCol != 0 && (sqltypes.IsText(v1.Type()) || sqltypes.IsText(v2.Type())) {
+		v1, v2 = row1[order.WeightStringCol], row2[order.WeightStringCol]
+	}
+	return sqltypes.NullsafeCompare(v1, v2)
+}
+
+// Swap is part of sort.Interface.
+func (sh *sortHeap) Swap(i, j int) {
+	sh.rows[i], sh.rows[j] = sh.rows[j], sh.rows[i]
+	if sh.sources != nil {
+		sh.sources[i], sh.sources[j] = sh.sources[j], sh.sources[i]
+	}
+}
+
+// Push is part of heap.Interface. It's never called:
+// rows are appended before the heap is initialized.
+func (sh *sortHeap) Push(x interface{}) {
+	panic("unreachable")
+}
+
+// Pop is part of heap.Interface.
+func (sh *sortHeap) Pop() interface{} {
+	n := len(sh.rows) - 1
+	row := sh.rows[n]
+	sh.rows = sh.rows[:n]
+	sh.sources = sh.sources[:n]
+	return row
+}
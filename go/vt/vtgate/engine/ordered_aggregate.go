@@ -0,0 +1,369 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/youtube/vitess/go/sqltypes"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+)
+
+// OrderedAggregate is a primitive that expects the underlying
+// primitive to feed results sorted by the Keys. Consecutive rows
+// with the same keys are combined using the Aggregates. The
+// underlying primitive is typically a scatter route that returns
+// partial aggregates computed by each shard. The values of columns
+// that are neither keys nor aggregates are taken from the first
+// row of each group.
+type OrderedAggregate struct {
+	// Aggregates specifies the aggregation parameters for each
+	// aggregation function: function opcode and input column number.
+	Aggregates []AggregateParams `json:",omitempty"`
+	// Keys specifies the input values that must be used for
+	// the aggregation key.
+	Keys []int `json:",omitempty"`
+	// KeyWeightStrings specifies, for each key, the column of its
+	// weight_string. The text keys are compared by their weight
+	// strings: MySQL groups them according to their collation.
+	// 0 means there is none.
+	KeyWeightStrings []int `json:",omitempty"`
+	// TruncateColumnCount specifies the number of columns to return
+	// in the final result. The rest of the columns are truncated.
+	// They're used only for computing the aggregates. If 0, no
+	// truncation happens.
+	TruncateColumnCount int `json:",omitempty"`
+	// Input is the primitive that feeds the rows.
+	Input Primitive
+}
+
+// AggregateParams specifies the parameters for an aggregate function.
+type AggregateParams struct {
+	Opcode AggregateOpcode
+	Col    int
+	// CountCol is the column that contains the count
+	// for an AggregateAvg. Col contains the sum.
+	CountCol int `json:",omitempty"`
+	// Alias is the name of the result column, if it
+	// differs from the name returned by the input.
+	Alias string `json:",omitempty"`
+	// WeightStringCol is the column of the weight_string of
+	// the partial minimum or maximum, if any. Like MySQL, the
+	// text values are compared according to their collation.
+	WeightStringCol int `json:",omitempty"`
+}
+
+// AggregateOpcode is the aggregation Opcode.
+type AggregateOpcode int
+
+// These constants list the possible aggregate opcodes.
+const (
+	// AggregateCount adds up the partial counts.
+	AggregateCount = AggregateOpcode(iota)
+	// AggregateSum adds up the partial sums.
+	AggregateSum
+	// AggregateMin returns the lowest partial minimum.
+	AggregateMin
+	// AggregateMax returns the highest partial maximum.
+	AggregateMax
+	// AggregateAvg adds up the partial sums and counts,
+	// and returns the average computed from them.
+	AggregateAvg
+)
+
+var aggregateName = map[AggregateOpcode]string{
+	AggregateCount: "count",
+	AggregateSum:   "sum",
+	AggregateMin:   "min",
+	AggregateMax:   "max",
+	AggregateAvg:   "avg",
+}
+
+// SupportedAggregates maps the supported aggregate
+// function names to their opcodes.
+var SupportedAggregates = map[string]AggregateOpcode{
+	"count": AggregateCount,
+	"sum":   AggregateSum,
+	"min":   AggregateMin,
+	"max":   AggregateMax,
+	"avg":   AggregateAvg,
+}
+
+func (code AggregateOpcode) String() string {
+	return aggregateName[code]
+}
+
+// MarshalJSON serializes the AggregateOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code AggregateOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// avgScaleIncrement is the number of decimal digits added
+// to the scale of the sum when computing an average.
+// This matches the MySQL default for div_precision_increment.
+const avgScaleIncrement = 4
+
+// Execute performs a non-streaming exec.
+func (oa *OrderedAggregate) Execute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool) (*sqltypes.Result, error) {
+	result, err := oa.Input.Execute(vcursor, joinvars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	out := &sqltypes.Result{
+		Fields: oa.convertFields(result.Fields),
+		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
+	}
+	var current []sqltypes.Value
+	for _, row := range result.Rows {
+		if current == nil {
+			current = row
+			continue
+		}
+		if oa.keysEqual(current, row) {
+			current, err = oa.merge(current, row)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if current, err = oa.finalize(current); err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, current)
+		current = row
+	}
+	if current != nil {
+		if current, err = oa.finalize(current); err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, current)
+	}
+	out.RowsAffected = uint64(len(out.Rows))
+	return out, nil
+}
+
+// StreamExecute performs a streaming exec. A group is sent
+// only after the first row of the next group is received.
+func (oa *OrderedAggregate) StreamExecute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool, sendReply func(*sqltypes.Result) error) error {
+	var current []sqltypes.Value
+	err := oa.Input.StreamExecute(vcursor, joinvars, wantfields, func(qr *sqltypes.Result) error {
+		if len(qr.Fields) != 0 {
+			if err := sendReply(&sqltypes.Result{Fields: oa.convertFields(qr.Fields)}); err != nil {
+				return err
+			}
+		}
+		out := &sqltypes.Result{}
+		for _, row := range qr.Rows {
+			if current == nil {
+				current = row
+				continue
+			}
+			var err error
+			if oa.keysEqual(current, row) {
+				current, err = oa.merge(current, row)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if current, err = oa.finalize(current); err != nil {
+				return err
+			}
+			out.Rows = append(out.Rows, current)
+			current = row
+		}
+		if len(out.Rows) == 0 {
+			return nil
+		}
+		out.RowsAffected = uint64(len(out.Rows))
+		return sendReply(out)
+	})
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	if current, err = oa.finalize(current); err != nil {
+		return err
+	}
+	return sendReply(&sqltypes.Result{Rows: [][]sqltypes.Value{current}, RowsAffected: 1})
+}
+
+// GetFields fetches the field info.
+func (oa *OrderedAggregate) GetFields(vcursor VCursor, joinvars map[string]interface{}) (*sqltypes.Result, error) {
+	qr, err := oa.Input.GetFields(vcursor, joinvars)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: oa.convertFields(qr.Fields)}, nil
+}
+
+// keysEqual returns true if the two rows belong to the same group.
+// The keys are compared byte by byte, except for the text keys,
+// whose weight strings are compared instead.
+func (oa *OrderedAggregate) keysEqual(row1, row2 []sqltypes.Value) bool {
+	for i, key := range oa.Keys {
+		v1, v2 := row1[key], row2[key]
+		if i < len(oa.KeyWeightStrings) && oa.KeyWeightStrings[i] != 0 && (sqltypes.IsCollated(v1.Type()) || sqltypes.IsCollated(v2.Type())) {
+			v1, v2 = row1[oa.KeyWeightStrings[i]], row2[oa.KeyWeightStrings[i]]
+		}
+		if v1.IsNull() != v2.IsNull() || !bytes.Equal(v1.Raw(), v2.Raw()) {
+			return false
+		}
+	}
+	return true
+}
+
+// merge combines row2 into row1, and returns the combined row.
+// row1 is not modified.
+func (oa *OrderedAggregate) merge(row1, row2 []sqltypes.Value) ([]sqltypes.Value, error) {
+	result := make([]sqltypes.Value, len(row1))
+	copy(result, row1)
+	for _, aggr := range oa.Aggregates {
+		v1, v2 := row1[aggr.Col], row2[aggr.Col]
+		var err error
+		switch aggr.Opcode {
+		case AggregateCount, AggregateSum:
+			result[aggr.Col], err = sqltypes.NullsafeAdd(v1, v2)
+		case AggregateAvg:
+			result[aggr.Col], err = sqltypes.NullsafeAdd(v1, v2)
+			if err != nil {
+				return nil, err
+			}
+			result[aggr.CountCol], err = sqltypes.NullsafeAdd(row1[aggr.CountCol], row2[aggr.CountCol])
+		case AggregateMin, AggregateMax:
+			var second bool
+			second, err = minMaxSecond(row1, row2, aggr)
+			if second {
+				result[aggr.Col] = v2
+				if aggr.WeightStringCol != 0 {
+					result[aggr.WeightStringCol] = row2[aggr.WeightStringCol]
+				}
+			}
+		default:
+			return nil, fmt.Errorf("BUG: unexpected opcode: %v", aggr.Opcode)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// finalize computes the averages and truncates the row.
+func (oa *OrderedAggregate) finalize(row []sqltypes.Value) ([]sqltypes.Value, error) {
+	for _, aggr := range oa.Aggregates {
+		if aggr.Opcode != AggregateAvg {
+			continue
+		}
+		avg, err := average(row[aggr.Col], row[aggr.CountCol])
+		if err != nil {
+			return nil, err
+		}
+		row[aggr.Col] = avg
+	}
+	if oa.TruncateColumnCount == 0 {
+		return row, nil
+	}
+	return row[:oa.TruncateColumnCount], nil
+}
+
+// convertFields renames the aliased aggregate columns and truncates
+// the fields. The input fields are not modified.
+func (oa *OrderedAggregate) convertFields(fields []*querypb.Field) []*querypb.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	if oa.TruncateColumnCount != 0 {
+		fields = fields[:oa.TruncateColumnCount]
+	}
+	var out []*querypb.Field
+	for _, aggr := range oa.Aggregates {
+		if aggr.Alias == "" {
+			continue
+		}
+		if out == nil {
+			out = make([]*querypb.Field, len(fields))
+			copy(out, fields)
+		}
+		field := *out[aggr.Col]
+		field.Name = aggr.Alias
+		out[aggr.Col] = &field
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// minMaxSecond returns true if the partial minimum or maximum
+// of row2 must replace the one of row1. Null values are ignored,
+// like MySQL does. The text, enum and set values are compared
+// by their weight strings.
+func minMaxSecond(row1, row2 []sqltypes.Value, aggr AggregateParams) (bool, error) {
+	v1, v2 := row1[aggr.Col], row2[aggr.Col]
+	switch {
+	case v1.IsNull():
+		return !v2.IsNull(), nil
+	case v2.IsNull():
+		return false, nil
+	}
+	if aggr.WeightStringCol != 0 && (sqltypes.IsCollated(v1.Type()) || sqltypes.IsCollated(v2.Type())) {
+		v1, v2 = row1[aggr.WeightStringCol], row2[aggr.WeightStringCol]
+	}
+	cmp, err := sqltypes.NullsafeCompare(v1, v2)
+	if err != nil {
+		return false, err
+	}
+	if aggr.Opcode == AggregateMin {
+		return cmp > 0, nil
+	}
+	return cmp < 0, nil
+}
+
+// average divides the sum by the count. A float sum produces a
+// float. Otherwise, the result is a decimal with the scale of
+// the sum increased by avgScaleIncrement, like MySQL does.
+func average(sum, count sqltypes.Value) (sqltypes.Value, error) {
+	if sum.IsNull() || count.IsNull() {
+		return sqltypes.NULL, nil
+	}
+	n, err := count.ParseInt64()
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if n == 0 {
+		return sqltypes.NULL, nil
+	}
+	if sum.IsFloat() {
+		f, err := sum.ParseFloat64()
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		// The value was produced by FormatFloat.
+		return sqltypes.MakeTrusted(sqltypes.Float64, strconv.AppendFloat(nil, f/float64(n), 'g', -1, 64)), nil
+	}
+	r, ok := new(big.Rat).SetString(sum.String())
+	if !ok {
+		return sqltypes.NULL, fmt.Errorf("could not parse sum: %v", sum)
+	}
+	r.Quo(r, new(big.Rat).SetInt64(n))
+	scale := 0
+	raw := sum.Raw()
+	for i := range raw {
+		if raw[i] == '.' {
+			scale = len(raw) - i - 1
+			break
+		}
+	}
+	// The value is a decimal produced by FloatString.
+	return sqltypes.MakeTrusted(sqltypes.Decimal, []byte(r.FloatString(scale+avgScaleIncrement))), nil
+}
@@ -20,6 +20,7 @@ type VCursor interface {
 	ExecuteRoute(route *Route, joinvars map[string]interface{}) (*sqltypes.Result, error)
 	StreamExecuteRoute(route *Route, joinvars map[string]interface{}, sendReply func(*sqltypes.Result) error) error
 	GetRouteFields(route *Route, joinvars map[string]interface{}) (*sqltypes.Result, error)
+	ExecuteSavepoint(sp *Savepoint) (*sqltypes.Result, error)
 }
 
 // Plan represents the execution strategy for a given query.
@@ -26,21 +26,76 @@ type Route struct {
 	Table      *vindexes.Table
 	Subquery   string
 	Generate   *Generate
+	OrderBy    []OrderbyParams
+	// TruncateColumnCount specifies the number of columns to
+	// return. The rest of the columns are the weight strings
+	// used to merge-sort the results. If 0, no truncation
+	// happens.
+	TruncateColumnCount int
+	Prefix              string
+	Mid                 []string
+	Suffix              string
+	Input               Primitive
+}
+
+// OrderbyParams specifies the parameters for ordering.
+// This is used for merge-sorting scatter queries.
+type OrderbyParams struct {
+	Col int
+	// WeightStringCol is the column of the weight_string of
+	// Col, which is compared instead of the text values: MySQL
+	// sorts them according to their collation. The weight
+	// strings always follow the select expressions, so 0 means
+	// there is none.
+	WeightStringCol int `json:",omitempty"`
+	Desc            bool
 }
 
 // Execute performs a non-streaming exec.
 func (rt *Route) Execute(vcursor VCursor, joinvars map[string]interface{}, wantields bool) (*sqltypes.Result, error) {
-	return vcursor.ExecuteRoute(rt, joinvars)
+	qr, err := vcursor.ExecuteRoute(rt, joinvars)
+	if err != nil {
+		return nil, err
+	}
+	return rt.truncate(qr), nil
 }
 
 // StreamExecute performs a streaming exec.
 func (rt *Route) StreamExecute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool, sendReply func(*sqltypes.Result) error) error {
-	return vcursor.StreamExecuteRoute(rt, joinvars, sendReply)
+	return vcursor.StreamExecuteRoute(rt, joinvars, func(qr *sqltypes.Result) error {
+		return sendReply(rt.truncate(qr))
+	})
 }
 
 // GetFields fetches the field info.
 func (rt *Route) GetFields(vcursor VCursor, joinvars map[string]interface{}) (*sqltypes.Result, error) {
-	return vcursor.GetRouteFields(rt, joinvars)
+	qr, err := vcursor.GetRouteFields(rt, joinvars)
+	if err != nil {
+		return nil, err
+	}
+	return rt.truncate(qr), nil
+}
+
+// truncate returns the result without the weight string columns.
+// The result itself may be shared, and is not modified.
+func (rt *Route) truncate(qr *sqltypes.Result) *sqltypes.Result {
+	if rt.TruncateColumnCount == 0 {
+		return qr
+	}
+	out := *qr
+	if len(qr.Fields) > rt.TruncateColumnCount {
+		out.Fields = qr.Fields[:rt.TruncateColumnCount]
+	}
+	if len(qr.Rows) != 0 {
+		out.Rows = make([][]sqltypes.Value, len(qr.Rows))
+		for i, row := range qr.Rows {
+			if len(row) > rt.TruncateColumnCount {
+				row = row[:rt.TruncateColumnCount]
+			}
+			out.Rows[i] = row
+		}
+	}
+	return &out
 }
 
 // MarshalJSON serializes the Route into a JSON representation.
@@ -54,27 +109,39 @@ func (rt *Route) MarshalJSON() ([]byte, error) {
 		vindexName = rt.Vindex.String()
 	}
 	marshalRoute := struct {
-		Opcode     RouteOpcode         `json:",omitempty"`
-		Keyspace   *vindexes.Keyspace  `json:",omitempty"`
-		Query      string              `json:",omitempty"`
-		FieldQuery string              `json:",omitempty"`
-		Vindex     string              `json:",omitempty"`
-		Values     interface{}         `json:",omitempty"`
-		JoinVars   map[string]struct{} `json:",omitempty"`
-		Table      string              `json:",omitempty"`
-		Subquery   string              `json:",omitempty"`
-		Generate   *Generate           `json:",omitempty"`
+		Opcode              RouteOpcode         `json:",omitempty"`
+		Keyspace            *vindexes.Keyspace  `json:",omitempty"`
+		Query               string              `json:",omitempty"`
+		FieldQuery          string              `json:",omitempty"`
+		Vindex              string              `json:",omitempty"`
+		Values              interface{}         `json:",omitempty"`
+		JoinVars            map[string]struct{} `json:",omitempty"`
+		Table               string              `json:",omitempty"`
+		Subquery            string              `json:",omitempty"`
+		Generate            *Generate           `json:",omitempty"`
+		OrderBy             []OrderbyParams     `json:",omitempty"`
+		TruncateColumnCount int                 `json:",omitempty"`
+		Prefix              string              `json:",omitempty"`
+		Mid                 []string            `json:",omitempty"`
+		Suffix              string              `json:",omitempty"`
+		Input               Primitive           `json:",omitempty"`
 	}{
-		Opcode:     rt.Opcode,
-		Keyspace:   rt.Keyspace,
-		Query:      rt.Query,
-		FieldQuery: rt.FieldQuery,
-		Vindex:     vindexName,
-		Values:     prettyValue(rt.Values),
-		JoinVars:   rt.JoinVars,
-		Table:      tname,
-		Subquery:   rt.Subquery,
-		Generate:   rt.Generate,
+		Opcode:              rt.Opcode,
+		Keyspace:            rt.Keyspace,
+		Query:               rt.Query,
+		FieldQuery:          rt.FieldQuery,
+		Vindex:              vindexName,
+		Values:              prettyValue(rt.Values),
+		JoinVars:            rt.JoinVars,
+		Table:               tname,
+		Subquery:            rt.Subquery,
+		Generate:            rt.Generate,
+		OrderBy:             rt.OrderBy,
+		TruncateColumnCount: rt.TruncateColumnCount,
+		Prefix:              rt.Prefix,
+		Mid:                 rt.Mid,
+		Suffix:              rt.Suffix,
+		Input:               rt.Input,
 	}
 	return json.Marshal(marshalRoute)
 }
@@ -143,6 +210,11 @@ type RouteOpcode int
 // In the case of a join, Joinvars will also be set.
 // These are variables that will be supplied by the
 // Join primitive when it invokes a Route.
+// Select opcodes that can target multiple shards may
+// have OrderBy set. If so, the results from the individual
+// shards are merge-sorted by VTGate, and the weight string
+// columns needed for it are truncated as per
+// TruncateColumnCount.
 // All DMLs must have the Table field set. The
 // ColVindexes in the field will be used to perform
 // various computations and sanity checks.
@@ -167,6 +239,10 @@ const (
 	// SelectScatter is for routing a scatter query
 	// to all shards of a keyspace.
 	SelectScatter
+	// SelectReference is for fetching from a reference
+	// table. Every shard of the keyspace has the same
+	// data. So, the query is sent to any one shard.
+	SelectReference
 	// UpdateUnsharded is for routing an update statement
 	// to an unsharded keyspace.
 	UpdateUnsharded
@@ -174,6 +250,13 @@ const (
 	// to a single shard: Requires: A Vindex, and
 	// a single Value.
 	UpdateEqual
+	// UpdateIN is for routing an update statement
+	// to the shards that match a list of vindex values.
+	// Requires: A Vindex, and a Values list.
+	UpdateIN
+	// UpdateScatter is for routing an update statement
+	// to all shards of a keyspace.
+	UpdateScatter
 	// DeleteUnsharded is for routing a delete statement
 	// to an unsharded keyspace.
 	DeleteUnsharded
@@ -182,14 +265,36 @@ const (
 	// Value, and a Subquery, which will be used to
 	// determine if lookup rows need to be deleted.
 	DeleteEqual
+	// DeleteIN is for routing a delete statement
+	// to the shards that match a list of vindex values.
+	// Requires: A Vindex, a Values list, and a Subquery
+	// if the table has owned vindexes. The last column
+	// of the Subquery is the primary vindex column, which
+	// is used to compute the keyspace id of each row.
+	DeleteIN
+	// DeleteScatter is for routing a delete statement
+	// to all shards of a keyspace. Requires a Subquery
+	// if the table has owned vindexes, like DeleteIN.
+	DeleteScatter
 	// InsertUnsharded is for routing an insert statement
 	// to an unsharded keyspace.
 	InsertUnsharded
-	// InsertUnsharded is for routing an insert statement
-	// to a single shard. Requires: A list of Values, one
-	// for each ColVindex. If the table has an Autoinc column,
-	// A Generate subplan must be created.
+	// InsertSharded is for routing an insert statement
+	// to one or more shards. Requires: A list of Values, one
+	// per row, each with one value for each ColVindex. If the
+	// table has an Autoinc column, A Generate subplan must be
+	// created. The rows are grouped by shard, and each shard
+	// is sent the query built from the Prefix, the Mid of each
+	// of its rows separated by commas, and the Suffix.
 	InsertSharded
+	// InsertSelect is for inserting the rows returned by
+	// the Input primitive into a sharded keyspace. The rows
+	// are grouped by shard like InsertSharded. Requires: A
+	// Prefix and a Suffix, and a list of Values that contains
+	// the column number of each ColVindex in the rows. If the
+	// table has an Autoinc column, the Value of the Generate
+	// subplan is its column number.
+	InsertSelect
 	// NumCodes is the total number of opcodes for routes.
 	NumCodes
 )
@@ -202,12 +307,18 @@ var routeName = [NumCodes]string{
 	"SelectEqual",
 	"SelectIN",
 	"SelectScatter",
+	"SelectReference",
 	"UpdateUnsharded",
 	"UpdateEqual",
+	"UpdateIN",
+	"UpdateScatter",
 	"DeleteUnsharded",
 	"DeleteEqual",
+	"DeleteIN",
+	"DeleteScatter",
 	"InsertUnsharded",
 	"InsertSharded",
+	"InsertSelect",
 }
 
 func (code RouteOpcode) String() string {
@@ -0,0 +1,40 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"errors"
+
+	"github.com/youtube/vitess/go/sqltypes"
+)
+
+// Savepoint is a primitive that sets, rolls back to, or releases
+// a savepoint in the current transaction. The statement is sent
+// to all the shards participating in the transaction, and the
+// savepoints are recorded in the session, so that they can also be
+// set on the shards which join the transaction later.
+type Savepoint struct {
+	// Action is one of the sqlparser savepoint actions:
+	// savepoint, rollback to, or release.
+	Action string
+	Name   string
+	// Query is the statement to send to the shards.
+	Query string
+}
+
+// Execute performs a non-streaming exec.
+func (sp *Savepoint) Execute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool) (*sqltypes.Result, error) {
+	return vcursor.ExecuteSavepoint(sp)
+}
+
+// StreamExecute performs a streaming exec.
+func (sp *Savepoint) StreamExecute(vcursor VCursor, joinvars map[string]interface{}, wantfields bool, sendReply func(*sqltypes.Result) error) error {
+	return errors.New("savepoint statements cannot be streamed")
+}
+
+// GetFields fetches the field info.
+func (sp *Savepoint) GetFields(vcursor VCursor, joinvars map[string]interface{}) (*sqltypes.Result, error) {
+	return &sqltypes.Result{}, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/vterrors"
 	"github.com/youtube/vitess/go/vt/vtgate/masterbuffer"
+	"github.com/youtube/vitess/go/vt/vtgate/schematracker"
 
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
 	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
@@ -68,7 +69,7 @@ type discoveryGateway struct {
 func createDiscoveryGateway(hc discovery.HealthCheck, topoServer topo.Server, serv topo.SrvTopoServer, cell string, retryCount int) Gateway {
 	dg := &discoveryGateway{
 		hc:                hc,
-		tsc:               discovery.NewTabletStatsCache(hc, cell),
+		tsc:               discovery.NewTabletStatsCacheDoNotSetListener(cell),
 		topoServer:        topoServer,
 		srvTopoServer:     serv,
 		localCell:         cell,
@@ -76,6 +77,9 @@ func createDiscoveryGateway(hc discovery.HealthCheck, topoServer topo.Server, se
 		tabletsWatchers:   make([]*discovery.TopologyWatcher, 0, 1),
 		statusAggregators: make(map[string]*TabletStatusAggregator),
 	}
+	// We chain the TabletStatsCache and the master buffer as
+	// listeners, both need the down events.
+	hc.SetListener(dg, true /* sendDownEvents */)
 	log.Infof("loading tablets for cells: %v", *cellsToWatch)
 	for _, c := range strings.Split(*cellsToWatch, ",") {
 		if c == "" {
@@ -96,6 +100,13 @@ func createDiscoveryGateway(hc discovery.HealthCheck, topoServer topo.Server, se
 	return dg
 }
 
+// StatsUpdate is part of the discovery.HealthCheckStatsListener interface.
+func (dg *discoveryGateway) StatsUpdate(ts *discovery.TabletStats) {
+	dg.tsc.StatsUpdate(ts)
+	masterbuffer.StatsUpdate(ts)
+	schematracker.StatsUpdate(ts)
+}
+
 // WaitForTablets is part of the gateway.Gateway interface.
 func (dg *discoveryGateway) WaitForTablets(ctx context.Context, tabletTypesToWait []topodatapb.TabletType) error {
 	// Skip waiting for tablets if we are not told to do so.
@@ -371,6 +382,17 @@ func (dg *discoveryGateway) withRetry(ctx context.Context, target *querypb.Targe
 	invalidTablets := make(map[string]bool)
 
 	for i := 0; i < dg.retryCount+1; i++ {
+		// Potentially buffer this request, while the master fails over.
+		buffered, bufferErr := masterbuffer.Wait(ctx, target, inTransaction)
+		if bufferErr != nil {
+			return bufferErr
+		}
+		if buffered {
+			// The master changed, the tablets we tried before
+			// may be good again.
+			invalidTablets = make(map[string]bool)
+		}
+
 		tablets := dg.tsc.GetHealthyTabletStats(target.Keyspace, target.Shard, target.TabletType)
 		if len(tablets) == 0 {
 			// fail fast if there is no tablet
@@ -404,11 +426,6 @@ func (dg *discoveryGateway) withRetry(ctx context.Context, target *querypb.Targe
 			continue
 		}
 
-		// Potentially buffer this request.
-		if bufferErr := masterbuffer.FakeBuffer(target, inTransaction, i); bufferErr != nil {
-			return bufferErr
-		}
-
 		err = action(conn, ts.Target)
 		if dg.canRetry(ctx, err, inTransaction, isStreaming) {
 			invalidTablets[ts.Key] = true
@@ -1,9 +1,11 @@
 package gateway
 
 import (
+	"flag"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -109,6 +111,44 @@ func TestDiscoveryGatewayGetTablets(t *testing.T) {
 	}
 }
 
+func TestDiscoveryGatewayBufferFailover(t *testing.T) {
+	flag.Set("enable_master_buffer", "true")
+	defer flag.Set("enable_master_buffer", "false")
+	keyspace := "ks"
+	shard := "0"
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: topodatapb.TabletType_MASTER,
+	}
+	hc := discovery.NewFakeHealthCheck()
+	dg := createDiscoveryGateway(hc, topo.Server{}, nil, "cell", 2).(*discoveryGateway)
+
+	// The master stops serving, as during a PlannedReparentShard.
+	oldMaster := hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, shard, topodatapb.TabletType_MASTER, true, 10, nil)
+	hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, shard, topodatapb.TabletType_MASTER, false, 10, nil)
+
+	done := make(chan error)
+	go func() {
+		_, err := dg.Execute(context.Background(), target, "query", nil, 0, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("Execute was not buffered: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The request goes to the new master when it is serving.
+	newMaster := hc.AddTestTablet("cell", "2.2.2.2", 1001, keyspace, shard, topodatapb.TabletType_MASTER, true, 20, nil)
+	if err := <-done; err != nil {
+		t.Errorf("Execute failed: %v", err)
+	}
+	if oldMaster.ExecCount.Get() != 0 || newMaster.ExecCount.Get() != 1 {
+		t.Errorf("ExecCount: old master %v, new master %v, want 0 and 1", oldMaster.ExecCount.Get(), newMaster.ExecCount.Get())
+	}
+}
+
 func testDiscoveryGatewayGeneric(t *testing.T, streaming bool, f func(dg Gateway, target *querypb.Target) error) {
 	keyspace := "ks"
 	shard := "0"
@@ -27,10 +27,11 @@ import (
 )
 
 var (
-	cert = flag.String("vtgate_grpc_cert", "", "the cert to use to connect")
-	key  = flag.String("vtgate_grpc_key", "", "the key to use to connect")
-	ca   = flag.String("vtgate_grpc_ca", "", "the server ca to use to validate servers when connecting")
-	name = flag.String("vtgate_grpc_server_name", "", "the server name to use to validate server certificate")
+	cert  = flag.String("vtgate_grpc_cert", "", "the cert to use to connect")
+	key   = flag.String("vtgate_grpc_key", "", "the key to use to connect")
+	ca    = flag.String("vtgate_grpc_ca", "", "the server ca to use to validate servers when connecting")
+	name  = flag.String("vtgate_grpc_server_name", "", "the server name to use to validate server certificate")
+	creds = flag.String("vtgate_grpc_auth_static_client_creds", "", "JSON file of the username and password to send to a vtgate using the static gRPC authentication plugin")
 )
 
 func init() {
@@ -47,7 +48,12 @@ func dial(ctx context.Context, addr string, timeout time.Duration) (vtgateconn.I
 	if err != nil {
 		return nil, err
 	}
-	cc, err := grpc.Dial(addr, opt, grpc.WithBlock(), grpc.WithTimeout(timeout))
+	authOpts, err := grpcutils.ClientAuthDialOptions(*creds)
+	if err != nil {
+		return nil, err
+	}
+	opts := append([]grpc.DialOption{opt, grpcutils.ClientTraceDialOption(), grpc.WithBlock(), grpc.WithTimeout(timeout)}, authOpts...)
+	cc, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +33,7 @@ const (
 )
 
 var (
-	useEffective = flag.Bool("grpc_use_effective_callerid", false, "If set, and SSL is not used, will set the immediate caller id from the effective caller id's principal.")
+	useEffective = flag.Bool("grpc_use_effective_callerid", false, "If set, and neither SSL nor the gRPC authentication is used, will set the immediate caller id from the effective caller id's principal.")
 )
 
 // VTGate is the public structure that is exported via gRPC
@@ -70,13 +70,18 @@ func immediateCallerID(ctx context.Context) string {
 
 // withCallerIDContext creates a context that extracts what we need
 // from the incoming call and can be forwarded for use when talking to vttablet.
+// If the call was authenticated by the gRPC authentication plugin, the
+// authenticated user is the immediate caller id.
 func withCallerIDContext(ctx context.Context, effectiveCallerID *vtrpcpb.CallerID) context.Context {
-	immediate := immediateCallerID(ctx)
-	if immediate == "" && *useEffective && effectiveCallerID != nil {
-		immediate = effectiveCallerID.Principal
-	}
-	if immediate == "" {
-		immediate = unsecureClient
+	immediate, authenticated := servenv.AuthenticatedUser(ctx)
+	if !authenticated {
+		immediate = immediateCallerID(ctx)
+		if immediate == "" && *useEffective && effectiveCallerID != nil {
+			immediate = effectiveCallerID.Principal
+		}
+		if immediate == "" {
+			immediate = unsecureClient
+		}
 	}
 	return callerid.NewContext(callinfo.GRPCCallInfo(ctx),
 		effectiveCallerID,
@@ -0,0 +1,109 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package masterbuffer
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	log "github.com/golang/glog"
+
+	"github.com/youtube/vitess/go/acl"
+	"github.com/youtube/vitess/go/vt/logz"
+)
+
+const bufferzHeaderHTML = `
+	<thead>
+		<tr>
+			<th>Keyspace</th>
+			<th>Shard</th>
+			<th>Master</th>
+			<th>State</th>
+			<th>Buffered Requests</th>
+			<th>Failover Duration</th>
+			<th>Last Failover End</th>
+		</tr>
+	</thead>
+`
+
+const bufferzRowHTML = `
+		<tr class="{{if .Buffering}}high{{else}}low{{end}}">
+			<td>{{.Keyspace}}</td>
+			<td>{{.Shard}}</td>
+			<td>{{.MasterAlias}}</td>
+			<td>{{if .Buffering}}BUFFERING{{else}}IDLE{{end}}</td>
+			<td>{{.Buffered}}</td>
+			<td>{{.FailoverDuration}}</td>
+			<td>{{.LastFailoverEnd}}</td>
+		</tr>
+`
+
+var bufferzRowTemplate = template.Must(template.New("bufferz").Parse(bufferzRowHTML))
+
+// shardStatus is a snapshot of a shardBuffer for the status page.
+type shardStatus struct {
+	Keyspace    string
+	Shard       string
+	MasterAlias string
+	Buffering   bool
+	Buffered    int
+	// FailoverDuration is the duration of the current failover
+	// if Buffering is true, of the last one otherwise.
+	FailoverDuration time.Duration
+	LastFailoverEnd  string
+}
+
+// status returns the state of all the shards, sorted by keyspace
+// and shard.
+func (b *buffer) status() []shardStatus {
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.shards))
+	for key := range b.shards {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]shardStatus, 0, len(keys))
+	for _, key := range keys {
+		sb := b.shards[key]
+		ss := shardStatus{
+			Keyspace:         sb.keyspace,
+			Shard:            sb.shard,
+			MasterAlias:      sb.masterAlias,
+			Buffering:        sb.buffering,
+			Buffered:         sb.buffered,
+			FailoverDuration: sb.lastFailoverDuration,
+			LastFailoverEnd:  sb.lastFailoverEnd,
+		}
+		if sb.buffering {
+			ss.FailoverDuration = time.Now().Sub(sb.failoverStart)
+		}
+		result = append(result, ss)
+	}
+	b.mu.Unlock()
+	return result
+}
+
+func init() {
+	http.HandleFunc("/debug/buffer", func(w http.ResponseWriter, r *http.Request) {
+		bufferzHandler(w, r, defaultBuffer)
+	})
+}
+
+func bufferzHandler(w http.ResponseWriter, r *http.Request, b *buffer) {
+	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+	logz.StartHTMLTable(w)
+	defer logz.EndHTMLTable(w)
+	w.Write([]byte(bufferzHeaderHTML))
+	for _, ss := range b.status() {
+		if err := bufferzRowTemplate.Execute(w, ss); err != nil {
+			log.Errorf("bufferz: couldn't execute template: %v", err)
+		}
+	}
+}
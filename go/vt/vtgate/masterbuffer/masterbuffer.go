@@ -3,25 +3,40 @@
 // license that can be found in the LICENSE file.
 
 /*
-Package masterbuffer contains experimental logic to buffer master requests in VTGate.
-Only statements outside of transactinos will be buffered (including the initial Begin
-to start a transaction).
+Package masterbuffer contains the logic to buffer master requests in VTGate
+during failovers. Only statements outside of transactions will be buffered
+(including the initial Begin to start a transaction).
 
-The reason why it might be useful to buffer master requests is during failovers:
+The reason why it is useful to buffer master requests is during failovers:
 the master vttablet can become unavailable for a few seconds. Upstream clients
 (e.g., web workers) might not retry on failures, and instead may prefer for VTGate to wait for
-a few seconds for the failover to complete. Thiis will block upstream callers for that time,
+a few seconds for the failover to complete. This will block upstream callers for that time,
 but will not return transient errors during the buffering time.
+
+The failovers are detected from the health check stream of the master
+tablets, see StatsUpdate. A shard starts buffering when its current master
+stops serving, or goes away. This is also what happens during a
+PlannedReparentShard, when the old master is demoted. The shard stops
+buffering, and releases all its buffered requests, as soon as a master
+with the same or a more recent externally reparented timestamp reports
+serving.
 */
 package masterbuffer
 
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"sync"
 	"time"
 
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/flagutil"
 	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/vt/discovery"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
 	"github.com/youtube/vitess/go/vt/vterrors"
 
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
@@ -30,21 +45,58 @@ import (
 )
 
 var (
-	enableFakeMasterBuffer = flag.Bool("enable_fake_master_buffer", false, "Enable fake master buffering.")
-	bufferKeyspace         = flag.String("buffer_keyspace", "", "The name of the keyspace to buffer master requests on.")
-	bufferShard            = flag.String("buffer_shard", "", "The name of the shard to buffer master requests on.")
-	maxBufferSize          = flag.Int("max_buffer_size", 10, "The maximum number of master requests to buffer at a time.")
-	fakeBufferDelay        = flag.Duration("fake_buffer_delay", 1*time.Second, "The amount of time that we should delay all master requests for, to fake a buffer.")
-
-	bufferedRequestsAttempted  = stats.NewInt("BufferedRequestsAttempted")
-	bufferedRequestsSuccessful = stats.NewInt("BufferedRequestsSuccessful")
-	// Use this lock when adding to the number of currently buffered requests.
-	bufferMu         sync.Mutex
-	bufferedRequests = stats.NewInt("BufferedRequests")
+	enableMasterBuffer   = flag.Bool("enable_master_buffer", false, "Enable buffering of master requests during failovers.")
+	bufferKeyspaceShards flagutil.StringListValue
+	maxBufferSize        = flag.Int("max_buffer_size", 10, "The maximum number of master requests to buffer at a time, per shard.")
+	bufferWindow         = flag.Duration("buffer_window", 10*time.Second, "The maximum time a master request is buffered. After that, it is sent to the tablets as if the failover was over.")
+	maxFailoverDuration  = flag.Duration("buffer_max_failover_duration", 20*time.Second, "Stop buffering if a failover takes longer than this.")
+
+	statsKeys = []string{"Keyspace", "ShardName"}
+
+	failoversStarted  = stats.NewMultiCounters("BufferFailoversStarted", statsKeys)
+	failoverDurations = stats.NewMultiTimings("BufferFailoverDurations", statsKeys)
+	requestsBuffered  = stats.NewMultiCounters("BufferRequestsBuffered", statsKeys)
+	requestsDrained   = stats.NewMultiCounters("BufferRequestsDrained", statsKeys)
+	requestsEvicted   = stats.NewMultiCounters("BufferRequestsEvicted", statsKeys)
+	requestsFull      = stats.NewMultiCounters("BufferRequestsFull", statsKeys)
 )
 
-// timeSleep can be mocked out in unit tests
-var timeSleep = time.Sleep
+func init() {
+	flag.Var(&bufferKeyspaceShards, "buffer_keyspace_shards", "If set, only the master requests of this comma-separated list of keyspace/shard are buffered. Otherwise, all shards are.")
+
+	// The flags of the former fake buffer are still accepted, so
+	// existing command lines keep working, but they do nothing.
+	flag.Var(&deprecatedFlag{name: "enable_fake_master_buffer", isBool: true, replacement: "enable_master_buffer"}, "enable_fake_master_buffer", "This flag is unused and deprecated, use -enable_master_buffer instead. It will be removed entirely in a future release.")
+	flag.Var(&deprecatedFlag{name: "buffer_keyspace", replacement: "buffer_keyspace_shards"}, "buffer_keyspace", "This flag is unused and deprecated, use -buffer_keyspace_shards instead. It will be removed entirely in a future release.")
+	flag.Var(&deprecatedFlag{name: "buffer_shard", replacement: "buffer_keyspace_shards"}, "buffer_shard", "This flag is unused and deprecated, use -buffer_keyspace_shards instead. It will be removed entirely in a future release.")
+	flag.Var(&deprecatedFlag{name: "fake_buffer_delay", replacement: "buffer_window"}, "fake_buffer_delay", "This flag is unused and deprecated, use -buffer_window instead. It will be removed entirely in a future release.")
+}
+
+// deprecatedFlag is a flag.Value that ignores its value, and logs a
+// warning when it is set.
+type deprecatedFlag struct {
+	name        string
+	isBool      bool
+	replacement string
+	value       string
+}
+
+// String is part of the flag.Value interface.
+func (f *deprecatedFlag) String() string {
+	return f.value
+}
+
+// Set is part of the flag.Value interface.
+func (f *deprecatedFlag) Set(value string) error {
+	f.value = value
+	log.Warningf("flag -%v is deprecated and ignored, use -%v instead", f.name, f.replacement)
+	return nil
+}
+
+// IsBoolFlag allows a boolean flag to be set without a value.
+func (f *deprecatedFlag) IsBoolFlag() bool {
+	return f.isBool
+}
 
 // errBufferFull is the error returned a buffer request is rejected because the buffer is full.
 var errBufferFull = vterrors.FromError(
@@ -52,36 +104,194 @@ var errBufferFull = vterrors.FromError(
 	errors.New("master request buffer full, rejecting request"),
 )
 
-// FakeBuffer will pretend to buffer master requests in VTGate.
-// Requests *will NOT actually be buffered*, they will just be delayed.
-// This can be useful to understand what the impact of master request buffering will be
-// on upstream callers. Once the impact is measured, it can be used to tweak parameter values
-// for the best behavior.
-// FakeBuffer should be called before a potential VtTablet Begin, otherwise it will increase transaction times.
-func FakeBuffer(target *querypb.Target, inTransaction bool, attemptNumber int) error {
-	if !*enableFakeMasterBuffer {
-		return nil
+// defaultBuffer is the buffer used by vtgate.
+var defaultBuffer = newBuffer()
+
+// Wait buffers a master request while its shard is failing over.
+// It returns true if the request was buffered, in which case the
+// caller should pick the master again. Wait should be called before
+// a potential VtTablet Begin, otherwise it will increase
+// transaction times.
+func Wait(ctx context.Context, target *querypb.Target, inTransaction bool) (bool, error) {
+	return defaultBuffer.wait(ctx, target, inTransaction)
+}
+
+// StatsUpdate detects the failovers from the health check stream.
+// It must be called with the down events of the HealthCheck too,
+// see discovery.HealthCheck.SetListener.
+func StatsUpdate(ts *discovery.TabletStats) {
+	defaultBuffer.statsUpdate(ts)
+}
+
+// buffer keeps track of the failovers of all shards.
+type buffer struct {
+	// mu protects shards, and all the shardBuffer objects in it.
+	mu sync.Mutex
+	// shards is indexed by keyspace/shard.
+	shards map[string]*shardBuffer
+}
+
+// shardBuffer is the buffering state of a shard.
+type shardBuffer struct {
+	keyspace string
+	shard    string
+
+	// masterKey and masterAlias identify the last master that
+	// reported serving, and externallyReparented is its
+	// TabletExternallyReparentedTimestamp.
+	masterKey            string
+	masterAlias          string
+	externallyReparented int64
+
+	// buffering is true during a failover. failoverEnd is closed
+	// when it ends, to release the buffered requests.
+	buffering     bool
+	failoverStart time.Time
+	failoverEnd   chan struct{}
+	timer         *time.Timer
+	// buffered is the number of requests currently buffered.
+	buffered int
+
+	// lastFailoverDuration and lastFailoverEnd describe the last
+	// failover, for the status page.
+	lastFailoverDuration time.Duration
+	lastFailoverEnd      string
+}
+
+func newBuffer() *buffer {
+	return &buffer{
+		shards: make(map[string]*shardBuffer),
+	}
+}
+
+// shouldBuffer returns true if the requests of that shard can be
+// buffered.
+func shouldBuffer(keyspace, shard string) bool {
+	if !*enableMasterBuffer {
+		return false
 	}
-	// Don't buffer non-master traffic, requests that are inside transactions, or retries.
-	if target.TabletType != topodatapb.TabletType_MASTER || inTransaction || attemptNumber != 0 {
-		return nil
+	if len(bufferKeyspaceShards) == 0 {
+		return true
 	}
-	if target.Keyspace != *bufferKeyspace || target.Shard != *bufferShard {
-		return nil
+	name := keyspace + "/" + shard
+	for _, ks := range bufferKeyspaceShards {
+		if ks == name {
+			return true
+		}
 	}
-	bufferedRequestsAttempted.Add(1)
+	return false
+}
 
-	bufferMu.Lock()
-	if int(bufferedRequests.Get()) >= *maxBufferSize {
-		bufferMu.Unlock()
-		return errBufferFull
+func (b *buffer) wait(ctx context.Context, target *querypb.Target, inTransaction bool) (bool, error) {
+	// Don't buffer non-master traffic, or requests that are inside transactions.
+	if target.TabletType != topodatapb.TabletType_MASTER || inTransaction {
+		return false, nil
+	}
+	if !shouldBuffer(target.Keyspace, target.Shard) {
+		return false, nil
 	}
-	bufferedRequests.Add(1)
-	bufferMu.Unlock()
+	statsKey := []string{target.Keyspace, target.Shard}
 
-	defer bufferedRequestsSuccessful.Add(1)
-	timeSleep(*fakeBufferDelay)
-	// Don't need to lock for this, as there's no race when decrementing the count
-	bufferedRequests.Add(-1)
-	return nil
+	b.mu.Lock()
+	sb, ok := b.shards[target.Keyspace+"/"+target.Shard]
+	if !ok || !sb.buffering {
+		b.mu.Unlock()
+		return false, nil
+	}
+	if sb.buffered >= *maxBufferSize {
+		b.mu.Unlock()
+		requestsFull.Add(statsKey, 1)
+		return false, errBufferFull
+	}
+	sb.buffered++
+	failoverEnd := sb.failoverEnd
+	b.mu.Unlock()
+	requestsBuffered.Add(statsKey, 1)
+
+	defer func() {
+		b.mu.Lock()
+		sb.buffered--
+		b.mu.Unlock()
+	}()
+
+	window := time.NewTimer(*bufferWindow)
+	defer window.Stop()
+	select {
+	case <-failoverEnd:
+		requestsDrained.Add(statsKey, 1)
+		return true, nil
+	case <-window.C:
+		// The failover is taking too long for this request, let
+		// it try its luck with the tablets.
+		requestsEvicted.Add(statsKey, 1)
+		return true, nil
+	case <-ctx.Done():
+		return true, vterrors.FromError(vtrpcpb.ErrorCode_DEADLINE_EXCEEDED, fmt.Errorf("context expired while the request was buffered: %v", ctx.Err()))
+	}
+}
+
+func (b *buffer) statsUpdate(ts *discovery.TabletStats) {
+	if ts.Target == nil || ts.Target.TabletType != topodatapb.TabletType_MASTER {
+		return
+	}
+	if !shouldBuffer(ts.Target.Keyspace, ts.Target.Shard) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := ts.Target.Keyspace + "/" + ts.Target.Shard
+	sb, ok := b.shards[key]
+	if !ok {
+		sb = &shardBuffer{
+			keyspace: ts.Target.Keyspace,
+			shard:    ts.Target.Shard,
+		}
+		b.shards[key] = sb
+	}
+
+	if ts.Up && ts.Serving && ts.LastError == nil {
+		if ts.TabletExternallyReparentedTimestamp < sb.externallyReparented {
+			// This is an old master that still thinks it
+			// is the master, ignore it.
+			return
+		}
+		sb.masterKey = ts.Key
+		sb.masterAlias = topoproto.TabletAliasString(ts.Tablet.Alias)
+		sb.externallyReparented = ts.TabletExternallyReparentedTimestamp
+		if sb.buffering {
+			b.stopBufferingLocked(sb, fmt.Sprintf("master %v is serving", sb.masterAlias))
+		}
+		return
+	}
+
+	// The master is not serving, or went away: a failover started.
+	if ts.Key == sb.masterKey && !sb.buffering {
+		b.startBufferingLocked(sb)
+	}
+}
+
+func (b *buffer) startBufferingLocked(sb *shardBuffer) {
+	failoversStarted.Add([]string{sb.keyspace, sb.shard}, 1)
+	sb.buffering = true
+	sb.failoverStart = time.Now()
+	sb.failoverEnd = make(chan struct{})
+	failoverEnd := sb.failoverEnd
+	sb.timer = time.AfterFunc(*maxFailoverDuration, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// Make sure this is still the same failover.
+		if sb.buffering && sb.failoverEnd == failoverEnd {
+			b.stopBufferingLocked(sb, "max failover duration exceeded")
+		}
+	})
+}
+
+func (b *buffer) stopBufferingLocked(sb *shardBuffer, reason string) {
+	sb.timer.Stop()
+	close(sb.failoverEnd)
+	sb.buffering = false
+	sb.lastFailoverDuration = time.Now().Sub(sb.failoverStart)
+	sb.lastFailoverEnd = reason
+	failoverDurations.Add([]string{sb.keyspace, sb.shard}, sb.lastFailoverDuration)
 }
@@ -5,256 +5,277 @@
 package masterbuffer
 
 import (
-	"sync"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/vt/discovery"
+
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
 	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
 )
 
-// fakeSleepController is used to control a fake sleepFunc
-type fakeSleepController struct {
-	called bool
-	block  bool
-	// block until the done channel if closed, if configured to do so.
-	done chan struct{}
-	// will close this channel when blocked
-	blocked chan struct{}
+const (
+	testKeyspace = "ks"
+	testShard    = "-80"
+)
+
+var masterTarget = &querypb.Target{
+	Keyspace:   testKeyspace,
+	Shard:      testShard,
+	TabletType: topodatapb.TabletType_MASTER,
 }
 
-type sleepFunc func(d time.Duration)
+// setFlags enables the buffer with the provided flags, and returns
+// a function to restore them.
+func setFlags(size int, window, failover time.Duration) func() {
+	oldEnable, oldSize, oldWindow, oldFailover := *enableMasterBuffer, *maxBufferSize, *bufferWindow, *maxFailoverDuration
+	*enableMasterBuffer, *maxBufferSize, *bufferWindow, *maxFailoverDuration = true, size, window, failover
+	return func() {
+		*enableMasterBuffer, *maxBufferSize, *bufferWindow, *maxFailoverDuration = oldEnable, oldSize, oldWindow, oldFailover
+		bufferKeyspaceShards = nil
+	}
+}
 
-// createFakeSleep creates a function that can be called to fake sleeping.
-// The created fake is managed by the passed in fakeSleepController.
-func createFakeSleep(c *fakeSleepController) sleepFunc {
-	return func(d time.Duration) {
-		c.called = true
-		if !c.block {
-			return
+// masterStats returns the health check stats of a master tablet.
+func masterStats(uid uint32, serving bool, externallyReparented int64) *discovery.TabletStats {
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "cell",
+			Uid:  uid,
+		},
+		Hostname: fmt.Sprintf("host%v", uid),
+	}
+	return &discovery.TabletStats{
+		Key:                                 discovery.TabletToMapKey(tablet),
+		Tablet:                              tablet,
+		Target:                              masterTarget,
+		Up:                                  true,
+		Serving:                             serving,
+		TabletExternallyReparentedTimestamp: externallyReparented,
+	}
+}
+
+type waitResult struct {
+	buffered bool
+	err      error
+}
+
+// startWait calls wait in the background.
+func startWait(ctx context.Context, b *buffer) chan waitResult {
+	result := make(chan waitResult, 1)
+	go func() {
+		buffered, err := b.wait(ctx, masterTarget, false)
+		result <- waitResult{buffered, err}
+	}()
+	return result
+}
+
+// waitForBuffered waits until the shard has that many buffered requests.
+func waitForBuffered(t *testing.T, b *buffer, want int) {
+	for i := 0; i < 100; i++ {
+		b.mu.Lock()
+		got := 0
+		if sb, ok := b.shards[testKeyspace+"/"+testShard]; ok {
+			got = sb.buffered
 		}
-		close(c.blocked)
-		select {
-		case <-c.done:
+		b.mu.Unlock()
+		if got == want {
 			return
 		}
+		time.Sleep(10 * time.Millisecond)
 	}
+	t.Fatalf("timed out waiting for %v buffered requests", want)
 }
 
-func TestFakeBuffer(t *testing.T) {
-	unbufferedKeyspace := "ukeyspace"
-	unbufferedShard := "80-"
-	bufferedKeyspace := "bkeyspace"
-	bufferedShard := "-80"
+func checkWaitResult(t *testing.T, result chan waitResult, wantBuffered bool, wantErr string) {
+	select {
+	case r := <-result:
+		if r.buffered != wantBuffered {
+			t.Errorf("wait() buffered = %v, want %v", r.buffered, wantBuffered)
+		}
+		if wantErr == "" && r.err != nil || wantErr != "" && (r.err == nil || !strings.Contains(r.err.Error(), wantErr)) {
+			t.Errorf("wait() = %v, want %v", r.err, wantErr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("wait() did not return")
+	}
+}
 
-	*bufferKeyspace = bufferedKeyspace
-	*bufferShard = bufferedShard
+func TestBufferNotBuffered(t *testing.T) {
+	defer setFlags(10, time.Minute, time.Minute)()
+	b := newBuffer()
+	b.statsUpdate(masterStats(1, true, 10))
+	b.statsUpdate(masterStats(1, false, 10))
 
 	for _, test := range []struct {
-		desc             string
-		enableFakeBuffer bool
-		keyspace         string
-		shard            string
-		tabletType       topodatapb.TabletType
-		inTransaction    bool
-		attemptNumber    int
-		bufferedRequests int
-		// was this request buffered?
-		wantCalled bool
-		// expected value of BufferedRequestsAttempts
-		wantAttempted int
-		wantErr       error
-	}{
-		{
-			desc:             "enableFakeBuffer=False",
-			enableFakeBuffer: false,
-		},
-		{
-			desc:             "tabletType=REPLICA",
-			enableFakeBuffer: true,
-			tabletType:       topodatapb.TabletType_REPLICA,
-		},
-		{
-			desc:             "inTransaction=True",
-			enableFakeBuffer: true,
-			inTransaction:    true,
-		},
-		{
-			desc:             "attemptNumber != 0",
-			enableFakeBuffer: true,
-			attemptNumber:    1,
-		},
-		{
-			desc:             "unbuffered keyspace",
-			enableFakeBuffer: true,
-			keyspace:         unbufferedKeyspace,
-			shard:            bufferedShard,
-		},
-		{
-			desc:             "unbuffered shard",
-			enableFakeBuffer: true,
-			keyspace:         bufferedKeyspace,
-			shard:            unbufferedShard,
-		},
-		{
-			desc:             "buffer full",
-			enableFakeBuffer: true,
-			keyspace:         bufferedKeyspace,
-			shard:            bufferedShard,
-			bufferedRequests: *maxBufferSize,
-			// When the buffer is full, bufferedRequestsAttempted should still be incremented
-			wantAttempted: 1,
-			wantErr:       errBufferFull,
-		},
-		{
-			desc:             "buffered successful",
-			enableFakeBuffer: true,
-			keyspace:         bufferedKeyspace,
-			shard:            bufferedShard,
-			wantCalled:       true,
-			wantAttempted:    1,
-		},
-	} {
-		controller := &fakeSleepController{}
-		timeSleep = createFakeSleep(controller)
-		// reset counters
-		bufferedRequestsAttempted.Set(0)
-		bufferedRequestsSuccessful.Set(0)
-		bufferedRequests.Set(int64(test.bufferedRequests))
-
-		*enableFakeMasterBuffer = test.enableFakeBuffer
-
-		var tabletType topodatapb.TabletType
-		// Default to MASTER tablet type.
-		if test.tabletType == topodatapb.TabletType_UNKNOWN {
+		desc          string
+		disabled      bool
+		keyspaceShard string
+		tabletType    topodatapb.TabletType
+		inTransaction bool
+	}{{
+		desc:     "disabled",
+		disabled: true,
+	}, {
+		desc:       "tabletType=REPLICA",
+		tabletType: topodatapb.TabletType_REPLICA,
+	}, {
+		desc:          "inTransaction=True",
+		inTransaction: true,
+	}, {
+		desc:          "unbuffered shard",
+		keyspaceShard: "other/0",
+	}} {
+		*enableMasterBuffer = !test.disabled
+		bufferKeyspaceShards = nil
+		if test.keyspaceShard != "" {
+			bufferKeyspaceShards = []string{test.keyspaceShard}
+		}
+		tabletType := test.tabletType
+		if tabletType == topodatapb.TabletType_UNKNOWN {
 			tabletType = topodatapb.TabletType_MASTER
 		}
-
 		target := &querypb.Target{
-			Keyspace:   test.keyspace,
-			Shard:      test.shard,
+			Keyspace:   testKeyspace,
+			Shard:      testShard,
 			TabletType: tabletType,
 		}
-		gotErr := FakeBuffer(target, test.inTransaction, test.attemptNumber)
-
-		if gotErr != test.wantErr {
-			t.Errorf("With %v, FakeBuffer() => %v; want: %v", test.desc, gotErr, test.wantErr)
-		}
-
-		if controller.called != test.wantCalled {
-			t.Errorf("With %v, FakeBuffer() => timeSleep.called: %v; want: %v",
-				test.desc, controller.called, test.wantCalled)
-		}
-
-		if bufferedRequestsAttempted.Get() != int64(test.wantAttempted) {
-			t.Errorf("With %v, FakeBuffer() => bufferedRequestsAttempted got: %v; want: %v",
-				test.desc, bufferedRequestsAttempted.Get(), test.wantAttempted)
-		}
-
-		if (!test.wantCalled && (bufferedRequestsSuccessful.Get() == 1)) ||
-			(test.wantCalled && (bufferedRequestsSuccessful.Get() != 1)) {
-			t.Errorf("With %v, FakeBuffer() => bufferedRequestsSuccessful got: %v; want: 1",
-				test.desc, bufferedRequestsSuccessful.Get())
+		if buffered, err := b.wait(context.Background(), target, test.inTransaction); buffered || err != nil {
+			t.Errorf("With %v, wait() = %v, %v; want false, nil", test.desc, buffered, err)
 		}
 	}
 }
 
-// min for ints
-func min(x, y int) int {
-	if x < y {
-		return x
+func TestBufferFailover(t *testing.T) {
+	defer setFlags(10, time.Minute, time.Minute)()
+	b := newBuffer()
+	ctx := context.Background()
+
+	// Nothing is buffered while the master is serving.
+	b.statsUpdate(masterStats(1, true, 10))
+	if buffered, err := b.wait(ctx, masterTarget, false); buffered || err != nil {
+		t.Errorf("wait() with serving master = %v, %v", buffered, err)
 	}
-	return y
-}
 
-func TestParallelFakeBuffer(t *testing.T) {
-	bufferedKeyspace := "bkeyspace"
-	bufferedShard := "-80"
+	// A non-serving replica that thinks it's a master doesn't
+	// start a failover.
+	b.statsUpdate(masterStats(2, false, 5))
+	if buffered, err := b.wait(ctx, masterTarget, false); buffered || err != nil {
+		t.Errorf("wait() with other tablet not serving = %v, %v", buffered, err)
+	}
 
-	*bufferKeyspace = bufferedKeyspace
-	*bufferShard = bufferedShard
-	*enableFakeMasterBuffer = true
+	// The master stops serving, as in PlannedReparentShard.
+	b.statsUpdate(masterStats(1, false, 10))
+	drainedBefore := requestsDrained.Counts()["ks.-80"]
+	result1 := startWait(ctx, b)
+	result2 := startWait(ctx, b)
+	waitForBuffered(t, b, 2)
 
-	// reset counters
-	bufferedRequestsAttempted.Set(0)
-	bufferedRequestsSuccessful.Set(0)
+	// An old master reporting serving doesn't end the failover.
+	b.statsUpdate(masterStats(3, true, 5))
+	waitForBuffered(t, b, 2)
 
-	var controllers []*fakeSleepController
-	var wg sync.WaitGroup
+	// The new master ends it.
+	b.statsUpdate(masterStats(2, true, 20))
+	checkWaitResult(t, result1, true, "")
+	checkWaitResult(t, result2, true, "")
+	if got := requestsDrained.Counts()["ks.-80"] - drainedBefore; got != 2 {
+		t.Errorf("BufferRequestsDrained = %v, want 2", got)
+	}
+	if buffered, err := b.wait(ctx, masterTarget, false); buffered || err != nil {
+		t.Errorf("wait() after failover = %v, %v", buffered, err)
+	}
+	status := b.status()
+	if len(status) != 1 || status[0].Buffering || status[0].MasterAlias != "cell-0000000002" || status[0].LastFailoverEnd != "master cell-0000000002 is serving" {
+		t.Errorf("status() = %#v", status)
+	}
 
-	for i := 1; i <= *maxBufferSize+2; i++ {
-		controller := &fakeSleepController{
-			block:   true,
-			done:    make(chan struct{}),
-			blocked: make(chan struct{}),
-		}
-		timeSleep = createFakeSleep(controller)
-		// Only the first maxBufferSize calls to FakeBuffer should actually call fakeSleep
-		wantFakeSleepCalled := (i <= *maxBufferSize)
+	// A down event of the master starts a failover too.
+	ts := masterStats(2, true, 20)
+	ts.Up = false
+	b.statsUpdate(ts)
+	result := startWait(ctx, b)
+	waitForBuffered(t, b, 1)
+	b.statsUpdate(masterStats(2, true, 20))
+	checkWaitResult(t, result, true, "")
+}
 
-		wg.Add(1)
-		finished := make(chan struct{})
-		var gotErr error
-		go func() {
-			defer wg.Done()
-			target := &querypb.Target{
-				Keyspace:   *bufferKeyspace,
-				Shard:      *bufferShard,
-				TabletType: topodatapb.TabletType_MASTER,
-			}
-			gotErr = FakeBuffer(target, false, 0)
-			close(finished)
-		}()
+func TestBufferLimits(t *testing.T) {
+	defer setFlags(2, time.Minute, time.Minute)()
+	b := newBuffer()
+	b.statsUpdate(masterStats(1, true, 10))
+	b.statsUpdate(masterStats(1, false, 10))
 
-		// wait until either the gorouotine is blocked (because it's buffering) or until
-		// it's finished (because it shouldn't be buffered).
-		select {
-		case <-controller.blocked:
-		case <-finished:
-		}
-
-		if controller.called {
-			controllers = append(controllers, controller)
-		} else {
-			// if we didn't call fakeSleep, the buffer is full and should return an error saying so.
-			if gotErr != errBufferFull {
-				t.Errorf("On iteration %v, FakeBuffer() => %v; want: %v", i, gotErr, errBufferFull)
-			}
-		}
+	// The buffer is full after maxBufferSize requests.
+	ctx, cancel := context.WithCancel(context.Background())
+	result1 := startWait(ctx, b)
+	result2 := startWait(ctx, b)
+	waitForBuffered(t, b, 2)
+	if buffered, err := b.wait(ctx, masterTarget, false); buffered || err != errBufferFull {
+		t.Errorf("wait() with full buffer = %v, %v, want %v", buffered, err, errBufferFull)
+	}
 
-		if controller.called != wantFakeSleepCalled {
-			t.Errorf("On iteration %v, FakeBuffer() => timeSleep.called: %v; want: %v",
-				i, controller.called, wantFakeSleepCalled)
-		}
+	// Canceled requests leave the buffer.
+	cancel()
+	checkWaitResult(t, result1, true, "context expired while the request was buffered")
+	checkWaitResult(t, result2, true, "context expired while the request was buffered")
+	waitForBuffered(t, b, 0)
 
-		if int(bufferedRequestsAttempted.Get()) != i {
-			t.Errorf("On iteration %v, FakeBuffer() => bufferedRequestsAttempted got: %v; want: %v",
-				i, bufferedRequestsAttempted.Get(), i)
-		}
+	// Requests are not buffered longer than the window.
+	*bufferWindow = 10 * time.Millisecond
+	if buffered, err := b.wait(context.Background(), masterTarget, false); !buffered || err != nil {
+		t.Errorf("wait() with small window = %v, %v", buffered, err)
+	}
+}
 
-		if int(bufferedRequests.Get()) != min(i, *maxBufferSize) {
-			t.Errorf("On iteration %v, FakeBuffer() => bufferedRequests got: %v; want: %v",
-				i, bufferedRequests.Get(), min(i, *maxBufferSize))
-		}
+func TestBufferMaxFailoverDuration(t *testing.T) {
+	defer setFlags(10, time.Minute, 50*time.Millisecond)()
+	b := newBuffer()
+	b.statsUpdate(masterStats(1, true, 10))
+	b.statsUpdate(masterStats(1, false, 10))
 
-		if int(bufferedRequestsSuccessful.Get()) != 0 {
-			t.Errorf("On iteration %v, FakeBuffer() => bufferedRequestsSuccessful got: %v; want: 0",
-				i, bufferedRequestsSuccessful.Get())
-		}
+	result := startWait(context.Background(), b)
+	checkWaitResult(t, result, true, "")
+	status := b.status()
+	if len(status) != 1 || status[0].Buffering || status[0].LastFailoverEnd != "max failover duration exceeded" {
+		t.Errorf("status() = %#v", status)
 	}
+}
 
-	// signal to all the buffered calls that they can stop buffering, and wait for them.
-	for _, c := range controllers {
-		close(c.done)
+func TestDeprecatedFlags(t *testing.T) {
+	fs := flag.NewFlagSet("vtgate", flag.ContinueOnError)
+	flag.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	args := []string{"-enable_fake_master_buffer", "-buffer_keyspace", "ks", "-buffer_shard", "-80", "-fake_buffer_delay", "2s"}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse(%v) failed: %v", args, err)
 	}
-	wg.Wait()
-
-	if int(bufferedRequestsSuccessful.Get()) != *maxBufferSize {
-		t.Errorf("After all FakeBuffer() calls are done, bufferedRequestsSuccessful got: %v; want: %v",
-			bufferedRequestsSuccessful.Get(), *maxBufferSize)
+	if *enableMasterBuffer || len(bufferKeyspaceShards) != 0 || *bufferWindow != 10*time.Second {
+		t.Errorf("deprecated flags changed the buffer flags: %v, %v, %v", *enableMasterBuffer, bufferKeyspaceShards, *bufferWindow)
 	}
-	if int(bufferedRequests.Get()) != 0 {
-		t.Errorf("After all FakeBuffer() calls are done, bufferedRequests got: %v; want: %v",
-			bufferedRequests.Get(), 0)
+}
+
+func TestBufferzHandler(t *testing.T) {
+	defer setFlags(10, time.Minute, time.Minute)()
+	b := newBuffer()
+	b.statsUpdate(masterStats(1, true, 10))
+	b.statsUpdate(masterStats(1, false, 10))
+
+	req, _ := http.NewRequest("GET", "/debug/buffer", nil)
+	response := httptest.NewRecorder()
+	bufferzHandler(response, req, b)
+	body := response.Body.String()
+	for _, want := range []string{"<td>ks</td>", "<td>-80</td>", "<td>cell-0000000001</td>", "<td>BUFFERING</td>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("bufferz page doesn't contain %v: %v", want, body)
+		}
 	}
 }
@@ -31,7 +31,7 @@ func buildUpdatePlan(upd *sqlparser.Update, vschema VSchema) (*engine.Route, err
 		Query: generateQuery(upd),
 	}
 	var err error
-	route.Table, err = vschema.Find(string(upd.Table.Qualifier), string(upd.Table.Name))
+	route.Table, err = findDMLTable(upd.Table, vschema)
 	if err != nil {
 		return nil, err
 	}
@@ -44,17 +44,32 @@ func buildUpdatePlan(upd *sqlparser.Update, vschema VSchema) (*engine.Route, err
 		return route, nil
 	}
 
-	err = getDMLRouting(upd.Where, route)
-	if err != nil {
-		return nil, err
+	route.Opcode = getDMLRouting(upd.Where, route, engine.UpdateEqual, engine.UpdateIN, engine.UpdateScatter)
+	if route.Opcode != engine.UpdateEqual && upd.Limit != nil {
+		return nil, errors.New("unsupported: multi-shard update with limit")
 	}
-	route.Opcode = engine.UpdateEqual
 	if isIndexChanging(upd.Exprs, route.Table.ColumnVindexes) {
 		return nil, errors.New("unsupported: DML cannot change vindex column")
 	}
 	return route, nil
 }
 
+// findDMLTable returns the table that a DML must be sent to.
+// Writes to a reference table are sent to its source.
+func findDMLTable(tableName *sqlparser.TableName, vschema VSchema) (*vindexes.Table, error) {
+	table, err := vschema.Find(string(tableName.Qualifier), string(tableName.Name))
+	if err != nil {
+		return nil, err
+	}
+	if !table.IsReference || !table.Keyspace.Sharded {
+		return table, nil
+	}
+	if table.Source == nil {
+		return nil, fmt.Errorf("unsupported: DML on reference table %s without a source", table.Name)
+	}
+	return table.Source, nil
+}
+
 func generateQuery(statement sqlparser.Statement) string {
 	buf := sqlparser.NewTrackedBuffer(dmlFormatter)
 	statement.Format(buf)
@@ -80,7 +95,7 @@ func buildDeletePlan(del *sqlparser.Delete, vschema VSchema) (*engine.Route, err
 		Query: generateQuery(del),
 	}
 	var err error
-	route.Table, err = vschema.Find(string(del.Table.Qualifier), string(del.Table.Name))
+	route.Table, err = findDMLTable(del.Table, vschema)
 	if err != nil {
 		return nil, err
 	}
@@ -93,19 +108,20 @@ func buildDeletePlan(del *sqlparser.Delete, vschema VSchema) (*engine.Route, err
 		return route, nil
 	}
 
-	err = getDMLRouting(del.Where, route)
-	if err != nil {
-		return nil, err
+	route.Opcode = getDMLRouting(del.Where, route, engine.DeleteEqual, engine.DeleteIN, engine.DeleteScatter)
+	if route.Opcode != engine.DeleteEqual && del.Limit != nil {
+		return nil, errors.New("unsupported: multi-shard delete with limit")
 	}
-	route.Opcode = engine.DeleteEqual
-	route.Subquery = generateDeleteSubquery(del, route.Table)
+	route.Subquery = generateDeleteSubquery(del, route.Table, route.Opcode != engine.DeleteEqual)
 	return route, nil
 }
 
 // generateDeleteSubquery generates the query to fetch the rows
 // that will be deleted. This allows VTGate to clean up any
-// owned vindexes as needed.
-func generateDeleteSubquery(del *sqlparser.Delete, table *vindexes.Table) string {
+// owned vindexes as needed. If the delete can target multiple
+// shards, the primary vindex column is added as the last column.
+// It's needed to compute the keyspace id of each row.
+func generateDeleteSubquery(del *sqlparser.Delete, table *vindexes.Table, multiShard bool) string {
 	if len(table.Owned) == 0 {
 		return ""
 	}
@@ -117,6 +133,10 @@ func generateDeleteSubquery(del *sqlparser.Delete, table *vindexes.Table) string
 		buf.WriteString(cv.Column.Original())
 		prefix = ", "
 	}
+	if multiShard {
+		buf.WriteString(prefix)
+		buf.WriteString(table.ColumnVindexes[0].Column.Original())
+	}
 	fmt.Fprintf(buf, " from %s", table.Name)
 	buf.WriteString(sqlparser.String(del.Where))
 	buf.WriteString(" for update")
@@ -124,10 +144,16 @@ func generateDeleteSubquery(del *sqlparser.Delete, table *vindexes.Table) string
 }
 
 // getDMLRouting updates the route with the necessary routing
-// info. If it cannot find a unique route, then it returns an error.
-func getDMLRouting(where *sqlparser.Where, route *engine.Route) error {
+// info, and returns the opcode to use. An equality constraint
+// on a unique vindex yields the equal opcode. An IN clause, or
+// an equality constraint on a non-unique vindex yields the in
+// opcode. The Values of the route are then a list. Otherwise,
+// the scatter opcode is returned.
+// The query is sent as is to the target shards: those that
+// are sent values they don't own will just not find the rows.
+func getDMLRouting(where *sqlparser.Where, route *engine.Route, equal, in, scatter engine.RouteOpcode) engine.RouteOpcode {
 	if where == nil {
-		return errors.New("unsupported: multi-shard where clause in DML")
+		return scatter
 	}
 	for _, index := range route.Table.Ordered {
 		if !vindexes.IsUnique(index.Vindex) {
@@ -136,10 +162,25 @@ func getDMLRouting(where *sqlparser.Where, route *engine.Route) error {
 		if values := getMatch(where.Expr, index.Column); values != nil {
 			route.Vindex = index.Vindex
 			route.Values = values
-			return nil
+			return equal
+		}
+	}
+	for _, index := range route.Table.Ordered {
+		if values := getINMatch(where.Expr, index.Column); values != nil {
+			route.Vindex = index.Vindex
+			route.Values = values
+			return in
+		}
+		if vindexes.IsUnique(index.Vindex) {
+			continue
+		}
+		if values := getMatch(where.Expr, index.Column); values != nil {
+			route.Vindex = index.Vindex
+			route.Values = []interface{}{values}
+			return in
 		}
 	}
-	return errors.New("unsupported: multi-shard where clause in DML")
+	return scatter
 }
 
 // getMatch returns the matched value if there is an equality
@@ -170,6 +211,52 @@ func getMatch(node sqlparser.BoolExpr, col cistring.CIString) interface{} {
 	return nil
 }
 
+// getINMatch returns the list of values if there is an IN
+// constraint on the specified column that can be used to
+// decide on a route. The list is either a []interface{} or
+// the name of a list bind var.
+func getINMatch(node sqlparser.BoolExpr, col cistring.CIString) interface{} {
+	filters := splitAndExpression(nil, node)
+	for _, filter := range filters {
+		comparison, ok := filter.(*sqlparser.ComparisonExpr)
+		if !ok {
+			continue
+		}
+		if comparison.Operator != sqlparser.InStr {
+			continue
+		}
+		if !nameMatch(comparison.Left, col) {
+			continue
+		}
+		switch right := comparison.Right.(type) {
+		case sqlparser.ListArg:
+			return string(right)
+		case sqlparser.ValTuple:
+			if values := tupleValues(right); values != nil {
+				return values
+			}
+		}
+	}
+	return nil
+}
+
+// tupleValues converts the tuple into a list of values.
+// It returns nil if any of the elements is not a value.
+func tupleValues(tuple sqlparser.ValTuple) []interface{} {
+	values := make([]interface{}, 0, len(tuple))
+	for _, expr := range tuple {
+		if !sqlparser.IsValue(expr) {
+			return nil
+		}
+		val, err := valConvert(expr)
+		if err != nil {
+			return nil
+		}
+		values = append(values, val)
+	}
+	return values
+}
+
 func nameMatch(node sqlparser.ValExpr, col cistring.CIString) bool {
 	colname, ok := node.(*sqlparser.ColName)
 	return ok && colname.Name.Equal(sqlparser.ColIdent(col))
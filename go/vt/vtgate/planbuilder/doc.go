@@ -19,7 +19,21 @@ The two core primitives built by this package are Route and Join.
 The Route primitive executes a query and returns the result.
 This can be either to a single keyspace or shard, or it can
 be a scatter query that spans multiple shards. In the case
-of a scatter, the rows can be returned in any order.
+of a scatter, the rows can be returned in any order, unless
+an ORDER BY was specified. If so, the ORDER BY is pushed down
+to every shard, and VTGate merge-sorts the results.
+
+The Limit primitive applies the LIMIT clause of a scatter
+query on the merged results of a Route. The Route itself
+fetches up to offset+count rows from each shard.
+
+The OrderedAggregate primitive combines the partial aggregates
+returned by the shards of a scatter Route. The Route is asked to
+sort the rows by the GROUP BY keys, which allows consecutive rows
+with the same keys to be merged. COUNT and SUM are added up, MIN
+and MAX are compared, and an AVG is computed from a SUM and a
+COUNT that are sent to the shards in its place. A DISTINCT is
+treated as a GROUP BY on all the columns.
 
 The Join primitive can perform a normal or a left join.
 If there is a join condition, it's actually executed
@@ -69,6 +69,12 @@ func findRoute(expr sqlparser.Expr, bldr builder) (rb *route, err error) {
 			if err != nil {
 				return false, err
 			}
+			switch subplan.(type) {
+			case *limit:
+				return false, errors.New("unsupported: scatter and limit in subqueries")
+			case *orderedAggregate:
+				return false, errors.New("unsupported: scatter with aggregates in subqueries")
+			}
 			subroute, ok := subplan.(*route)
 			if !ok {
 				return false, errors.New("unsupported: complex join in subqueries")
@@ -110,7 +116,8 @@ func subqueryCanMerge(outer, inner *route) error {
 	if !inner.IsSingle() {
 		return errors.New("unsupported: scatter subquery")
 	}
-	if inner.ERoute.Opcode == engine.SelectUnsharded {
+	switch inner.ERoute.Opcode {
+	case engine.SelectUnsharded, engine.SelectReference:
 		return nil
 	}
 	// SelectEqualUnique
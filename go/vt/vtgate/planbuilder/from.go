@@ -85,10 +85,20 @@ func processAliasedTable(tableExpr *sqlparser.AliasedTableExpr, vschema VSchema)
 		if err != nil {
 			return nil, err
 		}
+		switch subplan.(type) {
+		case *limit:
+			return nil, errors.New("unsupported: scatter and limit in subqueries")
+		case *orderedAggregate:
+			return nil, errors.New("unsupported: scatter with aggregates in subqueries")
+		}
 		subroute, ok := subplan.(*route)
 		if !ok {
 			return nil, errors.New("unsupported: complex join in subqueries")
 		}
+		// The order of the rows of a derived table is not significant.
+		// The route is reused by the outer query. So, the merge-sort
+		// requested by the subquery must not be carried over.
+		subroute.ERoute.OrderBy = nil
 		table := &vindexes.Table{
 			Keyspace: subroute.ERoute.Keyspace,
 		}
@@ -131,6 +141,13 @@ func getTablePlan(tableName *sqlparser.TableName, vschema VSchema) (*engine.Rout
 		return nil, nil, err
 	}
 	if table.Keyspace.Sharded {
+		if table.IsReference {
+			return &engine.Route{
+				Opcode:   engine.SelectReference,
+				Keyspace: table.Keyspace,
+				JoinVars: make(map[string]struct{}),
+			}, table, nil
+		}
 		return &engine.Route{
 			Opcode:   engine.SelectScatter,
 			Keyspace: table.Keyspace,
@@ -21,7 +21,7 @@ func buildInsertPlan(ins *sqlparser.Insert, vschema VSchema) (*engine.Route, err
 		Query: generateQuery(ins),
 	}
 	var err error
-	route.Table, err = vschema.Find(string(ins.Table.Qualifier), string(ins.Table.Name))
+	route.Table, err = findDMLTable(ins.Table, vschema)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +36,10 @@ func buildInsertPlan(ins *sqlparser.Insert, vschema VSchema) (*engine.Route, err
 	}
 	var values sqlparser.Values
 	switch rows := ins.Rows.(type) {
-	case *sqlparser.Select, *sqlparser.Union:
-		return nil, errors.New("unsupported: insert into select")
+	case *sqlparser.Select:
+		return buildInsertSelectPlan(ins, rows, route, vschema)
+	case *sqlparser.Union:
+		return nil, errors.New("unsupported: insert from union")
 	case sqlparser.Values:
 		values = rows
 	default:
@@ -87,9 +89,90 @@ func buildInsertPlan(ins *sqlparser.Insert, vschema VSchema) (*engine.Route, err
 	}
 	route.Values = routeValues
 	route.Query = generateQuery(ins)
+	route.Prefix, route.Mid, route.Suffix = generateInsertParts(ins)
 	return route, nil
 }
 
+// buildInsertSelectPlan builds the route for an INSERT with
+// a SELECT. The select is built as the Input of the route. If
+// a column vindex or the autoinc column is not in the column
+// list, it's added, and the select supplies a NULL for it.
+// Those values get computed by VTGate for each row.
+func buildInsertSelectPlan(ins *sqlparser.Insert, sel *sqlparser.Select, route *engine.Route, vschema VSchema) (*engine.Route, error) {
+	for _, expr := range sel.SelectExprs {
+		if _, ok := expr.(*sqlparser.StarExpr); ok {
+			return nil, errors.New("unsupported: '*' expression in insert select")
+		}
+	}
+	if len(ins.Columns) != len(sel.SelectExprs) {
+		return nil, errors.New("column list doesn't match values")
+	}
+	route.Opcode = engine.InsertSelect
+	colNums := make([]interface{}, 0, len(route.Table.ColumnVindexes))
+	for _, index := range route.Table.ColumnVindexes {
+		colNums = append(colNums, findOrInsertSelectPos(ins, sel, index.Column))
+	}
+	route.Values = colNums
+	if autoinc := route.Table.AutoIncrement; autoinc != nil {
+		colNum := 0
+		if autoinc.ColumnVindexNum >= 0 {
+			colNum = colNums[autoinc.ColumnVindexNum].(int)
+		} else {
+			colNum = findOrInsertSelectPos(ins, sel, autoinc.Column)
+		}
+		route.Generate = &engine.Generate{
+			Opcode:   engine.SelectUnsharded,
+			Keyspace: autoinc.Sequence.Keyspace,
+			Query:    fmt.Sprintf("select next value from `%s`", autoinc.Sequence.Name),
+			Value:    colNum,
+		}
+	}
+	var err error
+	route.Input, err = buildSelectPlan(sel, vschema)
+	if err != nil {
+		return nil, err
+	}
+	route.Query = generateQuery(ins)
+	route.Prefix, _, route.Suffix = generateInsertParts(ins)
+	return route, nil
+}
+
+// findOrInsertSelectPos returns the position of the column in
+// the insert. If the column is not present, it's added, and the
+// select is changed to supply a NULL for it.
+func findOrInsertSelectPos(ins *sqlparser.Insert, sel *sqlparser.Select, col cistring.CIString) int {
+	for i, column := range ins.Columns {
+		if col.Equal(cistring.CIString(column)) {
+			return i
+		}
+	}
+	ins.Columns = append(ins.Columns, sqlparser.ColIdent(col))
+	sel.SelectExprs = append(sel.SelectExprs, &sqlparser.NonStarExpr{Expr: &sqlparser.NullVal{}})
+	return len(ins.Columns) - 1
+}
+
+// generateInsertParts generates the parts of an insert that
+// are needed to build a separate query for each shard. The
+// query for a shard is the prefix, followed by the comma
+// separated mids of its rows, followed by the suffix.
+func generateInsertParts(ins *sqlparser.Insert) (prefix string, mid []string, suffix string) {
+	buf := sqlparser.NewTrackedBuffer(dmlFormatter)
+	buf.Myprintf("insert %v%sinto %v%v values ", ins.Comments, ins.Ignore, ins.Table, ins.Columns)
+	prefix = buf.String()
+	if values, ok := ins.Rows.(sqlparser.Values); ok {
+		mid = make([]string, 0, len(values))
+		for _, row := range values {
+			buf = sqlparser.NewTrackedBuffer(dmlFormatter)
+			buf.Myprintf("%v", row)
+			mid = append(mid, buf.String())
+		}
+	}
+	buf = sqlparser.NewTrackedBuffer(dmlFormatter)
+	buf.Myprintf("%v", ins.OnDup)
+	suffix = buf.String()
+	return prefix, mid, suffix
+}
+
 // buildIndexPlan adds the insert value to the Values field for the specified ColumnVindex.
 // This value will be used at the time of insert to validate the vindex value.
 func buildIndexPlan(colVindex *vindexes.ColumnVindex, rowNum int, row sqlparser.ValTuple, pos int) (interface{}, error) {
@@ -0,0 +1,99 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package planbuilder
+
+import (
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/vtgate/engine"
+)
+
+// limit is used to build a Limit primitive.
+// It's used to apply the LIMIT clause of a scatter
+// query on the merged results of a route. It's always
+// the top-level node, and its input is the route or
+// an orderedAggregate. So, most functions just delegate
+// to the input.
+type limit struct {
+	input  builder
+	elimit *engine.Limit
+}
+
+// newLimit builds a new limit on top of the input.
+func newLimit(input builder, count, offset int64) *limit {
+	return &limit{
+		input: input,
+		elimit: &engine.Limit{
+			Count:  count,
+			Offset: offset,
+			Input:  input.Primitive(),
+		},
+	}
+}
+
+// Symtab returns the associated symtab.
+func (l *limit) Symtab() *symtab {
+	return l.input.Symtab()
+}
+
+// SetSymtab sets the symtab.
+func (l *limit) SetSymtab(symtab *symtab) {
+	l.input.SetSymtab(symtab)
+}
+
+// Order returns the order of the node.
+func (l *limit) Order() int {
+	return l.input.Order()
+}
+
+// SetOrder sets the order for the input.
+func (l *limit) SetOrder(order int) {
+	l.input.SetOrder(order)
+}
+
+// Primitve returns the built primitive.
+func (l *limit) Primitive() engine.Primitive {
+	return l.elimit
+}
+
+// Leftmost returns the underlying route.
+func (l *limit) Leftmost() *route {
+	return l.input.Leftmost()
+}
+
+// Join should be unreachable: a limit is built only
+// after the FROM clause is fully analyzed.
+func (l *limit) Join(rhs builder, ajoin *sqlparser.JoinTableExpr) (builder, error) {
+	panic("unreachable")
+}
+
+// SetRHS should be unreachable for the same reason as Join.
+func (l *limit) SetRHS() {
+	panic("unreachable")
+}
+
+// PushSelect pushes the select expression into the input.
+func (l *limit) PushSelect(expr *sqlparser.NonStarExpr, rb *route) (colsym *colsym, colnum int, err error) {
+	return l.input.PushSelect(expr, rb)
+}
+
+// PushMisc updates the comments & 'for update' sections of the input.
+func (l *limit) PushMisc(sel *sqlparser.Select) {
+	l.input.PushMisc(sel)
+}
+
+// Wireup performs the wire-up tasks of the input.
+func (l *limit) Wireup(bldr builder, jt *jointab) error {
+	return l.input.Wireup(bldr, jt)
+}
+
+// SupplyVar should be unreachable.
+func (l *limit) SupplyVar(from, to int, col *sqlparser.ColName, varname string) {
+	panic("unreachable")
+}
+
+// SupplyCol changes the input to supply the requested column.
+func (l *limit) SupplyCol(ref colref) int {
+	return l.input.SupplyCol(ref)
+}
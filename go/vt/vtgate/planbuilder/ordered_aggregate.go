@@ -0,0 +1,312 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package planbuilder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/vtgate/engine"
+)
+
+// orderedAggregate is used to build an OrderedAggregate primitive.
+// It's used for scatter queries that have aggregates, a GROUP BY
+// or a DISTINCT that can't be fully pushed down to the shards.
+// The route computes partial aggregates for each shard, sorted
+// by the group keys, and the OrderedAggregate combines them.
+// An AVG is rewritten as a SUM, and a COUNT of the same
+// expressions is added as an extra column. Those extra columns
+// are truncated from the final result. If the AVG has no alias,
+// the result column is renamed back to the original expression.
+// Like limit, an orderedAggregate is always built on top of a
+// route, after the FROM clause is analyzed. So, most functions
+// just delegate to the input.
+type orderedAggregate struct {
+	input *route
+	eaggr *engine.OrderedAggregate
+}
+
+// newOrderedAggregate builds an orderedAggregate for the scatter
+// route. The select expressions must have already been pushed
+// into the route.
+func newOrderedAggregate(rb *route, sel *sqlparser.Select) (*orderedAggregate, error) {
+	oa := &orderedAggregate{
+		input: rb,
+		eaggr: &engine.OrderedAggregate{
+			Input: rb.ERoute,
+		},
+	}
+	var avgs []int
+	for i, selectExpr := range rb.Select.SelectExprs {
+		expr, ok := selectExpr.(*sqlparser.NonStarExpr)
+		if !ok {
+			return nil, errors.New("unsupported: '*' expression in scatter aggregates")
+		}
+		fexpr, ok := expr.Expr.(*sqlparser.FuncExpr)
+		if !ok || !fexpr.IsAggregate() {
+			if hasAggregates(expr.Expr) {
+				return nil, errors.New("unsupported: complex aggregate expression in scatter")
+			}
+			continue
+		}
+		opcode, ok := engine.SupportedAggregates[strings.ToLower(fexpr.Name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported: aggregate function %s in scatter", fexpr.Name)
+		}
+		if fexpr.Distinct {
+			return nil, fmt.Errorf("unsupported: distinct aggregate %s in scatter", sqlparser.String(fexpr))
+		}
+		aggr := engine.AggregateParams{
+			Opcode: opcode,
+			Col:    i,
+		}
+		if opcode == engine.AggregateAvg {
+			if expr.As.Original() == "" {
+				aggr.Alias = sqlparser.String(fexpr)
+			}
+			rb.Select.SelectExprs[i] = &sqlparser.NonStarExpr{
+				Expr: &sqlparser.FuncExpr{Name: "sum", Exprs: fexpr.Exprs},
+				As:   expr.As,
+			}
+			avgs = append(avgs, len(oa.eaggr.Aggregates))
+		}
+		oa.eaggr.Aggregates = append(oa.eaggr.Aggregates, aggr)
+	}
+	if len(avgs) != 0 {
+		oa.eaggr.TruncateColumnCount = len(rb.Colsyms)
+		for _, i := range avgs {
+			aggr := &oa.eaggr.Aggregates[i]
+			fexpr := sel.SelectExprs[aggr.Col].(*sqlparser.NonStarExpr).Expr.(*sqlparser.FuncExpr)
+			_, colnum, err := rb.PushSelect(&sqlparser.NonStarExpr{
+				Expr: &sqlparser.FuncExpr{Name: "count", Exprs: fexpr.Exprs},
+			}, rb)
+			if err != nil {
+				return nil, err
+			}
+			aggr.CountCol = colnum
+		}
+	}
+
+	if sel.Distinct != "" {
+		if len(oa.eaggr.Aggregates) != 0 || sel.GroupBy != nil {
+			return nil, errors.New("unsupported: distinct with aggregates or group by in scatter")
+		}
+		for i := range sel.SelectExprs {
+			oa.eaggr.Keys = append(oa.eaggr.Keys, i)
+		}
+		return oa, nil
+	}
+	for _, expr := range sel.GroupBy {
+		colnum := -1
+		switch node := expr.(type) {
+		case *sqlparser.ColName:
+			if cs, ok := node.Metadata.(*colsym); ok {
+				colnum = findColsym(rb, cs)
+			}
+		case sqlparser.NumVal:
+			num, err := strconv.ParseInt(string(node), 0, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing group by clause: %s", string(node))
+			}
+			if num < 1 || num > int64(len(sel.SelectExprs)) {
+				return nil, errors.New("group by column number out of range")
+			}
+			colnum = int(num - 1)
+		}
+		if colnum == -1 {
+			return nil, errors.New("unsupported: group by expression in scatter must reference a select expression")
+		}
+		oa.eaggr.Keys = append(oa.eaggr.Keys, colnum)
+	}
+	rb.SetGroupBy(sel.GroupBy)
+	return oa, nil
+}
+
+// hasAggregates returns true if the expression
+// contains an aggregate function.
+func hasAggregates(node sqlparser.SQLNode) bool {
+	has := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if fexpr, ok := node.(*sqlparser.FuncExpr); ok && fexpr.IsAggregate() {
+			has = true
+			return false, errors.New("dummy")
+		}
+		return true, nil
+	}, node)
+	return has
+}
+
+// PushOrderBy pushes the order by clause into the route.
+// The rows must be sorted by the keys for the groups to
+// be merged. So, the order by can only reference keys.
+// The rest of the keys are used for sorting after the
+// specified order.
+func (oa *orderedAggregate) PushOrderBy(orderBy sqlparser.OrderBy) error {
+	ordered := make(map[int]bool)
+	for _, order := range orderBy {
+		colnum := -1
+		switch node := order.Expr.(type) {
+		case *sqlparser.ColName:
+			_, isLocal, err := oa.Symtab().Find(node, true)
+			if err != nil {
+				return err
+			}
+			if !isLocal {
+				return errors.New("unsupported: subquery references outer query in order by")
+			}
+			if cs, ok := node.Metadata.(*colsym); ok {
+				colnum = findColsym(oa.input, cs)
+			}
+		case sqlparser.NumVal:
+			num, err := strconv.ParseInt(string(node), 0, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing order by clause: %s", string(node))
+			}
+			if num < 1 || num > int64(len(oa.Symtab().Colsyms)) {
+				return errors.New("order by column number out of range")
+			}
+			colnum = int(num - 1)
+		default:
+			return errors.New("unsupported: complex expression in order by")
+		}
+		if !oa.isKey(colnum) {
+			return errors.New("unsupported: order by must reference group by columns in scatter aggregates")
+		}
+		if err := oa.pushOrder(order.Expr, colnum, order.Direction); err != nil {
+			return err
+		}
+		ordered[colnum] = true
+	}
+	for _, key := range oa.eaggr.Keys {
+		if ordered[key] {
+			continue
+		}
+		expr := sqlparser.NumVal(strconv.AppendInt(nil, int64(key+1), 10))
+		if err := oa.pushOrder(expr, key, sqlparser.AscScr); err != nil {
+			return err
+		}
+		ordered[key] = true
+	}
+	return nil
+}
+
+func (oa *orderedAggregate) isKey(colnum int) bool {
+	for _, key := range oa.eaggr.Keys {
+		if key == colnum {
+			return true
+		}
+	}
+	return false
+}
+
+// pushOrder adds the order by expression to the route, and
+// requests the route to merge-sort the results.
+func (oa *orderedAggregate) pushOrder(expr sqlparser.ValExpr, colnum int, direction string) error {
+	if err := oa.input.AddOrder(&sqlparser.Order{Expr: expr, Direction: direction}); err != nil {
+		return err
+	}
+	oa.input.ERoute.OrderBy = append(oa.input.ERoute.OrderBy, engine.OrderbyParams{
+		Col:  colnum,
+		Desc: direction == sqlparser.DescScr,
+	})
+	return nil
+}
+
+// pushWeightStrings adds the weight strings of the keys and of the
+// partial minimums and maximums to the select expressions of the
+// route. The keys are merge-sorted and compared by their weight
+// strings, and so are the minimums and maximums of text values.
+// The weight strings are truncated from the final result.
+func (oa *orderedAggregate) pushWeightStrings() {
+	rb := oa.input
+	count := len(rb.Colsyms)
+	weightStrings := make(map[int]int)
+	for i, order := range rb.ERoute.OrderBy {
+		weightStrings[order.Col] = rb.pushWeightString(order.Col)
+		rb.ERoute.OrderBy[i].WeightStringCol = weightStrings[order.Col]
+	}
+	if len(weightStrings) != 0 {
+		for _, key := range oa.eaggr.Keys {
+			oa.eaggr.KeyWeightStrings = append(oa.eaggr.KeyWeightStrings, weightStrings[key])
+		}
+	}
+	for i, aggr := range oa.eaggr.Aggregates {
+		if aggr.Opcode == engine.AggregateMin || aggr.Opcode == engine.AggregateMax {
+			oa.eaggr.Aggregates[i].WeightStringCol = rb.pushWeightString(aggr.Col)
+		}
+	}
+	if oa.eaggr.TruncateColumnCount == 0 && len(rb.Colsyms) != count {
+		oa.eaggr.TruncateColumnCount = count
+	}
+}
+
+// Symtab returns the associated symtab.
+func (oa *orderedAggregate) Symtab() *symtab {
+	return oa.input.Symtab()
+}
+
+// SetSymtab sets the symtab.
+func (oa *orderedAggregate) SetSymtab(symtab *symtab) {
+	oa.input.SetSymtab(symtab)
+}
+
+// Order returns the order of the node.
+func (oa *orderedAggregate) Order() int {
+	return oa.input.Order()
+}
+
+// SetOrder sets the order for the underlying route.
+func (oa *orderedAggregate) SetOrder(order int) {
+	oa.input.SetOrder(order)
+}
+
+// Primitve returns the built primitive.
+func (oa *orderedAggregate) Primitive() engine.Primitive {
+	return oa.eaggr
+}
+
+// Leftmost returns the underlying route.
+func (oa *orderedAggregate) Leftmost() *route {
+	return oa.input
+}
+
+// Join should be unreachable: an orderedAggregate is built
+// only after the FROM clause is fully analyzed.
+func (oa *orderedAggregate) Join(rhs builder, ajoin *sqlparser.JoinTableExpr) (builder, error) {
+	panic("unreachable")
+}
+
+// SetRHS should be unreachable for the same reason as Join.
+func (oa *orderedAggregate) SetRHS() {
+	panic("unreachable")
+}
+
+// PushSelect pushes the select expression into the route.
+func (oa *orderedAggregate) PushSelect(expr *sqlparser.NonStarExpr, rb *route) (colsym *colsym, colnum int, err error) {
+	return oa.input.PushSelect(expr, rb)
+}
+
+// PushMisc updates the comments & 'for update' sections of the route.
+func (oa *orderedAggregate) PushMisc(sel *sqlparser.Select) {
+	oa.input.PushMisc(sel)
+}
+
+// Wireup performs the wire-up tasks of the route.
+func (oa *orderedAggregate) Wireup(bldr builder, jt *jointab) error {
+	return oa.input.Wireup(bldr, jt)
+}
+
+// SupplyVar should be unreachable.
+func (oa *orderedAggregate) SupplyVar(from, to int, col *sqlparser.ColName, varname string) {
+	panic("unreachable")
+}
+
+// SupplyCol changes the route to supply the requested column.
+func (oa *orderedAggregate) SupplyCol(ref colref) int {
+	return oa.input.SupplyCol(ref)
+}
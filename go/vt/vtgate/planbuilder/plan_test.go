@@ -15,6 +15,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/youtube/vitess/go/cistring"
 	"github.com/youtube/vitess/go/testfiles"
 	"github.com/youtube/vitess/go/vt/vtgate/vindexes"
 )
@@ -99,9 +100,42 @@ func TestPlan(t *testing.T) {
 	testFile(t, "postprocess_cases.txt", vschema)
 	testFile(t, "wireup_cases.txt", vschema)
 	testFile(t, "dml_cases.txt", vschema)
+	testFile(t, "savepoint_cases.txt", vschema)
 	testFile(t, "unsupported_cases.txt", vschema)
 }
 
+// columnVSchema adds the columns of some tables to a VSchema,
+// as if they were tracked from the tablets.
+type columnVSchema struct {
+	vschema *vindexes.VSchema
+	columns map[string][]cistring.CIString
+}
+
+func (vs *columnVSchema) Find(keyspace, tablename string) (*vindexes.Table, error) {
+	table, err := vs.vschema.Find(keyspace, tablename)
+	if err != nil {
+		return nil, err
+	}
+	if columns, ok := vs.columns[tablename]; ok {
+		withColumns := *table
+		withColumns.Columns = columns
+		return &withColumns, nil
+	}
+	return table, nil
+}
+
+func TestPlanWithColumns(t *testing.T) {
+	vschema := &columnVSchema{
+		vschema: loadSchema(t, "schema_test.json"),
+		columns: map[string][]cistring.CIString{
+			"user":       {cistring.New("id"), cistring.New("name")},
+			"user_extra": {cistring.New("user_id"), cistring.New("extra_id")},
+			"music":      {cistring.New("id"), cistring.New("user_id")},
+		},
+	}
+	testFile(t, "column_cases.txt", vschema)
+}
+
 func TestOne(t *testing.T) {
 	vschema := loadSchema(t, "schema_test.json")
 	testFile(t, "onecase.txt", vschema)
@@ -119,7 +153,7 @@ func loadSchema(t *testing.T, filename string) *vindexes.VSchema {
 	return vschema
 }
 
-func testFile(t *testing.T, filename string, vschema *vindexes.VSchema) {
+func testFile(t *testing.T, filename string, vschema VSchema) {
 	for tcase := range iterateExecFile(filename) {
 		plan, err := Build(tcase.input, vschema)
 		var out string
@@ -10,6 +10,7 @@ import (
 	"strconv"
 
 	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/vtgate/engine"
 	"github.com/youtube/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -18,15 +19,18 @@ import (
 
 // pushGroupBy processes the group by clause. It resolves all symbols,
 // and ensures that there are no subqueries. It also verifies that the
-// references don't addres an outer query. We only support group by
-// for unsharded or single shard routes.
-func pushGroupBy(groupBy sqlparser.GroupBy, bldr builder) error {
-	if groupBy == nil {
-		return nil
+// references don't addres an outer query. For scatter routes, the
+// group by is pushed down as is if it references a column with a
+// unique vindex. Otherwise, or if needsMerge is set, an orderedAggregate
+// is built to merge the groups returned by the shards.
+func pushGroupBy(sel *sqlparser.Select, bldr builder, needsMerge bool) (builder, error) {
+	groupBy := sel.GroupBy
+	if groupBy == nil && !needsMerge {
+		return bldr, nil
 	}
 	rb, ok := bldr.(*route)
 	if !ok {
-		return errors.New("unsupported: complex join and group by")
+		return nil, errors.New("unsupported: complex join and group by")
 	}
 	err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
 		switch node := node.(type) {
@@ -45,22 +49,23 @@ func pushGroupBy(groupBy sqlparser.GroupBy, bldr builder) error {
 		return true, nil
 	}, groupBy)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if rb.IsSingle() {
 		rb.SetGroupBy(groupBy)
-		return nil
+		return rb, nil
 	}
-	// It's a scatter route. We can allow group by if it references a
-	// column with a unique vindex.
+	// It's a scatter route. We can push down the group by if it
+	// references a column with a unique vindex: all the rows of
+	// a group are then in the same shard.
 	for _, expr := range groupBy {
 		vindex := bldr.Symtab().Vindex(expr, rb, true)
 		if vindex != nil && vindexes.IsUnique(vindex) {
 			rb.SetGroupBy(groupBy)
-			return nil
+			return rb, nil
 		}
 	}
-	return errors.New("unsupported: scatter and group by")
+	return newOrderedAggregate(rb, sel)
 }
 
 // pushOrderBy pushes the order by clause to the appropriate routes.
@@ -69,7 +74,13 @@ func pushGroupBy(groupBy sqlparser.GroupBy, bldr builder) error {
 // If column numbers were used to reference the columns, those numbers
 // are readjusted on push-down to match the numbers of the individual
 // queries.
+// If a route can target multiple shards, the order by is still pushed
+// down, and the results of the shards are merge-sorted by VTGate. For
+// this, the order by expressions must reference select expressions.
 func pushOrderBy(orderBy sqlparser.OrderBy, bldr builder) error {
+	if oa, ok := bldr.(*orderedAggregate); ok {
+		return oa.PushOrderBy(orderBy)
+	}
 	if orderBy == nil {
 		return nil
 	}
@@ -80,6 +91,9 @@ func pushOrderBy(orderBy sqlparser.OrderBy, bldr builder) error {
 		// we have to build a new node.
 		pushOrder := order
 		var rb *route
+		// colnum is the column number of the order by
+		// expression within the result of rb, if known.
+		colnum := -1
 		switch node := order.Expr.(type) {
 		case *sqlparser.ColName:
 			var isLocal bool
@@ -91,6 +105,9 @@ func pushOrderBy(orderBy sqlparser.OrderBy, bldr builder) error {
 			if !isLocal {
 				return errors.New("unsupported: subquery references outer query in order by")
 			}
+			if colsym, ok := node.Metadata.(*colsym); ok {
+				colnum = findColsym(rb, colsym)
+			}
 		case sqlparser.NumVal:
 			num, err := strconv.ParseInt(string(node), 0, 64)
 			if err != nil {
@@ -102,17 +119,14 @@ func pushOrderBy(orderBy sqlparser.OrderBy, bldr builder) error {
 			colsym := bldr.Symtab().Colsyms[num-1]
 			rb = colsym.Route()
 			// We have to recompute the column number.
-			for num, s := range rb.Colsyms {
-				if s == colsym {
-					pushOrder = &sqlparser.Order{
-						Expr:      sqlparser.NumVal(strconv.AppendInt(nil, int64(num+1), 10)),
-						Direction: order.Direction,
-					}
-				}
-			}
-			if pushOrder == order {
+			colnum = findColsym(rb, colsym)
+			if colnum == -1 {
 				panic("unexpected: column not found for order by")
 			}
+			pushOrder = &sqlparser.Order{
+				Expr:      sqlparser.NumVal(strconv.AppendInt(nil, int64(colnum+1), 10)),
+				Direction: order.Direction,
+			}
 		default:
 			return errors.New("unsupported: complex expression in order by")
 		}
@@ -120,27 +134,125 @@ func pushOrderBy(orderBy sqlparser.OrderBy, bldr builder) error {
 			return errors.New("unsupported: complex join and out of sequence order by")
 		}
 		if !rb.IsSingle() {
-			return errors.New("unsupported: scatter and order by")
+			// The column number is needed for the merge-sort.
+			// A '*' expression doesn't identify a column.
+			if colnum == -1 {
+				return errors.New("unsupported: scatter and order by expression that's not in the select list")
+			}
+			if _, ok := rb.Select.SelectExprs[colnum].(*sqlparser.StarExpr); ok {
+				return errors.New("unsupported: scatter and order by column referenced through '*'")
+			}
 		}
 		routeNumber = rb.Order()
 		if err := rb.AddOrder(pushOrder); err != nil {
 			return err
 		}
+		if !rb.IsSingle() {
+			rb.ERoute.OrderBy = append(rb.ERoute.OrderBy, engine.OrderbyParams{
+				Col:  colnum,
+				Desc: order.Direction == sqlparser.DescScr,
+			})
+		}
 	}
 	return nil
 }
 
-func pushLimit(limit *sqlparser.Limit, bldr builder) error {
+// pushWeightStrings adds the weight strings of the merge-sorted
+// columns to the select expressions of the scatter routes: MySQL sorts
+// the text values according to their collation, which VTGate doesn't
+// know, so their weight strings are compared instead. It's done once
+// the whole statement is analyzed: the results of the subqueries are
+// not merge-sorted, and their select expressions must not change. If
+// the route returns the final result, possibly through a limit, it
+// truncates the weight strings. Otherwise, the join or the
+// orderedAggregate only picks the columns it needs.
+func pushWeightStrings(bldr builder, final bool) {
+	switch node := bldr.(type) {
+	case *route:
+		if len(node.ERoute.OrderBy) == 0 {
+			return
+		}
+		count := len(node.Colsyms)
+		for i, order := range node.ERoute.OrderBy {
+			node.ERoute.OrderBy[i].WeightStringCol = node.pushWeightString(order.Col)
+		}
+		if final {
+			node.ERoute.TruncateColumnCount = count
+		}
+	case *limit:
+		pushWeightStrings(node.input, final)
+	case *orderedAggregate:
+		node.pushWeightStrings()
+	case *join:
+		pushWeightStrings(node.Left, false)
+		pushWeightStrings(node.Right, false)
+	}
+}
+
+// findColsym returns the column number of the colsym within
+// the result of the route, or -1 if it's not found.
+func findColsym(rb *route, cs *colsym) int {
+	for i, s := range rb.Colsyms {
+		if s == cs {
+			return i
+		}
+	}
+	return -1
+}
+
+// pushLimit pushes the limit clause into the route. If the
+// route can target multiple shards, each shard is asked
+// for offset+count rows, and a limit primitive is added on
+// top to apply the limit on the merged result. In that case,
+// the new top-level builder is returned.
+func pushLimit(limit *sqlparser.Limit, bldr builder) (builder, error) {
 	if limit == nil {
-		return nil
+		return bldr, nil
 	}
-	rb, ok := bldr.(*route)
+	var rb *route
+	switch node := bldr.(type) {
+	case *route:
+		if node.IsSingle() {
+			node.SetLimit(limit)
+			return bldr, nil
+		}
+		rb = node
+	case *orderedAggregate:
+		// The groups are sorted by their keys, and the keys are
+		// unique within the rows of each shard. So, every group
+		// of the first offset+count merged groups is within the
+		// first offset+count rows of each shard that has it.
+		rb = node.input
+	default:
+		return nil, errors.New("unsupported: limits with complex joins")
+	}
+	count, err := limitValue(limit.Rowcount)
+	if err != nil {
+		return nil, err
+	}
+	var offset int64
+	if limit.Offset != nil {
+		offset, err = limitValue(limit.Offset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rb.SetLimit(&sqlparser.Limit{
+		Rowcount: sqlparser.NumVal(strconv.AppendInt(nil, offset+count, 10)),
+	})
+	return newLimit(bldr, count, offset), nil
+}
+
+// limitValue returns the value of a limit expression. Only
+// numeric literals are supported for scatter limits.
+func limitValue(expr sqlparser.ValExpr) (int64, error) {
+	num, ok := expr.(sqlparser.NumVal)
 	if !ok {
-		return errors.New("unsupported: limits with complex joins")
+		return 0, errors.New("unsupported: limits with scatter and non-literal values")
 	}
-	if !rb.IsSingle() {
-		return errors.New("unsupported: limits with scatter")
+	val, err := strconv.ParseInt(string(num), 0, 64)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid value in limit clause: %s", string(num))
 	}
-	rb.SetLimit(limit)
-	return nil
+	return val, nil
 }
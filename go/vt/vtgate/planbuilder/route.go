@@ -113,6 +113,21 @@ func (rb *route) Join(rhs builder, ajoin *sqlparser.JoinTableExpr) (builder, err
 		return rb.merge(rRoute, ajoin)
 	}
 
+	// Every shard has a copy of a reference table. So, it can be
+	// merged with any route of the same keyspace.
+	if rRoute.ERoute.Opcode == engine.SelectReference {
+		return rb.merge(rRoute, ajoin)
+	}
+	if rb.ERoute.Opcode == engine.SelectReference {
+		// If the RHS of a LEFT JOIN is spread across shards, every
+		// shard would return the unmatched rows of the LHS.
+		if ajoin.Join == sqlparser.LeftJoinStr && !rRoute.IsSingle() {
+			return newJoin(rb, rRoute, ajoin)
+		}
+		rb.updateRoute(rRoute.ERoute.Opcode, rRoute.ERoute.Vindex, rRoute.ERoute.Values)
+		return rb.merge(rRoute, ajoin)
+	}
+
 	// Both route are sharded routes. Analyze join condition for merging.
 	for _, filter := range splitAndExpression(nil, ajoin.On) {
 		if rb.isSameRoute(rRoute, filter) {
@@ -522,6 +537,25 @@ func (rb *route) generateFieldQuery(sel *sqlparser.Select, jt *jointab) string {
 	return buf.ParsedQuery().Query
 }
 
+// pushWeightString adds the weight_string of the select expression
+// colnum to the select expressions, and returns its column number.
+// Like SupplyCol, it doesn't go through PushSelect, which doesn't
+// accept expressions for the right side of a left join.
+func (rb *route) pushWeightString(colnum int) int {
+	expr := rb.Select.SelectExprs[colnum].(*sqlparser.NonStarExpr).Expr
+	rb.Colsyms = append(rb.Colsyms, newColsym(rb, rb.Symtab()))
+	rb.Select.SelectExprs = append(
+		rb.Select.SelectExprs,
+		&sqlparser.NonStarExpr{
+			Expr: &sqlparser.FuncExpr{
+				Name:  "weight_string",
+				Exprs: sqlparser.SelectExprs{&sqlparser.NonStarExpr{Expr: expr}},
+			},
+		},
+	)
+	return len(rb.Colsyms) - 1
+}
+
 // SupplyVar should be unreachable.
 func (rb *route) SupplyVar(from, to int, col *sqlparser.ColName, varname string) {
 	panic("unreachable")
@@ -556,5 +590,9 @@ func (rb *route) SupplyCol(ref colref) int {
 
 // IsSingle returns true if the route targets only one database.
 func (rb *route) IsSingle() bool {
-	return rb.ERoute.Opcode == engine.SelectUnsharded || rb.ERoute.Opcode == engine.SelectEqualUnique
+	switch rb.ERoute.Opcode {
+	case engine.SelectUnsharded, engine.SelectEqualUnique, engine.SelectReference:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,21 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package planbuilder
+
+import (
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/vtgate/engine"
+)
+
+// buildSavepointPlan builds the instructions for a SAVEPOINT,
+// ROLLBACK TO SAVEPOINT or RELEASE SAVEPOINT statement. They don't
+// reference any table, and are sent to the shards of the transaction.
+func buildSavepointPlan(sp *sqlparser.Savepoint) *engine.Savepoint {
+	return &engine.Savepoint{
+		Action: sp.Action,
+		Name:   sp.Name.Original(),
+		Query:  sqlparser.String(sp),
+	}
+}
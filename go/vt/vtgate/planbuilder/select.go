@@ -6,6 +6,7 @@ package planbuilder
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/youtube/vitess/go/vt/sqlparser"
 	"github.com/youtube/vitess/go/vt/vtgate/engine"
@@ -19,6 +20,7 @@ func buildSelectPlan(sel *sqlparser.Select, vschema VSchema) (primitive engine.P
 	if err != nil {
 		return nil, err
 	}
+	pushWeightStrings(builder, true)
 	jt := newJointab(bindvars)
 	err = builder.Wireup(builder, jt)
 	if err != nil {
@@ -57,11 +59,14 @@ func processSelect(sel *sqlparser.Select, vschema VSchema, outer builder) (build
 			return nil, err
 		}
 	}
-	err = pushSelectExprs(sel, bldr)
+	bldr, err = pushSelectExprs(sel, bldr)
 	if err != nil {
 		return nil, err
 	}
 	if sel.Having != nil {
+		if _, ok := bldr.(*orderedAggregate); ok {
+			return nil, errors.New("unsupported: having with scatter aggregates")
+		}
 		err = pushFilter(sel.Having.Expr, bldr, sqlparser.HavingStr)
 		if err != nil {
 			return nil, err
@@ -71,7 +76,7 @@ func processSelect(sel *sqlparser.Select, vschema VSchema, outer builder) (build
 	if err != nil {
 		return nil, err
 	}
-	err = pushLimit(sel.Limit, bldr)
+	bldr, err = pushLimit(sel.Limit, bldr)
 	if err != nil {
 		return nil, err
 	}
@@ -119,11 +124,13 @@ func reorderBySubquery(filters []sqlparser.BoolExpr) {
 }
 
 // pushSelectExprs identifies the target route for the
-// select expressions and pushes them down.
-func pushSelectExprs(sel *sqlparser.Select, bldr builder) error {
-	err := checkAggregates(sel, bldr)
+// select expressions and pushes them down. If the aggregates
+// or the group by can't be fully pushed down, a new builder
+// is returned that combines the results of the shards.
+func pushSelectExprs(sel *sqlparser.Select, bldr builder) (builder, error) {
+	needsMerge, err := checkAggregates(sel, bldr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if sel.Distinct != "" {
 		// We know it's a route, but this may change
@@ -132,85 +139,84 @@ func pushSelectExprs(sel *sqlparser.Select, bldr builder) error {
 	}
 	colsyms, err := pushSelectRoutes(sel.SelectExprs, bldr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	bldr.Symtab().Colsyms = colsyms
-	err = pushGroupBy(sel.GroupBy, bldr)
-	if err != nil {
-		return err
-	}
-	return nil
+	return pushGroupBy(sel, bldr, needsMerge)
 }
 
 // checkAggregates returns an error if the select statement
 // has aggregates that cannot be pushed down due to a complex
-// plan.
-func checkAggregates(sel *sqlparser.Select, bldr builder) error {
-	hasAggregates := false
-	if sel.Distinct != "" {
-		hasAggregates = true
-	} else {
-		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
-			switch node := node.(type) {
-			case *sqlparser.FuncExpr:
-				if node.IsAggregate() {
-					hasAggregates = true
-					return false, errors.New("dummy")
-				}
-			}
-			return true, nil
-		}, sel.SelectExprs)
-	}
-	if !hasAggregates {
-		return nil
+// plan. It returns true if the aggregates are for a scatter
+// route and must be merged by VTGate.
+func checkAggregates(sel *sqlparser.Select, bldr builder) (needsMerge bool, err error) {
+	if sel.Distinct == "" && !hasAggregates(sel.SelectExprs) {
+		return false, nil
 	}
 
 	// Check if we can allow aggregates.
 	rb, ok := bldr.(*route)
 	if !ok {
-		return errors.New("unsupported: complex join with aggregates")
+		return false, errors.New("unsupported: complex join with aggregates")
 	}
 	if rb.IsSingle() {
-		return nil
+		return false, nil
 	}
-	// It's a scatter rb. We can allow aggregates if there is a unique
-	// vindex in the select list.
+	// It's a scatter rb. The aggregates can be pushed down if
+	// there is a unique vindex in the select list.
 	for _, selectExpr := range sel.SelectExprs {
 		switch selectExpr := selectExpr.(type) {
 		case *sqlparser.NonStarExpr:
 			vindex := bldr.Symtab().Vindex(selectExpr.Expr, rb, true)
 			if vindex != nil && vindexes.IsUnique(vindex) {
-				return nil
+				return false, nil
 			}
 		}
 	}
-	return errors.New("unsupported: scatter with aggregates")
+	return true, nil
 }
 
 // pusheSelectRoutes is a convenience function that pushes all the select
 // expressions and returns the list of colsyms generated for it.
 func pushSelectRoutes(selectExprs sqlparser.SelectExprs, bldr builder) ([]*colsym, error) {
-	colsyms := make([]*colsym, len(selectExprs))
-	for i, node := range selectExprs {
+	colsyms := make([]*colsym, 0, len(selectExprs))
+	for _, node := range selectExprs {
 		switch node := node.(type) {
 		case *sqlparser.NonStarExpr:
 			rb, err := findRoute(node.Expr, bldr)
 			if err != nil {
 				return nil, err
 			}
-			colsyms[i], _, err = bldr.PushSelect(node, rb)
+			colsym, _, err := bldr.PushSelect(node, rb)
 			if err != nil {
 				return nil, err
 			}
+			colsyms = append(colsyms, colsym)
 		case *sqlparser.StarExpr:
 			// We'll allow select * for simple routes.
-			rb, ok := bldr.(*route)
-			if !ok {
-				return nil, errors.New("unsupported: '*' expression in complex join")
+			if rb, ok := bldr.(*route); ok {
+				// We can push without validating the reference because
+				// MySQL will fail if it's invalid.
+				colsyms = append(colsyms, rb.PushStar(node))
+				continue
+			}
+			// For complex joins, the '*' is expanded into
+			// the columns of the tables, if they are known.
+			exprs, err := expandStar(node, bldr.Symtab())
+			if err != nil {
+				return nil, err
+			}
+			for _, expr := range exprs {
+				rb, err := findRoute(expr.Expr, bldr)
+				if err != nil {
+					return nil, err
+				}
+				colsym, _, err := bldr.PushSelect(expr, rb)
+				if err != nil {
+					return nil, err
+				}
+				colsyms = append(colsyms, colsym)
 			}
-			// We can push without validating the reference because
-			// MySQL will fail if it's invalid.
-			colsyms[i] = rb.PushStar(node)
 		case sqlparser.Nextval:
 			// For now, this is only supported as an implicit feature
 			// for auto_inc in inserts.
@@ -219,3 +225,33 @@ func pushSelectRoutes(selectExprs sqlparser.SelectExprs, bldr builder) ([]*colsy
 	}
 	return colsyms, nil
 }
+
+// expandStar expands a '*' expression into the columns of the tables
+// it refers to. It fails if the columns of one of these tables are
+// not known.
+func expandStar(star *sqlparser.StarExpr, st *symtab) ([]*sqlparser.NonStarExpr, error) {
+	tables := st.tables
+	if star.TableName != "" {
+		t := st.findTable(star.TableName)
+		if t == nil {
+			return nil, fmt.Errorf("symbol %s not found", sqlparser.String(star))
+		}
+		tables = []*tabsym{t}
+	}
+	var exprs []*sqlparser.NonStarExpr
+	for _, t := range tables {
+		if t.Columns == nil {
+			return nil, errors.New("unsupported: '*' expression in complex join")
+		}
+		for _, col := range t.Columns {
+			exprs = append(exprs, &sqlparser.NonStarExpr{
+				Expr: &sqlparser.ColName{
+					Metadata:  t,
+					Qualifier: &sqlparser.TableName{Name: t.ASTName},
+					Name:      sqlparser.ColIdent(col),
+				},
+			})
+		}
+	}
+	return exprs, nil
+}
@@ -71,6 +71,7 @@ func (st *symtab) AddAlias(alias, astName sqlparser.TableIdent, table *vindexes.
 		symtab:         st,
 		Keyspace:       table.Keyspace,
 		ColumnVindexes: table.ColumnVindexes,
+		Columns:        table.Columns,
 	})
 }
 
@@ -119,8 +120,11 @@ func (st *symtab) SetRHS() {
 // it. Subsequent searches will reuse this meatadata.
 // If autoResolve is true, and there is only one table in the symbol table,
 // then an unqualified reference is assumed to be implicitly against
-// that table. The table info doesn't contain the full list of columns.
-// So, any column reference is presumed valid. If a Colsyms scope is
+// that table. If the table info doesn't contain the full list of columns,
+// any column reference is presumed valid. Otherwise, the columns are
+// validated, and unqualified references are also resolved if there are
+// multiple tables, or in outer scopes, as long as all their columns are
+// known. If a Colsyms scope is
 // present, then the table scope is not searched. If a symbol is found
 // in the current symtab, then isLocal is set to true. Otherwise, the
 // search is continued in the outer symtab. If so, isLocal will be set
@@ -172,11 +176,18 @@ func (st *symtab) Find(col *sqlparser.ColName, autoResolve bool) (rb *route, isL
 		}
 		return nil, false, fmt.Errorf("symbol %s not found", sqlparser.String(col))
 	}
-	qualifier := sqlparser.TableIdent(sqlparser.String(col.Qualifier))
-	if qualifier == "" && autoResolve && len(st.tables) == 1 {
-		qualifier = st.tables[0].Alias
+	var alias *tabsym
+	if col.Qualifier == nil {
+		alias, err = st.findUnqualified(col, autoResolve)
+		if err != nil {
+			return nil, false, err
+		}
+	} else {
+		alias = st.findTable(sqlparser.TableIdent(sqlparser.String(col.Qualifier)))
+		if alias != nil && !alias.HasColumn(col.Name) {
+			return nil, false, fmt.Errorf("symbol %s not found", sqlparser.String(col))
+		}
 	}
-	alias := st.findTable(qualifier)
 	if alias == nil {
 		if st.Outer != nil {
 			// autoResolve only allowed for innermost scope.
@@ -192,6 +203,35 @@ func (st *symtab) Find(col *sqlparser.ColName, autoResolve bool) (rb *route, isL
 	return alias.Route(), true, nil
 }
 
+// findUnqualified returns the table an unqualified column reference
+// refers to, or nil if it can't be resolved in this symtab. Without
+// autoResolve, a reference can only be resolved if the columns of
+// all the tables are known.
+func (st *symtab) findUnqualified(col *sqlparser.ColName, autoResolve bool) (*tabsym, error) {
+	if autoResolve && len(st.tables) == 1 {
+		if !st.tables[0].HasColumn(col.Name) {
+			return nil, nil
+		}
+		return st.tables[0], nil
+	}
+	var found *tabsym
+	for _, t := range st.tables {
+		if t.Columns == nil {
+			// The reference can't be resolved unless
+			// the columns of all the tables are known.
+			return nil, nil
+		}
+		if !t.HasColumn(col.Name) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("ambiguous symbol reference: %v", sqlparser.String(col))
+		}
+		found = t
+	}
+	return found, nil
+}
+
 // Vindex returns the vindex if the expression is a plain column reference
 // that is part of the specified route, and has an associated vindex.
 func (st *symtab) Vindex(expr sqlparser.Expr, scope *route, autoResolve bool) vindexes.Vindex {
@@ -248,6 +288,9 @@ type tabsym struct {
 	symtab         *symtab
 	Keyspace       *vindexes.Keyspace
 	ColumnVindexes []*vindexes.ColumnVindex
+	// Columns is the list of columns of the table. It's nil
+	// if they are not known.
+	Columns []cistring.CIString
 }
 
 func (t *tabsym) newColRef(col *sqlparser.ColName) colref {
@@ -265,6 +308,20 @@ func (t *tabsym) Symtab() *symtab {
 	return t.symtab
 }
 
+// HasColumn returns true if the table has the column. If the
+// columns of the table are not known, it presumes it does.
+func (t *tabsym) HasColumn(name sqlparser.ColIdent) bool {
+	if t.Columns == nil {
+		return true
+	}
+	for _, col := range t.Columns {
+		if col.Equal(cistring.CIString(name)) {
+			return true
+		}
+	}
+	return false
+}
+
 // FindVindex returns the vindex if one was found for the column.
 func (t *tabsym) FindVindex(name sqlparser.ColIdent) vindexes.Vindex {
 	for _, colVindex := range t.ColumnVindexes {
@@ -24,6 +24,7 @@ import (
 	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/vtgate/engine"
 	"github.com/youtube/vitess/go/vt/vtgate/planbuilder"
+	"github.com/youtube/vitess/go/vt/vtgate/schematracker"
 	"github.com/youtube/vitess/go/vt/vtgate/vindexes"
 
 	vschemapb "github.com/youtube/vitess/go/vt/proto/vschema"
@@ -139,7 +140,8 @@ const (
 var plannerOnce sync.Once
 
 // NewPlanner creates a new planner for VTGate.
-// It will watch the vschema in the topology until the ctx is closed.
+// It will watch the vschema in the topology, and the schema changes
+// reported by the schematracker, until the ctx is closed.
 func NewPlanner(ctx context.Context, serv topo.SrvTopoServer, cell string, cacheSize int) *Planner {
 	plr := &Planner{
 		serv:  serv,
@@ -147,6 +149,12 @@ func NewPlanner(ctx context.Context, serv topo.SrvTopoServer, cell string, cache
 		plans: cache.NewLRUCache(int64(cacheSize)),
 	}
 	plr.WatchSrvVSchema(ctx, cell)
+	// The plans depend on the columns of the tables.
+	unregister := schematracker.OnChange(plr.plans.Clear)
+	go func() {
+		<-ctx.Done()
+		unregister()
+	}()
 	plannerOnce.Do(func() {
 		http.Handle("/debug/query_plans", plr)
 		http.Handle("/debug/vschema", plr)
@@ -353,5 +361,15 @@ func (vs *wrappedVSchema) Find(keyspace, tablename string) (table *vindexes.Tabl
 	if keyspace == "" {
 		keyspace = vs.keyspace
 	}
-	return vs.vschema.Find(keyspace, tablename)
+	table, err = vs.vschema.Find(keyspace, tablename)
+	if err != nil {
+		return nil, err
+	}
+	if columns := schematracker.Columns(table.Keyspace.Name, table.Name); columns != nil {
+		// The VSchema is shared, so we return a copy.
+		withColumns := *table
+		withColumns.Columns = columns
+		return &withColumns, nil
+	}
+	return table, nil
 }
@@ -0,0 +1,257 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vtgate
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+
+	log "github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/mysqlconn"
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/callerid"
+	"github.com/youtube/vitess/go/vt/servenv"
+	"github.com/youtube/vitess/go/vt/sqlparser"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+	vtgatepb "github.com/youtube/vitess/go/vt/proto/vtgate"
+)
+
+var (
+	mysqlServerPort             = flag.Int("mysql_server_port", 0, "If set, also listen for MySQL binary protocol connections on this port.")
+	mysqlAuthServerConfigFile   = flag.String("mysql_auth_server_config_file", "", "JSON File to read the users/passwords from.")
+	mysqlAuthServerConfigString = flag.String("mysql_auth_server_config_string", "", "JSON representation of the users/passwords config.")
+	mysqlAuthServerAllowNone    = flag.Bool("mysql_auth_server_allow_none", false, "If set, and no mysql_auth_server_config_file or mysql_auth_server_config_string is specified, the MySQL protocol accepts all users without authentication. The user names are then only trusted as effective caller ids: the immediate caller id is unauthenticated. Insecure, for tests only.")
+	mysqlDefaultTabletType      = flag.String("mysql_default_tablet_type", "master", "The default tablet type to use for MySQL protocol connections, when the database name doesn't specify one.")
+	mysqlStreamSelects          = flag.Bool("mysql_server_stream_selects", false, "If set, selects sent over the MySQL protocol outside of a transaction are executed with StreamExecute.")
+)
+
+// vtgateHandler implements the mysqlconn.Handler interface on top
+// of a VTGate. Each connection gets its own vtgatepb.Session, stored
+// in the ClientData field of the connection.
+type vtgateHandler struct {
+	vtg *VTGate
+
+	// authenticated is false if the MySQL users are accepted
+	// without authentication.
+	authenticated bool
+}
+
+func newVtgateHandler(vtg *VTGate) *vtgateHandler {
+	return &vtgateHandler{
+		vtg:           vtg,
+		authenticated: true,
+	}
+}
+
+func (vh *vtgateHandler) NewConnection(c *mysqlconn.Conn) {
+	c.StatusFlags |= mysqlconn.ServerStatusAutocommit
+	c.ClientData = &vtgatepb.Session{}
+}
+
+func (vh *vtgateHandler) ConnectionClosed(c *mysqlconn.Conn) {
+	// Rollback if there is an ongoing transaction. Ignore error.
+	session, _ := c.ClientData.(*vtgatepb.Session)
+	if session == nil || !session.InTransaction {
+		return
+	}
+	ctx := vh.callerIDContext(c)
+	if err := vh.vtg.Rollback(ctx, session); err != nil {
+		log.Warningf("Rollback on closed MySQL connection %v failed: %v", c.ConnectionID, err)
+	}
+}
+
+// ComQuery is part of the mysqlconn.Handler interface.
+func (vh *vtgateHandler) ComQuery(c *mysqlconn.Conn, query string, callback func(*sqltypes.Result) error) error {
+	ctx := vh.callerIDContext(c)
+	session, _ := c.ClientData.(*vtgatepb.Session)
+	if session == nil {
+		session = &vtgatepb.Session{}
+		c.ClientData = session
+	}
+
+	// Transaction statements are handled here, the
+	// session is never sent to the router for those.
+	switch strings.ToLower(strings.TrimRight(strings.TrimSpace(query), ";")) {
+	case "begin", "start transaction":
+		return vh.begin(ctx, c, session, callback)
+	case "commit":
+		return vh.commit(ctx, c, session, callback)
+	case "rollback":
+		return vh.rollback(ctx, c, session, callback)
+	}
+
+	keyspace, tabletType, err := parseMySQLTarget(c.SchemaName)
+	if err != nil {
+		return err
+	}
+
+	if *mysqlStreamSelects && !session.InTransaction && isSelect(query) {
+		return vh.vtg.StreamExecute(ctx, query, nil, keyspace, tabletType, &querypb.ExecuteOptions{}, callback)
+	}
+
+	result, err := vh.vtg.Execute(ctx, query, nil, keyspace, tabletType, session, false, &querypb.ExecuteOptions{})
+	if err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// isSelect returns true if the query parses as a select statement.
+func isSelect(query string) bool {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return false
+	}
+	_, ok := stmt.(sqlparser.SelectStatement)
+	return ok
+}
+
+// begin starts a new transaction. As MySQL does, an ongoing
+// transaction is committed first.
+func (vh *vtgateHandler) begin(ctx context.Context, c *mysqlconn.Conn, session *vtgatepb.Session, callback func(*sqltypes.Result) error) error {
+	if session.InTransaction {
+		if err := vh.commit(ctx, c, session, func(*sqltypes.Result) error { return nil }); err != nil {
+			return err
+		}
+	}
+	newSession, err := vh.vtg.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	c.ClientData = newSession
+	c.StatusFlags |= mysqlconn.ServerStatusInTrans
+	return callback(&sqltypes.Result{})
+}
+
+func (vh *vtgateHandler) commit(ctx context.Context, c *mysqlconn.Conn, session *vtgatepb.Session, callback func(*sqltypes.Result) error) error {
+	c.ClientData = &vtgatepb.Session{}
+	c.StatusFlags &^= mysqlconn.ServerStatusInTrans
+	if session.InTransaction {
+		if err := vh.vtg.Commit(ctx, session); err != nil {
+			return err
+		}
+	}
+	return callback(&sqltypes.Result{})
+}
+
+func (vh *vtgateHandler) rollback(ctx context.Context, c *mysqlconn.Conn, session *vtgatepb.Session, callback func(*sqltypes.Result) error) error {
+	c.ClientData = &vtgatepb.Session{}
+	c.StatusFlags &^= mysqlconn.ServerStatusInTrans
+	if session.InTransaction {
+		if err := vh.vtg.Rollback(ctx, session); err != nil {
+			return err
+		}
+	}
+	return callback(&sqltypes.Result{})
+}
+
+// unauthenticatedUser is the immediate caller id of the MySQL
+// connections when the users are not authenticated.
+const unauthenticatedUser = "unauthenticated"
+
+// callerIDContext returns a context carrying the MySQL user as both
+// the immediate and effective caller ids. If the users are not
+// authenticated, the immediate caller id is unauthenticatedUser, so
+// the table ACLs don't trust the user name sent by the client.
+func (vh *vtgateHandler) callerIDContext(c *mysqlconn.Conn) context.Context {
+	immediate := c.User
+	if !vh.authenticated {
+		immediate = unauthenticatedUser
+	}
+	return callerid.NewContext(context.Background(),
+		callerid.NewEffectiveCallerID(c.User, "" /* component */, "" /* subcomponent */),
+		callerid.NewImmediateCallerID(immediate))
+}
+
+// parseMySQLTarget parses the database name sent by a MySQL client.
+// It can be either 'keyspace' or 'keyspace@tablet_type'. If the
+// tablet type is not specified, mysql_default_tablet_type is used.
+func parseMySQLTarget(dbname string) (string, topodatapb.TabletType, error) {
+	keyspace := dbname
+	tabletType := *mysqlDefaultTabletType
+	if last := strings.LastIndexByte(dbname, '@'); last != -1 {
+		keyspace = dbname[:last]
+		tabletType = dbname[last+1:]
+	}
+	tt, err := topoproto.ParseTabletType(tabletType)
+	if err != nil {
+		return "", topodatapb.TabletType_UNKNOWN, fmt.Errorf("invalid tablet type in database name %v: %v", dbname, err)
+	}
+	return keyspace, tt, nil
+}
+
+var mysqlListener *mysqlconn.Listener
+
+// initMySQLProtocol starts the mysql protocol.
+// It should be called only once in a process.
+func initMySQLProtocol() {
+	// Flag is not set, just return.
+	if *mysqlServerPort == 0 {
+		return
+	}
+
+	// If no VTGate was created, just return.
+	if rpcVTGate == nil {
+		return
+	}
+
+	vh := newVtgateHandler(rpcVTGate)
+
+	// Initialize the config for the auth server.
+	var authServer mysqlconn.AuthServer
+	var err error
+	switch {
+	case *mysqlAuthServerConfigFile != "" && *mysqlAuthServerConfigString != "":
+		log.Fatalf("Both mysql_auth_server_config_file and mysql_auth_server_config_string specified, can only use one.")
+	case *mysqlAuthServerConfigFile != "":
+		authServer, err = mysqlconn.NewAuthServerStaticFromFile(*mysqlAuthServerConfigFile)
+	case *mysqlAuthServerConfigString != "":
+		authServer, err = mysqlconn.NewAuthServerStaticFromString(*mysqlAuthServerConfigString)
+	case *mysqlAuthServerAllowNone:
+		log.Warningf("No mysql_auth_server_config_file or mysql_auth_server_config_string specified, the MySQL protocol will accept all users without authentication.")
+		authServer = &mysqlconn.AuthServerNone{}
+		vh.authenticated = false
+	default:
+		log.Fatalf("No mysql_auth_server_config_file or mysql_auth_server_config_string specified. Set mysql_auth_server_allow_none to accept all users without authentication.")
+	}
+	if err != nil {
+		log.Fatalf("Cannot initialize MySQL auth server: %v", err)
+	}
+
+	// Check the default tablet type now, so misconfigurations are
+	// reported at startup.
+	if _, err := topoproto.ParseTabletType(*mysqlDefaultTabletType); err != nil {
+		log.Fatalf("Invalid mysql_default_tablet_type %v: %v", *mysqlDefaultTabletType, err)
+	}
+
+	// Create a Listener.
+	mysqlListener, err = mysqlconn.NewListener("tcp", net.JoinHostPort("", fmt.Sprintf("%v", *mysqlServerPort)), authServer, vh)
+	if err != nil {
+		log.Fatalf("mysqlconn.NewListener failed: %v", err)
+	}
+
+	// And starts listening.
+	go func() {
+		mysqlListener.Accept()
+	}()
+}
+
+func init() {
+	servenv.OnRun(initMySQLProtocol)
+
+	servenv.OnTermSync(func() {
+		if mysqlListener != nil {
+			mysqlListener.Close()
+			mysqlListener = nil
+		}
+	})
+}
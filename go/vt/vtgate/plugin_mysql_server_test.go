@@ -0,0 +1,143 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vtgate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/youtube/vitess/go/mysqlconn"
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/callerid"
+	"github.com/youtube/vitess/go/vt/tabletserver/sandboxconn"
+
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+	vtgatepb "github.com/youtube/vitess/go/vt/proto/vtgate"
+)
+
+// This file uses the sandbox_test framework.
+
+func TestParseMySQLTarget(t *testing.T) {
+	testcases := []struct {
+		in         string
+		keyspace   string
+		tabletType topodatapb.TabletType
+		err        string
+	}{{
+		in:         "",
+		keyspace:   "",
+		tabletType: topodatapb.TabletType_MASTER,
+	}, {
+		in:         "ks",
+		keyspace:   "ks",
+		tabletType: topodatapb.TabletType_MASTER,
+	}, {
+		in:         "ks@replica",
+		keyspace:   "ks",
+		tabletType: topodatapb.TabletType_REPLICA,
+	}, {
+		in:         "ks@rdonly",
+		keyspace:   "ks",
+		tabletType: topodatapb.TabletType_RDONLY,
+	}, {
+		in:  "ks@bad",
+		err: "invalid tablet type in database name ks@bad: unknown TabletType bad",
+	}}
+	for _, tcase := range testcases {
+		keyspace, tabletType, err := parseMySQLTarget(tcase.in)
+		if tcase.err != "" {
+			if err == nil || err.Error() != tcase.err {
+				t.Errorf("parseMySQLTarget(%v) error: %v, want %v", tcase.in, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil || keyspace != tcase.keyspace || tabletType != tcase.tabletType {
+			t.Errorf("parseMySQLTarget(%v): %v, %v, %v, want %v, %v", tcase.in, keyspace, tabletType, err, tcase.keyspace, tcase.tabletType)
+		}
+	}
+}
+
+func TestCallerIDContext(t *testing.T) {
+	c := &mysqlconn.Conn{User: "user1"}
+	vh := newVtgateHandler(rpcVTGate)
+	ctx := vh.callerIDContext(c)
+	if got := callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(ctx)); got != "user1" {
+		t.Errorf("effective caller id: %v, want user1", got)
+	}
+	if got := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx)); got != "user1" {
+		t.Errorf("immediate caller id: %v, want user1", got)
+	}
+
+	// Without authentication, the user is not trusted.
+	vh.authenticated = false
+	ctx = vh.callerIDContext(c)
+	if got := callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(ctx)); got != "user1" {
+		t.Errorf("effective caller id: %v, want user1", got)
+	}
+	if got := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx)); got != unauthenticatedUser {
+		t.Errorf("immediate caller id: %v, want %v", got, unauthenticatedUser)
+	}
+}
+
+func TestVtgateHandlerTransactions(t *testing.T) {
+	createSandbox(KsTestUnsharded)
+	hcVTGateTest.Reset()
+	sbc := hcVTGateTest.AddTestTablet("aa", "1.1.1.1", 1001, KsTestUnsharded, "0", topodatapb.TabletType_MASTER, true, 1, nil)
+
+	vh := newVtgateHandler(rpcVTGate)
+	c := &mysqlconn.Conn{
+		User:       "user1",
+		SchemaName: KsTestUnsharded,
+	}
+	vh.NewConnection(c)
+
+	var results []*sqltypes.Result
+	callback := func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	}
+
+	if err := vh.ComQuery(c, "begin", callback); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	if c.StatusFlags&mysqlconn.ServerStatusInTrans == 0 {
+		t.Errorf("StatusFlags: %x, want ServerStatusInTrans set", c.StatusFlags)
+	}
+	if err := vh.ComQuery(c, "select id from t1", callback); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	if !reflect.DeepEqual(results[1], sandboxconn.SingleRowResult) {
+		t.Errorf("select: %v, want %v", results[1], sandboxconn.SingleRowResult)
+	}
+	session := c.ClientData.(*vtgatepb.Session)
+	if !session.InTransaction || len(session.ShardSessions) != 1 {
+		t.Errorf("session: %v, want one shard session in transaction", session)
+	}
+
+	if err := vh.ComQuery(c, "COMMIT;", callback); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if commitCount := sbc.CommitCount.Get(); commitCount != 1 {
+		t.Errorf("CommitCount: %d, want 1", commitCount)
+	}
+	if c.StatusFlags&mysqlconn.ServerStatusInTrans != 0 {
+		t.Errorf("StatusFlags: %x, want ServerStatusInTrans cleared", c.StatusFlags)
+	}
+	if session := c.ClientData.(*vtgatepb.Session); session.InTransaction {
+		t.Errorf("session after commit: %v, want no transaction", session)
+	}
+
+	// A closed connection rolls back its transaction.
+	if err := vh.ComQuery(c, "start transaction", callback); err != nil {
+		t.Fatalf("start transaction failed: %v", err)
+	}
+	if err := vh.ComQuery(c, "select id from t1", callback); err != nil {
+		t.Fatalf("select failed: %v", err)
+	}
+	vh.ConnectionClosed(c)
+	if rollbackCount := sbc.RollbackCount.Get(); rollbackCount != 1 {
+		t.Errorf("RollbackCount: %d, want 1", rollbackCount)
+	}
+}
@@ -61,3 +61,7 @@ func (vc *requestContext) StreamExecuteRoute(route *engine.Route, joinvars map[s
 func (vc *requestContext) GetRouteFields(route *engine.Route, joinvars map[string]interface{}) (*sqltypes.Result, error) {
 	return vc.router.GetRouteFields(vc, route, joinvars)
 }
+
+func (vc *requestContext) ExecuteSavepoint(sp *engine.Savepoint) (*sqltypes.Result, error) {
+	return vc.router.ExecuteSavepoint(vc, sp)
+}
@@ -5,12 +5,17 @@
 package vtgate
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/youtube/vitess/go/sqltypes"
 	"github.com/youtube/vitess/go/vt/sqlannotation"
+	"github.com/youtube/vitess/go/vt/sqlparser"
 	"github.com/youtube/vitess/go/vt/topo"
 	"github.com/youtube/vitess/go/vt/vtgate/engine"
 	"github.com/youtube/vitess/go/vt/vtgate/vindexes"
@@ -21,6 +26,21 @@ import (
 	vtgatepb "github.com/youtube/vitess/go/vt/proto/vtgate"
 )
 
+// These are the possible values for the multi_shard_dml flag.
+const (
+	// multiShardDMLAllow allows multi-shard DMLs in any context.
+	multiShardDMLAllow = "allow"
+	// multiShardDMLTransaction requires multi-shard
+	// DMLs to be executed within a transaction.
+	multiShardDMLTransaction = "transaction"
+	// multiShardDMLTwoPC is like multiShardDMLTransaction,
+	// and additionally commits transactions that span
+	// multiple shards using the 2PC protocol.
+	multiShardDMLTwoPC = "twopc"
+)
+
+var multiShardDML = flag.String("multi_shard_dml", multiShardDMLAllow, "policy for update, delete and insert statements that can affect multiple shards: allow, transaction (they must be executed within a transaction) or twopc (like transaction, and multi-shard transactions are committed with 2PC)")
+
 // Router is the layer to route queries to the correct shards
 // based on the values in the query.
 type Router struct {
@@ -95,8 +115,13 @@ func (rtr *Router) ExecuteRoute(vcursor *requestContext, route *engine.Route, jo
 		return rtr.execUpdateEqual(vcursor, route)
 	case engine.DeleteEqual:
 		return rtr.execDeleteEqual(vcursor, route)
+	case engine.UpdateIN, engine.UpdateScatter,
+		engine.DeleteIN, engine.DeleteScatter:
+		return rtr.execDMLMulti(vcursor, route)
 	case engine.InsertSharded:
 		return rtr.execInsertSharded(vcursor, route)
+	case engine.InsertSelect:
+		return rtr.execInsertSelect(vcursor, route)
 	}
 
 	var err error
@@ -111,6 +136,8 @@ func (rtr *Router) ExecuteRoute(vcursor *requestContext, route *engine.Route, jo
 		params, err = rtr.paramsSelectIN(vcursor, route)
 	case engine.SelectScatter:
 		params, err = rtr.paramsSelectScatter(vcursor, route)
+	case engine.SelectReference:
+		params, err = rtr.paramsSelectReference(vcursor, route)
 	default:
 		// TODO(sougou): improve error.
 		return nil, fmt.Errorf("unsupported query route: %v", route)
@@ -118,7 +145,7 @@ func (rtr *Router) ExecuteRoute(vcursor *requestContext, route *engine.Route, jo
 	if err != nil {
 		return nil, err
 	}
-	return rtr.scatterConn.ExecuteMulti(
+	qr, err := rtr.scatterConn.ExecuteMulti(
 		vcursor.ctx,
 		route.Query+vcursor.comments,
 		params.ks,
@@ -128,6 +155,43 @@ func (rtr *Router) ExecuteRoute(vcursor *requestContext, route *engine.Route, jo
 		vcursor.notInTransaction,
 		vcursor.options,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if len(route.OrderBy) != 0 && len(params.shardVars) > 1 {
+		if err := engine.SortRows(qr.Rows, route.OrderBy); err != nil {
+			return nil, err
+		}
+	}
+	return qr, nil
+}
+
+// ExecuteSavepoint sends a savepoint statement to all the shards of the
+// transaction, and records the outcome in the session.
+func (rtr *Router) ExecuteSavepoint(vcursor *requestContext, sp *engine.Savepoint) (*sqltypes.Result, error) {
+	session := NewSafeSession(vcursor.session)
+	if !session.InTransaction() {
+		return nil, errors.New("savepoint statements are only allowed within a transaction")
+	}
+	if sp.Action != sqlparser.SavepointStr && !session.HasSavepoint(sp.Name) {
+		return nil, fmt.Errorf("savepoint %s does not exist", sp.Name)
+	}
+	if err := rtr.scatterConn.ExecuteSavepoint(vcursor.ctx, sp.Query, session); err != nil {
+		return nil, err
+	}
+	var err error
+	switch sp.Action {
+	case sqlparser.SavepointStr:
+		session.SetSavepoint(sp.Name)
+	case sqlparser.RollbackToStr:
+		err = session.RollbackToSavepoint(sp.Name)
+	case sqlparser.ReleaseStr:
+		err = session.ReleaseSavepoint(sp.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{}, nil
 }
 
 func copyBindVars(bindVars map[string]interface{}) map[string]interface{} {
@@ -182,12 +246,17 @@ func (rtr *Router) StreamExecuteRoute(vcursor *requestContext, route *engine.Rou
 		params, err = rtr.paramsSelectIN(vcursor, route)
 	case engine.SelectScatter:
 		params, err = rtr.paramsSelectScatter(vcursor, route)
+	case engine.SelectReference:
+		params, err = rtr.paramsSelectReference(vcursor, route)
 	default:
 		return fmt.Errorf("query %q cannot be used for streaming", route.Query)
 	}
 	if err != nil {
 		return err
 	}
+	if len(route.OrderBy) != 0 && len(params.shardVars) > 1 {
+		return rtr.streamMergeSort(vcursor, route, params, sendReply)
+	}
 	return rtr.scatterConn.StreamExecuteMulti(
 		vcursor.ctx,
 		route.Query+vcursor.comments,
@@ -199,6 +268,27 @@ func (rtr *Router) StreamExecuteRoute(vcursor *requestContext, route *engine.Rou
 	)
 }
 
+// streamMergeSort streams the results of each shard separately,
+// and merge-sorts them as specified by the OrderBy of the route.
+func (rtr *Router) streamMergeSort(vcursor *requestContext, route *engine.Route, params *scatterParams, sendReply func(*sqltypes.Result) error) error {
+	streams := make([]engine.StreamFunc, 0, len(params.shardVars))
+	for shard, vars := range params.shardVars {
+		shardVars := map[string]map[string]interface{}{shard: vars}
+		streams = append(streams, func(sendReply func(*sqltypes.Result) error) error {
+			return rtr.scatterConn.StreamExecuteMulti(
+				vcursor.ctx,
+				route.Query+vcursor.comments,
+				params.ks,
+				shardVars,
+				vcursor.tabletType,
+				vcursor.options,
+				sendReply,
+			)
+		})
+	}
+	return engine.MergeSort(streams, route.OrderBy, sendReply)
+}
+
 // IsKeyspaceRangeBasedSharded returns true if the keyspace in the vschema is
 // marked as sharded.
 func (rtr *Router) IsKeyspaceRangeBasedSharded(keyspace string) bool {
@@ -267,6 +357,14 @@ func (rtr *Router) paramsSelectScatter(vcursor *requestContext, route *engine.Ro
 	return newScatterParams(ks, vcursor.bindVars, shards), nil
 }
 
+func (rtr *Router) paramsSelectReference(vcursor *requestContext, route *engine.Route) (*scatterParams, error) {
+	ks, shard, err := getAnyShard(vcursor.ctx, rtr.serv, rtr.cell, route.Keyspace.Name, vcursor.tabletType)
+	if err != nil {
+		return nil, fmt.Errorf("paramsSelectReference: %v", err)
+	}
+	return newScatterParams(ks, vcursor.bindVars, []string{shard}), nil
+}
+
 func (rtr *Router) execUpdateEqual(vcursor *requestContext, route *engine.Route) (*sqltypes.Result, error) {
 	keys, err := rtr.resolveKeys([]interface{}{route.Values}, vcursor.bindVars)
 	if err != nil {
@@ -323,10 +421,69 @@ func (rtr *Router) execDeleteEqual(vcursor *requestContext, route *engine.Route)
 		vcursor.options)
 }
 
+// execDMLMulti executes an update or delete that can
+// affect multiple shards. If the table has owned vindexes,
+// their entries are deleted for all rows that will be deleted.
+func (rtr *Router) execDMLMulti(vcursor *requestContext, route *engine.Route) (*sqltypes.Result, error) {
+	if *multiShardDML != multiShardDMLAllow && !NewSafeSession(vcursor.session).InTransaction() {
+		return nil, errors.New("multi-shard DML is only allowed within a transaction")
+	}
+	var params *scatterParams
+	var err error
+	switch route.Opcode {
+	case engine.UpdateIN, engine.DeleteIN:
+		params, err = rtr.paramsDMLIN(vcursor, route)
+	default:
+		params, err = rtr.paramsSelectScatter(vcursor, route)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("execDMLMulti: %v", err)
+	}
+	if len(params.shardVars) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+	if route.Subquery != "" {
+		if err := rtr.deleteVindexEntriesMulti(vcursor, route, params); err != nil {
+			return nil, fmt.Errorf("execDMLMulti: %v", err)
+		}
+	}
+	// The keyspace ids of the affected rows are unknown.
+	rewritten := sqlannotation.AnnotateIfDML(route.Query, nil) + vcursor.comments
+	return rtr.scatterConn.ExecuteMulti(
+		vcursor.ctx,
+		rewritten,
+		params.ks,
+		params.shardVars,
+		vcursor.tabletType,
+		NewSafeSession(vcursor.session),
+		vcursor.notInTransaction,
+		vcursor.options,
+	)
+}
+
+// paramsDMLIN returns the shards that own the values of the
+// route. Unlike paramsSelectIN, the query is not rewritten to
+// use per-shard lists. So, the bind vars are not changed.
+func (rtr *Router) paramsDMLIN(vcursor *requestContext, route *engine.Route) (*scatterParams, error) {
+	vals, err := rtr.resolveList(route.Values, vcursor.bindVars)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := rtr.resolveKeys(vals, vcursor.bindVars)
+	if err != nil {
+		return nil, err
+	}
+	ks, routing, err := rtr.resolveShards(vcursor, keys, route)
+	if err != nil {
+		return nil, err
+	}
+	return newScatterParams(ks, vcursor.bindVars, routing.Shards()), nil
+}
+
 func (rtr *Router) execInsertSharded(vcursor *requestContext, route *engine.Route) (*sqltypes.Result, error) {
-	var firstKsid []byte
 	var firstAutoGenInsertID int64
 	inputs := route.Values.([]interface{})
+	ksids := make([][]byte, len(inputs))
 	for rowNum, input := range inputs {
 		insertid, err := rtr.handleGenerate(vcursor, route.Generate, rowNum)
 		if firstAutoGenInsertID == 0 && insertid != 0 {
@@ -340,41 +497,84 @@ func (rtr *Router) execInsertSharded(vcursor *requestContext, route *engine.Rout
 		if err != nil {
 			return nil, fmt.Errorf("execInsertSharded: %v", err)
 		}
-		for colNum := 0; colNum < len(keys); colNum++ {
-			if rowNum == 0 && colNum == 0 {
-				ksid, err := rtr.handlePrimary(vcursor, keys[0], route.Table.ColumnVindexes[0], vcursor.bindVars, rowNum)
-				if err != nil {
-					return nil, fmt.Errorf("execInsertSharded: %v", err)
-				}
-				firstKsid = ksid
-			} else {
-				err := rtr.handleNonPrimary(vcursor, keys[colNum], route.Table.ColumnVindexes[colNum], vcursor.bindVars, firstKsid, rowNum)
-				if err != nil {
-					return nil, fmt.Errorf("execInsertSharded: %v", err)
-				}
-			}
+		ksids[rowNum], err = rtr.handleRowVindexes(vcursor, route, keys, vcursor.bindVars, rowNum)
+		if err != nil {
+			return nil, fmt.Errorf("execInsertSharded: %v", err)
 		}
 	}
 
-	ks, shard, err := rtr.getRouting(vcursor.ctx, route.Keyspace.Name, vcursor.tabletType, firstKsid)
+	result, err := rtr.insertRows(vcursor, route, route.Mid, ksids)
 	if err != nil {
 		return nil, fmt.Errorf("execInsertSharded: %v", err)
 	}
+	if firstAutoGenInsertID != 0 {
+		if result.InsertID != 0 {
+			return nil, fmt.Errorf("sequence and db generated a value each for insert")
+		}
+		result.InsertID = uint64(firstAutoGenInsertID)
+	}
+	return result, nil
+}
 
-	rewritten := sqlannotation.AddKeyspaceID(route.Query, firstKsid, vcursor.comments)
-	result, err := rtr.scatterConn.Execute(
-		vcursor.ctx,
-		rewritten,
-		vcursor.bindVars,
-		ks,
-		[]string{shard},
-		vcursor.tabletType,
-		NewSafeSession(vcursor.session),
-		vcursor.notInTransaction,
-		vcursor.options)
+// execInsertSelect executes the Input of the route, and inserts
+// the returned rows. The values of the rows are encoded in the
+// insert statements. The column vindex and autoinc values that
+// are NULL are computed, like execInsertSharded does.
+func (rtr *Router) execInsertSelect(vcursor *requestContext, route *engine.Route) (*sqltypes.Result, error) {
+	input, err := route.Input.Execute(vcursor, make(map[string]interface{}), false)
+	if err != nil {
+		return nil, fmt.Errorf("execInsertSelect: %v", err)
+	}
+	if len(input.Rows) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+	var firstAutoGenInsertID int64
+	colNums := route.Values.([]interface{})
+	mids := make([]string, len(input.Rows))
+	ksids := make([][]byte, len(input.Rows))
+	for rowNum, inputRow := range input.Rows {
+		// Copy the row because it may be shared with the input.
+		row := make([]sqltypes.Value, len(inputRow))
+		copy(row, inputRow)
+		if route.Generate != nil {
+			colNum := route.Generate.Value.(int)
+			if row[colNum].IsNull() {
+				num, err := rtr.nextSequenceValue(vcursor, route.Generate)
+				if err != nil {
+					return nil, fmt.Errorf("execInsertSelect: %v", err)
+				}
+				if firstAutoGenInsertID == 0 {
+					firstAutoGenInsertID = num
+				}
+				row[colNum] = sqltypes.MakeTrusted(sqltypes.Int64, strconv.AppendInt(nil, num, 10))
+			}
+		}
+		keys := make([]interface{}, 0, len(colNums))
+		for _, colNum := range colNums {
+			keys = append(keys, row[colNum.(int)].ToNative())
+		}
+		bv := make(map[string]interface{})
+		ksids[rowNum], err = rtr.handleRowVindexes(vcursor, route, keys, bv, rowNum)
+		if err != nil {
+			return nil, fmt.Errorf("execInsertSelect: %v", err)
+		}
+		// Fill in the values computed by reverse mapping.
+		for i, colVindex := range route.Table.ColumnVindexes {
+			colNum := colNums[i].(int)
+			if !row[colNum].IsNull() {
+				continue
+			}
+			row[colNum], err = sqltypes.BuildValue(bv["_"+colVindex.Column.Original()+strconv.Itoa(rowNum)])
+			if err != nil {
+				return nil, fmt.Errorf("execInsertSelect: %v", err)
+			}
+		}
+		mids[rowNum] = encodeRow(row)
+	}
 
+	result, err := rtr.insertRows(vcursor, route, mids, ksids)
 	if err != nil {
-		return nil, fmt.Errorf("execInsertSharded: %v", err)
+		return nil, fmt.Errorf("execInsertSelect: %v", err)
 	}
 	if firstAutoGenInsertID != 0 {
 		if result.InsertID != 0 {
@@ -385,6 +585,100 @@ func (rtr *Router) execInsertSharded(vcursor *requestContext, route *engine.Rout
 	return result, nil
 }
 
+// encodeRow encodes the values of the row as a tuple
+// that can be used in the VALUES clause of an insert.
+func encodeRow(row []sqltypes.Value) string {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('(')
+	for i, v := range row {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		v.EncodeSQL(buf)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// handleRowVindexes computes the keyspace id of a row to be
+// inserted from its primary vindex value, and validates or
+// creates the values of the other vindexes. The vindex values
+// are saved in bv. keys must contain one value per ColVindex.
+func (rtr *Router) handleRowVindexes(vcursor *requestContext, route *engine.Route, keys []interface{}, bv map[string]interface{}, rowNum int) ([]byte, error) {
+	ksid, err := rtr.handlePrimary(vcursor, keys[0], route.Table.ColumnVindexes[0], bv, rowNum)
+	if err != nil {
+		return nil, err
+	}
+	for colNum := 1; colNum < len(keys); colNum++ {
+		err := rtr.handleNonPrimary(vcursor, keys[colNum], route.Table.ColumnVindexes[colNum], bv, ksid, rowNum)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ksid, nil
+}
+
+// insertRows groups the rows by shard, and sends one insert per
+// shard. mids and ksids contain the encoded values and keyspace
+// id of each row. The insert for a shard is annotated with the
+// keyspace id if all its rows have the same one.
+func (rtr *Router) insertRows(vcursor *requestContext, route *engine.Route, mids []string, ksids [][]byte) (*sqltypes.Result, error) {
+	ks, _, allShards, err := getKeyspaceShards(vcursor.ctx, rtr.serv, rtr.cell, route.Keyspace.Name, vcursor.tabletType)
+	if err != nil {
+		return nil, err
+	}
+	type batch struct {
+		mids  []string
+		ksids [][]byte
+	}
+	var shards []string
+	batches := make(map[string]*batch)
+	for i, ksid := range ksids {
+		shard, err := getShardForKeyspaceID(allShards, ksid)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := batches[shard]
+		if !ok {
+			b = &batch{}
+			batches[shard] = b
+			shards = append(shards, shard)
+		}
+		b.mids = append(b.mids, mids[i])
+		if len(b.ksids) == 0 || !bytes.Equal(b.ksids[0], ksid) {
+			b.ksids = append(b.ksids, ksid)
+		}
+	}
+	if len(shards) > 1 && *multiShardDML != multiShardDMLAllow && !NewSafeSession(vcursor.session).InTransaction() {
+		return nil, errors.New("multi-shard insert is only allowed within a transaction")
+	}
+	result := &sqltypes.Result{}
+	for _, shard := range shards {
+		b := batches[shard]
+		query := route.Prefix + strings.Join(b.mids, ", ") + route.Suffix
+		if len(b.ksids) == 1 {
+			query = sqlannotation.AddKeyspaceID(query, b.ksids[0], vcursor.comments)
+		} else {
+			query = sqlannotation.AnnotateIfDML(query, nil) + vcursor.comments
+		}
+		qr, err := rtr.scatterConn.Execute(
+			vcursor.ctx,
+			query,
+			vcursor.bindVars,
+			ks,
+			[]string{shard},
+			vcursor.tabletType,
+			NewSafeSession(vcursor.session),
+			vcursor.notInTransaction,
+			vcursor.options)
+		if err != nil {
+			return nil, err
+		}
+		appendResult(result, qr)
+	}
+	return result, nil
+}
+
 // resloveList returns a list of values, typically for an IN clause. If the input
 // is a bind var name, it uses the list provided in the bind var. If the input is
 // already a list, it returns just that.
@@ -504,9 +798,67 @@ func (rtr *Router) deleteVindexEntries(vcursor *requestContext, route *engine.Ro
 	if len(result.Rows) == 0 {
 		return nil
 	}
-	for i, colVindex := range route.Table.Owned {
+	return deleteLookupEntries(vcursor, route.Table.Owned, result.Rows, ksid)
+}
+
+// deleteVindexEntriesMulti fetches the rows that will be deleted
+// from all target shards, and deletes their owned vindex entries.
+// The last column of the Subquery is the primary vindex column.
+// It's used to compute the keyspace id of each row.
+func (rtr *Router) deleteVindexEntriesMulti(vcursor *requestContext, route *engine.Route, params *scatterParams) error {
+	result, err := rtr.scatterConn.ExecuteMulti(
+		vcursor.ctx,
+		route.Subquery,
+		params.ks,
+		params.shardVars,
+		vcursor.tabletType,
+		NewSafeSession(vcursor.session),
+		vcursor.notInTransaction,
+		vcursor.options)
+	if err != nil {
+		return err
+	}
+	if len(result.Rows) == 0 {
+		return nil
+	}
+	primaryCol := len(route.Table.Owned)
+	primaryKeys := make([]interface{}, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		primaryKeys = append(primaryKeys, row[primaryCol].ToNative())
+	}
+	mapper := route.Table.ColumnVindexes[0].Vindex.(vindexes.Unique)
+	ksids, err := mapper.Map(vcursor, primaryKeys)
+	if err != nil {
+		return err
+	}
+	// Group the rows by keyspace id, preserving their order.
+	var order []string
+	groups := make(map[string][][]sqltypes.Value)
+	for i, ksid := range ksids {
+		if len(ksid) == 0 {
+			return fmt.Errorf("could not map %v to a keyspace id", primaryKeys[i])
+		}
+		key := string(ksid)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], result.Rows[i])
+	}
+	for _, key := range order {
+		if err := deleteLookupEntries(vcursor, route.Table.Owned, groups[key], []byte(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteLookupEntries deletes the entries of the owned vindexes
+// for the rows, which must all have the same keyspace id. The
+// columns of the rows must match the owned vindexes.
+func deleteLookupEntries(vcursor *requestContext, owned []*vindexes.ColumnVindex, rows [][]sqltypes.Value, ksid []byte) error {
+	for i, colVindex := range owned {
 		keys := make(map[interface{}]bool)
-		for _, row := range result.Rows {
+		for _, row := range rows {
 			switch k := row[i].ToNative().(type) {
 			case []byte:
 				keys[string(k)] = true
@@ -520,7 +872,7 @@ func (rtr *Router) deleteVindexEntries(vcursor *requestContext, route *engine.Ro
 		}
 		switch vindex := colVindex.Vindex.(type) {
 		case vindexes.Lookup:
-			if err = vindex.Delete(vcursor, ids, ksid); err != nil {
+			if err := vindex.Delete(vcursor, ids, ksid); err != nil {
 				return err
 			}
 		default:
@@ -545,10 +897,21 @@ func (rtr *Router) handleGenerate(vcursor *requestContext, gen *engine.Generate,
 		vcursor.bindVars[engine.SeqVarName+strconv.Itoa(rowNum)] = val
 		return 0, nil
 	}
+	num, err := rtr.nextSequenceValue(vcursor, gen)
+	if err != nil {
+		return 0, fmt.Errorf("handleGenerate: %v", err)
+	}
+	vcursor.bindVars[engine.SeqVarName+strconv.Itoa(rowNum)] = num
+	return num, nil
+}
+
+// nextSequenceValue fetches the next value from the sequence
+// of the Generate subplan.
+func (rtr *Router) nextSequenceValue(vcursor *requestContext, gen *engine.Generate) (int64, error) {
 	// TODO(sougou): This is similar to paramsUnsharded.
 	ks, _, allShards, err := getKeyspaceShards(vcursor.ctx, rtr.serv, rtr.cell, gen.Keyspace.Name, vcursor.tabletType)
 	if err != nil {
-		return 0, fmt.Errorf("handleGenerate: %v", err)
+		return 0, err
 	}
 	if len(allShards) != 1 {
 		return 0, fmt.Errorf("unsharded keyspace %s has multiple shards", ks)
@@ -571,12 +934,7 @@ func (rtr *Router) handleGenerate(vcursor *requestContext, gen *engine.Generate,
 	}
 	// If no rows are returned, it's an internal error, and the code
 	// must panic, which will caught and reported.
-	num, err := qr.Rows[0][0].ParseInt64()
-	if err != nil {
-		return 0, err
-	}
-	vcursor.bindVars[engine.SeqVarName+strconv.Itoa(rowNum)] = num
-	return num, nil
+	return qr.Rows[0][0].ParseInt64()
 }
 
 func (rtr *Router) handlePrimary(vcursor *requestContext, vindexKey interface{}, colVindex *vindexes.ColumnVindex, bv map[string]interface{}, rowNum int) (ksid []byte, err error) {
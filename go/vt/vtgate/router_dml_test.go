@@ -6,6 +6,7 @@ package vtgate
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -14,7 +15,11 @@ import (
 	"github.com/youtube/vitess/go/vt/tabletserver/sandboxconn"
 	_ "github.com/youtube/vitess/go/vt/vtgate/vindexes"
 
+	"golang.org/x/net/context"
+
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+	vtgatepb "github.com/youtube/vitess/go/vt/proto/vtgate"
 )
 
 func TestUpdateEqual(t *testing.T) {
@@ -291,6 +296,199 @@ func TestDeleteEqualFail(t *testing.T) {
 	s.ShardSpec = DefaultShardSpec
 }
 
+func TestUpdateIN(t *testing.T) {
+	router, sbc1, sbc2, _ := createRouterEnv()
+
+	_, err := routerExec(router, "update user set a = 2 where id in (1, 3)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "update user set a = 2 where id in (1, 3)/* vtgate:: filtered_replication_unfriendly */",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries: %+v, want %+v\n", sbc1.Queries, wantQueries)
+	}
+	if !reflect.DeepEqual(sbc2.Queries, wantQueries) {
+		t.Errorf("sbc2.Queries: %+v, want %+v\n", sbc2.Queries, wantQueries)
+	}
+
+	sbc1.Queries = nil
+	sbc2.Queries = nil
+	_, err = routerExec(router, "update user set a = 2 where id in ::vals", map[string]interface{}{
+		"vals": []interface{}{int64(1)},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql: "update user set a = 2 where id in ::vals/* vtgate:: filtered_replication_unfriendly */",
+		BindVariables: map[string]interface{}{
+			"vals": []interface{}{int64(1)},
+		},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries: %+v, want %+v\n", sbc1.Queries, wantQueries)
+	}
+	if sbc2.Queries != nil {
+		t.Errorf("sbc2.Queries: %+v, want nil\n", sbc2.Queries)
+	}
+}
+
+func TestUpdateScatter(t *testing.T) {
+	router, conns := createScatterOrderEnv()
+
+	_, err := routerExec(router, "update user set a = 2 where b = 1", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "update user set a = 2 where b = 1/* vtgate:: filtered_replication_unfriendly */",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+}
+
+func TestDeleteIN(t *testing.T) {
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
+
+	subqueryResult := func(name string, id int) *sqltypes.Result {
+		return &sqltypes.Result{
+			Fields: []*querypb.Field{
+				{"name", sqltypes.VarChar},
+				{"Id", sqltypes.Int64},
+			},
+			RowsAffected: 1,
+			Rows: [][]sqltypes.Value{{
+				sqltypes.MakeTrusted(sqltypes.VarChar, []byte(name)),
+				sqltypes.MakeTrusted(sqltypes.Int64, []byte(strconv.Itoa(id))),
+			}},
+		}
+	}
+	sbc1.SetResults([]*sqltypes.Result{subqueryResult("name1", 1)})
+	sbc2.SetResults([]*sqltypes.Result{subqueryResult("name3", 3)})
+	_, err := routerExec(router, "delete from user where id in (1, 3)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select name, Id from user where id in (1, 3) for update",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "delete from user where id in (1, 3)/* vtgate:: filtered_replication_unfriendly */",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries:\n%+v, want\n%+v\n", sbc1.Queries, wantQueries)
+	}
+	if !reflect.DeepEqual(sbc2.Queries, wantQueries) {
+		t.Errorf("sbc2.Queries:\n%+v, want\n%+v\n", sbc2.Queries, wantQueries)
+	}
+
+	// The order of the lookup deletes depends on
+	// the order in which the shards responded.
+	wantQueries = []querytypes.BoundQuery{{
+		Sql: "delete from name_user_map where name = :name and user_id = :user_id",
+		BindVariables: map[string]interface{}{
+			"user_id": int64(1),
+			"name":    "name1",
+		},
+	}, {
+		Sql: "delete from name_user_map where name = :name and user_id = :user_id",
+		BindVariables: map[string]interface{}{
+			"user_id": int64(3),
+			"name":    "name3",
+		},
+	}}
+	if len(sbclookup.Queries) != len(wantQueries) {
+		t.Fatalf("sbclookup.Queries:\n%+v, want\n%+v\n", sbclookup.Queries, wantQueries)
+	}
+	for _, want := range wantQueries {
+		found := false
+		for _, got := range sbclookup.Queries {
+			if reflect.DeepEqual(got, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("sbclookup.Queries:\n%+v, must contain\n%+v\n", sbclookup.Queries, want)
+		}
+	}
+}
+
+func TestDeleteScatter(t *testing.T) {
+	router, conns := createScatterOrderEnv()
+
+	// music_extra has no owned vindexes.
+	_, err := routerExec(router, "delete from music_extra where a = 1", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "delete from music_extra where a = 1/* vtgate:: filtered_replication_unfriendly */",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+}
+
+func TestDMLMultiTransaction(t *testing.T) {
+	defer func(saved string) { *multiShardDML = saved }(*multiShardDML)
+	*multiShardDML = multiShardDMLTransaction
+	router, sbc1, _, _ := createRouterEnv()
+
+	_, err := routerExec(router, "update user set a = 2 where id in (1, 3)", nil)
+	want := "multi-shard DML is only allowed within a transaction"
+	if err == nil || err.Error() != want {
+		t.Errorf("routerExec: %v, want %v", err, want)
+	}
+	if sbc1.Queries != nil {
+		t.Errorf("sbc1.Queries: %+v, want nil\n", sbc1.Queries)
+	}
+
+	// Single-shard DMLs are not affected.
+	_, err = routerExec(router, "update user set a = 2 where id = 1", nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	sbc1.Queries = nil
+	session := &vtgatepb.Session{InTransaction: true}
+	_, err = router.Execute(context.Background(), "update user set a = 2 where id in (1, 3)", nil, "", topodatapb.TabletType_MASTER, session, false, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(session.ShardSessions) != 2 {
+		t.Errorf("len(session.ShardSessions): %d, want 2", len(session.ShardSessions))
+	}
+}
+
+func TestDMLMultiFail(t *testing.T) {
+	router, _, _, _ := createRouterEnv()
+	s := getSandbox("TestRouter")
+
+	_, err := routerExec(router, "update user set a = 2 where id in ::aa", nil)
+	want := "execDMLMulti: could not find bind var ::aa"
+	if err == nil || err.Error() != want {
+		t.Errorf("routerExec: %v, want %v", err, want)
+	}
+
+	s.SrvKeyspaceMustFail = 1
+	_, err = routerExec(router, "delete from user where a = 1", nil)
+	want = "execDMLMulti: paramsSelectScatter: keyspace TestRouter fetch error: topo error GetSrvKeyspace"
+	if err == nil || err.Error() != want {
+		t.Errorf("routerExec: %v, want %v", err, want)
+	}
+}
+
 func TestInsertSharded(t *testing.T) {
 	router, sbc1, sbc2, sbclookup := createRouterEnv()
 
@@ -828,25 +1026,326 @@ func TestMultiInsertGenerator(t *testing.T) {
 	}
 }
 
+func TestMultiInsertShards(t *testing.T) {
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
+
+	_, err := routerExec(router, "insert into user(id, v, name) values (1, 1, 'myname1'),(3, 2, 'myname3')", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	bindVars := map[string]interface{}{
+		"_Id0":   int64(1),
+		"_name0": []byte("myname1"),
+		"__seq0": int64(1),
+		"_Id1":   int64(3),
+		"_name1": []byte("myname3"),
+		"__seq1": int64(3),
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "insert into user(id, v, name) values (:_Id0, 1, :_name0) /* vtgate:: keyspace_id:166b40b44aba4bd6 */",
+		BindVariables: bindVars,
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries:\n%+v, want\n%+v\n", sbc1.Queries, wantQueries)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql:           "insert into user(id, v, name) values (:_Id1, 2, :_name1) /* vtgate:: keyspace_id:4eb190c9a2fa169c */",
+		BindVariables: bindVars,
+	}}
+	if !reflect.DeepEqual(sbc2.Queries, wantQueries) {
+		t.Errorf("sbc2.Queries:\n%+v, want\n%+v\n", sbc2.Queries, wantQueries)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql: "insert into name_user_map(name, user_id) values (:name, :user_id)",
+		BindVariables: map[string]interface{}{
+			"name":    []byte("myname1"),
+			"user_id": int64(1),
+		},
+	}, {
+		Sql: "insert into name_user_map(name, user_id) values (:name, :user_id)",
+		BindVariables: map[string]interface{}{
+			"name":    []byte("myname3"),
+			"user_id": int64(3),
+		},
+	}}
+	if !reflect.DeepEqual(sbclookup.Queries, wantQueries) {
+		t.Errorf("sbclookup.Queries: \n%+v, want \n%+v", sbclookup.Queries, wantQueries)
+	}
+
+	// Rows with different keyspace ids in the same shard
+	// are sent as one batch.
+	sbc1.Queries = nil
+	sbc2.Queries = nil
+	_, err = routerExec(router, "insert into user_extra(user_id, extra) values (1, 'abc'),(2, 'xyz')", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql: "insert into user_extra(user_id, extra) values (:_user_id0, 'abc'), (:_user_id1, 'xyz')/* vtgate:: filtered_replication_unfriendly */",
+		BindVariables: map[string]interface{}{
+			"_user_id0": int64(1),
+			"_user_id1": int64(2),
+		},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries:\n%+v, want\n%+v\n", sbc1.Queries, wantQueries)
+	}
+	if sbc2.Queries != nil {
+		t.Errorf("sbc2.Queries: %+v, want nil\n", sbc2.Queries)
+	}
+}
+
+func TestMultiInsertTransaction(t *testing.T) {
+	defer func(saved string) { *multiShardDML = saved }(*multiShardDML)
+	*multiShardDML = multiShardDMLTransaction
+	router, sbc1, sbc2, _ := createRouterEnv()
+
+	_, err := routerExec(router, "insert into user(id, v, name) values (1, 1, 'myname1'),(3, 2, 'myname3')", nil)
+	want := "execInsertSharded: multi-shard insert is only allowed within a transaction"
+	if err == nil || err.Error() != want {
+		t.Errorf("routerExec: %v, want %v", err, want)
+	}
+	if sbc1.Queries != nil || sbc2.Queries != nil {
+		t.Errorf("sbc1.Queries: %+v, sbc2.Queries: %+v, want nil\n", sbc1.Queries, sbc2.Queries)
+	}
+
+	// Single-shard inserts are not affected.
+	_, err = routerExec(router, "insert into user_extra(user_id, extra) values (1, 'abc'),(2, 'xyz')", nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	session := &vtgatepb.Session{InTransaction: true}
+	_, err = router.Execute(context.Background(), "insert into user(id, v, name) values (1, 1, 'myname1'),(3, 2, 'myname3')", nil, "", topodatapb.TabletType_MASTER, session, false, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(session.ShardSessions) != 3 {
+		t.Errorf("len(session.ShardSessions): %d, want 3", len(session.ShardSessions))
+	}
+}
+
 func TestMultiInsertFail(t *testing.T) {
-	router, _, _, _ := createRouterEnv()
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
 
-	_, err := routerExec(router, "insert into user_extra(user_id, extra) values (1, 'abc'),(2, 'xyz')", nil)
-	want := "execInsertSharded: value 2 for column user_id does not map to keyspace id 166b40b44aba4bd6"
+	_, err := routerExec(router, "insert into user_extra(user_id, extra) values (1, 'abc'),(null, 'xyz')", nil)
+	want := "execInsertSharded: value must be supplied for column user_id"
 	if err == nil || !strings.HasPrefix(err.Error(), want) {
 		t.Errorf("routerExec: %v, want prefix %v", err, want)
 	}
 
-	_, err = routerExec(router, "insert into user(id, name) values (1, 'abc'),(2, 'xyz')", nil)
-	want = "execInsertSharded: value 2 for column Id does not map to keyspace id 166b40b44aba4bd6"
+	sbclookup.SetResults([]*sqltypes.Result{sandboxconn.SingleRowResult, {}})
+	_, err = routerExec(router, "insert into music_extra(user_id, music_id) values (1, 1),(3, 3)", nil)
+	want = "execInsertSharded: value 3 for column music_id does not map to keyspace id 4eb190c9a2fa169c"
 	if err == nil || !strings.HasPrefix(err.Error(), want) {
 		t.Errorf("routerExec: %v, want prefix %v", err, want)
 	}
 
-	_, err = routerExec(router, "insert into music(user_id, id) values (1, 1),(2, null)", nil)
-	want = "execInsertSharded: value 2 for column user_id does not map to keyspace id 166b40b44aba4bd6"
+	sbc2.MustFailServer = 1
+	_, err = routerExec(router, "insert into user_extra(user_id, extra) values (1, 'abc'),(3, 'xyz')", nil)
+	want = "execInsertSharded: target: TestRouter.40-60.master"
 	if err == nil || !strings.HasPrefix(err.Error(), want) {
 		t.Errorf("routerExec: %v, want prefix %v", err, want)
 	}
+	if sbc1.ExecCount.Get() != 1 {
+		t.Errorf("sbc1.ExecCount: %v, want 1", sbc1.ExecCount.Get())
+	}
+}
+
+func TestInsertSelect(t *testing.T) {
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
+
+	sbclookup.SetResults([]*sqltypes.Result{{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.VarChar, []byte("myname1")),
+			sqltypes.NULL,
+		}, {
+			sqltypes.MakeTrusted(sqltypes.VarChar, []byte("myname3")),
+			sqltypes.NULL,
+		}},
+		RowsAffected: 2,
+	}, {
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.Int64, []byte("1")),
+		}},
+		RowsAffected: 1,
+	}, sandboxconn.SingleRowResult, {
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.Int64, []byte("3")),
+		}},
+		RowsAffected: 1,
+	}})
+	result, err := routerExec(router, "insert into user(name) select name from name_user_map", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select name, null from name_user_map",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "select next value from `user_seq`",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql: "insert into name_user_map(name, user_id) values (:name, :user_id)",
+		BindVariables: map[string]interface{}{
+			"name":    []byte("myname1"),
+			"user_id": int64(1),
+		},
+	}, {
+		Sql:           "select next value from `user_seq`",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql: "insert into name_user_map(name, user_id) values (:name, :user_id)",
+		BindVariables: map[string]interface{}{
+			"name":    []byte("myname3"),
+			"user_id": int64(3),
+		},
+	}}
+	if !reflect.DeepEqual(sbclookup.Queries, wantQueries) {
+		t.Errorf("sbclookup.Queries: \n%#v, want \n%#v\n", sbclookup.Queries, wantQueries)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql:           "insert into user(name, Id) values ('myname1', 1) /* vtgate:: keyspace_id:166b40b44aba4bd6 */",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries:\n%+v, want\n%+v\n", sbc1.Queries, wantQueries)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql:           "insert into user(name, Id) values ('myname3', 3) /* vtgate:: keyspace_id:4eb190c9a2fa169c */",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc2.Queries, wantQueries) {
+		t.Errorf("sbc2.Queries:\n%+v, want\n%+v\n", sbc2.Queries, wantQueries)
+	}
+	if result.InsertID != 1 || result.RowsAffected != 2 {
+		t.Errorf("result: %+v, want InsertID 1 and RowsAffected 2", result)
+	}
+}
+
+func TestInsertSelectEmpty(t *testing.T) {
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
+
+	sbclookup.SetResults([]*sqltypes.Result{{}})
+	result, err := routerExec(router, "insert into user(name) select name from name_user_map", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RowsAffected != 0 {
+		t.Errorf("result.RowsAffected: %d, want 0", result.RowsAffected)
+	}
+	if sbc1.Queries != nil || sbc2.Queries != nil {
+		t.Errorf("sbc1.Queries: %+v, sbc2.Queries: %+v, want nil", sbc1.Queries, sbc2.Queries)
+	}
+}
 
+func TestDMLReference(t *testing.T) {
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
+
+	for _, sql := range []string{
+		"insert into ref(id, col) values (1, 2)",
+		"update ref set col = 3 where id = 1",
+		"delete from ref where id = 1",
+	} {
+		_, err := routerExec(router, sql, nil)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "insert into ref(id, col) values (1, 2)",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "update ref set col = 3 where id = 1",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "delete from ref where id = 1",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbclookup.Queries, wantQueries) {
+		t.Errorf("sbclookup.Queries: %+v, want %+v\n", sbclookup.Queries, wantQueries)
+	}
+	if sbc1.Queries != nil || sbc2.Queries != nil {
+		t.Errorf("sbc1.Queries: %+v, sbc2.Queries: %+v, want nil\n", sbc1.Queries, sbc2.Queries)
+	}
+}
+
+func TestSavepoint(t *testing.T) {
+	router, sbc1, sbc2, _ := createRouterEnv()
+
+	_, err := routerExec(router, "savepoint a", nil)
+	want := "savepoint statements are only allowed within a transaction"
+	if err == nil || err.Error() != want {
+		t.Errorf("routerExec: %v, want %v", err, want)
+	}
+
+	session := &vtgatepb.Session{InTransaction: true}
+	exec := func(sql string) error {
+		_, err := router.Execute(context.Background(), sql, nil, "", topodatapb.TabletType_MASTER, session, false, nil)
+		return err
+	}
+	for _, sql := range []string{
+		"update user set a = 2 where id = 1",
+		"savepoint a",
+		// The shard of id 3 joins the transaction after the
+		// savepoint, which must be set on it too.
+		"update user set a = 2 where id = 3",
+		"savepoint b",
+		"rollback to a",
+	} {
+		if err := exec(sql); err != nil {
+			t.Fatalf("Execute(%s): %v", sql, err)
+		}
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(session.Savepoints, want) {
+		t.Errorf("session.Savepoints: %v, want %v", session.Savepoints, want)
+	}
+	if got := sbc2.BeginCount.Get(); got != 1 {
+		t.Errorf("sbc2.BeginCount: %d, want 1", got)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "update user set a = 2 where id = 1 /* vtgate:: keyspace_id:166b40b44aba4bd6 */",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "savepoint a",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "savepoint b",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "rollback to savepoint a",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries:\n%+v, want\n%+v\n", sbc1.Queries, wantQueries)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql:           "savepoint a",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "update user set a = 2 where id = 3 /* vtgate:: keyspace_id:4eb190c9a2fa169c */",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "savepoint b",
+		BindVariables: map[string]interface{}{},
+	}, {
+		Sql:           "rollback to savepoint a",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc2.Queries, wantQueries) {
+		t.Errorf("sbc2.Queries:\n%+v, want\n%+v\n", sbc2.Queries, wantQueries)
+	}
+
+	// b was removed by the rollback.
+	err = exec("release savepoint b")
+	want = "savepoint b does not exist"
+	if err == nil || err.Error() != want {
+		t.Errorf("release savepoint b: %v, want %v", err, want)
+	}
+	if err := exec("release savepoint A"); err != nil {
+		t.Errorf("release savepoint A: %v", err)
+	}
+	if len(session.Savepoints) != 0 {
+		t.Errorf("session.Savepoints: %v, want empty", session.Savepoints)
+	}
 }
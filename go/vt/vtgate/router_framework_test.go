@@ -129,6 +129,10 @@ var routerVSchema = `
 					"name": "keyspace_id"
 				}
 			]
+		},
+		"ref": {
+			"type": "reference",
+			"source": "TestUnsharded"
 		}
 	}
 }
@@ -738,6 +738,537 @@ func TestSelectScatterFail(t *testing.T) {
 
 // TODO(sougou): stream and non-stream testing are very similar.
 // Could reuse code,
+// createScatterOrderEnv creates eight shards. Each shard returns
+// two rows sorted by col desc: shard i returns col = i+8, and col = i.
+func createScatterOrderEnv() (*Router, []*sandboxconn.SandboxConn) {
+	// Special setup: Don't use createRouterEnv.
+	cell := "aa"
+	hc := discovery.NewFakeHealthCheck()
+	s := createSandbox("TestRouter")
+	s.VSchema = routerVSchema
+	getSandbox(KsTestUnsharded).VSchema = unshardedVSchema
+	serv := new(sandboxTopo)
+	scatterConn := newTestScatterConn(hc, serv, cell)
+	shards := []string{"-20", "20-40", "40-60", "60-80", "80-a0", "a0-c0", "c0-e0", "e0-"}
+	var conns []*sandboxconn.SandboxConn
+	for i, shard := range shards {
+		sbc := hc.AddTestTablet(cell, shard, 1, "TestRouter", shard, topodatapb.TabletType_MASTER, true, 1, nil)
+		sbc.SetResults([]*sqltypes.Result{{
+			Fields: []*querypb.Field{
+				{Name: "id", Type: sqltypes.Int32},
+				{Name: "col", Type: sqltypes.Int32},
+			},
+			RowsAffected: 2,
+			Rows: [][]sqltypes.Value{{
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte(fmt.Sprintf("%d", i+8))),
+			}, {
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte(fmt.Sprintf("%d", i))),
+			}},
+		}})
+		conns = append(conns, sbc)
+	}
+	return NewRouter(context.Background(), serv, cell, "", scatterConn), conns
+}
+
+// scatterOrderResult returns the expected result for the
+// rows of createScatterOrderEnv, for col values from..to.
+func scatterOrderResult(from, to int) *sqltypes.Result {
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "id", Type: sqltypes.Int32},
+			{Name: "col", Type: sqltypes.Int32},
+		},
+	}
+	for i := from; i >= to; i-- {
+		result.Rows = append(result.Rows, []sqltypes.Value{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte(fmt.Sprintf("%d", i))),
+		})
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+	return result
+}
+
+func TestSelectScatterOrderBy(t *testing.T) {
+	router, conns := createScatterOrderEnv()
+
+	result, err := routerExec(router, "select id, col from user order by col desc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select id, col, weight_string(col) from user order by col desc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterOrderResult(15, 0)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestStreamSelectScatterOrderBy(t *testing.T) {
+	router, conns := createScatterOrderEnv()
+
+	result, err := routerStream(router, "select id, col from user order by col desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select id, col, weight_string(col) from user order by col desc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterOrderResult(15, 0)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestStreamSelectScatterOrderByFail(t *testing.T) {
+	router, conns := createScatterOrderEnv()
+	conns[2].MustFailServer = 1
+
+	_, err := routerStream(router, "select id, col from user order by col desc")
+	want := "error: err"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("routerStream: %v, must contain %v", err, want)
+	}
+}
+
+// createScatterVarCharOrderEnv creates eight shards. Each shard
+// returns two rows sorted by col desc, with a case-insensitive
+// collation: shard i returns the letters i+8 and i of the alphabet,
+// in upper case for the even ones. The weight strings returned
+// by the shards are the upper case letters.
+func createScatterVarCharOrderEnv() (*Router, []*sandboxconn.SandboxConn) {
+	// Special setup: Don't use createRouterEnv.
+	cell := "aa"
+	hc := discovery.NewFakeHealthCheck()
+	s := createSandbox("TestRouter")
+	s.VSchema = routerVSchema
+	getSandbox(KsTestUnsharded).VSchema = unshardedVSchema
+	serv := new(sandboxTopo)
+	scatterConn := newTestScatterConn(hc, serv, cell)
+	shards := []string{"-20", "20-40", "40-60", "60-80", "80-a0", "a0-c0", "c0-e0", "e0-"}
+	var conns []*sandboxconn.SandboxConn
+	for i, shard := range shards {
+		sbc := hc.AddTestTablet(cell, shard, 1, "TestRouter", shard, topodatapb.TabletType_MASTER, true, 1, nil)
+		sbc.SetResults([]*sqltypes.Result{{
+			Fields: []*querypb.Field{
+				{Name: "id", Type: sqltypes.Int32},
+				{Name: "col", Type: sqltypes.VarChar},
+				{Name: "weight_string(col)", Type: sqltypes.VarBinary},
+			},
+			RowsAffected: 2,
+			Rows: [][]sqltypes.Value{{
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+				sqltypes.MakeTrusted(sqltypes.VarChar, []byte(varCharOrderValue(i+8))),
+				sqltypes.MakeTrusted(sqltypes.VarBinary, []byte(strings.ToUpper(varCharOrderValue(i+8)))),
+			}, {
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+				sqltypes.MakeTrusted(sqltypes.VarChar, []byte(varCharOrderValue(i))),
+				sqltypes.MakeTrusted(sqltypes.VarBinary, []byte(strings.ToUpper(varCharOrderValue(i)))),
+			}},
+		}})
+		conns = append(conns, sbc)
+	}
+	return NewRouter(context.Background(), serv, cell, "", scatterConn), conns
+}
+
+// varCharOrderValue returns the i-th letter of the alphabet,
+// in upper case if i is even.
+func varCharOrderValue(i int) string {
+	letter := string('a' + rune(i))
+	if i%2 == 0 {
+		return strings.ToUpper(letter)
+	}
+	return letter
+}
+
+// scatterVarCharOrderResult returns the expected result for the
+// rows of createScatterVarCharOrderEnv, for the letters from..to.
+// The weight strings are truncated.
+func scatterVarCharOrderResult(from, to int) *sqltypes.Result {
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "id", Type: sqltypes.Int32},
+			{Name: "col", Type: sqltypes.VarChar},
+		},
+	}
+	for i := from; i >= to; i-- {
+		result.Rows = append(result.Rows, []sqltypes.Value{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+			sqltypes.MakeTrusted(sqltypes.VarChar, []byte(varCharOrderValue(i))),
+		})
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+	return result
+}
+
+func TestSelectScatterOrderByVarChar(t *testing.T) {
+	router, conns := createScatterVarCharOrderEnv()
+
+	result, err := routerExec(router, "select id, col from user order by col desc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select id, col, weight_string(col) from user order by col desc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterVarCharOrderResult(15, 0)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestStreamSelectScatterOrderByVarChar(t *testing.T) {
+	router, conns := createScatterVarCharOrderEnv()
+
+	result, err := routerStream(router, "select id, col from user order by col desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select id, col, weight_string(col) from user order by col desc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterVarCharOrderResult(15, 0)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestSelectScatterLimitVarChar(t *testing.T) {
+	router, _ := createScatterVarCharOrderEnv()
+
+	result, err := routerExec(router, "select id, col from user order by col desc limit 2, 3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResult := scatterVarCharOrderResult(13, 11)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestSelectScatterLimit(t *testing.T) {
+	router, conns := createScatterOrderEnv()
+
+	result, err := routerExec(router, "select id, col from user order by col desc limit 2, 3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select id, col, weight_string(col) from user order by col desc limit 5",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterOrderResult(13, 11)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestStreamSelectScatterLimit(t *testing.T) {
+	router, _ := createScatterOrderEnv()
+
+	result, err := routerStream(router, "select id, col from user order by col desc limit 2, 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantResult := scatterOrderResult(13, 11)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestStreamSelectScatterLimitStops(t *testing.T) {
+	router, _ := createScatterOrderEnv()
+
+	// Without an order by, the rows come straight from the shards,
+	// and the streams are stopped once the limit is reached.
+	result, err := routerStream(router, "select id, col from user limit 1, 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Rows) != 2 {
+		t.Errorf("got %d rows, want 2: %+v", len(result.Rows), result)
+	}
+}
+
+// createScatterAggrEnv creates a router with eight shards. Every shard
+// returns a partial sum and count for col=1, and one for col=i+2.
+func createScatterAggrEnv() (*Router, []*sandboxconn.SandboxConn) {
+	// Special setup: Don't use createRouterEnv.
+	cell := "aa"
+	hc := discovery.NewFakeHealthCheck()
+	s := createSandbox("TestRouter")
+	s.VSchema = routerVSchema
+	getSandbox(KsTestUnsharded).VSchema = unshardedVSchema
+	serv := new(sandboxTopo)
+	scatterConn := newTestScatterConn(hc, serv, cell)
+	shards := []string{"-20", "20-40", "40-60", "60-80", "80-a0", "a0-c0", "c0-e0", "e0-"}
+	var conns []*sandboxconn.SandboxConn
+	for i, shard := range shards {
+		sbc := hc.AddTestTablet(cell, shard, 1, "TestRouter", shard, topodatapb.TabletType_MASTER, true, 1, nil)
+		sbc.SetResults([]*sqltypes.Result{{
+			Fields: []*querypb.Field{
+				{Name: "col", Type: sqltypes.Int32},
+				{Name: "sum(a)", Type: sqltypes.Decimal},
+				{Name: "count(*)", Type: sqltypes.Int64},
+				{Name: "count(a)", Type: sqltypes.Int64},
+			},
+			RowsAffected: 2,
+			Rows: [][]sqltypes.Value{{
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+				sqltypes.MakeTrusted(sqltypes.Decimal, []byte("1.5")),
+				sqltypes.MakeTrusted(sqltypes.Int64, []byte("3")),
+				sqltypes.MakeTrusted(sqltypes.Int64, []byte("2")),
+			}, {
+				sqltypes.MakeTrusted(sqltypes.Int32, []byte(fmt.Sprintf("%d", i+2))),
+				sqltypes.MakeTrusted(sqltypes.Decimal, []byte("2")),
+				sqltypes.MakeTrusted(sqltypes.Int64, []byte("1")),
+				sqltypes.MakeTrusted(sqltypes.Int64, []byte("1")),
+			}},
+		}})
+		conns = append(conns, sbc)
+	}
+	return NewRouter(context.Background(), serv, cell, "", scatterConn), conns
+}
+
+// scatterAggrResult returns the expected result for
+// "select col, avg(a), count(*) from user group by col"
+// executed against createScatterAggrEnv.
+func scatterAggrResult() *sqltypes.Result {
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "col", Type: sqltypes.Int32},
+			{Name: "avg(a)", Type: sqltypes.Decimal},
+			{Name: "count(*)", Type: sqltypes.Int64},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte("1")),
+			sqltypes.MakeTrusted(sqltypes.Decimal, []byte("0.75000")),
+			sqltypes.MakeTrusted(sqltypes.Int64, []byte("24")),
+		}},
+	}
+	for i := 2; i < 10; i++ {
+		result.Rows = append(result.Rows, []sqltypes.Value{
+			sqltypes.MakeTrusted(sqltypes.Int32, []byte(fmt.Sprintf("%d", i))),
+			sqltypes.MakeTrusted(sqltypes.Decimal, []byte("2.0000")),
+			sqltypes.MakeTrusted(sqltypes.Int64, []byte("1")),
+		})
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+	return result
+}
+
+func TestSelectScatterAggregate(t *testing.T) {
+	router, conns := createScatterAggrEnv()
+
+	result, err := routerExec(router, "select col, avg(a), count(*) from user group by col", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select col, sum(a), count(*), count(a), weight_string(col) from user group by col order by 1 asc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterAggrResult()
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestStreamSelectScatterAggregate(t *testing.T) {
+	router, conns := createScatterAggrEnv()
+
+	result, err := routerStream(router, "select col, avg(a), count(*) from user group by col")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select col, sum(a), count(*), count(a), weight_string(col) from user group by col order by 1 asc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterAggrResult()
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+func TestSelectScatterAggregateLimit(t *testing.T) {
+	router, conns := createScatterAggrEnv()
+
+	result, err := routerExec(router, "select col, avg(a), count(*) from user group by col limit 1, 2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select col, sum(a), count(*), count(a), weight_string(col) from user group by col order by 1 asc limit 3",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	wantResult := scatterAggrResult()
+	wantResult.Rows = wantResult.Rows[1:3]
+	wantResult.RowsAffected = 2
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("result: %+v, want %+v", result, wantResult)
+	}
+}
+
+// createScatterVarCharAggrEnv creates a router with eight shards.
+// Every shard returns the partial minimum and maximum of a for two
+// groups, with a case-insensitive collation: col is 'a' and 'z', in
+// upper case for the even shards. Shard i returns the letters i+1
+// and i+9 of varCharOrderValue as minimum and maximum. The weight
+// strings returned by the shards are the upper case letters.
+func createScatterVarCharAggrEnv() (*Router, []*sandboxconn.SandboxConn) {
+	// Special setup: Don't use createRouterEnv.
+	cell := "aa"
+	hc := discovery.NewFakeHealthCheck()
+	s := createSandbox("TestRouter")
+	s.VSchema = routerVSchema
+	getSandbox(KsTestUnsharded).VSchema = unshardedVSchema
+	serv := new(sandboxTopo)
+	scatterConn := newTestScatterConn(hc, serv, cell)
+	shards := []string{"-20", "20-40", "40-60", "60-80", "80-a0", "a0-c0", "c0-e0", "e0-"}
+	var conns []*sandboxconn.SandboxConn
+	for i, shard := range shards {
+		sbc := hc.AddTestTablet(cell, shard, 1, "TestRouter", shard, topodatapb.TabletType_MASTER, true, 1, nil)
+		result := &sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Name: "col", Type: sqltypes.VarChar},
+				{Name: "min(a)", Type: sqltypes.VarChar},
+				{Name: "max(a)", Type: sqltypes.VarChar},
+				{Name: "weight_string(col)", Type: sqltypes.VarBinary},
+				{Name: "weight_string(min(a))", Type: sqltypes.VarBinary},
+				{Name: "weight_string(max(a))", Type: sqltypes.VarBinary},
+			},
+			RowsAffected: 2,
+		}
+		for _, col := range []string{"a", "z"} {
+			if i%2 == 0 {
+				col = strings.ToUpper(col)
+			}
+			min, max := varCharOrderValue(i+1), varCharOrderValue(i+9)
+			result.Rows = append(result.Rows, []sqltypes.Value{
+				sqltypes.MakeTrusted(sqltypes.VarChar, []byte(col)),
+				sqltypes.MakeTrusted(sqltypes.VarChar, []byte(min)),
+				sqltypes.MakeTrusted(sqltypes.VarChar, []byte(max)),
+				sqltypes.MakeTrusted(sqltypes.VarBinary, []byte(strings.ToUpper(col))),
+				sqltypes.MakeTrusted(sqltypes.VarBinary, []byte(strings.ToUpper(min))),
+				sqltypes.MakeTrusted(sqltypes.VarBinary, []byte(strings.ToUpper(max))),
+			})
+		}
+		sbc.SetResults([]*sqltypes.Result{result})
+		conns = append(conns, sbc)
+	}
+	return NewRouter(context.Background(), serv, cell, "", scatterConn), conns
+}
+
+// verifyScatterVarCharAggrResult verifies the result of
+// "select col, min(a), max(a) from user group by col"
+// executed against createScatterVarCharAggrEnv. The groups
+// are merged whatever the case of col, which can come from
+// any shard.
+func verifyScatterVarCharAggrResult(t *testing.T, result *sqltypes.Result) {
+	wantFields := []*querypb.Field{
+		{Name: "col", Type: sqltypes.VarChar},
+		{Name: "min(a)", Type: sqltypes.VarChar},
+		{Name: "max(a)", Type: sqltypes.VarChar},
+	}
+	if !reflect.DeepEqual(result.Fields, wantFields) {
+		t.Errorf("result.Fields: %+v, want %+v", result.Fields, wantFields)
+	}
+	if len(result.Rows) != 2 || result.RowsAffected != 2 {
+		t.Fatalf("result: %+v, want two groups", result)
+	}
+	for i, col := range []string{"a", "z"} {
+		row := result.Rows[i]
+		if len(row) != 3 || !strings.EqualFold(row[0].String(), col) {
+			t.Errorf("row %d: %v, want %s", i, row, col)
+			continue
+		}
+		// Compared byte by byte, the minimum would be 'C', and the maximum 'p'.
+		if got, want := row[1].String(), "b"; got != want {
+			t.Errorf("min(a) of %s: %s, want %s", col, got, want)
+		}
+		if got, want := row[2].String(), "Q"; got != want {
+			t.Errorf("max(a) of %s: %s, want %s", col, got, want)
+		}
+	}
+}
+
+func TestSelectScatterAggregateVarChar(t *testing.T) {
+	router, conns := createScatterVarCharAggrEnv()
+
+	result, err := routerExec(router, "select col, min(a), max(a) from user group by col", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select col, min(a), max(a), weight_string(col), weight_string(min(a)), weight_string(max(a)) from user group by col order by 1 asc",
+		BindVariables: map[string]interface{}{},
+	}}
+	for _, conn := range conns {
+		if !reflect.DeepEqual(conn.Queries, wantQueries) {
+			t.Errorf("conn.Queries = %#v, want %#v", conn.Queries, wantQueries)
+		}
+	}
+	verifyScatterVarCharAggrResult(t, result)
+}
+
+func TestStreamSelectScatterAggregateVarChar(t *testing.T) {
+	router, _ := createScatterVarCharAggrEnv()
+
+	result, err := routerStream(router, "select col, min(a), max(a) from user group by col")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyScatterVarCharAggrResult(t, result)
+}
+
 func TestSimpleJoin(t *testing.T) {
 	router, sbc1, sbc2, _ := createRouterEnv()
 	result, err := routerExec(router, "select u1.id, u2.id from user u1 join user u2 where u1.id = 1 and u2.id = 3", nil)
@@ -1260,3 +1791,60 @@ func TestJoinErrors(t *testing.T) {
 		t.Errorf("err: %v, must start with %s", err, want)
 	}
 }
+
+func TestSelectReference(t *testing.T) {
+	router, sbc1, sbc2, sbclookup := createRouterEnv()
+
+	_, err := routerExec(router, "select col from ref", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select col from ref",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries: %+v, want %+v\n", sbc1.Queries, wantQueries)
+	}
+	if sbc2.Queries != nil {
+		t.Errorf("sbc2.Queries: %+v, want nil\n", sbc2.Queries)
+	}
+	if sbclookup.Queries != nil {
+		t.Errorf("sbclookup.Queries: %+v, want nil\n", sbclookup.Queries)
+	}
+
+	sbc1.Queries = nil
+	_, err = routerExec(router, "select user.col, ref.col from user join ref on user.ref_id = ref.id where user.id = 3", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries = []querytypes.BoundQuery{{
+		Sql:           "select user.col, ref.col from user join ref on user.ref_id = ref.id where user.id = 3",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc2.Queries, wantQueries) {
+		t.Errorf("sbc2.Queries: %+v, want %+v\n", sbc2.Queries, wantQueries)
+	}
+	if sbc1.Queries != nil {
+		t.Errorf("sbc1.Queries: %+v, want nil\n", sbc1.Queries)
+	}
+}
+
+func TestStreamSelectReference(t *testing.T) {
+	router, sbc1, sbc2, _ := createRouterEnv()
+
+	_, err := routerStream(router, "select col from ref")
+	if err != nil {
+		t.Error(err)
+	}
+	wantQueries := []querytypes.BoundQuery{{
+		Sql:           "select col from ref",
+		BindVariables: map[string]interface{}{},
+	}}
+	if !reflect.DeepEqual(sbc1.Queries, wantQueries) {
+		t.Errorf("sbc1.Queries: %+v, want %+v\n", sbc1.Queries, wantQueries)
+	}
+	if sbc2.Queries != nil {
+		t.Errorf("sbc2.Queries: %+v, want nil\n", sbc2.Queries)
+	}
+}
@@ -5,8 +5,12 @@
 package vtgate
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
+	"github.com/youtube/vitess/go/vt/sqlparser"
+
 	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
 	vtgatepb "github.com/youtube/vitess/go/vt/proto/vtgate"
 )
@@ -63,4 +67,83 @@ func (session *SafeSession) Reset() {
 	defer session.mu.Unlock()
 	session.Session.InTransaction = false
 	session.ShardSessions = nil
+	session.Savepoints = nil
+}
+
+// SavepointQueries returns the statements which set the active
+// savepoints, in the order they were set. They must be executed on the
+// shards which join the transaction.
+func (session *SafeSession) SavepointQueries() []string {
+	if session == nil {
+		return nil
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if len(session.Savepoints) == 0 {
+		return nil
+	}
+	queries := make([]string, 0, len(session.Savepoints))
+	for _, name := range session.Savepoints {
+		queries = append(queries, sqlparser.String(&sqlparser.Savepoint{
+			Action: sqlparser.SavepointStr,
+			Name:   sqlparser.NewColIdent(name),
+		}))
+	}
+	return queries
+}
+
+// HasSavepoint returns true if the savepoint is active. Savepoint
+// names are case-insensitive.
+func (session *SafeSession) HasSavepoint(name string) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.findSavepoint(name) >= 0
+}
+
+// SetSavepoint records a new savepoint. Like in MySQL, it replaces
+// an existing savepoint with the same name.
+func (session *SafeSession) SetSavepoint(name string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if i := session.findSavepoint(name); i >= 0 {
+		session.Savepoints = append(session.Savepoints[:i], session.Savepoints[i+1:]...)
+	}
+	session.Savepoints = append(session.Savepoints, name)
+}
+
+// RollbackToSavepoint removes the savepoints set after the
+// savepoint. The savepoint itself remains active.
+func (session *SafeSession) RollbackToSavepoint(name string) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	i := session.findSavepoint(name)
+	if i < 0 {
+		return fmt.Errorf("savepoint %s does not exist", name)
+	}
+	session.Savepoints = session.Savepoints[:i+1]
+	return nil
+}
+
+// ReleaseSavepoint removes the savepoint, and the savepoints set
+// after it.
+func (session *SafeSession) ReleaseSavepoint(name string) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	i := session.findSavepoint(name)
+	if i < 0 {
+		return fmt.Errorf("savepoint %s does not exist", name)
+	}
+	session.Savepoints = session.Savepoints[:i]
+	return nil
+}
+
+// findSavepoint returns the index of the savepoint, or -1.
+// It must be called with the lock held.
+func (session *SafeSession) findSavepoint(name string) int {
+	for i, sp := range session.Savepoints {
+		if strings.EqualFold(sp, name) {
+			return i
+		}
+	}
+	return -1
 }
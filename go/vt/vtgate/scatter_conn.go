@@ -15,11 +15,13 @@ import (
 
 	"github.com/youtube/vitess/go/sqltypes"
 	"github.com/youtube/vitess/go/stats"
+	"github.com/youtube/vitess/go/trace"
 	"github.com/youtube/vitess/go/vt/binlog/eventtoken"
 	"github.com/youtube/vitess/go/vt/concurrency"
 	"github.com/youtube/vitess/go/vt/tabletserver/querytypes"
 	"github.com/youtube/vitess/go/vt/topo/topoproto"
 	"github.com/youtube/vitess/go/vt/vterrors"
+	"github.com/youtube/vitess/go/vt/vtgate/consolidator"
 	"github.com/youtube/vitess/go/vt/vtgate/gateway"
 
 	querypb "github.com/youtube/vitess/go/vt/proto/query"
@@ -88,6 +90,18 @@ func (stc *ScatterConn) endAction(startTime time.Time, allErrors *concurrency.Al
 	stc.timings.Record(statsKey, startTime)
 }
 
+// startSpan starts the span of a ScatterConn call, and returns the
+// context to use for the calls to the shards, so their spans are
+// children of this one.
+func (stc *ScatterConn) startSpan(ctx context.Context, name, keyspace string) (context.Context, trace.Span) {
+	span := trace.NewSpanFromContext(ctx)
+	span.StartClient("ScatterConn." + name)
+	if keyspace != "" {
+		span.Annotate("keyspace", keyspace)
+	}
+	return trace.NewContext(ctx, span), span
+}
+
 // Execute executes a non-streaming query on the specified shards.
 func (stc *ScatterConn) Execute(
 	ctx context.Context,
@@ -101,6 +115,9 @@ func (stc *ScatterConn) Execute(
 	options *querypb.ExecuteOptions,
 ) (*sqltypes.Result, error) {
 
+	ctx, span := stc.startSpan(ctx, "Execute", keyspace)
+	defer span.Finish()
+
 	// mu protects qr
 	var mu sync.Mutex
 	qr := new(sqltypes.Result)
@@ -123,7 +140,7 @@ func (stc *ScatterConn) Execute(
 				}
 			} else {
 				var err error
-				innerqr, err = stc.gateway.Execute(ctx, target, query, bindVars, transactionID, options)
+				innerqr, err = stc.execute(ctx, target, query, bindVars, transactionID, options)
 				if err != nil {
 					return transactionID, err
 				}
@@ -157,6 +174,9 @@ func (stc *ScatterConn) ExecuteMulti(
 	options *querypb.ExecuteOptions,
 ) (*sqltypes.Result, error) {
 
+	ctx, span := stc.startSpan(ctx, "ExecuteMulti", keyspace)
+	defer span.Finish()
+
 	// mu protects qr
 	var mu sync.Mutex
 	qr := new(sqltypes.Result)
@@ -179,7 +199,7 @@ func (stc *ScatterConn) ExecuteMulti(
 				}
 			} else {
 				var err error
-				innerqr, err = stc.gateway.Execute(ctx, target, query, shardVars[target.Shard], transactionID, options)
+				innerqr, err = stc.execute(ctx, target, query, shardVars[target.Shard], transactionID, options)
 				if err != nil {
 					return transactionID, err
 				}
@@ -212,6 +232,9 @@ func (stc *ScatterConn) ExecuteEntityIds(
 	options *querypb.ExecuteOptions,
 ) (*sqltypes.Result, error) {
 
+	ctx, span := stc.startSpan(ctx, "ExecuteEntityIds", keyspace)
+	defer span.Finish()
+
 	// mu protects qr
 	var mu sync.Mutex
 	qr := new(sqltypes.Result)
@@ -237,7 +260,7 @@ func (stc *ScatterConn) ExecuteEntityIds(
 				}
 			} else {
 				var err error
-				innerqr, err = stc.gateway.Execute(ctx, target, sql, bindVar, transactionID, options)
+				innerqr, err = stc.execute(ctx, target, sql, bindVar, transactionID, options)
 				if err != nil {
 					return transactionID, err
 				}
@@ -256,6 +279,73 @@ func (stc *ScatterConn) ExecuteEntityIds(
 	return qr, nil
 }
 
+// execute runs a query on one shard. The replica and rdonly reads
+// outside of a transaction go through the consolidator, which may
+// share their execution and their result with identical queries.
+func (stc *ScatterConn) execute(ctx context.Context, target *querypb.Target, query string, bindVars map[string]interface{}, transactionID int64, options *querypb.ExecuteOptions) (*sqltypes.Result, error) {
+	if transactionID != 0 {
+		return stc.gateway.Execute(ctx, target, query, bindVars, transactionID, options)
+	}
+	return consolidator.Execute(ctx, target, query, bindVars, options, func(ctx context.Context) (*sqltypes.Result, error) {
+		return stc.gateway.Execute(ctx, target, query, bindVars, 0, options)
+	})
+}
+
+// beginWithSavepoints begins a transaction on a shard which joins the
+// transaction of the session after savepoints were set, and sets the
+// savepoints on it too. This way, rolling back to a savepoint also
+// undoes the changes made on the shard. The shard is added to the
+// session as soon as the transaction has begun, so that it is rolled
+// back with the others if setting the savepoints fails.
+func (stc *ScatterConn) beginWithSavepoints(ctx context.Context, target *querypb.Target, savepoints []string, session *SafeSession) (int64, error) {
+	transactionID, err := stc.gateway.Begin(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+	session.Append(&vtgatepb.Session_ShardSession{
+		Target:        target,
+		TransactionId: transactionID,
+	})
+	for _, query := range savepoints {
+		if _, err := stc.gateway.Execute(ctx, target, query, nil, transactionID, nil); err != nil {
+			return transactionID, err
+		}
+	}
+	return transactionID, nil
+}
+
+// ExecuteSavepoint executes a savepoint statement on all the shards
+// participating in the transaction of the session. The session itself
+// is not modified.
+func (stc *ScatterConn) ExecuteSavepoint(ctx context.Context, query string, session *SafeSession) error {
+	ctx, span := stc.startSpan(ctx, "ExecuteSavepoint", "")
+	defer span.Finish()
+
+	session.mu.Lock()
+	shardSessions := session.ShardSessions
+	session.mu.Unlock()
+
+	allErrors := new(concurrency.AllErrorRecorder)
+	var wg sync.WaitGroup
+	for _, shardSession := range shardSessions {
+		wg.Add(1)
+		go func(shardSession *vtgatepb.Session_ShardSession) {
+			defer wg.Done()
+			var err error
+			startTime, statsKey := stc.startAction("ExecuteSavepoint", shardSession.Target)
+			defer stc.endAction(startTime, allErrors, statsKey, &err)
+			_, err = stc.gateway.Execute(ctx, shardSession.Target, query, nil, shardSession.TransactionId, nil)
+		}(shardSession)
+	}
+	wg.Wait()
+	if allErrors.HasErrors() {
+		err := allErrors.AggrError(stc.aggregateErrors)
+		stc.txConn.RollbackIfNeeded(ctx, err, session)
+		return err
+	}
+	return nil
+}
+
 // scatterBatchRequest needs to be built to perform a scatter batch query.
 // A VTGate batch request will get translated into a differnt set of batches
 // for each keyspace:shard, and those results will map to different positions in the
@@ -281,6 +371,9 @@ func (stc *ScatterConn) ExecuteBatch(
 	asTransaction bool,
 	session *SafeSession,
 	options *querypb.ExecuteOptions) (qrs []sqltypes.Result, err error) {
+	ctx, span := stc.startSpan(ctx, "ExecuteBatch", "")
+	defer span.Finish()
+
 	allErrors := new(concurrency.AllErrorRecorder)
 
 	results := make([]sqltypes.Result, batchRequest.Length)
@@ -302,6 +395,15 @@ func (stc *ScatterConn) ExecuteBatch(
 			defer stc.endAction(startTime, allErrors, statsKey, &err)
 
 			shouldBegin, transactionID := transactionInfo(target, session, false)
+			if shouldBegin {
+				if savepoints := session.SavepointQueries(); len(savepoints) != 0 {
+					transactionID, err = stc.beginWithSavepoints(ctx, target, savepoints, session)
+					if err != nil {
+						return
+					}
+					shouldBegin = false
+				}
+			}
 			var innerqrs []sqltypes.Result
 			if shouldBegin {
 				innerqrs, transactionID, err = stc.gateway.BeginExecuteBatch(ctx, target, req.Queries, asTransaction, options)
@@ -339,7 +441,10 @@ func (stc *ScatterConn) ExecuteBatch(
 	return results, nil
 }
 
-func (stc *ScatterConn) processOneStreamingResult(mu *sync.Mutex, stream sqltypes.ResultStream, err error, replyErr *error, fieldSent *bool, sendReply func(reply *sqltypes.Result) error) error {
+// processOneStreamingResult sends the results of the stream of one
+// shard through sendReply. If sendReply fails, its error is stored in
+// replyErr, and cancel is called to stop the streams of all the shards.
+func (stc *ScatterConn) processOneStreamingResult(mu *sync.Mutex, stream sqltypes.ResultStream, err error, replyErr *error, fieldSent *bool, sendReply func(reply *sqltypes.Result) error, cancel context.CancelFunc) error {
 	if err != nil {
 		return err
 	}
@@ -373,6 +478,9 @@ func (stc *ScatterConn) processOneStreamingResult(mu *sync.Mutex, stream sqltype
 			*fieldSent = true
 		}
 		*replyErr = sendReply(qr)
+		if *replyErr != nil {
+			cancel()
+		}
 		mu.Unlock()
 	}
 }
@@ -389,6 +497,14 @@ func (stc *ScatterConn) StreamExecute(
 	sendReply func(reply *sqltypes.Result) error,
 ) error {
 
+	ctx, span := stc.startSpan(ctx, "StreamExecute", keyspace)
+	defer span.Finish()
+
+	// If sendReply fails, the streams are canceled, and its error
+	// is returned as is.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// mu protects fieldSent, replyErr and sendReply
 	var mu sync.Mutex
 	var replyErr error
@@ -402,10 +518,10 @@ func (stc *ScatterConn) StreamExecute(
 		tabletType,
 		func(target *querypb.Target) error {
 			stream, err := stc.gateway.StreamExecute(ctx, target, query, bindVars, options)
-			return stc.processOneStreamingResult(&mu, stream, err, &replyErr, &fieldSent, sendReply)
+			return stc.processOneStreamingResult(&mu, stream, err, &replyErr, &fieldSent, sendReply, cancel)
 		})
 	if replyErr != nil {
-		allErrors.RecordError(replyErr)
+		return replyErr
 	}
 	return allErrors.AggrError(stc.aggregateErrors)
 }
@@ -422,6 +538,14 @@ func (stc *ScatterConn) StreamExecuteMulti(
 	options *querypb.ExecuteOptions,
 	sendReply func(reply *sqltypes.Result) error,
 ) error {
+	ctx, span := stc.startSpan(ctx, "StreamExecuteMulti", keyspace)
+	defer span.Finish()
+
+	// If sendReply fails, the streams are canceled, and its error
+	// is returned as is.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// mu protects fieldSent, sendReply and replyErr
 	var mu sync.Mutex
 	var replyErr error
@@ -435,10 +559,10 @@ func (stc *ScatterConn) StreamExecuteMulti(
 		tabletType,
 		func(target *querypb.Target) error {
 			stream, err := stc.gateway.StreamExecute(ctx, target, query, shardVars[target.Shard], options)
-			return stc.processOneStreamingResult(&mu, stream, err, &replyErr, &fieldSent, sendReply)
+			return stc.processOneStreamingResult(&mu, stream, err, &replyErr, &fieldSent, sendReply, cancel)
 		})
 	if replyErr != nil {
-		allErrors.RecordError(replyErr)
+		return replyErr
 	}
 	return allErrors.AggrError(stc.aggregateErrors)
 }
@@ -834,6 +958,15 @@ func (stc *ScatterConn) multiGoTransaction(
 		defer stc.endAction(startTime, allErrors, statsKey, &err)
 
 		shouldBegin, transactionID := transactionInfo(target, session, notInTransaction)
+		if shouldBegin {
+			if savepoints := session.SavepointQueries(); len(savepoints) != 0 {
+				transactionID, err = stc.beginWithSavepoints(ctx, target, savepoints, session)
+				if err != nil {
+					return
+				}
+				shouldBegin = false
+			}
+		}
 		transactionID, err = action(target, shouldBegin, transactionID)
 		if shouldBegin && transactionID != 0 {
 			session.Append(&vtgatepb.Session_ShardSession{
@@ -0,0 +1,230 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package schematracker keeps track of the columns of the tables of all
+keyspaces, so the V3 planner can expand '*' expressions in joins and
+validate column references.
+
+The columns are published by the tablets in their health stream, when
+vttablet runs with -queryserver-config-publish-schema, see StatsUpdate.
+The columns of a table are only known if all the tablets of its
+keyspace that publish their schema agree on them. While a schema
+change is being applied, the table is unknown for a while, and the
+planner behaves as if the tracking was disabled for it.
+*/
+package schematracker
+
+import (
+	"flag"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/youtube/vitess/go/cistring"
+	"github.com/youtube/vitess/go/vt/discovery"
+)
+
+var enableSchemaTracking = flag.Bool("enable_schema_tracking", false, "Track the columns of the tables from the health stream of the tablets, for the V3 planner. The tablets must run with -queryserver-config-publish-schema.")
+
+// downTabletExpiry is how long the schema of a tablet is kept after
+// it went down. A tablet that changes type goes down, and comes back
+// up without publishing its schema again.
+const downTabletExpiry = 10 * time.Minute
+
+// defaultTracker is the tracker used by vtgate.
+var defaultTracker = newTracker()
+
+// StatsUpdate records the schema published by a tablet. It must be
+// called with the down events of the HealthCheck too, see
+// discovery.HealthCheck.SetListener.
+func StatsUpdate(ts *discovery.TabletStats) {
+	defaultTracker.statsUpdate(ts)
+}
+
+// Columns returns the columns of a table, or nil if they are not
+// known. The returned slice must not be modified.
+func Columns(keyspace, table string) []cistring.CIString {
+	return defaultTracker.columns(keyspace, table)
+}
+
+// OnChange registers a function to call every time the known
+// columns change, for instance to clear a plan cache. It returns a
+// function to unregister it.
+func OnChange(f func()) (unregister func()) {
+	return defaultTracker.onChange(f)
+}
+
+// tracker keeps track of the schemas of all keyspaces.
+type tracker struct {
+	// mu protects all the fields.
+	mu sync.Mutex
+	// keyspaces is indexed by keyspace name.
+	keyspaces map[string]*keyspaceSchema
+	// listeners are indexed by registration id.
+	listeners      map[int]func()
+	nextListenerID int
+}
+
+// keyspaceSchema is the schema of a keyspace.
+type keyspaceSchema struct {
+	// tablets contains the schema of each tablet that published
+	// it, indexed by tablet key.
+	tablets map[string]*tabletSchema
+	// tables contains the known columns of each table, computed
+	// from tablets.
+	tables map[string][]cistring.CIString
+}
+
+// tabletSchema is the schema published by a tablet.
+type tabletSchema struct {
+	tables map[string][]cistring.CIString
+	// up is false when the tablet went down, at downTime.
+	up       bool
+	downTime time.Time
+}
+
+func newTracker() *tracker {
+	return &tracker{
+		keyspaces: make(map[string]*keyspaceSchema),
+		listeners: make(map[int]func()),
+	}
+}
+
+func (t *tracker) statsUpdate(ts *discovery.TabletStats) {
+	if !*enableSchemaTracking || ts.Target == nil || ts.Target.Keyspace == "" {
+		return
+	}
+
+	t.mu.Lock()
+	ks, ok := t.keyspaces[ts.Target.Keyspace]
+	if !ok {
+		if !ts.Up || len(ts.TableSchemas) == 0 {
+			t.mu.Unlock()
+			return
+		}
+		ks = &keyspaceSchema{
+			tablets: make(map[string]*tabletSchema),
+		}
+		t.keyspaces[ts.Target.Keyspace] = ks
+	}
+
+	tablet, ok := ks.tablets[ts.Key]
+	switch {
+	case len(ts.TableSchemas) != 0:
+		tablet = &tabletSchema{
+			tables: make(map[string][]cistring.CIString, len(ts.TableSchemas)),
+		}
+		for _, table := range ts.TableSchemas {
+			columns := make([]cistring.CIString, 0, len(table.Columns))
+			for _, col := range table.Columns {
+				columns = append(columns, cistring.New(col.Name))
+			}
+			tablet.tables[table.Name] = columns
+		}
+		ks.tablets[ts.Key] = tablet
+	case !ok:
+		// We don't know the schema of this tablet.
+		t.mu.Unlock()
+		return
+	}
+	if ts.Up {
+		tablet.up = true
+	} else if tablet.up {
+		tablet.up = false
+		tablet.downTime = time.Now()
+	}
+
+	changed := ks.update()
+	var listeners []func()
+	if changed {
+		for _, f := range t.listeners {
+			listeners = append(listeners, f)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, f := range listeners {
+		f()
+	}
+}
+
+// update recomputes the known columns of the keyspace from the
+// schemas of the tablets that are up. It returns true if they
+// changed.
+func (ks *keyspaceSchema) update() bool {
+	now := time.Now()
+	tables := make(map[string][]cistring.CIString)
+	// conflicts contains the tables the tablets disagree on.
+	conflicts := make(map[string]bool)
+	first := true
+	for key, tablet := range ks.tablets {
+		if !tablet.up {
+			if now.Sub(tablet.downTime) > downTabletExpiry {
+				delete(ks.tablets, key)
+			}
+			continue
+		}
+		if first {
+			for name, columns := range tablet.tables {
+				tables[name] = columns
+			}
+			first = false
+			continue
+		}
+		for name, columns := range tables {
+			if other, ok := tablet.tables[name]; !ok || !equalColumns(columns, other) {
+				conflicts[name] = true
+			}
+		}
+		for name := range tablet.tables {
+			if _, ok := tables[name]; !ok {
+				conflicts[name] = true
+			}
+		}
+	}
+	for name := range conflicts {
+		delete(tables, name)
+	}
+	if reflect.DeepEqual(tables, ks.tables) {
+		return false
+	}
+	ks.tables = tables
+	return true
+}
+
+func (t *tracker) columns(keyspace, table string) []cistring.CIString {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ks, ok := t.keyspaces[keyspace]
+	if !ok {
+		return nil
+	}
+	return ks.tables[table]
+}
+
+func (t *tracker) onChange(f func()) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	id := t.nextListenerID
+	t.nextListenerID++
+	t.listeners[id] = f
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.listeners, id)
+	}
+}
+
+func equalColumns(a, b []cistring.CIString) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schematracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/youtube/vitess/go/cistring"
+	"github.com/youtube/vitess/go/vt/discovery"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+)
+
+// enable enables the tracking, and returns a function to restore
+// the flag.
+func enable() func() {
+	old := *enableSchemaTracking
+	*enableSchemaTracking = true
+	return func() {
+		*enableSchemaTracking = old
+	}
+}
+
+// tabletStats returns the health check stats of a tablet of keyspace
+// ks. tables maps table names to comma-separated columns.
+func tabletStats(uid uint32, up bool, tables map[string]string) *discovery.TabletStats {
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "cell",
+			Uid:  uid,
+		},
+		Hostname: fmt.Sprintf("host%v", uid),
+	}
+	ts := &discovery.TabletStats{
+		Key:    discovery.TabletToMapKey(tablet),
+		Tablet: tablet,
+		Target: &querypb.Target{
+			Keyspace:   "ks",
+			Shard:      "0",
+			TabletType: topodatapb.TabletType_REPLICA,
+		},
+		Up:      up,
+		Serving: up,
+	}
+	for name, columns := range tables {
+		ts.SchemaVersion = 1
+		table := &querypb.TableSchema{Name: name}
+		for _, col := range strings.Split(columns, ",") {
+			table.Columns = append(table.Columns, &querypb.Field{Name: col})
+		}
+		ts.TableSchemas = append(ts.TableSchemas, table)
+	}
+	return ts
+}
+
+func checkColumns(t *testing.T, tr *tracker, desc, table string, want []string) {
+	var wantColumns []cistring.CIString
+	for _, col := range want {
+		wantColumns = append(wantColumns, cistring.New(col))
+	}
+	if got := tr.columns("ks", table); !reflect.DeepEqual(got, wantColumns) {
+		t.Errorf("%v: columns(%v) = %v, want %v", desc, table, got, wantColumns)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	defer enable()()
+	tr := newTracker()
+	changes := 0
+	unregister := tr.onChange(func() { changes++ })
+
+	tr.statsUpdate(tabletStats(1, true, map[string]string{"a": "id,name", "b": "id"}))
+	checkColumns(t, tr, "one tablet", "a", []string{"id", "name"})
+	checkColumns(t, tr, "one tablet", "b", []string{"id"})
+	checkColumns(t, tr, "one tablet", "c", nil)
+	if changes != 1 {
+		t.Errorf("changes = %v, want 1", changes)
+	}
+
+	// A health update without schema doesn't change anything.
+	tr.statsUpdate(tabletStats(1, true, nil))
+	checkColumns(t, tr, "no schema", "a", []string{"id", "name"})
+	if changes != 1 {
+		t.Errorf("changes = %v, want 1", changes)
+	}
+
+	// A second tablet, in the middle of a schema change.
+	tr.statsUpdate(tabletStats(2, true, map[string]string{"a": "id,name,email", "b": "id", "c": "id"}))
+	checkColumns(t, tr, "schema change", "a", nil)
+	checkColumns(t, tr, "schema change", "b", []string{"id"})
+	checkColumns(t, tr, "schema change", "c", nil)
+	if changes != 2 {
+		t.Errorf("changes = %v, want 2", changes)
+	}
+
+	// The first tablet goes down, the second one is authoritative.
+	tr.statsUpdate(tabletStats(1, false, nil))
+	checkColumns(t, tr, "tablet down", "a", []string{"id", "name", "email"})
+	checkColumns(t, tr, "tablet down", "c", []string{"id"})
+
+	// It comes back up without publishing its schema again.
+	tr.statsUpdate(tabletStats(1, true, nil))
+	checkColumns(t, tr, "tablet up", "a", nil)
+
+	// Then catches up.
+	tr.statsUpdate(tabletStats(1, true, map[string]string{"a": "id,name,email", "b": "id", "c": "id"}))
+	checkColumns(t, tr, "schema change done", "a", []string{"id", "name", "email"})
+	checkColumns(t, tr, "schema change done", "c", []string{"id"})
+	if changes != 5 {
+		t.Errorf("changes = %v, want 5", changes)
+	}
+
+	// Tablets down for too long are forgotten.
+	tr.statsUpdate(tabletStats(2, false, nil))
+	tr.mu.Lock()
+	tr.keyspaces["ks"].tablets[tabletStats(2, false, nil).Key].downTime = time.Now().Add(-2 * downTabletExpiry)
+	tr.mu.Unlock()
+	tr.statsUpdate(tabletStats(1, true, nil))
+	tr.mu.Lock()
+	if got := len(tr.keyspaces["ks"].tablets); got != 1 {
+		t.Errorf("len(tablets) = %v, want 1", got)
+	}
+	tr.mu.Unlock()
+
+	// Unregistered listeners are not called anymore.
+	changes = 0
+	unregister()
+	tr.statsUpdate(tabletStats(1, true, map[string]string{"a": "id"}))
+	checkColumns(t, tr, "unregistered", "a", []string{"id"})
+	if changes != 0 {
+		t.Errorf("changes = %v, want 0", changes)
+	}
+}
+
+func TestTrackerDisabled(t *testing.T) {
+	tr := newTracker()
+	tr.statsUpdate(tabletStats(1, true, map[string]string{"a": "id"}))
+	checkColumns(t, tr, "disabled", "a", nil)
+}
+
+func TestSchemazHandler(t *testing.T) {
+	defer enable()()
+	tr := newTracker()
+	tr.statsUpdate(tabletStats(1, true, map[string]string{"a": "id,name"}))
+
+	req, _ := http.NewRequest("GET", "/debug/schema_tracker", nil)
+	response := httptest.NewRecorder()
+	schemazHandler(response, req, tr)
+	body := response.Body.String()
+	for _, want := range []string{"<td>ks</td>", "<td>a</td>", "<td>id, name</td>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("schemaz page doesn't contain %v: %v", want, body)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schematracker
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+
+	log "github.com/golang/glog"
+
+	"github.com/youtube/vitess/go/acl"
+	"github.com/youtube/vitess/go/cistring"
+	"github.com/youtube/vitess/go/vt/logz"
+)
+
+const schemazHeaderHTML = `
+	<thead>
+		<tr>
+			<th>Keyspace</th>
+			<th>Table</th>
+			<th>Columns</th>
+		</tr>
+	</thead>
+`
+
+const schemazRowHTML = `
+		<tr class="low">
+			<td>{{.Keyspace}}</td>
+			<td>{{.Table}}</td>
+			<td>{{range $i, $col := .Columns}}{{if $i}}, {{end}}{{$col.Original}}{{end}}</td>
+		</tr>
+`
+
+var schemazRowTemplate = template.Must(template.New("schemaz").Parse(schemazRowHTML))
+
+// tableStatus describes the known columns of a table for the status
+// page.
+type tableStatus struct {
+	Keyspace string
+	Table    string
+	Columns  []cistring.CIString
+}
+
+// status returns the known columns of all the tables, sorted by
+// keyspace and table.
+func (t *tracker) status() []tableStatus {
+	t.mu.Lock()
+	var result []tableStatus
+	for keyspace, ks := range t.keyspaces {
+		for table, columns := range ks.tables {
+			result = append(result, tableStatus{
+				Keyspace: keyspace,
+				Table:    table,
+				Columns:  columns,
+			})
+		}
+	}
+	t.mu.Unlock()
+	sort.Sort(byKeyspaceTable(result))
+	return result
+}
+
+type byKeyspaceTable []tableStatus
+
+func (s byKeyspaceTable) Len() int      { return len(s) }
+func (s byKeyspaceTable) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byKeyspaceTable) Less(i, j int) bool {
+	if s[i].Keyspace != s[j].Keyspace {
+		return s[i].Keyspace < s[j].Keyspace
+	}
+	return s[i].Table < s[j].Table
+}
+
+func init() {
+	http.HandleFunc("/debug/schema_tracker", func(w http.ResponseWriter, r *http.Request) {
+		schemazHandler(w, r, defaultTracker)
+	})
+}
+
+func schemazHandler(w http.ResponseWriter, r *http.Request, t *tracker) {
+	if err := acl.CheckAccessHTTP(r, acl.DEBUGGING); err != nil {
+		acl.SendError(w, err)
+		return
+	}
+	logz.StartHTMLTable(w)
+	defer logz.EndHTMLTable(w)
+	w.Write([]byte(schemazHeaderHTML))
+	for _, ts := range t.status() {
+		if err := schemazRowTemplate.Execute(w, ts); err != nil {
+			log.Errorf("schemaz: couldn't execute template: %v", err)
+		}
+	}
+}
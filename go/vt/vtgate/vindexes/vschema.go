@@ -24,12 +24,21 @@ type VSchema struct {
 // Table represents a table in VSchema.
 type Table struct {
 	IsSequence     bool            `json:"is_sequence,omitempty"`
+	IsReference    bool            `json:"is_reference,omitempty"`
 	Name           string          `json:"name"`
 	Keyspace       *Keyspace       `json:"-"`
 	ColumnVindexes []*ColumnVindex `json:"column_vindexes,omitempty"`
 	Ordered        []*ColumnVindex `json:"ordered,omitempty"`
 	Owned          []*ColumnVindex `json:"owned,omitempty"`
 	AutoIncrement  *AutoIncrement  `json:"auto_increment,omitempty"`
+	// Source is the table that writes to a reference
+	// table must be sent to. It's nil if the table is
+	// not a reference table, or if it has no source.
+	Source *Table `json:"source,omitempty"`
+	// Columns is the list of columns of the table, as
+	// published by the tablets. It's nil if they are not
+	// known. It is not part of the VSchema.
+	Columns []cistring.CIString `json:"columns,omitempty"`
 }
 
 // Keyspace contains the keyspcae info for each Table.
@@ -88,12 +97,16 @@ func BuildVSchema(source *vschemapb.SrvVSchema) (vschema *VSchema, err error) {
 	if err != nil {
 		return nil, err
 	}
+	err = resolveReferences(source, vschema)
+	if err != nil {
+		return nil, err
+	}
 	return vschema, nil
 }
 
 // BuildKeyspaceSchema builds the vschema portion for one keyspace.
-// The build ignores sequence references because those dependencies can
-// go cross-keyspace.
+// The build ignores sequence and reference table sources because
+// those dependencies can go cross-keyspace.
 func BuildKeyspaceSchema(input *vschemapb.Keyspace, keyspace string) (*KeyspaceSchema, error) {
 	if input == nil {
 		input = &vschemapb.Keyspace{}
@@ -116,7 +129,7 @@ func BuildKeyspaceSchema(input *vschemapb.Keyspace, keyspace string) (*KeyspaceS
 }
 
 // ValidateKeyspace ensures that the keyspace vschema is valid.
-// External references (like sequence) are not validated.
+// External references (like sequence or source) are not validated.
 func ValidateKeyspace(input *vschemapb.Keyspace) error {
 	_, err := BuildKeyspaceSchema(input, "")
 	return err
@@ -162,10 +175,16 @@ func buildTables(source *vschemapb.SrvVSchema, vschema *VSchema) error {
 				vschema.tables[tname] = t
 			}
 			vschema.Keyspaces[ksname].Tables[tname] = t
-			if table.Type == "sequence" {
+			switch table.Type {
+			case "sequence":
 				t.IsSequence = true
+			case "reference":
+				t.IsReference = true
+				if len(table.ColumnVindexes) != 0 {
+					return fmt.Errorf("reference table %s cannot have vindexes", tname)
+				}
 			}
-			if keyspace.Sharded && len(table.ColumnVindexes) == 0 {
+			if keyspace.Sharded && !t.IsReference && len(table.ColumnVindexes) == 0 {
 				return fmt.Errorf("missing primary col vindex for table: %s", tname)
 			}
 			for i, ind := range table.ColumnVindexes {
@@ -231,6 +250,81 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema) error
 	return nil
 }
 
+// resolveReferences resolves the source of every reference table.
+// The source must be in an unsharded keyspace. It can be a table
+// that's not in the vschema of that keyspace.
+func resolveReferences(source *vschemapb.SrvVSchema, vschema *VSchema) error {
+	for ksname, ks := range source.Keyspaces {
+		ksvschema := vschema.Keyspaces[ksname]
+		for tname, table := range ks.Tables {
+			if table.Source == "" {
+				continue
+			}
+			t := ksvschema.Tables[tname]
+			if !t.IsReference {
+				return fmt.Errorf("source specified for non-reference table %s", tname)
+			}
+			if table.Source == ksname {
+				return fmt.Errorf("source of reference table %s cannot be its own keyspace", tname)
+			}
+			sourceKs, ok := vschema.Keyspaces[table.Source]
+			if !ok {
+				return fmt.Errorf("source keyspace %s not found for table %s", table.Source, tname)
+			}
+			if sourceKs.Keyspace.Sharded {
+				return fmt.Errorf("source keyspace %s for table %s is sharded", table.Source, tname)
+			}
+			t.Source, _ = vschema.Find(table.Source, tname)
+		}
+	}
+	for tname, t := range vschema.tables {
+		if t == nil {
+			vschema.tables[tname] = resolveAmbiguousReference(vschema, tname)
+		}
+	}
+	return nil
+}
+
+// resolveAmbiguousReference returns the table that an unqualified
+// reference to tname resolves to, when tname is in more than one
+// keyspace only because of the copies of a reference table. If there
+// is one copy, it's the copy: it can be joined with the other tables
+// of its keyspace. Otherwise, it's the source. It returns nil if the
+// reference remains ambiguous.
+func resolveAmbiguousReference(vschema *VSchema, tname string) *Table {
+	var copies []*Table
+	var source *Table
+	for _, ksvschema := range vschema.Keyspaces {
+		t, ok := ksvschema.Tables[tname]
+		if !ok {
+			continue
+		}
+		if !t.IsReference || t.Source == nil {
+			if source != nil {
+				return nil
+			}
+			source = t
+			continue
+		}
+		copies = append(copies, t)
+	}
+	if len(copies) == 0 {
+		return nil
+	}
+	for _, t := range copies {
+		if source == nil {
+			source = t.Source
+		}
+		if t.Source.Keyspace.Name != source.Keyspace.Name {
+			return nil
+		}
+	}
+	if len(copies) == 1 {
+		return copies[0]
+	}
+	return source
+}
+
 // Find returns a pointer to the Table. If a keyspace is specified, only tables
 // from that keyspace are searched. If the specified keyspace is unsharded
 // and no tables matched, it's considered valid: Find will construct a table
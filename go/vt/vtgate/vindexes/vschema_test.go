@@ -725,6 +725,216 @@ func TestBadSequence(t *testing.T) {
 	}
 }
 
+func TestReference(t *testing.T) {
+	good := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {},
+			"sharded": {
+				Sharded: true,
+				Tables: map[string]*vschemapb.Table{
+					"ref": {
+						Type:   "reference",
+						Source: "unsharded",
+					},
+					"nosource": {
+						Type: "reference",
+					},
+				},
+			},
+		},
+	}
+	got, err := BuildVSchema(&good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ksu := &Keyspace{
+		Name: "unsharded",
+	}
+	kss := &Keyspace{
+		Name:    "sharded",
+		Sharded: true,
+	}
+	ref := &Table{
+		Name:        "ref",
+		Keyspace:    kss,
+		IsReference: true,
+		Source: &Table{
+			Name:     "ref",
+			Keyspace: ksu,
+		},
+	}
+	nosource := &Table{
+		Name:        "nosource",
+		Keyspace:    kss,
+		IsReference: true,
+	}
+	want := &VSchema{
+		tables: map[string]*Table{
+			"ref":      ref,
+			"nosource": nosource,
+		},
+		Keyspaces: map[string]*KeyspaceSchema{
+			"unsharded": {
+				Keyspace: ksu,
+				Tables:   map[string]*Table{},
+			},
+			"sharded": {
+				Keyspace: kss,
+				Tables: map[string]*Table{
+					"ref":      ref,
+					"nosource": nosource,
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotjson, _ := json.Marshal(got)
+		wantjson, _ := json.Marshal(want)
+		t.Errorf("BuildVSchema:s\n%s, want\n%s", gotjson, wantjson)
+	}
+}
+
+func TestFindReference(t *testing.T) {
+	ref := &vschemapb.Table{
+		Type:   "reference",
+		Source: "unsharded",
+	}
+	input := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"ref":   {},
+					"refs":  {},
+					"other": {},
+				},
+			},
+			"other_unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"other": {},
+				},
+			},
+			"sharded1": {
+				Sharded: true,
+				Tables: map[string]*vschemapb.Table{
+					"ref":   ref,
+					"refs":  ref,
+					"other": ref,
+				},
+			},
+			"sharded2": {
+				Sharded: true,
+				Tables: map[string]*vschemapb.Table{
+					"refs": ref,
+				},
+			},
+		},
+	}
+	vschema, err := BuildVSchema(&input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A single copy is preferred to its source.
+	got, err := vschema.Find("", "ref")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Keyspace.Name != "sharded1" {
+		t.Errorf("Find(ref): %s, want sharded1", got.Keyspace.Name)
+	}
+
+	// With several copies, the source is used.
+	got, err = vschema.Find("", "refs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Keyspace.Name != "unsharded" {
+		t.Errorf("Find(refs): %s, want unsharded", got.Keyspace.Name)
+	}
+
+	// Unrelated tables of the same name remain ambiguous.
+	_, err = vschema.Find("", "other")
+	want := "ambiguous table reference: other"
+	if err == nil || err.Error() != want {
+		t.Errorf("Find(other): %v, want %s", err, want)
+	}
+}
+
+func TestBadReference(t *testing.T) {
+	testcases := []struct {
+		tables map[string]*vschemapb.Table
+		err    string
+	}{{
+		tables: map[string]*vschemapb.Table{
+			"ref": {
+				Type: "reference",
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "c1",
+					Name:   "stfu1",
+				}},
+			},
+		},
+		err: "reference table ref cannot have vindexes",
+	}, {
+		tables: map[string]*vschemapb.Table{
+			"ref": {
+				Type:   "reference",
+				Source: "noexist",
+			},
+		},
+		err: "source keyspace noexist not found for table ref",
+	}, {
+		tables: map[string]*vschemapb.Table{
+			"ref": {
+				Type:   "reference",
+				Source: "other",
+			},
+		},
+		err: "source keyspace other for table ref is sharded",
+	}, {
+		tables: map[string]*vschemapb.Table{
+			"ref": {
+				Type:   "reference",
+				Source: "sharded",
+			},
+		},
+		err: "source of reference table ref cannot be its own keyspace",
+	}, {
+		tables: map[string]*vschemapb.Table{
+			"t1": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{{
+					Column: "c1",
+					Name:   "stfu1",
+				}},
+				Source: "other",
+			},
+		},
+		err: "source specified for non-reference table t1",
+	}}
+	for _, tcase := range testcases {
+		bad := vschemapb.SrvVSchema{
+			Keyspaces: map[string]*vschemapb.Keyspace{
+				"sharded": {
+					Sharded: true,
+					Vindexes: map[string]*vschemapb.Vindex{
+						"stfu1": {
+							Type: "stfu",
+						},
+					},
+					Tables: tcase.tables,
+				},
+				"other": {
+					Sharded: true,
+				},
+			},
+		}
+		_, err := BuildVSchema(&bad)
+		if err == nil || err.Error() != tcase.err {
+			t.Errorf("BuildVSchema: %v, want %v", err, tcase.err)
+		}
+	}
+}
+
 func TestFind(t *testing.T) {
 	input := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
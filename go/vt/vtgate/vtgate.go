@@ -110,6 +110,12 @@ func Init(ctx context.Context, hc discovery.HealthCheck, topoServer topo.Server,
 		log.Fatalf("VTGate already initialized")
 	}
 
+	switch *multiShardDML {
+	case multiShardDMLAllow, multiShardDMLTransaction, multiShardDMLTwoPC:
+	default:
+		log.Fatalf("invalid value for multi_shard_dml: %v", *multiShardDML)
+	}
+
 	// vschemaCounters needs to be initialized before planner to
 	// catch the initial load stats.
 	vschemaCounters = stats.NewCounters("VtgateVSchemaCounts")
@@ -573,9 +579,11 @@ func (vtg *VTGate) Begin(ctx context.Context) (*vtgatepb.Session, error) {
 	}, nil
 }
 
-// Commit commits a transaction.
+// Commit commits a transaction. With -multi_shard_dml=twopc, only the
+// transactions which span several shards are committed with 2PC.
 func (vtg *VTGate) Commit(ctx context.Context, session *vtgatepb.Session) error {
-	return formatError(vtg.txConn.Commit(ctx, false, NewSafeSession(session)))
+	twopc := *multiShardDML == multiShardDMLTwoPC && len(session.GetShardSessions()) > 1
+	return formatError(vtg.txConn.Commit(ctx, twopc, NewSafeSession(session)))
 }
 
 // Rollback rolls back a transaction.
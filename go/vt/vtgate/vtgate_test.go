@@ -121,6 +121,76 @@ func TestVTGateExecute(t *testing.T) {
 	}
 }
 
+func TestVTGateCommitTwoPC(t *testing.T) {
+	defer func(saved string) { *multiShardDML = saved }(*multiShardDML)
+	keyspace := "TestVTGateCommitTwoPC"
+	createSandbox(keyspace)
+	hcVTGateTest.Reset()
+	sbc1 := hcVTGateTest.AddTestTablet("aa", "1.1.1.1", 1001, keyspace, "-20", topodatapb.TabletType_MASTER, true, 1, nil)
+	sbc2 := hcVTGateTest.AddTestTablet("aa", "1.1.1.2", 1001, keyspace, "20-40", topodatapb.TabletType_MASTER, true, 1, nil)
+	newSession := func() *vtgatepb.Session {
+		return &vtgatepb.Session{
+			InTransaction: true,
+			ShardSessions: []*vtgatepb.Session_ShardSession{{
+				Target: &querypb.Target{
+					Keyspace:   keyspace,
+					Shard:      "-20",
+					TabletType: topodatapb.TabletType_MASTER,
+				},
+				TransactionId: 1,
+			}, {
+				Target: &querypb.Target{
+					Keyspace:   keyspace,
+					Shard:      "20-40",
+					TabletType: topodatapb.TabletType_MASTER,
+				},
+				TransactionId: 1,
+			}},
+		}
+	}
+
+	*multiShardDML = multiShardDMLTransaction
+	if err := rpcVTGate.Commit(context.Background(), newSession()); err != nil {
+		t.Error(err)
+	}
+	if c := sbc1.CommitCount.Get(); c != 1 {
+		t.Errorf("sbc1.CommitCount: %d, want 1", c)
+	}
+	if c := sbc1.CreateTransactionCount.Get(); c != 0 {
+		t.Errorf("sbc1.CreateTransactionCount: %d, want 0", c)
+	}
+
+	*multiShardDML = multiShardDMLTwoPC
+	if err := rpcVTGate.Commit(context.Background(), newSession()); err != nil {
+		t.Error(err)
+	}
+	if c := sbc1.CreateTransactionCount.Get(); c != 1 {
+		t.Errorf("sbc1.CreateTransactionCount: %d, want 1", c)
+	}
+	if c := sbc2.PrepareCount.Get(); c != 1 {
+		t.Errorf("sbc2.PrepareCount: %d, want 1", c)
+	}
+	if c := sbc1.StartCommitCount.Get(); c != 1 {
+		t.Errorf("sbc1.StartCommitCount: %d, want 1", c)
+	}
+	if c := sbc2.CommitPreparedCount.Get(); c != 1 {
+		t.Errorf("sbc2.CommitPreparedCount: %d, want 1", c)
+	}
+
+	// A single-shard transaction is committed normally.
+	session := newSession()
+	session.ShardSessions = session.ShardSessions[:1]
+	if err := rpcVTGate.Commit(context.Background(), session); err != nil {
+		t.Error(err)
+	}
+	if c := sbc1.CommitCount.Get(); c != 2 {
+		t.Errorf("sbc1.CommitCount: %d, want 2", c)
+	}
+	if c := sbc1.CreateTransactionCount.Get(); c != 1 {
+		t.Errorf("sbc1.CreateTransactionCount: %d, want 1", c)
+	}
+}
+
 func TestVTGateExecuteWithKeyspace(t *testing.T) {
 	createSandbox(KsTestUnsharded)
 	hcVTGateTest.Reset()
@@ -28,6 +28,8 @@ var (
 	key  = flag.String("vtworker_client_grpc_key", "", "the key to use to connect")
 	ca   = flag.String("vtworker_client_grpc_ca", "", "the server ca to use to validate servers when connecting")
 	name = flag.String("vtworker_client_grpc_server_name", "", "the server name to use to validate server certificate")
+
+	creds = flag.String("vtworker_client_grpc_auth_static_client_creds", "", "JSON file of the username and password to send to a vtworker using the static gRPC authentication plugin")
 )
 
 type gRPCVtworkerClient struct {
@@ -41,7 +43,12 @@ func gRPCVtworkerClientFactory(addr string, dialTimeout time.Duration) (vtworker
 	if err != nil {
 		return nil, err
 	}
-	cc, err := grpc.Dial(addr, opt, grpc.WithBlock(), grpc.WithTimeout(dialTimeout))
+	authOpts, err := grpcutils.ClientAuthDialOptions(*creds)
+	if err != nil {
+		return nil, err
+	}
+	opts := append([]grpc.DialOption{opt, grpc.WithBlock(), grpc.WithTimeout(dialTimeout)}, authOpts...)
+	cc, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, vterrors.NewVitessError(vtrpcpb.ErrorCode_DEADLINE_EXCEEDED, err, "grpc.Dial() err: %v", err)
 	}
@@ -0,0 +1,131 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testlib
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/logutil"
+	"github.com/youtube/vitess/go/vt/tabletmanager/tmclient"
+	"github.com/youtube/vitess/go/vt/vttest/fakesqldb"
+	"github.com/youtube/vitess/go/vt/wrangler"
+	"github.com/youtube/vitess/go/vt/zktopo/zktestserver"
+
+	querypb "github.com/youtube/vitess/go/vt/proto/query"
+	topodatapb "github.com/youtube/vitess/go/vt/proto/topodata"
+	vschemapb "github.com/youtube/vitess/go/vt/proto/vschema"
+)
+
+const countCountries = "SELECT COUNT(*) FROM `country`"
+
+func countResult(count string) *sqltypes.Result {
+	return &sqltypes.Result{
+		Fields:       []*querypb.Field{{Name: "COUNT(*)", Type: sqltypes.Int64}},
+		RowsAffected: 1,
+		Rows:         [][]sqltypes.Value{{sqltypes.MakeTrusted(sqltypes.Int64, []byte(count))}},
+	}
+}
+
+func TestApplyVSchemaReferenceTables(t *testing.T) {
+	ctx := context.Background()
+	ts := zktestserver.New(t, []string{"cell1"})
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	for _, keyspace := range []string{"ks", "src"} {
+		if err := ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}); err != nil {
+			t.Fatalf("CreateKeyspace(%v) failed: %v", keyspace, err)
+		}
+	}
+
+	// The source is in src/0, and the copies in ks/-80 and ks/80-.
+	sourceDb := fakesqldb.Register()
+	db1 := fakesqldb.Register()
+	db2 := fakesqldb.Register()
+	source := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, sourceDb, TabletKeyspaceShard(t, "src", "0"))
+	master1 := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_MASTER, db1, TabletKeyspaceShard(t, "ks", "-80"))
+	master2 := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_MASTER, db2, TabletKeyspaceShard(t, "ks", "80-"))
+	for _, ft := range []*FakeTablet{source, master1, master2} {
+		ft.StartActionLoop(t, wr)
+		defer ft.StopActionLoop(t)
+	}
+
+	withSource := `{"sharded": true, "tables": {"country": {"type": "reference", "source": "src"}}}`
+	withoutSource := `{"sharded": true, "tables": {"country": {"type": "reference"}}}`
+	testcases := []struct {
+		desc                 string
+		vschema              string
+		source, copy1, copy2 string
+		missing              bool
+		err                  string
+	}{{
+		desc:    "copied to every shard",
+		vschema: withSource,
+		source:  "3",
+		copy1:   "3",
+		copy2:   "3",
+	}, {
+		desc:    "not copied to a shard",
+		vschema: withSource,
+		source:  "3",
+		copy1:   "3",
+		copy2:   "0",
+		err:     "reference table country is not populated: 0 rows in ks/80-, but 3 rows in src/0",
+	}, {
+		desc:    "missing from a shard",
+		vschema: withSource,
+		source:  "3",
+		copy1:   "3",
+		missing: true,
+		err:     "reference table country is not populated: cannot count the rows of country on cell1-0000000002",
+	}, {
+		desc:    "copies without a source",
+		vschema: withoutSource,
+		copy1:   "2",
+		copy2:   "2",
+	}, {
+		desc:    "different copies without a source",
+		vschema: withoutSource,
+		copy1:   "2",
+		copy2:   "1",
+		err:     "reference table country is not populated: 1 rows in ks/80-, but 2 rows in ks/-80",
+	}}
+	for _, tcase := range testcases {
+		sourceDb.AddQuery(countCountries, countResult(tcase.source))
+		db1.AddQuery(countCountries, countResult(tcase.copy1))
+		if tcase.missing {
+			db2.DeleteQuery(countCountries)
+			db2.AddRejectedQuery(countCountries, errors.New("Table 'vt_ks.country' doesn't exist"))
+		} else {
+			db2.DeleteRejectedQuery(countCountries)
+			db2.AddQuery(countCountries, countResult(tcase.copy2))
+		}
+		if err := ts.SaveVSchema(ctx, "ks", &vschemapb.Keyspace{}); err != nil {
+			t.Fatalf("%s: SaveVSchema failed: %v", tcase.desc, err)
+		}
+
+		err := vp.Run([]string{"ApplyVSchema", "-skip_rebuild", "-vschema", tcase.vschema, "ks"})
+		if tcase.err == "" {
+			if err != nil {
+				t.Errorf("%s: ApplyVSchema failed: %v", tcase.desc, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Errorf("%s: ApplyVSchema: %v, must contain %v", tcase.desc, err, tcase.err)
+		}
+		vschema, err := ts.GetVSchema(ctx, "ks")
+		if err != nil {
+			t.Fatalf("%s: GetVSchema failed: %v", tcase.desc, err)
+		}
+		if saved := vschema.Tables["country"] != nil; saved != (tcase.err == "") {
+			t.Errorf("%s: vschema saved: %v, want %v", tcase.desc, saved, tcase.err == "")
+		}
+	}
+}
@@ -117,7 +117,7 @@ func waitForFilteredReplication(t *testing.T, expectedErr string, initialStats *
 	testConfig := tabletserver.DefaultQsConfig
 	testConfig.EnablePublishStats = false
 	testConfig.DebugURLPrefix = fmt.Sprintf("TestWaitForFilteredReplication-%d-", rand.Int63())
-	qs := tabletserver.NewTabletServer(testConfig)
+	qs := tabletserver.NewTabletServer(testConfig, ts)
 	grpcqueryservice.Register(dest.RPCServer, qs)
 
 	qs.BroadcastHealth(42, initialStats)
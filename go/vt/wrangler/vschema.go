@@ -0,0 +1,99 @@
+// Copyright 2016, Google Inc. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wrangler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/youtube/vitess/go/sqltypes"
+	"github.com/youtube/vitess/go/vt/topo/topoproto"
+
+	vschemapb "github.com/youtube/vitess/go/vt/proto/vschema"
+)
+
+// ValidateReferenceTables checks that the reference tables of the
+// vschema of a sharded keyspace are populated. VTGate reads them from
+// any shard, but nothing copies them to the shards. So, every shard
+// must already have a copy of each of them, with as many rows as the
+// source table if the reference table has one, or as the copy of the
+// first shard otherwise.
+func (wr *Wrangler) ValidateReferenceTables(ctx context.Context, keyspace string, vschema *vschemapb.Keyspace) error {
+	if !vschema.Sharded {
+		return nil
+	}
+	var tables []string
+	for name, table := range vschema.Tables {
+		if table.Type == "reference" {
+			tables = append(tables, name)
+		}
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	sort.Strings(tables)
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	sort.Strings(shards)
+	for _, name := range tables {
+		want := int64(-1)
+		wantFrom := ""
+		if source := vschema.Tables[name].Source; source != "" {
+			sourceShards, err := wr.ts.GetShardNames(ctx, source)
+			if err != nil {
+				return fmt.Errorf("cannot get the shards of the source keyspace %v of reference table %v: %v", source, name, err)
+			}
+			if len(sourceShards) != 1 {
+				return fmt.Errorf("source keyspace %v of reference table %v has %v shards, want 1", source, name, len(sourceShards))
+			}
+			if want, err = wr.countMasterRows(ctx, source, sourceShards[0], name); err != nil {
+				return err
+			}
+			wantFrom = source + "/" + sourceShards[0]
+		}
+		for _, shard := range shards {
+			count, err := wr.countMasterRows(ctx, keyspace, shard, name)
+			if err != nil {
+				return fmt.Errorf("reference table %v is not populated: %v", name, err)
+			}
+			if want == -1 {
+				want = count
+				wantFrom = keyspace + "/" + shard
+				continue
+			}
+			if count != want {
+				return fmt.Errorf("reference table %v is not populated: %v rows in %v/%v, but %v rows in %v", name, count, keyspace, shard, want, wantFrom)
+			}
+		}
+	}
+	return nil
+}
+
+// countMasterRows returns the number of rows of the table
+// on the master of the shard.
+func (wr *Wrangler) countMasterRows(ctx context.Context, keyspace, shard, table string) (int64, error) {
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return 0, err
+	}
+	if !si.HasMaster() {
+		return 0, fmt.Errorf("shard %v/%v has no master", keyspace, shard)
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM `%v`", strings.Replace(table, "`", "``", -1))
+	qr, err := wr.ExecuteFetchAsDba(ctx, si.MasterAlias, query, 1, false, false)
+	if err != nil {
+		return 0, fmt.Errorf("cannot count the rows of %v on %v: %v", table, topoproto.TabletAliasString(si.MasterAlias), err)
+	}
+	result := sqltypes.Proto3ToResult(qr)
+	if len(result.Rows) != 1 || len(result.Rows[0]) != 1 {
+		return 0, fmt.Errorf("unexpected result counting the rows of %v on %v: %v", table, topoproto.TabletAliasString(si.MasterAlias), result.Rows)
+	}
+	return result.Rows[0][0].ParseInt64()
+}
@@ -18,6 +18,12 @@ namespace Vitess\Proto\Query {
     /**  @var \Vitess\Proto\Query\RealtimeStats */
     public $realtime_stats = null;
     
+    /**  @var int */
+    public $schema_version = null;
+    
+    /**  @var \Vitess\Proto\Query\TableSchema[]  */
+    public $table_schemas = array();
+    
 
     /** @var \Closure[] */
     protected static $__extensions = array();
@@ -60,6 +66,23 @@ namespace Vitess\Proto\Query {
       $f->reference = '\Vitess\Proto\Query\RealtimeStats';
       $descriptor->addField($f);
 
+      // OPTIONAL INT64 schema_version = 5
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 5;
+      $f->name      = "schema_version";
+      $f->type      = \DrSlump\Protobuf::TYPE_INT64;
+      $f->rule      = \DrSlump\Protobuf::RULE_OPTIONAL;
+      $descriptor->addField($f);
+
+      // REPEATED MESSAGE table_schemas = 6
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 6;
+      $f->name      = "table_schemas";
+      $f->type      = \DrSlump\Protobuf::TYPE_MESSAGE;
+      $f->rule      = \DrSlump\Protobuf::RULE_REPEATED;
+      $f->reference = '\Vitess\Proto\Query\TableSchema';
+      $descriptor->addField($f);
+
       foreach (self::$__extensions as $cb) {
         $descriptor->addField($cb(), true);
       }
@@ -214,6 +237,100 @@ namespace Vitess\Proto\Query {
     public function setRealtimeStats(\Vitess\Proto\Query\RealtimeStats $value){
       return $this->_set(4, $value);
     }
+    
+    /**
+     * Check if <schema_version> has a value
+     *
+     * @return boolean
+     */
+    public function hasSchemaVersion(){
+      return $this->_has(5);
+    }
+    
+    /**
+     * Clear <schema_version> value
+     *
+     * @return \Vitess\Proto\Query\StreamHealthResponse
+     */
+    public function clearSchemaVersion(){
+      return $this->_clear(5);
+    }
+    
+    /**
+     * Get <schema_version> value
+     *
+     * @return int
+     */
+    public function getSchemaVersion(){
+      return $this->_get(5);
+    }
+    
+    /**
+     * Set <schema_version> value
+     *
+     * @param int $value
+     * @return \Vitess\Proto\Query\StreamHealthResponse
+     */
+    public function setSchemaVersion( $value){
+      return $this->_set(5, $value);
+    }
+    
+    /**
+     * Check if <table_schemas> has a value
+     *
+     * @return boolean
+     */
+    public function hasTableSchemas(){
+      return $this->_has(6);
+    }
+    
+    /**
+     * Clear <table_schemas> value
+     *
+     * @return \Vitess\Proto\Query\StreamHealthResponse
+     */
+    public function clearTableSchemas(){
+      return $this->_clear(6);
+    }
+    
+    /**
+     * Get <table_schemas> value
+     *
+     * @param int $idx
+     * @return \Vitess\Proto\Query\TableSchema
+     */
+    public function getTableSchemas($idx = NULL){
+      return $this->_get(6, $idx);
+    }
+    
+    /**
+     * Set <table_schemas> value
+     *
+     * @param \Vitess\Proto\Query\TableSchema $value
+     * @return \Vitess\Proto\Query\StreamHealthResponse
+     */
+    public function setTableSchemas(\Vitess\Proto\Query\TableSchema $value, $idx = NULL){
+      return $this->_set(6, $value, $idx);
+    }
+    
+    /**
+     * Get all elements of <table_schemas>
+     *
+     * @return \Vitess\Proto\Query\TableSchema[]
+     */
+    public function getTableSchemasList(){
+     return $this->_get(6);
+    }
+    
+    /**
+     * Add a new element to <table_schemas>
+     *
+     * @param \Vitess\Proto\Query\TableSchema $value
+     * @return \Vitess\Proto\Query\StreamHealthResponse
+     */
+    public function addTableSchemas(\Vitess\Proto\Query\TableSchema $value){
+     return $this->_add(6, $value);
+    }
   }
 }
 
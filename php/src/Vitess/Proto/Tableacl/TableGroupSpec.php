@@ -21,6 +21,12 @@ namespace Vitess\Proto\Tableacl {
     /**  @var string[]  */
     public $admins = array();
     
+    /**  @var \Vitess\Proto\Tableacl\ColumnDenySpec[]  */
+    public $column_denies = array();
+    
+    /**  @var \Vitess\Proto\Tableacl\RowFilterSpec[]  */
+    public $row_filters = array();
+    
 
     /** @var \Closure[] */
     protected static $__extensions = array();
@@ -69,6 +75,24 @@ namespace Vitess\Proto\Tableacl {
       $f->rule      = \DrSlump\Protobuf::RULE_REPEATED;
       $descriptor->addField($f);
 
+      // REPEATED MESSAGE column_denies = 6
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 6;
+      $f->name      = "column_denies";
+      $f->type      = \DrSlump\Protobuf::TYPE_MESSAGE;
+      $f->rule      = \DrSlump\Protobuf::RULE_REPEATED;
+      $f->reference = '\Vitess\Proto\Tableacl\ColumnDenySpec';
+      $descriptor->addField($f);
+
+      // REPEATED MESSAGE row_filters = 7
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 7;
+      $f->name      = "row_filters";
+      $f->type      = \DrSlump\Protobuf::TYPE_MESSAGE;
+      $f->rule      = \DrSlump\Protobuf::RULE_REPEATED;
+      $f->reference = '\Vitess\Proto\Tableacl\RowFilterSpec';
+      $descriptor->addField($f);
+
       foreach (self::$__extensions as $cb) {
         $descriptor->addField($cb(), true);
       }
@@ -340,6 +364,120 @@ namespace Vitess\Proto\Tableacl {
     public function addAdmins( $value){
      return $this->_add(5, $value);
     }
+    
+    /**
+     * Check if <column_denies> has a value
+     *
+     * @return boolean
+     */
+    public function hasColumnDenies(){
+      return $this->_has(6);
+    }
+    
+    /**
+     * Clear <column_denies> value
+     *
+     * @return \Vitess\Proto\Tableacl\TableGroupSpec
+     */
+    public function clearColumnDenies(){
+      return $this->_clear(6);
+    }
+    
+    /**
+     * Get <column_denies> value
+     *
+     * @param int $idx
+     * @return \Vitess\Proto\Tableacl\ColumnDenySpec
+     */
+    public function getColumnDenies($idx = NULL){
+      return $this->_get(6, $idx);
+    }
+    
+    /**
+     * Set <column_denies> value
+     *
+     * @param \Vitess\Proto\Tableacl\ColumnDenySpec $value
+     * @return \Vitess\Proto\Tableacl\TableGroupSpec
+     */
+    public function setColumnDenies(\Vitess\Proto\Tableacl\ColumnDenySpec $value, $idx = NULL){
+      return $this->_set(6, $value, $idx);
+    }
+    
+    /**
+     * Get all elements of <column_denies>
+     *
+     * @return \Vitess\Proto\Tableacl\ColumnDenySpec[]
+     */
+    public function getColumnDeniesList(){
+     return $this->_get(6);
+    }
+    
+    /**
+     * Add a new element to <column_denies>
+     *
+     * @param \Vitess\Proto\Tableacl\ColumnDenySpec $value
+     * @return \Vitess\Proto\Tableacl\TableGroupSpec
+     */
+    public function addColumnDenies(\Vitess\Proto\Tableacl\ColumnDenySpec $value){
+     return $this->_add(6, $value);
+    }
+    
+    /**
+     * Check if <row_filters> has a value
+     *
+     * @return boolean
+     */
+    public function hasRowFilters(){
+      return $this->_has(7);
+    }
+    
+    /**
+     * Clear <row_filters> value
+     *
+     * @return \Vitess\Proto\Tableacl\TableGroupSpec
+     */
+    public function clearRowFilters(){
+      return $this->_clear(7);
+    }
+    
+    /**
+     * Get <row_filters> value
+     *
+     * @param int $idx
+     * @return \Vitess\Proto\Tableacl\RowFilterSpec
+     */
+    public function getRowFilters($idx = NULL){
+      return $this->_get(7, $idx);
+    }
+    
+    /**
+     * Set <row_filters> value
+     *
+     * @param \Vitess\Proto\Tableacl\RowFilterSpec $value
+     * @return \Vitess\Proto\Tableacl\TableGroupSpec
+     */
+    public function setRowFilters(\Vitess\Proto\Tableacl\RowFilterSpec $value, $idx = NULL){
+      return $this->_set(7, $value, $idx);
+    }
+    
+    /**
+     * Get all elements of <row_filters>
+     *
+     * @return \Vitess\Proto\Tableacl\RowFilterSpec[]
+     */
+    public function getRowFiltersList(){
+     return $this->_get(7);
+    }
+    
+    /**
+     * Add a new element to <row_filters>
+     *
+     * @param \Vitess\Proto\Tableacl\RowFilterSpec $value
+     * @return \Vitess\Proto\Tableacl\TableGroupSpec
+     */
+    public function addRowFilters(\Vitess\Proto\Tableacl\RowFilterSpec $value){
+     return $this->_add(7, $value);
+    }
   }
 }
 
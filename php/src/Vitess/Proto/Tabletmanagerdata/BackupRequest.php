@@ -10,6 +10,10 @@ namespace Vitess\Proto\Tabletmanagerdata {
     public $concurrency = null;
     
 
+    /**  @var boolean */
+    public $incremental = null;
+    
+
     /** @var \Closure[] */
     protected static $__extensions = array();
 
@@ -25,6 +29,14 @@ namespace Vitess\Proto\Tabletmanagerdata {
       $f->rule      = \DrSlump\Protobuf::RULE_OPTIONAL;
       $descriptor->addField($f);
 
+      // OPTIONAL BOOL incremental = 2
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 2;
+      $f->name      = "incremental";
+      $f->type      = \DrSlump\Protobuf::TYPE_BOOL;
+      $f->rule      = \DrSlump\Protobuf::RULE_OPTIONAL;
+      $descriptor->addField($f);
+
       foreach (self::$__extensions as $cb) {
         $descriptor->addField($cb(), true);
       }
@@ -68,6 +80,43 @@ namespace Vitess\Proto\Tabletmanagerdata {
     public function setConcurrency( $value){
       return $this->_set(1, $value);
     }
+
+    /**
+     * Check if <incremental> has a value
+     *
+     * @return boolean
+     */
+    public function hasIncremental(){
+      return $this->_has(2);
+    }
+    
+    /**
+     * Clear <incremental> value
+     *
+     * @return \Vitess\Proto\Tabletmanagerdata\BackupRequest
+     */
+    public function clearIncremental(){
+      return $this->_clear(2);
+    }
+    
+    /**
+     * Get <incremental> value
+     *
+     * @return boolean
+     */
+    public function getIncremental(){
+      return $this->_get(2);
+    }
+    
+    /**
+     * Set <incremental> value
+     *
+     * @param boolean $value
+     * @return \Vitess\Proto\Tabletmanagerdata\BackupRequest
+     */
+    public function setIncremental( $value){
+      return $this->_set(2, $value);
+    }
   }
 }
 
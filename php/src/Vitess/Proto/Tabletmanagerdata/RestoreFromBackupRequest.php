@@ -6,6 +6,13 @@ namespace Vitess\Proto\Tabletmanagerdata {
 
   class RestoreFromBackupRequest extends \DrSlump\Protobuf\Message {
 
+    /**  @var string */
+    public $restore_to_position = null;
+    
+
+    /**  @var int */
+    public $restore_to_timestamp = null;
+    
 
     /** @var \Closure[] */
     protected static $__extensions = array();
@@ -14,12 +21,102 @@ namespace Vitess\Proto\Tabletmanagerdata {
     {
       $descriptor = new \DrSlump\Protobuf\Descriptor(__CLASS__, 'tabletmanagerdata.RestoreFromBackupRequest');
 
+      // OPTIONAL STRING restore_to_position = 1
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 1;
+      $f->name      = "restore_to_position";
+      $f->type      = \DrSlump\Protobuf::TYPE_STRING;
+      $f->rule      = \DrSlump\Protobuf::RULE_OPTIONAL;
+      $descriptor->addField($f);
+
+      // OPTIONAL INT64 restore_to_timestamp = 2
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 2;
+      $f->name      = "restore_to_timestamp";
+      $f->type      = \DrSlump\Protobuf::TYPE_INT64;
+      $f->rule      = \DrSlump\Protobuf::RULE_OPTIONAL;
+      $descriptor->addField($f);
+
       foreach (self::$__extensions as $cb) {
         $descriptor->addField($cb(), true);
       }
 
       return $descriptor;
     }
+
+    /**
+     * Check if <restore_to_position> has a value
+     *
+     * @return boolean
+     */
+    public function hasRestoreToPosition(){
+      return $this->_has(1);
+    }
+    
+    /**
+     * Clear <restore_to_position> value
+     *
+     * @return \Vitess\Proto\Tabletmanagerdata\RestoreFromBackupRequest
+     */
+    public function clearRestoreToPosition(){
+      return $this->_clear(1);
+    }
+    
+    /**
+     * Get <restore_to_position> value
+     *
+     * @return string
+     */
+    public function getRestoreToPosition(){
+      return $this->_get(1);
+    }
+    
+    /**
+     * Set <restore_to_position> value
+     *
+     * @param string $value
+     * @return \Vitess\Proto\Tabletmanagerdata\RestoreFromBackupRequest
+     */
+    public function setRestoreToPosition( $value){
+      return $this->_set(1, $value);
+    }
+
+    /**
+     * Check if <restore_to_timestamp> has a value
+     *
+     * @return boolean
+     */
+    public function hasRestoreToTimestamp(){
+      return $this->_has(2);
+    }
+    
+    /**
+     * Clear <restore_to_timestamp> value
+     *
+     * @return \Vitess\Proto\Tabletmanagerdata\RestoreFromBackupRequest
+     */
+    public function clearRestoreToTimestamp(){
+      return $this->_clear(2);
+    }
+    
+    /**
+     * Get <restore_to_timestamp> value
+     *
+     * @return int
+     */
+    public function getRestoreToTimestamp(){
+      return $this->_get(2);
+    }
+    
+    /**
+     * Set <restore_to_timestamp> value
+     *
+     * @param int $value
+     * @return \Vitess\Proto\Tabletmanagerdata\RestoreFromBackupRequest
+     */
+    public function setRestoreToTimestamp( $value){
+      return $this->_set(2, $value);
+    }
   }
 }
 
@@ -15,6 +15,9 @@ namespace Vitess\Proto\Vschema {
     /**  @var \Vitess\Proto\Vschema\AutoIncrement */
     public $auto_increment = null;
     
+    /**  @var string */
+    public $source = null;
+    
 
     /** @var \Closure[] */
     protected static $__extensions = array();
@@ -49,6 +52,14 @@ namespace Vitess\Proto\Vschema {
       $f->reference = '\Vitess\Proto\Vschema\AutoIncrement';
       $descriptor->addField($f);
 
+      // OPTIONAL STRING source = 4
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 4;
+      $f->name      = "source";
+      $f->type      = \DrSlump\Protobuf::TYPE_STRING;
+      $f->rule      = \DrSlump\Protobuf::RULE_OPTIONAL;
+      $descriptor->addField($f);
+
       foreach (self::$__extensions as $cb) {
         $descriptor->addField($cb(), true);
       }
@@ -186,6 +197,43 @@ namespace Vitess\Proto\Vschema {
     public function setAutoIncrement(\Vitess\Proto\Vschema\AutoIncrement $value){
       return $this->_set(3, $value);
     }
+    
+    /**
+     * Check if <source> has a value
+     *
+     * @return boolean
+     */
+    public function hasSource(){
+      return $this->_has(4);
+    }
+    
+    /**
+     * Clear <source> value
+     *
+     * @return \Vitess\Proto\Vschema\Table
+     */
+    public function clearSource(){
+      return $this->_clear(4);
+    }
+    
+    /**
+     * Get <source> value
+     *
+     * @return string
+     */
+    public function getSource(){
+      return $this->_get(4);
+    }
+    
+    /**
+     * Set <source> value
+     *
+     * @param string $value
+     * @return \Vitess\Proto\Vschema\Table
+     */
+    public function setSource( $value){
+      return $this->_set(4, $value);
+    }
   }
 }
 
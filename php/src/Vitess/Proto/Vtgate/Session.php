@@ -12,6 +12,9 @@ namespace Vitess\Proto\Vtgate {
     /**  @var \Vitess\Proto\Vtgate\Session\ShardSession[]  */
     public $shard_sessions = array();
     
+    /**  @var string[]  */
+    public $savepoints = array();
+    
 
     /** @var \Closure[] */
     protected static $__extensions = array();
@@ -37,6 +40,14 @@ namespace Vitess\Proto\Vtgate {
       $f->reference = '\Vitess\Proto\Vtgate\Session\ShardSession';
       $descriptor->addField($f);
 
+      // REPEATED STRING savepoints = 3
+      $f = new \DrSlump\Protobuf\Field();
+      $f->number    = 3;
+      $f->name      = "savepoints";
+      $f->type      = \DrSlump\Protobuf::TYPE_STRING;
+      $f->rule      = \DrSlump\Protobuf::RULE_REPEATED;
+      $descriptor->addField($f);
+
       foreach (self::$__extensions as $cb) {
         $descriptor->addField($cb(), true);
       }
@@ -137,6 +148,63 @@ namespace Vitess\Proto\Vtgate {
     public function addShardSessions(\Vitess\Proto\Vtgate\Session\ShardSession $value){
      return $this->_add(2, $value);
     }
+    
+    /**
+     * Check if <savepoints> has a value
+     *
+     * @return boolean
+     */
+    public function hasSavepoints(){
+      return $this->_has(3);
+    }
+    
+    /**
+     * Clear <savepoints> value
+     *
+     * @return \Vitess\Proto\Vtgate\Session
+     */
+    public function clearSavepoints(){
+      return $this->_clear(3);
+    }
+    
+    /**
+     * Get <savepoints> value
+     *
+     * @param int $idx
+     * @return string
+     */
+    public function getSavepoints($idx = NULL){
+      return $this->_get(3, $idx);
+    }
+    
+    /**
+     * Set <savepoints> value
+     *
+     * @param string $value
+     * @return \Vitess\Proto\Vtgate\Session
+     */
+    public function setSavepoints( $value, $idx = NULL){
+      return $this->_set(3, $value, $idx);
+    }
+    
+    /**
+     * Get all elements of <savepoints>
+     *
+     * @return string[]
+     */
+    public function getSavepointsList(){
+     return $this->_get(3);
+    }
+    
+    /**
+     * Add a new element to <savepoints>
+     *
+     * @param string $value
+     * @return \Vitess\Proto\Vtgate\Session
+     */
+    public function addSavepoints( $value){
+     return $this->_add(3, $value);
+    }
   }
 }
 
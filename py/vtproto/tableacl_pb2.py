@@ -19,7 +19,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   name='tableacl.proto',
   package='tableacl',
   syntax='proto3',
-  serialized_pb=_b('\n\x0etableacl.proto\x12\x08tableacl\"q\n\x0eTableGroupSpec\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x1f\n\x17table_names_or_prefixes\x18\x02 \x03(\t\x12\x0f\n\x07readers\x18\x03 \x03(\t\x12\x0f\n\x07writers\x18\x04 \x03(\t\x12\x0e\n\x06\x61\x64mins\x18\x05 \x03(\t\"8\n\x06\x43onfig\x12.\n\x0ctable_groups\x18\x01 \x03(\x0b\x32\x18.tableacl.TableGroupSpecb\x06proto3')
+  serialized_pb=_b('\n\x0etableacl.proto\x12\x08tableacl\"\xd0\x01\n\x0eTableGroupSpec\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x1f\n\x17table_names_or_prefixes\x18\x02 \x03(\t\x12\x0f\n\x07readers\x18\x03 \x03(\t\x12\x0f\n\x07writers\x18\x04 \x03(\t\x12\x0e\n\x06\x61\x64mins\x18\x05 \x03(\t\x12/\n\rcolumn_denies\x18\x06 \x03(\x0b\x32\x18.tableacl.ColumnDenySpec\x12,\n\x0brow_filters\x18\x07 \x03(\x0b\x32\x17.tableacl.RowFilterSpec\"C\n\x0e\x43olumnDenySpec\x12\x0f\n\x07\x63olumns\x18\x01 \x03(\t\x12\x12\n\nprincipals\x18\x02 \x03(\t\x12\x0c\n\x04mask\x18\x03 \x01(\x08\"6\n\rRowFilterSpec\x12\x12\n\nprincipals\x18\x01 \x03(\t\x12\x11\n\tpredicate\x18\x02 \x01(\t\"8\n\x06\x43onfig\x12.\n\x0ctable_groups\x18\x01 \x03(\x0b\x32\x18.tableacl.TableGroupSpecb\x06proto3')
 )
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
@@ -68,6 +68,20 @@ _TABLEGROUPSPEC = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None),
+    _descriptor.FieldDescriptor(
+      name='column_denies', full_name='tableacl.TableGroupSpec.column_denies', index=5,
+      number=6, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
+    _descriptor.FieldDescriptor(
+      name='row_filters', full_name='tableacl.TableGroupSpec.row_filters', index=6,
+      number=7, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
   ],
   extensions=[
   ],
@@ -80,8 +94,91 @@ _TABLEGROUPSPEC = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=28,
-  serialized_end=141,
+  serialized_start=29,
+  serialized_end=237,
+)
+
+
+_COLUMNDENYSPEC = _descriptor.Descriptor(
+  name='ColumnDenySpec',
+  full_name='tableacl.ColumnDenySpec',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='columns', full_name='tableacl.ColumnDenySpec.columns', index=0,
+      number=1, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
+    _descriptor.FieldDescriptor(
+      name='principals', full_name='tableacl.ColumnDenySpec.principals', index=1,
+      number=2, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
+    _descriptor.FieldDescriptor(
+      name='mask', full_name='tableacl.ColumnDenySpec.mask', index=2,
+      number=3, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=239,
+  serialized_end=306,
+)
+
+
+_ROWFILTERSPEC = _descriptor.Descriptor(
+  name='RowFilterSpec',
+  full_name='tableacl.RowFilterSpec',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='principals', full_name='tableacl.RowFilterSpec.principals', index=0,
+      number=1, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
+    _descriptor.FieldDescriptor(
+      name='predicate', full_name='tableacl.RowFilterSpec.predicate', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=308,
+  serialized_end=362,
 )
 
 
@@ -111,12 +208,16 @@ _CONFIG = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=143,
-  serialized_end=199,
+  serialized_start=364,
+  serialized_end=420,
 )
 
+_TABLEGROUPSPEC.fields_by_name['column_denies'].message_type = _COLUMNDENYSPEC
+_TABLEGROUPSPEC.fields_by_name['row_filters'].message_type = _ROWFILTERSPEC
 _CONFIG.fields_by_name['table_groups'].message_type = _TABLEGROUPSPEC
 DESCRIPTOR.message_types_by_name['TableGroupSpec'] = _TABLEGROUPSPEC
+DESCRIPTOR.message_types_by_name['ColumnDenySpec'] = _COLUMNDENYSPEC
+DESCRIPTOR.message_types_by_name['RowFilterSpec'] = _ROWFILTERSPEC
 DESCRIPTOR.message_types_by_name['Config'] = _CONFIG
 
 TableGroupSpec = _reflection.GeneratedProtocolMessageType('TableGroupSpec', (_message.Message,), dict(
@@ -126,6 +227,20 @@ TableGroupSpec = _reflection.GeneratedProtocolMessageType('TableGroupSpec', (_me
   ))
 _sym_db.RegisterMessage(TableGroupSpec)
 
+ColumnDenySpec = _reflection.GeneratedProtocolMessageType('ColumnDenySpec', (_message.Message,), dict(
+  DESCRIPTOR = _COLUMNDENYSPEC,
+  __module__ = 'tableacl_pb2'
+  # @@protoc_insertion_point(class_scope:tableacl.ColumnDenySpec)
+  ))
+_sym_db.RegisterMessage(ColumnDenySpec)
+
+RowFilterSpec = _reflection.GeneratedProtocolMessageType('RowFilterSpec', (_message.Message,), dict(
+  DESCRIPTOR = _ROWFILTERSPEC,
+  __module__ = 'tableacl_pb2'
+  # @@protoc_insertion_point(class_scope:tableacl.RowFilterSpec)
+  ))
+_sym_db.RegisterMessage(RowFilterSpec)
+
 Config = _reflection.GeneratedProtocolMessageType('Config', (_message.Message,), dict(
   DESCRIPTOR = _CONFIG,
   __module__ = 'tableacl_pb2'
@@ -5,6 +5,8 @@
 
 import logging
 import struct
+import threading
+import time
 import unittest
 
 from vtdb import keyrange
@@ -203,8 +205,6 @@ class BaseTestCase(unittest.TestCase):
     logging.info('Start: %s.', '.'.join(self.id().split('.')[-2:]))
 
 
-# TODO(liguo): once we have the final master buffering code in place, these
-# tests should verify that we only buffer when the master is unavailable.
 class TestMasterBuffering(BaseTestCase):
 
   shard_index = 0
@@ -213,68 +213,69 @@ class TestMasterBuffering(BaseTestCase):
   def setUp(self):
     super(TestMasterBuffering, self).setUp()
     restart_vtgate(extra_args=[
-        '-enable_fake_master_buffer',
-        '-buffer_keyspace', KEYSPACE_NAME,
-        '-buffer_shard', SHARD_NAMES[self.shard_index],
-        '-fake_buffer_delay', '1ms',
+        '-enable_master_buffer',
+        '-buffer_keyspace_shards', '%s/%s' % (
+            KEYSPACE_NAME, SHARD_NAMES[self.shard_index]),
+        '-buffer_window', '10s',
         ])
 
-  def get_sucessful_buffered_requests(self):
-    return utils.vtgate.get_vars()['BufferedRequestsSuccessful']
+  def get_buffered_requests(self):
+    buffered = utils.vtgate.get_vars().get('BufferRequestsBuffered', {})
+    return buffered.get(
+        '%s.%s' % (KEYSPACE_NAME, SHARD_NAMES[self.shard_index]), 0)
 
-  def test_tx_is_buffered(self):
-    """Tests that for a transaction, we buffer exactly one request."""
-    vtgate_conn = get_connection()
+  def test_reparent_is_buffered(self):
+    """Tests that master requests are buffered during a reparent."""
     kid_list = SHARD_KID_MAP[SHARD_NAMES[self.shard_index]]
     keyspace_id = kid_list[0]
-
-    initial_buffered = self.get_sucessful_buffered_requests()
-
-    cursor = vtgate_conn.cursor(
-        tablet_type='master', keyspace=KEYSPACE_NAME,
-        keyspace_ids=[pack_kid(keyspace_id)],
-        writable=True)
-    cursor.begin()
-    cursor.execute(
-        'insert into vt_insert_test (msg, keyspace_id) '
-        'values (:msg, :keyspace_id)',
-        {'msg': 'test %s' % 1000, 'keyspace_id': keyspace_id})
-    cursor.execute('select * from vt_insert_test', {})
-    cursor.rollback()
-
-    num_buffered = self.get_sucessful_buffered_requests() - initial_buffered
-    # No matter how many requests there were in the transaction, we should only
-    # buffer one request (the Begin to the vttablet).
-    self.assertEqual(num_buffered, 1)
-
-  def test_master_read_is_buffered(self):
-    """Tests that we buffer master reads."""
-    vtgate_conn = get_connection()
-    kid_list = SHARD_KID_MAP[SHARD_NAMES[self.shard_index]]
-    keyspace_id = kid_list[0]
-
-    initial_buffered = self.get_sucessful_buffered_requests()
-
-    cursor = vtgate_conn.cursor(
-        tablet_type='master', keyspace=KEYSPACE_NAME,
-        keyspace_ids=[pack_kid(keyspace_id)])
-    cursor.execute('select * from vt_insert_test', {})
-
-    num_buffered = self.get_sucessful_buffered_requests() - initial_buffered
-    self.assertEqual(num_buffered, 1)
+    initial_buffered = self.get_buffered_requests()
+
+    # Send master reads in the background while we reparent.
+    errors = []
+    done = threading.Event()
+
+    def send_requests():
+      vtgate_conn = get_connection()
+      while not done.is_set():
+        try:
+          cursor = vtgate_conn.cursor(
+              tablet_type='master', keyspace=KEYSPACE_NAME,
+              keyspace_ids=[pack_kid(keyspace_id)])
+          cursor.execute('select * from vt_insert_test', {})
+        except Exception as e:  # pylint: disable=broad-except
+          errors.append(e)
+        time.sleep(0.01)
+
+    thread = threading.Thread(target=send_requests)
+    thread.start()
+    try:
+      for new_master in [shard_0_replica1, shard_0_master]:
+        utils.run_vtctl(['PlannedReparentShard',
+                         '-keyspace_shard', '%s/%s' % (
+                             KEYSPACE_NAME, SHARD_NAMES[self.shard_index]),
+                         '-new_master', new_master.tablet_alias],
+                        auto_log=True)
+        utils.vtgate.wait_for_endpoints(
+            '%s.%s.master' % (KEYSPACE_NAME, SHARD_NAMES[self.shard_index]),
+            1)
+    finally:
+      done.set()
+      thread.join()
+
+    self.assertEqual(errors, [])
+    self.assertGreater(self.get_buffered_requests(), initial_buffered)
 
   def test_replica_read_is_not_buffered(self):
     """Tests that we do not buffer replica reads."""
     vtgate_conn = get_connection()
 
-    initial_buffered = self.get_sucessful_buffered_requests()
+    initial_buffered = self.get_buffered_requests()
     vtgate_conn._execute(
         'select * from vt_insert_test', {},
         tablet_type='replica', keyspace_name=KEYSPACE_NAME,
         keyranges=[self.keyrange]
         )
-    num_buffered = self.get_sucessful_buffered_requests() - initial_buffered
-    self.assertEqual(num_buffered, 0)
+    self.assertEqual(self.get_buffered_requests(), initial_buffered)
 
 
 if __name__ == '__main__':